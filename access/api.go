@@ -8,6 +8,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 )
 
 // API provides all public-facing functionality of the Flow Access API.
@@ -28,10 +29,12 @@ type API interface {
 	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
 	GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error)
 	GetTransactionResult(ctx context.Context, id flow.Identifier) (*TransactionResult, error)
+	SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*flow.TransactionSimulationResult, error)
 
 	GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtLatestBlock(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error)
+	GetTransactionsByAddress(ctx context.Context, address flow.Address, startHeight, endHeight uint64, pageSize uint, pageToken []byte) (*AccountTransactionsPage, error)
 
 	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments [][]byte) ([]byte, error)
 	ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error)
@@ -75,6 +78,22 @@ func MessageToTransactionResult(message *access.TransactionResultResponse) *Tran
 	}
 }
 
+// AccountTransaction is a transaction touching an account, with the roles of the account in the transaction.
+type AccountTransaction struct {
+	TransactionID    flow.Identifier
+	BlockID          flow.Identifier
+	BlockHeight      uint64
+	TransactionIndex uint32
+	Roles            storage.AccountRoles
+}
+
+// AccountTransactionsPage is a page of the transactions touching an account. The next page token is nil if
+// there are no more transactions in the requested height range.
+type AccountTransactionsPage struct {
+	Transactions  []AccountTransaction
+	NextPageToken []byte
+}
+
 // NetworkParameters contains the network-wide parameters for the Flow blockchain.
 type NetworkParameters struct {
 	ChainID flow.ChainID
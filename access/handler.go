@@ -11,11 +11,16 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/onflow/flow-go/engine/common/rpc/accounts"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/model/flow"
 )
 
+// Handler serves the Access API, the transaction simulation API and the account transactions API.
 type Handler struct {
+	simulation.UnimplementedTransactionSimulationAPIServer
+	accounts.UnimplementedAccountTransactionsAPIServer
 	api   API
 	chain flow.Chain
 }
@@ -185,6 +190,24 @@ func (h *Handler) SendTransaction(
 	}, nil
 }
 
+// SimulateTransaction runs a transaction against the latest sealed state without committing it.
+func (h *Handler) SimulateTransaction(
+	ctx context.Context,
+	req *simulation.SimulateTransactionRequest,
+) (*simulation.SimulateTransactionResponse, error) {
+	tx, err := convert.MessageToTransaction(req.GetTransaction(), h.chain)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := h.api.SimulateTransaction(ctx, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.TransactionSimulationResultToMessage(result), nil
+}
+
 // GetTransaction gets a transaction by ID.
 func (h *Handler) GetTransaction(
 	ctx context.Context,
@@ -294,6 +317,45 @@ func (h *Handler) GetAccountAtBlockHeight(
 	}, nil
 }
 
+// GetTransactionsByAddress gets a page of the transactions touching an account.
+func (h *Handler) GetTransactionsByAddress(
+	ctx context.Context,
+	req *accounts.GetTransactionsByAddressRequest,
+) (*accounts.GetTransactionsByAddressResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := h.api.GetTransactionsByAddress(
+		ctx,
+		address,
+		req.GetStartHeight(),
+		req.GetEndHeight(),
+		uint(req.GetPageSize()),
+		req.GetPageToken(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*accounts.AccountTransaction, 0, len(page.Transactions))
+	for _, transaction := range page.Transactions {
+		transactions = append(transactions, &accounts.AccountTransaction{
+			TransactionId:    convert.IdentifierToMessage(transaction.TransactionID),
+			BlockId:          convert.IdentifierToMessage(transaction.BlockID),
+			BlockHeight:      transaction.BlockHeight,
+			TransactionIndex: transaction.TransactionIndex,
+			Roles:            uint32(transaction.Roles),
+		})
+	}
+
+	return &accounts.GetTransactionsByAddressResponse{
+		Transactions:  transactions,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
 // ExecuteScriptAtLatestBlock executes a script at a the latest block.
 func (h *Handler) ExecuteScriptAtLatestBlock(
 	ctx context.Context,
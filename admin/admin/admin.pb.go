@@ -78,10 +78,13 @@ func (x *RunCommandRequest) GetData() *structpb.Struct {
 	return nil
 }
 
+// RunCommandResponse represents the result of an admin command
 type RunCommandResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Output *structpb.Value `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"` // Output of the command, if any
 }
 
 func (x *RunCommandResponse) Reset() {
@@ -116,6 +119,13 @@ func (*RunCommandResponse) Descriptor() ([]byte, []int) {
 	return file_admin_admin_proto_rawDescGZIP(), []int{1}
 }
 
+func (x *RunCommandResponse) GetOutput() *structpb.Value {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
 var File_admin_admin_proto protoreflect.FileDescriptor
 
 var file_admin_admin_proto_rawDesc = []byte{
@@ -130,8 +140,11 @@ var file_admin_admin_proto_rawDesc = []byte{
 	0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a,
 	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
-	0x72, 0x75, 0x63, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x75,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x12, 0x52, 0x75,
 	0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2e, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
 	0x32, 0x69, 0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x60, 0x0a, 0x0a, 0x52, 0x75, 0x6e,
 	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
 	0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
@@ -161,16 +174,18 @@ var file_admin_admin_proto_goTypes = []interface{}{
 	(*RunCommandRequest)(nil),  // 0: admin.RunCommandRequest
 	(*RunCommandResponse)(nil), // 1: admin.RunCommandResponse
 	(*structpb.Struct)(nil),    // 2: google.protobuf.Struct
+	(*structpb.Value)(nil),     // 3: google.protobuf.Value
 }
 var file_admin_admin_proto_depIdxs = []int32{
 	2, // 0: admin.RunCommandRequest.data:type_name -> google.protobuf.Struct
-	0, // 1: admin.Admin.RunCommand:input_type -> admin.RunCommandRequest
-	1, // 2: admin.Admin.RunCommand:output_type -> admin.RunCommandResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 1: admin.RunCommandResponse.output:type_name -> google.protobuf.Value
+	0, // 2: admin.Admin.RunCommand:input_type -> admin.RunCommandRequest
+	1, // 3: admin.Admin.RunCommand:output_type -> admin.RunCommandResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_admin_admin_proto_init() }
@@ -26,7 +26,9 @@ const (
 	CommandRunnerShutdownTimeout = 5 * time.Second
 )
 
-type CommandHandler func(ctx context.Context, data map[string]interface{}) error
+// CommandHandler runs a command and returns its output, if any. The output is returned to the
+// client as JSON.
+type CommandHandler func(ctx context.Context, data map[string]interface{}) (interface{}, error)
 type CommandValidator func(data map[string]interface{}) error
 type CommandRunnerOption func(*CommandRunner)
 
@@ -275,6 +277,7 @@ func (r *CommandRunner) processLoop(ctx context.Context) {
 
 			r.logger.Info().Str("command", command.command).Msg("received new command")
 
+			var output interface{}
 			var err error
 
 			if validator := r.getValidator(command.command); validator != nil {
@@ -287,7 +290,8 @@ func (r *CommandRunner) processLoop(ctx context.Context) {
 			if handler := r.getHandler(command.command); handler != nil {
 				// TODO: we can probably merge the command context with the worker context
 				// using something like: https://github.com/teivah/onecontext
-				if handleErr := handler(command.ctx, command.data); handleErr != nil {
+				var handleErr error
+				if output, handleErr = handler(command.ctx, command.data); handleErr != nil {
 					if errors.Is(handleErr, context.Canceled) {
 						err = status.Error(codes.Canceled, "client canceled")
 					} else if errors.Is(handleErr, context.DeadlineExceeded) {
@@ -302,7 +306,7 @@ func (r *CommandRunner) processLoop(ctx context.Context) {
 			}
 
 		sendResponse:
-			command.responseChan <- &CommandResponse{err}
+			command.responseChan <- &CommandResponse{output, err}
 			close(command.responseChan)
 		case <-ctx.Done():
 			return
@@ -90,10 +90,10 @@ func (suite *CommandRunnerSuite) SetupCommandRunner(opts ...CommandRunnerOption)
 func (suite *CommandRunnerSuite) TestHandler() {
 	called := false
 
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
@@ -101,7 +101,7 @@ func (suite *CommandRunnerSuite) TestHandler() {
 		suite.EqualValues(data["number"], 123)
 		called = true
 
-		return nil
+		return nil, nil
 	})
 
 	suite.SetupCommandRunner()
@@ -124,6 +124,32 @@ func (suite *CommandRunnerSuite) TestHandler() {
 	suite.True(called)
 }
 
+func (suite *CommandRunnerSuite) TestHandlerOutput() {
+	type output struct {
+		Name   string   `json:"name"`
+		Values []uint64 `json:"values"`
+	}
+
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+		return output{Name: "foo", Values: []uint64{1, 2}}, nil
+	})
+
+	suite.SetupCommandRunner()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	request := &pb.RunCommandRequest{
+		CommandName: "foo",
+	}
+
+	resp, err := suite.client.RunCommand(ctx, request)
+	suite.NoError(err)
+	suite.Equal(map[string]interface{}{
+		"name":   "foo",
+		"values": []interface{}{float64(1), float64(2)},
+	}, resp.GetOutput().AsInterface())
+}
+
 func (suite *CommandRunnerSuite) TestUnimplementedHandler() {
 	suite.SetupCommandRunner()
 
@@ -146,16 +172,16 @@ func (suite *CommandRunnerSuite) TestUnimplementedHandler() {
 func (suite *CommandRunnerSuite) TestValidator() {
 	calls := 0
 
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
 		calls += 1
 
-		return nil
+		return nil, nil
 	})
 
 	validatorErr := errors.New("unexpected value")
@@ -196,14 +222,14 @@ func (suite *CommandRunnerSuite) TestValidator() {
 
 func (suite *CommandRunnerSuite) TestHandlerError() {
 	handlerErr := errors.New("handler error")
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
-		return handlerErr
+		return nil, handlerErr
 	})
 
 	suite.SetupCommandRunner()
@@ -226,9 +252,9 @@ func (suite *CommandRunnerSuite) TestHandlerError() {
 }
 
 func (suite *CommandRunnerSuite) TestTimeout() {
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		<-ctx.Done()
-		return ctx.Err()
+		return nil, ctx.Err()
 	})
 
 	suite.SetupCommandRunner()
@@ -252,17 +278,17 @@ func (suite *CommandRunnerSuite) TestTimeout() {
 func (suite *CommandRunnerSuite) TestHTTPServer() {
 	called := false
 
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
 		suite.EqualValues(data["key"], "value")
 		called = true
 
-		return nil
+		return nil, nil
 	})
 
 	suite.SetupCommandRunner()
@@ -387,17 +413,17 @@ func generateCerts(t *testing.T) (tls.Certificate, *x509.CertPool, tls.Certifica
 func (suite *CommandRunnerSuite) TestTLS() {
 	called := false
 
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
 		suite.EqualValues(data["key"], "value")
 		called = true
 
-		return nil
+		return nil, nil
 	})
 
 	serverCert, serverCertPool, clientCert, clientCertPool := generateCerts(suite.T())
@@ -431,9 +457,9 @@ func (suite *CommandRunnerSuite) TestTLS() {
 }
 
 func (suite *CommandRunnerSuite) TestCleanup() {
-	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) error {
+	suite.bootstrapper.RegisterHandler("foo", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
 		<-ctx.Done()
-		return ctx.Err()
+		return nil, ctx.Err()
 	})
 
 	suite.SetupCommandRunner()
@@ -2,10 +2,12 @@ package admin
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	pb "github.com/onflow/flow-go/admin/admin"
 )
@@ -23,7 +25,8 @@ type CommandRequest struct {
 }
 
 type CommandResponse struct {
-	err error
+	output interface{}
+	err    error
 }
 
 func (s *adminServer) RunCommand(ctx context.Context, in *pb.RunCommandRequest) (*pb.RunCommandResponse, error) {
@@ -56,7 +59,32 @@ func (s *adminServer) RunCommand(ctx context.Context, in *pb.RunCommandRequest)
 		return nil, response.err
 	}
 
-	return &pb.RunCommandResponse{}, nil
+	if response.output == nil {
+		return &pb.RunCommandResponse{}, nil
+	}
+
+	output, err := toValue(response.output)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not encode command output: %v", err)
+	}
+
+	return &pb.RunCommandResponse{Output: output}, nil
+}
+
+// toValue converts the output of a command to a protobuf value, through its JSON encoding.
+func toValue(output interface{}) (*structpb.Value, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return structpb.NewValue(decoded)
 }
 
 func NewAdminServer(commandQ chan<- *CommandRequest) *adminServer {
@@ -35,6 +35,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/buffer"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
@@ -96,11 +97,14 @@ type AccessNodeConfig struct {
 	rpcConf                      rpc.Config
 	ExecutionNodeAddress         string // deprecated
 	HistoricalAccessRPCs         []access.AccessAPIClient
+	historicalArchiveDirs        []string
+	HistoricalArchives           history.Archives
 	logTxTimeToFinalized         bool
 	logTxTimeToExecuted          bool
 	logTxTimeToFinalizedExecuted bool
 	retryEnabled                 bool
 	rpcMetricsEnabled            bool
+	indexAccountTransactions     bool
 	baseOptions                  []cmd.Option
 }
 
@@ -128,6 +132,8 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		pingEnabled:                  false,
 		retryEnabled:                 false,
 		rpcMetricsEnabled:            false,
+		indexAccountTransactions:     false,
+		historicalArchiveDirs:        nil,
 		nodeInfoFile:                 "",
 		apiRatelimits:                nil,
 		apiBurstlimits:               nil,
@@ -266,7 +272,7 @@ func (builder *FlowAccessNodeBuilder) buildFollowerCore() *FlowAccessNodeBuilder
 func (builder *FlowAccessNodeBuilder) buildFollowerEngine() *FlowAccessNodeBuilder {
 	builder.Component("follower engine", func(_ cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 		// initialize cleaner for DB
-		cleaner := storage.NewCleaner(node.Logger, node.DB, builder.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
+		cleaner := storage.NewCleaner(node.Logger, node.BadgerDB, builder.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
 		conCache := buffer.NewPendingBlocks()
 
 		followerEng, err := follower.New(
@@ -430,7 +436,26 @@ func (anb *FlowAccessNodeBuilder) Build() AccessNodeBuilder {
 			anb.rpcConf.TransportCredentials = credentials.NewTLS(tlsConfig)
 			return nil
 		}).
+		Component("historical archives", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if len(anb.historicalArchiveDirs) == 0 {
+				return &module.NoopReadDoneAware{}, nil
+			}
+			node.Logger.Info().Strs("archive_dirs", anb.historicalArchiveDirs).Msg("historical archive directories")
+
+			archives, err := history.OpenAll(anb.historicalArchiveDirs)
+			if err != nil {
+				return nil, fmt.Errorf("could not open historical archives: %w", err)
+			}
+			anb.HistoricalArchives = archives
+			return newHistoricalArchives(archives, node.Logger), nil
+		}).
 		Component("RPC engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			// the transactions are only served by account if the node indexes them
+			accountTransactions := node.Storage.AccountTransactions
+			if !anb.indexAccountTransactions {
+				accountTransactions = nil
+			}
+
 			anb.RpcEng = rpc.New(
 				node.Logger,
 				node.State,
@@ -443,6 +468,8 @@ func (anb *FlowAccessNodeBuilder) Build() AccessNodeBuilder {
 				node.Storage.Transactions,
 				node.Storage.Receipts,
 				node.Storage.Results,
+				accountTransactions,
+				anb.HistoricalArchives,
 				node.RootChainID,
 				anb.TransactionMetrics,
 				anb.collectionGRPCPort,
@@ -471,7 +498,13 @@ func (anb *FlowAccessNodeBuilder) Build() AccessNodeBuilder {
 				return nil, fmt.Errorf("could not create requester engine: %w", err)
 			}
 
-			anb.IngestEng, err = ingestion.New(node.Logger, node.Network, node.State, node.Me, anb.RequestEng, node.Storage.Blocks, node.Storage.Headers, node.Storage.Collections, node.Storage.Transactions, node.Storage.Results, node.Storage.Receipts, anb.TransactionMetrics,
+			// the transactions of the finalized blocks are only indexed by account if enabled
+			accountTransactions := node.Storage.AccountTransactions
+			if !anb.indexAccountTransactions {
+				accountTransactions = nil
+			}
+
+			anb.IngestEng, err = ingestion.New(node.Logger, node.Network, node.State, node.Me, anb.RequestEng, node.Storage.Blocks, node.Storage.Headers, node.Storage.Collections, node.Storage.Transactions, node.Storage.Results, node.Storage.Receipts, accountTransactions, anb.TransactionMetrics,
 				anb.CollectionsToMarkFinalized, anb.CollectionsToMarkExecuted, anb.BlocksToMarkExecuted, anb.RpcEng)
 			if err != nil {
 				return nil, err
@@ -555,6 +588,7 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringVarP(&builder.rpcConf.CollectionAddr, "static-collection-ingress-addr", "", defaultConfig.rpcConf.CollectionAddr, "the address (of the collection node) to send transactions to")
 		flags.StringVarP(&builder.ExecutionNodeAddress, "script-addr", "s", defaultConfig.ExecutionNodeAddress, "the address (of the execution node) forward the script to")
 		flags.StringVarP(&builder.rpcConf.HistoricalAccessAddrs, "historical-access-addr", "", defaultConfig.rpcConf.HistoricalAccessAddrs, "comma separated rpc addresses for historical access nodes")
+		flags.StringSliceVar(&builder.historicalArchiveDirs, "historical-archive-dirs", defaultConfig.historicalArchiveDirs, "comma separated directories of the historical block archives to serve the blocks, collections, transactions and events missing from the protocol state")
 		flags.DurationVar(&builder.rpcConf.CollectionClientTimeout, "collection-client-timeout", defaultConfig.rpcConf.CollectionClientTimeout, "grpc client timeout for a collection node")
 		flags.DurationVar(&builder.rpcConf.ExecutionClientTimeout, "execution-client-timeout", defaultConfig.rpcConf.ExecutionClientTimeout, "grpc client timeout for an execution node")
 		flags.UintVar(&builder.rpcConf.MaxHeightRange, "rpc-max-height-range", defaultConfig.rpcConf.MaxHeightRange, "maximum size for height range requests")
@@ -566,6 +600,7 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.BoolVar(&builder.pingEnabled, "ping-enabled", defaultConfig.pingEnabled, "whether to enable the ping process that pings all other peers and report the connectivity to metrics")
 		flags.BoolVar(&builder.retryEnabled, "retry-enabled", defaultConfig.retryEnabled, "whether to enable the retry mechanism at the access node level")
 		flags.BoolVar(&builder.rpcMetricsEnabled, "rpc-metrics-enabled", defaultConfig.rpcMetricsEnabled, "whether to enable the rpc metrics")
+		flags.BoolVar(&builder.indexAccountTransactions, "index-account-transactions", defaultConfig.indexAccountTransactions, "whether to index the transactions of the finalized blocks by the accounts they touch")
 		flags.StringVarP(&builder.nodeInfoFile, "node-info-file", "", defaultConfig.nodeInfoFile, "full path to a json file which provides more details about nodes when reporting its reachability metrics")
 		flags.StringToIntVar(&builder.apiRatelimits, "api-rate-limits", defaultConfig.apiRatelimits, "per second rate limits for Access API methods e.g. Ping=300,GetTransaction=500 etc.")
 		flags.StringToIntVar(&builder.apiBurstlimits, "api-burst-limits", defaultConfig.apiBurstlimits, "burst limits for Access API methods e.g. Ping=100,GetTransaction=100 etc.")
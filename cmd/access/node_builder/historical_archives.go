@@ -0,0 +1,39 @@
+package node_builder
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module/history"
+	"github.com/onflow/flow-go/module/lifecycle"
+)
+
+// historicalArchives closes the historical archives served by the node on shutdown. It is registered before the
+// engines reading the archives, so that it is shut down after them.
+type historicalArchives struct {
+	lm       *lifecycle.LifecycleManager
+	archives history.Archives
+	log      zerolog.Logger
+}
+
+func newHistoricalArchives(archives history.Archives, log zerolog.Logger) *historicalArchives {
+	return &historicalArchives{
+		lm:       lifecycle.NewLifecycleManager(),
+		archives: archives,
+		log:      log,
+	}
+}
+
+func (h *historicalArchives) Ready() <-chan struct{} {
+	h.lm.OnStart(func() {})
+	return h.lm.Started()
+}
+
+func (h *historicalArchives) Done() <-chan struct{} {
+	h.lm.OnStop(func() {
+		err := h.archives.Close()
+		if err != nil {
+			h.log.Error().Err(err).Msg("could not close historical archives")
+		}
+	})
+	return h.lm.Stopped()
+}
@@ -169,7 +169,10 @@ func (builder *StakedAccessNodeBuilder) enqueueUnstakedNetworkInit(ctx context.C
 
 		msgValidators := unstakedNetworkMsgValidators(node.Logger, node.IdentityProvider, builder.NodeID)
 
-		middleware := builder.initMiddleware(builder.NodeID, node.Metrics.Network, libP2PFactory, msgValidators...)
+		middleware, err := builder.initMiddleware(builder.NodeID, node.Metrics.Network, libP2PFactory, msgValidators...)
+		if err != nil {
+			return nil, err
+		}
 
 		// topology returns empty list since peers are not known upfront
 		top := topology.EmptyListTopology{}
@@ -228,6 +231,7 @@ func (builder *StakedAccessNodeBuilder) initLibP2PFactory(ctx context.Context,
 			// act as a DHT server
 			SetDHTOptions(dhtOptions...).
 			SetPubsubOptions(psOpts...).
+			SetPeerScoring(p2p.NewPeerScoring(builder.Logger, builder.Metrics.Network)).
 			SetLogger(builder.Logger).
 			SetResolver(resolver).
 			Build(ctx)
@@ -244,7 +248,12 @@ func (builder *StakedAccessNodeBuilder) initLibP2PFactory(ctx context.Context,
 func (builder *StakedAccessNodeBuilder) initMiddleware(nodeID flow.Identifier,
 	networkMetrics module.NetworkMetrics,
 	factoryFunc p2p.LibP2PFactoryFunc,
-	validators ...network.MessageValidator) network.Middleware {
+	validators ...network.MessageValidator) (network.Middleware, error) {
+
+	inboundRateLimits, err := builder.InboundRateLimits.Limits()
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbound rate limits: %w", err)
+	}
 
 	// disable connection pruning for the staked AN which supports the unstaked AN
 	peerManagerFactory := p2p.PeerManagerFactory([]p2p.Option{p2p.WithInterval(builder.PeerUpdateInterval)}, p2p.WithConnectionPruning(false))
@@ -260,8 +269,9 @@ func (builder *StakedAccessNodeBuilder) initMiddleware(nodeID flow.Identifier,
 		builder.IDTranslator,
 		p2p.WithMessageValidators(validators...),
 		p2p.WithPeerManager(peerManagerFactory),
+		p2p.WithInboundRateLimits(inboundRateLimits),
 		// use default identifier provider
 	)
 
-	return builder.Middleware
+	return builder.Middleware, nil
 }
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
 
 	"github.com/spf13/pflag"
+	"go.uber.org/atomic"
 
 	"github.com/onflow/flow-go-sdk/client"
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -50,6 +52,7 @@ import (
 	chmodule "github.com/onflow/flow-go/module/chunks"
 	dkgmodule "github.com/onflow/flow-go/module/dkg"
 	"github.com/onflow/flow-go/module/epochs"
+	"github.com/onflow/flow-go/module/executionfork"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/mempool"
 	consensusMempools "github.com/onflow/flow-go/module/mempool/consensus"
@@ -89,6 +92,7 @@ func main() {
 		requiredApprovalsForSealVerification   uint
 		requiredApprovalsForSealConstruction   uint
 		emergencySealing                       bool
+		crashOnExecutionFork                   bool
 		dkgControllerConfig                    dkgmodule.ControllerConfig
 		startupTimeString                      string
 		startupTime                            time.Time
@@ -110,6 +114,9 @@ func main() {
 		receiptRequester        *requester.Engine
 		syncCore                *synchronization.Core
 		comp                    *compliance.Engine
+		sealingEngine           *sealing.Engine
+		startedSealingEngine    atomic.Value // the sealing engine once built, read by the admin server
+		forkReporter            *executionfork.Reporter
 		conMetrics              module.ConsensusMetrics
 		mainMetrics             module.HotstuffMetrics
 		receiptValidator        module.ReceiptValidator
@@ -144,6 +151,7 @@ func main() {
 		flags.UintVar(&requiredApprovalsForSealVerification, "required-verification-seal-approvals", validation.DefaultRequiredApprovalsForSealValidation, "minimum number of approvals that are required to verify a seal")
 		flags.UintVar(&requiredApprovalsForSealConstruction, "required-construction-seal-approvals", sealing.DefaultRequiredApprovalsForSealConstruction, "minimum number of approvals that are required to construct a seal")
 		flags.BoolVar(&emergencySealing, "emergency-sealing-active", sealing.DefaultEmergencySealingActive, "(de)activation of emergency sealing")
+		flags.BoolVar(&crashOnExecutionFork, "crash-on-execution-fork", true, "crash the node when an execution fork is detected, after reporting it. When disabled, the node keeps running with sealing halted, and serves the fork report through the get-execution-fork admin command")
 		flags.BoolVar(&insecureAccessAPI, "insecure-access-api", false, "required if insecure GRPC connection should be used")
 		flags.StringSliceVar(&accessNodeIDS, "access-node-ids", []string{}, fmt.Sprintf("array of access node ID's sorted in priority order where the first ID in this array will get the first connection attempt and each subsequent ID after serves as a fallback. minimum length %d", common.DefaultAccessNodeIDSMinimum))
 		flags.DurationVar(&dkgControllerConfig.BaseStartDelay, "dkg-controller-base-start-delay", dkgmodule.DefaultBaseStartDelay, "used to define the range for jitter prior to DKG start (eg. 500µs) - the base value is scaled quadratically with the # of DKG participants")
@@ -339,13 +347,32 @@ func main() {
 		Module("block seals mempool", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
 			// use a custom ejector so we don't eject seals that would break
 			// the chain of seals
-			seals, err = consensusMempools.NewExecStateForkSuppressor(consensusMempools.LogForkAndCrash(node.Logger), node.DB, node.Logger, sealLimit)
+			// execution forks are reported for operators to resolve them; the node crashes
+			// afterwards, unless it is configured to keep running with sealing halted
+			forkReporter = executionfork.NewReporter(node.Logger, conMetrics)
+			reportFork := consensusMempools.ReportFork(node.Logger, forkReporter, node.Storage.Receipts)
+			onExecFork := reportFork
+			if crashOnExecutionFork {
+				crash := consensusMempools.LogForkAndCrash(node.Logger)
+				onExecFork = func(conflictingSeals []*flow.IncorporatedResultSeal) {
+					reportFork(conflictingSeals)
+					crash(conflictingSeals)
+				}
+			}
+			seals, err = consensusMempools.NewExecStateForkSuppressor(onExecFork, node.DB, node.Logger, sealLimit)
 			if err != nil {
 				return fmt.Errorf("failed to wrap seals mempool into ExecStateForkSuppressor: %w", err)
 			}
 			err = node.Metrics.Mempool.Register(metrics.ResourcePendingIncorporatedSeal, seals.Size)
 			return nil
 		}).
+		AdminCommand("get-execution-fork", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+			report, detected := forkReporter.Detected()
+			if !detected {
+				return "no execution fork detected", nil
+			}
+			return report, nil
+		}, nil).
 		Module("pending receipts mempool", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
 			pendingReceipts = stdmap.NewPendingReceipts(node.Storage.Headers, pendingReceiptsLimit)
 			return nil
@@ -402,7 +429,7 @@ func main() {
 			config.EmergencySealingActive = emergencySealing
 			config.RequiredApprovalsForSealConstruction = requiredApprovalsForSealConstruction
 
-			e, err := sealing.NewEngine(
+			sealingEngine, err = sealing.NewEngine(
 				node.Logger,
 				node.Tracer,
 				conMetrics,
@@ -423,11 +450,35 @@ func main() {
 				config,
 			)
 
+			if err != nil {
+				return nil, err
+			}
+
 			// subscribe for finalization events from hotstuff
-			finalizationDistributor.AddOnBlockFinalizedConsumer(e.OnFinalizedBlock)
-			finalizationDistributor.AddOnBlockIncorporatedConsumer(e.OnBlockIncorporated)
+			finalizationDistributor.AddOnBlockFinalizedConsumer(sealingEngine.OnFinalizedBlock)
+			finalizationDistributor.AddOnBlockIncorporatedConsumer(sealingEngine.OnBlockIncorporated)
 
-			return e, err
+			startedSealingEngine.Store(sealingEngine)
+			return sealingEngine, nil
+		}).
+		AdminCommand("get-sealing-progress", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+			started, ok := startedSealingEngine.Load().(*sealing.Engine)
+			if !ok {
+				return nil, fmt.Errorf("sealing engine is not started yet")
+			}
+			maxBlocks := uint(sealing.DefaultSealingProgressMaxBlocks)
+			if max, ok := data["max-blocks"]; ok {
+				maxBlocks = uint(max.(float64))
+			}
+			return started.SealingProgress(maxBlocks)
+		}, func(data map[string]interface{}) error {
+			if max, ok := data["max-blocks"]; ok {
+				blocks, ok := max.(float64)
+				if !ok || blocks < 1 {
+					return fmt.Errorf("the max-blocks field must be a positive number of blocks")
+				}
+			}
+			return nil
 		}).
 		Component("matching engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			receiptRequester, err = requester.New(
@@ -515,7 +566,7 @@ func main() {
 			// TODO: we should probably find a way to initialize mutually dependent engines separately
 
 			// initialize the entity database accessors
-			cleaner := bstorage.NewCleaner(node.Logger, node.DB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
+			cleaner := bstorage.NewCleaner(node.Logger, node.BadgerDB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
 
 			// initialize the pending blocks cache
 			proposals := buffer.NewPendingBlocks()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -29,13 +30,16 @@ import (
 	"github.com/onflow/flow-go/engine/execution/checker"
 	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
 	exeprovider "github.com/onflow/flow-go/engine/execution/provider"
+	"github.com/onflow/flow-go/engine/execution/pruner"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/extralog"
+	ledgerState "github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	ledger "github.com/onflow/flow-go/ledger/complete"
 	"github.com/onflow/flow-go/ledger/complete/wal"
@@ -45,7 +49,9 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/backup"
 	"github.com/onflow/flow-go/module/buffer"
+	"github.com/onflow/flow-go/module/executionfork"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/signature"
@@ -54,6 +60,8 @@ import (
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 func main() {
@@ -68,6 +76,7 @@ func main() {
 		myReceipts                    *storage.MyExecutionReceipts
 		providerEngine                *exeprovider.Engine
 		checkerEng                    *checker.Engine
+		forkReporter                  *executionfork.Reporter
 		syncCore                      *chainsync.Core
 		pendingBlocks                 *buffer.PendingBlocks // used in follower engine
 		deltas                        *ingestion.Deltas
@@ -82,6 +91,7 @@ func main() {
 		err                           error
 		executionState                state.ExecutionState
 		triedir                       string
+		executionStateArchive         string
 		collector                     module.ExecutionMetrics
 		mTrieCacheSize                uint32
 		transactionResultsCacheSize   uint
@@ -89,6 +99,7 @@ func main() {
 		checkpointsToKeep             uint
 		stateDeltasLimit              uint
 		cadenceExecutionCache         uint
+		programsWarmUpSize            uint
 		chdpCacheSize                 uint
 		requestInterval               time.Duration
 		preferredExeNodeIDStr         string
@@ -97,6 +108,9 @@ func main() {
 		syncThreshold                 int
 		extensiveLog                  bool
 		pauseExecution                bool
+		indexAccounts                 bool
+		indexOwners                   bool
+		pruningConfig                 pruner.Config
 		checkStakedAtBlock            func(blockID flow.Identifier) (bool, error)
 		diskWAL                       *wal.DiskWAL
 		scriptLogThreshold            time.Duration
@@ -119,11 +133,13 @@ func main() {
 			flags.StringVarP(&rpcConf.ListenAddr, "rpc-addr", "i", "localhost:9000", "the address the gRPC server listens on")
 			flags.BoolVar(&rpcConf.RpcMetricsEnabled, "rpc-metrics-enabled", false, "whether to enable the rpc metrics")
 			flags.StringVar(&triedir, "triedir", datadir, "directory to store the execution State")
+			flags.StringVar(&executionStateArchive, "execution-state-archive", "", "execution state archive to bootstrap from, created by the export-execution-state-archive util command. Only used if the node has not been bootstrapped yet")
 			flags.Uint32Var(&mTrieCacheSize, "mtrie-cache-size", 500, "cache size for MTrie")
 			flags.UintVar(&checkpointDistance, "checkpoint-distance", 40, "number of WAL segments between checkpoints")
 			flags.UintVar(&checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
 			flags.UintVar(&stateDeltasLimit, "state-deltas-limit", 100, "maximum number of state deltas in the memory pool")
 			flags.UintVar(&cadenceExecutionCache, "cadence-execution-cache", computation.DefaultProgramsCacheSize, "cache size for Cadence execution")
+			flags.UintVar(&programsWarmUpSize, "programs-warm-up-size", computation.DefaultProgramsWarmUpSize, "maximum number of most used contracts loaded into the Cadence execution cache on startup (0 to disable)")
 			flags.UintVar(&chdpCacheSize, "chdp-cache", storage.DefaultCacheSize, "cache size for Chunk Data Packs")
 			flags.DurationVar(&requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 			flags.DurationVar(&scriptLogThreshold, "script-log-threshold", computation.DefaultScriptLogThreshold, "threshold for logging script execution")
@@ -136,11 +152,22 @@ func main() {
 			flags.UintVar(&chdpQueryTimeout, "chunk-data-pack-query-timeout-sec", 10, "number of seconds to determine a chunk data pack query being slow")
 			flags.UintVar(&chdpDeliveryTimeout, "chunk-data-pack-delivery-timeout-sec", 10, "number of seconds to determine a chunk data pack response delivery being slow")
 			flags.BoolVar(&pauseExecution, "pause-execution", false, "pause the execution. when set to true, no block will be executed, but still be able to serve queries")
+			flags.BoolVar(&indexAccounts, "index-account-transactions", false, "index the transactions by the accounts they touch as payer, proposer or authorizer")
+			flags.BoolVar(&indexOwners, "index-account-register-owners", false, "index the transactions by the owners of the registers they write as well, requires index-account-transactions")
+			flags.Uint64Var(&pruningConfig.RetainHeights, "pruning-retain-heights", 0, fmt.Sprintf("number of the latest sealed heights whose chunk data packs, events, transaction results and execution state interactions are retained, at least %d (0 to disable pruning)", pruner.MinRetainHeights))
+			flags.UintVar(&pruningConfig.BatchSize, "pruning-batch-size", pruner.DefaultBatchSize, "number of heights pruned in a single write batch")
+			flags.DurationVar(&pruningConfig.Interval, "pruning-interval", pruner.DefaultInterval, "interval between two pruning rounds")
 			flags.BoolVar(&enableBlockDataUpload, "enable-blockdata-upload", false, "enable uploading block data to Cloud Bucket")
 			flags.StringVar(&gcpBucketName, "gcp-bucket-name", "", "GCP Bucket name for block data uploader")
 			flags.StringVar(&s3BucketName, "s3-bucket-name", "", "S3 Bucket name for block data uploader")
 		}).
 		ValidateFlags(func() error {
+			if indexOwners && !indexAccounts {
+				return fmt.Errorf("invalid flag. index-account-register-owners requires index-account-transactions")
+			}
+			if pruningConfig.RetainHeights > 0 && pruningConfig.RetainHeights < pruner.MinRetainHeights {
+				return fmt.Errorf("invalid flag. pruning-retain-heights must be at least %d", pruner.MinRetainHeights)
+			}
 			if enableBlockDataUpload {
 				if gcpBucketName == "" && s3BucketName == "" {
 					return fmt.Errorf("invalid flag. gcp-bucket-name or s3-bucket-name required when blockdata-uploader is enabled")
@@ -154,6 +181,63 @@ func main() {
 	}
 
 	nodeBuilder.
+		PreInit(func(builder cmd.NodeBuilder, node *cmd.NodeConfig) {
+			if executionStateArchive == "" {
+				return
+			}
+
+			// the archive replaces the root checkpoint and root protocol state snapshot of the bootstrap
+			// folder, which must only happen before the node is bootstrapped from them
+			bootstrapped, err := badgerState.IsBootstrapped(node.DB)
+			if err != nil {
+				node.Logger.Fatal().Err(err).Msg("could not determine whether database contains bootstrapped state")
+			}
+			if bootstrapped {
+				node.Logger.Warn().
+					Str("archive", executionStateArchive).
+					Msg("database is already bootstrapped, ignoring execution state archive")
+				return
+			}
+
+			manifest, err := importExecutionStateArchive(executionStateArchive, node.BootstrapDir)
+			if err != nil {
+				node.Logger.Fatal().Err(err).Str("archive", executionStateArchive).Msg("could not import execution state archive")
+			}
+
+			node.Logger.Info().
+				Str("archive", executionStateArchive).
+				Hex("sealed_block_id", manifest.BlockID[:]).
+				Uint64("sealed_block_height", manifest.Height).
+				Hex("state_commitment", manifest.StateCommitment[:]).
+				Msg("execution state archive imported")
+		}).
+		PreInit(func(builder cmd.NodeBuilder, node *cmd.NodeConfig) {
+			if node.RestoredBackup == nil {
+				return
+			}
+
+			// the checkpoint of the backup is only consistent with the public db of the same backup, which
+			// was just restored into an empty data directory, so the execution state must not exist either
+			entries, err := ioutil.ReadDir(triedir)
+			if err != nil && !os.IsNotExist(err) {
+				node.Logger.Fatal().Err(err).Msg("could not read trie directory")
+			}
+			if len(entries) > 0 {
+				node.Logger.Fatal().
+					Str("backup", node.BackupRestoreDir).
+					Str("triedir", triedir).
+					Msg("execution state already exists while the public db was restored from backup, remove it to restore the execution state of the backup")
+			}
+
+			err = backup.RestoreFile(node.BackupRestoreDir, node.RestoredBackup, executionStateBackupFilename, filepath.Join(triedir, bootstrapFilenames.FilenameWALRootCheckpoint))
+			if err != nil {
+				node.Logger.Fatal().Err(err).Str("backup", node.BackupRestoreDir).Msg("could not restore execution state from backup")
+			}
+
+			node.Logger.Info().
+				Str("backup", node.BackupRestoreDir).
+				Msg("execution state restored from backup")
+		}).
 		Module("mutable follower state", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
 			// For now, we only support state implementations from package badger.
 			// If we ever support different implementations, the following can be replaced by a type-aware factory
@@ -175,6 +259,11 @@ func main() {
 			collector = metrics.NewExecutionCollector(node.Tracer, node.MetricsRegisterer)
 			return nil
 		}).
+		Module("execution fork reporter", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
+			// the report is persisted, to be served after the node crashed on the fork
+			forkReporter, err = executionfork.NewPersistentReporter(node.Logger, collector, node.DB)
+			return err
+		}).
 		Module("sync core", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
 			syncCore, err = chainsync.New(node.Logger, chainsync.DefaultConfig())
 			return err
@@ -287,7 +376,14 @@ func main() {
 
 				// TODO: check that the checkpoint file contains the root block's statecommit hash
 
-				err = bootstrapper.BootstrapExecutionDatabase(node.DB, node.RootSeal.FinalState, node.RootBlock.Header)
+				// the root state commitment is the state of the block sealed by the root snapshot,
+				// which is the root block itself for a spork root snapshot
+				sealedRoot, err := node.Storage.Headers.ByBlockID(node.RootSeal.BlockID)
+				if err != nil {
+					return nil, fmt.Errorf("could not get sealed root block: %w", err)
+				}
+
+				err = bootstrapper.BootstrapExecutionDatabase(node.DB, node.RootSeal.FinalState, sealedRoot)
 				if err != nil {
 					return nil, fmt.Errorf("could not bootstrap execution database: %w", err)
 				}
@@ -302,7 +398,13 @@ func main() {
 			}
 
 			ledgerStorage, err = ledger.NewLedger(diskWAL, int(mTrieCacheSize), collector, node.Logger.With().Str("subcomponent", "ledger").Logger(), ledger.DefaultPathFinderVersion)
-			return ledgerStorage, err
+			if err != nil {
+				return nil, err
+			}
+
+			node.Backup.AddArtifact(executionStateBackupArtifact(ledgerStorage))
+
+			return ledgerStorage, nil
 		}).
 		Component("execution state ledger WAL compactor", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 
@@ -329,6 +431,13 @@ func main() {
 			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
 
 			committer := committer.NewLedgerViewCommitter(ledgerStorage, node.Tracer)
+
+			// the owners of the written registers are only collected to index the transactions by them
+			var computerOpts []computer.BlockComputerOption
+			if indexOwners {
+				computerOpts = append(computerOpts, computer.WithRegisterOwners())
+			}
+
 			manager, err := computation.New(
 				node.Logger,
 				collector,
@@ -341,6 +450,7 @@ func main() {
 				committer,
 				scriptLogThreshold,
 				blockDataUploaders,
+				computerOpts...,
 			)
 			if err != nil {
 				return nil, err
@@ -368,6 +478,7 @@ func main() {
 				events,
 				serviceEvents,
 				txResults,
+				node.Storage.AccountTransactions,
 				node.DB,
 				node.Tracer,
 			)
@@ -387,15 +498,54 @@ func main() {
 
 			return providerEngine, err
 		}).
+		Component("programs warmer", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			warmer := computation.NewProgramsWarmer(
+				node.Logger,
+				collector,
+				computationManager,
+				executionState,
+				node.Storage.Headers,
+				path.Join(triedir, "programs_usage.json"),
+				programsWarmUpSize,
+			)
+			return warmer, nil
+		}).
+		Component("pruner", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if pruningConfig.RetainHeights == 0 {
+				return &module.NoopReadDoneAware{}, nil
+			}
+
+			return pruner.New(
+				node.Logger,
+				collector,
+				node.DB,
+				node.State,
+				executionState,
+				node.Storage.Headers,
+				results,
+				storage.NewConsumerProgress(node.DB, module.ConsumeProgressExecutionPrunedHeight),
+				pruningConfig,
+			)
+		}).
 		Component("checker engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			checkerEng = checker.New(
 				node.Logger,
 				node.State,
 				executionState,
 				node.Storage.Seals,
+				results,
+				node.Storage.Receipts,
+				forkReporter,
 			)
 			return checkerEng, nil
 		}).
+		AdminCommand("get-execution-fork", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+			report, detected := forkReporter.Detected()
+			if !detected {
+				return "no execution fork detected", nil
+			}
+			return report, nil
+		}, nil).
 		Component("ingestion engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			collectionRequester, err = requester.New(node.Logger, node.Metrics.Engine, node.Network, node.Me, node.State,
 				engine.RequestCollections,
@@ -440,6 +590,8 @@ func main() {
 				syncFast,
 				checkStakedAtBlock,
 				pauseExecution,
+				indexAccounts,
+				indexOwners,
 			)
 
 			// TODO: we should solve these mutual dependencies better
@@ -453,7 +605,7 @@ func main() {
 		Component("follower engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 
 			// initialize cleaner for DB
-			cleaner := storage.NewCleaner(node.Logger, node.DB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
+			cleaner := storage.NewCleaner(node.Logger, node.BadgerDB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
 
 			// create a finalizer that handles updating the protocol
 			// state when the follower detects newly finalized blocks
@@ -564,6 +716,50 @@ func main() {
 		}).Run()
 }
 
+// importExecutionStateArchive verifies the execution state archive and places its root checkpoint
+// and root protocol state snapshot in the bootstrap folder, from which the node is then bootstrapped.
+func importExecutionStateArchive(archive string, dir string) (*bootstrap.ArchiveManifest, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return bootstrap.ImportArchive(file, dir)
+}
+
+// executionStateBackupFilename is the name of the file of a backup holding the checkpoint of the execution state.
+const executionStateBackupFilename = "execution-state.checkpoint"
+
+// executionStateBackupArtifact returns the backup artifact holding a checkpoint of the execution state at the
+// highest executed block of the backup, which is restored as the root checkpoint of the ledger. The trie of the
+// execution state is retained when the backup starts, as it may be evicted from the ledger by the time the
+// checkpoint is written.
+func executionStateBackupArtifact(l *ledger.Ledger) backup.Artifact {
+	return backup.Artifact{
+		Filename: executionStateBackupFilename,
+		Prepare: func(tx kv.Txn) (backup.WriteFunc, error) {
+			var blockID flow.Identifier
+			err := operation.RetrieveExecutedBlock(&blockID)(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not retrieve highest executed block: %w", err)
+			}
+			var commit flow.StateCommitment
+			err = operation.LookupStateCommitment(blockID, &commit)(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not look up state commitment of block %v: %w", blockID, err)
+			}
+			write, err := l.CheckpointerAt(ledgerState.State(commit))
+			if err != nil {
+				return nil, fmt.Errorf("could not retain execution state of block %v: %w", blockID, err)
+			}
+			return func(_ context.Context, w io.Writer) error {
+				return write(w)
+			}, nil
+		},
+	}
+}
+
 // copy the checkpoint files from the bootstrap folder to the execution state folder
 // Checkpoint file is required to restore the trie, and has to be placed in the execution
 // state folder.
@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // MarkFlagRequired marks a flag added to a cobra command as required. Panics
@@ -13,3 +20,71 @@ func MarkFlagRequired(command *cobra.Command, flagName string) {
 		panic("marked unknown flag as required: " + err.Error())
 	}
 }
+
+// StringToFloat64Var defines a flag of a map of floats, formatted as key=value pairs separated
+// by commas, e.g. a=0.5,b=2. Like the other map flags, repeated flags are merged.
+func StringToFloat64Var(flags *pflag.FlagSet, p *map[string]float64, name string, value map[string]float64, usage string) {
+	flags.Var(newStringToFloat64Value(value, p), name, usage)
+}
+
+// stringToFloat64Value is the pflag.Value of a map of floats.
+type stringToFloat64Value struct {
+	value   *map[string]float64
+	changed bool
+}
+
+var _ pflag.Value = (*stringToFloat64Value)(nil)
+
+func newStringToFloat64Value(value map[string]float64, p *map[string]float64) *stringToFloat64Value {
+	*p = value
+	return &stringToFloat64Value{value: p}
+}
+
+func (s *stringToFloat64Value) Set(val string) error {
+	pairs := strings.Split(val, ",")
+	out := make(map[string]float64, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("%s must be formatted as key=value", pair)
+		}
+		f, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid value of %s: %w", kv[0], err)
+		}
+		out[kv[0]] = f
+	}
+
+	if !s.changed || *s.value == nil {
+		*s.value = out
+	} else {
+		for k, v := range out {
+			(*s.value)[k] = v
+		}
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *stringToFloat64Value) Type() string {
+	return "stringToFloat64"
+}
+
+func (s *stringToFloat64Value) String() string {
+	keys := make([]string, 0, len(*s.value))
+	for k := range *s.value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		buf.WriteString(k)
+		buf.WriteRune('=')
+		buf.WriteString(strconv.FormatFloat((*s.value)[k], 'g', -1, 64))
+	}
+	return "[" + buf.String() + "]"
+}
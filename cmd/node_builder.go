@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -10,19 +11,25 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
 
 	"github.com/onflow/flow-go/admin"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/backup"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/envelope"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/events"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/migration"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 const NotSet = "not set"
@@ -113,12 +120,22 @@ type BaseConfig struct {
 	datadir               string
 	secretsdir            string
 	secretsDBEnabled      bool
+	DBMigration           migration.Config
+	BackupRestoreDir      string
 	level                 string
 	metricsPort           uint
 	BootstrapDir          string
 	PeerUpdateInterval    time.Duration
+	PeerUpdateMinInterval time.Duration
+	TopologyCheckInterval time.Duration
 	UnicastMessageTimeout time.Duration
 	DNSCacheTTL           time.Duration
+	InboundRateLimits     InboundRateLimitConfig
+	ConnLimits            p2p.ConnLimits
+	Transports            []string
+	OutboundQueueSize     int
+	NetworkCapture        NetworkCaptureConfig
+	Envelopes             EnvelopeConfig
 	profilerEnabled       bool
 	profilerDir           string
 	profilerInterval      time.Duration
@@ -143,8 +160,11 @@ type NodeConfig struct {
 	Tracer            module.Tracer
 	MetricsRegisterer prometheus.Registerer
 	Metrics           Metrics
-	DB                *badger.DB
-	SecretsDB         *badger.DB
+	DB                kv.DB
+	BadgerDB          *badger.DB // badger database DB is stored in, for badger specific operations such as value log GC
+	SecretsDB         kv.DB
+	Backup            *backup.Manager
+	RestoredBackup    *backup.Manifest // backup the public db was restored from at startup, nil if it was not restored
 	Storage           Storage
 	ProtocolEvents    *events.Distributor
 	State             protocol.State
@@ -187,6 +207,11 @@ func DefaultBaseConfig() *BaseConfig {
 		secretsDBEnabled:      true,
 		level:                 "info",
 		PeerUpdateInterval:    p2p.DefaultPeerUpdateInterval,
+		PeerUpdateMinInterval: p2p.DefaultPeerUpdateMinInterval,
+		ConnLimits:            p2p.DefaultConnLimits,
+		Transports:            []string{string(p2p.TransportTCP)},
+		OutboundQueueSize:     0,
+		TopologyCheckInterval: p2p.DefaultTopologyCheckInterval,
 		UnicastMessageTimeout: p2p.DefaultUnicastTimeout,
 		metricsPort:           8080,
 		profilerEnabled:       false,
@@ -198,5 +223,75 @@ func DefaultBaseConfig() *BaseConfig {
 		metricsEnabled:        true,
 		receiptsCacheSize:     bstorage.DefaultCacheSize,
 		guaranteesCacheSize:   bstorage.DefaultCacheSize,
+		NetworkCapture: NetworkCaptureConfig{
+			MaxFileSize: capture.DefaultMaxFileSize,
+			MaxFiles:    capture.DefaultMaxFiles,
+		},
 	}
 }
+
+// NetworkCaptureConfig is the configuration of the capture of the messages sent and received by the node.
+// An empty directory disables the capture.
+type NetworkCaptureConfig struct {
+	Dir         string // directory of the capture files
+	MaxFileSize int64  // size in bytes after which the capture file is rotated
+	MaxFiles    int    // number of capture files kept, the oldest ones are removed
+}
+
+// EnvelopeConfig designates the channels whose messages are sent in envelopes signed with the staking key
+// of their sender, and optionally encrypted to the networking keys of their recipients.
+type EnvelopeConfig struct {
+	SignedChannels    []string // channels whose envelopes are signed
+	EncryptedChannels []string // channels whose envelopes are signed and encrypted
+}
+
+// Channels returns the envelope mode of each designated channel, nil if no channel is designated.
+func (c EnvelopeConfig) Channels() map[network.Channel]envelope.Mode {
+	if len(c.SignedChannels) == 0 && len(c.EncryptedChannels) == 0 {
+		return nil
+	}
+	channels := make(map[network.Channel]envelope.Mode)
+	for _, channel := range c.SignedChannels {
+		channels[network.Channel(channel)] = envelope.Signed
+	}
+	for _, channel := range c.EncryptedChannels {
+		channels[network.Channel(channel)] = envelope.Encrypted
+	}
+	return channels
+}
+
+// InboundRateLimitConfig is the configuration of the rate limits on the inbound traffic of each peer.
+// Zero rates disable the limits.
+type InboundRateLimitConfig struct {
+	StreamRate    float64            // unicast streams opened per second by a peer
+	StreamBurst   int                // unicast streams opened at once by a peer
+	MessageRate   float64            // messages per second received from a peer on all channels
+	MessageBurst  int                // messages received at once from a peer on all channels
+	ChannelRates  map[string]float64 // messages per second received from a peer on a channel
+	ChannelBursts map[string]int     // messages received at once from a peer on a channel
+	DenyDuration  time.Duration      // how long to reject the connections of a peer exceeding a limit
+}
+
+// Limits returns the inbound rate limits of the middleware.
+// It returns an error if a channel has a burst limit but no rate limit, as its burst limit would be ignored.
+func (c InboundRateLimitConfig) Limits() (p2p.InboundRateLimits, error) {
+	for channel := range c.ChannelBursts {
+		if _, ok := c.ChannelRates[channel]; !ok {
+			return p2p.InboundRateLimits{}, fmt.Errorf("burst limit of channel %s has no matching rate limit", channel)
+		}
+	}
+
+	limits := p2p.InboundRateLimits{
+		Streams:      p2p.RateLimit{Rate: rate.Limit(c.StreamRate), Burst: c.StreamBurst},
+		Messages:     p2p.RateLimit{Rate: rate.Limit(c.MessageRate), Burst: c.MessageBurst},
+		Channels:     make(map[network.Channel]p2p.RateLimit, len(c.ChannelRates)),
+		DenyDuration: c.DenyDuration,
+	}
+	for channel, r := range c.ChannelRates {
+		limits.Channels[network.Channel(channel)] = p2p.RateLimit{
+			Rate:  rate.Limit(r),
+			Burst: c.ChannelBursts[channel],
+		}
+	}
+	return limits, nil
+}
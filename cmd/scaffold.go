@@ -20,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
+	"go.uber.org/atomic"
 
 	"github.com/onflow/flow-go/admin"
 	"github.com/onflow/flow-go/cmd/build"
@@ -28,12 +29,15 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/backup"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/lifecycle"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/network/capture"
 	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/envelope"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/network/topology"
@@ -43,7 +47,10 @@ import (
 	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/storage"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/migration"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	sutil "github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/debug"
 	"github.com/onflow/flow-go/utils/io"
@@ -60,20 +67,21 @@ type Metrics struct {
 }
 
 type Storage struct {
-	Headers      storage.Headers
-	Index        storage.Index
-	Identities   storage.Identities
-	Guarantees   storage.Guarantees
-	Receipts     *bstorage.ExecutionReceipts
-	Results      storage.ExecutionResults
-	Seals        storage.Seals
-	Payloads     storage.Payloads
-	Blocks       storage.Blocks
-	Transactions storage.Transactions
-	Collections  storage.Collections
-	Setups       storage.EpochSetups
-	Commits      storage.EpochCommits
-	Statuses     storage.EpochStatuses
+	Headers             storage.Headers
+	Index               storage.Index
+	Identities          storage.Identities
+	Guarantees          storage.Guarantees
+	Receipts            *bstorage.ExecutionReceipts
+	Results             storage.ExecutionResults
+	Seals               storage.Seals
+	Payloads            storage.Payloads
+	Blocks              storage.Blocks
+	Transactions        storage.Transactions
+	Collections         storage.Collections
+	Setups              storage.EpochSetups
+	Commits             storage.EpochCommits
+	Statuses            storage.EpochStatuses
+	AccountTransactions storage.AccountTransactions
 }
 
 type namedModuleFunc struct {
@@ -122,9 +130,19 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVar(&fnb.BaseConfig.BindAddr, "bind", defaultConfig.BindAddr, "address to bind on")
 	fnb.flags.StringVarP(&fnb.BaseConfig.BootstrapDir, "bootstrapdir", "b", defaultConfig.BootstrapDir, "path to the bootstrap directory")
 	fnb.flags.StringVarP(&fnb.BaseConfig.datadir, "datadir", "d", defaultConfig.datadir, "directory to store the public database (protocol state)")
+	fnb.flags.BoolVar(&fnb.BaseConfig.DBMigration.DryRun, "db-migration-dry-run", defaultConfig.DBMigration.DryRun, "whether to apply the pending migrations of the public database without committing them, and exit")
+	fnb.flags.StringVar(&fnb.BaseConfig.DBMigration.BackupDir, "db-migration-backup-dir", defaultConfig.DBMigration.BackupDir, "directory to back up the public database to before applying pending migrations, empty to skip the backup")
+	fnb.flags.StringVar(&fnb.BaseConfig.BackupRestoreDir, "restore-backup-dir", defaultConfig.BackupRestoreDir, "directory of a backup taken with the backup-database admin command, restored if the node has no database yet")
 	fnb.flags.StringVar(&fnb.BaseConfig.secretsdir, "secretsdir", defaultConfig.secretsdir, "directory to store private database (secrets)")
 	fnb.flags.StringVarP(&fnb.BaseConfig.level, "loglevel", "l", defaultConfig.level, "level for logging output")
 	fnb.flags.DurationVar(&fnb.BaseConfig.PeerUpdateInterval, "peerupdate-interval", defaultConfig.PeerUpdateInterval, "how often to refresh the peer connections for the node")
+	fnb.flags.DurationVar(&fnb.BaseConfig.PeerUpdateMinInterval, "peerupdate-min-interval", defaultConfig.PeerUpdateMinInterval, "minimum duration between two refreshes of the peer connections for the node")
+	fnb.flags.IntVar(&fnb.BaseConfig.ConnLimits.HighWater, "conn-high-water", defaultConfig.ConnLimits.HighWater, "number of connections above which the idle and low value connections are trimmed, 0 to only prune the connections with peers outside the fanout")
+	fnb.flags.IntVar(&fnb.BaseConfig.ConnLimits.LowWater, "conn-low-water", defaultConfig.ConnLimits.LowWater, "number of connections left once the connections are trimmed")
+	fnb.flags.DurationVar(&fnb.BaseConfig.ConnLimits.GracePeriod, "conn-grace-period", defaultConfig.ConnLimits.GracePeriod, "duration after being opened during which a connection is not trimmed")
+	fnb.flags.DurationVar(&fnb.BaseConfig.ConnLimits.SilencePeriod, "conn-silence-period", defaultConfig.ConnLimits.SilencePeriod, "minimum duration between two trims of the connections")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.Transports, "transports", defaultConfig.Transports, "libp2p transports the node listens and dials on, sharing the port of its address e.g. tcp,quic. QUIC is preferred to TCP when dialing nodes listening on both")
+	fnb.flags.DurationVar(&fnb.BaseConfig.TopologyCheckInterval, "topology-check-interval", defaultConfig.TopologyCheckInterval, "how often to check that the node is connected to the nodes it requires")
 	fnb.flags.DurationVar(&fnb.BaseConfig.UnicastMessageTimeout, "unicast-timeout", defaultConfig.UnicastMessageTimeout, "how long a unicast transmission can take to complete")
 	fnb.flags.UintVarP(&fnb.BaseConfig.metricsPort, "metricport", "m", defaultConfig.metricsPort, "port for /metrics endpoint")
 	fnb.flags.BoolVar(&fnb.BaseConfig.profilerEnabled, "profiler-enabled", defaultConfig.profilerEnabled, "whether to enable the auto-profiler")
@@ -144,12 +162,29 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVar(&fnb.BaseConfig.adminClientCAs, "admin-client-certs", defaultConfig.adminClientCAs, "admin client certs (for mutual TLS)")
 
 	fnb.flags.DurationVar(&fnb.BaseConfig.DNSCacheTTL, "dns-cache-ttl", dns.DefaultTimeToLive, "time-to-live for dns cache")
+
+	fnb.flags.Float64Var(&fnb.BaseConfig.InboundRateLimits.StreamRate, "inbound-stream-rate-limit", defaultConfig.InboundRateLimits.StreamRate, "per second rate limit of the unicast streams opened by each peer, 0 to disable")
+	fnb.flags.IntVar(&fnb.BaseConfig.InboundRateLimits.StreamBurst, "inbound-stream-burst-limit", defaultConfig.InboundRateLimits.StreamBurst, "burst limit of the unicast streams opened by each peer")
+	fnb.flags.Float64Var(&fnb.BaseConfig.InboundRateLimits.MessageRate, "inbound-message-rate-limit", defaultConfig.InboundRateLimits.MessageRate, "per second rate limit of the messages received from each peer on all channels, 0 to disable")
+	fnb.flags.IntVar(&fnb.BaseConfig.InboundRateLimits.MessageBurst, "inbound-message-burst-limit", defaultConfig.InboundRateLimits.MessageBurst, "burst limit of the messages received from each peer on all channels")
+	StringToFloat64Var(fnb.flags, &fnb.BaseConfig.InboundRateLimits.ChannelRates, "inbound-channel-rate-limits", defaultConfig.InboundRateLimits.ChannelRates, "per second rate limits of the messages received from each peer on channels e.g. push-blocks=10,sync-committee=0.5 etc.")
+	fnb.flags.StringToIntVar(&fnb.BaseConfig.InboundRateLimits.ChannelBursts, "inbound-channel-burst-limits", defaultConfig.InboundRateLimits.ChannelBursts, "burst limits of the messages received from each peer on channels with a rate limit e.g. push-blocks=10,sync-committee=100 etc.")
+	fnb.flags.DurationVar(&fnb.BaseConfig.InboundRateLimits.DenyDuration, "inbound-rate-limit-deny-duration", defaultConfig.InboundRateLimits.DenyDuration, "how long to disconnect and deny the peers exceeding an inbound rate limit, 0 to only drop their messages")
+	fnb.flags.IntVar(&fnb.BaseConfig.OutboundQueueSize, "outbound-queue-size", defaultConfig.OutboundQueueSize, "size in bytes of the messages buffered for each destination before the lowest priority ones are dropped e.g. 16777216, 0 to send without queuing (default)")
+	fnb.flags.StringVar(&fnb.BaseConfig.NetworkCapture.Dir, "network-capture-dir", defaultConfig.NetworkCapture.Dir, "directory to capture the messages sent and received by the node, empty to disable the capture")
+	fnb.flags.Int64Var(&fnb.BaseConfig.NetworkCapture.MaxFileSize, "network-capture-max-file-size", defaultConfig.NetworkCapture.MaxFileSize, "size in bytes after which the network capture file is rotated")
+	fnb.flags.IntVar(&fnb.BaseConfig.NetworkCapture.MaxFiles, "network-capture-max-files", defaultConfig.NetworkCapture.MaxFiles, "number of network capture files kept, the oldest ones are removed")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.Envelopes.SignedChannels, "signed-channels", defaultConfig.Envelopes.SignedChannels, "channels whose messages are sent in envelopes signed with the staking key of their sender e.g. dkg-committee")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.Envelopes.EncryptedChannels, "encrypted-channels", defaultConfig.Envelopes.EncryptedChannels, "channels whose messages are sent in signed envelopes encrypted to the networking keys of their recipients e.g. dkg-committee")
 	fnb.flags.UintVar(&fnb.BaseConfig.guaranteesCacheSize, "guarantees-cache-size", bstorage.DefaultCacheSize, "collection guarantees cache size")
 	fnb.flags.UintVar(&fnb.BaseConfig.receiptsCacheSize, "receipts-cache-size", bstorage.DefaultCacheSize, "receipts cache size")
 
 }
 
 func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
+	// the inspector is created with the network, and read by the admin command from the admin server
+	var inspector atomic.Value
+
 	fnb.Component("network", func(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
 
 		codec := cborcodec.NewCodec()
@@ -173,6 +208,11 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 			},
 		}
 
+		transports, err := p2p.ParseTransports(fnb.BaseConfig.Transports)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transports: %w", err)
+		}
+
 		libP2PNodeFactory, err := p2p.DefaultLibP2PNodeFactory(ctx,
 			fnb.Logger.Level(zerolog.ErrorLevel),
 			fnb.Me.NodeID(),
@@ -185,24 +225,45 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 			fnb.Metrics.Network,
 			pingProvider,
 			fnb.BaseConfig.DNSCacheTTL,
+			fnb.BaseConfig.ConnLimits,
+			transports,
 			fnb.BaseConfig.NodeRole)
 
 		if err != nil {
 			return nil, fmt.Errorf("could not generate libp2p node factory: %w", err)
 		}
 
+		inboundRateLimits, err := fnb.BaseConfig.InboundRateLimits.Limits()
+		if err != nil {
+			return nil, fmt.Errorf("invalid inbound rate limits: %w", err)
+		}
+
 		mwOpts := []p2p.MiddlewareOption{
 			p2p.WithIdentifierProvider(fnb.NetworkingIdentifierProvider),
+			p2p.WithInboundRateLimits(inboundRateLimits),
+			p2p.WithTransports(transports...),
 		}
 		if len(fnb.MsgValidators) > 0 {
 			mwOpts = append(mwOpts, p2p.WithMessageValidators(fnb.MsgValidators...))
 		}
 
-		// run peer manager with the specified interval and let is also prune connections
-		peerManagerFactory := p2p.PeerManagerFactory([]p2p.Option{p2p.WithInterval(fnb.PeerUpdateInterval)})
+		netOpts := []p2p.NetworkOption{p2p.WithOutboundQueues(fnb.BaseConfig.OutboundQueueSize)}
+		if channels := fnb.BaseConfig.Envelopes.Channels(); channels != nil {
+			sealer := envelope.NewSealer(fnb.Me, fnb.NetworkKey, fnb.IdentityProvider, envelope.NewHasher, channels)
+			mwOpts = append(mwOpts, p2p.WithEnvelopeValidation(sealer))
+			netOpts = append(netOpts, p2p.WithEnvelopes(sealer))
+		}
+
+		// run peer manager with the specified interval and let is also prune connections, unless the connections
+		// are trimmed by the connection manager once they exceed the high watermark
+		peerManagerFactory := p2p.PeerManagerFactory(
+			[]p2p.Option{p2p.WithInterval(fnb.PeerUpdateInterval), p2p.WithMinInterval(fnb.PeerUpdateMinInterval)},
+			p2p.WithConnectionPruning(fnb.BaseConfig.ConnLimits.HighWater == 0),
+			p2p.WithConnectorMetrics(fnb.Metrics.Network),
+		)
 		mwOpts = append(mwOpts, p2p.WithPeerManager(peerManagerFactory))
 
-		fnb.Middleware = p2p.NewMiddleware(
+		middleware := p2p.NewMiddleware(
 			fnb.Logger.Level(zerolog.ErrorLevel),
 			libP2PNodeFactory,
 			fnb.Me.NodeID(),
@@ -213,6 +274,22 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 			fnb.IDTranslator,
 			mwOpts...,
 		)
+		fnb.Middleware = middleware
+
+		if fnb.BaseConfig.NetworkCapture.Dir != "" {
+			writer, err := capture.NewWriter(
+				fnb.Logger,
+				codec,
+				fnb.BaseConfig.NetworkCapture.Dir,
+				fnb.BaseConfig.NetworkCapture.MaxFileSize,
+				fnb.BaseConfig.NetworkCapture.MaxFiles,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create network capture writer: %w", err)
+			}
+			fnb.Middleware = capture.NewMiddleware(fnb.Logger, fnb.Middleware, writer)
+			fnb.Logger.Warn().Str("dir", fnb.BaseConfig.NetworkCapture.Dir).Msg("network capture enabled")
+		}
 
 		subscriptionManager := p2p.NewChannelSubscriptionManager(fnb.Middleware)
 
@@ -236,6 +313,7 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 			subscriptionManager,
 			fnb.Metrics.Network,
 			fnb.IdentityProvider,
+			netOpts...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("could not initialize network: %w", err)
@@ -243,14 +321,40 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 
 		fnb.Network = net
 
+		// nodes without a staked role, e.g. ghost nodes, do not require connections to any role
+		role, _ := flow.ParseRole(fnb.BaseConfig.NodeRole)
+		// the inspector uses the middleware before it is wrapped, e.g. by the network capture
+		inspector.Store(p2p.NewTopologyInspector(
+			fnb.Logger,
+			fnb.Me.NodeID(),
+			role,
+			net,
+			middleware,
+			fnb.Metrics.Network,
+			fnb.BaseConfig.TopologyCheckInterval,
+		))
+
 		idEvents := gadgets.NewIdentityDeltas(func() {
 			fnb.Middleware.UpdateNodeAddresses()
 			fnb.Middleware.UpdateAllowList()
+			net.RemoveStaleOutboundQueues()
 		})
 		fnb.ProtocolEvents.AddConsumer(idEvents)
 
 		return net, err
 	})
+
+	fnb.Component("topology inspector", func(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
+		return inspector.Load().(*p2p.TopologyInspector), nil
+	})
+
+	fnb.AdminCommand("get-network-topology", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+		ti, ok := inspector.Load().(*p2p.TopologyInspector)
+		if !ok {
+			return nil, fmt.Errorf("network is not started")
+		}
+		return ti.Report()
+	}, nil)
 }
 
 func (fnb *FlowNodeBuilder) EnqueueMetricsServerInit() {
@@ -431,7 +535,8 @@ func (fnb *FlowNodeBuilder) initDB() {
 
 	// if a db has been passed in, use that instead of creating one
 	if fnb.BaseConfig.db != nil {
-		fnb.DB = fnb.BaseConfig.db
+		fnb.BadgerDB = fnb.BaseConfig.db
+		fnb.DB = badgerkv.New(fnb.BadgerDB)
 		return
 	}
 
@@ -459,9 +564,99 @@ func (fnb *FlowNodeBuilder) initDB() {
 		WithValueLogFileSize(128 << 23).
 		WithValueLogMaxEntries(100000) // Default is 1000000
 
+	if fnb.BaseConfig.BackupRestoreDir != "" {
+		fnb.restoreDB(opts)
+	}
+
 	publicDB, err := bstorage.InitPublic(opts)
 	fnb.MustNot(err).Msg("could not open public db")
-	fnb.DB = publicDB
+	fnb.BadgerDB = publicDB
+	fnb.DB = badgerkv.New(publicDB)
+}
+
+// restoreDB restores the public database from a backup, after validating it, if the node has no database
+// yet. The node must not have started with the database since, so the backup is ignored otherwise.
+func (fnb *FlowNodeBuilder) restoreDB(opts badger.Options) {
+	dir := fnb.BaseConfig.BackupRestoreDir
+	_, err := os.Stat(filepath.Join(fnb.BaseConfig.datadir, badger.ManifestFilename))
+	if err == nil {
+		fnb.Logger.Info().Str("backup", dir).Msg("public db already exists, ignoring backup")
+		return
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		fnb.Logger.Fatal().Err(err).Msg("could not check whether public db exists")
+	}
+
+	manifest, err := backup.Validate(dir)
+	fnb.MustNot(err).Str("backup", dir).Msg("could not validate backup")
+
+	err = backup.RestoreDatabase(dir, opts)
+	fnb.MustNot(err).Str("backup", dir).Msg("could not restore public db from backup")
+
+	fnb.RestoredBackup = manifest
+
+	fnb.Logger.Info().
+		Str("backup", dir).
+		Time("created_at", manifest.CreatedAt).
+		Msg("public db restored from backup")
+}
+
+// initBackup enables taking backups of the public database while the node runs, through admin commands.
+func (fnb *FlowNodeBuilder) initBackup() {
+	fnb.Backup = backup.NewManager(fnb.Logger, fnb.BadgerDB)
+
+	fnb.Component("backup manager", func(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
+		return fnb.Backup, nil
+	})
+
+	fnb.AdminCommand("backup-database", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+		var rateLimit int64
+		if limit, ok := data["rate-limit"]; ok {
+			rateLimit = int64(limit.(float64))
+		}
+		err := fnb.Backup.Start(data["dir"].(string), rateLimit)
+		if err != nil {
+			return nil, err
+		}
+		return "backup started", nil
+	}, func(data map[string]interface{}) error {
+		dir, ok := data["dir"].(string)
+		if !ok || dir == "" {
+			return fmt.Errorf("the backup directory must be given as a string in the dir field")
+		}
+		if limit, ok := data["rate-limit"]; ok {
+			rate, ok := limit.(float64)
+			if !ok || rate < 0 {
+				return fmt.Errorf("the rate-limit field must be a non-negative number of bytes per second")
+			}
+		}
+		return nil
+	})
+
+	fnb.AdminCommand("get-backup-status", func(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+		status, ok := fnb.Backup.Status()
+		if !ok {
+			return nil, fmt.Errorf("no backup was started")
+		}
+		return status, nil
+	}, nil)
+}
+
+// migrateDB brings the public database to the latest schema version. The node refuses to start on a database
+// following a newer schema version, which was written by a newer software version.
+func (fnb *FlowNodeBuilder) migrateDB() {
+	migrator, err := migration.NewMigrator(fnb.Logger, migration.Migrations)
+	fnb.MustNot(err).Msg("could not initialize db migrator")
+
+	err = migrator.Migrate(fnb.DB, fnb.BaseConfig.DBMigration)
+	fnb.MustNot(err).Msg("could not migrate public db")
+
+	if fnb.BaseConfig.DBMigration.DryRun {
+		fnb.Logger.Info().Msg("db migration dry run completed, exiting")
+		err = fnb.DB.Close()
+		fnb.MustNot(err).Msg("could not close public db")
+		os.Exit(0)
+	}
 }
 
 func (fnb *FlowNodeBuilder) initSecretsDB() {
@@ -495,7 +690,7 @@ func (fnb *FlowNodeBuilder) initSecretsDB() {
 
 	secretsDB, err := bstorage.InitSecret(opts)
 	fnb.MustNot(err).Msg("could not open secrets db")
-	fnb.SecretsDB = secretsDB
+	fnb.SecretsDB = badgerkv.New(secretsDB)
 }
 
 func (fnb *FlowNodeBuilder) initStorage() {
@@ -503,7 +698,7 @@ func (fnb *FlowNodeBuilder) initStorage() {
 	// in order to void long iterations with big keys when initializing with an
 	// already populated database, we bootstrap the initial maximum key size
 	// upon starting
-	err := operation.RetryOnConflict(fnb.DB.Update, func(tx *badger.Txn) error {
+	err := operation.RetryOnConflict(fnb.DB.Update, func(tx kv.Txn) error {
 		return operation.InitMax(tx)
 	})
 	fnb.MustNot(err).Msg("could not initialize max tracker")
@@ -521,21 +716,23 @@ func (fnb *FlowNodeBuilder) initStorage() {
 	setups := bstorage.NewEpochSetups(fnb.Metrics.Cache, fnb.DB)
 	commits := bstorage.NewEpochCommits(fnb.Metrics.Cache, fnb.DB)
 	statuses := bstorage.NewEpochStatuses(fnb.Metrics.Cache, fnb.DB)
+	accountTransactions := bstorage.NewAccountTransactions(fnb.DB)
 
 	fnb.Storage = Storage{
-		Headers:      headers,
-		Guarantees:   guarantees,
-		Receipts:     receipts,
-		Results:      results,
-		Seals:        seals,
-		Index:        index,
-		Payloads:     payloads,
-		Blocks:       blocks,
-		Transactions: transactions,
-		Collections:  collections,
-		Setups:       setups,
-		Commits:      commits,
-		Statuses:     statuses,
+		Headers:             headers,
+		Guarantees:          guarantees,
+		Receipts:            receipts,
+		Results:             results,
+		Seals:               seals,
+		Index:               index,
+		Payloads:            payloads,
+		Blocks:              blocks,
+		Transactions:        transactions,
+		Collections:         collections,
+		Setups:              setups,
+		Commits:             commits,
+		Statuses:            statuses,
+		AccountTransactions: accountTransactions,
 	}
 }
 
@@ -978,6 +1175,8 @@ func (fnb *FlowNodeBuilder) Ready() <-chan struct{} {
 		fnb.initProfiler()
 
 		fnb.initDB()
+		fnb.migrateDB()
+		fnb.initBackup()
 		fnb.initSecretsDB()
 
 		fnb.initMetrics()
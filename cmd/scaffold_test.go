@@ -7,12 +7,15 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/cmd/bootstrap/utils"
 	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -45,3 +48,34 @@ func TestLoadSecretsEncryptionKey(t *testing.T) {
 		})
 	})
 }
+
+// TestInboundRateLimitConfig checks that fractional channel rates are parsed from the flags, and that burst
+// limits without a matching rate limit are rejected.
+func TestInboundRateLimitConfig(t *testing.T) {
+	var config InboundRateLimitConfig
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	StringToFloat64Var(flags, &config.ChannelRates, "inbound-channel-rate-limits", nil, "")
+	flags.StringToIntVar(&config.ChannelBursts, "inbound-channel-burst-limits", nil, "")
+
+	err := flags.Parse([]string{
+		"--inbound-channel-rate-limits=push-blocks=10,sync-committee=0.5",
+		"--inbound-channel-burst-limits=sync-committee=2",
+	})
+	require.NoError(t, err)
+
+	limits, err := config.Limits()
+	require.NoError(t, err)
+	assert.Equal(t, p2p.RateLimit{Rate: 10}, limits.Channels[network.Channel("push-blocks")])
+	assert.Equal(t, p2p.RateLimit{Rate: 0.5, Burst: 2}, limits.Channels[network.Channel("sync-committee")])
+
+	t.Run("should reject burst limits without rate limit", func(t *testing.T) {
+		config.ChannelBursts["request-collections"] = 5
+		_, err := config.Limits()
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject invalid rates", func(t *testing.T) {
+		err := flags.Parse([]string{"--inbound-channel-rate-limits=push-blocks=fast"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,324 @@
+package check_database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// names of the consistency checks
+const (
+	CheckFinalizedChain = "finalized_chain" // finalized heights are contiguous from the root, with matching parents
+	CheckHeightIndex    = "height_index"    // no finalized block is indexed above the finalized height
+	CheckSealedHeight   = "sealed_height"   // the sealed height is finalized
+	CheckBlockChildren  = "block_children"  // the children index links known blocks to their known children
+	CheckPayloadSeals   = "payload_seals"   // the payload seals of known blocks resolve to seals of known results
+	CheckChunkIndex     = "chunk_index"     // the chunks are indexed to known blocks
+)
+
+// Problem is an inconsistency found in the database. The problems which are repairable are safe to repair, as
+// they are stale index entries which the node re-creates if needed, and never the removal of an entity.
+type Problem struct {
+	Check      string `json:"check"`
+	Key        string `json:"key"`
+	Message    string `json:"message"`
+	Repairable bool   `json:"repairable"`
+	Repaired   bool   `json:"repaired"`
+
+	repair func(kv.Txn) error
+}
+
+// Summary is the outcome of the consistency checks of a database.
+type Summary struct {
+	RootHeight      uint64            `json:"root_height"`
+	FinalizedHeight uint64            `json:"finalized_height"`
+	SealedHeight    uint64            `json:"sealed_height"`
+	Checked         map[string]uint64 `json:"checked"` // number of entries checked by each check
+	Problems        []*Problem        `json:"problems"`
+}
+
+// Unrepaired returns the number of problems which are not repaired.
+func (s *Summary) Unrepaired() int {
+	unrepaired := 0
+	for _, problem := range s.Problems {
+		if !problem.Repaired {
+			unrepaired++
+		}
+	}
+	return unrepaired
+}
+
+func (s *Summary) report(check string, key string, format string, args ...interface{}) {
+	s.Problems = append(s.Problems, &Problem{
+		Check:   check,
+		Key:     key,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (s *Summary) reportRepairable(check string, key string, repair func(kv.Txn) error, format string, args ...interface{}) {
+	s.Problems = append(s.Problems, &Problem{
+		Check:      check,
+		Key:        key,
+		Message:    fmt.Sprintf(format, args...),
+		Repairable: true,
+		repair:     repair,
+	})
+}
+
+// Check verifies the referential integrity of the protocol state stored in the database, and repairs the
+// repairable problems if requested. An error is only returned if the database cannot be read or repaired,
+// the problems found are reported in the summary.
+func Check(db kv.DB, repair bool) (*Summary, error) {
+	summary := &Summary{
+		Checked: make(map[string]uint64),
+	}
+
+	err := db.View(func(tx kv.Txn) error {
+		err := operation.RetrieveRootHeight(&summary.RootHeight)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve root height: %w", err)
+		}
+		err = operation.RetrieveFinalizedHeight(&summary.FinalizedHeight)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve finalized height: %w", err)
+		}
+		err = operation.RetrieveSealedHeight(&summary.SealedHeight)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve sealed height: %w", err)
+		}
+
+		checks := []func(kv.Txn, *Summary) error{
+			checkFinalizedChain,
+			checkHeightIndex,
+			checkSealedHeight,
+			checkBlockChildren,
+			checkPayloadSeals,
+			checkChunkIndex,
+		}
+		for _, check := range checks {
+			err = check(tx, summary)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !repair {
+		return summary, nil
+	}
+
+	for _, problem := range summary.Problems {
+		if !problem.Repairable {
+			continue
+		}
+		err = db.Update(problem.repair)
+		if err != nil {
+			return nil, fmt.Errorf("could not repair %s problem at %s: %w", problem.Check, problem.Key, err)
+		}
+		problem.Repaired = true
+	}
+
+	return summary, nil
+}
+
+// checkFinalizedChain verifies that a block is indexed at each height from the root to the finalized height,
+// and that each of these blocks is known and is the child of the block finalized at the previous height.
+func checkFinalizedChain(tx kv.Txn, summary *Summary) error {
+	var parentID flow.Identifier
+	for height := summary.RootHeight; height <= summary.FinalizedHeight; height++ {
+		summary.Checked[CheckFinalizedChain]++
+		key := fmt.Sprint(height)
+
+		var blockID flow.Identifier
+		err := operation.LookupBlockHeight(height, &blockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			summary.report(CheckFinalizedChain, key, "no block indexed at finalized height")
+			parentID = flow.ZeroID
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not look up block at height %d: %w", height, err)
+		}
+
+		var header flow.Header
+		err = operation.RetrieveHeader(blockID, &header)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			summary.report(CheckFinalizedChain, key, "indexed block %x not found", blockID)
+			parentID = blockID
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve header of block %x: %w", blockID, err)
+		}
+
+		if header.Height != height {
+			summary.report(CheckFinalizedChain, key, "indexed block %x has height %d", blockID, header.Height)
+		}
+		if height > summary.RootHeight && parentID != flow.ZeroID && header.ParentID != parentID {
+			summary.report(CheckFinalizedChain, key, "indexed block %x has parent %x instead of the block %x finalized at the previous height", blockID, header.ParentID, parentID)
+		}
+		parentID = blockID
+	}
+
+	return nil
+}
+
+// checkHeightIndex verifies that no block is indexed above the finalized height. Such entries are left when
+// the finalized height is lost, and would prevent the blocks from being indexed again once finalized, so they
+// are safe to remove.
+func checkHeightIndex(tx kv.Txn, summary *Summary) error {
+	return operation.TraverseBlockHeights(func(height uint64, blockID flow.Identifier) error {
+		summary.Checked[CheckHeightIndex]++
+		if height <= summary.FinalizedHeight {
+			return nil
+		}
+		summary.reportRepairable(CheckHeightIndex, fmt.Sprint(height), operation.RemoveBlockHeight(height),
+			"block %x indexed above the finalized height %d", blockID, summary.FinalizedHeight)
+		return nil
+	})(tx)
+}
+
+// checkSealedHeight verifies that the sealed height is not above the finalized height.
+func checkSealedHeight(tx kv.Txn, summary *Summary) error {
+	summary.Checked[CheckSealedHeight]++
+	if summary.SealedHeight > summary.FinalizedHeight {
+		summary.report(CheckSealedHeight, fmt.Sprint(summary.SealedHeight), "sealed height is above the finalized height %d", summary.FinalizedHeight)
+	}
+	return nil
+}
+
+// checkBlockChildren verifies that the children index of each block refers to known blocks, which are the
+// children of the block. The children which are not known are safe to remove from the index, as they are
+// indexed again if they are received again, and the index of a block which is not known is never used.
+func checkBlockChildren(tx kv.Txn, summary *Summary) error {
+	return operation.TraverseBlockChildren(func(blockID flow.Identifier, childrenIDs []flow.Identifier) error {
+		summary.Checked[CheckBlockChildren]++
+		key := blockID.String()
+
+		exists, err := headerExists(tx, blockID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			summary.reportRepairable(CheckBlockChildren, key, operation.RemoveBlockChildren(blockID),
+				"children indexed for unknown block")
+			return nil
+		}
+
+		known := make([]flow.Identifier, 0, len(childrenIDs))
+		var unknown []flow.Identifier
+		for _, childID := range childrenIDs {
+			var child flow.Header
+			err := operation.RetrieveHeader(childID, &child)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				unknown = append(unknown, childID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not retrieve header of block %x: %w", childID, err)
+			}
+			known = append(known, childID)
+
+			if child.ParentID != blockID {
+				summary.report(CheckBlockChildren, key, "indexed child %x has parent %x", childID, child.ParentID)
+			}
+		}
+		if len(unknown) > 0 {
+			summary.reportRepairable(CheckBlockChildren, key, operation.UpdateBlockChildren(blockID, known),
+				"indexed children %v not found", unknown)
+		}
+
+		return nil
+	})(tx)
+}
+
+// checkPayloadSeals verifies that the payload seals of each block are indexed for a known block, and refer to
+// known seals. The seals included in the blocks above the root must also refer to known results, for the same
+// block. The results sealed in the blocks of the root sealing segment may predate the segment, so they may not
+// be known.
+func checkPayloadSeals(tx kv.Txn, summary *Summary) error {
+	return operation.TraversePayloadSeals(func(blockID flow.Identifier, sealIDs []flow.Identifier) error {
+		summary.Checked[CheckPayloadSeals]++
+		key := blockID.String()
+
+		var header flow.Header
+		err := operation.RetrieveHeader(blockID, &header)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			summary.report(CheckPayloadSeals, key, "payload seals indexed for unknown block")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve header of block %x: %w", blockID, err)
+		}
+
+		for _, sealID := range sealIDs {
+			var seal flow.Seal
+			err := operation.RetrieveSeal(sealID, &seal)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				summary.report(CheckPayloadSeals, key, "payload seal %x not found", sealID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not retrieve seal %x: %w", sealID, err)
+			}
+
+			if header.Height <= summary.RootHeight {
+				continue
+			}
+
+			var result flow.ExecutionResult
+			err = operation.RetrieveExecutionResult(seal.ResultID, &result)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				summary.report(CheckPayloadSeals, key, "result %x of payload seal %x not found", seal.ResultID, sealID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not retrieve result %x: %w", seal.ResultID, err)
+			}
+			if result.BlockID != seal.BlockID {
+				summary.report(CheckPayloadSeals, key, "payload seal %x for block %x refers to result %x for block %x", sealID, seal.BlockID, seal.ResultID, result.BlockID)
+			}
+		}
+
+		return nil
+	})(tx)
+}
+
+// checkChunkIndex verifies that the chunks are indexed to known blocks. The entries of unknown blocks are safe to
+// remove, as they cannot be resolved anyway.
+func checkChunkIndex(tx kv.Txn, summary *Summary) error {
+	return operation.TraverseBlockIDsByChunkID(func(chunkID flow.Identifier, blockID flow.Identifier) error {
+		summary.Checked[CheckChunkIndex]++
+
+		exists, err := headerExists(tx, blockID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			summary.reportRepairable(CheckChunkIndex, chunkID.String(), operation.RemoveBlockIDByChunkID(chunkID),
+				"chunk indexed to unknown block %x", blockID)
+		}
+		return nil
+	})(tx)
+}
+
+func headerExists(tx kv.Txn, blockID flow.Identifier) (bool, error) {
+	var header flow.Header
+	err := operation.RetrieveHeader(blockID, &header)(tx)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve header of block %x: %w", blockID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,160 @@
+package check_database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// storeChain stores a consistent chain of finalized blocks from the root height, where each block seals the
+// result of its parent.
+func storeChain(t *testing.T, db kv.DB, rootHeight uint64, length int) []*flow.Header {
+	var headers []*flow.Header
+	require.NoError(t, db.Update(func(tx kv.Txn) error {
+		var parent *flow.Header
+		for i := 0; i < length; i++ {
+			header := unittest.BlockHeaderFixture(func(header *flow.Header) {
+				header.Height = rootHeight + uint64(i)
+				if parent != nil {
+					header.ParentID = parent.ID()
+				}
+			})
+			blockID := header.ID()
+			require.NoError(t, operation.InsertHeader(blockID, &header)(tx))
+			require.NoError(t, operation.IndexBlockHeight(header.Height, blockID)(tx))
+			require.NoError(t, operation.InsertBlockChildren(blockID, nil)(tx))
+
+			var sealIDs []flow.Identifier
+			if parent != nil {
+				require.NoError(t, operation.UpdateBlockChildren(parent.ID(), []flow.Identifier{blockID})(tx))
+
+				result := unittest.ExecutionResultFixture(func(result *flow.ExecutionResult) {
+					result.BlockID = parent.ID()
+				})
+				require.NoError(t, operation.InsertExecutionResult(result)(tx))
+				require.NoError(t, operation.IndexBlockIDByChunkID(result.Chunks[0].ID(), parent.ID())(tx))
+				seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+				require.NoError(t, operation.InsertSeal(seal.ID(), seal)(tx))
+				sealIDs = append(sealIDs, seal.ID())
+			}
+			require.NoError(t, operation.IndexPayloadSeals(blockID, sealIDs)(tx))
+
+			headers = append(headers, &header)
+			parent = &header
+		}
+
+		require.NoError(t, operation.InsertRootHeight(rootHeight)(tx))
+		require.NoError(t, operation.InsertFinalizedHeight(parent.Height)(tx))
+		require.NoError(t, operation.InsertSealedHeight(parent.Height-1)(tx))
+		return nil
+	}))
+	return headers
+}
+
+func problemsOf(summary *Summary, check string) []*Problem {
+	var problems []*Problem
+	for _, problem := range summary.Problems {
+		if problem.Check == check {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+func TestCheckConsistent(t *testing.T) {
+	unittest.RunWithDB(t, func(db kv.DB) {
+		storeChain(t, db, 10, 5)
+
+		summary, err := Check(db, false)
+		require.NoError(t, err)
+		assert.Empty(t, summary.Problems)
+		assert.Equal(t, uint64(10), summary.RootHeight)
+		assert.Equal(t, uint64(14), summary.FinalizedHeight)
+		assert.Equal(t, uint64(5), summary.Checked[CheckFinalizedChain])
+		assert.Equal(t, uint64(5), summary.Checked[CheckBlockChildren])
+		assert.Equal(t, uint64(4), summary.Checked[CheckChunkIndex])
+	})
+}
+
+func TestCheckInconsistent(t *testing.T) {
+	unittest.RunWithDB(t, func(db kv.DB) {
+		headers := storeChain(t, db, 10, 5)
+
+		// the finalized height is lost, after a block was indexed at the next height
+		require.NoError(t, db.Update(operation.UpdateFinalizedHeight(13)))
+		// a finalized block is replaced by a block of another fork
+		other := unittest.BlockHeaderFixture(func(header *flow.Header) {
+			header.Height = 12
+		})
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			require.NoError(t, operation.InsertHeader(other.ID(), &other)(tx))
+			require.NoError(t, operation.RemoveBlockHeight(12)(tx))
+			return operation.IndexBlockHeight(12, other.ID())(tx)
+		}))
+		// a child which is not stored is indexed
+		missing := unittest.IdentifierFixture()
+		require.NoError(t, db.Update(operation.UpdateBlockChildren(headers[0].ID(), []flow.Identifier{headers[1].ID(), missing})))
+		// a chunk is indexed to a block which is not stored
+		chunkID := unittest.IdentifierFixture()
+		require.NoError(t, db.Update(operation.IndexBlockIDByChunkID(chunkID, unittest.IdentifierFixture())))
+		// a payload seal of the other block refers to a result which is not stored
+		seal := unittest.Seal.Fixture()
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			require.NoError(t, operation.InsertSeal(seal.ID(), seal)(tx))
+			return operation.IndexPayloadSeals(other.ID(), []flow.Identifier{seal.ID()})(tx)
+		}))
+
+		summary, err := Check(db, false)
+		require.NoError(t, err)
+
+		chain := problemsOf(summary, CheckFinalizedChain)
+		// the replaced block does not have the previous finalized block as parent, and is not the parent of the next one
+		require.Len(t, chain, 2)
+		assert.Equal(t, "12", chain[0].Key)
+		assert.Equal(t, "13", chain[1].Key)
+		assert.False(t, chain[0].Repairable)
+
+		heights := problemsOf(summary, CheckHeightIndex)
+		require.Len(t, heights, 1)
+		assert.Equal(t, "14", heights[0].Key)
+		assert.True(t, heights[0].Repairable)
+
+		children := problemsOf(summary, CheckBlockChildren)
+		require.Len(t, children, 1)
+		assert.Equal(t, headers[0].ID().String(), children[0].Key)
+		assert.True(t, children[0].Repairable)
+
+		chunks := problemsOf(summary, CheckChunkIndex)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, chunkID.String(), chunks[0].Key)
+
+		seals := problemsOf(summary, CheckPayloadSeals)
+		require.Len(t, seals, 1)
+		assert.False(t, seals[0].Repairable)
+
+		assert.Equal(t, len(summary.Problems), summary.Unrepaired())
+
+		t.Run("repairable problems are repaired", func(t *testing.T) {
+			summary, err := Check(db, true)
+			require.NoError(t, err)
+			assert.Equal(t, 3, summary.Unrepaired())
+
+			summary, err = Check(db, false)
+			require.NoError(t, err)
+			assert.Len(t, summary.Problems, 3)
+			assert.Empty(t, problemsOf(summary, CheckHeightIndex))
+			assert.Empty(t, problemsOf(summary, CheckBlockChildren))
+			assert.Empty(t, problemsOf(summary, CheckChunkIndex))
+
+			var childrenIDs []flow.Identifier
+			require.NoError(t, db.View(operation.RetrieveBlockChildren(headers[0].ID(), &childrenIDs)))
+			assert.Equal(t, []flow.Identifier{headers[1].ID()}, childrenIDs)
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package check_database
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+)
+
+var (
+	flagDatadir string
+	flagRepair  bool
+)
+
+// Cmd checks the referential integrity of the protocol state, e.g. after a crash or a truncation of the
+// database. It prints a JSON summary of the problems found to the standard output, and exits with a non-zero
+// code if some of them are not repaired.
+var Cmd = &cobra.Command{
+	Use:   "check-database",
+	Short: "Checks the consistency of the indices of the protocol state database, and optionally repairs the stale ones",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().BoolVar(&flagRepair, "repair", false,
+		"whether to remove the stale index entries, which is safe to do while the node is stopped")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Bool("repair", flagRepair).
+		Msg("flags")
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	summary, err := Check(db, flagRepair)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not check database")
+	}
+
+	for _, problem := range summary.Problems {
+		log.Warn().
+			Str("check", problem.Check).
+			Str("key", problem.Key).
+			Bool("repairable", problem.Repairable).
+			Bool("repaired", problem.Repaired).
+			Msg(problem.Message)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(summary)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode summary")
+	}
+
+	unrepaired := summary.Unrepaired()
+	if unrepaired > 0 {
+		// the deferred close does not run on exit
+		db.Close()
+		log.Error().Int("problems", unrepaired).Msg("database is inconsistent")
+		os.Exit(1)
+	}
+
+	log.Info().Int("repaired", len(summary.Problems)).Msg("database is consistent")
+}
@@ -3,15 +3,14 @@ package common
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/state/protocol"
 	protocolbadger "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InitProtocolState(db *badger.DB, storages *storage.All) (protocol.State, error) {
+func InitProtocolState(db kv.DB, storages *storage.All) (protocol.State, error) {
 	metrics := &metrics.NoopCollector{}
 
 	protocolState, err := protocolbadger.OpenState(
@@ -0,0 +1,361 @@
+package diagnose_execution_fork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executionfork"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+var (
+	flagDatadir           string
+	flagBlockID           string
+	flagChunkDataDatadirs []string
+)
+
+// Cmd guides the resolution of an execution fork: it compares the chunks of the conflicting results for a block and
+// their chunk data packs, and recommends which executors diverged. The conflicting results are the evidence stored
+// by a consensus node which halted sealing, or the report persisted by an execution node which detected the fork,
+// unless a block is given, in which case they are the results of the receipts for the block.
+var Cmd = &cobra.Command{
+	Use:   "diagnose-execution-fork",
+	Short: "Compares the conflicting results of an execution fork and recommends which executors diverged",
+	Run:   run,
+}
+
+// diagnosis is printed once the comparison is done, for operators to keep it along with the resolution of the fork.
+type diagnosis struct {
+	Report         *executionfork.Report          `json:"report"`
+	ChunkDataDiffs []*executionfork.ChunkDataDiff `json:"chunk_data_diffs"`
+	Recommendation executionfork.Recommendation   `json:"recommendation"`
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"block for which to compare the results of the receipts, the stored execution fork evidence or report if not set")
+
+	Cmd.Flags().StringSliceVar(&flagChunkDataDatadirs, "chunk-data-datadirs", nil,
+		"directories that store the protocol state of execution nodes, to load the chunk data packs of the divergent chunk from, in addition to the datadir")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Str("block_id", flagBlockID).
+		Msg("flags")
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+
+	var (
+		block    *flow.Header
+		results  []*flow.ExecutionResult
+		receipts flow.ExecutionReceiptList
+		err      error
+	)
+	if flagBlockID == "" {
+		block, results, err = readEvidence(db)
+		if errors.Is(err, storage.ErrNotFound) {
+			block, results, err = readReport(db, storages)
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Info().Msg("no execution fork evidence or report was found, give the block to compare the results of with --block-id")
+			return
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read execution fork evidence")
+		}
+		receipts, err = storages.Receipts.ByBlockID(block.ID())
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Fatal().Err(err).Msg("could not read receipts")
+		}
+	} else {
+		blockID, err := flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed block ID")
+		}
+		block, err = storages.Headers.ByBlockID(blockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read block")
+		}
+		receipts, err = storages.Receipts.ByBlockID(blockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read receipts")
+		}
+		results = distinctResults(receipts)
+		if len(results) < 2 {
+			log.Info().Int("results", len(results)).Msg("the receipts for the block don't commit to conflicting results")
+			return
+		}
+	}
+
+	sealedResultID, err := findSealedResult(db, storages, block)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not look up the seal of the block")
+	}
+
+	report := executionfork.NewReport(block, results, receipts)
+	recommendation := executionfork.Recommend(report, sealedResultID)
+
+	log.Info().
+		Hex("block_id", report.BlockID[:]).
+		Uint64("block_height", report.BlockHeight).
+		Bool("sealed", sealedResultID != flow.ZeroID).
+		Msg("comparing conflicting results")
+
+	for i, result := range report.Results {
+		executors := make([]string, 0, len(result.Receipts))
+		for _, receipt := range result.Receipts {
+			executors = append(executors, receipt.ExecutorID.String())
+		}
+		log.Info().
+			Int("result", i).
+			Hex("result_id", result.ResultID[:]).
+			Str("initial_state", result.InitialState).
+			Str("final_state", result.FinalState).
+			Int("chunks", result.Chunks).
+			Strs("executors", executors).
+			Msg("conflicting result")
+	}
+
+	if report.DivergentChunk == nil {
+		log.Info().Msg("the chunks of the results don't diverge, the results differ in their service events or spocks")
+	} else {
+		for i, data := range report.DivergentChunk.Data {
+			lg := log.Info().
+				Uint64("chunk", report.DivergentChunk.Index).
+				Int("result", i)
+			if data == nil {
+				lg.Msg("result has no such chunk")
+				continue
+			}
+			lg.Uint("collection_index", data.CollectionIndex).
+				Str("start_state", data.StartState).
+				Str("end_state", data.EndState).
+				Hex("event_collection", data.EventCollection[:]).
+				Uint64("transactions", data.NumberOfTransactions).
+				Uint64("computation_used", data.TotalComputationUsed).
+				Msg("divergent chunk")
+		}
+	}
+
+	chunkDataDiffs, err := diffChunkDataPacks(report, results, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not compare chunk data packs")
+	}
+	for _, diff := range chunkDataDiffs {
+		log.Info().
+			Hex("result_id", diff.ResultIDs[1][:]).
+			Hex("compared_to_result_id", diff.ResultIDs[0][:]).
+			Strs("start_states", diff.StartStates[:]).
+			Int("different_transactions", len(diff.Transactions)).
+			Int("different_registers", len(diff.Registers)).
+			Msg("divergent chunk data pack")
+	}
+
+	diverged := make([]string, 0, len(recommendation.DivergedExecutors))
+	for _, executorID := range recommendation.DivergedExecutors {
+		diverged = append(diverged, executorID.String())
+	}
+	log.Info().
+		Hex("canonical_result_id", recommendation.CanonicalResultID[:]).
+		Strs("diverged_executors", diverged).
+		Str("reason", recommendation.Reason).
+		Msg("recommendation")
+	log.Info().Msg("once the diverged executors are fixed, remove the evidence with remove-execution-fork on the consensus nodes to resume sealing")
+
+	encoded, err := json.MarshalIndent(diagnosis{
+		Report:         report,
+		ChunkDataDiffs: chunkDataDiffs,
+		Recommendation: recommendation,
+	}, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode diagnosis")
+	}
+	fmt.Println(string(encoded))
+}
+
+// readEvidence reads the conflicting results evidencing an execution fork, stored by a consensus node.
+func readEvidence(db kv.DB) (*flow.Header, []*flow.ExecutionResult, error) {
+	var conflictingSeals []*flow.IncorporatedResultSeal
+	err := db.View(operation.RetrieveExecutionForkEvidence(&conflictingSeals))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(conflictingSeals) == 0 {
+		return nil, nil, storage.ErrNotFound
+	}
+
+	results := make([]*flow.ExecutionResult, 0, len(conflictingSeals))
+	for _, seal := range conflictingSeals {
+		results = append(results, seal.IncorporatedResult.Result)
+	}
+	return conflictingSeals[0].Header, results, nil
+}
+
+// readReport reads the conflicting results of the execution fork reported by an execution node.
+func readReport(db kv.DB, storages *storage.All) (*flow.Header, []*flow.ExecutionResult, error) {
+	report, err := executionfork.ReadReport(db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := storages.Headers.ByBlockID(report.BlockID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read block of execution fork report: %w", err)
+	}
+	results := make([]*flow.ExecutionResult, 0, len(report.Results))
+	for _, reported := range report.Results {
+		result, err := storages.Results.ByID(reported.ResultID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read result %x of execution fork report: %w", reported.ResultID, err)
+		}
+		results = append(results, result)
+	}
+	return block, results, nil
+}
+
+// diffChunkDataPacks compares the chunk data packs of the divergent chunk of each result to the one of the first
+// result whose pack is found. Each execution node only stores the chunk data packs of the results it produced, the
+// packs are looked up in the datadir and in the protocol state of the other execution nodes given by flag.
+func diffChunkDataPacks(report *executionfork.Report, results []*flow.ExecutionResult, storages *storage.All) ([]*executionfork.ChunkDataDiff, error) {
+	diffs := []*executionfork.ChunkDataDiff{}
+	if report.DivergentChunk == nil {
+		return diffs, nil
+	}
+
+	chunkDataPacks := []storage.ChunkDataPacks{storages.ChunkDataPacks}
+	for _, datadir := range flagChunkDataDatadirs {
+		db := common.InitStorage(datadir)
+		defer db.Close()
+		chunkDataPacks = append(chunkDataPacks, common.InitStorages(db).ChunkDataPacks)
+	}
+
+	index := report.DivergentChunk.Index
+	var (
+		referenceResultID flow.Identifier
+		reference         *flow.ChunkDataPack
+	)
+	for i, result := range results {
+		if index >= uint64(len(result.Chunks)) {
+			continue
+		}
+		resultID := report.Results[i].ResultID
+		pack, err := findChunkDataPack(chunkDataPacks, result.Chunks[index].ID())
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Warn().
+				Hex("result_id", resultID[:]).
+				Uint64("chunk", index).
+				Msg("chunk data pack of divergent chunk not found, give the datadir of an execution node which produced the result with --chunk-data-datadirs")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if reference == nil {
+			referenceResultID = resultID
+			reference = pack
+			continue
+		}
+		diff, err := executionfork.DiffChunkDataPacks(
+			[2]flow.Identifier{referenceResultID, resultID},
+			[2]*flow.ChunkDataPack{reference, pack},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not compare chunk data packs of results %x and %x: %w", referenceResultID, resultID, err)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// findChunkDataPack looks up the chunk data pack of the chunk in the given storages.
+func findChunkDataPack(chunkDataPacks []storage.ChunkDataPacks, chunkID flow.Identifier) (*flow.ChunkDataPack, error) {
+	for _, packs := range chunkDataPacks {
+		pack, err := packs.ByChunkID(chunkID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read chunk data pack %x: %w", chunkID, err)
+		}
+		return pack, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+// distinctResults returns the distinct results the receipts commit to, in the order of the receipts.
+func distinctResults(receipts flow.ExecutionReceiptList) []*flow.ExecutionResult {
+	seen := make(map[flow.Identifier]struct{})
+	var results []*flow.ExecutionResult
+	for _, receipt := range receipts {
+		resultID := receipt.ExecutionResult.ID()
+		if _, ok := seen[resultID]; ok {
+			continue
+		}
+		seen[resultID] = struct{}{}
+		results = append(results, &receipt.ExecutionResult)
+	}
+	return results
+}
+
+// findSealedResult returns the result sealed for the block, by looking for its seal in the finalized blocks, or
+// flow.ZeroID if the block isn't sealed.
+func findSealedResult(db kv.DB, storages *storage.All, block *flow.Header) (flow.Identifier, error) {
+	var finalizedHeight uint64
+	err := db.View(operation.RetrieveFinalizedHeight(&finalizedHeight))
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not read finalized height: %w", err)
+	}
+	finalized, err := storages.Headers.ByHeight(finalizedHeight)
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not read finalized block: %w", err)
+	}
+	lastSeal, err := storages.Seals.ByBlockID(finalized.ID())
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not read last seal: %w", err)
+	}
+	lastSealed, err := storages.Headers.ByBlockID(lastSeal.BlockID)
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not read last sealed block: %w", err)
+	}
+	if lastSealed.Height < block.Height {
+		return flow.ZeroID, nil
+	}
+
+	blockID := block.ID()
+	for height := block.Height + 1; height <= finalizedHeight; height++ {
+		header, err := storages.Headers.ByHeight(height)
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not read finalized block at height %d: %w", height, err)
+		}
+		payload, err := storages.Payloads.ByBlockID(header.ID())
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not read payload of finalized block at height %d: %w", height, err)
+		}
+		for _, seal := range payload.Seals {
+			if seal.BlockID == blockID {
+				return seal.ResultID, nil
+			}
+		}
+	}
+
+	// the block is not finalized, the seal is for another block at the same height
+	return flow.ZeroID, nil
+}
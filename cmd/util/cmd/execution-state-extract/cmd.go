@@ -2,6 +2,7 @@ package extract
 
 import (
 	"encoding/hex"
+	"fmt"
 	"os"
 	"path"
 
@@ -25,6 +26,8 @@ var (
 	flagNoMigration       bool
 	flagNoReport          bool
 	flagCleanupStorage    bool
+	flagDryRun            bool
+	flagChain             string
 )
 
 var Cmd = &cobra.Command{
@@ -59,11 +62,32 @@ func init() {
 
 	Cmd.Flags().BoolVar(&flagCleanupStorage, "cleanup-storage", false,
 		"cleanup storage by removing broken contracts")
+
+	Cmd.Flags().BoolVar(&flagDryRun, "dry-run", false,
+		"migrate and report the state, and write the migration report, without writing the checkpoint")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", string(flow.Mainnet),
+		"chain name, used to check the migration invariants")
+}
+
+func getChain(chainName string) (chain flow.Chain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chain = flow.ChainID(chainName).Chain()
+	return
 }
 
 func run(*cobra.Command, []string) {
 	var stateCommitment flow.StateCommitment
 
+	chain, err := getChain(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain name")
+	}
+
 	if len(flagBlockHash) > 0 && len(flagStateCommitment) > 0 {
 		log.Fatal().Msg("cannot run the command with both block hash and state commitment as inputs, only one of them should be provided")
 		return
@@ -120,7 +144,7 @@ func run(*cobra.Command, []string) {
 
 	log.Info().Msgf("Block state commitment: %s", hex.EncodeToString(stateCommitment[:]))
 
-	err := extractExecutionState(
+	err = extractExecutionState(
 		flagExecutionStateDir,
 		stateCommitment,
 		flagOutputDir,
@@ -128,6 +152,8 @@ func run(*cobra.Command, []string) {
 		!flagNoMigration,
 		!flagNoReport,
 		flagCleanupStorage,
+		flagDryRun,
+		chain,
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msgf("error extracting the execution state: %s", err.Error())
@@ -28,6 +28,8 @@ func extractExecutionState(
 	migrate bool,
 	report bool,
 	cleanupStorage bool,
+	dryRun bool,
+	chain flow.Chain,
 ) error {
 
 	diskWal, err := wal.NewDiskWAL(
@@ -71,10 +73,22 @@ func extractExecutionState(
 			OutputDir: outputDir,
 		}
 
+		migrationRunner := &mgr.MigrationRunner{
+			Log:       log,
+			OutputDir: outputDir,
+			Migrations: []mgr.NamedMigration{
+				{Name: "prune", Migrate: mgr.PruneMigration},
+				{Name: "storage-format-v5", Migrate: storageFormatV5Migration.Migrate},
+				{Name: "storage-used-update", Migrate: storageUsedUpdateMigration.Migrate},
+			},
+			Invariants: []mgr.Invariant{
+				&mgr.TotalSupplyInvariant{Log: log, Chain: chain},
+				&mgr.StorageUsedInvariant{},
+			},
+		}
+
 		migrations = []ledger.Migration{
-			mgr.PruneMigration,
-			storageFormatV5Migration.Migrate,
-			storageUsedUpdateMigration.Migrate,
+			migrationRunner.Migrate,
 		}
 	}
 	if report {
@@ -93,6 +107,11 @@ func extractExecutionState(
 			},
 		}
 	}
+
+	if dryRun {
+		return dryRunExecutionState(led, targetHash, migrations, reporters, log)
+	}
+
 	newState, err := led.ExportCheckpointAt(
 		ledger.State(targetHash),
 		migrations,
@@ -113,3 +132,37 @@ func extractExecutionState(
 
 	return nil
 }
+
+// dryRunExecutionState applies the migrations and runs the reporters on the state, the same way
+// as when exporting a checkpoint, without writing the checkpoint.
+func dryRunExecutionState(
+	led *complete.Ledger,
+	targetHash flow.StateCommitment,
+	migrations []ledger.Migration,
+	reporters []ledger.Reporter,
+	log zerolog.Logger,
+) error {
+
+	payloads, err := led.PayloadsAt(ledger.State(targetHash))
+	if err != nil {
+		return fmt.Errorf("cannot get payloads: %w", err)
+	}
+
+	for i, migrate := range migrations {
+		payloads, err = migrate(payloads)
+		if err != nil {
+			return fmt.Errorf("error applying migration (%d): %w", i, err)
+		}
+	}
+
+	for i, reporter := range reporters {
+		err = reporter.Report(payloads)
+		if err != nil {
+			return fmt.Errorf("error running reporter (%d): %w", i, err)
+		}
+	}
+
+	log.Info().Int("payloads", len(payloads)).Msg("dry run done, no checkpoint was written")
+
+	return nil
+}
@@ -67,6 +67,8 @@ func TestExtractExecutionState(t *testing.T) {
 				false,
 				false,
 				false,
+				false,
+				flow.Emulator.Chain(),
 			)
 			require.Error(t, err)
 		})
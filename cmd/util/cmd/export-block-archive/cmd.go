@@ -0,0 +1,236 @@
+package export_block_archive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/history"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+var (
+	flagDatadir     string
+	flagArchiveDir  string
+	flagStartHeight uint64
+	flagEndHeight   uint64
+)
+
+// Cmd exports the sealed blocks, with their collections, seals, sealed execution results, transaction results and
+// events, to a portable archive which access nodes can serve once the history is no longer held by the network.
+// An existing archive is extended from its last block, so the export can be resumed.
+var Cmd = &cobra.Command{
+	Use:   "export-block-archive",
+	Short: "Exports the sealed blocks with their collections, results and events to a portable archive",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagArchiveDir, "archive-dir", "",
+		"directory of the archive to create or extend")
+	_ = Cmd.MarkFlagRequired("archive-dir")
+
+	Cmd.Flags().Uint64Var(&flagStartHeight, "start-height", 0,
+		"height of the first sealed block to export, ignored when extending an existing archive")
+
+	Cmd.Flags().Uint64Var(&flagEndHeight, "end-height", 0,
+		"height of the last sealed block to export (inclusive), the latest sealed block if not set")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Str("archive_dir", flagArchiveDir).
+		Uint64("start_height", flagStartHeight).
+		Uint64("end_height", flagEndHeight).
+		Msg("flags")
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+
+	writer, err := history.OpenWriter(flagArchiveDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not open archive")
+	}
+	defer func() {
+		err := writer.Close()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not close archive")
+		}
+	}()
+
+	startHeight := flagStartHeight
+	last, ok := writer.LastHeight()
+	if ok {
+		startHeight = last + 1
+		log.Info().Uint64("last_height", last).Msg("extending existing archive")
+	}
+
+	var sealedHeight uint64
+	err = db.View(operation.RetrieveSealedHeight(&sealedHeight))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not retrieve the sealed height")
+	}
+
+	endHeight := flagEndHeight
+	if endHeight == 0 {
+		endHeight = sealedHeight
+	}
+	if endHeight > sealedHeight {
+		log.Fatal().Uint64("end_height", endHeight).Uint64("sealed_height", sealedHeight).Msg("end height is above the sealed height")
+	}
+
+	if endHeight < startHeight {
+		log.Fatal().Uint64("start_height", startHeight).Uint64("end_height", endHeight).Msg("end height is below the start height")
+	}
+
+	var finalizedHeight uint64
+	err = db.View(operation.RetrieveFinalizedHeight(&finalizedHeight))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not retrieve the finalized height")
+	}
+	seals := newSealIndex(storages, startHeight+1, finalizedHeight)
+
+	for height := startHeight; height <= endHeight; height++ {
+		block, err := readBlock(db, storages, seals, height)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not read block")
+		}
+
+		err = writer.Append(block)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not append block to archive")
+		}
+
+		if height%1000 == 0 {
+			log.Info().Uint64("height", height).Msg("exported blocks")
+		}
+	}
+
+	log.Info().
+		Uint64("start_height", startHeight).
+		Uint64("end_height", endHeight).
+		Msg("blocks exported")
+}
+
+// readBlock reads the sealed block at the given height with its collections, seal and, if it is available, its
+// sealed execution result, transaction results and events.
+func readBlock(db kv.DB, storages *storage.All, seals *sealIndex, height uint64) (*history.Block, error) {
+	block, err := storages.Blocks.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve block: %w", err)
+	}
+	blockID := block.ID()
+
+	archived := &history.Block{Block: block}
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := storages.Collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve collection %x: %w", guarantee.CollectionID, err)
+		}
+		archived.Collections = append(archived.Collections, collection)
+	}
+
+	seal, err := seals.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find seal: %w", err)
+	}
+	archived.Seal = seal
+
+	result, err := storages.Results.ByID(seal.ResultID)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Warn().Uint64("height", height).Hex("block_id", blockID[:]).Msg("sealed result is not available, exporting block without results")
+		return archived, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve sealed execution result: %w", err)
+	}
+	archived.Result = result
+
+	var txResults []flow.TransactionResult
+	err = db.View(operation.LookupTransactionResultsByBlockID(blockID, &txResults))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve transaction results: %w", err)
+	}
+	archived.TransactionResults = txResults
+
+	events, err := storages.Events.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve events: %w", err)
+	}
+	archived.Events = events
+
+	return archived, nil
+}
+
+// sealIndex finds the seals of the blocks exported in order of height. The seal of a block is included in the
+// payload of one of its finalized descendants, so the payloads are scanned forward from the block after the first
+// exported one, and the seals found ahead of their block are kept until it is exported.
+type sealIndex struct {
+	storages *storage.All
+	next     uint64 // height of the next finalized block to scan
+	last     uint64 // height of the last finalized block
+	seals    map[flow.Identifier]*flow.Seal
+}
+
+func newSealIndex(storages *storage.All, next uint64, last uint64) *sealIndex {
+	return &sealIndex{
+		storages: storages,
+		next:     next,
+		last:     last,
+		seals:    make(map[flow.Identifier]*flow.Seal),
+	}
+}
+
+// ByBlockID returns the seal of the block with the given ID. It returns storage.ErrNotFound if no finalized
+// block includes it.
+func (s *sealIndex) ByBlockID(blockID flow.Identifier) (*flow.Seal, error) {
+	seal, ok := s.seals[blockID]
+	if ok {
+		delete(s.seals, blockID)
+		return seal, nil
+	}
+
+	// the seal of the root block is not included in a payload, it is the last seal of the root block
+	seal, err := s.storages.Seals.ByBlockID(blockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("could not retrieve last seal: %w", err)
+	}
+	if err == nil && seal.BlockID == blockID {
+		return seal, nil
+	}
+
+	for s.next <= s.last {
+		block, err := s.storages.Blocks.ByHeight(s.next)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve block at height %d: %w", s.next, err)
+		}
+		s.next++
+
+		var found *flow.Seal
+		for _, seal := range block.Payload.Seals {
+			if seal.BlockID == blockID {
+				found = seal
+				continue
+			}
+			s.seals[seal.BlockID] = seal
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+
+	return nil, storage.ErrNotFound
+}
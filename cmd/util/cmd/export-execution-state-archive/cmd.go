@@ -0,0 +1,178 @@
+package archive
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+var (
+	flagExecutionStateDir string
+	flagDatadir           string
+	flagBlockID           string
+	flagOutputFile        string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "export-execution-state-archive",
+	Short: "Exports the sealed execution state of a block, with the matching protocol state snapshot, into an archive to bootstrap an execution node",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of the block (hex-encoded, 64 characters) to take the protocol state snapshot at, the latest finalized block if not set. "+
+			"The archive contains the execution state of the latest block sealed as of this block.")
+
+	Cmd.Flags().StringVar(&flagOutputFile, "output-file", "",
+		"file to write the archive to")
+	_ = Cmd.MarkFlagRequired("output-file")
+}
+
+func run(*cobra.Command, []string) {
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	var snapshot protocol.Snapshot
+	if len(flagBlockID) > 0 {
+		blockID, err := flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed block ID")
+		}
+		snapshot = state.AtBlockID(blockID)
+	} else {
+		snapshot = state.Final()
+	}
+
+	root, err := inmem.FromSnapshot(snapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create protocol state snapshot")
+	}
+
+	_, seal, err := root.SealedResult()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get sealed result of snapshot")
+	}
+
+	log.Info().
+		Hex("sealed_block_id", seal.BlockID[:]).
+		Hex("state_commitment", seal.FinalState[:]).
+		Msg("exporting sealed execution state")
+
+	// the checkpoint is only needed until it is added to the archive
+	checkpointDir, err := ioutil.TempDir(filepath.Dir(flagOutputFile), "checkpoint-")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create temporary checkpoint directory")
+	}
+	defer os.RemoveAll(checkpointDir)
+
+	err = exportCheckpoint(flagExecutionStateDir, seal.FinalState, checkpointDir, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not export checkpoint")
+	}
+
+	out, err := os.Create(flagOutputFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create archive file")
+	}
+	defer out.Close()
+
+	manifest, err := bootstrap.ExportArchive(out, filepath.Join(checkpointDir, bootstrapFilenames.FilenameWALRootCheckpoint), root)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not export archive")
+	}
+
+	err = out.Close()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not close archive file")
+	}
+
+	log.Info().
+		Hex("sealed_block_id", manifest.BlockID[:]).
+		Uint64("sealed_block_height", manifest.Height).
+		Hex("result_id", manifest.ResultID[:]).
+		Hex("state_commitment", manifest.StateCommitment[:]).
+		Str("output_file", flagOutputFile).
+		Msg("execution state archive exported")
+}
+
+// exportCheckpoint writes the root checkpoint of the given state commitment, read from the
+// write-ahead log and checkpoints in the given execution state directory, without migrations.
+func exportCheckpoint(dir string, commit flow.StateCommitment, outputDir string, log zerolog.Logger) error {
+
+	diskWal, err := wal.NewDiskWAL(
+		zerolog.Nop(),
+		nil,
+		metrics.NewNoopCollector(),
+		dir,
+		complete.DefaultCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create disk WAL: %w", err)
+	}
+	defer func() {
+		<-diskWal.Done()
+	}()
+
+	led, err := complete.NewLedger(
+		diskWal,
+		complete.DefaultCacheSize,
+		&metrics.NoopCollector{},
+		log,
+		complete.DefaultPathFinderVersion)
+	if err != nil {
+		return fmt.Errorf("cannot create ledger from write-a-head logs and checkpoints: %w", err)
+	}
+
+	exported, err := led.ExportCheckpointAt(
+		ledger.State(commit),
+		nil,
+		nil,
+		complete.DefaultPathFinderVersion,
+		outputDir,
+		bootstrapFilenames.FilenameWALRootCheckpoint,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot generate the output checkpoint: %w", err)
+	}
+
+	if flow.StateCommitment(exported) != commit {
+		return fmt.Errorf("exported checkpoint has state commitment %x, expected %x", exported, commit)
+	}
+
+	return nil
+}
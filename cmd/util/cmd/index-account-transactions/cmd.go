@@ -0,0 +1,90 @@
+package index_account_transactions
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/storage"
+	storagebadger "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var (
+	flagDatadir     string
+	flagStartHeight uint64
+	flagEndHeight   uint64
+)
+
+// Cmd backfills the index of the transactions by account. The accounts owning the registers written by the
+// transactions are only known while executing them, so only the payers, proposers and authorizers are backfilled.
+var Cmd = &cobra.Command{
+	Use:   "index-account-transactions",
+	Short: "Backfills the index of the transactions of the finalized blocks by payer, proposer and authorizer",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().Uint64Var(&flagStartHeight, "start-height", 0,
+		"height of the first finalized block to index")
+	_ = Cmd.MarkFlagRequired("start-height")
+
+	Cmd.Flags().Uint64Var(&flagEndHeight, "end-height", 0,
+		"height of the last finalized block to index (inclusive), the latest finalized block if not set")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Uint64("start_height", flagStartHeight).
+		Uint64("end_height", flagEndHeight).
+		Msg("flags")
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	accountTransactions := storagebadger.NewAccountTransactions(db)
+
+	endHeight := flagEndHeight
+	if endHeight == 0 {
+		err := db.View(operation.RetrieveFinalizedHeight(&endHeight))
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not retrieve the finalized height")
+		}
+	}
+
+	if endHeight < flagStartHeight {
+		log.Fatal().Uint64("end_height", endHeight).Msg("end height is below the start height")
+	}
+
+	for height := flagStartHeight; height <= endHeight; height++ {
+		block, err := storages.Blocks.ByHeight(height)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not retrieve block")
+		}
+
+		transactions, err := storage.BlockAccountTransactions(block, storages.Collections)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not get the account transactions of the block")
+		}
+
+		err = accountTransactions.Index(block.ID(), height, transactions)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not index the account transactions of the block")
+		}
+
+		if height%1000 == 0 {
+			log.Info().Uint64("height", height).Msg("indexed account transactions")
+		}
+	}
+
+	log.Info().
+		Uint64("start_height", flagStartHeight).
+		Uint64("end_height", endHeight).
+		Msg("account transactions indexed")
+}
@@ -0,0 +1,141 @@
+package inspect
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagCheckpoint      string
+	flagAddress         string
+	flagStateCommitment string
+	flagChain           string
+	flagOutputFile      string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "inspect-account",
+	Short: "Decodes the registers of an account in a checkpoint (keys, contracts, storage) and prints them as JSON",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file to read the execution state from")
+	_ = Cmd.MarkFlagRequired("checkpoint")
+
+	Cmd.Flags().StringVar(&flagAddress, "address", "",
+		"address of the account to inspect (hex-encoded)")
+	_ = Cmd.MarkFlagRequired("address")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"state commitment (hex-encoded, 64 characters) of the trie to inspect, the last trie of the checkpoint if not set")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", string(flow.Mainnet),
+		"chain name, used to resolve the service account when computing the storage capacity")
+
+	Cmd.Flags().StringVar(&flagOutputFile, "output-file", "",
+		"file to write the JSON report to, stdout if not set")
+}
+
+func getChain(chainName string) (chain flow.Chain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chain = flow.ChainID(chainName).Chain()
+	return
+}
+
+func run(*cobra.Command, []string) {
+
+	chain, err := getChain(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain name")
+	}
+
+	address := flow.HexToAddress(flagAddress)
+	if !chain.IsValid(address) {
+		log.Warn().Str("address", address.Hex()).Msg("address is not valid on the given chain")
+	}
+
+	log.Info().Str("checkpoint", flagCheckpoint).Msg("loading checkpoint")
+
+	t, err := loadTrie(flagCheckpoint, flagStateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not load trie")
+	}
+
+	report, err := InspectAccount(log.Logger, t, chain, address)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not inspect account")
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode report")
+	}
+
+	if len(flagOutputFile) == 0 {
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+	} else {
+		err = ioutil.WriteFile(flagOutputFile, data, 0644)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write report")
+	}
+}
+
+// loadTrie loads the trie with the given state commitment from a checkpoint file,
+// or the last trie of the checkpoint if no state commitment is given.
+func loadTrie(checkpoint string, stateCommitment string) (*trie.MTrie, error) {
+
+	flattenedForest, err := wal.LoadCheckpoint(checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not load checkpoint: %w", err)
+	}
+
+	tries, err := flattener.RebuildTries(flattenedForest)
+	if err != nil {
+		return nil, fmt.Errorf("could not rebuild tries from checkpoint: %w", err)
+	}
+
+	if len(tries) == 0 {
+		return nil, fmt.Errorf("checkpoint contains no tries")
+	}
+
+	if len(stateCommitment) == 0 {
+		return tries[len(tries)-1], nil
+	}
+
+	bytes, err := hex.DecodeString(stateCommitment)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode state commitment: %w", err)
+	}
+
+	rootHash, err := ledger.ToRootHash(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state commitment: %w", err)
+	}
+
+	for _, t := range tries {
+		if t.RootHash() == rootHash {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("checkpoint contains no trie with state commitment %x", rootHash)
+}
@@ -0,0 +1,334 @@
+package inspect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/blueprints"
+	"github.com/onflow/flow-go/fvm/programs"
+	fvmState "github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// AccountReport is the decoded content of all registers of an account.
+type AccountReport struct {
+	Address              string
+	StateCommitment      string
+	Exists               bool
+	Frozen               bool
+	StorageUsed          uint64
+	StorageCapacity      *uint64 `json:",omitempty"`
+	StorageCapacityError string  `json:",omitempty"`
+	PublicKeys           []PublicKeyReport
+	Contracts            []ContractReport
+	StoredValues         []StoredValueReport
+	Registers            []RegisterReport
+	Errors               []string `json:",omitempty"`
+}
+
+// PublicKeyReport is a decoded account public key.
+type PublicKeyReport struct {
+	Index     int
+	PublicKey string
+	SignAlgo  string
+	HashAlgo  string
+	Weight    int
+	SeqNumber uint64
+	Revoked   bool
+}
+
+// ContractReport is a contract deployed to the account.
+type ContractReport struct {
+	Name string
+	Code string
+}
+
+// StoredValueReport is a Cadence value stored in the account.
+type StoredValueReport struct {
+	Domain     string
+	Identifier string
+	Type       string
+	Size       int
+}
+
+// RegisterReport is a register owned by the account.
+type RegisterReport struct {
+	Controller string
+	Key        string
+	Size       int
+}
+
+// InspectAccount decodes the registers of the given account in the given trie.
+// Registers which cannot be decoded are reported in the errors of the report,
+// so a partially corrupted account can still be inspected.
+func InspectAccount(
+	log zerolog.Logger,
+	t *trie.MTrie,
+	chain flow.Chain,
+	address flow.Address,
+) (*AccountReport, error) {
+
+	registers, err := accountRegisters(t, address)
+	if err != nil {
+		return nil, fmt.Errorf("could not collect registers of account: %w", err)
+	}
+
+	report := &AccountReport{
+		Address:         address.Hex(),
+		StateCommitment: t.RootHash().String(),
+		PublicKeys:      []PublicKeyReport{},
+		Contracts:       []ContractReport{},
+		StoredValues:    []StoredValueReport{},
+		Registers:       []RegisterReport{},
+	}
+
+	for _, register := range registers {
+		report.Registers = append(report.Registers, RegisterReport{
+			Controller: hex.EncodeToString([]byte(register.id.Controller)),
+			Key:        register.id.Key,
+			Size:       len(register.value),
+		})
+	}
+
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		ledgerKey := state.RegisterIDToKey(flow.NewRegisterID(owner, controller, key))
+		path, err := pathfinder.KeyToPath(ledgerKey, complete.DefaultPathFinderVersion)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert key to path: %w", err)
+		}
+		payloads := t.UnsafeRead([]ledger.Path{path})
+		return payloads[0].Value, nil
+	})
+
+	// the inspection reads the whole account, which can be larger than what a transaction is allowed to touch
+	sth := fvmState.NewStateHolder(fvmState.NewState(view, fvmState.WithMaxInteractionSizeAllowed(math.MaxUint64)))
+	accounts := fvmState.NewAccounts(sth)
+
+	report.Exists, err = accounts.Exists(address)
+	if err != nil {
+		return nil, fmt.Errorf("could not check if account exists: %w", err)
+	}
+
+	report.Frozen, err = accounts.GetAccountFrozen(address)
+	if err != nil {
+		report.addError("could not get frozen flag: %s", err)
+	}
+
+	report.StorageUsed, err = accounts.GetStorageUsed(address)
+	if err != nil {
+		report.addError("could not get storage used: %s", err)
+	}
+
+	report.inspectPublicKeys(accounts, address)
+	report.inspectContracts(accounts, address)
+
+	for _, register := range registers {
+		report.inspectStoredValue(address, register)
+	}
+
+	if report.Exists {
+		capacity, err := storageCapacity(log, view.NewChild(), chain, address)
+		if err != nil {
+			report.StorageCapacityError = err.Error()
+		} else {
+			report.StorageCapacity = &capacity
+		}
+	}
+
+	return report, nil
+}
+
+func (r *AccountReport) addError(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *AccountReport) inspectPublicKeys(accounts fvmState.Accounts, address flow.Address) {
+	count, err := accounts.GetPublicKeyCount(address)
+	if err != nil {
+		r.addError("could not get public key count: %s", err)
+		return
+	}
+
+	for i := uint64(0); i < count; i++ {
+		key, err := accounts.GetPublicKey(address, i)
+		if err != nil {
+			r.addError("could not get public key %d: %s", i, err)
+			continue
+		}
+
+		publicKey := ""
+		if key.PublicKey != nil {
+			publicKey = hex.EncodeToString(key.PublicKey.Encode())
+		}
+
+		r.PublicKeys = append(r.PublicKeys, PublicKeyReport{
+			Index:     key.Index,
+			PublicKey: publicKey,
+			SignAlgo:  key.SignAlgo.String(),
+			HashAlgo:  key.HashAlgo.String(),
+			Weight:    key.Weight,
+			SeqNumber: key.SeqNumber,
+			Revoked:   key.Revoked,
+		})
+	}
+}
+
+func (r *AccountReport) inspectContracts(accounts fvmState.Accounts, address flow.Address) {
+	names, err := accounts.GetContractNames(address)
+	if err != nil {
+		r.addError("could not get contract names: %s", err)
+		return
+	}
+
+	for _, name := range names {
+		code, err := accounts.GetContract(name, address)
+		if err != nil {
+			r.addError("could not get contract %s: %s", name, err)
+			continue
+		}
+		r.Contracts = append(r.Contracts, ContractReport{
+			Name: name,
+			Code: string(code),
+		})
+	}
+}
+
+// inspectStoredValue decodes the Cadence value stored in the given register, if any.
+// Cadence keys have the form <domain>\x1F<identifier>, e.g. storage\x1FflowTokenVault.
+func (r *AccountReport) inspectStoredValue(address flow.Address, register accountRegister) {
+	if fvmState.IsFVMStateKey(register.id.Owner, register.id.Controller, register.id.Key) {
+		return
+	}
+
+	parts := strings.SplitN(register.id.Key, "\x1F", 2)
+	if len(parts) != 2 {
+		return
+	}
+
+	stored := StoredValueReport{
+		Domain:     parts[0],
+		Identifier: parts[1],
+		Size:       len(register.value),
+	}
+
+	data, version := interpreter.StripMagic(register.value)
+
+	decode := interpreter.DecodeValue
+	if version <= 4 {
+		decode = interpreter.DecodeValueV4
+	}
+
+	owner := common.BytesToAddress(address.Bytes())
+	value, err := decode(data, &owner, []string{register.id.Key}, version, nil)
+	if err != nil {
+		r.addError("could not decode value %s: %s", strings.Join(parts, "/"), err)
+	} else if staticType := value.StaticType(); staticType != nil {
+		stored.Type = staticType.String()
+	}
+
+	r.StoredValues = append(r.StoredValues, stored)
+}
+
+type accountRegister struct {
+	id    flow.RegisterID
+	value flow.RegisterValue
+}
+
+// accountRegisters returns all registers owned by the given address, sorted by key.
+func accountRegisters(t *trie.MTrie, address flow.Address) ([]accountRegister, error) {
+	owner := string(address.Bytes())
+
+	var registers []accountRegister
+	var err error
+
+	var walk func(n *node.Node)
+	walk = func(n *node.Node) {
+		if n == nil || err != nil {
+			return
+		}
+		if n.IsLeaf() {
+			payload := n.Payload()
+			if payload == nil {
+				return
+			}
+			var id flow.RegisterID
+			id, err = keyToRegisterID(payload.Key)
+			if err != nil {
+				return
+			}
+			if id.Owner == owner {
+				registers = append(registers, accountRegister{id: id, value: flow.RegisterValue(payload.Value)})
+			}
+			return
+		}
+		walk(n.LeftChild())
+		walk(n.RightChild())
+	}
+	walk(t.RootNode())
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(registers, func(i, j int) bool {
+		if registers[i].id.Controller != registers[j].id.Controller {
+			return registers[i].id.Controller < registers[j].id.Controller
+		}
+		return registers[i].id.Key < registers[j].id.Key
+	})
+
+	return registers, nil
+}
+
+func keyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
+	if len(key.KeyParts) != 3 ||
+		key.KeyParts[0].Type != state.KeyPartOwner ||
+		key.KeyParts[1].Type != state.KeyPartController ||
+		key.KeyParts[2].Type != state.KeyPartKey {
+		return flow.RegisterID{}, fmt.Errorf("key not in expected format %s", key.String())
+	}
+
+	return flow.NewRegisterID(
+		string(key.KeyParts[0].Value),
+		string(key.KeyParts[1].Value),
+		string(key.KeyParts[2].Value),
+	), nil
+}
+
+// storageCapacity computes the storage capacity of the account the same way
+// the FVM does when checking storage limits, in bytes.
+func storageCapacity(log zerolog.Logger, view fvmState.View, chain flow.Chain, address flow.Address) (uint64, error) {
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	ctx := fvm.NewContext(log, fvm.WithChain(chain))
+
+	script := fvm.Script(blueprints.GetStorageCapacityScript(address, chain.ServiceAddress()))
+	err := vm.Run(ctx, script, view, programs.NewEmptyPrograms())
+	if err != nil {
+		return 0, fmt.Errorf("could not run storage capacity script: %w", err)
+	}
+	if script.Err != nil {
+		return 0, fmt.Errorf("storage capacity script failed: %w", script.Err)
+	}
+
+	// the script returns a UFix64 in megabytes, see TransactionEnv.GetStorageCapacity
+	capacity, ok := script.Value.ToGoValue().(uint64)
+	if !ok {
+		return 0, fmt.Errorf("storage capacity script returned an unexpected value: %s", script.Value)
+	}
+	return capacity / 100, nil
+}
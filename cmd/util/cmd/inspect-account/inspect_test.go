@@ -0,0 +1,102 @@
+package inspect
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestInspectAccount(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		chain := flow.Mainnet.Chain()
+
+		led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		commit, err := bootstrap.NewBootstrapper(zerolog.Nop()).BootstrapLedger(
+			led,
+			unittest.ServiceAccountPublicKey,
+			chain,
+			fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+			fvm.WithMinimumStorageReservation(fvm.DefaultMinimumStorageReservation),
+			fvm.WithStorageMBPerFLOW(fvm.DefaultStorageMBPerFLOW),
+		)
+		require.NoError(t, err)
+
+		_, err = led.ExportCheckpointAt(ledger.State(commit), nil, nil, complete.DefaultPathFinderVersion, dir, "checkpoint")
+		require.NoError(t, err)
+		checkpoint := filepath.Join(dir, "checkpoint")
+
+		t.Run("service account", func(t *testing.T) {
+			trie, err := loadTrie(checkpoint, hex.EncodeToString(commit[:]))
+			require.NoError(t, err)
+
+			report, err := InspectAccount(zerolog.Nop(), trie, chain, chain.ServiceAddress())
+			require.NoError(t, err)
+
+			assert.Empty(t, report.Errors)
+			assert.True(t, report.Exists)
+			assert.False(t, report.Frozen)
+			assert.NotZero(t, report.StorageUsed)
+			require.NotNil(t, report.StorageCapacity, report.StorageCapacityError)
+			assert.NotZero(t, *report.StorageCapacity)
+			assert.NotEmpty(t, report.Registers)
+
+			require.Len(t, report.PublicKeys, 1)
+			assert.Equal(t, unittest.ServiceAccountPublicKey.SignAlgo.String(), report.PublicKeys[0].SignAlgo)
+			assert.Equal(t, unittest.ServiceAccountPublicKey.HashAlgo.String(), report.PublicKeys[0].HashAlgo)
+			assert.Equal(t, unittest.ServiceAccountPublicKey.Weight, report.PublicKeys[0].Weight)
+
+			contracts := make(map[string]string)
+			for _, contract := range report.Contracts {
+				contracts[contract.Name] = contract.Code
+			}
+			assert.Contains(t, contracts, "FlowServiceAccount")
+			assert.NotEmpty(t, contracts["FlowServiceAccount"])
+
+			var vault *StoredValueReport
+			for i, value := range report.StoredValues {
+				if value.Domain == "storage" && value.Identifier == "flowTokenVault" {
+					vault = &report.StoredValues[i]
+				}
+			}
+			require.NotNil(t, vault)
+			assert.Contains(t, vault.Type, "FlowToken.Vault")
+		})
+
+		t.Run("missing account", func(t *testing.T) {
+			// no state commitment selects the last trie of the checkpoint
+			trie, err := loadTrie(checkpoint, "")
+			require.NoError(t, err)
+
+			report, err := InspectAccount(zerolog.Nop(), trie, chain, unittest.RandomAddressFixture())
+			require.NoError(t, err)
+
+			assert.False(t, report.Exists)
+			assert.Nil(t, report.StorageCapacity)
+			assert.Empty(t, report.PublicKeys)
+			assert.Empty(t, report.Contracts)
+			assert.Empty(t, report.Registers)
+		})
+
+		t.Run("unknown state commitment", func(t *testing.T) {
+			unknown := unittest.StateCommitmentFixture()
+			_, err := loadTrie(checkpoint, hex.EncodeToString(unknown[:]))
+			require.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/storage/badger/migration"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "get the schema version, the pending migrations and the number and size of the keys of each prefix",
+	Run: func(cmd *cobra.Command, args []string) {
+		db := common.InitStorage(flagDatadir)
+		defer db.Close()
+
+		version, err := migration.Version(db)
+		if err != nil {
+			log.Error().Err(err).Msg("could not get schema version")
+			return
+		}
+
+		migrator, err := migration.NewMigrator(log.Logger, migration.Migrations)
+		if err != nil {
+			log.Error().Err(err).Msg("could not initialize migrator")
+			return
+		}
+
+		log.Info().
+			Uint64("version", version).
+			Uint64("latest_version", migrator.LatestVersion()).
+			Msg("schema version")
+
+		pending, err := migrator.Pending(version)
+		if err != nil {
+			log.Error().Err(err).Msg("could not get pending migrations")
+		}
+		for _, m := range pending {
+			log.Info().
+				Uint64("version", m.Version).
+				Str("description", m.Description).
+				Msg("pending migration")
+		}
+
+		log.Info().Msg("counting keys of each prefix, this may take a while")
+		var stats []operation.PrefixStats
+		err = db.View(operation.SummarizePrefixes(&stats))
+		if err != nil {
+			log.Error().Err(err).Msg("could not count keys")
+			return
+		}
+
+		var total operation.PrefixStats
+		for _, prefix := range stats {
+			log.Info().
+				Uint8("code", prefix.Code).
+				Str("name", operation.PrefixName(prefix.Code)).
+				Uint64("keys", prefix.Keys).
+				Uint64("key_bytes", prefix.KeySize).
+				Uint64("value_bytes", prefix.ValueSize).
+				Msg("prefix")
+
+			total.Keys += prefix.Keys
+			total.KeySize += prefix.KeySize
+			total.ValueSize += prefix.ValueSize
+		}
+		log.Info().
+			Uint64("keys", total.Keys).
+			Uint64("key_bytes", total.KeySize).
+			Uint64("value_bytes", total.ValueSize).
+			Msg("total")
+	},
+}
@@ -1,13 +1,12 @@
 package cmd
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InitStorages() (*storage.All, *badger.DB) {
+func InitStorages() (*storage.All, kv.DB) {
 	db := common.InitStorage(flagDatadir)
 	storages := common.InitStorages(db)
 	return storages, db
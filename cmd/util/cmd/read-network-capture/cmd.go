@@ -0,0 +1,189 @@
+package readcapture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+)
+
+var (
+	flagCaptureDir  string
+	flagCaptureFile string
+	flagDirection   string
+	flagChannels    []string
+	flagOriginIDs   []string
+	flagFrom        string
+	flagTo          string
+	flagDecode      bool
+	flagOutputFile  string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "read-network-capture",
+	Short: "Prints the messages of a network capture, or extracts a subset of them into a capture file or replays them",
+	Run:   run,
+}
+
+func init() {
+	// the flags selecting the messages are shared with the subcommands
+	Cmd.PersistentFlags().StringVar(&flagCaptureDir, "capture-dir", "",
+		"directory of the capture files written by a node with --network-capture-dir")
+
+	Cmd.PersistentFlags().StringVar(&flagCaptureFile, "capture-file", "",
+		"single capture file to read, instead of a capture directory")
+
+	Cmd.PersistentFlags().StringVar(&flagDirection, "direction", "",
+		"only select the messages in the given direction (inbound or outbound)")
+
+	Cmd.PersistentFlags().StringSliceVar(&flagChannels, "channels", nil,
+		"only select the messages of the given channels")
+
+	Cmd.PersistentFlags().StringSliceVar(&flagOriginIDs, "origin-ids", nil,
+		"only select the messages of the given origin node IDs (hex-encoded)")
+
+	Cmd.PersistentFlags().StringVar(&flagFrom, "from", "",
+		"only select the messages captured at or after the given time (RFC3339)")
+
+	Cmd.PersistentFlags().StringVar(&flagTo, "to", "",
+		"only select the messages captured at or before the given time (RFC3339)")
+
+	Cmd.Flags().BoolVar(&flagDecode, "decode", false,
+		"decode the payloads of the printed messages")
+
+	Cmd.Flags().StringVar(&flagOutputFile, "output-file", "",
+		"capture file to write the selected messages to, e.g. as a test fixture, instead of printing them")
+}
+
+// printedRecord is a captured message, as printed by the command.
+type printedRecord struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Direction capture.Direction `json:"direction"`
+	Channel   network.Channel   `json:"channel"`
+	OriginID  flow.Identifier   `json:"origin_id"`
+	TargetIDs []flow.Identifier `json:"target_ids"`
+	Type      string            `json:"type"`
+	Size      int               `json:"size"`
+	Event     interface{}       `json:"event,omitempty"`
+}
+
+func run(*cobra.Command, []string) {
+
+	selected := readSelected()
+
+	if flagOutputFile != "" {
+		err := capture.WriteFile(flagOutputFile, selected)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write capture file")
+		}
+		log.Info().Str("file", flagOutputFile).Msg("selected messages written")
+		return
+	}
+
+	codec := cborcodec.NewCodec()
+	encoder := json.NewEncoder(os.Stdout)
+	for _, record := range selected {
+		printed := printedRecord{
+			Timestamp: record.Timestamp,
+			Direction: record.Direction,
+			Channel:   record.Channel,
+			OriginID:  record.OriginID,
+			TargetIDs: record.TargetIDs,
+			Type:      record.Type,
+			Size:      len(record.Envelope),
+		}
+
+		if flagDecode {
+			event, err := record.Event(codec)
+			if err != nil {
+				log.Warn().Err(err).Time("timestamp", record.Timestamp).Msg("could not decode captured message")
+			} else {
+				printed.Event = event
+			}
+		}
+
+		err := encoder.Encode(printed)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not print captured message")
+		}
+	}
+}
+
+// readSelected reads the capture and returns the messages selected by the flags.
+func readSelected() []*capture.Record {
+
+	if (flagCaptureDir == "") == (flagCaptureFile == "") {
+		log.Fatal().Msg("exactly one of --capture-dir and --capture-file must be set")
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid filter")
+	}
+
+	var records []*capture.Record
+	if flagCaptureDir != "" {
+		records, err = capture.ReadDir(flagCaptureDir)
+	} else {
+		records, err = capture.ReadFile(flagCaptureFile)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read capture")
+	}
+
+	selected := filter.Apply(records)
+	log.Info().Int("total", len(records)).Int("selected", len(selected)).Msg("capture read")
+
+	return selected
+}
+
+func parseFilter() (capture.Filter, error) {
+	var filter capture.Filter
+
+	switch capture.Direction(strings.ToLower(flagDirection)) {
+	case "":
+	case capture.Inbound:
+		filter.Direction = capture.Inbound
+	case capture.Outbound:
+		filter.Direction = capture.Outbound
+	default:
+		return filter, fmt.Errorf("invalid direction: %s", flagDirection)
+	}
+
+	for _, channel := range flagChannels {
+		filter.Channels = append(filter.Channels, network.Channel(channel))
+	}
+
+	for _, originID := range flagOriginIDs {
+		id, err := flow.HexStringToIdentifier(originID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid origin ID %s: %w", originID, err)
+		}
+		filter.OriginIDs = append(filter.OriginIDs, id)
+	}
+
+	var err error
+	if flagFrom != "" {
+		filter.From, err = time.Parse(time.RFC3339, flagFrom)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --from time: %w", err)
+		}
+	}
+	if flagTo != "" {
+		filter.To, err = time.Parse(time.RFC3339, flagTo)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --to time: %w", err)
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,116 @@
+package readcapture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/stub"
+)
+
+var flagNodeID string
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replays the selected inbound messages of a network capture on a stub network, printing the delivered events",
+	Run:   runReplay,
+}
+
+func init() {
+	Cmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringVar(&flagNodeID, "node-id", flow.ZeroID.String(),
+		"node ID of the capturing node, i.e. the node the messages are replayed to (hex-encoded)")
+}
+
+// replayedEvent is an event delivered to an engine during the replay, as printed by the command.
+type replayedEvent struct {
+	Channel  network.Channel `json:"channel"`
+	OriginID flow.Identifier `json:"origin_id"`
+	Type     string          `json:"type"`
+	Event    interface{}     `json:"event"`
+}
+
+func runReplay(*cobra.Command, []string) {
+
+	nodeID, err := flow.HexStringToIdentifier(flagNodeID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid node ID")
+	}
+
+	selected := readSelected()
+
+	me, err := local.New(&flow.Identity{NodeID: nodeID}, nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create local identity")
+	}
+	net := stub.NewNetwork(nil, me, stub.NewNetworkHub())
+
+	// the events of all channels with inbound messages are printed in the order of delivery
+	printer := &printingEngine{encoder: json.NewEncoder(os.Stdout)}
+	registered := make(map[network.Channel]struct{})
+	for _, record := range selected {
+		if record.Direction != capture.Inbound {
+			continue
+		}
+		if _, ok := registered[record.Channel]; ok {
+			continue
+		}
+		_, err = net.Register(record.Channel, printer)
+		if err != nil {
+			log.Fatal().Err(err).Str("channel", record.Channel.String()).Msg("could not register engine")
+		}
+		registered[record.Channel] = struct{}{}
+	}
+
+	err = net.Replay(selected, cborcodec.NewCodec())
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not replay capture")
+	}
+	log.Info().Int("channels", len(registered)).Int("delivered", printer.delivered).Msg("capture replayed")
+}
+
+// printingEngine prints the events delivered by the stub network.
+type printingEngine struct {
+	encoder   *json.Encoder
+	delivered int
+}
+
+var _ network.Engine = (*printingEngine)(nil)
+
+func (e *printingEngine) SubmitLocal(event interface{}) {
+	log.Fatal().Msg("unexpected local event during replay")
+}
+
+func (e *printingEngine) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	err := e.Process(channel, originID, event)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not process event")
+	}
+}
+
+func (e *printingEngine) ProcessLocal(event interface{}) error {
+	return fmt.Errorf("unexpected local event during replay")
+}
+
+func (e *printingEngine) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	e.delivered++
+	err := e.encoder.Encode(replayedEvent{
+		Channel:  channel,
+		OriginID: originID,
+		Type:     fmt.Sprintf("%T", event),
+		Event:    event,
+	})
+	if err != nil {
+		return fmt.Errorf("could not print event: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 func run(*cobra.Command, []string) {
@@ -17,21 +18,30 @@ func run(*cobra.Command, []string) {
 	db := common.InitStorage(flagDatadir)
 	defer db.Close()
 
-	err := db.Update(operation.RemoveExecutionForkEvidence())
+	// the evidence is stored by consensus nodes, the report by execution nodes
+	removedEvidence := remove(db, operation.RemoveExecutionForkEvidence(), "evidence")
+	removedReport := remove(db, operation.RemoveExecutionForkReport(), "report")
 
 	// for testing purpose
 	// expectedSeals := unittest.IncorporatedResultSeal.Fixtures(2)
 	// err := db.Update(operation.InsertExecutionForkEvidence(expectedSeals))
 
-	if err == storage.ErrNotFound {
+	if !removedEvidence && !removedReport {
 		log.Info().Msg("no execution fork was found, exit")
 		return
 	}
 
+	log.Info().Msg("execution fork removed")
+}
+
+// remove removes the stored execution fork information, and returns false if it is not found.
+func remove(db kv.DB, removal func(kv.Txn) error, name string) bool {
+	err := db.Update(removal)
+	if err == storage.ErrNotFound {
+		return false
+	}
 	if err != nil {
-		log.Fatal().Err(err).Msg("could not remove execution fork")
-		return
+		log.Fatal().Err(err).Str("information", name).Msg("could not remove execution fork")
 	}
-
-	log.Info().Msg("execution fork removed")
+	return true
 }
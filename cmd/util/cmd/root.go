@@ -9,12 +9,19 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	check_database "github.com/onflow/flow-go/cmd/util/cmd/check-database"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
+	diagnose_execution_fork "github.com/onflow/flow-go/cmd/util/cmd/diagnose-execution-fork"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
 	extract "github.com/onflow/flow-go/cmd/util/cmd/execution-state-extract"
+	export_block_archive "github.com/onflow/flow-go/cmd/util/cmd/export-block-archive"
+	archive "github.com/onflow/flow-go/cmd/util/cmd/export-execution-state-archive"
 	ledger_json_exporter "github.com/onflow/flow-go/cmd/util/cmd/export-json-execution-state"
+	index_account_transactions "github.com/onflow/flow-go/cmd/util/cmd/index-account-transactions"
+	inspect_account "github.com/onflow/flow-go/cmd/util/cmd/inspect-account"
 	read_badger "github.com/onflow/flow-go/cmd/util/cmd/read-badger/cmd"
+	read_network_capture "github.com/onflow/flow-go/cmd/util/cmd/read-network-capture"
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
 )
@@ -58,6 +65,13 @@ func addCommands() {
 	rootCmd.AddCommand(read_protocol_state.RootCmd)
 	rootCmd.AddCommand(ledger_json_exporter.Cmd)
 	rootCmd.AddCommand(epochs.RootCmd)
+	rootCmd.AddCommand(archive.Cmd)
+	rootCmd.AddCommand(inspect_account.Cmd)
+	rootCmd.AddCommand(read_network_capture.Cmd)
+	rootCmd.AddCommand(index_account_transactions.Cmd)
+	rootCmd.AddCommand(check_database.Cmd)
+	rootCmd.AddCommand(export_block_archive.Cmd)
+	rootCmd.AddCommand(diagnose_execution_fork.Cmd)
 }
 
 func initConfig() {
@@ -0,0 +1,231 @@
+package migrations
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Invariant is a property of the ledger which migrations must preserve.
+type Invariant interface {
+	Name() string
+	// Check returns a summary of the payloads, which must be the same before and after
+	// migrating, or an error if the payloads violate the invariant.
+	Check(payloads []ledger.Payload) (string, error)
+}
+
+// TotalSupplyInvariant checks that the total FLOW supply recorded by the FlowToken contract,
+// and the sum of the balances of all FlowToken vaults, are preserved.
+type TotalSupplyInvariant struct {
+	Log   zerolog.Logger
+	Chain flow.Chain
+}
+
+var _ Invariant = &TotalSupplyInvariant{}
+
+func (i *TotalSupplyInvariant) Name() string {
+	return "total-supply"
+}
+
+func (i *TotalSupplyInvariant) Check(payloads []ledger.Payload) (string, error) {
+	flowTokenAddress := fvm.FlowTokenAddress(i.Chain)
+	vaultTypeID := fmt.Sprintf("A.%s.FlowToken.Vault", flowTokenAddress.Hex())
+	contractID := flow.NewRegisterID(string(flowTokenAddress.Bytes()), "", "contract\x1FFlowToken")
+
+	workerCount := runtime.NumCPU()
+	jobs := make(chan ledger.Payload)
+	balances := make([]uint64, workerCount)
+	supplies := make([]uint64, workerCount)
+	failures := make([]int, workerCount)
+
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workerCount; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for p := range jobs {
+				balance, supply, err := flowTokenBalances(p, vaultTypeID, contractID)
+				if err != nil {
+					// values which cannot be decoded are not Cadence values, or are broken already
+					i.Log.Debug().Err(err).Msg("could not decode payload")
+					failures[w]++
+					continue
+				}
+				balances[w] += balance
+				supplies[w] += supply
+			}
+		}(w)
+	}
+
+	for _, p := range payloads {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	var balance, supply uint64
+	var failed int
+	for w := 0; w < workerCount; w++ {
+		balance += balances[w]
+		supply += supplies[w]
+		failed += failures[w]
+	}
+
+	if failed > 0 {
+		i.Log.Warn().Int("payloads", failed).Msg("payloads could not be decoded and are not included in the total supply")
+	}
+
+	return fmt.Sprintf("total supply: %d, vault balances: %d", supply, balance), nil
+}
+
+// flowTokenBalances returns the sum of the balances of the vaults stored in the payload,
+// and the total supply if the payload is the FlowToken contract.
+func flowTokenBalances(p ledger.Payload, vaultTypeID string, contractID flow.RegisterID) (uint64, uint64, error) {
+	id, err := keyToRegisterID(p.Key)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Ignore known payload keys that are not Cadence values
+	if state.IsFVMStateKey(id.Owner, id.Controller, id.Key) || len(p.Value) == 0 {
+		return 0, 0, nil
+	}
+
+	value, version := interpreter.StripMagic(p.Value)
+
+	err = storageMigrationV5DecMode.Valid(value)
+	if err != nil {
+		return 0, 0, nil
+	}
+
+	decodeFunction := interpreter.DecodeValue
+	if version <= 4 {
+		decodeFunction = interpreter.DecodeValueV4
+	}
+
+	owner := common.BytesToAddress([]byte(id.Owner))
+	cValue, err := decodeFunction(value, &owner, []string{id.Key}, version, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode value of %s: %w", id.String(), err)
+	}
+
+	var supply uint64
+	if id == contractID {
+		composite, ok := cValue.(*interpreter.CompositeValue)
+		if !ok {
+			return 0, 0, fmt.Errorf("FlowToken contract is not a composite value")
+		}
+		totalSupply, ok := composite.GetField("totalSupply").(interpreter.UFix64Value)
+		if !ok {
+			return 0, 0, fmt.Errorf("FlowToken contract has no UFix64 total supply")
+		}
+		supply = uint64(totalSupply)
+	}
+
+	var balance uint64
+	var visitErr error
+	visitor := &interpreter.EmptyVisitor{
+		CompositeValueVisitor: func(inter *interpreter.Interpreter, value *interpreter.CompositeValue) bool {
+			if visitErr != nil {
+				return false
+			}
+			if string(value.TypeID()) == vaultTypeID {
+				vaultBalance, ok := value.GetField("balance").(interpreter.UFix64Value)
+				if !ok {
+					visitErr = fmt.Errorf("FlowToken vault in %s has no UFix64 balance", id.String())
+					return false
+				}
+				balance += uint64(vaultBalance)
+				return false
+			}
+			return true
+		},
+		DictionaryValueVisitor: func(interpreter *interpreter.Interpreter, value *interpreter.DictionaryValue) bool {
+			// deferred values are stored in their own registers
+			return value.DeferredKeys() == nil
+		},
+	}
+
+	inter, err := interpreter.NewInterpreter(nil, common.StringLocation("somewhere"))
+	if err != nil {
+		return 0, 0, err
+	}
+	cValue.Accept(inter, visitor)
+	if visitErr != nil {
+		return 0, 0, visitErr
+	}
+
+	return balance, supply, nil
+}
+
+// StorageUsedInvariant checks that the storage used register of each account matches
+// the size of the registers of the account.
+type StorageUsedInvariant struct{}
+
+var _ Invariant = &StorageUsedInvariant{}
+
+func (i *StorageUsedInvariant) Name() string {
+	return "storage-used"
+}
+
+func (i *StorageUsedInvariant) Check(payloads []ledger.Payload) (string, error) {
+	used := make(map[string]uint64)
+	recorded := make(map[string]uint64)
+
+	for _, p := range payloads {
+		id, err := keyToRegisterID(p.Key)
+		if err != nil {
+			return "", err
+		}
+		if len([]byte(id.Owner)) != flow.AddressLength {
+			// not an address
+			continue
+		}
+
+		used[id.Owner] += uint64(registerSize(id, p))
+
+		if id.Key == state.KeyStorageUsed && len(p.Value) > 0 {
+			storageUsed, _, err := utils.ReadUint64(p.Value)
+			if err != nil {
+				return "", fmt.Errorf("cannot decode storage used of %x: %w", id.Owner, err)
+			}
+			recorded[id.Owner] = storageUsed
+		}
+	}
+
+	var inconsistent []string
+	for owner, size := range used {
+		storageUsed, ok := recorded[owner]
+		if size == 0 && !ok {
+			// only deleted registers
+			continue
+		}
+		if !ok || storageUsed != size {
+			inconsistent = append(inconsistent, flow.BytesToAddress([]byte(owner)).Hex())
+		}
+	}
+
+	if len(inconsistent) > 0 {
+		sort.Strings(inconsistent)
+		const maxListed = 10
+		listed := inconsistent
+		if len(listed) > maxListed {
+			listed = listed[:maxListed]
+		}
+		return "", fmt.Errorf("%d accounts have an inconsistent storage used (%s)", len(inconsistent), strings.Join(listed, ", "))
+	}
+
+	return "", nil
+}
@@ -0,0 +1,448 @@
+package migrations
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// AccountMigration migrates the payloads of a single account.
+type AccountMigration func(address flow.Address, payloads []ledger.Payload) ([]ledger.Payload, error)
+
+// NamedMigration is a step of a migration run.
+// Exactly one of Migrate and MigrateAccount must be set.
+type NamedMigration struct {
+	Name string
+	// Migrate migrates all payloads at once.
+	Migrate ledger.Migration
+	// MigrateAccount migrates the payloads of each account independently. Accounts are
+	// migrated in parallel, payloads which are not owned by an account are left untouched.
+	MigrateAccount AccountMigration
+}
+
+// MigrationRunner applies an ordered list of migrations to the payloads of a ledger,
+// checks the invariants before and after migrating, and reports the changed registers.
+type MigrationRunner struct {
+	Log zerolog.Logger
+	// OutputDir is where the migration report is written, no report is written if empty.
+	OutputDir  string
+	Migrations []NamedMigration
+	Invariants []Invariant
+	// Workers is the number of accounts migrated in parallel, the number of CPUs if not set.
+	Workers int
+}
+
+// MigrationReport is the outcome of a migration run.
+type MigrationReport struct {
+	Migrations []MigrationStepReport
+	Invariants []InvariantReport
+	Accounts   []AccountChange
+	Registers  []RegisterChange
+}
+
+// MigrationStepReport is the outcome of a single migration.
+type MigrationStepReport struct {
+	Name           string
+	Duration       string
+	PayloadsBefore int
+	PayloadsAfter  int
+}
+
+// InvariantReport is the outcome of an invariant check.
+type InvariantReport struct {
+	Name        string
+	Before      string
+	After       string
+	BeforeError string `json:",omitempty"`
+	AfterError  string `json:",omitempty"`
+	Violated    bool
+}
+
+// AccountChange sums the changes of the registers of an owner.
+type AccountChange struct {
+	Owner            string
+	RegistersAdded   int
+	RegistersRemoved int
+	RegistersUpdated int
+	SizeDelta        int64
+}
+
+// RegisterChange is a register added, removed or updated by the migrations.
+// Sizes are the sizes of the register values in bytes.
+type RegisterChange struct {
+	Owner      string
+	Controller string
+	Key        string
+	Change     string
+	SizeBefore int
+	SizeAfter  int
+}
+
+const (
+	RegisterAdded   = "added"
+	RegisterRemoved = "removed"
+	RegisterUpdated = "updated"
+)
+
+func (r *MigrationRunner) filename() string {
+	return path.Join(r.OutputDir, fmt.Sprintf("migration_report_%d.json", int32(time.Now().Unix())))
+}
+
+// Migrate runs the migrations and can be used wherever a ledger.Migration is expected.
+func (r *MigrationRunner) Migrate(payloads []ledger.Payload) ([]ledger.Payload, error) {
+	migrated, _, err := r.Run(payloads)
+	return migrated, err
+}
+
+// Run runs the migrations in order and returns the migrated payloads with the report of the run.
+// The report is written before invariant violations are returned, so they can be investigated.
+// Migrations must not modify the values of the given payloads in place.
+func (r *MigrationRunner) Run(payloads []ledger.Payload) ([]ledger.Payload, *MigrationReport, error) {
+
+	for _, migration := range r.Migrations {
+		if (migration.Migrate == nil) == (migration.MigrateAccount == nil) {
+			return nil, nil, fmt.Errorf("migration %s must set exactly one of Migrate and MigrateAccount", migration.Name)
+		}
+	}
+
+	// keep the original payloads to compute the changes, migrations may replace payloads of the slice they get
+	original := make([]ledger.Payload, len(payloads))
+	copy(original, payloads)
+
+	report := &MigrationReport{}
+
+	before := make([]InvariantReport, len(r.Invariants))
+	for i, invariant := range r.Invariants {
+		before[i].Name = invariant.Name()
+		before[i].Before, before[i].BeforeError = r.checkInvariant(invariant, original)
+	}
+
+	migrated := payloads
+	for _, migration := range r.Migrations {
+		r.Log.Info().Str("migration", migration.Name).Msg("migration is underway")
+
+		start := time.Now()
+		count := len(migrated)
+
+		var err error
+		if migration.Migrate != nil {
+			migrated, err = migration.Migrate(migrated)
+		} else {
+			migrated, err = r.migrateAccounts(migration.MigrateAccount, migrated)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error applying migration %s: %w", migration.Name, err)
+		}
+
+		step := MigrationStepReport{
+			Name:           migration.Name,
+			Duration:       time.Since(start).String(),
+			PayloadsBefore: count,
+			PayloadsAfter:  len(migrated),
+		}
+		report.Migrations = append(report.Migrations, step)
+
+		r.Log.Info().
+			Str("migration", migration.Name).
+			Str("timeTaken", step.Duration).
+			Int("payloadsBefore", step.PayloadsBefore).
+			Int("payloadsAfter", step.PayloadsAfter).
+			Msg("migration is done")
+	}
+
+	var violations []string
+	for i, invariant := range r.Invariants {
+		result := before[i]
+		result.After, result.AfterError = r.checkInvariant(invariant, migrated)
+
+		switch {
+		case result.AfterError != "":
+			result.Violated = true
+		case result.BeforeError != "":
+			// the migrations may be meant to fix the violation
+			r.Log.Warn().Str("invariant", result.Name).Str("error", result.BeforeError).Msg("invariant was violated before migrating")
+		case result.Before != result.After:
+			result.Violated = true
+		}
+
+		if result.Violated {
+			violations = append(violations, result.Name)
+			r.Log.Error().
+				Str("invariant", result.Name).
+				Str("before", result.Before).
+				Str("after", result.After).
+				Str("error", result.AfterError).
+				Msg("invariant violated by migrations")
+		}
+
+		report.Invariants = append(report.Invariants, result)
+	}
+
+	report.Registers, report.Accounts = payloadChanges(original, migrated)
+
+	r.Log.Info().
+		Int("changedRegisters", len(report.Registers)).
+		Int("changedAccounts", len(report.Accounts)).
+		Msg("migrations are done")
+
+	if r.OutputDir != "" {
+		err := r.writeReport(report)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not write migration report: %w", err)
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, report, fmt.Errorf("migrations violated invariants: %v", violations)
+	}
+
+	return migrated, report, nil
+}
+
+// checkInvariant checks the invariant on the payloads, and returns its summary and error message, if any.
+func (r *MigrationRunner) checkInvariant(invariant Invariant, payloads []ledger.Payload) (string, string) {
+	start := time.Now()
+	summary, err := invariant.Check(payloads)
+
+	r.Log.Info().
+		Str("invariant", invariant.Name()).
+		Str("summary", summary).
+		Str("timeTaken", time.Since(start).String()).
+		AnErr("error", err).
+		Msg("invariant checked")
+
+	if err != nil {
+		return summary, err.Error()
+	}
+	return summary, ""
+}
+
+func (r *MigrationRunner) writeReport(report *MigrationReport) error {
+	fn := r.filename()
+	r.Log.Info().Msgf("Writing migration report to %s.", fn)
+
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writer := bufio.NewWriter(f)
+	err = json.NewEncoder(writer).Encode(report)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// migrateAccounts applies the migration to each account in parallel. The migrated payloads are
+// ordered deterministically: payloads not owned by an account first, in their original order,
+// then the payloads of each account ordered by address, in the order returned by the migration.
+func (r *MigrationRunner) migrateAccounts(migrate AccountMigration, payloads []ledger.Payload) ([]ledger.Payload, error) {
+
+	var other []ledger.Payload
+	accounts := make(map[flow.Address][]ledger.Payload)
+
+	for _, p := range payloads {
+		address, ok := payloadAddress(p)
+		if !ok {
+			other = append(other, p)
+			continue
+		}
+		accounts[address] = append(accounts[address], p)
+	}
+
+	addresses := make([]flow.Address, 0, len(accounts))
+	for address := range accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Hex() < addresses[j].Hex()
+	})
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([][]ledger.Payload, len(addresses))
+	errs := make([]error, len(addresses))
+
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				address := addresses[index]
+				results[index], errs[index] = migrate(address, accounts[address])
+			}
+		}()
+	}
+
+	for i := range addresses {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	migrated := make([]ledger.Payload, 0, len(payloads))
+	migrated = append(migrated, other...)
+	for i, address := range addresses {
+		// the first error by address order, so the error is deterministic as well
+		if errs[i] != nil {
+			return nil, fmt.Errorf("error migrating account %s: %w", address.Hex(), errs[i])
+		}
+		migrated = append(migrated, results[i]...)
+	}
+
+	return migrated, nil
+}
+
+// payloadAddress returns the address of the account owning the payload, if any.
+func payloadAddress(p ledger.Payload) (flow.Address, bool) {
+	id, err := keyToRegisterID(p.Key)
+	if err != nil || len([]byte(id.Owner)) != flow.AddressLength {
+		return flow.EmptyAddress, false
+	}
+	return flow.BytesToAddress([]byte(id.Owner)), true
+}
+
+// sortedPayloads returns pointers to the payloads, sorted by key. The payloads themselves are not reordered.
+func sortedPayloads(payloads []ledger.Payload) []*ledger.Payload {
+	sorted := make([]*ledger.Payload, 0, len(payloads))
+	for i := range payloads {
+		sorted = append(sorted, &payloads[i])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareKeys(sorted[i].Key, sorted[j].Key) < 0
+	})
+	return sorted
+}
+
+// compareKeys orders the keys by their parts, and returns 0 only for equal keys.
+func compareKeys(a ledger.Key, b ledger.Key) int {
+	for i := 0; i < len(a.KeyParts) && i < len(b.KeyParts); i++ {
+		if a.KeyParts[i].Type != b.KeyParts[i].Type {
+			if a.KeyParts[i].Type < b.KeyParts[i].Type {
+				return -1
+			}
+			return 1
+		}
+		if cmp := bytes.Compare(a.KeyParts[i].Value, b.KeyParts[i].Value); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(a.KeyParts) - len(b.KeyParts)
+}
+
+// payloadChanges returns the registers changed between the two sets of payloads, and the
+// changes summed by owner, both sorted by owner.
+func payloadChanges(before []ledger.Payload, after []ledger.Payload) ([]RegisterChange, []AccountChange) {
+
+	// both sets of payloads are walked in key order, so that they are compared without indexing them
+	sortedBefore := sortedPayloads(before)
+	sortedAfter := sortedPayloads(after)
+
+	var registers []RegisterChange
+	i, j := 0, 0
+	for i < len(sortedBefore) || j < len(sortedAfter) {
+		var cmp int
+		switch {
+		case i == len(sortedBefore):
+			cmp = 1
+		case j == len(sortedAfter):
+			cmp = -1
+		default:
+			cmp = compareKeys(sortedBefore[i].Key, sortedAfter[j].Key)
+		}
+
+		switch {
+		case cmp < 0:
+			p := sortedBefore[i]
+			registers = append(registers, registerChange(*p, RegisterRemoved, len(p.Value), 0))
+			i++
+		case cmp > 0:
+			p := sortedAfter[j]
+			registers = append(registers, registerChange(*p, RegisterAdded, 0, len(p.Value)))
+			j++
+		default:
+			p := sortedAfter[j]
+			if !sortedBefore[i].Value.Equals(p.Value) {
+				registers = append(registers, registerChange(*p, RegisterUpdated, len(sortedBefore[i].Value), len(p.Value)))
+			}
+			i++
+			j++
+		}
+	}
+
+	sort.Slice(registers, func(i, j int) bool {
+		if registers[i].Owner != registers[j].Owner {
+			return registers[i].Owner < registers[j].Owner
+		}
+		if registers[i].Controller != registers[j].Controller {
+			return registers[i].Controller < registers[j].Controller
+		}
+		return registers[i].Key < registers[j].Key
+	})
+
+	var accounts []AccountChange
+	for _, register := range registers {
+		if len(accounts) == 0 || accounts[len(accounts)-1].Owner != register.Owner {
+			accounts = append(accounts, AccountChange{Owner: register.Owner})
+		}
+		account := &accounts[len(accounts)-1]
+
+		switch register.Change {
+		case RegisterAdded:
+			account.RegistersAdded++
+		case RegisterRemoved:
+			account.RegistersRemoved++
+		case RegisterUpdated:
+			account.RegistersUpdated++
+		}
+		account.SizeDelta += int64(register.SizeAfter) - int64(register.SizeBefore)
+	}
+
+	return registers, accounts
+}
+
+func registerChange(p ledger.Payload, change string, sizeBefore int, sizeAfter int) RegisterChange {
+	registerChange := RegisterChange{
+		Change:     change,
+		SizeBefore: sizeBefore,
+		SizeAfter:  sizeAfter,
+	}
+
+	id, err := keyToRegisterID(p.Key)
+	if err != nil {
+		// not a register, report the canonical form of the key
+		registerChange.Key = p.Key.String()
+		return registerChange
+	}
+
+	registerChange.Owner = hex.EncodeToString([]byte(id.Owner))
+	registerChange.Controller = hex.EncodeToString([]byte(id.Controller))
+	registerChange.Key = id.Key
+	return registerChange
+}
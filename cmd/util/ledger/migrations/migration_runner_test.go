@@ -0,0 +1,272 @@
+package migrations_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	sdk "github.com/onflow/flow-go-sdk"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/cmd/util/ledger/migrations"
+	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/fvm"
+	state2 "github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestMigrationRunner(t *testing.T) {
+
+	address1 := flow.HexToAddress("01")
+	address2 := flow.HexToAddress("02")
+
+	payloads := func() []ledger.Payload {
+		return []ledger.Payload{
+			{Key: accountPayloadKey(address2, "a"), Value: []byte{1}},
+			{Key: accountPayloadKey(address1, "a"), Value: []byte{1}},
+			{Key: accountPayloadKey(address1, "b"), Value: []byte{}},
+			{Key: ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte("global"))}), Value: []byte{1}},
+		}
+	}
+
+	t.Run("migrations are applied in order and changes are reported", func(t *testing.T) {
+		var applied []string
+		record := func(name string) ledger.Migration {
+			return func(p []ledger.Payload) ([]ledger.Payload, error) {
+				applied = append(applied, name)
+				return p, nil
+			}
+		}
+
+		runner := &migrations.MigrationRunner{
+			Log: zerolog.Nop(),
+			Migrations: []migrations.NamedMigration{
+				{Name: "first", Migrate: record("first")},
+				{Name: "prune", Migrate: migrations.PruneMigration},
+				{Name: "second", Migrate: record("second")},
+				{Name: "update", MigrateAccount: func(address flow.Address, payloads []ledger.Payload) ([]ledger.Payload, error) {
+					if address != address2 {
+						return payloads, nil
+					}
+					return append(payloads, ledger.Payload{Key: accountPayloadKey(address, "c"), Value: []byte{1, 2, 3}}), nil
+				}},
+			},
+		}
+
+		migrated, report, err := runner.Run(payloads())
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"first", "second"}, applied)
+		assert.Len(t, migrated, 4)
+
+		require.Len(t, report.Migrations, 4)
+		assert.Equal(t, "prune", report.Migrations[1].Name)
+		assert.Equal(t, 4, report.Migrations[1].PayloadsBefore)
+		assert.Equal(t, 3, report.Migrations[1].PayloadsAfter)
+
+		assert.Equal(t, []migrations.RegisterChange{
+			{Owner: address1.Hex(), Controller: "", Key: "b", Change: migrations.RegisterRemoved},
+			{Owner: address2.Hex(), Controller: "", Key: "c", Change: migrations.RegisterAdded, SizeAfter: 3},
+		}, report.Registers)
+
+		assert.Equal(t, []migrations.AccountChange{
+			{Owner: address1.Hex(), RegistersRemoved: 1},
+			{Owner: address2.Hex(), RegistersAdded: 1, SizeDelta: 3},
+		}, report.Accounts)
+	})
+
+	t.Run("account migrations are deterministic", func(t *testing.T) {
+		migrate := func(workers int) []ledger.Payload {
+			runner := &migrations.MigrationRunner{
+				Log:     zerolog.Nop(),
+				Workers: workers,
+				Migrations: []migrations.NamedMigration{
+					{Name: "noop", MigrateAccount: func(_ flow.Address, payloads []ledger.Payload) ([]ledger.Payload, error) {
+						return payloads, nil
+					}},
+				},
+			}
+			migrated, err := runner.Migrate(payloads())
+			require.NoError(t, err)
+			return migrated
+		}
+
+		expected := migrate(1)
+		// payloads not owned by an account first, then accounts by address
+		assert.Equal(t, "global", string(expected[0].Key.KeyParts[0].Value))
+		assert.Equal(t, address1.Bytes(), expected[1].Key.KeyParts[0].Value)
+		assert.Equal(t, address2.Bytes(), expected[3].Key.KeyParts[0].Value)
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, expected, migrate(8))
+		}
+	})
+
+	t.Run("account migration errors are returned", func(t *testing.T) {
+		runner := &migrations.MigrationRunner{
+			Log: zerolog.Nop(),
+			Migrations: []migrations.NamedMigration{
+				{Name: "failing", MigrateAccount: func(address flow.Address, _ []ledger.Payload) ([]ledger.Payload, error) {
+					return nil, fmt.Errorf("cannot migrate %s", address)
+				}},
+			},
+		}
+		_, err := runner.Migrate(payloads())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), address1.Hex())
+	})
+
+	t.Run("invariant violations are reported", func(t *testing.T) {
+		dir := t.TempDir()
+
+		runner := &migrations.MigrationRunner{
+			Log:       zerolog.Nop(),
+			OutputDir: dir,
+			Migrations: []migrations.NamedMigration{
+				{Name: "prune", Migrate: migrations.PruneMigration},
+			},
+			Invariants: []migrations.Invariant{
+				payloadCountInvariant{},
+			},
+		}
+
+		_, report, err := runner.Run(payloads())
+		require.Error(t, err)
+
+		require.Len(t, report.Invariants, 1)
+		assert.True(t, report.Invariants[0].Violated)
+		assert.Equal(t, "4", report.Invariants[0].Before)
+		assert.Equal(t, "3", report.Invariants[0].After)
+
+		// the report is written anyway
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("invariants may be violated before migrating", func(t *testing.T) {
+		dir := t.TempDir()
+
+		storageUsedUpdate := &migrations.StorageUsedUpdateMigration{
+			Log:       zerolog.Nop(),
+			OutputDir: dir,
+		}
+
+		runner := &migrations.MigrationRunner{
+			Log: zerolog.Nop(),
+			Migrations: []migrations.NamedMigration{
+				{Name: "storage-used-update", Migrate: storageUsedUpdate.Migrate},
+			},
+			Invariants: []migrations.Invariant{
+				&migrations.StorageUsedInvariant{},
+			},
+		}
+
+		_, report, err := runner.Run([]ledger.Payload{
+			{Key: accountPayloadKey(address1, state2.KeyExists), Value: []byte{1}},
+			{Key: accountPayloadKey(address1, state2.KeyStorageUsed), Value: utils.Uint64ToBinary(1)},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, report.Invariants, 1)
+		assert.False(t, report.Invariants[0].Violated)
+		assert.NotEmpty(t, report.Invariants[0].BeforeError)
+		assert.Empty(t, report.Invariants[0].AfterError)
+	})
+}
+
+func TestStorageUsedInvariant(t *testing.T) {
+	address := flow.HexToAddress("01")
+	invariant := &migrations.StorageUsedInvariant{}
+
+	_, err := invariant.Check([]ledger.Payload{
+		{Key: accountPayloadKey(address, state2.KeyExists), Value: []byte{1}},
+		{Key: accountPayloadKey(address, state2.KeyStorageUsed), Value: utils.Uint64ToBinary(55)},
+	})
+	require.NoError(t, err)
+
+	_, err = invariant.Check([]ledger.Payload{
+		{Key: accountPayloadKey(address, state2.KeyExists), Value: []byte{1}},
+		{Key: accountPayloadKey(address, state2.KeyStorageUsed), Value: utils.Uint64ToBinary(54)},
+	})
+	require.Error(t, err)
+
+	_, err = invariant.Check([]ledger.Payload{
+		{Key: accountPayloadKey(address, state2.KeyExists), Value: []byte{1}},
+	})
+	require.Error(t, err)
+}
+
+func TestTotalSupplyInvariant(t *testing.T) {
+	chain := flow.Emulator.Chain()
+
+	led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	commit, err := bootstrap.NewBootstrapper(zerolog.Nop()).BootstrapLedger(
+		led,
+		unittest.ServiceAccountPublicKey,
+		chain,
+		fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+	)
+	require.NoError(t, err)
+
+	payloads, err := led.PayloadsAt(ledger.State(commit))
+	require.NoError(t, err)
+
+	invariant := &migrations.TotalSupplyInvariant{
+		Log:   zerolog.Nop(),
+		Chain: chain,
+	}
+
+	supply := uint64(unittest.GenesisTokenSupply)
+	summary, err := invariant.Check(payloads)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("total supply: %d, vault balances: %d", supply, supply), summary)
+
+	// dropping the vault of the service account loses its balance
+	runner := &migrations.MigrationRunner{
+		Log: zerolog.Nop(),
+		Migrations: []migrations.NamedMigration{
+			{Name: "drop-vault", MigrateAccount: func(address flow.Address, payloads []ledger.Payload) ([]ledger.Payload, error) {
+				if address != chain.ServiceAddress() {
+					return payloads, nil
+				}
+				kept := make([]ledger.Payload, 0, len(payloads))
+				for _, p := range payloads {
+					if string(p.Key.KeyParts[2].Value) != "storage\x1FflowTokenVault" {
+						kept = append(kept, p)
+					}
+				}
+				return kept, nil
+			}},
+		},
+		Invariants: []migrations.Invariant{invariant},
+	}
+
+	_, _, err = runner.Run(payloads)
+	require.Error(t, err)
+}
+
+// payloadCountInvariant is violated by migrations which add or remove payloads.
+type payloadCountInvariant struct{}
+
+func (payloadCountInvariant) Name() string {
+	return "payload-count"
+}
+
+func (payloadCountInvariant) Check(payloads []ledger.Payload) (string, error) {
+	return fmt.Sprintf("%d", len(payloads)), nil
+}
+
+func accountPayloadKey(address flow.Address, key string) ledger.Key {
+	return createAccountPayloadKey(sdk.Address(address), key)
+}
@@ -1,21 +1,20 @@
 package persister
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Persister can persist relevant information for hotstuff.
 type Persister struct {
-	db      *badger.DB
+	db      kv.DB
 	chainID flow.ChainID
 }
 
 // New creates a nev persister using the injected stores to persist
 // relevant hotstuff data.
-func New(db *badger.DB, chainID flow.ChainID) *Persister {
+func New(db kv.DB, chainID flow.ChainID) *Persister {
 	p := &Persister{
 		db:      db,
 		chainID: chainID,
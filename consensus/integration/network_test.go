@@ -71,6 +71,7 @@ func (n *Network) Register(channel network.Channel, engine network.Engine) (netw
 		cancel:  cancel,
 		net:     n,
 		channel: channel,
+		engine:  engine,
 		queue:   make(chan message, 1024),
 	}
 	go func() {
@@ -137,6 +138,39 @@ func (n *Network) unicast(event interface{}, channel network.Channel, targetID f
 	return nil
 }
 
+// request is called when the attached Engine to the channel is sending a request to the Engine attached to the
+// same channel on another node. The request is handled synchronously by the target Engine, after the delay
+// given by the filter of the hub.
+func (n *Network) request(ctx context.Context, event interface{}, channel network.Channel, targetID flow.Identifier) (interface{}, error) {
+	net, found := n.hub.networks[targetID]
+	if !found {
+		return nil, fmt.Errorf("could not find target network on hub: %x", targetID)
+	}
+	con, found := net.conduits[channel]
+	if !found {
+		return nil, fmt.Errorf("invalid channel (%s) for target ID (%x)", channel, targetID)
+	}
+	handler, ok := con.engine.(network.RequestHandler)
+	if !ok {
+		return nil, fmt.Errorf("engine of channel %s does not handle requests", channel)
+	}
+
+	sender, receiver := n.node, net.node
+	block, delay := n.hub.filter(channel, event, sender, receiver)
+	// the request is lost
+	if block {
+		return nil, network.NewPeerUnreachableError(fmt.Errorf("request blocked to %x", targetID))
+	}
+
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return handler.HandleRequest(channel, n.originID, event)
+}
+
 // publish is called when the attached Engine is sending an event to a group of Engines attached to the
 // same channel on other nodes based on selector.
 // In this test helper implementation, publish uses submit method under the hood.
@@ -157,6 +191,7 @@ type Conduit struct {
 	cancel  context.CancelFunc
 	net     *Network
 	channel network.Channel
+	engine  network.Engine
 	queue   chan message
 }
 
@@ -188,6 +223,13 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.net.multicast(event, c.channel, num, targetIDs...)
 }
 
+func (c *Conduit) Request(ctx context.Context, event interface{}, targetID flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit closed")
+	}
+	return c.net.request(ctx, event, c.channel, targetID)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit closed")
@@ -5,7 +5,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -39,6 +38,8 @@ import (
 	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/state/protocol/util"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -46,7 +47,7 @@ import (
 const hotstuffTimeout = 100 * time.Millisecond
 
 type Node struct {
-	db         *badger.DB
+	db         kv.DB
 	dbDir      string
 	index      int
 	log        zerolog.Logger
@@ -113,7 +114,8 @@ func createNode(
 	stopper *Stopper,
 ) *Node {
 
-	db, dbDir := unittest.TempBadgerDB(t)
+	badgerDB, dbDir := unittest.TempBadgerDB(t)
+	db := badgerkv.New(badgerDB)
 	metrics := metrics.NewNoopCollector()
 	tracer := trace.NewNoopTracer()
 
@@ -7,7 +7,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
 	entitiesproto "github.com/onflow/flow/protobuf/go/flow/entities"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -26,6 +25,7 @@ import (
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	factorymock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/engine/common/rpc/accounts"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
@@ -33,8 +33,10 @@ import (
 	module "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -94,9 +96,9 @@ func (suite *Suite) SetupTest() {
 }
 
 func (suite *Suite) RunTest(
-	f func(handler *access.Handler, db *badger.DB, blocks *storage.Blocks, headers *storage.Headers, results *storage.ExecutionResults),
+	f func(handler *access.Handler, db kv.DB, blocks *storage.Blocks, headers *storage.Headers, results *storage.ExecutionResults),
 ) {
-	unittest.RunWithBadgerDB(suite.T(), func(db *badger.DB) {
+	unittest.RunWithDB(suite.T(), func(db kv.DB) {
 		headers, _, _, _, _, blocks, _, _, _, results := util.StorageLayer(suite.T(), db)
 		transactions := storage.NewTransactions(suite.metrics, db)
 		collections := storage.NewCollections(db, transactions)
@@ -112,6 +114,8 @@ func (suite *Suite) RunTest(
 			transactions,
 			receipts,
 			results,
+			nil,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			nil,
@@ -129,7 +133,7 @@ func (suite *Suite) RunTest(
 }
 
 func (suite *Suite) TestSendAndGetTransaction() {
-	suite.RunTest(func(handler *access.Handler, _ *badger.DB, _ *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
+	suite.RunTest(func(handler *access.Handler, _ kv.DB, _ *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
 		referenceBlock := unittest.BlockHeaderFixture()
 		transaction := unittest.TransactionFixture()
 		transaction.SetReferenceBlockID(referenceBlock.ID())
@@ -182,7 +186,7 @@ func (suite *Suite) TestSendAndGetTransaction() {
 }
 
 func (suite *Suite) TestSendExpiredTransaction() {
-	suite.RunTest(func(handler *access.Handler, _ *badger.DB, _ *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
+	suite.RunTest(func(handler *access.Handler, _ kv.DB, _ *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
 		referenceBlock := unittest.BlockHeaderFixture()
 
 		// create latest block that is past the expiry window
@@ -224,7 +228,7 @@ func (mc *mockCloser) Close() error { return nil }
 // TestSendTransactionToRandomCollectionNode tests that collection nodes are chosen from the appropriate cluster when
 // forwarding transactions by sending two transactions bound for two different collection clusters.
 func (suite *Suite) TestSendTransactionToRandomCollectionNode() {
-	unittest.RunWithBadgerDB(suite.T(), func(db *badger.DB) {
+	unittest.RunWithDB(suite.T(), func(db kv.DB) {
 
 		// create a transaction
 		referenceBlock := unittest.BlockHeaderFixture()
@@ -288,6 +292,8 @@ func (suite *Suite) TestSendTransactionToRandomCollectionNode() {
 			transactions,
 			nil,
 			nil,
+			nil,
+			nil,
 			suite.chainID,
 			metrics,
 			connFactory, // passing in the connection factory
@@ -335,7 +341,7 @@ func (suite *Suite) TestSendTransactionToRandomCollectionNode() {
 }
 
 func (suite *Suite) TestGetBlockByIDAndHeight() {
-	suite.RunTest(func(handler *access.Handler, db *badger.DB, blocks *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
+	suite.RunTest(func(handler *access.Handler, db kv.DB, blocks *storage.Blocks, _ *storage.Headers, _ *storage.ExecutionResults) {
 
 		// test block1 get by ID
 		block1 := unittest.BlockFixture()
@@ -416,8 +422,85 @@ func (suite *Suite) TestGetBlockByIDAndHeight() {
 	})
 }
 
+// TestGetTransactionsByAddress tests that the transactions indexed by account are served page by page
+func (suite *Suite) TestGetTransactionsByAddress() {
+	unittest.RunWithDB(suite.T(), func(db kv.DB) {
+		accountTransactions := storage.NewAccountTransactions(db)
+
+		address := unittest.AddressFixture()
+		block := unittest.BlockFixture()
+		blockID := block.ID()
+		transactions := []realstorage.AccountTransaction{
+			{
+				Address:          address,
+				BlockID:          blockID,
+				BlockHeight:      block.Header.Height,
+				TransactionID:    unittest.IdentifierFixture(),
+				TransactionIndex: 0,
+				Roles:            realstorage.AccountRolePayer | realstorage.AccountRoleProposer,
+			},
+			{
+				Address:          address,
+				BlockID:          blockID,
+				BlockHeight:      block.Header.Height,
+				TransactionID:    unittest.IdentifierFixture(),
+				TransactionIndex: 1,
+				Roles:            realstorage.AccountRoleAuthorizer,
+			},
+		}
+		require.NoError(suite.T(), accountTransactions.Index(blockID, block.Header.Height, transactions))
+
+		backend := backend.New(
+			suite.state,
+			nil, nil, nil, nil, nil, nil, nil, nil,
+			accountTransactions,
+			nil,
+			suite.chainID,
+			suite.metrics,
+			nil,
+			false,
+			backend.DefaultMaxHeightRange,
+			nil,
+			nil,
+			suite.log,
+		)
+		handler := access.NewHandler(backend, suite.chainID.Chain())
+
+		req := &accounts.GetTransactionsByAddressRequest{
+			Address:     address.Bytes(),
+			StartHeight: block.Header.Height,
+			EndHeight:   block.Header.Height,
+			PageSize:    1,
+		}
+		for i, transaction := range transactions {
+			resp, err := handler.GetTransactionsByAddress(context.Background(), req)
+			require.NoError(suite.T(), err)
+			require.Len(suite.T(), resp.Transactions, 1)
+
+			actual := resp.Transactions[0]
+			assert.Equal(suite.T(), transaction.TransactionID[:], actual.TransactionId)
+			assert.Equal(suite.T(), blockID[:], actual.BlockId)
+			assert.Equal(suite.T(), block.Header.Height, actual.BlockHeight)
+			assert.Equal(suite.T(), transaction.TransactionIndex, actual.TransactionIndex)
+			assert.Equal(suite.T(), uint32(transaction.Roles), actual.Roles)
+
+			if i < len(transactions)-1 {
+				require.NotEmpty(suite.T(), resp.NextPageToken)
+			} else {
+				assert.Empty(suite.T(), resp.NextPageToken)
+			}
+			req.PageToken = resp.NextPageToken
+		}
+
+		// the address must be valid on the chain
+		req.Address = []byte{1, 2, 3}
+		_, err := handler.GetTransactionsByAddress(context.Background(), req)
+		require.Error(suite.T(), err)
+	})
+}
+
 func (suite *Suite) TestGetExecutionResultByBlockID() {
-	suite.RunTest(func(handler *access.Handler, db *badger.DB, blocks *storage.Blocks, _ *storage.Headers, executionResults *storage.ExecutionResults) {
+	suite.RunTest(func(handler *access.Handler, db kv.DB, blocks *storage.Blocks, _ *storage.Headers, executionResults *storage.ExecutionResults) {
 
 		// test block1 get by ID
 		nonexistingID := unittest.IdentifierFixture()
@@ -493,7 +576,7 @@ func (suite *Suite) TestGetExecutionResultByBlockID() {
 // TestGetSealedTransaction tests that transactions status of transaction that belongs to a sealed block
 // is reported as sealed
 func (suite *Suite) TestGetSealedTransaction() {
-	unittest.RunWithBadgerDB(suite.T(), func(db *badger.DB) {
+	unittest.RunWithDB(suite.T(), func(db kv.DB) {
 		headers, _, _, _, _, blocks, _, _, _, _ := util.StorageLayer(suite.T(), db)
 		results := storage.NewExecutionResults(suite.metrics, db)
 		receipts := storage.NewExecutionReceipts(suite.metrics, db, results, storage.DefaultCacheSize)
@@ -552,6 +635,8 @@ func (suite *Suite) TestGetSealedTransaction() {
 			transactions,
 			receipts,
 			results,
+			nil,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			connFactory,
@@ -565,11 +650,11 @@ func (suite *Suite) TestGetSealedTransaction() {
 		handler := access.NewHandler(backend, suite.chainID.Chain())
 
 		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, blocks, headers, collections, transactions,
-			receipts, results, suite.chainID, metrics, 0, 0, false, false, nil, nil)
+			receipts, results, nil, nil, suite.chainID, metrics, 0, 0, false, false, nil, nil)
 
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
-			transactions, results, receipts, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, rpcEng)
+			transactions, results, receipts, nil, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, rpcEng)
 		require.NoError(suite.T(), err)
 
 		// 1. Assume that follower engine updated the block storage and the protocol state. The block is reported as sealed
@@ -612,7 +697,7 @@ func (suite *Suite) TestGetSealedTransaction() {
 // TestExecuteScript tests the three execute Script related calls to make sure that the execution api is called with
 // the correct block id
 func (suite *Suite) TestExecuteScript() {
-	unittest.RunWithBadgerDB(suite.T(), func(db *badger.DB) {
+	unittest.RunWithDB(suite.T(), func(db kv.DB) {
 		headers, _, _, _, _, blocks, _, _, _, _ := util.StorageLayer(suite.T(), db)
 		transactions := storage.NewTransactions(suite.metrics, db)
 		collections := storage.NewCollections(db, transactions)
@@ -636,6 +721,8 @@ func (suite *Suite) TestExecuteScript() {
 			transactions,
 			receipts,
 			results,
+			nil,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			connFactory,
@@ -662,7 +749,7 @@ func (suite *Suite) TestExecuteScript() {
 			Once()
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
-			transactions, results, receipts, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, nil)
+			transactions, results, receipts, nil, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, nil)
 		require.NoError(suite.T(), err)
 
 		// create a block and a seal pointing to that block
@@ -58,6 +58,9 @@ type Engine struct {
 	executionReceipts storage.ExecutionReceipts
 	executionResults  storage.ExecutionResults
 
+	// index of the transactions by account, nil if the transactions are not indexed by account
+	accountTransactions storage.AccountTransactions
+
 	// metrics
 	transactionMetrics         module.TransactionMetrics
 	collectionsToMarkFinalized *stdmap.Times
@@ -80,6 +83,7 @@ func New(
 	transactions storage.Transactions,
 	executionResults storage.ExecutionResults,
 	executionReceipts storage.ExecutionReceipts,
+	accountTransactions storage.AccountTransactions,
 	transactionMetrics module.TransactionMetrics,
 	collectionsToMarkFinalized *stdmap.Times,
 	collectionsToMarkExecuted *stdmap.Times,
@@ -100,6 +104,7 @@ func New(
 		transactions:               transactions,
 		executionResults:           executionResults,
 		executionReceipts:          executionReceipts,
+		accountTransactions:        accountTransactions,
 		transactionMetrics:         transactionMetrics,
 		collectionsToMarkFinalized: collectionsToMarkFinalized,
 		collectionsToMarkExecuted:  collectionsToMarkExecuted,
@@ -562,6 +567,14 @@ func (e *Engine) updateLastFullBlockReceivedIndex() {
 
 	// if more contiguous blocks are now complete, update db
 	if latestFullHeight > lastFullHeight {
+		// index the transactions of the complete blocks before updating the last full height, so that no block is
+		// left unindexed if the node stops in between
+		err = e.indexAccountTransactions(lastFullHeight+1, latestFullHeight)
+		if err != nil {
+			logError(err)
+			return
+		}
+
 		err = e.blocks.UpdateLastFullBlockHeight(latestFullHeight)
 		if err != nil {
 			logError(err)
@@ -583,6 +596,33 @@ func (e *Engine) updateLastFullBlockReceivedIndex() {
 	e.log.Debug().Uint64("last_full_blk_height", latestFullHeight).Msg("updated LastFullBlockReceived index")
 }
 
+// indexAccountTransactions indexes the transactions of the complete blocks between the given heights (inclusive)
+// by the accounts they touch, if the node indexes the transactions by account
+func (e *Engine) indexAccountTransactions(startHeight, endHeight uint64) error {
+	if e.accountTransactions == nil {
+		return nil
+	}
+
+	for height := startHeight; height <= endHeight; height++ {
+		block, err := e.blocks.ByHeight(height)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve block by height %d: %w", height, err)
+		}
+
+		transactions, err := storage.BlockAccountTransactions(block, e.collections)
+		if err != nil {
+			return fmt.Errorf("failed to get the account transactions of block at height %d: %w", height, err)
+		}
+
+		err = e.accountTransactions.Index(block.ID(), height, transactions)
+		if err != nil {
+			return fmt.Errorf("failed to index the account transactions of block at height %d: %w", height, err)
+		}
+	}
+
+	return nil
+}
+
 // missingCollectionsAtHeight returns all missing collection guarantees at a given height
 func (e *Engine) missingCollectionsAtHeight(h uint64) ([]*flow.CollectionGuarantee, error) {
 	blk, err := e.blocks.ByHeight(h)
@@ -93,10 +93,10 @@ func (suite *Suite) SetupTest() {
 	require.NoError(suite.T(), err)
 
 	rpcEng := rpc.New(log, suite.proto.state, rpc.Config{}, nil, nil, suite.blocks, suite.headers, suite.collections,
-		suite.transactions, suite.receipts, suite.results, flow.Testnet, metrics.NewNoopCollector(), 0, 0, false, false, nil, nil)
+		suite.transactions, suite.receipts, suite.results, nil, nil, flow.Testnet, metrics.NewNoopCollector(), 0, 0, false, false, nil, nil)
 
 	eng, err := New(log, net, suite.proto.state, suite.me, suite.request, suite.blocks, suite.headers, suite.collections,
-		suite.transactions, suite.results, suite.receipts, metrics.NewNoopCollector(), collectionsToMarkFinalized, collectionsToMarkExecuted,
+		suite.transactions, suite.results, suite.receipts, nil, metrics.NewNoopCollector(), collectionsToMarkFinalized, collectionsToMarkExecuted,
 		blocksToMarkExecuted, rpcEng)
 	require.NoError(suite.T(), err)
 
@@ -415,13 +415,16 @@ func (suite *Suite) TestUpdateLastFullBlockReceivedIndex() {
 	blocks := make([]flow.Block, blkCnt)
 	heightMap := make(map[uint64]*flow.Block, blkCnt)
 	collMap := make(map[flow.Identifier]*flow.LightCollection, blkCnt*collPerBlk)
+	fullCollMap := make(map[flow.Identifier]*flow.Collection, blkCnt*collPerBlk)
 
 	// generate the test blocks, cgs and collections
 	for i := 0; i < blkCnt; i++ {
 		guarantees := make([]*flow.CollectionGuarantee, collPerBlk)
 		for j := 0; j < collPerBlk; j++ {
-			coll := unittest.CollectionFixture(2).Light()
+			fullColl := unittest.CollectionFixture(2)
+			coll := fullColl.Light()
 			collMap[coll.ID()] = &coll
+			fullCollMap[coll.ID()] = &fullColl
 			cg := unittest.CollectionGuaranteeFixture(func(cg *flow.CollectionGuarantee) {
 				cg.CollectionID = coll.ID()
 			})
@@ -518,6 +521,44 @@ func (suite *Suite) TestUpdateLastFullBlockReceivedIndex() {
 		suite.blocks.AssertExpectations(suite.T()) // not new call to UpdateLastFullBlockHeight should be made
 	})
 
+	suite.Run("transactions of new full blocks are indexed by account", func() {
+		rtnErr = nil
+		lastFullBlockHeight = rootBlkHeight
+
+		accountTransactions := new(storage.AccountTransactions)
+		suite.eng.accountTransactions = accountTransactions
+		defer func() {
+			suite.eng.accountTransactions = nil
+		}()
+
+		suite.collections.On("ByID", mock.IsType(flow.Identifier{})).Return(
+			func(cID flow.Identifier) *flow.Collection {
+				return fullCollMap[cID]
+			},
+			nil)
+		for i := 1; i < blkCnt; i++ {
+			block := blocks[i]
+			accountTransactions.On("Index", block.ID(), block.Header.Height, mock.Anything).
+				Run(func(args mock.Arguments) {
+					transactions := args.Get(2).([]storerr.AccountTransaction)
+					// the payer, proposer and authorizer of each fixture transaction are the same account
+					suite.Assert().Len(transactions, collPerBlk*2)
+					for _, transaction := range transactions {
+						suite.Assert().Equal(block.ID(), transaction.BlockID)
+						suite.Assert().True(transaction.Roles.Has(storerr.AccountRolePayer))
+					}
+				}).
+				Return(nil).
+				Once()
+		}
+		suite.blocks.On("UpdateLastFullBlockHeight", finalizedHeight).Return(nil).Once()
+
+		suite.eng.updateLastFullBlockReceivedIndex()
+
+		accountTransactions.AssertExpectations(suite.T())
+		suite.blocks.AssertExpectations(suite.T())
+	})
+
 	suite.Run("missing collections are requested", func() {
 		// root block is the last complete block
 		rtnErr = nil
@@ -16,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -61,6 +62,7 @@ type Backend struct {
 	collections       storage.Collections
 	executionReceipts storage.ExecutionReceipts
 	connFactory       ConnectionFactory
+	archives          history.Archives
 }
 
 func New(
@@ -73,6 +75,8 @@ func New(
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
 	executionResults storage.ExecutionResults,
+	accountTransactions storage.AccountTransactions,
+	historicalArchives history.Archives,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	connFactory ConnectionFactory,
@@ -110,6 +114,7 @@ func New(
 			retry:                retry,
 			connFactory:          connFactory,
 			previousAccessNodes:  historicalAccessNodes,
+			archives:             historicalArchives,
 			log:                  log,
 		},
 		backendEvents: backendEvents{
@@ -119,21 +124,25 @@ func New(
 			connFactory:       connFactory,
 			log:               log,
 			maxHeightRange:    maxHeightRange,
+			archives:          historicalArchives,
 		},
 		backendBlockHeaders: backendBlockHeaders{
-			headers: headers,
-			state:   state,
+			headers:  headers,
+			state:    state,
+			archives: historicalArchives,
 		},
 		backendBlockDetails: backendBlockDetails{
-			blocks: blocks,
-			state:  state,
+			blocks:   blocks,
+			state:    state,
+			archives: historicalArchives,
 		},
 		backendAccounts: backendAccounts{
-			state:             state,
-			headers:           headers,
-			executionReceipts: executionReceipts,
-			connFactory:       connFactory,
-			log:               log,
+			state:               state,
+			headers:             headers,
+			executionReceipts:   executionReceipts,
+			accountTransactions: accountTransactions,
+			connFactory:         connFactory,
+			log:                 log,
 		},
 		backendExecutionResults: backendExecutionResults{
 			executionResults: executionResults,
@@ -142,6 +151,7 @@ func New(
 		executionReceipts: executionReceipts,
 		connFactory:       connFactory,
 		chainID:           chainID,
+		archives:          historicalArchives,
 	}
 
 	retry.SetBackend(b)
@@ -206,6 +216,10 @@ func (b *Backend) Ping(ctx context.Context) error {
 func (b *Backend) GetCollectionByID(_ context.Context, colID flow.Identifier) (*flow.LightCollection, error) {
 	// retrieve the collection from the collection storage
 	col, err := b.collections.LightByID(colID)
+	if errors.Is(err, storage.ErrNotFound) {
+		// the collection may be part of the history exported to the archives
+		col, err = b.archivedCollection(colID)
+	}
 	if err != nil {
 		// Collections are retrieved asynchronously as we finalize blocks, so
 		// it is possible for a client to request a finalized block from us
@@ -218,6 +232,20 @@ func (b *Backend) GetCollectionByID(_ context.Context, colID flow.Identifier) (*
 	return col, nil
 }
 
+// archivedCollection returns the collection with the given ID from the historical archives.
+func (b *Backend) archivedCollection(colID flow.Identifier) (*flow.LightCollection, error) {
+	archived, err := b.archives.ByCollectionID(colID)
+	if err != nil {
+		return nil, err
+	}
+	col, ok := archived.Collection(colID)
+	if !ok {
+		return nil, fmt.Errorf("archived block %x does not include collection %x", archived.Block.ID(), colID)
+	}
+	light := col.Light()
+	return &light, nil
+}
+
 func (b *Backend) GetNetworkParameters(_ context.Context) access.NetworkParameters {
 	return access.NetworkParameters{
 		ChainID: b.chainID,
@@ -2,6 +2,8 @@ package backend
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -10,18 +12,32 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// DefaultAccountTransactionsPageSize is the number of transactions returned per page of the transactions of an
+// account if no page size is requested.
+const DefaultAccountTransactionsPageSize = 100
+
+// MaxAccountTransactionsPageSize is the maximum number of transactions returned per page of the transactions of an
+// account.
+const MaxAccountTransactionsPageSize = 1000
+
+// accountTransactionsPageTokenSize is the size of the page tokens of the transactions of an account, i.e. the
+// height, the block ID and the transaction index of the first transaction of the page.
+const accountTransactionsPageTokenSize = 8 + len(flow.ZeroID) + 4
+
 type backendAccounts struct {
-	state             protocol.State
-	headers           storage.Headers
-	executionReceipts storage.ExecutionReceipts
-	connFactory       ConnectionFactory
-	log               zerolog.Logger
+	state               protocol.State
+	headers             storage.Headers
+	executionReceipts   storage.ExecutionReceipts
+	accountTransactions storage.AccountTransactions // nil if the node does not index the transactions by account
+	connFactory         ConnectionFactory
+	log                 zerolog.Logger
 }
 
 func (b *backendAccounts) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
@@ -70,6 +86,88 @@ func (b *backendAccounts) GetAccountAtBlockHeight(
 	return account, nil
 }
 
+// GetTransactionsByAddress returns a page of the transactions touching the account as payer, proposer, authorizer
+// or register owner between the start and end heights (inclusive), read from the local index of the transactions by
+// account. The page starts from the page token if not empty, which is the next page token of a previous page.
+func (b *backendAccounts) GetTransactionsByAddress(
+	_ context.Context,
+	address flow.Address,
+	startHeight, endHeight uint64,
+	pageSize uint,
+	pageToken []byte,
+) (*access.AccountTransactionsPage, error) {
+
+	if b.accountTransactions == nil {
+		return nil, status.Error(codes.FailedPrecondition, "transactions are not indexed by account on this node")
+	}
+
+	if endHeight < startHeight {
+		return nil, status.Error(codes.InvalidArgument, "invalid start or end height")
+	}
+
+	if pageSize == 0 {
+		pageSize = DefaultAccountTransactionsPageSize
+	}
+	if pageSize > MaxAccountTransactionsPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "requested page size (%d) exceeded maximum (%d)", pageSize, MaxAccountTransactionsPageSize)
+	}
+
+	var cursor *storage.AccountTransactionCursor
+	if len(pageToken) > 0 {
+		var err error
+		cursor, err = decodeAccountTransactionsPageToken(pageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		if cursor.Height < startHeight || cursor.Height > endHeight {
+			return nil, status.Errorf(codes.InvalidArgument, "page token height %d is out of the requested height range", cursor.Height)
+		}
+	}
+
+	transactions, next, err := b.accountTransactions.ByAddress(address, startHeight, endHeight, cursor, pageSize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account transactions: %v", err)
+	}
+
+	page := &access.AccountTransactionsPage{
+		Transactions: make([]access.AccountTransaction, 0, len(transactions)),
+	}
+	for _, transaction := range transactions {
+		page.Transactions = append(page.Transactions, access.AccountTransaction{
+			TransactionID:    transaction.TransactionID,
+			BlockID:          transaction.BlockID,
+			BlockHeight:      transaction.BlockHeight,
+			TransactionIndex: transaction.TransactionIndex,
+			Roles:            transaction.Roles,
+		})
+	}
+	if next != nil {
+		page.NextPageToken = encodeAccountTransactionsPageToken(next)
+	}
+
+	return page, nil
+}
+
+func encodeAccountTransactionsPageToken(cursor *storage.AccountTransactionCursor) []byte {
+	token := make([]byte, accountTransactionsPageTokenSize)
+	binary.BigEndian.PutUint64(token, cursor.Height)
+	copy(token[8:], cursor.BlockID[:])
+	binary.BigEndian.PutUint32(token[accountTransactionsPageTokenSize-4:], cursor.TransactionIndex)
+	return token
+}
+
+func decodeAccountTransactionsPageToken(token []byte) (*storage.AccountTransactionCursor, error) {
+	if len(token) != accountTransactionsPageTokenSize {
+		return nil, fmt.Errorf("invalid page token size %d", len(token))
+	}
+	cursor := &storage.AccountTransactionCursor{
+		Height:           binary.BigEndian.Uint64(token),
+		TransactionIndex: binary.BigEndian.Uint32(token[accountTransactionsPageTokenSize-4:]),
+	}
+	copy(cursor.BlockID[:], token[8:])
+	return cursor, nil
+}
+
 func (b *backendAccounts) getAccountAtBlockID(
 	ctx context.Context,
 	address flow.Address,
@@ -2,15 +2,18 @@ package backend
 
 import (
 	"context"
+	"errors"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
 type backendBlockDetails struct {
-	blocks storage.Blocks
-	state  protocol.State
+	blocks   storage.Blocks
+	state    protocol.State
+	archives history.Archives
 }
 
 func (b *backendBlockDetails) GetLatestBlock(_ context.Context, isSealed bool) (*flow.Block, error) {
@@ -41,6 +44,9 @@ func (b *backendBlockDetails) GetLatestBlock(_ context.Context, isSealed bool) (
 
 func (b *backendBlockDetails) GetBlockByID(_ context.Context, id flow.Identifier) (*flow.Block, error) {
 	block, err := b.blocks.ByID(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		block, err = archivedBlock(b.archives.ByBlockID(id))
+	}
 	if err != nil {
 		err = convertStorageError(err)
 		return nil, err
@@ -51,6 +57,9 @@ func (b *backendBlockDetails) GetBlockByID(_ context.Context, id flow.Identifier
 
 func (b *backendBlockDetails) GetBlockByHeight(_ context.Context, height uint64) (*flow.Block, error) {
 	block, err := b.blocks.ByHeight(height)
+	if errors.Is(err, storage.ErrNotFound) {
+		block, err = archivedBlock(b.archives.ByHeight(height))
+	}
 	if err != nil {
 		err = convertStorageError(err)
 		return nil, err
@@ -58,3 +67,11 @@ func (b *backendBlockDetails) GetBlockByHeight(_ context.Context, height uint64)
 
 	return block, nil
 }
+
+// archivedBlock returns the block of the given lookup of the historical archives.
+func archivedBlock(archived *history.Block, err error) (*flow.Block, error) {
+	if err != nil {
+		return nil, err
+	}
+	return archived.Block, nil
+}
@@ -2,15 +2,18 @@ package backend
 
 import (
 	"context"
+	"errors"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
 type backendBlockHeaders struct {
-	headers storage.Headers
-	state   protocol.State
+	headers  storage.Headers
+	state    protocol.State
+	archives history.Archives
 }
 
 func (b *backendBlockHeaders) GetLatestBlockHeader(_ context.Context, isSealed bool) (*flow.Header, error) {
@@ -35,6 +38,9 @@ func (b *backendBlockHeaders) GetLatestBlockHeader(_ context.Context, isSealed b
 
 func (b *backendBlockHeaders) GetBlockHeaderByID(_ context.Context, id flow.Identifier) (*flow.Header, error) {
 	header, err := b.headers.ByBlockID(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		header, err = archivedHeader(b.archives.ByBlockID(id))
+	}
 	if err != nil {
 		err = convertStorageError(err)
 		return nil, err
@@ -45,6 +51,9 @@ func (b *backendBlockHeaders) GetBlockHeaderByID(_ context.Context, id flow.Iden
 
 func (b *backendBlockHeaders) GetBlockHeaderByHeight(_ context.Context, height uint64) (*flow.Header, error) {
 	header, err := b.headers.ByHeight(height)
+	if errors.Is(err, storage.ErrNotFound) {
+		header, err = archivedHeader(b.archives.ByHeight(height))
+	}
 	if err != nil {
 		err = convertStorageError(err)
 		return nil, err
@@ -52,3 +61,11 @@ func (b *backendBlockHeaders) GetBlockHeaderByHeight(_ context.Context, height u
 
 	return header, nil
 }
+
+// archivedHeader returns the header of the block of the given lookup of the historical archives.
+func archivedHeader(archived *history.Block, err error) (*flow.Header, error) {
+	if err != nil {
+		return nil, err
+	}
+	return archived.Block.Header, nil
+}
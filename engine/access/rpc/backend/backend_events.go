@@ -14,6 +14,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -25,6 +26,7 @@ type backendEvents struct {
 	connFactory       ConnectionFactory
 	log               zerolog.Logger
 	maxHeightRange    uint
+	archives          history.Archives
 }
 
 // GetEventsForHeightRange retrieves events for all sealed blocks between the start block height and
@@ -61,11 +63,23 @@ func (b *backendEvents) GetEventsForHeightRange(
 		endHeight = head.Height
 	}
 
-	// find the block headers for all the blocks between min and max height (inclusive)
+	// find the block headers for all the blocks between min and max height (inclusive), the blocks below the
+	// history held by the node are served from the historical archives
 	blockHeaders := make([]*flow.Header, 0)
+	archivedResults := make([]flow.BlockEvents, 0)
 
 	for i := startHeight; i <= endHeight; i++ {
 		header, err := b.headers.ByHeight(i)
+		if errors.Is(err, storage.ErrNotFound) && len(blockHeaders) == 0 {
+			archived, ok, err := b.archivedBlockEvents(i, eventType)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				archivedResults = append(archivedResults, archived)
+				continue
+			}
+		}
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
 		}
@@ -73,7 +87,46 @@ func (b *backendEvents) GetEventsForHeightRange(
 		blockHeaders = append(blockHeaders, header)
 	}
 
-	return b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	if len(blockHeaders) == 0 {
+		return archivedResults, nil
+	}
+
+	results, err := b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(archivedResults, results...), nil
+}
+
+// archivedBlockEvents returns the events of the given type emitted by the block at the given height, read from
+// the historical archives. It returns false if the archives do not hold the block.
+func (b *backendEvents) archivedBlockEvents(height uint64, eventType string) (flow.BlockEvents, bool, error) {
+	archived, err := b.archives.ByHeight(height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return flow.BlockEvents{}, false, nil
+	}
+	if err != nil {
+		return flow.BlockEvents{}, false, status.Errorf(codes.Internal, "failed to get events: %v", err)
+	}
+
+	header := archived.Block.Header
+	if archived.Seal == nil || archived.Result == nil || archived.Seal.ResultID != archived.Result.ID() {
+		return flow.BlockEvents{}, false, status.Errorf(codes.NotFound, "events of archived block %x at height %d are not available", header.ID(), height)
+	}
+
+	events := make([]flow.Event, 0)
+	for _, event := range archived.Events {
+		if event.Type == flow.EventType(eventType) {
+			events = append(events, event)
+		}
+	}
+	return flow.BlockEvents{
+		BlockID:        header.ID(),
+		BlockHeight:    header.Height,
+		BlockTimestamp: header.Timestamp,
+		Events:         events,
+	}, true, nil
 }
 
 // GetEventsForBlockIDs retrieves events for all the specified block IDs that have the given type
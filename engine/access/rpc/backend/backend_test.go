@@ -20,6 +20,7 @@ import (
 	access "github.com/onflow/flow-go/engine/access/mock"
 	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -87,7 +88,8 @@ func (suite *Suite) TestPing() {
 	backend := New(
 		suite.state,
 		suite.colClient,
-		nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -111,7 +113,8 @@ func (suite *Suite) TestGetLatestFinalizedBlockHeader() {
 
 	backend := New(
 		suite.state,
-		nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -143,7 +146,8 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot() {
 	backend := New(
 		suite.state,
 		nil, nil, nil, nil,
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -174,7 +178,8 @@ func (suite *Suite) TestGetLatestSealedBlockHeader() {
 	backend := New(
 		suite.state,
 		nil, nil, nil, nil,
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -212,7 +217,8 @@ func (suite *Suite) TestGetTransaction() {
 		suite.state,
 		nil, nil, nil, nil, nil,
 		suite.transactions,
-		nil, nil,
+		nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -248,6 +254,8 @@ func (suite *Suite) TestGetCollection() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -328,6 +336,8 @@ func (suite *Suite) TestTransactionStatusTransition() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
@@ -445,6 +455,8 @@ func (suite *Suite) TestTransactionExpiredStatusTransition() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -600,6 +612,8 @@ func (suite *Suite) TestTransactionPendingToFinalizedStatusTransition() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
@@ -656,6 +670,8 @@ func (suite *Suite) TestTransactionResultUnknown() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -695,7 +711,8 @@ func (suite *Suite) TestGetLatestFinalizedBlock() {
 		suite.state,
 		nil, nil,
 		suite.blocks,
-		nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -814,11 +831,12 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 		// create the handler
 		backend := New(
 			suite.state,
-			nil, nil,
-			nil,
+			nil, nil, nil,
 			suite.headers, nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -841,11 +859,12 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 		// create the handler
 		backend := New(
 			suite.state,
-			nil, nil,
-			nil,
+			nil, nil, nil,
 			suite.headers, nil, nil,
 			receipts,
 			nil,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -896,11 +915,12 @@ func (suite *Suite) TestGetExecutionResultByBlockID() {
 		// create the handler
 		backend := New(
 			suite.state,
-			nil, nil,
-			nil,
+			nil, nil, nil,
 			suite.headers, nil, nil,
 			suite.receipts,
 			results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -922,11 +942,12 @@ func (suite *Suite) TestGetExecutionResultByBlockID() {
 		// create the handler
 		backend := New(
 			suite.state,
-			nil, nil,
-			nil,
+			nil, nil, nil,
 			suite.headers, nil, nil,
 			nil,
 			results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1070,6 +1091,8 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil, nil, nil, suite.headers, nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory,
@@ -1105,6 +1128,8 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory,
@@ -1139,6 +1164,8 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory,
@@ -1156,7 +1183,6 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 		suite.Require().Equal(expectedResp, actualResp)
 	})
 
-	// set max height range to 1 and request range of 2
 	suite.Run("invalid request exceeding max height range", func() {
 		headHeight = maxHeight - 1
 		setupHeadHeight(headHeight)
@@ -1172,6 +1198,8 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory,
@@ -1205,6 +1233,8 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil, nil,
 			suite.receipts,
 			suite.results,
+			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory,
@@ -1276,6 +1306,8 @@ func (suite *Suite) TestGetAccount() {
 		nil, nil,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
@@ -1298,6 +1330,74 @@ func (suite *Suite) TestGetAccount() {
 	})
 }
 
+// TestSimulateTransaction tests that the transaction is simulated on an execution node which executed the latest
+// sealed block
+func (suite *Suite) TestSimulateTransaction() {
+	ctx := context.Background()
+
+	// setup the latest sealed block, which is also the reference block of the transaction
+	block := unittest.BlockFixture()
+	header := block.Header
+	blockID := header.ID()
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("AtBlockID", blockID).Return(suite.snapshot, nil).Maybe()
+	suite.snapshot.On("Head").Return(header, nil)
+
+	tx := unittest.TransactionBodyFixture(unittest.WithReferenceBlock(blockID))
+
+	receipts, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	// setup the simulation client mock
+	simulationClient := new(access.TransactionSimulationAPIClient)
+	simulationClient.
+		On("SimulateTransaction", ctx, mock.Anything).
+		Return(&simulation.SimulateTransactionResponse{
+			StatusCode:      1,
+			ErrorMessage:    "failed",
+			ComputationUsed: 10,
+			BlockId:         blockID[:],
+		}, nil).
+		Once()
+
+	// create a mock connection factory
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetTransactionSimulationAPIClient", mock.Anything).Return(simulationClient, &mockCloser{}, nil)
+
+	backend := New(
+		suite.state,
+		nil, nil, nil,
+		suite.headers,
+		nil, nil,
+		suite.receipts,
+		suite.results,
+		nil,
+		nil,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+	)
+
+	preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+
+	result, err := backend.SimulateTransaction(ctx, &tx)
+	suite.checkResponse(result, err)
+
+	suite.Require().Equal(tx.ID(), result.TransactionID)
+	suite.Require().Equal(blockID, result.BlockID)
+	suite.Require().Equal(uint(1), result.StatusCode)
+	suite.Require().Equal("failed", result.ErrorMessage)
+	suite.Require().Equal(uint64(10), result.ComputationUsed)
+
+	simulationClient.AssertExpectations(suite.T())
+	suite.assertAllExpectations()
+}
+
 func (suite *Suite) TestGetAccountAtBlockHeight() {
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
 
@@ -1351,6 +1451,8 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 		nil, nil,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		flow.Testnet,
 		metrics.NewNoopCollector(),
 		connFactory,
@@ -1373,6 +1475,118 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 	})
 }
 
+func (suite *Suite) TestGetTransactionsByAddress() {
+	ctx := context.Background()
+	address := unittest.AddressFixture()
+	startHeight := uint64(10)
+	endHeight := uint64(20)
+
+	blockID := unittest.IdentifierFixture()
+	transactions := []storage.AccountTransaction{
+		{
+			Address:          address,
+			BlockID:          blockID,
+			BlockHeight:      startHeight,
+			TransactionID:    unittest.IdentifierFixture(),
+			TransactionIndex: 0,
+			Roles:            storage.AccountRolePayer | storage.AccountRoleProposer,
+		},
+		{
+			Address:          address,
+			BlockID:          blockID,
+			BlockHeight:      startHeight,
+			TransactionID:    unittest.IdentifierFixture(),
+			TransactionIndex: 1,
+			Roles:            storage.AccountRoleAuthorizer,
+		},
+	}
+	next := &storage.AccountTransactionCursor{
+		Height:           startHeight + 1,
+		BlockID:          unittest.IdentifierFixture(),
+		TransactionIndex: 3,
+	}
+
+	accountTransactions := new(storagemock.AccountTransactions)
+	accountTransactions.
+		On("ByAddress", address, startHeight, endHeight, (*storage.AccountTransactionCursor)(nil), uint(len(transactions))).
+		Return(transactions, next, nil).
+		Once()
+	accountTransactions.
+		On("ByAddress", address, startHeight, endHeight, next, uint(DefaultAccountTransactionsPageSize)).
+		Return([]storage.AccountTransaction{}, nil, nil).
+		Once()
+
+	backend := New(
+		suite.state,
+		nil, nil, nil, nil, nil, nil, nil, nil, accountTransactions,
+		nil,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+	)
+
+	suite.Run("pages are served from the local index", func() {
+		page, err := backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, uint(len(transactions)), nil)
+		suite.Require().NoError(err)
+		suite.Require().Len(page.Transactions, len(transactions))
+		for i, transaction := range page.Transactions {
+			suite.Assert().Equal(transactions[i].TransactionID, transaction.TransactionID)
+			suite.Assert().Equal(transactions[i].BlockID, transaction.BlockID)
+			suite.Assert().Equal(transactions[i].BlockHeight, transaction.BlockHeight)
+			suite.Assert().Equal(transactions[i].TransactionIndex, transaction.TransactionIndex)
+			suite.Assert().Equal(transactions[i].Roles, transaction.Roles)
+		}
+		suite.Require().NotEmpty(page.NextPageToken)
+
+		// the next page starts from the cursor returned with the first page
+		page, err = backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, 0, page.NextPageToken)
+		suite.Require().NoError(err)
+		suite.Assert().Empty(page.Transactions)
+		suite.Assert().Nil(page.NextPageToken)
+
+		accountTransactions.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid requests are rejected", func() {
+		_, err := backend.GetTransactionsByAddress(ctx, address, endHeight, startHeight, 0, nil)
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, MaxAccountTransactionsPageSize+1, nil)
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, 0, []byte{1, 2, 3})
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+
+		outOfRange := encodeAccountTransactionsPageToken(&storage.AccountTransactionCursor{Height: endHeight + 1})
+		_, err = backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, 0, outOfRange)
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("transactions are not served if not indexed", func() {
+		backend := New(
+			suite.state,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil,
+			nil,
+			flow.Testnet,
+			metrics.NewNoopCollector(),
+			nil,
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			nil,
+			suite.log,
+		)
+
+		_, err := backend.GetTransactionsByAddress(ctx, address, startHeight, endHeight, 0, nil)
+		suite.Assert().Equal(codes.FailedPrecondition, status.Code(err))
+	})
+}
+
 func (suite *Suite) TestGetNetworkParameters() {
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
 
@@ -1380,7 +1594,8 @@ func (suite *Suite) TestGetNetworkParameters() {
 
 	backend := New(
 		nil, nil, nil, nil, nil, nil, nil,
-		nil, nil,
+		nil, nil, nil,
+		nil,
 		flow.Mainnet,
 		metrics.NewNoopCollector(),
 		nil,
@@ -16,8 +16,10 @@ import (
 
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -38,6 +40,7 @@ type backendTransactions struct {
 	connFactory          ConnectionFactory
 
 	previousAccessNodes []accessproto.AccessAPIClient
+	archives            history.Archives
 	log                 zerolog.Logger
 }
 
@@ -75,6 +78,44 @@ func (b *backendTransactions) SendTransaction(
 	return nil
 }
 
+// SimulateTransaction runs the transaction against the latest sealed state without committing it, on one of the
+// execution nodes which executed the latest sealed block.
+func (b *backendTransactions) SimulateTransaction(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+) (*flow.TransactionSimulationResult, error) {
+	err := b.transactionValidator.Validate(tx)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction: %s", err.Error())
+	}
+
+	sealed, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed block: %v", err)
+	}
+
+	execNodes, err := executionNodesForBlockID(ctx, sealed.ID(), b.executionReceipts, b.state, b.log)
+	if err != nil {
+		// if no execution receipt were found, return a NotFound GRPC error
+		if errors.As(err, &InsufficientExecutionReceipts{}) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to find execution nodes to simulate the transaction: %v", err)
+	}
+
+	req := &simulation.SimulateTransactionRequest{
+		Transaction: convert.TransactionToMessage(*tx),
+	}
+	resp, err := b.simulateTransactionOnAnyExeNode(ctx, execNodes, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to simulate transaction on the execution nodes: %v", err)
+	}
+
+	result := convert.MessageToTransactionSimulationResult(resp)
+	result.TransactionID = tx.ID()
+	return result, nil
+}
+
 // trySendTransaction tries to transaction to a collection node
 func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.TransactionBody) error {
 
@@ -376,6 +417,16 @@ func (b *backendTransactions) getHistoricalTransaction(
 	ctx context.Context,
 	txID flow.Identifier,
 ) (*flow.TransactionBody, error) {
+	archived, err := b.archives.ByTransactionID(txID)
+	if err == nil {
+		tx, ok := archived.Transaction(txID)
+		if ok {
+			return tx, nil
+		}
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return nil, convertStorageError(err)
+	}
+
 	for _, historicalNode := range b.previousAccessNodes {
 		txResp, err := historicalNode.GetTransaction(ctx, &accessproto.GetTransactionRequest{Id: txID[:]})
 		if err == nil {
@@ -396,6 +447,14 @@ func (b *backendTransactions) getHistoricalTransactionResult(
 	ctx context.Context,
 	txID flow.Identifier,
 ) (*access.TransactionResult, error) {
+	archived, err := b.archives.ByTransactionID(txID)
+	if err == nil {
+		return archivedTransactionResult(archived, txID), nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, convertStorageError(err)
+	}
+
 	for _, historicalNode := range b.previousAccessNodes {
 		result, err := historicalNode.GetTransactionResult(ctx, &accessproto.GetTransactionRequest{Id: txID[:]})
 		if err == nil {
@@ -420,6 +479,29 @@ func (b *backendTransactions) getHistoricalTransactionResult(
 	return nil, status.Errorf(codes.NotFound, "no known transaction with ID %s", txID)
 }
 
+// archivedTransactionResult returns the result of the transaction with the given ID included in the given block
+// read from the historical archives. The archived blocks are finalized, and the transaction is reported sealed
+// only if the block was archived with its seal and the sealed execution result.
+func archivedTransactionResult(archived *history.Block, txID flow.Identifier) *access.TransactionResult {
+	result := &access.TransactionResult{
+		Status:  flow.TransactionStatusFinalized,
+		BlockID: archived.Block.ID(),
+	}
+	if archived.Seal == nil || archived.Result == nil || archived.Seal.ResultID != archived.Result.ID() {
+		return result
+	}
+
+	txResult, ok := archived.TransactionResult(txID)
+	if !ok {
+		return result
+	}
+	result.Status = flow.TransactionStatusSealed
+	result.StatusCode = txResult.StatusCode()
+	result.ErrorMessage = txResult.ErrorMessage
+	result.Events = archived.TransactionEvents(txID)
+	return result
+}
+
 func (b *backendTransactions) registerTransactionForRetry(tx *flow.TransactionBody) {
 	referenceBlock, err := b.state.AtBlockID(tx.ReferenceBlockID).Head()
 	if err != nil {
@@ -500,3 +582,33 @@ func (b *backendTransactions) tryGetTransactionResult(ctx context.Context, execN
 	}
 	return resp, nil
 }
+
+func (b *backendTransactions) simulateTransactionOnAnyExeNode(ctx context.Context, execNodes flow.IdentityList, req *simulation.SimulateTransactionRequest) (*simulation.SimulateTransactionResponse, error) {
+	var errors *multierror.Error
+	// try to simulate the transaction on one of the execution nodes
+	for _, execNode := range execNodes {
+		resp, err := b.trySimulateTransaction(ctx, execNode, req)
+		if err == nil {
+			b.log.Debug().
+				Str("execution_node", execNode.String()).
+				Hex("block_id", resp.GetBlockId()).
+				Msg("Successfully simulated transaction on any node")
+			return resp, nil
+		}
+		errors = multierror.Append(errors, err)
+	}
+	return nil, errors.ErrorOrNil()
+}
+
+func (b *backendTransactions) trySimulateTransaction(ctx context.Context, execNode *flow.Identity, req *simulation.SimulateTransactionRequest) (*simulation.SimulateTransactionResponse, error) {
+	simulationRPCClient, closer, err := b.connFactory.GetTransactionSimulationAPIClient(execNode.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	resp, err := simulationRPCClient.SimulateTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
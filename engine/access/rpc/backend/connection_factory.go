@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 	"google.golang.org/grpc"
 
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/utils/grpcutils"
 )
 
@@ -21,6 +22,7 @@ const defaultClientTimeout = 3 * time.Second
 type ConnectionFactory interface {
 	GetAccessAPIClient(address string) (access.AccessAPIClient, io.Closer, error)
 	GetExecutionAPIClient(address string) (execution.ExecutionAPIClient, io.Closer, error)
+	GetTransactionSimulationAPIClient(address string) (simulation.TransactionSimulationAPIClient, io.Closer, error)
 }
 
 type ProxyConnectionFactory struct {
@@ -36,6 +38,10 @@ func (p *ProxyConnectionFactory) GetExecutionAPIClient(address string) (executio
 	return p.ConnectionFactory.GetExecutionAPIClient(p.targetAddress)
 }
 
+func (p *ProxyConnectionFactory) GetTransactionSimulationAPIClient(address string) (simulation.TransactionSimulationAPIClient, io.Closer, error) {
+	return p.ConnectionFactory.GetTransactionSimulationAPIClient(p.targetAddress)
+}
+
 type ConnectionFactoryImpl struct {
 	CollectionGRPCPort        uint
 	ExecutionGRPCPort         uint
@@ -92,6 +98,24 @@ func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (executio
 	return executionAPIClient, closer, nil
 }
 
+// GetTransactionSimulationAPIClient returns a client of the transaction simulation API served by the execution nodes
+// on their gRPC port.
+func (cf *ConnectionFactoryImpl) GetTransactionSimulationAPIClient(address string) (simulation.TransactionSimulationAPIClient, io.Closer, error) {
+
+	grpcAddress, err := getGRPCAddress(address, cf.ExecutionGRPCPort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := cf.createConnection(grpcAddress, cf.ExecutionNodeGRPCTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	simulationAPIClient := simulation.NewTransactionSimulationAPIClient(conn)
+	closer := io.Closer(conn)
+	return simulationAPIClient, closer, nil
+}
+
 // getExecutionNodeAddress translates flow.Identity address to the GRPC address of the node by switching the port to the
 // GRPC port from the libp2p port
 func getGRPCAddress(address string, grpcPort uint) (string, error) {
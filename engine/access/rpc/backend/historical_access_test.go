@@ -8,10 +8,13 @@ import (
 
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
 	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -47,6 +50,8 @@ func (suite *Suite) TestHistoricalTransactionResult() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -104,6 +109,8 @@ func (suite *Suite) TestHistoricalTransaction() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -126,3 +133,147 @@ func (suite *Suite) TestHistoricalTransaction() {
 
 	suite.assertAllExpectations()
 }
+
+// TestHistoricalArchives tests that the transactions, blocks, collections and events missing from the storage are
+// retrieved from the historical archives
+func (suite *Suite) TestHistoricalArchives() {
+
+	ctx := context.Background()
+	collection := unittest.CollectionFixture(2)
+	guarantee := unittest.CollectionGuaranteeFixture(func(guarantee *flow.CollectionGuarantee) {
+		guarantee.CollectionID = collection.ID()
+	})
+	block := unittest.BlockFixture()
+	block.SetPayload(unittest.PayloadFixture(unittest.WithGuarantees(guarantee)))
+	blockID := block.ID()
+
+	failedTx := collection.Transactions[0]
+	event := unittest.EventFixture(flow.EventAccountCreated, 0, 0, failedTx.ID(), 10)
+	result := unittest.ExecutionResultFixture(unittest.WithBlock(&block))
+	archived := &history.Block{
+		Block:       &block,
+		Collections: []*flow.Collection{&collection},
+		Seal:        unittest.Seal.Fixture(unittest.Seal.WithResult(result)),
+		Result:      result,
+		TransactionResults: []flow.TransactionResult{
+			{TransactionID: failedTx.ID(), ErrorMessage: "failed"},
+			{TransactionID: collection.Transactions[1].ID()},
+		},
+		Events: []flow.Event{event, unittest.EventFixture(flow.EventAccountUpdated, 0, 1, failedTx.ID(), 10)},
+	}
+
+	// the next block was archived before it was sealed
+	unsealedCollection := unittest.CollectionFixture(1)
+	unsealedBlock := unittest.BlockWithParentFixture(block.Header)
+	unsealedBlock.SetPayload(unittest.PayloadFixture(unittest.WithGuarantees(unittest.CollectionGuaranteeFixture(func(guarantee *flow.CollectionGuarantee) {
+		guarantee.CollectionID = unsealedCollection.ID()
+	}))))
+	unsealed := &history.Block{
+		Block:       &unsealedBlock,
+		Collections: []*flow.Collection{&unsealedCollection},
+	}
+
+	unittest.RunWithTempDir(suite.T(), func(dir string) {
+		writer, err := history.OpenWriter(dir)
+		suite.Require().NoError(err)
+		suite.Require().NoError(writer.Append(archived))
+		suite.Require().NoError(writer.Append(unsealed))
+		suite.Require().NoError(writer.Close())
+
+		archives, err := history.OpenAll([]string{dir})
+		suite.Require().NoError(err)
+		defer archives.Close()
+
+		// none of the data is found in the storage
+		suite.transactions.On("ByID", mock.Anything).Return(nil, storage.ErrNotFound)
+		suite.blocks.On("ByID", mock.Anything).Return(nil, storage.ErrNotFound)
+		suite.blocks.On("ByHeight", mock.Anything).Return(nil, storage.ErrNotFound)
+		suite.headers.On("ByHeight", mock.Anything).Return(nil, storage.ErrNotFound)
+		suite.collections.On("LightByID", mock.Anything).Return(nil, storage.ErrNotFound)
+		suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+		sealedHead := unittest.BlockHeaderWithParentFixture(unsealedBlock.Header)
+		suite.snapshot.On("Head").Return(&sealedHead, nil)
+
+		backend := New(
+			suite.state,
+			nil,
+			nil,
+			suite.blocks,
+			suite.headers,
+			suite.collections,
+			suite.transactions,
+			suite.receipts,
+			suite.results,
+			nil,
+			archives,
+			suite.chainID,
+			metrics.NewNoopCollector(),
+			nil,
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			nil,
+			suite.log,
+		)
+
+		tx, err := backend.GetTransaction(ctx, failedTx.ID())
+		suite.checkResponse(tx, err)
+		suite.Assert().Equal(failedTx.ID(), tx.ID())
+
+		txResult, err := backend.GetTransactionResult(ctx, failedTx.ID())
+		suite.checkResponse(txResult, err)
+		suite.Assert().Equal(flow.TransactionStatusSealed, txResult.Status)
+		suite.Assert().Equal(uint(1), txResult.StatusCode)
+		suite.Assert().Equal("failed", txResult.ErrorMessage)
+		suite.Assert().Equal(archived.Events, txResult.Events)
+		suite.Assert().Equal(blockID, txResult.BlockID)
+
+		txResult, err = backend.GetTransactionResult(ctx, collection.Transactions[1].ID())
+		suite.checkResponse(txResult, err)
+		suite.Assert().Equal(flow.TransactionStatusSealed, txResult.Status)
+		suite.Assert().Equal(uint(0), txResult.StatusCode)
+
+		// the transactions of the blocks archived before they were sealed are only finalized
+		txResult, err = backend.GetTransactionResult(ctx, unsealedCollection.Transactions[0].ID())
+		suite.checkResponse(txResult, err)
+		suite.Assert().Equal(flow.TransactionStatusFinalized, txResult.Status)
+		suite.Assert().Equal(unsealedBlock.ID(), txResult.BlockID)
+		suite.Assert().Empty(txResult.Events)
+
+		blockEvents, err := backend.GetEventsForHeightRange(ctx, string(flow.EventAccountCreated), block.Header.Height, block.Header.Height)
+		suite.checkResponse(blockEvents, err)
+		suite.Require().Len(blockEvents, 1)
+		suite.Assert().Equal(blockID, blockEvents[0].BlockID)
+		suite.Assert().Equal([]flow.Event{event}, blockEvents[0].Events)
+
+		// the events of the blocks archived before they were sealed are not available
+		_, err = backend.GetEventsForHeightRange(ctx, string(flow.EventAccountCreated), block.Header.Height, unsealedBlock.Header.Height)
+		suite.Require().Error(err)
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+
+		// transactions missing from the archives are unknown
+		txResult, err = backend.GetTransactionResult(ctx, unittest.IdentifierFixture())
+		suite.checkResponse(txResult, err)
+		suite.Assert().Equal(flow.TransactionStatusUnknown, txResult.Status)
+
+		actualBlock, err := backend.GetBlockByID(ctx, blockID)
+		suite.checkResponse(actualBlock, err)
+		suite.Assert().Equal(blockID, actualBlock.ID())
+
+		actualBlock, err = backend.GetBlockByHeight(ctx, block.Header.Height)
+		suite.checkResponse(actualBlock, err)
+		suite.Assert().Equal(blockID, actualBlock.ID())
+
+		header, err := backend.GetBlockHeaderByHeight(ctx, block.Header.Height)
+		suite.checkResponse(header, err)
+		suite.Assert().Equal(blockID, header.ID())
+
+		_, err = backend.GetBlockHeaderByHeight(ctx, unsealedBlock.Header.Height+1)
+		suite.Require().Error(err)
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+
+		light, err := backend.GetCollectionByID(ctx, collection.ID())
+		suite.checkResponse(light, err)
+		suite.Assert().Equal(collection.Light(), *light)
+	})
+}
@@ -41,7 +41,7 @@ func (suite *Suite) TestTransactionRetry() {
 	// blockID := block.ID()
 	// Setup Handler + Retry
 	backend := New(suite.state, suite.colClient, nil, suite.blocks, suite.headers,
-		suite.collections, suite.transactions, suite.receipts, suite.results, suite.chainID, metrics.NewNoopCollector(), nil,
+		suite.collections, suite.transactions, suite.receipts, suite.results, nil, nil, suite.chainID, metrics.NewNoopCollector(), nil,
 		false, DefaultMaxHeightRange, nil, nil, suite.log)
 	retry := newRetry().SetBackend(backend).Activate()
 	backend.retry = retry
@@ -104,7 +104,7 @@ func (suite *Suite) TestSuccessfulTransactionsDontRetry() {
 
 	// Setup Handler + Retry
 	backend := New(suite.state, suite.colClient, nil, suite.blocks, suite.headers,
-		suite.collections, suite.transactions, suite.receipts, suite.results, suite.chainID, metrics.NewNoopCollector(), connFactory,
+		suite.collections, suite.transactions, suite.receipts, suite.results, nil, nil, suite.chainID, metrics.NewNoopCollector(), connFactory,
 		false, DefaultMaxHeightRange, nil, nil, suite.log)
 	retry := newRetry().SetBackend(backend).Activate()
 	backend.retry = retry
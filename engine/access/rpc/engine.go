@@ -19,8 +19,11 @@ import (
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/common/rpc/accounts"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/history"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/grpcutils"
@@ -71,6 +74,8 @@ func New(log zerolog.Logger,
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
 	executionResults storage.ExecutionResults,
+	accountTransactions storage.AccountTransactions,
+	historicalArchives history.Archives,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	collectionGRPCPort uint,
@@ -142,6 +147,8 @@ func New(log zerolog.Logger,
 		transactions,
 		executionReceipts,
 		executionResults,
+		accountTransactions,
+		historicalArchives,
 		chainID,
 		transactionMetrics,
 		connectionFactory,
@@ -172,6 +179,26 @@ func New(log zerolog.Logger,
 		access.NewHandler(backend, chainID.Chain()),
 	)
 
+	simulation.RegisterTransactionSimulationAPIServer(
+		eng.unsecureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
+	simulation.RegisterTransactionSimulationAPIServer(
+		eng.secureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
+	accounts.RegisterAccountTransactionsAPIServer(
+		eng.unsecureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
+	accounts.RegisterAccountTransactionsAPIServer(
+		eng.secureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
 	if rpcMetricsEnabled {
 		// Not interested in legacy metrics, so initialize here
 		grpc_prometheus.EnableHandlingTimeHistogram()
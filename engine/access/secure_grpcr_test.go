@@ -102,7 +102,7 @@ func (suite *SecureGRPCTestSuite) SetupTest() {
 	suite.publicKey = networkingKey.PublicKey()
 
 	suite.rpcEng = rpc.New(suite.log, suite.state, config, suite.collClient, nil, suite.blocks, suite.headers, suite.collections, suite.transactions,
-		nil, nil, suite.chainID, suite.metrics, 0, 0, false, false, nil, nil)
+		nil, nil, nil, nil, suite.chainID, suite.metrics, 0, 0, false, false, nil, nil)
 	unittest.AssertClosesBefore(suite.T(), suite.rpcEng.Ready(), 2*time.Second)
 
 	// wait for the server to startup
@@ -0,0 +1,11 @@
+package wrapper
+
+import (
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
+)
+
+// TransactionSimulationAPIClient allows for generation of a mock (via mockery) for the TransactionSimulationAPIClient
+// generated from the simulation protobuf
+type TransactionSimulationAPIClient interface {
+	simulation.TransactionSimulationAPIClient
+}
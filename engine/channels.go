@@ -211,14 +211,15 @@ func initializeChannelRoleMap() {
 
 // ClusterChannelRoles returns the list of roles that are involved in the given cluster-based channel.
 func ClusterChannelRoles(clusterChannel network.Channel) flow.RoleList {
-	if prefix, ok := clusterChannelPrefix(clusterChannel); ok {
+	if prefix, ok := ClusterChannelPrefix(clusterChannel); ok {
 		return clusterChannelPrefixRoleMap[prefix]
 	}
 
 	return flow.RoleList{}
 }
 
-func clusterChannelPrefix(clusterChannel network.Channel) (string, bool) {
+// ClusterChannelPrefix returns the prefix of a cluster-based channel, i.e. the channel without its cluster ID.
+func ClusterChannelPrefix(clusterChannel network.Channel) (string, bool) {
 	for prefix := range clusterChannelPrefixRoleMap {
 		if strings.HasPrefix(clusterChannel.String(), prefix) {
 			return prefix, true
@@ -231,7 +232,7 @@ func clusterChannelPrefix(clusterChannel network.Channel) (string, bool) {
 // IsClusterChannel returns true if channel is cluster-based.
 // Currently, only collection nodes are involved in a cluster-based channels.
 func IsClusterChannel(channel network.Channel) bool {
-	_, ok := clusterChannelPrefix(channel)
+	_, ok := ClusterChannelPrefix(channel)
 	return ok
 }
 
@@ -1,18 +1,17 @@
 package factories
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/module"
 	builder "github.com/onflow/flow-go/module/builder/collection"
 	finalizer "github.com/onflow/flow-go/module/finalizer/collection"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type BuilderFactory struct {
-	db               *badger.DB
+	db               kv.DB
 	mainChainHeaders storage.Headers
 	trace            module.Tracer
 	opts             []builder.Opt
@@ -21,7 +20,7 @@ type BuilderFactory struct {
 }
 
 func NewBuilderFactory(
-	db *badger.DB,
+	db kv.DB,
 	mainChainHeaders storage.Headers,
 	trace module.Tracer,
 	metrics module.CollectionMetrics,
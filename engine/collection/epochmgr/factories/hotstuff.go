@@ -3,7 +3,6 @@ package factories
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/consensus"
@@ -21,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/state/cluster"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type HotStuffMetricsFunc func(chainID flow.ChainID) module.HotstuffMetrics
@@ -29,7 +29,7 @@ type HotStuffFactory struct {
 	log           zerolog.Logger
 	me            module.Local
 	aggregator    module.AggregatingSigner
-	db            *badger.DB
+	db            kv.DB
 	protoState    protocol.State
 	createMetrics HotStuffMetricsFunc
 	opts          []consensus.Option
@@ -39,7 +39,7 @@ func NewHotStuffFactory(
 	log zerolog.Logger,
 	me module.Local,
 	aggregator module.AggregatingSigner,
-	db *badger.DB,
+	db kv.DB,
 	protoState protocol.State,
 	createMetrics HotStuffMetricsFunc,
 	opts ...consensus.Option,
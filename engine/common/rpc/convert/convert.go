@@ -10,6 +10,7 @@ import (
 
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/inmem"
@@ -332,6 +333,48 @@ func EventsToMessages(flowEvents []flow.Event) []*entities.Event {
 	return events
 }
 
+func TransactionSimulationResultToMessage(r *flow.TransactionSimulationResult) *simulation.SimulateTransactionResponse {
+	registerWrites := make([]*simulation.RegisterWrite, len(r.RegisterWrites))
+	for i, w := range r.RegisterWrites {
+		registerWrites[i] = &simulation.RegisterWrite{
+			Owner:      []byte(w.RegisterID.Owner),
+			Controller: []byte(w.RegisterID.Controller),
+			Key:        []byte(w.RegisterID.Key),
+			ValueSize:  uint64(w.ValueSize),
+		}
+	}
+
+	return &simulation.SimulateTransactionResponse{
+		StatusCode:      uint32(r.StatusCode),
+		ErrorMessage:    r.ErrorMessage,
+		Events:          EventsToMessages(r.Events),
+		ComputationUsed: r.ComputationUsed,
+		RegisterWrites:  registerWrites,
+		BlockId:         IdentifierToMessage(r.BlockID),
+	}
+}
+
+// MessageToTransactionSimulationResult converts a simulation response to a simulation result, whose transaction ID
+// is left unset as the response doesn't carry it.
+func MessageToTransactionSimulationResult(m *simulation.SimulateTransactionResponse) *flow.TransactionSimulationResult {
+	registerWrites := make([]flow.RegisterWrite, len(m.GetRegisterWrites()))
+	for i, w := range m.GetRegisterWrites() {
+		registerWrites[i] = flow.RegisterWrite{
+			RegisterID: flow.NewRegisterID(string(w.GetOwner()), string(w.GetController()), string(w.GetKey())),
+			ValueSize:  int(w.GetValueSize()),
+		}
+	}
+
+	return &flow.TransactionSimulationResult{
+		BlockID:         MessageToIdentifier(m.GetBlockId()),
+		StatusCode:      uint(m.GetStatusCode()),
+		ErrorMessage:    m.GetErrorMessage(),
+		Events:          MessagesToEvents(m.GetEvents()),
+		ComputationUsed: m.GetComputationUsed(),
+		RegisterWrites:  registerWrites,
+	}
+}
+
 func IdentifierToMessage(i flow.Identifier) []byte {
 	return i[:]
 }
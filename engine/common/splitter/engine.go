@@ -151,3 +151,32 @@ func (e *Engine) process(processFunc func(module.Engine) error) error {
 
 	return multiErr.ErrorOrNil()
 }
+
+// HandleRequest passes the request to the registered engine handling the requests of the channel.
+// As a request has a single response, at most one of the registered engines may handle requests.
+func (e *Engine) HandleRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	if channel != e.channel {
+		return nil, fmt.Errorf("received request on unknown channel %s", channel)
+	}
+
+	var handler network.RequestHandler
+	e.enginesMu.RLock()
+	for eng := range e.engines {
+		h, ok := eng.(network.RequestHandler)
+		if !ok {
+			continue
+		}
+		if handler != nil {
+			e.enginesMu.RUnlock()
+			return nil, fmt.Errorf("multiple engines handle requests on channel %s", channel)
+		}
+		handler = h
+	}
+	e.enginesMu.RUnlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("no engine handles requests on channel %s", channel)
+	}
+
+	return handler.HandleRequest(channel, originID, request)
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/onflow/flow-go/engine/common/splitter"
+	"github.com/onflow/flow-go/model/flow"
 	mockmodule "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -146,3 +148,37 @@ func (suite *Suite) TestConcurrentEvents() {
 		engine.AssertExpectations(suite.T())
 	}
 }
+
+// requestHandlerEngine is an engine which also handles requests.
+type requestHandlerEngine struct {
+	*mockmodule.Engine
+	handler *mocknetwork.RequestHandler
+}
+
+func (e *requestHandlerEngine) HandleRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	return e.handler.HandleRequest(channel, originID, request)
+}
+
+// TestHandleRequest tests that requests are passed to the only registered engine handling requests.
+func (suite *Suite) TestHandleRequest() {
+	id := unittest.IdentifierFixture()
+	request := getEvent()
+
+	_, err := suite.engine.HandleRequest(suite.channel, id, request)
+	suite.Assert().Error(err)
+
+	suite.engine.RegisterEngine(new(mockmodule.Engine))
+	handler := &requestHandlerEngine{Engine: new(mockmodule.Engine), handler: new(mocknetwork.RequestHandler)}
+	suite.engine.RegisterEngine(handler)
+
+	handler.handler.On("HandleRequest", suite.channel, id, request).Return("response", nil).Once()
+	response, err := suite.engine.HandleRequest(suite.channel, id, request)
+	suite.Require().NoError(err)
+	suite.Assert().Equal("response", response)
+	handler.handler.AssertExpectations(suite.T())
+
+	// the response of a request cannot be chosen between multiple engines
+	suite.engine.RegisterEngine(&requestHandlerEngine{Engine: new(mockmodule.Engine), handler: new(mocknetwork.RequestHandler)})
+	_, err = suite.engine.HandleRequest(suite.channel, id, request)
+	suite.Assert().Error(err)
+}
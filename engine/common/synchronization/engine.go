@@ -3,18 +3,19 @@
 package synchronization
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
 	"time"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/fifoqueue"
 	"github.com/onflow/flow-go/model/events"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter/id"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module"
 	identifier "github.com/onflow/flow-go/module/id"
@@ -31,6 +32,9 @@ const defaultSyncResponseQueueCapacity = 500
 // defaultBlockResponseQueueCapacity maximum capacity of block responses queue
 const defaultBlockResponseQueueCapacity = 500
 
+// blockRequestTimeout is the maximum time to wait for the response of a range or batch request
+const blockRequestTimeout = 10 * time.Second
+
 // Engine is the synchronization engine, responsible for synchronizing chain state.
 type Engine struct {
 	unit    *engine.Unit
@@ -227,6 +231,11 @@ func (e *Engine) Process(channel network.Channel, originID flow.Identifier, even
 	return e.process(originID, event)
 }
 
+// HandleRequest handles the range and batch requests sent by the other nodes with Conduit.Request.
+func (e *Engine) HandleRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	return e.requestHandler.HandleRequest(channel, originID, request)
+}
+
 // process processes events for the synchronization engine.
 // Error returns:
 //  * IncompatibleInputTypeError if input has unexpected type
@@ -365,9 +374,15 @@ func (e *Engine) pollHeight() {
 	e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageSyncRequest)
 }
 
-// sendRequests sends a request for each range and batch using consensus participants from last finalized snapshot.
+// sendRequests sends a request for each range and batch to a few consensus participants from last finalized
+// snapshot. The requests are sent in the background, and their responses are processed like the block
+// responses received from the network.
 func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Range, batches []flow.Batch) {
-	var errs *multierror.Error
+	participants = participants.Filter(id.Not(id.Is(e.me.NodeID())))
+	if len(participants) == 0 && len(ranges)+len(batches) > 0 {
+		e.log.Warn().Msg("sending range and batch requests failed: no participants to send requests to")
+		return
+	}
 
 	for _, ran := range ranges {
 		req := &messages.RangeRequest{
@@ -375,10 +390,8 @@ func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Ra
 			FromHeight: ran.From,
 			ToHeight:   ran.To,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
-		if err != nil {
-			errs = multierror.Append(errs, fmt.Errorf("could not submit range request: %w", err))
-			continue
+		for _, targetID := range participants.Sample(synccore.DefaultBlockRequestNodes) {
+			e.request(req, targetID)
 		}
 		e.log.Debug().
 			Uint64("range_from", req.FromHeight).
@@ -394,10 +407,8 @@ func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Ra
 			Nonce:    rand.Uint64(),
 			BlockIDs: batch.BlockIDs,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
-		if err != nil {
-			errs = multierror.Append(errs, fmt.Errorf("could not submit batch request: %w", err))
-			continue
+		for _, targetID := range participants.Sample(synccore.DefaultBlockRequestNodes) {
+			e.request(req, targetID)
 		}
 		e.log.Debug().
 			Strs("block_ids", flow.IdentifierList(batch.BlockIDs).Strings()).
@@ -406,8 +417,33 @@ func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Ra
 		e.core.BatchRequested(batch)
 		e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBatchRequest)
 	}
+}
 
-	if err := errs.ErrorOrNil(); err != nil {
-		e.log.Warn().Err(err).Msg("sending range and batch requests failed")
-	}
+// request sends the range or batch request to the target in the background, and queues its response
+// for processing. The targets which do not support requests are sent the request as a unicast message,
+// and respond with a unicast message processed as any other response.
+// A failed request is retried by the sync core once the request is considered lost.
+func (e *Engine) request(req interface{}, targetID flow.Identifier) {
+	e.unit.Launch(func() {
+		ctx, cancel := context.WithTimeout(e.unit.Ctx(), blockRequestTimeout)
+		defer cancel()
+
+		res, err := e.con.Request(ctx, req, targetID)
+		if errors.Is(err, network.ErrRequestNotSupported) {
+			err = e.con.Unicast(req, targetID)
+			if err != nil {
+				e.log.Warn().Err(err).Hex("target_id", targetID[:]).Msgf("sending %T failed", req)
+			}
+			return
+		}
+		if err != nil {
+			e.log.Warn().Err(err).Hex("target_id", targetID[:]).Msgf("%T failed", req)
+			return
+		}
+
+		err = e.responseMessageHandler.Process(targetID, res)
+		if err != nil {
+			e.log.Warn().Err(err).Hex("target_id", targetID[:]).Msgf("could not process response to %T", req)
+		}
+	})
 }
@@ -1,8 +1,10 @@
 package synchronization
 
 import (
+	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	netint "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/stub"
 	protocolint "github.com/onflow/flow-go/state/protocol"
 	protocolEvents "github.com/onflow/flow-go/state/protocol/events"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -446,29 +449,113 @@ func (ss *SyncSuite) TestSendRequests() {
 
 	ranges := unittest.RangeListFixture(1)
 	batches := unittest.BatchListFixture(1)
+	others := ss.participants[1:].NodeIDs()
+
+	// each request is sent to every other participant, and its response is processed
+	var requests sync.WaitGroup
+	requests.Add(2 * len(others))
 
 	// should submit and mark requested all ranges
-	ss.con.On("Multicast", mock.AnythingOfType("*messages.RangeRequest"), synccore.DefaultBlockRequestNodes, mock.Anything, mock.Anything).Return(nil).Run(
+	ss.con.On("Request", mock.Anything, mock.AnythingOfType("*messages.RangeRequest"), mock.Anything).Return(&messages.BlockResponse{}, nil).Run(
 		func(args mock.Arguments) {
-			req := args.Get(0).(*messages.RangeRequest)
+			defer requests.Done()
+			req := args.Get(1).(*messages.RangeRequest)
 			ss.Assert().Equal(ranges[0].From, req.FromHeight)
 			ss.Assert().Equal(ranges[0].To, req.ToHeight)
+			ss.Assert().Contains(others, args.Get(2).(flow.Identifier))
 		},
-	)
+	).Times(len(others))
 	ss.core.On("RangeRequested", ranges[0])
 
 	// should submit and mark requested all batches
-	ss.con.On("Multicast", mock.AnythingOfType("*messages.BatchRequest"), synccore.DefaultBlockRequestNodes, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(
+	ss.con.On("Request", mock.Anything, mock.AnythingOfType("*messages.BatchRequest"), mock.Anything).Return(&messages.BlockResponse{}, nil).Run(
 		func(args mock.Arguments) {
-			req := args.Get(0).(*messages.BatchRequest)
+			defer requests.Done()
+			req := args.Get(1).(*messages.BatchRequest)
 			ss.Assert().Equal(batches[0].BlockIDs, req.BlockIDs)
+			ss.Assert().Contains(others, args.Get(2).(flow.Identifier))
 		},
-	)
+	).Times(len(others))
 	ss.core.On("BatchRequested", batches[0])
 
-	// exclude my node ID
-	ss.e.sendRequests(ss.participants[1:].NodeIDs(), ranges, batches)
+	// my node ID should be excluded
+	ss.e.sendRequests(ss.participants.NodeIDs(), ranges, batches)
+	unittest.RequireReturnsBefore(ss.T(), requests.Wait, time.Second, "requests should be sent")
+	ss.con.AssertExpectations(ss.T())
+	ss.core.AssertExpectations(ss.T())
+}
+
+// TestSendRequests_UnicastFallback tests that the requests to the nodes which do not support the request/response
+// protocol are sent as unicast messages.
+func (ss *SyncSuite) TestSendRequests_UnicastFallback() {
+	ranges := unittest.RangeListFixture(1)
+	targetID := ss.participants[1].NodeID
+
+	var sent sync.WaitGroup
+	sent.Add(1)
+	notSupported := fmt.Errorf("could not send request: %w", netint.ErrRequestNotSupported)
+	ss.con.On("Request", mock.Anything, mock.AnythingOfType("*messages.RangeRequest"), targetID).Return(nil, notSupported).Once()
+	ss.con.On("Unicast", mock.AnythingOfType("*messages.RangeRequest"), targetID).Return(nil).Run(
+		func(args mock.Arguments) {
+			defer sent.Done()
+			req := args.Get(0).(*messages.RangeRequest)
+			ss.Assert().Equal(ranges[0].From, req.FromHeight)
+			ss.Assert().Equal(ranges[0].To, req.ToHeight)
+		},
+	).Once()
+	ss.core.On("RangeRequested", ranges[0])
+
+	ss.e.sendRequests(flow.IdentifierList{targetID}, ranges, nil)
+	unittest.RequireReturnsBefore(ss.T(), sent.Wait, time.Second, "request should be sent as a unicast message")
 	ss.con.AssertExpectations(ss.T())
+	ss.core.AssertExpectations(ss.T())
+}
+
+// TestHandleRequest tests that the range and batch requests sent with Conduit.Request are
+// answered with the requested blocks.
+func (ss *SyncSuite) TestHandleRequest() {
+	originID := unittest.IdentifierFixture()
+
+	// fill in blocks at heights -1 to -2 from head
+	ref := ss.head.Height
+	for height := ref; height >= ref-2; height-- {
+		block := unittest.BlockFixture()
+		block.Header.Height = height
+		ss.heights[height] = &block
+		ss.blockIDs[block.ID()] = &block
+	}
+
+	rangeReq := &messages.RangeRequest{
+		Nonce:      rand.Uint64(),
+		FromHeight: ref - 1,
+		ToHeight:   ref + 2,
+	}
+	res, err := ss.e.HandleRequest(engine.SyncCommittee, originID, rangeReq)
+	require.NoError(ss.T(), err)
+	expected := []*flow.Block{ss.heights[ref-1], ss.heights[ref]}
+	ss.Assert().ElementsMatch(expected, res.(*messages.BlockResponse).Blocks, "response should contain known blocks of range")
+	ss.Assert().Equal(rangeReq.Nonce, res.(*messages.BlockResponse).Nonce, "response should contain request nonce")
+
+	// the response to a request for unknown blocks is empty
+	batchReq := &messages.BatchRequest{
+		Nonce:    rand.Uint64(),
+		BlockIDs: []flow.Identifier{unittest.IdentifierFixture()},
+	}
+	res, err = ss.e.HandleRequest(engine.SyncCommittee, originID, batchReq)
+	require.NoError(ss.T(), err)
+	ss.Assert().Empty(res.(*messages.BlockResponse).Blocks)
+
+	batchReq.BlockIDs = append(batchReq.BlockIDs, ss.heights[ref-2].ID())
+	res, err = ss.e.HandleRequest(engine.SyncCommittee, originID, batchReq)
+	require.NoError(ss.T(), err)
+	ss.Assert().ElementsMatch([]*flow.Block{ss.heights[ref-2]}, res.(*messages.BlockResponse).Blocks)
+
+	// other messages are not requests
+	_, err = ss.e.HandleRequest(engine.SyncCommittee, originID, &messages.SyncRequest{})
+	ss.Assert().ErrorIs(err, engine.IncompatibleInputTypeError)
+
+	// responses are never sent as separate messages
+	ss.con.AssertNotCalled(ss.T(), "Unicast", mock.Anything, mock.Anything)
 }
 
 // test a synchronization engine can be started and stopped
@@ -527,3 +614,131 @@ func (ss *SyncSuite) TestOnFinalizedBlock() {
 	require.ElementsMatch(ss.T(), ss.e.participantsProvider.Identifiers(), ss.participants[1:].NodeIDs())
 	require.Equal(ss.T(), actualHeader, &finalizedBlock)
 }
+
+// TestSyncEngine_FaultyNetwork tests that a node catches up with the finalized chain of another node
+// over a network delaying, dropping, duplicating and reordering messages, once a partition is healed.
+func TestSyncEngine_FaultyNetwork(t *testing.T) {
+	// the seed is fixed, so that a failure can be reproduced
+	faults := stub.NewFaults(1, stub.LinkFaults{
+		Latency:   stub.UniformLatency(0, 20*time.Millisecond),
+		Drop:      0.2,
+		Duplicate: 0.2,
+		Reorder:   0.2,
+	})
+	hub := stub.NewFaultyNetworkHub(faults)
+
+	// the provider has finalized a chain, of which the other node only knows the root block
+	chain := []*flow.Block{unittest.GenesisFixture()}
+	for height := 1; height <= 30; height++ {
+		block := unittest.BlockWithParentFixture(chain[height-1].Header)
+		chain = append(chain, &block)
+	}
+
+	providerID := unittest.IdentifierFixture()
+	nodeID := unittest.IdentifierFixture()
+
+	provider := newFaultyNetworkSyncNode(t, hub, providerID, nodeID, chain)
+	provider.comp.On("SubmitLocal", mock.Anything).Return().Maybe()
+
+	var mu sync.Mutex
+	synced := make(map[uint64]struct{})
+	node := newFaultyNetworkSyncNode(t, hub, nodeID, providerID, chain[:1])
+	node.comp.On("SubmitLocal", mock.Anything).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		synced[args.Get(0).(*events.SyncedBlock).Block.Header.Height] = struct{}{}
+	}).Return()
+	countSynced := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(synced)
+	}
+
+	unittest.RequireComponentsReadyBefore(t, time.Second, provider.e, node.e)
+
+	// while the nodes are partitioned, no block is synchronized
+	faults.Partition(flow.IdentifierList{providerID})
+	// the nodes keep polling each other, until several of their messages were lost to the partition
+	hub.DeliverAllEventuallyUntil(t, func() bool {
+		return faults.Stats().Partitioned >= 10
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Zero(t, countSynced())
+
+	// once the partition is healed, all the missing blocks are synchronized despite the faults
+	faults.Heal()
+	hub.DeliverAllEventuallyUntil(t, func() bool {
+		return countSynced() == len(chain)-1
+	}, 10*time.Second, 20*time.Millisecond)
+
+	unittest.RequireComponentsDoneBefore(t, time.Second, provider.e, node.e)
+}
+
+// faultyNetworkSyncNode is a node running the synchronization engine on a stub network.
+type faultyNetworkSyncNode struct {
+	e    *Engine
+	comp *mocknetwork.Engine
+}
+
+// newFaultyNetworkSyncNode returns a node which has finalized the given chain, and synchronizes with its peer.
+func newFaultyNetworkSyncNode(t *testing.T, hub *stub.Hub, nodeID flow.Identifier, peerID flow.Identifier, chain []*flow.Block) *faultyNetworkSyncNode {
+	log := zerolog.New(ioutil.Discard)
+
+	me := &module.Local{}
+	me.On("NodeID").Return(nodeID)
+
+	head := chain[len(chain)-1].Header
+	snapshot := &protocol.Snapshot{}
+	snapshot.On("Head").Return(head, nil)
+	state := &protocol.State{}
+	state.On("Final").Return(snapshot)
+
+	heights := make(map[uint64]*flow.Block)
+	blockIDs := make(map[flow.Identifier]*flow.Block)
+	for _, block := range chain {
+		heights[block.Header.Height] = block
+		blockIDs[block.ID()] = block
+	}
+	blocks := &storage.Blocks{}
+	blocks.On("ByHeight", mock.Anything).Return(
+		func(height uint64) *flow.Block {
+			return heights[height]
+		},
+		func(height uint64) error {
+			if _, ok := heights[height]; !ok {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+	blocks.On("ByID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Block {
+			return blockIDs[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			if _, ok := blockIDs[blockID]; !ok {
+				return storerr.ErrNotFound
+			}
+			return nil
+		},
+	)
+
+	// retry quickly, and never give up on the requests lost by the network
+	config := synccore.DefaultConfig()
+	config.RetryInterval = 50 * time.Millisecond
+	config.MaxAttempts = 1000
+	core, err := synccore.New(log, config)
+	require.NoError(t, err)
+
+	finalizedHeader, err := NewFinalizedHeaderCache(log, state, pubsub.NewFinalizationDistributor())
+	require.NoError(t, err)
+
+	comp := &mocknetwork.Engine{}
+	e, err := New(log, metrics.NewNoopCollector(), stub.NewNetwork(state, me, hub), me, blocks, comp, core, finalizedHeader,
+		id.NewFixedIdentifierProvider(flow.IdentifierList{peerID}),
+		WithPollInterval(50*time.Millisecond),
+		WithScanInterval(20*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	return &faultyNetworkSyncNode{e: e, comp: comp}
+}
@@ -214,48 +214,87 @@ func (r *RequestHandlerEngine) onSyncRequest(originID flow.Identifier, req *mess
 // onRangeRequest processes a request for a range of blocks by height.
 func (r *RequestHandlerEngine) onRangeRequest(originID flow.Identifier, req *messages.RangeRequest) error {
 	r.log.Debug().Str("origin_id", originID.String()).Msg("received new range request")
+
+	res, err := r.rangeResponse(req)
+	if err != nil {
+		return err
+	}
+
+	// if there are no blocks to send, skip network message
+	if len(res.Blocks) == 0 {
+		r.log.Debug().Msg("skipping empty range response")
+		return nil
+	}
+
+	// send the response
+	err = r.con.Unicast(res, originID)
+	if err != nil {
+		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending range response failed")
+		return nil
+	}
+	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
+
+	return nil
+}
+
+// rangeResponse returns the response to a request for a range of blocks by height, with the
+// finalized blocks of the range known by this node.
+func (r *RequestHandlerEngine) rangeResponse(req *messages.RangeRequest) (*messages.BlockResponse, error) {
+	res := &messages.BlockResponse{
+		Nonce: req.Nonce,
+	}
+
 	// get the latest final state to know if we can fulfill the request
 	head := r.finalizedHeader.Get()
 
 	// if we don't have anything to send, we can bail right away
 	if head.Height < req.FromHeight || req.FromHeight > req.ToHeight {
-		return nil
+		return res, nil
 	}
 
 	// enforce client-side max request size
+	toHeight := req.ToHeight
 	maxHeight := req.FromHeight + uint64(synchronization.DefaultConfig().MaxSize)
-	if maxHeight < req.ToHeight {
-		req.ToHeight = maxHeight
+	if maxHeight < toHeight {
+		toHeight = maxHeight
 	}
 
 	// get all of the blocks, one by one
-	blocks := make([]*flow.Block, 0, req.ToHeight-req.FromHeight+1)
-	for height := req.FromHeight; height <= req.ToHeight; height++ {
+	res.Blocks = make([]*flow.Block, 0, toHeight-req.FromHeight+1)
+	for height := req.FromHeight; height <= toHeight; height++ {
 		block, err := r.blocks.ByHeight(height)
 		if errors.Is(err, storage.ErrNotFound) {
 			r.log.Error().Uint64("height", height).Msg("skipping unknown heights")
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("could not get block for height (%d): %w", height, err)
+			return nil, fmt.Errorf("could not get block for height (%d): %w", height, err)
 		}
-		blocks = append(blocks, block)
+		res.Blocks = append(res.Blocks, block)
+	}
+
+	return res, nil
+}
+
+// onBatchRequest processes a request for a specific block by block ID.
+func (r *RequestHandlerEngine) onBatchRequest(originID flow.Identifier, req *messages.BatchRequest) error {
+	r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
+
+	res, err := r.batchResponse(req)
+	if err != nil {
+		return err
 	}
 
 	// if there are no blocks to send, skip network message
-	if len(blocks) == 0 {
-		r.log.Debug().Msg("skipping empty range response")
+	if len(res.Blocks) == 0 {
+		r.log.Debug().Msg("skipping empty batch response")
 		return nil
 	}
 
 	// send the response
-	res := &messages.BlockResponse{
-		Nonce:  req.Nonce,
-		Blocks: blocks,
-	}
-	err := r.con.Unicast(res, originID)
+	err = r.con.Unicast(res, originID)
 	if err != nil {
-		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending range response failed")
+		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending batch response failed")
 		return nil
 	}
 	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
@@ -263,12 +302,11 @@ func (r *RequestHandlerEngine) onRangeRequest(originID flow.Identifier, req *mes
 	return nil
 }
 
-// onBatchRequest processes a request for a specific block by block ID.
-func (r *RequestHandlerEngine) onBatchRequest(originID flow.Identifier, req *messages.BatchRequest) error {
-	r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
-	// we should bail and send nothing on empty request
-	if len(req.BlockIDs) == 0 {
-		return nil
+// batchResponse returns the response to a request for specific blocks by block ID, with the
+// requested blocks known by this node.
+func (r *RequestHandlerEngine) batchResponse(req *messages.BatchRequest) (*messages.BlockResponse, error) {
+	res := &messages.BlockResponse{
+		Nonce: req.Nonce,
 	}
 
 	// deduplicate the block IDs in the batch request
@@ -283,7 +321,7 @@ func (r *RequestHandlerEngine) onBatchRequest(originID flow.Identifier, req *mes
 	}
 
 	// try to get all the blocks by ID
-	blocks := make([]*flow.Block, 0, len(blockIDs))
+	res.Blocks = make([]*flow.Block, 0, len(blockIDs))
 	for blockID := range blockIDs {
 		block, err := r.blocks.ByID(blockID)
 		if errors.Is(err, storage.ErrNotFound) {
@@ -291,30 +329,37 @@ func (r *RequestHandlerEngine) onBatchRequest(originID flow.Identifier, req *mes
 			continue
 		}
 		if err != nil {
-			return fmt.Errorf("could not get block by ID (%s): %w", blockID, err)
+			return nil, fmt.Errorf("could not get block by ID (%s): %w", blockID, err)
 		}
-		blocks = append(blocks, block)
+		res.Blocks = append(res.Blocks, block)
 	}
 
-	// if there are no blocks to send, skip network message
-	if len(blocks) == 0 {
-		r.log.Debug().Msg("skipping empty batch response")
-		return nil
-	}
+	return res, nil
+}
 
-	// send the response
-	res := &messages.BlockResponse{
-		Nonce:  req.Nonce,
-		Blocks: blocks,
+// HandleRequest handles the range and batch requests sent with Conduit.Request, and returns the
+// requested blocks known by this node. The response is empty if none of the blocks are known.
+func (r *RequestHandlerEngine) HandleRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	var res *messages.BlockResponse
+	var err error
+	switch req := request.(type) {
+	case *messages.RangeRequest:
+		r.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageRangeRequest)
+		r.log.Debug().Str("origin_id", originID.String()).Msg("received new range request")
+		res, err = r.rangeResponse(req)
+	case *messages.BatchRequest:
+		r.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageBatchRequest)
+		r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
+		res, err = r.batchResponse(req)
+	default:
+		return nil, fmt.Errorf("received request with type %T from %x: %w", request, originID[:], engine.IncompatibleInputTypeError)
 	}
-	err := r.con.Unicast(res, originID)
 	if err != nil {
-		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending batch response failed")
-		return nil
+		return nil, err
 	}
-	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
 
-	return nil
+	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
+	return res, nil
 }
 
 // processAvailableRequests is processor of pending events which drives events from networking layer to business logic.
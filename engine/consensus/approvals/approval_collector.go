@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/consensus"
 	"github.com/onflow/flow-go/model/chunks"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool"
@@ -178,3 +179,23 @@ func (c *ApprovalCollector) CollectMissingVerifiers() map[uint64]flow.Identifier
 
 	return targetIDs
 }
+
+// ChunksProgress returns the progress of collecting the approvals of every chunk. The verifiers which haven't
+// provided an approval are only listed for the chunks which didn't collect enough approvals yet.
+func (c *ApprovalCollector) ChunksProgress() []consensus.ChunkSealingProgress {
+	progress := make([]consensus.ChunkSealingProgress, 0, len(c.chunkCollectors))
+	for i, collector := range c.chunkCollectors {
+		chunkIndex := uint64(i)
+		chunk := consensus.ChunkSealingProgress{
+			Index:             chunkIndex,
+			Approvals:         collector.NumberApprovals(),
+			RequiredApprovals: collector.RequiredApprovals(),
+			Approved:          c.aggregatedSignatures.HasSignature(chunkIndex),
+		}
+		if !chunk.Approved {
+			chunk.MissingVerifiers = collector.GetMissingSigners()
+		}
+		progress = append(progress, chunk)
+	}
+	return progress
+}
@@ -127,3 +127,39 @@ func (s *ApprovalCollectorTestSuite) TestCollectMissingVerifiers() {
 	// skip first ID since we should have approval for it
 	require.Empty(s.T(), s.collector.CollectMissingVerifiers())
 }
+
+// TestChunksProgress tests that approval collector reports the approvals collected for every chunk and lists the
+// verifiers which haven't provided an approval only for the chunks which aren't approved yet
+func (s *ApprovalCollectorTestSuite) TestChunksProgress() {
+	s.sealsPL.On("Add", mock.Anything).Return(true, nil).Maybe()
+
+	// no approvals processed
+	progress := s.collector.ChunksProgress()
+	require.Len(s.T(), progress, s.Chunks.Len())
+	for _, chunk := range progress {
+		require.Equal(s.T(), uint(0), chunk.Approvals)
+		require.Equal(s.T(), uint(len(s.AuthorizedVerifiers)), chunk.RequiredApprovals)
+		require.False(s.T(), chunk.Approved)
+		require.ElementsMatch(s.T(), s.ChunksAssignment.Verifiers(s.Chunks[chunk.Index]), chunk.MissingVerifiers)
+	}
+
+	// process all approvals for the first chunk and one approval for the second chunk
+	for verID := range s.AuthorizedVerifiers {
+		approval := unittest.ResultApprovalFixture(unittest.WithChunk(0), unittest.WithApproverID(verID))
+		err := s.collector.ProcessApproval(approval)
+		require.NoError(s.T(), err)
+	}
+	approval := unittest.ResultApprovalFixture(unittest.WithChunk(1), unittest.WithApproverID(s.VerID))
+	err := s.collector.ProcessApproval(approval)
+	require.NoError(s.T(), err)
+
+	progress = s.collector.ChunksProgress()
+	require.Equal(s.T(), uint(len(s.AuthorizedVerifiers)), progress[0].Approvals)
+	require.True(s.T(), progress[0].Approved)
+	require.Empty(s.T(), progress[0].MissingVerifiers)
+
+	require.Equal(s.T(), uint(1), progress[1].Approvals)
+	require.False(s.T(), progress[1].Approved)
+	require.Len(s.T(), progress[1].MissingVerifiers, len(s.AuthorizedVerifiers)-1)
+	require.NotContains(s.T(), progress[1].MissingVerifiers, s.VerID)
+}
@@ -93,4 +93,8 @@ type AssignmentCollectorState interface {
 
 	// ProcessingStatus returns the AssignmentCollector's ProcessingStatus (state descriptor).
 	ProcessingStatus() ProcessingStatus
+
+	// SealingProgress returns the progress of sealing the result for each block incorporating it, given
+	// the height of the latest finalized block. No errors are expected during normal operations.
+	SealingProgress(finalizedBlockHeight uint64) (consensus.ResultSealingProgress, error)
 }
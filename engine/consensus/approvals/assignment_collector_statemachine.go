@@ -121,6 +121,13 @@ func (asm *AssignmentCollectorStateMachine) RequestMissingApprovals(observer con
 	return collector.RequestMissingApprovals(observer, maxHeightForRequesting)
 }
 
+// SealingProgress returns the progress of sealing the result for each block incorporating it, given
+// the height of the latest finalized block. No errors are expected during normal operations.
+func (asm *AssignmentCollectorStateMachine) SealingProgress(finalizedBlockHeight uint64) (consensus.ResultSealingProgress, error) {
+	collector := asm.atomicLoadCollector()
+	return collector.SealingProgress(finalizedBlockHeight)
+}
+
 // ProcessingStatus returns the AssignmentCollector's ProcessingStatus (state descriptor).
 func (asm *AssignmentCollectorStateMachine) ProcessingStatus() ProcessingStatus {
 	collector := asm.atomicLoadCollector()
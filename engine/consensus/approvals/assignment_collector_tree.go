@@ -223,6 +223,19 @@ func (t *AssignmentCollectorTree) GetCollectorsByInterval(from, to uint64) []Ass
 	return vertices
 }
 
+// GetCollectorsAtHeight returns all collectors, whatever their state, for results of blocks at the given height.
+func (t *AssignmentCollectorTree) GetCollectorsAtHeight(height uint64) []AssignmentCollector {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var collectors []AssignmentCollector
+	iter := t.forest.GetVerticesAtLevel(height)
+	for iter.HasNext() {
+		collectors = append(collectors, iter.NextVertex().(*assignmentCollectorVertex).collector)
+	}
+	return collectors
+}
+
 // LazyInitCollector is a helper structure that is used to return collector which is lazy initialized
 type LazyInitCollector struct {
 	Collector AssignmentCollector
@@ -86,3 +86,24 @@ func (ac *CachingAssignmentCollector) GetIncorporatedResults() []*flow.Incorpora
 func (ac *CachingAssignmentCollector) GetApprovals() []*flow.ResultApproval {
 	return ac.approvalsCache.All()
 }
+
+// SealingProgress returns the incorporated results cached so far. Their chunk assignments are only computed once
+// the collector starts verifying approvals, hence their chunks are not reported.
+func (ac *CachingAssignmentCollector) SealingProgress(uint64) (consensus.ResultSealingProgress, error) {
+	progress := consensus.ResultSealingProgress{
+		ResultID:         ac.ResultID(),
+		ProcessingStatus: CachingApprovals.String(),
+	}
+	for _, incorporatedResult := range ac.incResCache.All() {
+		incorporatedBlock, err := ac.headers.ByBlockID(incorporatedResult.IncorporatedBlockID)
+		if err != nil {
+			return consensus.ResultSealingProgress{}, fmt.Errorf("failed to retrieve header of incorporated block %s: %w",
+				incorporatedResult.IncorporatedBlockID, err)
+		}
+		progress.IncorporatedResults = append(progress.IncorporatedResults, consensus.IncorporatedResultSealingProgress{
+			IncorporatedBlockID:     incorporatedResult.IncorporatedBlockID,
+			IncorporatedBlockHeight: incorporatedBlock.Height,
+		})
+	}
+	return progress, nil
+}
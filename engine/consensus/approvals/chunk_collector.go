@@ -53,3 +53,15 @@ func (c *ChunkApprovalCollector) GetMissingSigners() flow.IdentifierList {
 
 	return result
 }
+
+// NumberApprovals returns the number of approvals collected from the verifiers assigned to current chunk
+func (c *ChunkApprovalCollector) NumberApprovals() uint {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.chunkApprovals.NumberSignatures()
+}
+
+// RequiredApprovals returns the number of approvals that are required for the chunk to be sealed
+func (c *ChunkApprovalCollector) RequiredApprovals() uint {
+	return c.requiredApprovalsForSealConstruction
+}
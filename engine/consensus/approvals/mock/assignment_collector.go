@@ -170,3 +170,24 @@ func (_m *AssignmentCollector) ResultID() flow.Identifier {
 
 	return r0
 }
+
+// SealingProgress provides a mock function with given fields: finalizedBlockHeight
+func (_m *AssignmentCollector) SealingProgress(finalizedBlockHeight uint64) (consensus.ResultSealingProgress, error) {
+	ret := _m.Called(finalizedBlockHeight)
+
+	var r0 consensus.ResultSealingProgress
+	if rf, ok := ret.Get(0).(func(uint64) consensus.ResultSealingProgress); ok {
+		r0 = rf(finalizedBlockHeight)
+	} else {
+		r0 = ret.Get(0).(consensus.ResultSealingProgress)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(finalizedBlockHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -23,6 +23,9 @@ func (oc *OrphanAssignmentCollector) CheckEmergencySealing(consensus.SealingObse
 func (oc *OrphanAssignmentCollector) RequestMissingApprovals(consensus.SealingObservation, uint64) (uint, error) {
 	return 0, nil
 }
+func (oc *OrphanAssignmentCollector) SealingProgress(uint64) (consensus.ResultSealingProgress, error) {
+	return consensus.ResultSealingProgress{ResultID: oc.ResultID(), ProcessingStatus: Orphaned.String()}, nil
+}
 func (oc *OrphanAssignmentCollector) ProcessIncorporatedResult(*flow.IncorporatedResult) error {
 	return nil
 }
@@ -3,6 +3,7 @@ package approvals
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 
 	"github.com/rs/zerolog/log"
@@ -355,6 +356,41 @@ func (ac *VerifyingAssignmentCollector) RequestMissingApprovals(observation cons
 	return overallRequestCount, nil
 }
 
+// SealingProgress returns, for each block incorporating the result, the approvals collected for every chunk and
+// the approval requests sent to the verifiers which haven't provided theirs yet.
+func (ac *VerifyingAssignmentCollector) SealingProgress(finalizedBlockHeight uint64) (consensus.ResultSealingProgress, error) {
+	progress := consensus.ResultSealingProgress{
+		ResultID:         ac.ResultID(),
+		ProcessingStatus: VerifyingApprovals.String(),
+	}
+	for _, collector := range ac.allCollectors() {
+		incorporatedResult := consensus.IncorporatedResultSealingProgress{
+			IncorporatedBlockID:     collector.IncorporatedBlockID(),
+			IncorporatedBlockHeight: collector.IncorporatedBlock().Height,
+			Sealable:                true,
+			EmergencySealable:       ac.emergencySealable(collector, finalizedBlockHeight),
+			Chunks:                  collector.ChunksProgress(),
+		}
+		for i := range incorporatedResult.Chunks {
+			chunk := &incorporatedResult.Chunks[i]
+			incorporatedResult.Sealable = incorporatedResult.Sealable && chunk.Approved
+			item, requested := ac.requestTracker.Get(ac.ResultID(), collector.IncorporatedBlockID(), chunk.Index)
+			if requested && !chunk.Approved {
+				nextRequest := item.NextTimeout
+				chunk.Requests = item.Requests
+				chunk.NextRequest = &nextRequest
+			}
+		}
+		progress.IncorporatedResults = append(progress.IncorporatedResults, incorporatedResult)
+	}
+
+	// the collectors are kept in a map, so they are ordered for the report to be stable
+	sort.Slice(progress.IncorporatedResults, func(i, j int) bool {
+		return progress.IncorporatedResults[i].IncorporatedBlockHeight < progress.IncorporatedResults[j].IncorporatedBlockHeight
+	})
+	return progress, nil
+}
+
 // authorizedVerifiersAtBlock pre-select all authorized Verifiers at the block that incorporates the result.
 // The method returns the set of all node IDs that:
 //   * are authorized members of the network at the given block and
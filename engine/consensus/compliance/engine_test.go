@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -13,6 +14,11 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
+	real "github.com/onflow/flow-go/module/buffer"
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/stub"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -189,3 +195,123 @@ func (cs *ComplianceSuite) TestSubmittingMultipleEntries() {
 	// check the submit vote was called with correct parameters
 	cs.hotstuff.AssertExpectations(cs.T())
 }
+
+// TestFaultyNetwork tests that the proposals of a leader are forwarded exactly once to hotstuff, over a
+// network delaying, dropping, duplicating and reordering messages, and once a partition is healed.
+func (cs *ComplianceSuite) TestFaultyNetwork() {
+	// the seed is fixed, so that a failure can be reproduced
+	faults := stub.NewFaults(1, stub.LinkFaults{
+		Latency:   stub.UniformLatency(0, 20*time.Millisecond),
+		Drop:      0.2,
+		Duplicate: 0.2,
+		Reorder:   0.2,
+	})
+	hub := stub.NewFaultyNetworkHub(faults)
+
+	// the leader proposes a chain of blocks extending the finalized head
+	leaderID := cs.participants[1].NodeID
+	var chain []*messages.BlockProposal
+	proposals := make(map[flow.Identifier]*messages.BlockProposal)
+	parent := cs.head
+	for i := 0; i < 20; i++ {
+		block := unittest.BlockWithParentFixture(parent)
+		block.Header.ProposerID = leaderID
+		proposal := unittest.ProposalFromBlock(&block)
+		chain = append(chain, proposal)
+		proposals[block.ID()] = proposal
+		parent = block.Header
+	}
+
+	leaderMe := &module.Local{}
+	leaderMe.On("NodeID").Return(leaderID)
+	leader, err := stub.NewNetwork(cs.state, leaderMe, hub).Register(engine.ConsensusCommittee, &mocknetwork.Engine{})
+	require.NoError(cs.T(), err)
+
+	// the protocol state persists the headers of the valid proposals, so that duplicates are ignored
+	state := &protocol.MutableState{}
+	state.On("Final").Return(cs.snapshot)
+	state.On("Extend", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		block := args.Get(1).(*flow.Block)
+		cs.headerDB[block.ID()] = block.Header
+	}).Return(nil)
+
+	// the missing ancestors of the proposals are requested again from the leader
+	var mu sync.Mutex
+	requested := make(map[flow.Identifier]struct{})
+	submitted := make(map[flow.Identifier]int)
+	blockSync := &module.BlockRequester{}
+	blockSync.On("RequestBlock", mock.Anything).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested[args.Get(0).(flow.Identifier)] = struct{}{}
+	}).Return()
+	resendRequested := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for blockID := range requested {
+			if submitted[blockID] == 0 {
+				require.NoError(cs.T(), leader.Unicast(proposals[blockID], cs.myID))
+			}
+		}
+	}
+
+	closed := func() <-chan struct{} {
+		channel := make(chan struct{})
+		close(channel)
+		return channel
+	}()
+	hot := &module.HotStuff{}
+	hot.On("Ready").Return(closed)
+	hot.On("Done").Return(closed)
+	hot.On("SubmitProposal", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		submitted[args.Get(0).(*flow.Header).ID()]++
+	}).Return()
+	countSubmitted := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(submitted)
+	}
+
+	core, err := NewCore(unittest.Logger(), cs.metrics, cs.tracer, cs.metrics, cs.metrics, cs.cleaner, cs.headers, cs.payloads, state, real.NewPendingBlocks(), blockSync)
+	require.NoError(cs.T(), err)
+	e, err := NewEngine(unittest.Logger(), stub.NewNetwork(cs.state, cs.me, hub), cs.me, cs.prov, core)
+	require.NoError(cs.T(), err)
+	e.WithConsensus(hot)
+	unittest.RequireCloseBefore(cs.T(), e.Ready(), time.Second, "engine should start")
+
+	// the proposals sent while the leader is partitioned are lost
+	faults.Partition(flow.IdentifierList{leaderID})
+	for _, proposal := range chain[:5] {
+		require.NoError(cs.T(), leader.Publish(proposal, cs.myID))
+	}
+	// every copy of the proposals which is not dropped on its link is lost to the partition once due
+	hub.DeliverAllEventuallyUntil(cs.T(), func() bool {
+		stats := faults.Stats()
+		return stats.Dropped+stats.Partitioned == uint64(len(chain[:5]))+stats.Duplicated
+	}, time.Second, 10*time.Millisecond)
+	require.Zero(cs.T(), countSubmitted())
+	require.NotZero(cs.T(), faults.Stats().Partitioned)
+
+	// once the partition is healed, the missing proposals are requested from the leader, and all
+	// proposals are eventually processed despite the faults
+	faults.Heal()
+	for _, proposal := range chain[5:] {
+		require.NoError(cs.T(), leader.Publish(proposal, cs.myID))
+	}
+	hub.DeliverAllEventuallyUntil(cs.T(), func() bool {
+		// the leader keeps proposing on top of its last proposal, and answers the requests
+		require.NoError(cs.T(), leader.Publish(chain[len(chain)-1], cs.myID))
+		resendRequested()
+		return countSubmitted() == len(chain)
+	}, 10*time.Second, 20*time.Millisecond)
+
+	unittest.RequireCloseBefore(cs.T(), e.Done(), time.Second, "engine should stop")
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, proposal := range chain {
+		assert.Equal(cs.T(), 1, submitted[proposal.Header.ID()], "each proposal should be forwarded to hotstuff exactly once")
+	}
+}
@@ -3,7 +3,10 @@
 package mock
 
 import (
+	consensus "github.com/onflow/flow-go/engine/consensus"
+
 	flow "github.com/onflow/flow-go/model/flow"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -53,3 +56,26 @@ func (_m *SealingCore) ProcessIncorporatedResult(result *flow.IncorporatedResult
 
 	return r0
 }
+
+// SealingProgress provides a mock function with given fields: maxBlocks
+func (_m *SealingCore) SealingProgress(maxBlocks uint) (*consensus.SealingProgress, error) {
+	ret := _m.Called(maxBlocks)
+
+	var r0 *consensus.SealingProgress
+	if rf, ok := ret.Get(0).(func(uint) *consensus.SealingProgress); ok {
+		r0 = rf(maxBlocks)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*consensus.SealingProgress)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(maxBlocks)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
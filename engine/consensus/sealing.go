@@ -22,4 +22,10 @@ type SealingCore interface {
 	// * exception in case of unexpected error
 	// * nil - successfully processed finalized block
 	ProcessFinalizedBlock(finalizedBlockID flow.Identifier) error
+	// SealingProgress returns the progress of sealing the unsealed finalized blocks, covering at most
+	// maxBlocks blocks. Concurrency safe.
+	// Returns:
+	// * exception in case of unexpected error
+	// * the sealing progress otherwise
+	SealingProgress(maxBlocks uint) (*SealingProgress, error)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/consensus"
@@ -37,6 +38,10 @@ const DefaultRequiredApprovalsForSealConstruction = 1
 // to make fire fighting easier while seal & verification is under development.
 const DefaultEmergencySealingActive = false
 
+// DefaultSealingProgressMaxBlocks is the default maximal number of unsealed finalized blocks covered by a
+// sealing progress report.
+const DefaultSealingProgressMaxBlocks = 100
+
 // Config is a structure of values that configure behavior of sealing engine
 type Config struct {
 	EmergencySealingActive               bool   // flag which indicates if emergency sealing is active or not. NOTE: this is temporary while sealing & verification is under development
@@ -74,6 +79,7 @@ type Core struct {
 	metrics                    module.ConsensusMetrics            // used to track consensus metrics
 	sealingTracker             consensus.SealingTracker           // logic-aware component for tracking sealing progress.
 	tracer                     module.Tracer                      // used to trace execution
+	reportingProgress          *atomic.Bool                       // set while the sealing progress is reported to the metrics
 	config                     Config
 }
 
@@ -114,6 +120,7 @@ func NewCore(
 		sealsMempool:               sealsMempool,
 		config:                     config,
 		requestTracker:             approvals.NewRequestTracker(headers, 10, 30),
+		reportingProgress:          atomic.NewBool(false),
 	}
 
 	factoryMethod := func(result *flow.ExecutionResult) (approvals.AssignmentCollector, error) {
@@ -532,9 +539,112 @@ func (c *Core) ProcessFinalizedBlock(finalizedBlockID flow.Identifier) error {
 	//   this function, `sealingObservation` lives solely in the scope of the newly-created goroutine.
 	c.unit.Launch(sealingObservation.Complete)
 
+	// a single report runs at a time, the finalized blocks processed meanwhile are covered by the next report
+	if c.reportingProgress.CAS(false, true) {
+		c.unit.Launch(func() {
+			defer c.reportingProgress.Store(false)
+			c.reportSealingProgress(lastSealed.Height, finalized.Height)
+		})
+	}
+
 	return nil
 }
 
+// SealingProgress returns the progress of sealing the finalized blocks above the last sealed block, covering at
+// most maxBlocks blocks from the block following the last sealed block. Concurrency safe.
+// No errors are expected during normal operations.
+func (c *Core) SealingProgress(maxBlocks uint) (*consensus.SealingProgress, error) {
+	lastSealedHeight := c.counterLastSealedHeight.Value()
+	lastFinalizedHeight := c.counterLastFinalizedHeight.Value()
+	progress, err := c.sealingProgress(lastSealedHeight, lastFinalizedHeight, maxBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("could not collect sealing progress: %w", err)
+	}
+	return progress, nil
+}
+
+// sealingProgress collects the progress of sealing the finalized blocks with height in (lastSealedHeight, lastFinalizedHeight],
+// covering at most maxBlocks blocks. No errors are expected during normal operations.
+func (c *Core) sealingProgress(lastSealedHeight, lastFinalizedHeight uint64, maxBlocks uint) (*consensus.SealingProgress, error) {
+	progress := &consensus.SealingProgress{
+		LastSealedHeight:       lastSealedHeight,
+		LastFinalizedHeight:    lastFinalizedHeight,
+		EmergencySealingActive: c.config.EmergencySealingActive,
+		Blocks:                 []consensus.BlockSealingProgress{},
+	}
+	for height := lastSealedHeight + 1; height <= lastFinalizedHeight; height++ {
+		if uint(len(progress.Blocks)) >= maxBlocks {
+			progress.Truncated = true
+			break
+		}
+		finalized, err := c.headers.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve finalized block at height %d: %w", height, err)
+		}
+		finalizedID := finalized.ID()
+
+		block := consensus.BlockSealingProgress{
+			BlockID: finalizedID,
+			Height:  height,
+			Results: []consensus.ResultSealingProgress{},
+		}
+		// the collectors of results for orphaned blocks at the same height are skipped
+		for _, collector := range c.collectorTree.GetCollectorsAtHeight(height) {
+			if collector.BlockID() != finalizedID {
+				continue
+			}
+			result, err := collector.SealingProgress(lastFinalizedHeight)
+			if err != nil {
+				return nil, fmt.Errorf("could not collect sealing progress of result %v: %w", collector.ResultID(), err)
+			}
+			block.Results = append(block.Results, result)
+		}
+		progress.Blocks = append(progress.Blocks, block)
+	}
+	return progress, nil
+}
+
+// reportSealingProgress reports the progress of sealing the finalized blocks above the last sealed block to
+// the metrics, covering at most DefaultSealingProgressMaxBlocks blocks.
+func (c *Core) reportSealingProgress(lastSealedHeight, lastFinalizedHeight uint64) {
+	progress, err := c.sealingProgress(lastSealedHeight, lastFinalizedHeight, DefaultSealingProgressMaxBlocks)
+	if err != nil {
+		c.log.Error().Err(err).Msg("could not report sealing progress")
+		return
+	}
+
+	blocksWithoutResults := uint(0)
+	chunksWithMissingApprovals := uint(0)
+	outstandingRequests := uint(0)
+	emergencySealableResults := uint(0)
+	for _, block := range progress.Blocks {
+		if len(block.Results) == 0 {
+			blocksWithoutResults++
+		}
+		for _, result := range block.Results {
+			emergencySealable := false
+			for _, incorporatedResult := range result.IncorporatedResults {
+				emergencySealable = emergencySealable || incorporatedResult.EmergencySealable
+				for _, chunk := range incorporatedResult.Chunks {
+					if chunk.Approved {
+						continue
+					}
+					chunksWithMissingApprovals++
+					outstandingRequests += chunk.Requests
+				}
+			}
+			if emergencySealable {
+				emergencySealableResults++
+			}
+		}
+	}
+
+	c.metrics.FinalizedBlocksWithoutResults(blocksWithoutResults)
+	c.metrics.ChunksWithMissingApprovals(chunksWithMissingApprovals)
+	c.metrics.OutstandingApprovalRequests(outstandingRequests)
+	c.metrics.EmergencySealableResults(emergencySealableResults)
+}
+
 // prune updates the AssignmentCollectorTree's knowledge about sealed and finalized blocks.
 // Furthermore, it  removes obsolete entries from AssignmentCollectorTree, RequestTracker
 // and IncorporatedResultSeals mempool.
@@ -210,6 +210,58 @@ func (s *ApprovalProcessingCoreTestSuite) TestProcessFinalizedBlock_CollectorsCl
 	require.Equal(s.T(), uint64(0), s.core.collectorTree.GetSize())
 }
 
+// TestSealingProgress tests that the sealing progress covers the unsealed finalized blocks, lists the results
+// incorporated for them with the approvals collected for their chunks, and is truncated at the given number of blocks
+func (s *ApprovalProcessingCoreTestSuite) TestSealingProgress() {
+	s.SigVerifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	err := s.core.processIncorporatedResult(s.IncorporatedResult)
+	require.NoError(s.T(), err)
+
+	approval := unittest.ResultApprovalFixture(unittest.WithChunk(0),
+		unittest.WithApproverID(s.VerID),
+		unittest.WithBlockID(s.Block.ID()),
+		unittest.WithExecutionResultID(s.IncorporatedResult.Result.ID()))
+	err = s.core.processApproval(approval)
+	require.NoError(s.T(), err)
+
+	s.MarkFinalized(&s.IncorporatedBlock)
+	s.core.counterLastFinalizedHeight.Set(s.IncorporatedBlock.Height)
+
+	progress, err := s.core.SealingProgress(DefaultSealingProgressMaxBlocks)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), s.ParentBlock.Height, progress.LastSealedHeight)
+	require.Equal(s.T(), s.IncorporatedBlock.Height, progress.LastFinalizedHeight)
+	require.False(s.T(), progress.Truncated)
+	require.Len(s.T(), progress.Blocks, 2)
+
+	// the executed block has the incorporated result, which is being verified
+	block := progress.Blocks[0]
+	require.Equal(s.T(), s.Block.ID(), block.BlockID)
+	require.Len(s.T(), block.Results, 1)
+	result := block.Results[0]
+	require.Equal(s.T(), s.IncorporatedResult.Result.ID(), result.ResultID)
+	require.Equal(s.T(), approvals.VerifyingApprovals.String(), result.ProcessingStatus)
+	require.Len(s.T(), result.IncorporatedResults, 1)
+	incorporatedResult := result.IncorporatedResults[0]
+	require.Equal(s.T(), s.IncorporatedBlock.ID(), incorporatedResult.IncorporatedBlockID)
+	require.False(s.T(), incorporatedResult.Sealable)
+	require.Len(s.T(), incorporatedResult.Chunks, s.Chunks.Len())
+	require.Equal(s.T(), uint(1), incorporatedResult.Chunks[0].Approvals)
+	require.NotContains(s.T(), incorporatedResult.Chunks[0].MissingVerifiers, s.VerID)
+
+	// the incorporating block has no results yet
+	require.Equal(s.T(), s.IncorporatedBlock.ID(), progress.Blocks[1].BlockID)
+	require.Empty(s.T(), progress.Blocks[1].Results)
+
+	// the progress is truncated at the given number of blocks
+	progress, err = s.core.SealingProgress(1)
+	require.NoError(s.T(), err)
+	require.True(s.T(), progress.Truncated)
+	require.Len(s.T(), progress.Blocks, 1)
+	require.Equal(s.T(), s.Block.ID(), progress.Blocks[0].BlockID)
+}
+
 // TestProcessIncorporated_ApprovalsBeforeResult tests a scenario when first we have received approvals for unknown
 // execution result and after that we discovered execution result. In this scenario we should be able
 // to create a seal right after discovering execution result since all approvals should be cached.(if cache capacity is big enough)
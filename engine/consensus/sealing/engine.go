@@ -433,6 +433,12 @@ func (e *Engine) OnBlockIncorporated(incorporatedBlockID flow.Identifier) {
 	e.blockIncorporatedNotifier.Notify()
 }
 
+// SealingProgress returns the progress of sealing the unsealed finalized blocks, covering at most maxBlocks
+// blocks. Concurrency safe.
+func (e *Engine) SealingProgress(maxBlocks uint) (*consensus.SealingProgress, error) {
+	return e.core.SealingProgress(maxBlocks)
+}
+
 // processIncorporatedBlock selects receipts that were included into incorporated block and submits them
 // for further processing to sealing core.
 func (e *Engine) processIncorporatedBlock(incorporatedBlockID flow.Identifier) error {
@@ -0,0 +1,55 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SealingProgress is a snapshot of the progress of sealing the finalized blocks above the last sealed block. It
+// captures, for each of these blocks, the results incorporated for it and the approvals collected for their chunks,
+// so that operators can see why the sealing of a block does not progress.
+type SealingProgress struct {
+	LastSealedHeight       uint64                 `json:"last_sealed_height"`
+	LastFinalizedHeight    uint64                 `json:"last_finalized_height"`
+	EmergencySealingActive bool                   `json:"emergency_sealing_active"`
+	Blocks                 []BlockSealingProgress `json:"blocks"` // by increasing height, from the block following the last sealed block
+	Truncated              bool                   `json:"truncated"`
+}
+
+// BlockSealingProgress is the progress of sealing a finalized block. A block without results has not been executed
+// yet, or its receipts have not been incorporated yet.
+type BlockSealingProgress struct {
+	BlockID flow.Identifier         `json:"block_id"`
+	Height  uint64                  `json:"height"`
+	Results []ResultSealingProgress `json:"results"`
+}
+
+// ResultSealingProgress is the progress of sealing an execution result of a block. Approvals are only verified for
+// the results whose previous result is sealed or is being verified, which is captured by the processing status.
+type ResultSealingProgress struct {
+	ResultID            flow.Identifier                     `json:"result_id"`
+	ProcessingStatus    string                              `json:"processing_status"`
+	IncorporatedResults []IncorporatedResultSealingProgress `json:"incorporated_results"`
+}
+
+// IncorporatedResultSealingProgress is the progress of sealing an execution result for the verifier assignment of
+// the block incorporating it. The chunks are only known once the result is being verified.
+type IncorporatedResultSealingProgress struct {
+	IncorporatedBlockID     flow.Identifier        `json:"incorporated_block_id"`
+	IncorporatedBlockHeight uint64                 `json:"incorporated_block_height"`
+	Sealable                bool                   `json:"sealable"`           // all the chunks have the required approvals
+	EmergencySealable       bool                   `json:"emergency_sealable"` // the result is old enough to be emergency sealed
+	Chunks                  []ChunkSealingProgress `json:"chunks"`
+}
+
+// ChunkSealingProgress is the progress of collecting the approvals of a chunk from the verifiers assigned to it.
+type ChunkSealingProgress struct {
+	Index             uint64              `json:"index"`
+	Approvals         uint                `json:"approvals"`
+	RequiredApprovals uint                `json:"required_approvals"`
+	Approved          bool                `json:"approved"`
+	MissingVerifiers  flow.IdentifierList `json:"missing_verifiers"`
+	Requests          uint                `json:"requests"`               // number of approval requests sent to the missing verifiers
+	NextRequest       *time.Time          `json:"next_request,omitempty"` // end of the blackout period before the next request
+}
@@ -11,10 +11,15 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executionfork"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// errExecutionFork is returned by the consistency check when the result executed by the node differs from the
+// sealed result.
+var errExecutionFork = errors.New("execution result is different from the sealed result")
+
 type Engine struct {
 	notifications.NoopConsumer // satisfy the FinalizationConsumer interface
 
@@ -23,6 +28,9 @@ type Engine struct {
 	state     protocol.State
 	execState state.ExecutionState
 	sealsDB   storage.Seals
+	results   storage.ExecutionResults
+	receipts  storage.ExecutionReceipts
+	reporter  *executionfork.Reporter
 }
 
 func New(
@@ -30,6 +38,9 @@ func New(
 	state protocol.State,
 	execState state.ExecutionState,
 	sealsDB storage.Seals,
+	results storage.ExecutionResults,
+	receipts storage.ExecutionReceipts,
+	reporter *executionfork.Reporter,
 ) *Engine {
 	return &Engine{
 		unit:      engine.NewUnit(),
@@ -37,12 +48,15 @@ func New(
 		state:     state,
 		execState: execState,
 		sealsDB:   sealsDB,
+		results:   results,
+		receipts:  receipts,
+		reporter:  reporter,
 	}
 }
 
 func (e *Engine) Ready() <-chan struct{} {
-	// make sure we will run into a crashloop if result gets inconsistent
-	// with sealed result.
+	// make sure we will not execute any block if our result is inconsistent
+	// with the sealed result.
 
 	finalized, err := e.state.Final().Head()
 
@@ -51,6 +65,12 @@ func (e *Engine) Ready() <-chan struct{} {
 	}
 
 	err = e.checkLastSealed(finalized.ID())
+	if errors.Is(err, errExecutionFork) {
+		// the node halts instead of crash-looping, so that operators can get the fork report through the
+		// get-execution-fork admin command, the admin server being started before the engines
+		e.log.Error().Err(err).Msg("execution fork detected on startup, halting until the node is stopped")
+		return make(chan struct{})
+	}
 	if err != nil {
 		e.log.Fatal().Err(err).Msg("execution consistency check failed on startup")
 	}
@@ -100,9 +120,37 @@ func (e *Engine) checkLastSealed(finalizedID flow.Identifier) error {
 			return fmt.Errorf("could not get sealed block when checkLastSealed: %v, err: %w", blockID, err)
 		}
 
-		return fmt.Errorf("execution result is different from the sealed result, height: %v, block_id: %v, sealed_commit: %x, my_commit: %x",
-			sealed.Height, blockID, sealedCommit, mycommit)
+		// the fork is reported, and persisted, before the node crashes, so operators can resolve it
+		err = e.reportFork(sealed, seal.ResultID)
+		if err != nil {
+			e.log.Error().Err(err).Hex("block_id", blockID[:]).Msg("could not report execution fork")
+		}
+
+		return fmt.Errorf("%w, height: %v, block_id: %v, sealed_commit: %x, my_commit: %x",
+			errExecutionFork, sealed.Height, blockID, sealedCommit, mycommit)
+	}
+
+	return nil
+}
+
+// reportFork reports the execution fork between the result executed by this node and the sealed result
+// for the block.
+func (e *Engine) reportFork(sealed *flow.Header, sealedResultID flow.Identifier) error {
+	blockID := sealed.ID()
+	myResult, err := e.results.ByBlockID(blockID)
+	if err != nil {
+		return fmt.Errorf("could not get my execution result: %w", err)
+	}
+	sealedResult, err := e.results.ByID(sealedResultID)
+	if err != nil {
+		return fmt.Errorf("could not get sealed execution result %v: %w", sealedResultID, err)
+	}
+	receipts, err := e.receipts.ByBlockID(blockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not get execution receipts: %w", err)
 	}
 
+	results := []*flow.ExecutionResult{sealedResult, myResult}
+	e.reporter.Report(executionfork.NewReport(sealed, results, receipts))
 	return nil
 }
@@ -51,6 +51,18 @@ type blockComputer struct {
 	log            zerolog.Logger
 	systemChunkCtx fvm.Context
 	committer      ViewCommitter
+	registerOwners bool // collect the owners of the registers written by each transaction
+}
+
+// BlockComputerOption configures a block computer.
+type BlockComputerOption func(*blockComputer)
+
+// WithRegisterOwners makes the block computer collect the owners of the registers written by each transaction in
+// the computation results, to index the transactions by the register owners.
+func WithRegisterOwners() BlockComputerOption {
+	return func(e *blockComputer) {
+		e.registerOwners = true
+	}
 }
 
 func SystemChunkContext(vmCtx fvm.Context, logger zerolog.Logger) fvm.Context {
@@ -72,8 +84,9 @@ func NewBlockComputer(
 	tracer module.Tracer,
 	logger zerolog.Logger,
 	committer ViewCommitter,
+	opts ...BlockComputerOption,
 ) (BlockComputer, error) {
-	return &blockComputer{
+	e := &blockComputer{
 		vm:             vm,
 		vmCtx:          vmCtx,
 		metrics:        metrics,
@@ -81,7 +94,11 @@ func NewBlockComputer(
 		log:            logger,
 		systemChunkCtx: SystemChunkContext(vmCtx, logger),
 		committer:      committer,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -131,6 +148,7 @@ func (e *blockComputer) executeBlock(
 		Events:             make([]flow.EventsList, chunksSize),
 		ServiceEvents:      make(flow.EventsList, 0),
 		TransactionResults: make([]flow.TransactionResult, 0),
+		RegisterOwners:     make([][]flow.Address, 0),
 		StateCommitments:   make([]flow.StateCommitment, 0),
 		Proofs:             make([][]byte, 0),
 	}
@@ -379,6 +397,11 @@ func (e *blockComputer) executeTransaction(
 			Msg("transaction executed successfully")
 	}
 
+	var owners []flow.Address
+	if e.registerOwners {
+		owners = registerOwners(txView)
+	}
+
 	mergeSpan := e.tracer.StartSpanFromParent(txSpan, trace.EXEMergeTransactionView)
 	defer mergeSpan.Finish()
 
@@ -392,6 +415,9 @@ func (e *blockComputer) executeTransaction(
 	res.AddEvents(collectionIndex, tx.Events)
 	res.AddServiceEvents(tx.ServiceEvents)
 	res.AddTransactionResult(&txResult)
+	if e.registerOwners {
+		res.AddRegisterOwners(owners)
+	}
 	res.AddComputationUsed(tx.ComputationUsed)
 
 	e.log.Info().
@@ -404,6 +430,26 @@ func (e *blockComputer) executeTransaction(
 	return nil
 }
 
+// registerOwners returns the accounts owning the registers written in the view.
+func registerOwners(view state.View) []flow.Address {
+	registerIDs, _ := view.RegisterUpdates()
+
+	var owners []flow.Address
+	seen := make(map[string]struct{})
+	for _, registerID := range registerIDs {
+		// the global registers have no owner
+		if len(registerID.Owner) != flow.AddressLength {
+			continue
+		}
+		if _, ok := seen[registerID.Owner]; ok {
+			continue
+		}
+		seen[registerID.Owner] = struct{}{}
+		owners = append(owners, flow.BytesToAddress([]byte(registerID.Owner)))
+	}
+	return owners
+}
+
 type blockCommitter struct {
 	tracer    module.Tracer
 	committer ViewCommitter
@@ -35,6 +35,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	modulemock "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -91,6 +92,79 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 		vm.AssertExpectations(t)
 	})
 
+	t.Run("owners of the written registers are recorded", func(t *testing.T) {
+
+		execCtx := fvm.NewContext(zerolog.Nop())
+
+		owner := flow.HexToAddress("0a")
+		vm := new(computermock.VirtualMachine)
+		vm.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				tx := args[1].(*fvm.TransactionProcedure)
+				view := args[2].(state.View)
+
+				// the transactions of the collection write a register of the owner and a global register
+				if tx.TxIndex < 2 {
+					require.NoError(t, view.Set(string(owner.Bytes()), "", "balance", flow.RegisterValue{1}))
+					require.NoError(t, view.Set(string(owner.Bytes()), string(owner.Bytes()), "key", flow.RegisterValue{1}))
+					require.NoError(t, view.Set("", "", "uuid", flow.RegisterValue{1}))
+				}
+			}).
+			Times(2 * (2 + 1)) // 2 txs in collection + system chunk, executed twice
+
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter(), computer.WithRegisterOwners())
+		require.NoError(t, err)
+
+		authorizer := flow.HexToAddress("0b")
+		block := generateBlockWithVisitor(1, 2, rag, func(body *flow.TransactionBody) {
+			body.ProposalKey = flow.ProposalKey{Address: body.Payer}
+			body.Authorizers = []flow.Address{authorizer}
+		})
+
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return nil, nil
+		})
+
+		result, err := exe.ExecuteBlock(context.Background(), block, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.Len(t, result.RegisterOwners, 2+1) // +1 system chunk tx
+		assert.Equal(t, []flow.Address{owner}, result.RegisterOwners[0])
+		assert.Equal(t, []flow.Address{owner}, result.RegisterOwners[1])
+		assert.Empty(t, result.RegisterOwners[2])
+
+		// the system chunk transaction is not indexed
+		transactions := result.AccountTransactions(false)
+		require.Len(t, transactions, 2*2) // payer and proposer, and authorizer of each tx
+		for _, transaction := range transactions {
+			assert.Equal(t, block.ID(), transaction.BlockID)
+			assert.Equal(t, block.Height(), transaction.BlockHeight)
+			assert.False(t, transaction.Roles.Has(storage.AccountRoleOwner))
+		}
+
+		transactions = result.AccountTransactions(true)
+		require.Len(t, transactions, 2*3)
+		assert.Equal(t, owner, transactions[2].Address)
+		assert.Equal(t, storage.AccountRoleOwner, transactions[2].Roles)
+		assert.Equal(t, uint32(1), transactions[5].TransactionIndex)
+		assert.Equal(t, storage.AccountRolePayer|storage.AccountRoleProposer, transactions[3].Roles)
+		assert.Equal(t, storage.AccountRoleAuthorizer, transactions[4].Roles)
+
+		// the owners are not collected by default
+		exe, err = computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), committer.NewNoopViewCommitter())
+		require.NoError(t, err)
+
+		view = delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return nil, nil
+		})
+		result, err = exe.ExecuteBlock(context.Background(), block, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		assert.Empty(t, result.RegisterOwners)
+		assert.Len(t, result.AccountTransactions(true), 2*2)
+
+		vm.AssertExpectations(t)
+	})
+
 	t.Run("empty block still computes system chunk", func(t *testing.T) {
 
 		execCtx := fvm.NewContext(
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
 
@@ -38,6 +39,7 @@ type ComputationManager interface {
 		view state.View,
 	) (*execution.ComputationResult, error)
 	GetAccount(addr flow.Address, header *flow.Header, view state.View) (*flow.Account, error)
+	SimulateTransaction(tx *flow.TransactionBody, header *flow.Header, view state.View) (*flow.TransactionSimulationResult, error)
 }
 
 var DefaultScriptLogThreshold = 1 * time.Second
@@ -52,8 +54,10 @@ type Manager struct {
 	vmCtx              fvm.Context
 	blockComputer      computer.BlockComputer
 	programsCache      *ProgramsCache
+	programUsage       *ProgramUsage
 	scriptLogThreshold time.Duration
 	uploaders          []uploader.Uploader
+	simulationCtx      fvm.Context
 }
 
 func New(
@@ -68,6 +72,7 @@ func New(
 	committer computer.ViewCommitter,
 	scriptLogThreshold time.Duration,
 	uploaders []uploader.Uploader,
+	opts ...computer.BlockComputerOption,
 ) (*Manager, error) {
 	log := logger.With().Str("engine", "computation").Logger()
 
@@ -78,6 +83,7 @@ func New(
 		tracer,
 		log.With().Str("component", "block_computer").Logger(),
 		committer,
+		opts...,
 	)
 
 	if err != nil {
@@ -98,17 +104,35 @@ func New(
 		vmCtx:              vmCtx,
 		blockComputer:      blockComputer,
 		programsCache:      programsCache,
+		programUsage:       NewProgramUsage(metrics),
 		scriptLogThreshold: scriptLogThreshold,
 		uploaders:          uploaders,
+		simulationCtx: fvm.NewContextFromParent(
+			vmCtx,
+			fvm.WithTransactionProcessors(fvm.NewSimulationTransactionProcessors(log)...),
+		),
 	}
 
 	return &e, nil
 }
 
+// ProgramUsage returns the usage counts of contract programs executed by this manager.
+func (e *Manager) ProgramUsage() *ProgramUsage {
+	return e.programUsage
+}
+
+func (e *Manager) newEmptyPrograms() *programs.Programs {
+	emptyPrograms := programs.NewEmptyPrograms()
+	if e.programUsage != nil {
+		emptyPrograms.WithUsageTracker(e.programUsage)
+	}
+	return emptyPrograms
+}
+
 func (e *Manager) getChildProgramsOrEmpty(blockID flow.Identifier) *programs.Programs {
 	blockPrograms := e.programsCache.Get(blockID)
 	if blockPrograms == nil {
-		return programs.NewEmptyPrograms()
+		return e.newEmptyPrograms()
 	}
 	return blockPrograms.ChildPrograms()
 }
@@ -191,7 +215,7 @@ func (e *Manager) ComputeBlock(
 	fromCache := e.programsCache.Get(block.ParentID())
 
 	if fromCache == nil {
-		blockPrograms = programs.NewEmptyPrograms()
+		blockPrograms = e.newEmptyPrograms()
 	} else {
 		blockPrograms = fromCache.ChildPrograms()
 	}
@@ -252,3 +276,120 @@ func (e *Manager) GetAccount(address flow.Address, blockHeader *flow.Header, vie
 
 	return account, nil
 }
+
+// SimulateTransaction runs the given transaction against the view without verifying
+// its signatures or incrementing the proposal key sequence number. The register
+// updates made by the transaction are reported but never merged back into the view.
+func (e *Manager) SimulateTransaction(txBody *flow.TransactionBody, blockHeader *flow.Header, view state.View) (*flow.TransactionSimulationResult, error) {
+	blockCtx := fvm.NewContextFromParent(e.simulationCtx, fvm.WithBlockHeader(blockHeader))
+
+	programs := e.getChildProgramsOrEmpty(blockHeader.ID())
+
+	tx := fvm.Transaction(txBody, 0)
+	txView := view.NewChild()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				e.log.Error().
+					Hex("tx_id", logging.Entity(txBody)).
+					Interface("recovered", r).
+					Msg("transaction simulation caused runtime panic")
+
+				err = fmt.Errorf("cadence runtime error: %s", r)
+			}
+		}()
+
+		return e.vm.Run(blockCtx, tx, txView, programs)
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction (internal error): %w", err)
+	}
+
+	result := &flow.TransactionSimulationResult{
+		TransactionID:   tx.ID,
+		BlockID:         blockHeader.ID(),
+		Events:          tx.Events,
+		ComputationUsed: tx.ComputationUsed,
+	}
+
+	if tx.Err != nil {
+		// as for the executed transactions, a status code of 1 indicates an error and 0 indicates no error
+		result.StatusCode = 1
+		result.ErrorMessage = tx.Err.Error()
+	}
+
+	registerIDs, values := txView.RegisterUpdates()
+	result.RegisterWrites = make([]flow.RegisterWrite, 0, len(registerIDs))
+	for i, registerID := range registerIDs {
+		result.RegisterWrites = append(result.RegisterWrites, flow.RegisterWrite{
+			RegisterID: registerID,
+			ValueSize:  len(values[i]),
+		})
+	}
+
+	return result, nil
+}
+
+// WarmUpPrograms parses and checks the contracts at the given locations against the view
+// of the given block, and caches the resulting programs for that block, so that executing
+// its children does not have to load them again. Contracts which cannot be loaded are skipped.
+// Programs already cached for the block are left untouched. It returns the number of loaded contracts.
+func (e *Manager) WarmUpPrograms(ctx context.Context, blockHeader *flow.Header, view state.View, locations []common.AddressLocation) (int, error) {
+	blockID := blockHeader.ID()
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(blockHeader))
+
+	// lookups made while warming up must not be counted as usage
+	warmPrograms := programs.NewEmptyPrograms()
+
+	loaded := 0
+	for _, location := range locations {
+		select {
+		case <-ctx.Done():
+			return loaded, ctx.Err()
+		default:
+		}
+
+		code := fmt.Sprintf(
+			"import %s from %s\n\npub fun main() {}",
+			location.Name,
+			location.Address.ShortHexWithPrefix(),
+		)
+		script := fvm.Script([]byte(code))
+
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("cadence runtime error: %s", r)
+				}
+			}()
+
+			return e.vm.Run(blockCtx, script, view.NewChild(), warmPrograms)
+		}()
+		if err != nil {
+			return loaded, fmt.Errorf("failed to load program (%s): %w", location, err)
+		}
+
+		if script.Err != nil {
+			e.log.Warn().
+				Str("location", location.String()).
+				Str("error", script.Err.Error()).
+				Msg("could not load program for warm-up, skipping")
+			continue
+		}
+
+		loaded++
+	}
+
+	// drop the programs of the warm-up scripts themselves
+	warmPrograms.Cleanup(nil)
+
+	if e.programsCache.Get(blockID) == nil {
+		if e.programUsage != nil {
+			warmPrograms.WithUsageTracker(e.programUsage)
+		}
+		e.programsCache.Set(blockID, warmPrograms)
+	}
+
+	return loaded, nil
+}
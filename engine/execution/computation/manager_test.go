@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -280,6 +281,110 @@ func TestExecuteScript_ShortScriptsAreNotLogged(t *testing.T) {
 	require.NotContains(t, buffer.String(), "exceeded threshold")
 }
 
+func TestSimulateTransaction(t *testing.T) {
+
+	logger := zerolog.Nop()
+
+	chain := flow.Mainnet.Chain()
+
+	execCtx := fvm.NewContext(logger, fvm.WithChain(chain))
+
+	rt := fvm.NewInterpreterRuntime()
+
+	vm := fvm.NewVirtualMachine(rt)
+
+	privateKeys, err := testutil.GenerateAccountPrivateKeys(1)
+	require.NoError(t, err)
+
+	ledger := testutil.RootBootstrappedLedger(vm, execCtx)
+	accounts, err := testutil.CreateAccounts(vm, ledger, programs.NewEmptyPrograms(), privateKeys, chain)
+	require.NoError(t, err)
+
+	// neither signed nor using the current sequence number of the proposal key
+	tx := testutil.DeployCounterContractTransaction(accounts[0], chain)
+	tx.SetProposalKey(chain.ServiceAddress(), 0, 42).
+		SetGasLimit(1000).
+		SetPayer(chain.ServiceAddress())
+
+	manager, err := New(logger, metrics.NewNoopCollector(), nil, nil, nil, vm, execCtx, DefaultProgramsCacheSize, committer.NewNoopViewCommitter(), scriptLogThreshold, nil)
+	require.NoError(t, err)
+
+	view := delta.NewView(ledger.Get)
+	header := unittest.BlockHeaderFixture()
+
+	result, err := manager.SimulateTransaction(tx, &header, view)
+	require.NoError(t, err)
+
+	assert.False(t, result.Failed(), result.ErrorMessage)
+	assert.Equal(t, uint(0), result.StatusCode)
+	assert.Equal(t, tx.ID(), result.TransactionID)
+	assert.Equal(t, header.ID(), result.BlockID)
+	assert.NotEmpty(t, result.Events)
+	assert.NotEmpty(t, result.RegisterWrites)
+
+	// nothing is committed to the view the transaction was simulated against
+	updatedIDs, _ := view.RegisterUpdates()
+	assert.Empty(t, updatedIDs)
+}
+
+func TestWarmUpPrograms(t *testing.T) {
+
+	logger := zerolog.Nop()
+
+	chain := flow.Mainnet.Chain()
+
+	execCtx := fvm.NewContext(logger, fvm.WithChain(chain))
+
+	rt := fvm.NewInterpreterRuntime()
+
+	vm := fvm.NewVirtualMachine(rt)
+
+	ledger := testutil.RootBootstrappedLedger(vm, execCtx)
+
+	manager, err := New(logger, metrics.NewNoopCollector(), nil, nil, nil, vm, execCtx, DefaultProgramsCacheSize, committer.NewNoopViewCommitter(), scriptLogThreshold, nil)
+	require.NoError(t, err)
+
+	fungibleToken := common.AddressLocation{
+		Address: common.BytesToAddress(fvm.FungibleTokenAddress(chain).Bytes()),
+		Name:    "FungibleToken",
+	}
+	missing := common.AddressLocation{
+		Address: common.BytesToAddress(fvm.FungibleTokenAddress(chain).Bytes()),
+		Name:    "Missing",
+	}
+
+	view := delta.NewView(ledger.Get)
+	header := unittest.BlockHeaderFixture()
+
+	loaded, err := manager.WarmUpPrograms(context.Background(), &header, view, []common.AddressLocation{missing, fungibleToken})
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded)
+
+	// lookups made during the warm-up are not counted, lookups made afterwards are
+	assert.Empty(t, manager.ProgramUsage().Hottest(10))
+
+	blockPrograms := manager.programsCache.Get(header.ID())
+	require.NotNil(t, blockPrograms)
+
+	program, _, has := blockPrograms.Get(fungibleToken)
+	assert.True(t, has)
+	assert.NotNil(t, program)
+
+	script := []byte(fmt.Sprintf(
+		`
+			import FungibleToken from %s
+
+			pub fun main() {}
+		`,
+		fvm.FungibleTokenAddress(execCtx.Chain).HexWithPrefix(),
+	))
+
+	_, err = manager.ExecuteScript(script, nil, &header, view.NewChild())
+	require.NoError(t, err)
+
+	assert.Equal(t, []common.AddressLocation{fungibleToken}, manager.ProgramUsage().Hottest(10))
+}
+
 type PanickingVM struct{}
 
 func (p *PanickingVM) Run(f fvm.Context, procedure fvm.Procedure, view state.View, p2 *programs.Programs) error {
@@ -88,3 +88,26 @@ func (_m *ComputationManager) GetAccount(addr flow.Address, header *flow.Header,
 
 	return r0, r1
 }
+
+// SimulateTransaction provides a mock function with given fields: tx, header, view
+func (_m *ComputationManager) SimulateTransaction(tx *flow.TransactionBody, header *flow.Header, view state.View) (*flow.TransactionSimulationResult, error) {
+	ret := _m.Called(tx, header, view)
+
+	var r0 *flow.TransactionSimulationResult
+	if rf, ok := ret.Get(0).(func(*flow.TransactionBody, *flow.Header, state.View) *flow.TransactionSimulationResult); ok {
+		r0 = rf(tx, header, view)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TransactionSimulationResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*flow.TransactionBody, *flow.Header, state.View) error); ok {
+		r1 = rf(tx, header, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,191 @@
+package computation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/onflow/cadence/runtime/common"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// ProgramUsage counts the lookups of contract programs during execution, so the
+// most used contracts can be loaded into the programs cache when the node restarts.
+// The lookups of the contracts already counted only take a read lock, as they happen
+// concurrently during execution.
+type ProgramUsage struct {
+	lock     sync.RWMutex
+	metrics  module.ExecutionMetrics
+	counts   map[common.LocationID]*programCounter
+	warmedUp *atomic.Bool
+}
+
+var _ programs.UsageTracker = (*ProgramUsage)(nil)
+
+// programUsageEntry is the persisted usage count of a single contract.
+type programUsageEntry struct {
+	Address flow.Address `json:"address"`
+	Name    string       `json:"name"`
+	Count   uint64       `json:"count"`
+}
+
+// programCounter is the usage count of a single contract, incremented atomically.
+type programCounter struct {
+	location common.AddressLocation
+	count    *atomic.Uint64
+}
+
+func (e *programUsageEntry) location() common.AddressLocation {
+	return common.AddressLocation{
+		Address: common.BytesToAddress(e.Address.Bytes()),
+		Name:    e.Name,
+	}
+}
+
+func NewProgramUsage(metrics module.ExecutionMetrics) *ProgramUsage {
+	return &ProgramUsage{
+		metrics:  metrics,
+		counts:   make(map[common.LocationID]*programCounter),
+		warmedUp: atomic.NewBool(false),
+	}
+}
+
+// ProgramUsed records a lookup of the contract program at the given location.
+func (u *ProgramUsage) ProgramUsed(location common.AddressLocation, cached bool) {
+	u.metrics.ExecutionProgramsCacheLookup(cached, u.warmedUp.Load())
+
+	u.counter(location).count.Inc()
+}
+
+// counter returns the usage counter of the contract at the given location, creating it if needed.
+func (u *ProgramUsage) counter(location common.AddressLocation) *programCounter {
+	id := location.ID()
+
+	u.lock.RLock()
+	counter, ok := u.counts[id]
+	u.lock.RUnlock()
+	if ok {
+		return counter
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	counter, ok = u.counts[id]
+	if !ok {
+		counter = &programCounter{
+			location: location,
+			count:    atomic.NewUint64(0),
+		}
+		u.counts[id] = counter
+	}
+	return counter
+}
+
+// SetWarmedUp marks the end of the programs cache warm-up. Lookups recorded afterwards
+// are reported separately, so the hit rate before and after the warm-up can be compared.
+func (u *ProgramUsage) SetWarmedUp() {
+	u.warmedUp.Store(true)
+}
+
+// Hottest returns at most n contract locations, ordered by descending usage.
+// Locations with the same usage are ordered by their location ID.
+func (u *ProgramUsage) Hottest(n uint) []common.AddressLocation {
+	entries := u.hottest(n)
+
+	locations := make([]common.AddressLocation, 0, len(entries))
+	for _, entry := range entries {
+		locations = append(locations, entry.location())
+	}
+	return locations
+}
+
+func (u *ProgramUsage) hottest(n uint) []programUsageEntry {
+	u.lock.RLock()
+	entries := make([]programUsageEntry, 0, len(u.counts))
+	for _, counter := range u.counts {
+		entries = append(entries, programUsageEntry{
+			Address: flow.BytesToAddress(counter.location.Address.Bytes()),
+			Name:    counter.location.Name,
+			Count:   counter.count.Load(),
+		})
+	}
+	u.lock.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].location().ID() < entries[j].location().ID()
+	})
+
+	if uint(len(entries)) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// Store writes the usage counts of the n most used contracts to the given file.
+// The file is replaced atomically, so a crash never leaves a partially written file.
+func (u *ProgramUsage) Store(path string, n uint) error {
+	data, err := json.Marshal(u.hottest(n))
+	if err != nil {
+		return fmt.Errorf("could not encode program usage: %w", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary program usage file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("could not write program usage: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary program usage file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("could not replace program usage file: %w", err)
+	}
+
+	return nil
+}
+
+// Load adds the usage counts stored in the given file to the current counts.
+// A missing file is not an error, as no usage has been recorded yet.
+func (u *ProgramUsage) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read program usage file: %w", err)
+	}
+
+	var entries []programUsageEntry
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return fmt.Errorf("could not decode program usage file: %w", err)
+	}
+
+	for _, stored := range entries {
+		u.counter(stored.location()).count.Add(stored.Count)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package computation
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestProgramUsage(t *testing.T) {
+
+	first := common.AddressLocation{Address: common.BytesToAddress([]byte{1}), Name: "First"}
+	second := common.AddressLocation{Address: common.BytesToAddress([]byte{2}), Name: "Second"}
+	third := common.AddressLocation{Address: common.BytesToAddress([]byte{3}), Name: "Third"}
+
+	t.Run("hottest locations are ordered by usage", func(t *testing.T) {
+		usage := NewProgramUsage(metrics.NewNoopCollector())
+
+		usage.ProgramUsed(third, false)
+		usage.ProgramUsed(second, false)
+		usage.ProgramUsed(second, true)
+		usage.ProgramUsed(first, false)
+		usage.ProgramUsed(first, true)
+		usage.ProgramUsed(first, true)
+
+		assert.Equal(t, []common.AddressLocation{first, second, third}, usage.Hottest(10))
+		assert.Equal(t, []common.AddressLocation{first, second}, usage.Hottest(2))
+		assert.Empty(t, usage.Hottest(0))
+	})
+
+	t.Run("ties are ordered by location", func(t *testing.T) {
+		usage := NewProgramUsage(metrics.NewNoopCollector())
+
+		usage.ProgramUsed(third, false)
+		usage.ProgramUsed(first, false)
+		usage.ProgramUsed(second, false)
+
+		assert.Equal(t, []common.AddressLocation{first, second, third}, usage.Hottest(3))
+	})
+
+	t.Run("store and load", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			path := filepath.Join(dir, "usage.json")
+
+			usage := NewProgramUsage(metrics.NewNoopCollector())
+			usage.ProgramUsed(first, false)
+			usage.ProgramUsed(second, false)
+			usage.ProgramUsed(second, true)
+			usage.ProgramUsed(third, false)
+
+			// only the two hottest locations are stored
+			err := usage.Store(path, 2)
+			require.NoError(t, err)
+
+			loaded := NewProgramUsage(metrics.NewNoopCollector())
+			loaded.ProgramUsed(first, false)
+			loaded.ProgramUsed(first, true)
+
+			err = loaded.Load(path)
+			require.NoError(t, err)
+
+			// first has been used 3 times, second 2 times
+			assert.Equal(t, []common.AddressLocation{first, second}, loaded.Hottest(10))
+		})
+	})
+
+	t.Run("loading a missing file", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			usage := NewProgramUsage(metrics.NewNoopCollector())
+
+			err := usage.Load(filepath.Join(dir, "missing.json"))
+			require.NoError(t, err)
+			assert.Empty(t, usage.Hottest(10))
+		})
+	})
+
+	t.Run("concurrent lookups are all counted", func(t *testing.T) {
+		usage := NewProgramUsage(metrics.NewNoopCollector())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					usage.ProgramUsed(first, true)
+					usage.ProgramUsed(second, true)
+				}
+				usage.ProgramUsed(first, true)
+			}()
+		}
+		wg.Wait()
+
+		entries := usage.hottest(10)
+		require.Len(t, entries, 2)
+		assert.Equal(t, uint64(808), entries[0].Count)
+		assert.Equal(t, uint64(800), entries[1].Count)
+	})
+}
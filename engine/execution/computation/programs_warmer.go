@@ -0,0 +1,138 @@
+package computation
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// DefaultProgramsWarmUpSize is the default maximum number of contracts loaded into
+// the programs cache on startup.
+const DefaultProgramsWarmUpSize = 100
+
+// programUsageStoreInterval is the interval at which the usage of contracts is persisted.
+const programUsageStoreInterval = 10 * time.Minute
+
+// ProgramsWarmer loads the most used contracts into the programs cache when the node starts,
+// and persists the usage of contracts while it is running, so the next start can do the same.
+type ProgramsWarmer struct {
+	unit      *engine.Unit
+	log       zerolog.Logger
+	metrics   module.ExecutionMetrics
+	manager   *Manager
+	execState state.ReadOnlyExecutionState
+	headers   storage.Headers
+	path      string // file storing the usage of contracts
+	size      uint   // maximum number of contracts to load
+}
+
+func NewProgramsWarmer(
+	log zerolog.Logger,
+	metrics module.ExecutionMetrics,
+	manager *Manager,
+	execState state.ReadOnlyExecutionState,
+	headers storage.Headers,
+	path string,
+	size uint,
+) *ProgramsWarmer {
+	return &ProgramsWarmer{
+		unit:      engine.NewUnit(),
+		log:       log.With().Str("component", "programs_warmer").Logger(),
+		metrics:   metrics,
+		manager:   manager,
+		execState: execState,
+		headers:   headers,
+		path:      path,
+		size:      size,
+	}
+}
+
+// Ready starts loading the most used contracts in the background and returns
+// immediately, so the warm-up never delays the startup of the node.
+func (w *ProgramsWarmer) Ready() <-chan struct{} {
+	if w.size > 0 {
+		w.unit.Launch(w.warmUp)
+		w.unit.LaunchPeriodically(w.storeUsage, programUsageStoreInterval, programUsageStoreInterval)
+	}
+	return w.unit.Ready()
+}
+
+// Done persists the usage of contracts and returns a channel that is closed once
+// the warm-up has stopped.
+func (w *ProgramsWarmer) Done() <-chan struct{} {
+	return w.unit.Done(func() {
+		if w.size > 0 {
+			w.storeUsage()
+		}
+	})
+}
+
+func (w *ProgramsWarmer) warmUp() {
+	// the usage is considered warmed up whatever the outcome, so lookups recorded
+	// from now on are attributed to the warmed up cache
+	defer w.manager.ProgramUsage().SetWarmedUp()
+
+	usage := w.manager.ProgramUsage()
+	err := usage.Load(w.path)
+	if err != nil {
+		w.log.Error().Err(err).Str("path", w.path).Msg("could not load program usage, skipping warm-up")
+		return
+	}
+
+	locations := usage.Hottest(w.size)
+	if len(locations) == 0 {
+		w.log.Info().Msg("no program usage recorded, skipping warm-up")
+		return
+	}
+
+	ctx := w.unit.Ctx()
+
+	_, blockID, err := w.execState.GetHighestExecutedBlockID(ctx)
+	if err != nil {
+		w.log.Error().Err(err).Msg("could not get highest executed block, skipping warm-up")
+		return
+	}
+
+	header, err := w.headers.ByBlockID(blockID)
+	if err != nil {
+		w.log.Error().Err(err).Hex("block_id", logging.ID(blockID)).Msg("could not get highest executed block header, skipping warm-up")
+		return
+	}
+
+	commit, err := w.execState.StateCommitmentByBlockID(ctx, blockID)
+	if err != nil {
+		w.log.Error().Err(err).Hex("block_id", logging.ID(blockID)).Msg("could not get highest executed state commitment, skipping warm-up")
+		return
+	}
+
+	start := time.Now()
+	loaded, err := w.manager.WarmUpPrograms(ctx, header, w.execState.NewView(commit), locations)
+	duration := time.Since(start)
+	if err != nil {
+		w.log.Error().Err(err).Int("loaded", loaded).Msg("could not warm up programs cache")
+		return
+	}
+
+	w.metrics.ExecutionProgramsWarmedUp(loaded, duration)
+
+	w.log.Info().
+		Hex("block_id", logging.ID(blockID)).
+		Uint64("height", header.Height).
+		Int("requested", len(locations)).
+		Int("loaded", loaded).
+		Dur("duration", duration).
+		Msg("programs cache warmed up")
+}
+
+func (w *ProgramsWarmer) storeUsage() {
+	err := w.manager.ProgramUsage().Store(w.path, w.size)
+	if err != nil {
+		w.log.Error().Err(err).Str("path", w.path).Msg("could not store program usage")
+	}
+}
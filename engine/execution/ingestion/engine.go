@@ -65,6 +65,8 @@ type Engine struct {
 	syncFast           bool                // sync fast allows execution node to skip fetching collection during state syncing, and rely on state syncing to catch up
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error)
 	pauseExecution     bool
+	indexAccounts      bool // index the transactions by the accounts they touch
+	indexOwners        bool // index the transactions by the owners of the registers they write as well
 }
 
 func New(
@@ -90,6 +92,8 @@ func New(
 	syncFast bool,
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error),
 	pauseExecution bool,
+	indexAccounts bool,
+	indexOwners bool,
 ) (*Engine, error) {
 	log := logger.With().Str("engine", "ingestion").Logger()
 
@@ -121,6 +125,8 @@ func New(
 		syncFast:           syncFast,
 		checkStakedAtBlock: checkStakedAtBlock,
 		pauseExecution:     pauseExecution,
+		indexAccounts:      indexAccounts,
+		indexOwners:        indexOwners,
 	}
 
 	// move to state syncing engine
@@ -190,6 +196,32 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 	return nil
 }
 
+// sealedRoot returns the block sealed as of the root block, whose execution state the node is
+// bootstrapped with. For a spork root snapshot, this is the root block itself. Otherwise, the
+// blocks of the root sealing segment after the sealed root are executed after bootstrapping.
+func (e *Engine) sealedRoot() (*flow.Header, error) {
+	rootBlock, err := e.state.Params().Root()
+	if err != nil {
+		return nil, fmt.Errorf("could not get root block: %w", err)
+	}
+
+	_, seal, err := e.state.AtBlockID(rootBlock.ID()).SealedResult()
+	if err != nil {
+		return nil, fmt.Errorf("could not get root seal: %w", err)
+	}
+
+	if seal.BlockID == rootBlock.ID() {
+		return rootBlock, nil
+	}
+
+	sealedRoot, err := e.state.AtBlockID(seal.BlockID).Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed root block: %w", err)
+	}
+
+	return sealedRoot, nil
+}
+
 func (e *Engine) finalizedUnexecutedBlocks(finalized protocol.Snapshot) ([]flow.Identifier, error) {
 	// get finalized height
 	final, err := finalized.Head()
@@ -207,12 +239,12 @@ func (e *Engine) finalizedUnexecutedBlocks(finalized protocol.Snapshot) ([]flow.
 	// blocks.
 	lastExecuted := final.Height
 
-	rootBlock, err := e.state.Params().Root()
+	sealedRoot, err := e.sealedRoot()
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve root block: %w", err)
+		return nil, fmt.Errorf("failed to retrieve sealed root block: %w", err)
 	}
 
-	for ; lastExecuted > rootBlock.Height; lastExecuted-- {
+	for ; lastExecuted > sealedRoot.Height; lastExecuted-- {
 		header, err := e.state.AtHeight(lastExecuted).Head()
 		if err != nil {
 			return nil, fmt.Errorf("could not get header at height: %v, %w", lastExecuted, err)
@@ -322,12 +354,12 @@ func (e *Engine) reloadUnexecutedBlocks() error {
 		}
 
 		// don't reload root block
-		rootBlock, err := e.state.Params().Root()
+		sealedRoot, err := e.sealedRoot()
 		if err != nil {
-			return fmt.Errorf("failed to retrieve root block: %w", err)
+			return fmt.Errorf("failed to retrieve sealed root block: %w", err)
 		}
 
-		isRoot := rootBlock.ID() == last.ID()
+		isRoot := sealedRoot.ID() == last.ID()
 		if !isRoot {
 			executed, err := state.IsBlockExecuted(e.unit.Ctx(), e.execState, lastExecutedID)
 			if err != nil {
@@ -1055,6 +1087,37 @@ func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow
 	return e.computationManager.GetAccount(addr, block, blockView)
 }
 
+// SimulateTransaction runs the given transaction against the execution state of the
+// latest sealed block, without verifying its signatures or incrementing the proposal
+// key sequence number. None of the changes made by the transaction are committed.
+func (e *Engine) SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*flow.TransactionSimulationResult, error) {
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest sealed block: %w", err)
+	}
+
+	blockID := sealed.ID()
+
+	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+	}
+
+	blockView := e.execState.NewView(stateCommit)
+
+	if e.extensiveLogging {
+		e.log.Debug().
+			Hex("block_id", logging.ID(blockID)).
+			Uint64("block_height", sealed.Height).
+			Hex("state_commitment", stateCommit[:]).
+			Hex("tx_id", logging.Entity(tx)).
+			Hex("script_hex", tx.Script).
+			Msg("extensive log: simulated transaction content")
+	}
+
+	return e.computationManager.SimulateTransaction(tx, sealed, blockView)
+}
+
 func (e *Engine) handleComputationResult(
 	ctx context.Context,
 	result *execution.ComputationResult,
@@ -1122,6 +1185,11 @@ func (e *Engine) saveExecutionResults(
 		return nil, fmt.Errorf("could not generate execution receipt: %w", err)
 	}
 
+	var accountTransactions []storage.AccountTransaction
+	if e.indexAccounts {
+		accountTransactions = result.AccountTransactions(e.indexOwners)
+	}
+
 	err = e.execState.SaveExecutionResults(childCtx,
 		block.Header,
 		endState,
@@ -1129,7 +1197,8 @@ func (e *Engine) saveExecutionResults(
 		executionReceipt,
 		result.Events,
 		result.ServiceEvents,
-		result.TransactionResults)
+		result.TransactionResults,
+		accountTransactions)
 	if err != nil {
 		return nil, fmt.Errorf("cannot persist execution state: %w", err)
 	}
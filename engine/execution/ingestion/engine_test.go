@@ -171,6 +171,8 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		false,
 		checkStakedAtBlock,
 		false,
+		false,
+		false,
 	)
 	require.NoError(t, err)
 
@@ -248,6 +250,7 @@ func (ctx *testingContext) assertSuccessfulBlockComputation(
 			mock.Anything,
 			mock.Anything,
 			mock.Anything,
+			mock.Anything,
 		).
 		Return(nil)
 
@@ -549,6 +552,8 @@ func Test_OnlyHeadOfTheQueueIsExecuted(t *testing.T) {
 
 		ctx.state.On("Params").Return(params)
 		params.On("Root").Return(&blockA, nil)
+		ctx.state.On("AtBlockID", blockA.ID()).Return(blockASnapshot)
+		blockASnapshot.On("SealedResult").Return(nil, &flow.Seal{BlockID: blockA.ID()}, nil)
 
 		<-ctx.engine.Ready()
 
@@ -901,7 +906,7 @@ func TestExecutionGenerationResultsAreChained(t *testing.T) {
 		Return(previousExecutionResultID, nil)
 
 	execState.
-		On("SaveExecutionResults", mock.Anything, executableBlock.Block.Header, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		On("SaveExecutionResults", mock.Anything, executableBlock.Block.Header, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(nil)
 
 	e := Engine{
@@ -1177,6 +1182,8 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 		false,
 		checkStakedAtBlock,
 		false,
+		false,
+		false,
 	)
 
 	require.NoError(t, err)
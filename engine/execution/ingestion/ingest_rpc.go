@@ -14,4 +14,7 @@ type IngestRPC interface {
 
 	// GetAccount returns the Account details at the given Block id
 	GetAccount(ctx context.Context, address flow.Address, blockID flow.Identifier) (*flow.Account, error)
+
+	// SimulateTransaction runs a transaction against the latest sealed state without committing it
+	SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*flow.TransactionSimulationResult, error)
 }
@@ -60,3 +60,26 @@ func (_m *IngestRPC) GetAccount(ctx context.Context, address flow.Address, block
 
 	return r0, r1
 }
+
+// SimulateTransaction provides a mock function with given fields: ctx, tx
+func (_m *IngestRPC) SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*flow.TransactionSimulationResult, error) {
+	ret := _m.Called(ctx, tx)
+
+	var r0 *flow.TransactionSimulationResult
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody) *flow.TransactionSimulationResult); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TransactionSimulationResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *flow.TransactionBody) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/storage"
 )
 
 // TODO If the executor will be a separate process/machine we would need to rework
@@ -25,6 +26,7 @@ type ComputationResult struct {
 	EventsHashes       []flow.Identifier
 	ServiceEvents      flow.EventsList
 	TransactionResults []flow.TransactionResult
+	RegisterOwners     [][]flow.Address // the accounts owning the registers written by each transaction, if collected
 	ComputationUsed    uint64
 	StateReads         uint64
 	TrieUpdates        []*ledger.TrieUpdate
@@ -42,6 +44,10 @@ func (cr *ComputationResult) AddTransactionResult(inp *flow.TransactionResult) {
 	cr.TransactionResults = append(cr.TransactionResults, *inp)
 }
 
+func (cr *ComputationResult) AddRegisterOwners(inp []flow.Address) {
+	cr.RegisterOwners = append(cr.RegisterOwners, inp)
+}
+
 func (cr *ComputationResult) AddComputationUsed(inp uint64) {
 	cr.ComputationUsed += inp
 }
@@ -49,3 +55,25 @@ func (cr *ComputationResult) AddComputationUsed(inp uint64) {
 func (cr *ComputationResult) AddStateSnapshot(inp *delta.SpockSnapshot) {
 	cr.StateSnapshots = append(cr.StateSnapshots, inp)
 }
+
+// AccountTransactions returns the accounts touched by the transactions of the block as payer, proposer or
+// authorizer, and as owner of the registers they wrote if includeOwners is set. The system transaction,
+// which is not part of the block, is skipped.
+func (cr *ComputationResult) AccountTransactions(includeOwners bool) []storage.AccountTransaction {
+	header := cr.ExecutableBlock.Block.Header
+	blockID := header.ID()
+
+	var transactions []storage.AccountTransaction
+	var txIndex uint32
+	for _, collection := range cr.ExecutableBlock.Collections() {
+		for _, tx := range collection.Transactions {
+			var owners []flow.Address
+			if includeOwners && int(txIndex) < len(cr.RegisterOwners) {
+				owners = cr.RegisterOwners[txIndex]
+			}
+			transactions = append(transactions, storage.TransactionAccounts(blockID, header.Height, txIndex, tx, owners)...)
+			txIndex++
+		}
+	}
+	return transactions
+}
@@ -0,0 +1,243 @@
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// MinRetainHeights is the minimum number of the latest sealed heights whose artifacts are retained, as a safety
+// margin for the verification nodes and the execution nodes still requesting them.
+const MinRetainHeights = 1000
+
+// DefaultBatchSize is the default number of heights whose artifacts are removed in a single write batch.
+const DefaultBatchSize = 100
+
+// DefaultInterval is the default interval between two pruning rounds.
+const DefaultInterval = time.Minute
+
+// Config is the configuration of the pruner.
+type Config struct {
+	RetainHeights uint64        // number of the latest sealed heights whose artifacts are retained
+	BatchSize     uint          // number of heights whose artifacts are removed in a single write batch
+	Interval      time.Duration // interval between two pruning rounds
+}
+
+// Pruner removes the chunk data packs, events, transaction results and execution state interactions of the
+// finalized blocks which are sealed and executed, except for the latest heights which are retained. The height
+// up to which the blocks were pruned is persisted, so that the pruning resumes from there after a restart.
+//
+// The artifacts of the blocks which were executed but not finalized are not pruned.
+type Pruner struct {
+	unit      *engine.Unit
+	log       zerolog.Logger
+	metrics   module.ExecutionMetrics
+	db        kv.DB
+	state     protocol.State
+	execState state.ReadOnlyExecutionState
+	headers   storage.Headers
+	results   storage.ExecutionResults
+	progress  storage.ConsumerProgress
+	config    Config
+}
+
+// New returns a pruner of the artifacts of the sealed blocks. The pruning starts above the root block of the
+// protocol state if it was never run before.
+func New(
+	log zerolog.Logger,
+	metrics module.ExecutionMetrics,
+	db kv.DB,
+	state protocol.State,
+	execState state.ReadOnlyExecutionState,
+	headers storage.Headers,
+	results storage.ExecutionResults,
+	progress storage.ConsumerProgress,
+	config Config,
+) (*Pruner, error) {
+	if config.RetainHeights < MinRetainHeights {
+		return nil, fmt.Errorf("retained heights (%d) must be at least %d", config.RetainHeights, MinRetainHeights)
+	}
+	if config.BatchSize == 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+
+	root, err := state.Params().Root()
+	if err != nil {
+		return nil, fmt.Errorf("could not get root block: %w", err)
+	}
+	err = progress.InitProcessedIndex(root.Height)
+	if err != nil && !errors.Is(err, storage.ErrAlreadyExists) {
+		return nil, fmt.Errorf("could not initialize pruned height: %w", err)
+	}
+
+	return &Pruner{
+		unit:      engine.NewUnit(),
+		log:       log.With().Str("component", "pruner").Logger(),
+		metrics:   metrics,
+		db:        db,
+		state:     state,
+		execState: execState,
+		headers:   headers,
+		results:   results,
+		progress:  progress,
+		config:    config,
+	}, nil
+}
+
+// Ready starts pruning periodically in the background.
+func (p *Pruner) Ready() <-chan struct{} {
+	p.unit.LaunchPeriodically(p.prune, p.config.Interval, 0)
+	return p.unit.Ready()
+}
+
+// Done stops the pruning, once the current batch is written.
+func (p *Pruner) Done() <-chan struct{} {
+	return p.unit.Done()
+}
+
+func (p *Pruner) prune() {
+	err := p.pruneSealed()
+	if err != nil {
+		p.log.Error().Err(err).Msg("could not prune sealed blocks")
+	}
+}
+
+// pruneSealed prunes the blocks up to the highest height which is sealed, executed and not retained.
+func (p *Pruner) pruneSealed() error {
+	sealed, err := p.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+	if sealed.Height <= p.config.RetainHeights {
+		return nil
+	}
+	limit := sealed.Height - p.config.RetainHeights
+
+	executed, _, err := p.execState.GetHighestExecutedBlockID(p.unit.Ctx())
+	if err != nil {
+		return fmt.Errorf("could not get highest executed block: %w", err)
+	}
+	if executed < limit {
+		limit = executed
+	}
+
+	pruned, err := p.progress.ProcessedIndex()
+	if err != nil {
+		return fmt.Errorf("could not get pruned height: %w", err)
+	}
+
+	for pruned < limit {
+		end := pruned + uint64(p.config.BatchSize)
+		if end > limit {
+			end = limit
+		}
+
+		err = p.pruneHeights(pruned+1, end)
+		if err != nil {
+			return fmt.Errorf("could not prune heights %d to %d: %w", pruned+1, end, err)
+		}
+		pruned = end
+
+		select {
+		case <-p.unit.Quit():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+// pruneHeights removes the artifacts of the finalized blocks between the given heights (inclusive) in a single
+// write batch, and persists the end height as the pruned height.
+func (p *Pruner) pruneHeights(startHeight, endHeight uint64) error {
+	started := time.Now()
+
+	blockIDs := make([]flow.Identifier, 0, endHeight-startHeight+1)
+	var chunkIDs []flow.Identifier
+	for height := startHeight; height <= endHeight; height++ {
+		header, err := p.headers.ByHeight(height)
+		if err != nil {
+			return fmt.Errorf("could not get block at height %d: %w", height, err)
+		}
+		blockID := header.ID()
+		blockIDs = append(blockIDs, blockID)
+
+		result, err := p.results.ByBlockID(blockID)
+		if err != nil {
+			return fmt.Errorf("could not get execution result of block %x: %w", blockID, err)
+		}
+		for _, chunk := range result.Chunks {
+			chunkIDs = append(chunkIDs, chunk.ID())
+		}
+	}
+
+	// the chunk data packs and transaction results which are still cached are evicted as newer ones are cached,
+	// since the pruned blocks are far behind the executed ones
+	batch := badgerstorage.NewBatch(p.db)
+	writeBatch := batch.GetWriter()
+	var reclaimed uint64
+	err := p.db.View(func(tx kv.Txn) error {
+		for _, chunkID := range chunkIDs {
+			err := operation.BatchRemoveChunkDataPack(chunkID, writeBatch, &reclaimed)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove chunk data pack %x: %w", chunkID, err)
+			}
+		}
+
+		for _, blockID := range blockIDs {
+			err := operation.BatchRemoveEventsByBlockID(blockID, writeBatch, &reclaimed)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove events of block %x: %w", blockID, err)
+			}
+
+			err = operation.BatchRemoveTransactionResultsByBlockID(blockID, writeBatch, &reclaimed)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove transaction results of block %x: %w", blockID, err)
+			}
+
+			err = operation.BatchRemoveExecutionStateInteractions(blockID, writeBatch, &reclaimed)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove execution state interactions of block %x: %w", blockID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush pruning batch: %w", err)
+	}
+
+	err = p.progress.SetProcessedIndex(endHeight)
+	if err != nil {
+		return fmt.Errorf("could not update pruned height: %w", err)
+	}
+
+	duration := time.Since(started)
+	p.metrics.ExecutionDataPruned(endHeight, reclaimed, duration)
+	p.log.Debug().
+		Uint64("start_height", startHeight).
+		Uint64("end_height", endHeight).
+		Uint64("reclaimed_bytes", reclaimed).
+		Dur("duration", duration).
+		Msg("pruned sealed blocks")
+
+	return nil
+}
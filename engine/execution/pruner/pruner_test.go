@@ -0,0 +1,196 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	statemock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	badgermodel "github.com/onflow/flow-go/storage/badger/model"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// blockArtifacts are the artifacts stored for an executed block.
+type blockArtifacts struct {
+	header *flow.Header
+	result *flow.ExecutionResult
+	event  flow.Event
+	txID   flow.Identifier
+}
+
+func storeArtifacts(t *testing.T, db kv.DB, artifacts blockArtifacts) {
+	blockID := artifacts.header.ID()
+	for _, chunk := range artifacts.result.Chunks {
+		require.NoError(t, db.Update(operation.InsertChunkDataPack(&badgermodel.StoredChunkDataPack{ChunkID: chunk.ID()})))
+	}
+	require.NoError(t, db.Update(operation.InsertEvent(blockID, artifacts.event)))
+	require.NoError(t, db.Update(operation.InsertTransactionResult(blockID, &flow.TransactionResult{TransactionID: artifacts.txID})))
+	require.NoError(t, db.Update(operation.InsertExecutionStateInteractions(blockID, []*delta.Snapshot{})))
+
+	batch := badgerstorage.NewBatch(db)
+	require.NoError(t, operation.BatchIndexEventByType(blockID, artifacts.header.Height, artifacts.event)(batch.GetWriter()))
+	require.NoError(t, operation.BatchIndexBlockEventsByType(blockID, artifacts.header.Height)(batch.GetWriter()))
+	require.NoError(t, batch.Flush())
+}
+
+func assertArtifacts(t *testing.T, db kv.DB, artifacts blockArtifacts, stored bool) {
+	blockID := artifacts.header.ID()
+	exists := func(err error) bool {
+		if errors.Is(err, storage.ErrNotFound) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	for _, chunk := range artifacts.result.Chunks {
+		var chunkDataPack badgermodel.StoredChunkDataPack
+		assert.Equal(t, stored, exists(db.View(operation.RetrieveChunkDataPack(chunk.ID(), &chunkDataPack))))
+	}
+
+	var events []flow.Event
+	require.NoError(t, db.View(operation.LookupEventsByBlockID(blockID, &events)))
+	assert.Equal(t, stored, len(events) > 0)
+
+	var entries []operation.EventTypeIndexEntry
+	from := operation.EventTypeIndexEntry{Height: artifacts.header.Height}
+	require.NoError(t, db.View(operation.LookupEventsByType(artifacts.event.Type, from, artifacts.header.Height, 0, &entries)))
+	assert.Equal(t, stored, len(entries) > 0)
+
+	var height uint64
+	assert.Equal(t, stored, exists(db.View(operation.RetrieveBlockEventsByTypeHeight(blockID, &height))))
+
+	var result flow.TransactionResult
+	assert.Equal(t, stored, exists(db.View(operation.RetrieveTransactionResult(blockID, artifacts.txID, &result))))
+
+	var interactions []*delta.Snapshot
+	assert.Equal(t, stored, exists(db.View(operation.RetrieveExecutionStateInteractions(blockID, &interactions))))
+}
+
+func TestPruneSealed(t *testing.T) {
+	unittest.RunWithDB(t, func(db kv.DB) {
+		rootHeight := uint64(100)
+		root := unittest.BlockHeaderFixture(func(header *flow.Header) {
+			header.Height = rootHeight
+		})
+
+		// the blocks above the root block, with their stored artifacts
+		headers := new(storagemock.Headers)
+		results := new(storagemock.ExecutionResults)
+		artifacts := make([]blockArtifacts, 5)
+		for i := range artifacts {
+			header := unittest.BlockHeaderFixture(func(header *flow.Header) {
+				header.Height = rootHeight + 1 + uint64(i)
+			})
+			artifacts[i] = blockArtifacts{
+				header: &header,
+				result: unittest.ExecutionResultFixture(func(result *flow.ExecutionResult) {
+					result.BlockID = header.ID()
+				}),
+				event: unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture(), 0),
+				txID:  unittest.IdentifierFixture(),
+			}
+			storeArtifacts(t, db, artifacts[i])
+
+			headers.On("ByHeight", header.Height).Return(artifacts[i].header, nil)
+			results.On("ByBlockID", header.ID()).Return(artifacts[i].result, nil)
+		}
+
+		params := new(protocol.Params)
+		params.On("Root").Return(&root, nil)
+		sealedSnapshot := new(protocol.Snapshot)
+		sealed := unittest.BlockHeaderFixture(func(header *flow.Header) {
+			header.Height = rootHeight + 4 + MinRetainHeights
+		})
+		sealedSnapshot.On("Head").Return(&sealed, nil)
+		state := new(protocol.State)
+		state.On("Params").Return(params)
+		state.On("Sealed").Return(sealedSnapshot)
+
+		executedHeight := rootHeight + 2
+		execState := new(statemock.ReadOnlyExecutionState)
+		execState.On("GetHighestExecutedBlockID", mock.Anything).Return(
+			func(context.Context) uint64 { return executedHeight },
+			flow.ZeroID,
+			nil,
+		)
+
+		progress := badgerstorage.NewConsumerProgress(db, module.ConsumeProgressExecutionPrunedHeight)
+		config := Config{
+			RetainHeights: MinRetainHeights,
+			BatchSize:     2,
+			Interval:      DefaultInterval,
+		}
+		pruner, err := New(zerolog.Nop(), metrics.NewNoopCollector(), db, state, execState, headers, results, progress, config)
+		require.NoError(t, err)
+
+		pruned, err := progress.ProcessedIndex()
+		require.NoError(t, err)
+		assert.Equal(t, rootHeight, pruned)
+
+		t.Run("only the executed blocks are pruned", func(t *testing.T) {
+			require.NoError(t, pruner.pruneSealed())
+
+			pruned, err := progress.ProcessedIndex()
+			require.NoError(t, err)
+			assert.Equal(t, executedHeight, pruned)
+
+			for _, block := range artifacts {
+				assertArtifacts(t, db, block, block.header.Height > executedHeight)
+			}
+		})
+
+		t.Run("the latest sealed heights are retained", func(t *testing.T) {
+			executedHeight = rootHeight + 5
+
+			require.NoError(t, pruner.pruneSealed())
+
+			pruned, err := progress.ProcessedIndex()
+			require.NoError(t, err)
+			assert.Equal(t, rootHeight+4, pruned)
+
+			for _, block := range artifacts {
+				assertArtifacts(t, db, block, block.header.Height > rootHeight+4)
+			}
+		})
+
+		t.Run("the pruning resumes from the pruned height", func(t *testing.T) {
+			pruner, err := New(zerolog.Nop(), metrics.NewNoopCollector(), db, state, execState, headers, results, progress, config)
+			require.NoError(t, err)
+
+			require.NoError(t, pruner.pruneSealed())
+
+			pruned, err := progress.ProcessedIndex()
+			require.NoError(t, err)
+			assert.Equal(t, rootHeight+4, pruned)
+		})
+	})
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(zerolog.Nop(), metrics.NewNoopCollector(), nil, nil, nil, nil, nil, nil, Config{
+		RetainHeights: MinRetainHeights - 1,
+		BatchSize:     DefaultBatchSize,
+	})
+	assert.Error(t, err)
+
+	_, err = New(zerolog.Nop(), metrics.NewNoopCollector(), nil, nil, nil, nil, nil, nil, Config{
+		RetainHeights: MinRetainHeights,
+	})
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
@@ -91,6 +92,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	simulation.RegisterTransactionSimulationAPIServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -127,8 +129,9 @@ func (e *Engine) serve() {
 	}
 }
 
-// handler implements a subset of the Observation API.
+// handler implements a subset of the Observation API, and the transaction simulation API.
 type handler struct {
+	simulation.UnimplementedTransactionSimulationAPIServer
 	engine             ingestion.IngestRPC
 	chain              flow.ChainID
 	blocks             storage.Blocks
@@ -139,6 +142,7 @@ type handler struct {
 }
 
 var _ execution.ExecutionAPIServer = &handler{}
+var _ simulation.TransactionSimulationAPIServer = &handler{}
 
 // Ping responds to requests when the server is up.
 func (h *handler) Ping(ctx context.Context, req *execution.PingRequest) (*execution.PingResponse, error) {
@@ -167,6 +171,27 @@ func (h *handler) ExecuteScriptAtBlockID(
 	return res, nil
 }
 
+// SimulateTransaction runs the transaction against the latest sealed execution state, without verifying its
+// signatures or incrementing the proposal key sequence number. None of the changes made by the transaction are
+// committed.
+func (h *handler) SimulateTransaction(
+	ctx context.Context,
+	req *simulation.SimulateTransactionRequest,
+) (*simulation.SimulateTransactionResponse, error) {
+
+	tx, err := convert.MessageToTransaction(req.GetTransaction(), h.chain.Chain())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction: %v", err)
+	}
+
+	result, err := h.engine.SimulateTransaction(ctx, &tx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to simulate transaction: %v", err)
+	}
+
+	return convert.TransactionSimulationResultToMessage(result), nil
+}
+
 func (h *handler) GetEventsForBlockIDs(_ context.Context,
 	req *execution.GetEventsForBlockIDsRequest) (*execution.GetEventsForBlockIDsResponse, error) {
 
@@ -15,6 +15,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/simulation"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
 	"github.com/onflow/flow-go/model/flow"
 	realstorage "github.com/onflow/flow-go/storage"
@@ -424,3 +425,63 @@ func (suite *Suite) TestGetTransactionResult() {
 		suite.events.AssertExpectations(suite.T())
 	})
 }
+
+// TestSimulateTransaction tests the SimulateTransaction API call
+func (suite *Suite) TestSimulateTransaction() {
+
+	serviceAddress := flow.Mainnet.Chain().ServiceAddress()
+	tx := flow.NewTransactionBody().
+		SetScript([]byte("transaction { execute {} }")).
+		SetPayer(serviceAddress).
+		SetProposalKey(serviceAddress, 0, 0)
+
+	mockEngine := new(ingestion.IngestRPC)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Mainnet,
+	}
+
+	req := &simulation.SimulateTransactionRequest{
+		Transaction: convert.TransactionToMessage(*tx),
+	}
+
+	suite.Run("happy path with valid request", func() {
+
+		result := &flow.TransactionSimulationResult{
+			TransactionID: tx.ID(),
+			BlockID:       unittest.IdentifierFixture(),
+			StatusCode:    1,
+			ErrorMessage:  "failed",
+			Events:        []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)},
+			RegisterWrites: []flow.RegisterWrite{{
+				RegisterID: flow.NewRegisterID(serviceAddress.Hex(), "", "key"),
+				ValueSize:  10,
+			}},
+		}
+
+		// setup mock expectations
+		mockEngine.On("SimulateTransaction", mock.Anything, tx).Return(result, nil).Once()
+
+		resp, err := handler.SimulateTransaction(context.Background(), req)
+
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint32(1), resp.GetStatusCode())
+		suite.Require().Equal(result.ErrorMessage, resp.GetErrorMessage())
+		suite.Require().Equal(result.BlockID[:], resp.GetBlockId())
+		suite.Require().Len(resp.GetEvents(), 1)
+		suite.Require().Len(resp.GetRegisterWrites(), 1)
+		suite.Require().Equal(uint64(10), resp.GetRegisterWrites()[0].GetValueSize())
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid request with nil transaction", func() {
+
+		_, err := handler.SimulateTransaction(context.Background(), &simulation.SimulateTransactionRequest{})
+
+		suite.Require().Error(err)
+		errStatus, _ := status.FromError(err)
+		suite.Require().Equal(codes.InvalidArgument, errStatus.Code())
+	})
+}
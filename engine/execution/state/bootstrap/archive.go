@@ -0,0 +1,433 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// ArchiveVersion is the version of the execution state archive format.
+const ArchiveVersion = 1
+
+// names of the files contained in an execution state archive
+const (
+	archiveManifestFile   = "manifest.json"
+	archiveCheckpointFile = "root.checkpoint"
+	archiveSnapshotFile   = "root-protocol-state-snapshot.json"
+	archiveResultFile     = "execution-result.json"
+	archiveSealFile       = "seal.json"
+)
+
+// archiveContentFiles are the files listed in the manifest of an execution state archive.
+var archiveContentFiles = []string{
+	archiveCheckpointFile,
+	archiveSnapshotFile,
+	archiveResultFile,
+	archiveSealFile,
+}
+
+// ArchiveManifest describes the content of an execution state archive. The archive
+// bootstraps an execution node from the state sealed by the latest seal of the root
+// protocol state snapshot.
+type ArchiveManifest struct {
+	Version         uint
+	BlockID         flow.Identifier      // ID of the sealed block
+	Height          uint64               // height of the sealed block
+	ResultID        flow.Identifier      // ID of the sealed execution result
+	StateCommitment flow.StateCommitment // state commitment of the sealed block, root hash of the checkpoint
+	Files           map[string]string    // hex-encoded SHA256 hash of each file, by file name
+}
+
+// ExportArchive writes an execution state archive to the given writer. The archive contains
+// the checkpoint at the given path, the protocol state snapshot, and the execution result and
+// seal of the latest sealed block of the snapshot. It returns an error if the root hash of the
+// checkpoint is not the state commitment of the seal.
+func ExportArchive(w io.Writer, checkpointPath string, snapshot *inmem.Snapshot) (*ArchiveManifest, error) {
+
+	result, seal, err := snapshot.SealedResult()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed result of snapshot: %w", err)
+	}
+
+	sealed, err := verifySnapshot(snapshot, result, seal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	err = verifyCheckpoint(checkpointPath, seal.FinalState)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	encodedSnapshot, err := json.Marshal(snapshot.Encodable())
+	if err != nil {
+		return nil, fmt.Errorf("could not encode snapshot: %w", err)
+	}
+	encodedResult, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode execution result: %w", err)
+	}
+	encodedSeal, err := json.Marshal(seal)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode seal: %w", err)
+	}
+
+	manifest := &ArchiveManifest{
+		Version:         ArchiveVersion,
+		BlockID:         sealed.ID(),
+		Height:          sealed.Height,
+		ResultID:        result.ID(),
+		StateCommitment: seal.FinalState,
+		Files:           make(map[string]string, len(archiveContentFiles)),
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	checkpoint, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open checkpoint: %w", err)
+	}
+	defer checkpoint.Close()
+
+	info, err := checkpoint.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("could not stat checkpoint: %w", err)
+	}
+
+	manifest.Files[archiveCheckpointFile], err = writeArchiveFile(tw, archiveCheckpointFile, info.Size(), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, data := range map[string][]byte{
+		archiveSnapshotFile: encodedSnapshot,
+		archiveResultFile:   encodedResult,
+		archiveSealFile:     encodedSeal,
+	} {
+		manifest.Files[name], err = writeArchiveBytes(tw, name, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the manifest is written last, as it contains the hashes of all other files
+	encodedManifest, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode manifest: %w", err)
+	}
+	_, err = writeArchiveBytes(tw, archiveManifestFile, encodedManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not close archive: %w", err)
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not close archive compression: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ImportArchive reads an execution state archive and places its root checkpoint and root
+// protocol state snapshot in the given bootstrap directory, where the node expects them
+// when bootstrapping. Nothing is written to the bootstrap directory unless the hashes of
+// all files match the manifest, the snapshot, execution result and seal are consistent,
+// and the root hash of the checkpoint is the state commitment of the seal.
+func ImportArchive(r io.Reader, bootstrapDir string) (*ArchiveManifest, error) {
+
+	err := os.MkdirAll(bootstrapDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create bootstrap directory: %w", err)
+	}
+
+	// extract into a temporary directory in the bootstrap directory, so verified files
+	// can be moved into place without copying them
+	tmpDir, err := ioutil.TempDir(bootstrapDir, "execution-state-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	hashes, err := extractArchive(r, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ArchiveManifest
+	err = readArchiveJSON(tmpDir, archiveManifestFile, &manifest)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version (%d), expected %d", manifest.Version, ArchiveVersion)
+	}
+
+	for _, name := range archiveContentFiles {
+		expected, ok := manifest.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("manifest is missing the hash of %s", name)
+		}
+		actual, ok := hashes[name]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing %s", name)
+		}
+		if expected != actual {
+			return nil, fmt.Errorf("mismatching hash of %s, manifest has %s, file has %s", name, expected, actual)
+		}
+	}
+
+	var encodable inmem.EncodableSnapshot
+	err = readArchiveJSON(tmpDir, archiveSnapshotFile, &encodable)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := inmem.SnapshotFromEncodable(encodable)
+
+	var result flow.ExecutionResult
+	err = readArchiveJSON(tmpDir, archiveResultFile, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	var seal flow.Seal
+	err = readArchiveJSON(tmpDir, archiveSealFile, &seal)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := verifySnapshot(snapshot, &result, &seal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	if manifest.BlockID != sealed.ID() || manifest.Height != sealed.Height {
+		return nil, fmt.Errorf("manifest is for block %x at height %d, but snapshot seals block %x at height %d",
+			manifest.BlockID, manifest.Height, sealed.ID(), sealed.Height)
+	}
+	if manifest.ResultID != result.ID() {
+		return nil, fmt.Errorf("manifest is for result %x, but archive contains result %x", manifest.ResultID, result.ID())
+	}
+	if manifest.StateCommitment != seal.FinalState {
+		return nil, fmt.Errorf("manifest has state commitment %x, but seal has state commitment %x",
+			manifest.StateCommitment, seal.FinalState)
+	}
+
+	err = verifyCheckpoint(filepath.Join(tmpDir, archiveCheckpointFile), seal.FinalState)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	err = moveArchiveFile(tmpDir, archiveSnapshotFile, filepath.Join(bootstrapDir, bootstrapFilenames.PathRootProtocolStateSnapshot))
+	if err != nil {
+		return nil, err
+	}
+	err = moveArchiveFile(tmpDir, archiveCheckpointFile, filepath.Join(bootstrapDir, bootstrapFilenames.PathRootCheckpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// verifySnapshot checks that the given execution result and seal are the latest sealed result
+// of the snapshot, and returns the header of the sealed block.
+func verifySnapshot(snapshot *inmem.Snapshot, result *flow.ExecutionResult, seal *flow.Seal) (*flow.Header, error) {
+
+	snapshotResult, snapshotSeal, err := snapshot.SealedResult()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed result: %w", err)
+	}
+	if snapshotResult.ID() != result.ID() {
+		return nil, fmt.Errorf("snapshot has sealed result %x, expected %x", snapshotResult.ID(), result.ID())
+	}
+	if snapshotSeal.ID() != seal.ID() {
+		return nil, fmt.Errorf("snapshot has seal %x, expected %x", snapshotSeal.ID(), seal.ID())
+	}
+
+	if seal.ResultID != result.ID() {
+		return nil, fmt.Errorf("seal is for result %x, expected %x", seal.ResultID, result.ID())
+	}
+	if seal.BlockID != result.BlockID {
+		return nil, fmt.Errorf("seal is for block %x, but result is for block %x", seal.BlockID, result.BlockID)
+	}
+	finalState, err := result.FinalStateCommitment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final state of result: %w", err)
+	}
+	if seal.FinalState != finalState {
+		return nil, fmt.Errorf("seal has final state %x, but result has final state %x", seal.FinalState, finalState)
+	}
+
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	if len(segment) == 0 {
+		return nil, fmt.Errorf("empty sealing segment")
+	}
+	sealed := segment[0].Header
+	if sealed.ID() != seal.BlockID {
+		return nil, fmt.Errorf("sealing segment starts with block %x, but seal is for block %x", sealed.ID(), seal.BlockID)
+	}
+
+	return sealed, nil
+}
+
+// verifyCheckpoint checks that the checkpoint at the given path contains a single trie
+// with the given state commitment as root hash.
+func verifyCheckpoint(path string, commit flow.StateCommitment) error {
+
+	forest, err := wal.LoadCheckpoint(path)
+	if err != nil {
+		return fmt.Errorf("could not load checkpoint: %w", err)
+	}
+
+	tries, err := flattener.RebuildTries(forest)
+	if err != nil {
+		return fmt.Errorf("could not rebuild tries from checkpoint: %w", err)
+	}
+	if len(tries) != 1 {
+		return fmt.Errorf("checkpoint must contain exactly one trie, but contains %d", len(tries))
+	}
+
+	rootHash := flow.StateCommitment(tries[0].RootHash())
+	if rootHash != commit {
+		return fmt.Errorf("checkpoint has root hash %x, but seal has state commitment %x", rootHash, commit)
+	}
+
+	return nil
+}
+
+// writeArchiveFile writes a file to the archive, and returns the hex-encoded SHA256 hash of its content.
+func writeArchiveFile(tw *tar.Writer, name string, size int64, content io.Reader) (string, error) {
+	err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: size,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not write archive header of %s: %w", name, err)
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tw, hasher), content)
+	if err != nil {
+		return "", fmt.Errorf("could not write %s to archive: %w", name, err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func writeArchiveBytes(tw *tar.Writer, name string, data []byte) (string, error) {
+	return writeArchiveFile(tw, name, int64(len(data)), bytes.NewReader(data))
+}
+
+// extractArchive extracts the known files of the archive into the given directory, and
+// returns the hex-encoded SHA256 hash of each extracted file, by file name.
+func extractArchive(r io.Reader, dir string) (map[string]string, error) {
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read archive compression: %w", err)
+	}
+	defer gz.Close()
+
+	known := make(map[string]struct{}, len(archiveContentFiles)+1)
+	known[archiveManifestFile] = struct{}{}
+	for _, name := range archiveContentFiles {
+		known[name] = struct{}{}
+	}
+
+	hashes := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read archive: %w", err)
+		}
+
+		// only the known files are extracted, which also prevents writing outside of the directory
+		if _, ok := known[header.Name]; !ok {
+			return nil, fmt.Errorf("unexpected file in archive: %s", header.Name)
+		}
+		if _, ok := hashes[header.Name]; ok {
+			return nil, fmt.Errorf("duplicate file in archive: %s", header.Name)
+		}
+
+		hash, err := extractArchiveFile(tr, filepath.Join(dir, header.Name))
+		if err != nil {
+			return nil, fmt.Errorf("could not extract %s: %w", header.Name, err)
+		}
+		hashes[header.Name] = hash
+	}
+
+	return hashes, nil
+}
+
+func extractArchiveFile(r io.Reader, path string) (string, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), r)
+	if err != nil {
+		_ = file.Close()
+		return "", err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func readArchiveJSON(dir string, name string, target interface{}) error {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", name, err)
+	}
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return fmt.Errorf("could not decode %s: %w", name, err)
+	}
+	return nil
+}
+
+func moveArchiveFile(dir string, name string, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0700)
+	if err != nil {
+		return fmt.Errorf("could not create directory for %s: %w", dst, err)
+	}
+	err = os.Rename(filepath.Join(dir, name), dst)
+	if err != nil {
+		return fmt.Errorf("could not move %s to %s: %w", name, dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	completeLedger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestArchive(t *testing.T) {
+
+	t.Run("export and import", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			checkpoint, commit := checkpointFixture(t, dir)
+			snapshot := snapshotFixture(t, commit)
+
+			var archive bytes.Buffer
+			exported, err := ExportArchive(&archive, checkpoint, snapshot)
+			require.NoError(t, err)
+
+			bootstrapDir := filepath.Join(dir, "bootstrap")
+			imported, err := ImportArchive(&archive, bootstrapDir)
+			require.NoError(t, err)
+			assert.Equal(t, exported, imported)
+
+			result, seal, err := snapshot.SealedResult()
+			require.NoError(t, err)
+			assert.Equal(t, seal.BlockID, imported.BlockID)
+			assert.Equal(t, result.ID(), imported.ResultID)
+			assert.Equal(t, commit, imported.StateCommitment)
+
+			// the checkpoint and snapshot are where the node expects them when bootstrapping
+			expectedCheckpoint, err := ioutil.ReadFile(checkpoint)
+			require.NoError(t, err)
+			importedCheckpoint, err := ioutil.ReadFile(filepath.Join(bootstrapDir, bootstrapFilenames.PathRootCheckpoint))
+			require.NoError(t, err)
+			assert.Equal(t, expectedCheckpoint, importedCheckpoint)
+
+			data, err := ioutil.ReadFile(filepath.Join(bootstrapDir, bootstrapFilenames.PathRootProtocolStateSnapshot))
+			require.NoError(t, err)
+			var encodable inmem.EncodableSnapshot
+			require.NoError(t, json.Unmarshal(data, &encodable))
+			head, err := inmem.SnapshotFromEncodable(encodable).Head()
+			require.NoError(t, err)
+			expectedHead, err := snapshot.Head()
+			require.NoError(t, err)
+			assert.Equal(t, expectedHead.ID(), head.ID())
+		})
+	})
+
+	t.Run("checkpoint not matching the seal", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			checkpoint, _ := checkpointFixture(t, dir)
+			snapshot := snapshotFixture(t, unittest.StateCommitmentFixture())
+
+			var archive bytes.Buffer
+			_, err := ExportArchive(&archive, checkpoint, snapshot)
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("tampered archive", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			checkpoint, commit := checkpointFixture(t, dir)
+			snapshot := snapshotFixture(t, commit)
+
+			var archive bytes.Buffer
+			_, err := ExportArchive(&archive, checkpoint, snapshot)
+			require.NoError(t, err)
+
+			// flip a byte of the compressed content
+			tampered := archive.Bytes()
+			tampered[len(tampered)/2] ^= 0xff
+
+			bootstrapDir := filepath.Join(dir, "bootstrap")
+			_, err = ImportArchive(bytes.NewReader(tampered), bootstrapDir)
+			require.Error(t, err)
+
+			// nothing is placed in the bootstrap directory
+			assert.NoFileExists(t, filepath.Join(bootstrapDir, bootstrapFilenames.PathRootCheckpoint))
+			assert.NoFileExists(t, filepath.Join(bootstrapDir, bootstrapFilenames.PathRootProtocolStateSnapshot))
+		})
+	})
+}
+
+// checkpointFixture writes a checkpoint with a few registers to the given directory,
+// and returns its path and root hash.
+func checkpointFixture(t *testing.T, dir string) (string, flow.StateCommitment) {
+	led, err := completeLedger.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), completeLedger.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	keys := []ledger.Key{
+		ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte{1, 2, 3})}),
+		ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte{4, 5, 6})}),
+	}
+	values := []ledger.Value{[]byte{1}, []byte{2}}
+	update, err := ledger.NewUpdate(led.InitialState(), keys, values)
+	require.NoError(t, err)
+
+	state, _, err := led.Set(update)
+	require.NoError(t, err)
+
+	_, err = led.ExportCheckpointAt(state, nil, nil, completeLedger.DefaultPathFinderVersion, dir, bootstrapFilenames.FilenameWALRootCheckpoint)
+	require.NoError(t, err)
+
+	return filepath.Join(dir, bootstrapFilenames.FilenameWALRootCheckpoint), flow.StateCommitment(state)
+}
+
+// snapshotFixture returns a root snapshot whose seal has the given state commitment.
+// Only the parts of the snapshot included in the archive verification are populated.
+func snapshotFixture(t *testing.T, commit flow.StateCommitment) *inmem.Snapshot {
+	block := unittest.GenesisFixture()
+	result := unittest.BootstrapExecutionResultFixture(block, commit)
+	seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+	return inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head:              block.Header,
+		LatestSeal:        seal,
+		LatestResult:      result,
+		SealingSegment:    []*flow.Block{block},
+		QuorumCertificate: unittest.QuorumCertificateFixture(unittest.QCWithBlockID(block.ID())),
+	})
+}
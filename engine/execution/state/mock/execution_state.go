@@ -11,6 +11,8 @@ import (
 	messages "github.com/onflow/flow-go/model/messages"
 
 	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/onflow/flow-go/storage"
 )
 
 // ExecutionState is an autogenerated mock type for the ExecutionState type
@@ -225,13 +227,13 @@ func (_m *ExecutionState) RetrieveStateDelta(_a0 context.Context, _a1 flow.Ident
 	return r0, r1
 }
 
-// SaveExecutionResults provides a mock function with given fields: ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results
-func (_m *ExecutionState) SaveExecutionResults(ctx context.Context, header *flow.Header, endState flow.StateCommitment, chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult) error {
-	ret := _m.Called(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results)
+// SaveExecutionResults provides a mock function with given fields: ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, accountTransactions
+func (_m *ExecutionState) SaveExecutionResults(ctx context.Context, header *flow.Header, endState flow.StateCommitment, chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult, accountTransactions []storage.AccountTransaction) error {
+	ret := _m.Called(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, accountTransactions)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *flow.Header, flow.StateCommitment, []*flow.ChunkDataPack, *flow.ExecutionReceipt, []flow.EventsList, flow.EventsList, []flow.TransactionResult) error); ok {
-		r0 = rf(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results)
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.Header, flow.StateCommitment, []*flow.ChunkDataPack, *flow.ExecutionReceipt, []flow.EventsList, flow.EventsList, []flow.TransactionResult, []storage.AccountTransaction) error); ok {
+		r0 = rf(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, accountTransactions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/davecgh/go-spew/spew"
-	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/ledger"
@@ -18,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // ReadOnlyExecutionState allows to read the execution state
@@ -66,7 +66,8 @@ type ExecutionState interface {
 
 	SaveExecutionResults(ctx context.Context, header *flow.Header, endState flow.StateCommitment,
 		chunkDataPacks []*flow.ChunkDataPack,
-		executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult) error
+		executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult,
+		accountTransactions []storage.AccountTransaction) error
 }
 
 const (
@@ -76,20 +77,21 @@ const (
 )
 
 type state struct {
-	tracer             module.Tracer
-	ls                 ledger.Ledger
-	commits            storage.Commits
-	blocks             storage.Blocks
-	headers            storage.Headers
-	collections        storage.Collections
-	chunkDataPacks     storage.ChunkDataPacks
-	results            storage.ExecutionResults
-	receipts           storage.ExecutionReceipts
-	myReceipts         storage.MyExecutionReceipts
-	events             storage.Events
-	serviceEvents      storage.ServiceEvents
-	transactionResults storage.TransactionResults
-	db                 *badger.DB
+	tracer              module.Tracer
+	ls                  ledger.Ledger
+	commits             storage.Commits
+	blocks              storage.Blocks
+	headers             storage.Headers
+	collections         storage.Collections
+	chunkDataPacks      storage.ChunkDataPacks
+	results             storage.ExecutionResults
+	receipts            storage.ExecutionReceipts
+	myReceipts          storage.MyExecutionReceipts
+	events              storage.Events
+	serviceEvents       storage.ServiceEvents
+	transactionResults  storage.TransactionResults
+	accountTransactions storage.AccountTransactions
+	db                  kv.DB
 }
 
 func RegisterIDToKey(reg flow.RegisterID) ledger.Key {
@@ -114,24 +116,26 @@ func NewExecutionState(
 	events storage.Events,
 	serviceEvents storage.ServiceEvents,
 	transactionResults storage.TransactionResults,
-	db *badger.DB,
+	accountTransactions storage.AccountTransactions,
+	db kv.DB,
 	tracer module.Tracer,
 ) ExecutionState {
 	return &state{
-		tracer:             tracer,
-		ls:                 ls,
-		commits:            commits,
-		blocks:             blocks,
-		headers:            headers,
-		collections:        collections,
-		chunkDataPacks:     chunkDataPacks,
-		results:            results,
-		receipts:           receipts,
-		myReceipts:         myReceipts,
-		events:             events,
-		serviceEvents:      serviceEvents,
-		transactionResults: transactionResults,
-		db:                 db,
+		tracer:              tracer,
+		ls:                  ls,
+		commits:             commits,
+		blocks:              blocks,
+		headers:             headers,
+		collections:         collections,
+		chunkDataPacks:      chunkDataPacks,
+		results:             results,
+		receipts:            receipts,
+		myReceipts:          myReceipts,
+		events:              events,
+		serviceEvents:       serviceEvents,
+		transactionResults:  transactionResults,
+		accountTransactions: accountTransactions,
+		db:                  db,
 	}
 
 }
@@ -329,7 +333,7 @@ func (s *state) GetExecutionResultID(ctx context.Context, blockID flow.Identifie
 
 func (s *state) SaveExecutionResults(ctx context.Context, header *flow.Header, endState flow.StateCommitment,
 	chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList,
-	results []flow.TransactionResult) error {
+	results []flow.TransactionResult, accountTransactions []storage.AccountTransaction) error {
 
 	spew.Config.DisableMethods = true
 	spew.Config.DisablePointerMethods = true
@@ -368,6 +372,11 @@ func (s *state) SaveExecutionResults(ctx context.Context, header *flow.Header, e
 		return fmt.Errorf("cannot store events: %w", err)
 	}
 
+	err = s.events.BatchIndexByEventType(blockID, header.Height, events, batch)
+	if err != nil {
+		return fmt.Errorf("cannot index events by type: %w", err)
+	}
+
 	err = s.serviceEvents.BatchStore(blockID, serviceEvents, batch)
 	if err != nil {
 		return fmt.Errorf("cannot store service events: %w", err)
@@ -378,6 +387,11 @@ func (s *state) SaveExecutionResults(ctx context.Context, header *flow.Header, e
 		return fmt.Errorf("cannot store transaction result: %w", err)
 	}
 
+	err = s.accountTransactions.BatchIndex(blockID, header.Height, accountTransactions, batch)
+	if err != nil {
+		return fmt.Errorf("cannot index account transactions: %w", err)
+	}
+
 	executionResult := &executionReceipt.ExecutionResult
 	err = s.results.BatchStore(executionResult, batch)
 	if err != nil {
@@ -433,7 +447,7 @@ func (s *state) RetrieveStateDelta(ctx context.Context, blockID flow.Identifier)
 	var serviceEvents []flow.Event
 	var txResults []flow.TransactionResult
 
-	err = s.db.View(func(txn *badger.Txn) error {
+	err = s.db.View(func(txn kv.Txn) error {
 		err = operation.LookupStateCommitment(blockID, &endStateCommitment)(txn)
 		if err != nil {
 			return fmt.Errorf("cannot lookup state commitment: %w", err)
@@ -499,7 +513,7 @@ func (s *state) UpdateHighestExecutedBlockIfHigher(ctx context.Context, header *
 		defer span.Finish()
 	}
 
-	return operation.RetryOnConflict(s.db.Update, func(txn *badger.Txn) error {
+	return operation.RetryOnConflict(s.db.Update, func(txn kv.Txn) error {
 		var blockID flow.Identifier
 		err := operation.RetrieveExecutedBlock(&blockID)(txn)
 		if err != nil {
@@ -527,7 +541,7 @@ func (s *state) UpdateHighestExecutedBlockIfHigher(ctx context.Context, header *
 func (s *state) GetHighestExecutedBlockID(ctx context.Context) (uint64, flow.Identifier, error) {
 	var blockID flow.Identifier
 	var highest flow.Header
-	err := s.db.View(func(tx *badger.Txn) error {
+	err := s.db.View(func(tx kv.Txn) error {
 		err := operation.RetrieveExecutedBlock(&blockID)(tx)
 		if err != nil {
 			return fmt.Errorf("could not lookup executed block: %w", err)
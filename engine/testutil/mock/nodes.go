@@ -49,6 +49,7 @@ import (
 	"github.com/onflow/flow-go/state/protocol/events"
 	"github.com/onflow/flow-go/storage"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -56,8 +57,8 @@ import (
 // as well as all of its backend dependencies.
 type StateFixture struct {
 	DBDir          string
-	PublicDB       *badger.DB
-	SecretsDB      *badger.DB
+	PublicDB       kv.DB
+	SecretsDB      kv.DB
 	Storage        *storage.All
 	ProtocolEvents *events.Distributor
 	State          protocol.MutableState
@@ -68,8 +69,8 @@ type GenericNode struct {
 	Log            zerolog.Logger
 	Metrics        *metrics.NoopCollector
 	Tracer         module.Tracer
-	PublicDB       *badger.DB
-	SecretsDB      *badger.DB
+	PublicDB       kv.DB
+	SecretsDB      kv.DB
 	Headers        storage.Headers
 	Identities     storage.Identities
 	Guarantees     storage.Guarantees
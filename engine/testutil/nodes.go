@@ -75,6 +75,7 @@ import (
 	"github.com/onflow/flow-go/state/protocol/events/gadgets"
 	"github.com/onflow/flow-go/state/protocol/util"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -203,9 +204,9 @@ func CompleteStateFixture(
 	dataDir := unittest.TempDir(t)
 	publicDBDir := filepath.Join(dataDir, "protocol")
 	secretsDBDir := filepath.Join(dataDir, "secrets")
-	db := unittest.TypedBadgerDB(t, publicDBDir, storage.InitPublic)
+	db := badgerkv.New(unittest.TypedBadgerDB(t, publicDBDir, storage.InitPublic))
 	s := storage.InitAll(metric, db)
-	secretsDB := unittest.TypedBadgerDB(t, secretsDBDir, storage.InitSecret)
+	secretsDB := badgerkv.New(unittest.TypedBadgerDB(t, secretsDBDir, storage.InitSecret))
 	consumer := events.NewDistributor()
 
 	state, err := badgerstate.Bootstrap(metric, db, s.Headers, s.Seals, s.Results, s.Blocks, s.Setups, s.EpochCommits, s.Statuses, rootSnapshot)
@@ -490,6 +491,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	transactionsStorage := storage.NewTransactions(node.Metrics, node.PublicDB)
 	collectionsStorage := storage.NewCollections(node.PublicDB, transactionsStorage)
 	eventsStorage := storage.NewEvents(node.Metrics, node.PublicDB)
+	accountTransactionsStorage := storage.NewAccountTransactions(node.PublicDB)
 	serviceEventsStorage := storage.NewServiceEvents(node.Metrics, node.PublicDB)
 	txResultStorage := storage.NewTransactionResults(node.Metrics, node.PublicDB, storage.DefaultCacheSize)
 	commitsStorage := storage.NewCommits(node.Metrics, node.PublicDB)
@@ -535,7 +537,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	require.NoError(t, err)
 
 	execState := executionState.NewExecutionState(
-		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, node.PublicDB, node.Tracer,
+		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, accountTransactionsStorage, node.PublicDB, node.Tracer,
 	)
 
 	requestEngine, err := requester.New(
@@ -616,6 +618,8 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		false,
 		checkStakedAtBlock,
 		false,
+		true,
+		true,
 	)
 	require.NoError(t, err)
 	requestEngine.WithHandle(ingestionEngine.OnCollection)
@@ -625,7 +629,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	followerCore, finalizer := createFollowerCore(t, &node, followerState, finalizationDistributor, rootHead, rootQC)
 
 	// initialize cleaner for DB
-	cleaner := storage.NewCleaner(node.Log, node.PublicDB, node.Metrics, flow.DefaultValueLogGCFrequency)
+	cleaner := storage.NewCleaner(node.Log, node.PublicDB.(*badgerkv.DB).Badger(), node.Metrics, flow.DefaultValueLogGCFrequency)
 
 	followerEng, err := follower.New(node.Log, node.Net, node.Me, node.Metrics, node.Metrics, cleaner,
 		node.Headers, node.Payloads, followerState, pendingBlocks, followerCore, syncCore, node.Tracer)
@@ -665,7 +669,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		ExecutionEngine:     computation,
 		RequestEngine:       requestEngine,
 		ReceiptsEngine:      pusherEngine,
-		BadgerDB:            node.PublicDB,
+		BadgerDB:            node.PublicDB.(*badgerkv.DB).Badger(),
 		VM:                  vm,
 		ExecutionState:      execState,
 		Ledger:              ls,
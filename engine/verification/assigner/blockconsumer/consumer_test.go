@@ -17,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -115,7 +116,9 @@ func withConsumer(
 	process func(notifier module.ProcessingNotifier, block *flow.Block),
 	withBlockConsumer func(*blockconsumer.BlockConsumer, []*flow.Block),
 ) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	// the scenarios expect the blocks to be received in order, which relies on the latency of badger
+	unittest.RunWithBadgerDB(t, func(badgerDB *badger.DB) {
+		db := badgerkv.New(badgerDB)
 		maxProcessing := uint64(workerCount)
 
 		processedHeight := bstorage.NewConsumerProgress(db, module.ConsumeProgressVerificationBlockHeight)
@@ -3,7 +3,6 @@ package blockconsumer_test
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/engine/testutil"
@@ -12,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -46,7 +46,7 @@ func withReader(
 	withBlockReader func(*blockconsumer.FinalizedBlockReader, []*flow.Block),
 ) {
 	require.Equal(t, blockCount%2, 0, "block count for this test should be even")
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 
 		collector := &metrics.NoopCollector{}
 		tracer := &trace.NoopTracer{}
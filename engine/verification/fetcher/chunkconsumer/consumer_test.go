@@ -15,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -141,7 +142,9 @@ func WithConsumer(
 	process func(module.ProcessingNotifier, *chunks.Locator),
 	withConsumer func(*chunkconsumer.ChunkConsumer, *storage.ChunksQueue),
 ) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	// the scenarios expect the jobs to be received in order, which relies on the latency of badger
+	unittest.RunWithBadgerDB(t, func(badgerDB *badger.DB) {
+		db := badgerkv.New(badgerDB)
 		maxProcessing := uint64(3)
 
 		processedIndex := storage.NewConsumerProgress(db, module.ConsumeProgressVerificationChunkIndex)
@@ -99,6 +99,19 @@ func defaultContext(logger zerolog.Logger) Context {
 	}
 }
 
+// NewSimulationTransactionProcessors returns the transaction processors used to
+// simulate a transaction.
+//
+// Simulated transactions are neither signature verified nor subject to the proposal
+// key sequence number check, so they do not increment the sequence number either.
+func NewSimulationTransactionProcessors(logger zerolog.Logger) []TransactionProcessor {
+	return []TransactionProcessor{
+		NewTransactionAccountFrozenChecker(),
+		NewTransactionAccountFrozenEnabler(),
+		NewTransactionInvocator(logger),
+	}
+}
+
 // An Option sets a configuration parameter for a virtual machine context.
 type Option func(ctx Context) Context
 
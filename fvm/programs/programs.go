@@ -29,6 +29,12 @@ type ProgramEntry struct {
 
 type ProgramGetFunc func(location common.Location) (*ProgramEntry, bool)
 
+// UsageTracker is notified about every lookup of a contract program,
+// which allows to observe which contracts are used and how often they are cached.
+type UsageTracker interface {
+	ProgramUsed(location common.AddressLocation, cached bool)
+}
+
 func emptyProgramGetFunc(_ common.Location) (*ProgramEntry, bool) {
 	return nil, false
 }
@@ -42,6 +48,7 @@ type Programs struct {
 	programs   map[common.LocationID]ProgramEntry
 	parentFunc ProgramGetFunc
 	cleaned    bool
+	tracker    UsageTracker
 }
 
 func NewEmptyPrograms() *Programs {
@@ -52,12 +59,20 @@ func NewEmptyPrograms() *Programs {
 	}
 }
 
+// WithUsageTracker sets the tracker notified about contract program lookups.
+// The tracker is inherited by all child programs created afterwards.
+func (p *Programs) WithUsageTracker(tracker UsageTracker) *Programs {
+	p.tracker = tracker
+	return p
+}
+
 func (p *Programs) ChildPrograms() *Programs {
 	return &Programs{
 		programs: map[common.LocationID]ProgramEntry{},
 		parentFunc: func(location common.Location) (*ProgramEntry, bool) {
 			return p.get(location)
 		},
+		tracker: p.tracker,
 	}
 }
 
@@ -65,9 +80,15 @@ func (p *Programs) ChildPrograms() *Programs {
 // and boolean indicating if the value was found
 func (p *Programs) Get(location common.Location) (*interpreter.Program, *state.State, bool) {
 	p.lock.RLock()
-	defer p.lock.RUnlock()
-
 	programEntry, has := p.get(location)
+	p.lock.RUnlock()
+
+	// the tracker is notified without holding the lock, so that it never delays the other lookups
+	if p.tracker != nil {
+		if addressLocation, ok := location.(common.AddressLocation); ok {
+			p.tracker.ProgramUsed(addressLocation, has)
+		}
+	}
 
 	if has {
 		return programEntry.Program, programEntry.State, true
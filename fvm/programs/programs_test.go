@@ -169,4 +169,44 @@ func Test_Programs(t *testing.T) {
 		require.True(t, child.HasChanges())
 	})
 
+	t.Run("usage tracking", func(t *testing.T) {
+		tracker := &countingTracker{}
+
+		parent := NewEmptyPrograms().WithUsageTracker(tracker)
+		parent.Set(addressLocation, &interpreter.Program{}, newState)
+
+		// the tracker is inherited by children
+		child := parent.ChildPrograms()
+
+		_, _, has := child.Get(addressLocation)
+		require.True(t, has)
+
+		missingLocation := common.AddressLocation{
+			Address: common.BytesToAddress([]byte{5, 6, 7}),
+			Name:    "missing",
+		}
+		_, _, has = child.Get(missingLocation)
+		require.False(t, has)
+
+		// only address locations are tracked
+		_, _, has = child.Get(someLocation)
+		require.False(t, has)
+
+		require.Equal(t, 1, tracker.hits)
+		require.Equal(t, 1, tracker.misses)
+	})
+
+}
+
+type countingTracker struct {
+	hits   int
+	misses int
+}
+
+func (c *countingTracker) ProgramUsed(_ common.AddressLocation, cached bool) {
+	if cached {
+		c.hits++
+		return
+	}
+	c.misses++
 }
@@ -350,12 +350,62 @@ func (l *Ledger) ExportCheckpointAt(
 	return ledger.State(newTrie.RootHash()), nil
 }
 
+// CheckpointAt writes a checkpoint holding only the trie at the given state, which can be used as the root
+// checkpoint of a new ledger. The tries are immutable, so the checkpoint can be written while the ledger is
+// updated, as long as the trie is not evicted from the forest beforehand.
+func (l *Ledger) CheckpointAt(state ledger.State, writer io.Writer) error {
+	write, err := l.CheckpointerAt(state)
+	if err != nil {
+		return err
+	}
+	return write(writer)
+}
+
+// CheckpointerAt looks up the trie at the given state, and returns a function writing a checkpoint holding
+// only this trie, as CheckpointAt does. The function retains the trie, so that the checkpoint can still be
+// written once the trie is evicted from the forest.
+func (l *Ledger) CheckpointerAt(state ledger.State) (func(io.Writer) error, error) {
+	t, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie at the given state commitment: %w", err)
+	}
+
+	return func(writer io.Writer) error {
+		flatTrie, err := flattener.FlattenTrie(t)
+		if err != nil {
+			return fmt.Errorf("failed to flatten the trie: %w", err)
+		}
+
+		err = wal.StoreCheckpoint(flatTrie.ToFlattenedForestWithASingleTrie(), writer)
+		if err != nil {
+			return fmt.Errorf("failed to store the checkpoint: %w", err)
+		}
+		return nil
+	}, nil
+}
+
 // MostRecentTouchedState returns a state which is most recently touched.
 func (l *Ledger) MostRecentTouchedState() (ledger.State, error) {
 	root, err := l.forest.MostRecentTouchedRootHash()
 	return ledger.State(root), err
 }
 
+// PayloadsAt returns all payloads of the trie at specific state, without exporting a checkpoint
+func (l *Ledger) PayloadsAt(state ledger.State) ([]ledger.Payload, error) {
+	t, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trie at the given state commitment: %w", err)
+	}
+
+	// clean up tries to release memory
+	err = l.keepOnlyOneTrie(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean up tries to reduce memory usage: %w", err)
+	}
+
+	return t.AllPayloads(), nil
+}
+
 // DumpTrieAsJSON export trie at specific state as JSONL (each line is JSON encoding of a payload)
 func (l *Ledger) DumpTrieAsJSON(state ledger.State, writer io.Writer) error {
 	fmt.Println(ledger.RootHash(state))
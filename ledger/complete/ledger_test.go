@@ -5,7 +5,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -510,6 +512,57 @@ func Test_ExportCheckpointAt(t *testing.T) {
 	})
 }
 
+func Test_CheckpointAt(t *testing.T) {
+	// the checkpoint written at a state is loaded as the root checkpoint of a new ledger,
+	// while the ledger keeps being updated
+	unittest.RunWithTempDir(t, func(dbDir string) {
+		unittest.RunWithTempDir(t, func(dir2 string) {
+
+			diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dbDir, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led, err := complete.NewLedger(diskWal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			u := utils.UpdateFixture()
+			u.SetState(led.InitialState())
+			state, _, err := led.Set(u)
+			require.NoError(t, err)
+
+			u2, err := ledger.NewUpdate(state, u.Keys(), []ledger.Value{ledger.Value("C"), ledger.Value("D")})
+			require.NoError(t, err)
+			_, _, err = led.Set(u2)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = led.CheckpointAt(state, &buf)
+			require.NoError(t, err)
+			err = ioutil.WriteFile(filepath.Join(dir2, "root.checkpoint"), buf.Bytes(), 0600)
+			require.NoError(t, err)
+
+			diskWal2, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir2, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led2, err := complete.NewLedger(diskWal2, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			q, err := ledger.NewQuery(state, u.Keys())
+			require.NoError(t, err)
+
+			retValues, err := led2.Get(q)
+			require.NoError(t, err)
+
+			for i, v := range u.Values() {
+				assert.Equal(t, v, retValues[i])
+			}
+
+			err = led.CheckpointAt(ledger.State(unittest.StateCommitmentFixture()), &buf)
+			require.Error(t, err)
+
+			<-diskWal.Done()
+			<-diskWal2.Done()
+		})
+	})
+}
+
 func TestWALUpdateIsRunInParallel(t *testing.T) {
 
 	// The idea of this test is - WAL update should be run in parallel
@@ -644,3 +697,41 @@ func migrationByKey(p []ledger.Payload) ([]ledger.Payload, error) {
 	}
 	return ret, nil
 }
+
+func Test_CheckpointerAt(t *testing.T) {
+	// the checkpointer retains the trie, so the checkpoint is written once the trie is evicted from the forest
+	unittest.RunWithTempDir(t, func(dbDir string) {
+
+		diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dbDir, 2, pathfinder.PathByteSize, wal.SegmentSize)
+		require.NoError(t, err)
+		led, err := complete.NewLedger(diskWal, 2, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		u := utils.UpdateFixture()
+		u.SetState(led.InitialState())
+		state, _, err := led.Set(u)
+		require.NoError(t, err)
+
+		write, err := led.CheckpointerAt(state)
+		require.NoError(t, err)
+
+		newState := state
+		for i := 0; i < 3; i++ {
+			update, err := ledger.NewUpdate(newState, u.Keys(), []ledger.Value{ledger.Value(fmt.Sprintf("C%d", i)), ledger.Value(fmt.Sprintf("D%d", i))})
+			require.NoError(t, err)
+			newState, _, err = led.Set(update)
+			require.NoError(t, err)
+		}
+
+		var buf bytes.Buffer
+		err = led.CheckpointAt(state, &buf)
+		require.Error(t, err, "trie should be evicted from the forest")
+
+		buf.Reset()
+		err = write(&buf)
+		require.NoError(t, err)
+		assert.NotZero(t, buf.Len())
+
+		<-diskWal.Done()
+	})
+}
@@ -33,4 +33,6 @@ var (
 	SPOCKTag = tag("SPoCK")
 	// DKGMessageTag is used for DKG messages
 	DKGMessageTag = tag("DKG-Message")
+	// NetworkEnvelopeTag is used for the envelopes of the messages sent on private channels
+	NetworkEnvelopeTag = tag("Network-Envelope")
 )
@@ -20,6 +20,14 @@ func (t TransactionResult) String() string {
 	return fmt.Sprintf("Transaction ID: %s, Error Message: %s", t.TransactionID.String(), t.ErrorMessage)
 }
 
+// StatusCode returns the status code of the transaction: 0 if it succeeded, 1 if it returned an error.
+func (t TransactionResult) StatusCode() uint {
+	if t.ErrorMessage != "" {
+		return 1
+	}
+	return 0
+}
+
 // ID returns a canonical identifier that is guaranteed to be unique.
 func (t TransactionResult) ID() Identifier {
 	return t.TransactionID
@@ -28,3 +36,28 @@ func (t TransactionResult) ID() Identifier {
 func (te *TransactionResult) Checksum() Identifier {
 	return te.ID()
 }
+
+// TransactionSimulationResult is the outcome of running a transaction against a sealed execution state without
+// committing any of its changes.
+type TransactionSimulationResult struct {
+	TransactionID Identifier
+	// BlockID is the ID of the sealed block whose execution state the transaction was run against.
+	BlockID Identifier
+	// StatusCode is 0 if the transaction succeeded, 1 if it returned an error, as for the executed transactions.
+	StatusCode      uint
+	ErrorMessage    string
+	Events          []Event
+	ComputationUsed uint64
+	RegisterWrites  []RegisterWrite
+}
+
+// Failed returns true if the simulated transaction returned an error.
+func (r *TransactionSimulationResult) Failed() bool {
+	return r.ErrorMessage != ""
+}
+
+// RegisterWrite summarizes a single register update made by a simulated transaction.
+type RegisterWrite struct {
+	RegisterID RegisterID
+	ValueSize  int
+}
@@ -0,0 +1,270 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/pb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
+)
+
+// ManifestFilename is the name of the file listing the files of a backup. It is written last, so a backup
+// without manifest is incomplete.
+const ManifestFilename = "manifest.json"
+
+// ProtocolDatabaseFilename is the name of the file of a backup holding the protocol database, in the badger
+// backup format.
+const ProtocolDatabaseFilename = "protocol.badger"
+
+// kvListSize is the number of entries encoded in each list of the badger backup format.
+const kvListSize = 1000
+
+// Artifact is a file of a backup, besides the protocol database. It is prepared from the snapshot of the
+// protocol database the backup is taken at, so that it is consistent with the database: Prepare is called
+// with the snapshot as soon as the backup starts, and returns the function writing the file once the
+// protocol database is written. Anything the file is written from, e.g. the trie of the execution state at
+// the snapshot, must be retained by Prepare, as it may be pruned while the protocol database is written.
+type Artifact struct {
+	Filename string
+	Prepare  func(tx kv.Txn) (WriteFunc, error)
+}
+
+// WriteFunc writes a file of a backup.
+type WriteFunc func(ctx context.Context, w io.Writer) error
+
+// Manifest lists the files of a backup, to validate them before they are restored.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+}
+
+// File is a file of a backup, with its size and SHA-256 checksum.
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Create writes a backup of the protocol database and of the given artifacts to the given directory, which
+// must be empty or not exist. All the files are written from a single snapshot of the database, taken when
+// the backup starts, while the database keeps being updated. The writes are limited to the given rate in
+// bytes per second, 0 for no limit, and the number of bytes written to each file is reported to progress.
+// The files written are removed if the backup fails or is cancelled, so that it can be retried in the same
+// directory.
+func Create(ctx context.Context, db *badger.DB, dir string, rateLimit int64, artifacts []Artifact, progress func(filename string, written int64)) (*Manifest, error) {
+	err := ensureEmptyDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{CreatedAt: time.Now().UTC()}
+	err = writeBackup(ctx, db, dir, rateLimit, artifacts, progress, manifest)
+	if err != nil {
+		return nil, removeFiles(dir, manifest, err)
+	}
+	return manifest, nil
+}
+
+// writeBackup writes the files of a backup, and lists the files completely written in the manifest.
+func writeBackup(ctx context.Context, db *badger.DB, dir string, rateLimit int64, artifacts []Artifact, progress func(string, int64), manifest *Manifest) error {
+	tx := db.NewTransaction(false)
+	defer tx.Discard()
+
+	writes := make([]WriteFunc, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		write, err := artifact.Prepare(badgerkv.NewTxn(tx))
+		if err != nil {
+			return fmt.Errorf("could not prepare %s: %w", artifact.Filename, err)
+		}
+		writes = append(writes, write)
+	}
+
+	file, err := writeFile(ctx, dir, ProtocolDatabaseFilename, rateLimit, progress, func(w io.Writer) error {
+		return writeDatabase(ctx, tx, w)
+	})
+	if err != nil {
+		return fmt.Errorf("could not write %s: %w", ProtocolDatabaseFilename, err)
+	}
+	manifest.Files = append(manifest.Files, *file)
+
+	for i, artifact := range artifacts {
+		write := writes[i]
+		file, err := writeFile(ctx, dir, artifact.Filename, rateLimit, progress, func(w io.Writer) error {
+			return write(ctx, w)
+		})
+		if err != nil {
+			return fmt.Errorf("could not write %s: %w", artifact.Filename, err)
+		}
+		manifest.Files = append(manifest.Files, *file)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %w", err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, ManifestFilename), data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// removeFiles removes the files of a failed backup, i.e. the files listed in the manifest and the manifest if
+// it was partially written, and returns the error of the backup along with the errors of the removal.
+func removeFiles(dir string, manifest *Manifest, backupErr error) error {
+	errs := multierror.Append(nil, backupErr)
+	filenames := []string{ManifestFilename}
+	for _, file := range manifest.Files {
+		filenames = append(filenames, file.Name)
+	}
+	for _, filename := range filenames {
+		err := os.Remove(filepath.Join(dir, filename))
+		if err != nil && !os.IsNotExist(err) {
+			errs = multierror.Append(errs, fmt.Errorf("could not remove %s: %w", filename, err))
+		}
+	}
+	if len(errs.Errors) == 1 {
+		return backupErr
+	}
+	return errs
+}
+
+// writeFile writes a file of the backup, under a temporary name until it is complete. The temporary file is
+// removed if the file can't be written.
+func writeFile(ctx context.Context, dir string, filename string, rateLimit int64, progress func(string, int64), write func(io.Writer) error) (*File, error) {
+	path := filepath.Join(dir, filename)
+	tmp, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not create file: %w", err)
+	}
+	renamed := false
+	defer func() {
+		_ = tmp.Close()
+		if !renamed {
+			_ = os.Remove(path + ".tmp")
+		}
+	}()
+
+	hash := sha256.New()
+	writer := newThrottledWriter(ctx, io.MultiWriter(tmp, hash), rateLimit, func(written int64) {
+		if progress != nil {
+			progress(filename, written)
+		}
+	})
+
+	err = write(writer)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Flush()
+	if err != nil {
+		return nil, err
+	}
+	err = tmp.Sync()
+	if err != nil {
+		return nil, fmt.Errorf("could not sync file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not close file: %w", err)
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not rename file: %w", err)
+	}
+	renamed = true
+
+	return &File{
+		Name:   filename,
+		Size:   writer.Written(),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// writeDatabase writes the latest version of all the entries of the snapshot in the badger backup format, so
+// that it can be restored with badger's Load.
+func writeDatabase(ctx context.Context, tx *badger.Txn, w io.Writer) error {
+	it := tx.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	list := &pb.KVList{}
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return fmt.Errorf("could not read value of key %x: %w", item.Key(), err)
+		}
+		list.Kv = append(list.Kv, &pb.KV{
+			Key:       item.KeyCopy(nil),
+			Value:     value,
+			UserMeta:  []byte{item.UserMeta()},
+			Version:   item.Version(),
+			ExpiresAt: item.ExpiresAt(),
+		})
+
+		if len(list.Kv) < kvListSize {
+			continue
+		}
+		err = writeKVList(list, w)
+		if err != nil {
+			return err
+		}
+		list = &pb.KVList{}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+
+	if len(list.Kv) == 0 {
+		return nil
+	}
+	return writeKVList(list, w)
+}
+
+// writeKVList writes a list of entries prefixed by its size, as in the badger backup format.
+func writeKVList(list *pb.KVList, w io.Writer) error {
+	data, err := proto.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("could not encode entries: %w", err)
+	}
+	err = binary.Write(w, binary.LittleEndian, uint64(len(data)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ensureEmptyDir creates the given directory if it does not exist, and checks it is empty otherwise.
+func ensureEmptyDir(dir string) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("could not create backup directory: %w", err)
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("could not read backup directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("backup directory %s is not empty", dir)
+	}
+	return nil
+}
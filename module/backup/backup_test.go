@@ -0,0 +1,339 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// storeEntries stores the given number of entries, larger than a list of the badger backup format.
+func storeEntries(t *testing.T, db *badger.DB, count int) {
+	require.NoError(t, db.Update(func(tx *badger.Txn) error {
+		for i := 0; i < count; i++ {
+			err := tx.Set([]byte(fmt.Sprintf("key-%05d", i)), []byte(fmt.Sprintf("value-%d", i)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// keyArtifact is an artifact holding the value of the given key in the snapshot of the backup.
+func keyArtifact(key string) Artifact {
+	return Artifact{
+		Filename: "artifact",
+		Prepare: func(tx kv.Txn) (WriteFunc, error) {
+			return func(ctx context.Context, w io.Writer) error {
+				return tx.Get([]byte(key), func(val []byte) error {
+					_, err := w.Write(val)
+					return err
+				})
+			}, nil
+		},
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 2500)
+
+			backupDir := filepath.Join(dir, "backup")
+			written := make(map[string]int64)
+			manifest, err := Create(context.Background(), db, backupDir, 0, []Artifact{keyArtifact("key-00042")}, func(filename string, n int64) {
+				written[filename] = n
+			})
+			require.NoError(t, err)
+			require.Len(t, manifest.Files, 2)
+			assert.Equal(t, ProtocolDatabaseFilename, manifest.Files[0].Name)
+			assert.Equal(t, manifest.Files[0].Size, written[ProtocolDatabaseFilename])
+			assert.Equal(t, int64(len("value-42")), written["artifact"])
+
+			validated, err := Validate(backupDir)
+			require.NoError(t, err)
+			assert.Equal(t, manifest.Files, validated.Files)
+
+			restoreDir := filepath.Join(dir, "restored")
+			require.NoError(t, RestoreDatabase(backupDir, badger.DefaultOptions(restoreDir).WithLogger(nil)))
+			restored, err := badger.Open(badger.DefaultOptions(restoreDir).WithLogger(nil))
+			require.NoError(t, err)
+			defer restored.Close()
+
+			count := 0
+			require.NoError(t, restored.View(func(tx *badger.Txn) error {
+				it := tx.NewIterator(badger.DefaultIteratorOptions)
+				defer it.Close()
+				for it.Rewind(); it.Valid(); it.Next() {
+					count++
+				}
+				item, err := tx.Get([]byte("key-02499"))
+				require.NoError(t, err)
+				return item.Value(func(value []byte) error {
+					assert.Equal(t, "value-2499", string(value))
+					return nil
+				})
+			}))
+			assert.Equal(t, 2500, count)
+
+			path := filepath.Join(dir, "trie", "root.checkpoint")
+			require.NoError(t, RestoreFile(backupDir, validated, "artifact", path))
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "value-42", string(data))
+
+			t.Run("file not in manifest", func(t *testing.T) {
+				err := RestoreFile(backupDir, validated, "missing", filepath.Join(dir, "missing"))
+				require.Error(t, err)
+			})
+
+			t.Run("file altered since validation", func(t *testing.T) {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(backupDir, "artifact"), []byte("value-43"), 0600))
+
+				path := filepath.Join(dir, "altered")
+				err := RestoreFile(backupDir, validated, "artifact", path)
+				require.Error(t, err)
+				_, err = os.Stat(path)
+				assert.True(t, os.IsNotExist(err))
+			})
+		})
+	})
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 1)
+
+			// the artifact is written after the database is updated, but from the snapshot of the backup
+			artifact := keyArtifact("key-00000")
+			prepare := artifact.Prepare
+			artifact.Prepare = func(tx kv.Txn) (WriteFunc, error) {
+				write, err := prepare(tx)
+				if err != nil {
+					return nil, err
+				}
+				return func(ctx context.Context, w io.Writer) error {
+					require.NoError(t, db.Update(func(tx *badger.Txn) error {
+						return tx.Set([]byte("key-00000"), []byte("updated"))
+					}))
+					return write(ctx, w)
+				}, nil
+			}
+
+			_, err := Create(context.Background(), db, dir, 0, []Artifact{artifact}, nil)
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadFile(filepath.Join(dir, "artifact"))
+			require.NoError(t, err)
+			assert.Equal(t, "value-0", string(data))
+		})
+	})
+}
+
+// TestCreatePrepareFirst tests that the artifacts are prepared before the protocol database is written, so
+// that what they are written from is retained from the start of the backup.
+func TestCreatePrepareFirst(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 1)
+
+			prepared := false
+			artifact := keyArtifact("key-00000")
+			prepare := artifact.Prepare
+			artifact.Prepare = func(tx kv.Txn) (WriteFunc, error) {
+				_, err := os.Stat(filepath.Join(dir, ProtocolDatabaseFilename))
+				assert.True(t, os.IsNotExist(err))
+				prepared = true
+				return prepare(tx)
+			}
+
+			_, err := Create(context.Background(), db, dir, 0, []Artifact{artifact}, nil)
+			require.NoError(t, err)
+			assert.True(t, prepared)
+		})
+	})
+}
+
+func TestCreateNotEmptyDir(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), nil, 0600))
+
+			_, err := Create(context.Background(), db, dir, 0, nil, nil)
+			require.Error(t, err)
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 10)
+			_, err := Create(context.Background(), db, dir, 0, []Artifact{keyArtifact("key-00001")}, nil)
+			require.NoError(t, err)
+
+			t.Run("tampered file", func(t *testing.T) {
+				path := filepath.Join(dir, "artifact")
+				require.NoError(t, ioutil.WriteFile(path, []byte("value-2"), 0600))
+				defer func() {
+					require.NoError(t, ioutil.WriteFile(path, []byte("value-1"), 0600))
+				}()
+
+				_, err := Validate(dir)
+				require.Error(t, err)
+			})
+
+			t.Run("missing manifest", func(t *testing.T) {
+				path := filepath.Join(dir, ManifestFilename)
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.Remove(path))
+				defer func() {
+					require.NoError(t, ioutil.WriteFile(path, data, 0600))
+				}()
+
+				_, err = Validate(dir)
+				require.Error(t, err)
+			})
+
+			_, err = Validate(dir)
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestCreateRateLimit(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			artifact := Artifact{
+				Filename: "artifact",
+				Prepare: func(tx kv.Txn) (WriteFunc, error) {
+					return func(ctx context.Context, w io.Writer) error {
+						_, err := w.Write(make([]byte, 3*bufferSize))
+						return err
+					}, nil
+				},
+			}
+
+			// the burst of the limiter is spent at once, the rest of the file is written at the limited rate
+			start := time.Now()
+			_, err := Create(context.Background(), db, filepath.Join(dir, "limited"), 8*bufferSize, []Artifact{artifact}, nil)
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+
+			t.Run("canceled", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err := Create(ctx, db, filepath.Join(dir, "canceled"), bufferSize, []Artifact{artifact}, nil)
+				require.ErrorIs(t, err, context.Canceled)
+			})
+		})
+	})
+}
+
+// TestCreateCanceledRetry checks that a backup cancelled while writing an artifact, after the protocol database
+// is written, leaves no file behind, so that it can be retried in the same directory.
+func TestCreateCanceledRetry(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 10)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			canceled := Artifact{
+				Filename: "artifact",
+				Prepare: func(tx kv.Txn) (WriteFunc, error) {
+					return func(ctx context.Context, w io.Writer) error {
+						_, err := w.Write([]byte("partial"))
+						if err != nil {
+							return err
+						}
+						cancel()
+						return ctx.Err()
+					}, nil
+				},
+			}
+			_, err := Create(ctx, db, dir, 0, []Artifact{canceled}, nil)
+			require.ErrorIs(t, err, context.Canceled)
+
+			entries, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			manifest, err := Create(context.Background(), db, dir, 0, []Artifact{keyArtifact("key-00001")}, nil)
+			require.NoError(t, err)
+			validated, err := Validate(dir)
+			require.NoError(t, err)
+			assert.Equal(t, manifest.Files, validated.Files)
+		})
+	})
+}
+
+func TestManager(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			storeEntries(t, db, 10)
+
+			release := make(chan struct{})
+			manager := NewManager(zerolog.Nop(), db)
+			manager.AddArtifact(Artifact{
+				Filename: "artifact",
+				Prepare: func(tx kv.Txn) (WriteFunc, error) {
+					return func(ctx context.Context, w io.Writer) error {
+						<-release
+						return nil
+					}, nil
+				},
+			})
+			unittest.AssertClosesBefore(t, manager.Ready(), time.Second)
+
+			_, ok := manager.Status()
+			assert.False(t, ok)
+
+			require.NoError(t, manager.Start(filepath.Join(dir, "first"), 0))
+			require.Error(t, manager.Start(filepath.Join(dir, "second"), 0))
+
+			status, ok := manager.Status()
+			require.True(t, ok)
+			assert.True(t, status.Running)
+
+			close(release)
+			require.Eventually(t, func() bool {
+				status, _ := manager.Status()
+				return !status.Running
+			}, time.Second, 10*time.Millisecond)
+
+			status, _ = manager.Status()
+			assert.Empty(t, status.Error)
+			assert.NotNil(t, status.FinishedAt)
+			assert.Positive(t, status.BytesWritten)
+
+			_, err := Validate(filepath.Join(dir, "first"))
+			require.NoError(t, err)
+
+			// a backup to a directory which is not empty fails
+			require.NoError(t, manager.Start(filepath.Join(dir, "first"), 0))
+			require.Eventually(t, func() bool {
+				status, _ := manager.Status()
+				return !status.Running
+			}, time.Second, 10*time.Millisecond)
+			status, _ = manager.Status()
+			assert.NotEmpty(t, status.Error)
+
+			unittest.AssertClosesBefore(t, manager.Done(), time.Second)
+		})
+	})
+}
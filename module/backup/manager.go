@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+)
+
+// progressLogInterval is the number of bytes written between two logs of the progress of a backup.
+const progressLogInterval = 1 << 30
+
+// Status is the status of the latest backup started by a manager.
+type Status struct {
+	Dir          string     `json:"dir"`
+	Running      bool       `json:"running"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	File         string     `json:"file"`          // file being written
+	BytesWritten int64      `json:"bytes_written"` // bytes written to all the files
+	Error        string     `json:"error,omitempty"`
+}
+
+// Manager takes backups of the protocol database and of the artifacts added by the node, one at a time and in
+// the background, while the node runs.
+type Manager struct {
+	unit      *engine.Unit
+	log       zerolog.Logger
+	db        *badger.DB
+	mu        sync.Mutex
+	artifacts []Artifact
+	status    *Status
+	written   map[string]int64 // bytes written to each file of the running backup
+	logged    int64            // bytes written when the progress was last logged
+}
+
+// NewManager returns a manager of the backups of the given protocol database.
+func NewManager(log zerolog.Logger, db *badger.DB) *Manager {
+	return &Manager{
+		unit: engine.NewUnit(),
+		log:  log.With().Str("component", "backup").Logger(),
+		db:   db,
+	}
+}
+
+// AddArtifact adds a file to the backups taken from now on.
+func (m *Manager) AddArtifact(artifact Artifact) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.artifacts = append(m.artifacts, artifact)
+}
+
+// Ready returns a channel closed once the manager is ready to take backups.
+func (m *Manager) Ready() <-chan struct{} {
+	return m.unit.Ready()
+}
+
+// Done cancels the running backup, if any, and returns a channel closed once it is stopped.
+func (m *Manager) Done() <-chan struct{} {
+	return m.unit.Done()
+}
+
+// Start starts a backup to the given directory, which must be empty or not exist, with writes limited to the
+// given rate in bytes per second, 0 for no limit. It returns an error if a backup is already running.
+func (m *Manager) Start(dir string, rateLimit int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != nil && m.status.Running {
+		return fmt.Errorf("backup to %s is already running", m.status.Dir)
+	}
+
+	m.status = &Status{
+		Dir:       dir,
+		Running:   true,
+		StartedAt: time.Now().UTC(),
+	}
+	m.written = make(map[string]int64)
+	m.logged = 0
+	artifacts := append([]Artifact(nil), m.artifacts...)
+
+	m.unit.Launch(func() {
+		m.log.Info().Str("dir", dir).Int64("rate_limit", rateLimit).Msg("backup started")
+
+		manifest, err := Create(m.unit.Ctx(), m.db, dir, rateLimit, artifacts, m.onProgress)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		finishedAt := time.Now().UTC()
+		m.status.Running = false
+		m.status.FinishedAt = &finishedAt
+		if err != nil {
+			m.status.Error = err.Error()
+			m.log.Error().Err(err).Str("dir", dir).Msg("backup failed")
+			return
+		}
+		m.log.Info().
+			Str("dir", dir).
+			Int("files", len(manifest.Files)).
+			Int64("bytes_written", m.status.BytesWritten).
+			Dur("duration", finishedAt.Sub(m.status.StartedAt)).
+			Msg("backup completed")
+	})
+
+	return nil
+}
+
+// Status returns the status of the latest backup, and false if no backup was started.
+func (m *Manager) Status() (Status, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status == nil {
+		return Status{}, false
+	}
+	return *m.status, true
+}
+
+func (m *Manager) onProgress(filename string, written int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status.BytesWritten += written - m.written[filename]
+	m.status.File = filename
+	m.written[filename] = written
+
+	if m.status.BytesWritten-m.logged >= progressLogInterval {
+		m.logged = m.status.BytesWritten
+		m.log.Info().
+			Str("file", filename).
+			Int64("bytes_written", m.status.BytesWritten).
+			Msg("backup in progress")
+	}
+}
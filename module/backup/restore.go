@@ -0,0 +1,145 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// maxPendingWrites is the number of pending writes while restoring the protocol database.
+const maxPendingWrites = 256
+
+// Validate checks that the backup in the given directory is complete, and that the size and checksum of each
+// of its files match its manifest.
+func Validate(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest, the backup may be incomplete: %w", err)
+	}
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode manifest: %w", err)
+	}
+
+	hasDatabase := false
+	for _, file := range manifest.Files {
+		if file.Name == ProtocolDatabaseFilename {
+			hasDatabase = true
+		}
+		err = validateFile(dir, file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup file %s: %w", file.Name, err)
+		}
+	}
+	if !hasDatabase {
+		return nil, fmt.Errorf("backup has no %s file", ProtocolDatabaseFilename)
+	}
+
+	return &manifest, nil
+}
+
+func validateFile(dir string, file File) error {
+	f, err := os.Open(filepath.Join(dir, file.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+	if size != file.Size {
+		return fmt.Errorf("file has %d bytes, expected %d", size, file.Size)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if checksum != file.SHA256 {
+		return fmt.Errorf("file has checksum %s, expected %s", checksum, file.SHA256)
+	}
+	return nil
+}
+
+// RestoreDatabase loads the protocol database of the backup in the given directory into a new database opened
+// with the given options. The backup must be validated beforehand.
+func RestoreDatabase(dir string, opts badger.Options) error {
+	file, err := os.Open(filepath.Join(dir, ProtocolDatabaseFilename))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return fmt.Errorf("could not open db: %w", err)
+	}
+
+	err = db.Load(file, maxPendingWrites)
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("could not load backup: %w", err)
+	}
+
+	return db.Close()
+}
+
+// RestoreFile copies a file of the backup in the given directory to the given path. The file must be listed
+// in the manifest of the backup, which must be validated beforehand, and is checked against the manifest
+// again while it is copied, so that a file altered since the backup was validated is never restored.
+func RestoreFile(dir string, manifest *Manifest, filename string, path string) error {
+	var expected *File
+	for i, file := range manifest.Files {
+		if file.Name == filename {
+			expected = &manifest.Files[i]
+			break
+		}
+	}
+	if expected == nil {
+		return fmt.Errorf("backup has no %s file", filename)
+	}
+
+	in, err := os.Open(filepath.Join(dir, filename))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), in)
+	if err != nil {
+		return fmt.Errorf("could not copy file: %w", err)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if size != expected.Size || checksum != expected.SHA256 {
+		_ = os.Remove(path + ".tmp")
+		return fmt.Errorf("file has %d bytes and checksum %s, expected %d bytes and checksum %s", size, checksum, expected.Size, expected.SHA256)
+	}
+
+	err = out.Sync()
+	if err != nil {
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// bufferSize is the size of the writes to the files of a backup.
+const bufferSize = 64 << 10
+
+// throttledWriter buffers the writes to a file, limits their rate and counts the bytes written.
+type throttledWriter struct {
+	*bufio.Writer
+	limited *limitedWriter
+}
+
+func newThrottledWriter(ctx context.Context, w io.Writer, rateLimit int64, progress func(written int64)) *throttledWriter {
+	limited := &limitedWriter{
+		ctx:      ctx,
+		writer:   w,
+		progress: progress,
+	}
+	if rateLimit > 0 {
+		burst := int(rateLimit)
+		if burst > bufferSize {
+			burst = bufferSize
+		}
+		limited.limiter = rate.NewLimiter(rate.Limit(rateLimit), burst)
+	}
+
+	return &throttledWriter{
+		Writer:  bufio.NewWriterSize(limited, bufferSize),
+		limited: limited,
+	}
+}
+
+// Written returns the number of bytes written to the underlying writer.
+func (w *throttledWriter) Written() int64 {
+	return w.limited.written
+}
+
+// limitedWriter writes to the underlying writer at a limited rate, in chunks no larger than the burst of the
+// limiter, and stops writing once the context is canceled.
+type limitedWriter struct {
+	ctx      context.Context
+	writer   io.Writer
+	limiter  *rate.Limiter
+	written  int64
+	progress func(written int64)
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		chunk := p
+		if w.limiter != nil {
+			if len(chunk) > w.limiter.Burst() {
+				chunk = chunk[:w.limiter.Burst()]
+			}
+			err := w.limiter.WaitN(w.ctx, len(chunk))
+			if err != nil {
+				return total, err
+			}
+		} else if err := w.ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := w.writer.Write(chunk)
+		total += n
+		w.written += int64(n)
+		w.progress(w.written)
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+	}
+	return total, nil
+}
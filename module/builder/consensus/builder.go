@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/onflow/flow-go/model/flow"
@@ -20,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Builder is the builder for consensus block payloads. Upon providing a payload
@@ -27,7 +27,7 @@ import (
 type Builder struct {
 	metrics    module.MempoolMetrics
 	tracer     module.Tracer
-	db         *badger.DB
+	db         kv.DB
 	state      protocol.MutableState
 	seals      storage.Seals
 	headers    storage.Headers
@@ -44,7 +44,7 @@ type Builder struct {
 // NewBuilder creates a new block builder.
 func NewBuilder(
 	metrics module.MempoolMetrics,
-	db *badger.DB,
+	db kv.DB,
 	state protocol.MutableState,
 	headers storage.Headers,
 	seals storage.Seals,
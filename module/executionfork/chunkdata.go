@@ -0,0 +1,127 @@
+package executionfork
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkDataDiff is the difference between the chunk data packs of the divergent chunk of two conflicting results,
+// i.e. between the transactions of the collections they executed and the registers they read, with their values at
+// the start state of the chunk. The data of the two packs is listed in the order of their results.
+type ChunkDataDiff struct {
+	ResultIDs    [2]flow.Identifier `json:"result_ids"`
+	StartStates  [2]string          `json:"start_states"`
+	Transactions []TransactionDiff  `json:"transactions"`
+	Registers    []RegisterDiff     `json:"registers"`
+}
+
+// TransactionDiff is a position of the collections of the chunk data packs at which the transactions differ.
+type TransactionDiff struct {
+	Index          int                `json:"index"`
+	TransactionIDs [2]flow.Identifier `json:"transaction_ids"` // flow.ZeroID if the collection is shorter
+}
+
+// RegisterDiff is a register which is read by one of the chunks only, or which has different values at the start
+// states of the chunks.
+type RegisterDiff struct {
+	Path   string    `json:"path"`
+	Key    string    `json:"key"`
+	Read   [2]bool   `json:"read"`
+	Values [2]string `json:"values"` // hex-encoded, empty if the register is not read or not set
+}
+
+// DiffChunkDataPacks compares the chunk data packs of the divergent chunk of two conflicting results.
+func DiffChunkDataPacks(resultIDs [2]flow.Identifier, packs [2]*flow.ChunkDataPack) (*ChunkDataDiff, error) {
+	diff := &ChunkDataDiff{
+		ResultIDs:    resultIDs,
+		Transactions: []TransactionDiff{},
+		Registers:    []RegisterDiff{},
+	}
+
+	var transactions [2][]flow.Identifier
+	var registers [2]map[ledger.Path]*ledger.Payload
+	for i, pack := range packs {
+		diff.StartStates[i] = stateString(pack.StartState)
+		// the system chunk has no collection
+		if pack.Collection != nil {
+			transactions[i] = pack.Collection.Light().Transactions
+		}
+		read, err := readRegisters(pack.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("could not read registers of chunk data pack %x: %w", pack.ChunkID, err)
+		}
+		registers[i] = read
+	}
+
+	maxTransactions := len(transactions[0])
+	if len(transactions[1]) > maxTransactions {
+		maxTransactions = len(transactions[1])
+	}
+	for index := 0; index < maxTransactions; index++ {
+		var txIDs [2]flow.Identifier
+		for i := range transactions {
+			if index < len(transactions[i]) {
+				txIDs[i] = transactions[i][index]
+			}
+		}
+		if txIDs[0] != txIDs[1] {
+			diff.Transactions = append(diff.Transactions, TransactionDiff{Index: index, TransactionIDs: txIDs})
+		}
+	}
+
+	paths := make(map[ledger.Path]struct{})
+	for i := range registers {
+		for path := range registers[i] {
+			paths[path] = struct{}{}
+		}
+	}
+	for path := range paths {
+		register := RegisterDiff{Path: hex.EncodeToString(path[:])}
+		for i := range registers {
+			payload, ok := registers[i][path]
+			if !ok {
+				continue
+			}
+			register.Read[i] = true
+			if payload == nil {
+				continue
+			}
+			register.Values[i] = hex.EncodeToString(payload.Value)
+			if register.Key == "" && len(payload.Key.KeyParts) > 0 {
+				register.Key = payload.Key.String()
+			}
+		}
+		if register.Read[0] != register.Read[1] || register.Values[0] != register.Values[1] {
+			diff.Registers = append(diff.Registers, register)
+		}
+	}
+	sort.Slice(diff.Registers, func(i, j int) bool {
+		return diff.Registers[i].Path < diff.Registers[j].Path
+	})
+
+	return diff, nil
+}
+
+// readRegisters returns the registers read by a chunk, with their payload at the start state of the chunk, as
+// proven by the storage proof of its chunk data pack. The payload is nil for the registers which are not set.
+func readRegisters(proof flow.StorageProof) (map[ledger.Path]*ledger.Payload, error) {
+	batchProof, err := encoding.DecodeTrieBatchProof(proof)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode storage proof: %w", err)
+	}
+
+	registers := make(map[ledger.Path]*ledger.Payload, len(batchProof.Proofs))
+	for _, trieProof := range batchProof.Proofs {
+		if !trieProof.Inclusion || trieProof.Payload == nil || len(trieProof.Payload.Value) == 0 {
+			registers[trieProof.Path] = nil
+			continue
+		}
+		registers[trieProof.Path] = trieProof.Payload
+	}
+	return registers, nil
+}
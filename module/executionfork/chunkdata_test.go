@@ -0,0 +1,115 @@
+package executionfork
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// storageProof returns a storage proof of the given registers, nil payloads being proven not to be set.
+func storageProof(registers map[ledger.Path]*ledger.Payload) flow.StorageProof {
+	batchProof := ledger.NewTrieBatchProof()
+	for path, payload := range registers {
+		proof, _ := utils.TrieProofFixture()
+		proof.Path = path
+		proof.Payload = payload
+		if payload == nil {
+			proof.Payload = ledger.EmptyPayload()
+			proof.Inclusion = false
+		}
+		batchProof.Proofs = append(batchProof.Proofs, proof)
+	}
+	return encoding.EncodeTrieBatchProof(batchProof)
+}
+
+func TestDiffChunkDataPacks(t *testing.T) {
+	tx1 := unittest.TransactionBodyFixture()
+	tx2 := unittest.TransactionBodyFixture()
+	tx3 := unittest.TransactionBodyFixture()
+	tx4 := unittest.TransactionBodyFixture()
+
+	pathA := utils.PathByUint8(1)
+	pathB := utils.PathByUint8(2)
+	pathC := utils.PathByUint8(3)
+	pathD := utils.PathByUint8(4)
+
+	packs := [2]*flow.ChunkDataPack{
+		{
+			ChunkID:    unittest.IdentifierFixture(),
+			StartState: unittest.StateCommitmentFixture(),
+			Collection: &flow.Collection{Transactions: []*flow.TransactionBody{&tx1, &tx2}},
+			Proof: storageProof(map[ledger.Path]*ledger.Payload{
+				pathA: utils.LightPayload8('A', 'A'),
+				pathB: utils.LightPayload8('B', 'B'),
+				pathC: nil,
+			}),
+		},
+		{
+			ChunkID:    unittest.IdentifierFixture(),
+			StartState: unittest.StateCommitmentFixture(),
+			Collection: &flow.Collection{Transactions: []*flow.TransactionBody{&tx1, &tx3, &tx4}},
+			Proof: storageProof(map[ledger.Path]*ledger.Payload{
+				pathA: utils.LightPayload8('A', 'A'),
+				pathB: utils.LightPayload8('B', 'C'),
+				pathD: utils.LightPayload8('D', 'D'),
+			}),
+		},
+	}
+	resultIDs := [2]flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()}
+
+	diff, err := DiffChunkDataPacks(resultIDs, packs)
+	require.NoError(t, err)
+
+	assert.Equal(t, resultIDs, diff.ResultIDs)
+	assert.Equal(t, [2]string{stateString(packs[0].StartState), stateString(packs[1].StartState)}, diff.StartStates)
+
+	// the collections diverge from the second transaction on
+	assert.Equal(t, []TransactionDiff{
+		{Index: 1, TransactionIDs: [2]flow.Identifier{tx2.ID(), tx3.ID()}},
+		{Index: 2, TransactionIDs: [2]flow.Identifier{flow.ZeroID, tx4.ID()}},
+	}, diff.Transactions)
+
+	// the registers read with the same value are left out
+	assert.Equal(t, []RegisterDiff{
+		{
+			Path:   hex.EncodeToString(pathB[:]),
+			Key:    "/0/B",
+			Read:   [2]bool{true, true},
+			Values: [2]string{hex.EncodeToString([]byte{'B'}), hex.EncodeToString([]byte{'C'})},
+		},
+		{
+			Path: hex.EncodeToString(pathC[:]),
+			Read: [2]bool{true, false},
+		},
+		{
+			Path:   hex.EncodeToString(pathD[:]),
+			Key:    "/0/D",
+			Read:   [2]bool{false, true},
+			Values: [2]string{"", hex.EncodeToString([]byte{'D'})},
+		},
+	}, diff.Registers)
+}
+
+func TestDiffChunkDataPacks_SystemChunk(t *testing.T) {
+	proof := storageProof(map[ledger.Path]*ledger.Payload{
+		utils.PathByUint8(1): utils.LightPayload8('A', 'A'),
+	})
+	startState := unittest.StateCommitmentFixture()
+	packs := [2]*flow.ChunkDataPack{
+		{ChunkID: unittest.IdentifierFixture(), StartState: startState, Proof: proof},
+		{ChunkID: unittest.IdentifierFixture(), StartState: startState, Proof: proof},
+	}
+
+	diff, err := DiffChunkDataPacks([2]flow.Identifier{}, packs)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Transactions)
+	assert.Empty(t, diff.Registers)
+}
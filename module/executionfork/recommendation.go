@@ -0,0 +1,85 @@
+package executionfork
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Recommendation points out the executors which diverged in an execution fork, given the result considered
+// canonical. No result is considered canonical if the fork can't be resolved from the report alone.
+type Recommendation struct {
+	CanonicalResultID flow.Identifier     `json:"canonical_result_id"`
+	DivergedExecutors flow.IdentifierList `json:"diverged_executors"`
+	Reason            string              `json:"reason"`
+}
+
+// Recommend recommends which executors diverged in the fork. The sealed result for the block, if any among the
+// conflicting results, is canonical. Otherwise, the result committed to by the most executors is canonical,
+// unless several results are committed to by as many executors. flow.ZeroID is given as sealed result ID if the
+// block isn't sealed.
+func Recommend(report *Report, sealedResultID flow.Identifier) Recommendation {
+	recommendation := Recommendation{
+		DivergedExecutors: flow.IdentifierList{},
+	}
+
+	canonical := -1
+	for i, result := range report.Results {
+		if sealedResultID != flow.ZeroID && result.ResultID == sealedResultID {
+			canonical = i
+			recommendation.Reason = fmt.Sprintf("result %x is sealed", sealedResultID)
+			break
+		}
+	}
+
+	if canonical < 0 {
+		tie := false
+		mostExecutors := -1
+		for i, result := range report.Results {
+			executors := len(executorIDs(result))
+			if executors > mostExecutors {
+				canonical, mostExecutors, tie = i, executors, false
+			} else if executors == mostExecutors {
+				tie = true
+			}
+		}
+		if canonical < 0 || tie {
+			recommendation.Reason = "the conflicting results are committed to by as many executors, the divergent chunk " +
+				"has to be re-executed to tell which executors diverged"
+			return recommendation
+		}
+		recommendation.Reason = fmt.Sprintf("result %x is committed to by the most executors (%d)",
+			report.Results[canonical].ResultID, mostExecutors)
+	}
+
+	recommendation.CanonicalResultID = report.Results[canonical].ResultID
+	diverged := make(map[flow.Identifier]struct{})
+	for i, result := range report.Results {
+		if i == canonical {
+			continue
+		}
+		// executors committing to the canonical result as well as to a conflicting one diverged too
+		for _, executorID := range executorIDs(result) {
+			if _, ok := diverged[executorID]; ok {
+				continue
+			}
+			diverged[executorID] = struct{}{}
+			recommendation.DivergedExecutors = append(recommendation.DivergedExecutors, executorID)
+		}
+	}
+	return recommendation
+}
+
+// executorIDs returns the distinct executors committing to the result, in the order of their receipts.
+func executorIDs(result Result) flow.IdentifierList {
+	seen := make(map[flow.Identifier]struct{})
+	executors := flow.IdentifierList{}
+	for _, receipt := range result.Receipts {
+		if _, ok := seen[receipt.ExecutorID]; ok {
+			continue
+		}
+		seen[receipt.ExecutorID] = struct{}{}
+		executors = append(executors, receipt.ExecutorID)
+	}
+	return executors
+}
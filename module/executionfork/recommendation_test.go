@@ -0,0 +1,57 @@
+package executionfork
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// reportWithExecutors returns a report of conflicting results, each committed to by the given executors.
+func reportWithExecutors(executors ...flow.IdentifierList) *Report {
+	report := &Report{}
+	for _, resultExecutors := range executors {
+		result := Result{ResultID: unittest.IdentifierFixture()}
+		for _, executorID := range resultExecutors {
+			result.Receipts = append(result.Receipts, Receipt{
+				ReceiptID:  unittest.IdentifierFixture(),
+				ExecutorID: executorID,
+			})
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+func TestRecommend(t *testing.T) {
+	executors := flow.IdentifierList(unittest.IdentifierListFixture(4))
+
+	t.Run("sealed result", func(t *testing.T) {
+		report := reportWithExecutors(executors[:3], executors[3:])
+		sealedResultID := report.Results[1].ResultID
+
+		recommendation := Recommend(report, sealedResultID)
+		assert.Equal(t, sealedResultID, recommendation.CanonicalResultID)
+		assert.Equal(t, executors[:3], recommendation.DivergedExecutors)
+	})
+
+	t.Run("most executors", func(t *testing.T) {
+		// the executor committing to both results diverged as well
+		report := reportWithExecutors(executors[:3], flow.IdentifierList{executors[3], executors[3], executors[0]})
+
+		recommendation := Recommend(report, flow.ZeroID)
+		assert.Equal(t, report.Results[0].ResultID, recommendation.CanonicalResultID)
+		assert.Equal(t, flow.IdentifierList{executors[3], executors[0]}, recommendation.DivergedExecutors)
+	})
+
+	t.Run("as many executors", func(t *testing.T) {
+		report := reportWithExecutors(executors[:2], executors[2:])
+
+		recommendation := Recommend(report, flow.ZeroID)
+		assert.Equal(t, flow.ZeroID, recommendation.CanonicalResultID)
+		assert.Empty(t, recommendation.DivergedExecutors)
+		assert.NotEmpty(t, recommendation.Reason)
+	})
+}
@@ -0,0 +1,142 @@
+package executionfork
+
+import (
+	"encoding/hex"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Report describes an execution fork: conflicting execution results for the same block, the receipts committing
+// to each of them and the first chunk in which they diverge.
+type Report struct {
+	BlockID        flow.Identifier `json:"block_id"`
+	BlockHeight    uint64          `json:"block_height"`
+	Results        []Result        `json:"results"`
+	DivergentChunk *DivergentChunk `json:"divergent_chunk"` // nil if the chunks of the results don't diverge
+}
+
+// Result is one of the conflicting execution results of a fork.
+type Result struct {
+	ResultID     flow.Identifier `json:"result_id"`
+	InitialState string          `json:"initial_state"`
+	FinalState   string          `json:"final_state"`
+	Chunks       int             `json:"chunks"`
+	Receipts     []Receipt       `json:"receipts"`
+}
+
+// Receipt is a receipt committing to one of the conflicting execution results of a fork.
+type Receipt struct {
+	ReceiptID  flow.Identifier `json:"receipt_id"`
+	ExecutorID flow.Identifier `json:"executor_id"`
+}
+
+// DivergentChunk is the first chunk in which the conflicting execution results of a fork diverge. Its data is
+// listed for each result, in the order of the results of the report. A result with fewer chunks has no data for
+// the chunk.
+type DivergentChunk struct {
+	Index uint64       `json:"index"`
+	Data  []*ChunkData `json:"data"`
+}
+
+// ChunkData is the data of a chunk of an execution result which is compared between the conflicting results.
+type ChunkData struct {
+	CollectionIndex      uint            `json:"collection_index"`
+	StartState           string          `json:"start_state"`
+	EndState             string          `json:"end_state"`
+	EventCollection      flow.Identifier `json:"event_collection"`
+	NumberOfTransactions uint64          `json:"number_of_transactions"`
+	TotalComputationUsed uint64          `json:"total_computation_used"`
+}
+
+// NewReport builds the report of the fork between the given conflicting results for the block. Receipts which
+// don't commit to any of the results are ignored.
+func NewReport(block *flow.Header, results []*flow.ExecutionResult, receipts flow.ExecutionReceiptList) *Report {
+	report := &Report{
+		BlockID:     block.ID(),
+		BlockHeight: block.Height,
+		Results:     make([]Result, 0, len(results)),
+	}
+
+	byResult := receipts.GroupByResultID()
+	for _, result := range results {
+		resultID := result.ID()
+		conflicting := Result{
+			ResultID: resultID,
+			Chunks:   len(result.Chunks),
+			Receipts: []Receipt{},
+		}
+		// results without chunks were rejected before any conflict was detected, but the report
+		// is built without assuming so
+		if initialState, err := result.InitialStateCommit(); err == nil {
+			conflicting.InitialState = stateString(initialState)
+		}
+		if finalState, err := result.FinalStateCommitment(); err == nil {
+			conflicting.FinalState = stateString(finalState)
+		}
+		for _, receipt := range byResult.GetGroup(resultID) {
+			conflicting.Receipts = append(conflicting.Receipts, Receipt{
+				ReceiptID:  receipt.ID(),
+				ExecutorID: receipt.ExecutorID,
+			})
+		}
+		report.Results = append(report.Results, conflicting)
+	}
+
+	report.DivergentChunk = FirstDivergentChunk(results)
+	return report
+}
+
+// FirstDivergentChunk returns the first chunk for which the data of the given results differ, or nil if all
+// their chunks are identical.
+func FirstDivergentChunk(results []*flow.ExecutionResult) *DivergentChunk {
+	maxChunks := 0
+	for _, result := range results {
+		if len(result.Chunks) > maxChunks {
+			maxChunks = len(result.Chunks)
+		}
+	}
+
+	for i := 0; i < maxChunks; i++ {
+		chunk := &DivergentChunk{
+			Index: uint64(i),
+			Data:  make([]*ChunkData, 0, len(results)),
+		}
+		diverged := false
+		for _, result := range results {
+			var data *ChunkData
+			if i < len(result.Chunks) {
+				data = newChunkData(result.Chunks[i])
+			}
+			if len(chunk.Data) > 0 && !sameChunkData(chunk.Data[0], data) {
+				diverged = true
+			}
+			chunk.Data = append(chunk.Data, data)
+		}
+		if diverged {
+			return chunk
+		}
+	}
+	return nil
+}
+
+func newChunkData(chunk *flow.Chunk) *ChunkData {
+	return &ChunkData{
+		CollectionIndex:      chunk.CollectionIndex,
+		StartState:           stateString(chunk.StartState),
+		EndState:             stateString(chunk.EndState),
+		EventCollection:      chunk.EventCollection,
+		NumberOfTransactions: chunk.NumberOfTransactions,
+		TotalComputationUsed: chunk.TotalComputationUsed,
+	}
+}
+
+func sameChunkData(a, b *ChunkData) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func stateString(commit flow.StateCommitment) string {
+	return hex.EncodeToString(commit[:])
+}
@@ -0,0 +1,101 @@
+package executionfork
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// forkResult returns a copy of the result whose chunks diverge from the given chunk on.
+func forkResult(result *flow.ExecutionResult, chunkIndex int) *flow.ExecutionResult {
+	forked := *result
+	forked.Chunks = make(flow.ChunkList, 0, len(result.Chunks))
+	for _, chunk := range result.Chunks {
+		copied := *chunk
+		forked.Chunks = append(forked.Chunks, &copied)
+	}
+	for i := chunkIndex; i < len(forked.Chunks); i++ {
+		forked.Chunks[i].EndState = unittest.StateCommitmentFixture()
+		if i > chunkIndex {
+			forked.Chunks[i].StartState = forked.Chunks[i-1].EndState
+		}
+	}
+	return &forked
+}
+
+func TestNewReport(t *testing.T) {
+	block := unittest.BlockHeaderFixture()
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(block.ID()), unittest.WithChunks(4))
+	forked := forkResult(result, 2)
+
+	executorA := unittest.IdentifierFixture()
+	executorB := unittest.IdentifierFixture()
+	executorC := unittest.IdentifierFixture()
+	receipts := flow.ExecutionReceiptList{
+		unittest.ExecutionReceiptFixture(unittest.WithResult(result), unittest.WithExecutorID(executorA)),
+		unittest.ExecutionReceiptFixture(unittest.WithResult(forked), unittest.WithExecutorID(executorB)),
+		unittest.ExecutionReceiptFixture(unittest.WithResult(result), unittest.WithExecutorID(executorC)),
+		// receipt committing to none of the conflicting results
+		unittest.ExecutionReceiptFixture(unittest.WithExecutorID(unittest.IdentifierFixture())),
+	}
+
+	report := NewReport(&block, []*flow.ExecutionResult{result, forked}, receipts)
+
+	assert.Equal(t, block.ID(), report.BlockID)
+	assert.Equal(t, block.Height, report.BlockHeight)
+	require.Len(t, report.Results, 2)
+
+	assert.Equal(t, result.ID(), report.Results[0].ResultID)
+	assert.Equal(t, 4, report.Results[0].Chunks)
+	require.Len(t, report.Results[0].Receipts, 2)
+	assert.Equal(t, executorA, report.Results[0].Receipts[0].ExecutorID)
+	assert.Equal(t, receipts[0].ID(), report.Results[0].Receipts[0].ReceiptID)
+	assert.Equal(t, executorC, report.Results[0].Receipts[1].ExecutorID)
+
+	assert.Equal(t, forked.ID(), report.Results[1].ResultID)
+	require.Len(t, report.Results[1].Receipts, 1)
+	assert.Equal(t, executorB, report.Results[1].Receipts[0].ExecutorID)
+
+	// the results start from the same state, but end in different states
+	assert.Equal(t, report.Results[0].InitialState, report.Results[1].InitialState)
+	assert.NotEqual(t, report.Results[0].FinalState, report.Results[1].FinalState)
+	finalState := forked.Chunks[3].EndState
+	assert.Equal(t, hex.EncodeToString(finalState[:]), report.Results[1].FinalState)
+
+	require.NotNil(t, report.DivergentChunk)
+	assert.Equal(t, uint64(2), report.DivergentChunk.Index)
+	require.Len(t, report.DivergentChunk.Data, 2)
+	assert.Equal(t, report.DivergentChunk.Data[0].StartState, report.DivergentChunk.Data[1].StartState)
+	assert.NotEqual(t, report.DivergentChunk.Data[0].EndState, report.DivergentChunk.Data[1].EndState)
+}
+
+func TestFirstDivergentChunk(t *testing.T) {
+	result := unittest.ExecutionResultFixture(unittest.WithChunks(3))
+
+	t.Run("identical chunks", func(t *testing.T) {
+		assert.Nil(t, FirstDivergentChunk([]*flow.ExecutionResult{result, forkResult(result, 3)}))
+	})
+
+	t.Run("first chunk", func(t *testing.T) {
+		chunk := FirstDivergentChunk([]*flow.ExecutionResult{result, forkResult(result, 0)})
+		require.NotNil(t, chunk)
+		assert.Equal(t, uint64(0), chunk.Index)
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		shorter := forkResult(result, 3)
+		shorter.Chunks = shorter.Chunks[:2]
+
+		chunk := FirstDivergentChunk([]*flow.ExecutionResult{result, shorter})
+		require.NotNil(t, chunk)
+		assert.Equal(t, uint64(2), chunk.Index)
+		require.Len(t, chunk.Data, 2)
+		assert.NotNil(t, chunk.Data[0])
+		assert.Nil(t, chunk.Data[1])
+	})
+}
@@ -0,0 +1,123 @@
+package executionfork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// Reporter surfaces the execution forks detected by the node: each report is logged and recorded in the metrics,
+// and the first one is kept to be served to operators. Concurrency safe.
+type Reporter struct {
+	log     zerolog.Logger
+	metrics module.ExecutionForkMetrics
+	db      kv.DB // database persisting the first report, nil if the reports are not persisted
+	lock    sync.RWMutex
+	report  *Report // first report, nil as long as no fork is detected
+}
+
+func NewReporter(log zerolog.Logger, metrics module.ExecutionForkMetrics) *Reporter {
+	return &Reporter{
+		log:     log.With().Str("component", "execution_fork_reporter").Logger(),
+		metrics: metrics,
+	}
+}
+
+// NewPersistentReporter creates a reporter persisting the first report in the database, so that the report
+// outlives the node crashing on the fork. The report persisted by a previous run of the node, if any, is served
+// as the first report.
+func NewPersistentReporter(log zerolog.Logger, metrics module.ExecutionForkMetrics, db kv.DB) (*Reporter, error) {
+	r := NewReporter(log, metrics)
+	r.db = db
+
+	report, err := ReadReport(db)
+	if errors.Is(err, storage.ErrNotFound) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.report = report
+	return r, nil
+}
+
+// Report surfaces the given execution fork.
+func (r *Reporter) Report(report *Report) {
+	r.lock.Lock()
+	first := r.report == nil
+	if first {
+		r.report = report
+	}
+	r.lock.Unlock()
+
+	if first && r.db != nil {
+		err := writeReport(r.db, report)
+		if err != nil {
+			r.log.Error().Err(err).Msg("could not persist execution fork report")
+		}
+	}
+
+	r.metrics.ExecutionForkDetected(report.BlockHeight)
+
+	lg := r.log.Error().
+		Hex("block_id", report.BlockID[:]).
+		Uint64("block_height", report.BlockHeight).
+		Int("conflicting_results", len(report.Results))
+	if report.DivergentChunk != nil {
+		lg = lg.Uint64("divergent_chunk", report.DivergentChunk.Index)
+	}
+	reportAsJSON, err := json.Marshal(report)
+	if err != nil {
+		lg = lg.AnErr("report_error", err)
+	} else {
+		lg = lg.RawJSON("report", reportAsJSON)
+	}
+	lg.Msg("execution fork detected")
+}
+
+// Detected returns the first execution fork reported, and false if no fork was reported.
+func (r *Reporter) Detected() (*Report, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.report, r.report != nil
+}
+
+// ReadReport reads the report persisted by an execution node. It returns storage.ErrNotFound if no report was
+// persisted.
+func ReadReport(db kv.DB) (*Report, error) {
+	var encoded []byte
+	err := db.View(operation.RetrieveExecutionForkReport(&encoded))
+	if err != nil {
+		return nil, fmt.Errorf("could not read execution fork report: %w", err)
+	}
+	var report Report
+	err = json.Unmarshal(encoded, &report)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode execution fork report: %w", err)
+	}
+	return &report, nil
+}
+
+// writeReport persists the report, unless a report is already persisted.
+func writeReport(db kv.DB, report *Report) error {
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("could not encode execution fork report: %w", err)
+	}
+	err = db.Update(operation.InsertExecutionForkReport(encoded))
+	if errors.Is(err, storage.ErrAlreadyExists) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not persist execution fork report: %w", err)
+	}
+	return nil
+}
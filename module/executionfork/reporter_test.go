@@ -0,0 +1,73 @@
+package executionfork
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReporter(t *testing.T) {
+	metrics := &module.ExecutionForkMetrics{}
+	reporter := NewReporter(unittest.Logger(), metrics)
+
+	_, detected := reporter.Detected()
+	assert.False(t, detected)
+
+	first := &Report{BlockID: unittest.IdentifierFixture(), BlockHeight: 10}
+	second := &Report{BlockID: unittest.IdentifierFixture(), BlockHeight: 12, DivergentChunk: &DivergentChunk{}}
+	metrics.On("ExecutionForkDetected", uint64(10)).Once()
+	metrics.On("ExecutionForkDetected", uint64(12)).Once()
+
+	reporter.Report(first)
+	reporter.Report(second)
+
+	// the first fork is kept
+	report, detected := reporter.Detected()
+	require.True(t, detected)
+	assert.Equal(t, first, report)
+	metrics.AssertExpectations(t)
+}
+
+func TestPersistentReporter(t *testing.T) {
+	unittest.RunWithDB(t, func(db kv.DB) {
+		metrics := &module.ExecutionForkMetrics{}
+		metrics.On("ExecutionForkDetected", mock.Anything)
+
+		reporter, err := NewPersistentReporter(unittest.Logger(), metrics, db)
+		require.NoError(t, err)
+		_, detected := reporter.Detected()
+		assert.False(t, detected)
+
+		_, err = ReadReport(db)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		first := &Report{BlockID: unittest.IdentifierFixture(), BlockHeight: 10}
+		second := &Report{BlockID: unittest.IdentifierFixture(), BlockHeight: 12}
+		reporter.Report(first)
+		reporter.Report(second)
+
+		// the first report is persisted, and served after a restart
+		persisted, err := ReadReport(db)
+		require.NoError(t, err)
+		assert.Equal(t, first, persisted)
+
+		restarted, err := NewPersistentReporter(unittest.Logger(), metrics, db)
+		require.NoError(t, err)
+		report, detected := restarted.Detected()
+		require.True(t, detected)
+		assert.Equal(t, first, report)
+
+		// the fork detected again after the restart keeps the persisted report
+		restarted.Report(second)
+		persisted, err = ReadReport(db)
+		require.NoError(t, err)
+		assert.Equal(t, first, persisted)
+	})
+}
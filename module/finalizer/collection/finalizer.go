@@ -3,8 +3,6 @@ package collection
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
@@ -13,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Finalizer is a simple wrapper around our temporary state to clean up after a
@@ -20,7 +19,7 @@ import (
 // finalized collection from the mempool and updating the finalized boundary in
 // the cluster state.
 type Finalizer struct {
-	db           *badger.DB
+	db           kv.DB
 	transactions mempool.Transactions
 	prov         network.Engine
 	metrics      module.CollectionMetrics
@@ -28,7 +27,7 @@ type Finalizer struct {
 
 // NewFinalizer creates a new finalizer for collection nodes.
 func NewFinalizer(
-	db *badger.DB,
+	db kv.DB,
 	transactions mempool.Transactions,
 	prov network.Engine,
 	metrics module.CollectionMetrics,
@@ -53,7 +52,7 @@ func NewFinalizer(
 // and being finalized, entities should be present in both the volatile memory
 // pools and persistent storage.
 func (f *Finalizer) MakeFinal(blockID flow.Identifier) error {
-	return operation.RetryOnConflict(f.db.Update, func(tx *badger.Txn) error {
+	return operation.RetryOnConflict(f.db.Update, func(tx kv.Txn) error {
 
 		// retrieve the header of the block we want to finalize
 		var header flow.Header
@@ -5,7 +5,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,11 +18,13 @@ import (
 	"github.com/onflow/flow-go/network/mocknetwork"
 	cluster "github.com/onflow/flow-go/state/cluster/badger"
 	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/memkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestFinalizer(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 
 		// seed the RNG
 		rand.Seed(time.Now().UnixNano())
@@ -38,9 +39,8 @@ func TestFinalizer(t *testing.T) {
 
 		// a helper function to clean up shared state between tests
 		cleanup := func() {
-			// wipe the DB
-			err := db.DropAll()
-			require.Nil(t, err)
+			// replace the DB with an empty one
+			db = memkv.New()
 			// clear the mempool
 			for _, tx := range pool.All() {
 				pool.Rem(tx.ID())
@@ -4,7 +4,6 @@ import (
 	"math/rand"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -15,6 +14,7 @@ import (
 	mockprot "github.com/onflow/flow-go/state/protocol/mock"
 	storage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 	mockstor "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -27,7 +27,7 @@ func LogCleanup(list *[]flow.Identifier) func(flow.Identifier) error {
 }
 
 func TestNewFinalizer(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		headers := &mockstor.Headers{}
 		state := &mockprot.MutableState{}
 		tracer := trace.NewNoopTracer()
@@ -74,7 +74,7 @@ func TestMakeFinalValidChain(t *testing.T) {
 	// this will hold the IDs of blocks clean up
 	var list []flow.Identifier
 
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 
 		// insert the latest finalized height
 		err := db.Update(operation.InsertFinalizedHeight(final.Height))
@@ -132,7 +132,7 @@ func TestMakeFinalInvalidHeight(t *testing.T) {
 	// this will hold the IDs of blocks clean up
 	var list []flow.Identifier
 
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 
 		// insert the latest finalized height
 		err := db.Update(operation.InsertFinalizedHeight(final.Height))
@@ -184,7 +184,7 @@ func TestMakeFinalDuplicate(t *testing.T) {
 	// this will hold the IDs of blocks clean up
 	var list []flow.Identifier
 
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 
 		// insert the latest finalized height
 		err := db.Update(operation.InsertFinalizedHeight(final.Height))
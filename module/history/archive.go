@@ -0,0 +1,265 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Archive reads the blocks of an archive. The blocks are located by binary searching the lookup file of the
+// archive, so only its size is held in memory, and each block is read with a single read of the data file. The
+// archive serves the blocks covered by the lookup file when it was opened. It is safe for concurrent use.
+type Archive struct {
+	dir     string
+	data    *os.File
+	lookup  *os.File
+	entries int    // number of entries of the lookup file
+	heights int    // position in the lookup file of the first entry of kind height
+	first   uint64 // height of the first block of the archive
+}
+
+// Open opens the archive in the given directory for reading.
+func Open(dir string) (*Archive, error) {
+	data, err := os.Open(filepath.Join(dir, DataFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not open data file: %w", err)
+	}
+	err = checkDataHeader(data)
+	if err != nil {
+		_ = data.Close()
+		return nil, err
+	}
+
+	lookup, err := os.Open(filepath.Join(dir, LookupFilename))
+	if err != nil {
+		_ = data.Close()
+		return nil, fmt.Errorf("could not open lookup file: %w", err)
+	}
+
+	a := &Archive{
+		dir:    dir,
+		data:   data,
+		lookup: lookup,
+	}
+	err = a.init()
+	if err != nil {
+		_ = a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// init reads the size of the lookup file and the range of heights it covers.
+func (a *Archive) init() error {
+	info, err := a.lookup.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat lookup file: %w", err)
+	}
+	if info.Size()%int64(entrySize) != 0 {
+		return fmt.Errorf("invalid lookup file size %d: %w", info.Size(), ErrCorrupted)
+	}
+	a.entries = int(info.Size() / int64(entrySize))
+
+	// the entries of kind height are the last ones of the lookup file, sorted by height
+	a.heights, err = a.search(kindHeight, flow.ZeroID)
+	if err != nil {
+		return err
+	}
+	if a.heights == a.entries {
+		return nil
+	}
+
+	first, err := a.entry(a.heights)
+	if err != nil {
+		return err
+	}
+	last, err := a.entry(a.entries - 1)
+	if err != nil {
+		return err
+	}
+	if first.kind != kindHeight || last.kind != kindHeight || last.height()-first.height() != uint64(a.entries-a.heights-1) {
+		return fmt.Errorf("blocks of the lookup file are not contiguous: %w", ErrCorrupted)
+	}
+	a.first = first.height()
+	return nil
+}
+
+// Dir returns the directory of the archive.
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Range returns the heights of the first and last blocks of the archive, and false if it is empty.
+func (a *Archive) Range() (uint64, uint64, bool) {
+	count := a.entries - a.heights
+	if count == 0 {
+		return 0, 0, false
+	}
+	return a.first, a.first + uint64(count) - 1, true
+}
+
+// ByHeight returns the block at the given height. It returns storage.ErrNotFound if the archive does not hold it.
+func (a *Archive) ByHeight(height uint64) (*Block, error) {
+	if height < a.first || height-a.first >= uint64(a.entries-a.heights) {
+		return nil, storage.ErrNotFound
+	}
+	e, err := a.entry(a.heights + int(height-a.first))
+	if err != nil {
+		return nil, err
+	}
+	return a.read(e.location)
+}
+
+// ByBlockID returns the block with the given ID. It returns storage.ErrNotFound if the archive does not hold it.
+func (a *Archive) ByBlockID(blockID flow.Identifier) (*Block, error) {
+	return a.find(kindBlock, blockID)
+}
+
+// ByCollectionID returns the block including the collection with the given ID. It returns storage.ErrNotFound if
+// the archive does not hold it.
+func (a *Archive) ByCollectionID(collID flow.Identifier) (*Block, error) {
+	return a.find(kindCollection, collID)
+}
+
+// ByTransactionID returns the block including the transaction with the given ID. A transaction included in
+// several blocks is located in the first one. It returns storage.ErrNotFound if the archive does not hold it.
+func (a *Archive) ByTransactionID(txID flow.Identifier) (*Block, error) {
+	return a.find(kindTransaction, txID)
+}
+
+// Close closes the archive.
+func (a *Archive) Close() error {
+	dataErr := a.data.Close()
+	lookupErr := a.lookup.Close()
+	if dataErr != nil {
+		return fmt.Errorf("could not close data file: %w", dataErr)
+	}
+	if lookupErr != nil {
+		return fmt.Errorf("could not close lookup file: %w", lookupErr)
+	}
+	return nil
+}
+
+// find reads the block located by the first entry of the lookup file with the given kind and key.
+func (a *Archive) find(k kind, key flow.Identifier) (*Block, error) {
+	i, err := a.search(k, key)
+	if err != nil {
+		return nil, err
+	}
+	if i == a.entries {
+		return nil, storage.ErrNotFound
+	}
+	e, err := a.entry(i)
+	if err != nil {
+		return nil, err
+	}
+	if e.compare(k, key) != 0 {
+		return nil, storage.ErrNotFound
+	}
+	return a.read(e.location)
+}
+
+// search returns the position of the first entry of the lookup file which is not below the given kind and key.
+func (a *Archive) search(k kind, key flow.Identifier) (int, error) {
+	var err error
+	i := sort.Search(a.entries, func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var e entry
+		e, err = a.entry(i)
+		return err != nil || e.compare(k, key) >= 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// entry reads the entry at the given position of the lookup file.
+func (a *Archive) entry(i int) (entry, error) {
+	buf := make([]byte, entrySize)
+	_, err := a.lookup.ReadAt(buf, int64(i)*int64(entrySize))
+	if err != nil {
+		return entry{}, fmt.Errorf("could not read lookup entry %d: %w", i, err)
+	}
+	return decodeEntry(buf), nil
+}
+
+func (a *Archive) read(loc location) (*Block, error) {
+	record := make([]byte, loc.length)
+	_, err := a.data.ReadAt(record, loc.offset)
+	if err != nil {
+		return nil, fmt.Errorf("could not read record at offset %d: %w", loc.offset, err)
+	}
+	return decodeRecord(record)
+}
+
+// Archives is a set of archives, searched in order.
+type Archives []*Archive
+
+// OpenAll opens the archives in the given directories for reading.
+func OpenAll(dirs []string) (Archives, error) {
+	var archives Archives
+	for _, dir := range dirs {
+		archive, err := Open(dir)
+		if err != nil {
+			_ = archives.Close()
+			return nil, fmt.Errorf("could not open archive %s: %w", dir, err)
+		}
+		archives = append(archives, archive)
+	}
+	return archives, nil
+}
+
+// ByHeight returns the block at the given height from the first archive holding it.
+func (a Archives) ByHeight(height uint64) (*Block, error) {
+	return a.search(func(archive *Archive) (*Block, error) { return archive.ByHeight(height) })
+}
+
+// ByBlockID returns the block with the given ID from the first archive holding it.
+func (a Archives) ByBlockID(blockID flow.Identifier) (*Block, error) {
+	return a.search(func(archive *Archive) (*Block, error) { return archive.ByBlockID(blockID) })
+}
+
+// ByCollectionID returns the block including the collection with the given ID from the first archive holding it.
+func (a Archives) ByCollectionID(collID flow.Identifier) (*Block, error) {
+	return a.search(func(archive *Archive) (*Block, error) { return archive.ByCollectionID(collID) })
+}
+
+// ByTransactionID returns the block including the transaction with the given ID from the first archive holding
+// it.
+func (a Archives) ByTransactionID(txID flow.Identifier) (*Block, error) {
+	return a.search(func(archive *Archive) (*Block, error) { return archive.ByTransactionID(txID) })
+}
+
+// Close closes all the archives.
+func (a Archives) Close() error {
+	var err error
+	for _, archive := range a {
+		closeErr := archive.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("could not close archive %s: %w", archive.Dir(), closeErr)
+		}
+	}
+	return err
+}
+
+func (a Archives) search(lookup func(*Archive) (*Block, error)) (*Block, error) {
+	for _, archive := range a {
+		block, err := lookup(archive)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read archive %s: %w", archive.Dir(), err)
+		}
+		return block, nil
+	}
+	return nil, storage.ErrNotFound
+}
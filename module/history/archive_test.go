@@ -0,0 +1,326 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// blockFixtures returns a chain of archived blocks from the given height, each including two collections of two
+// transactions, sealed except the last one.
+func blockFixtures(height uint64, count int) []*Block {
+	var blocks []*Block
+	for i := 0; i < count; i++ {
+		var collections []*flow.Collection
+		var guarantees []*flow.CollectionGuarantee
+		for c := 0; c < 2; c++ {
+			collection := unittest.CollectionFixture(2)
+			collections = append(collections, &collection)
+			guarantees = append(guarantees, unittest.CollectionGuaranteeFixture(func(guarantee *flow.CollectionGuarantee) {
+				guarantee.CollectionID = collection.ID()
+			}))
+		}
+
+		block := unittest.BlockFixture()
+		block.Header.Height = height + uint64(i)
+		block.SetPayload(unittest.PayloadFixture(unittest.WithGuarantees(guarantees...)))
+
+		archived := &Block{Block: &block, Collections: collections}
+		if i < count-1 {
+			archived.Result = unittest.ExecutionResultFixture(unittest.WithBlock(&block))
+			archived.Seal = unittest.Seal.Fixture(unittest.Seal.WithResult(archived.Result))
+			for _, collection := range collections {
+				for _, tx := range collection.Transactions {
+					archived.TransactionResults = append(archived.TransactionResults, flow.TransactionResult{
+						TransactionID: tx.ID(),
+						ErrorMessage:  "failed",
+					})
+					archived.Events = append(archived.Events, unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 10))
+				}
+			}
+		}
+		blocks = append(blocks, archived)
+	}
+	return blocks
+}
+
+func appendBlocks(t *testing.T, dir string, blocks []*Block) {
+	w, err := OpenWriter(dir)
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, w.Append(block))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestArchive(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(100, 5)
+		appendBlocks(t, dir, blocks)
+
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		first, last, ok := archive.Range()
+		require.True(t, ok)
+		assert.Equal(t, uint64(100), first)
+		assert.Equal(t, uint64(104), last)
+
+		for _, block := range blocks {
+			archived, err := archive.ByHeight(block.Block.Header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.ID(), archived.Block.ID())
+			assert.Equal(t, block.Block.Payload.Hash(), archived.Block.Payload.Hash())
+			assert.Equal(t, block.TransactionResults, archived.TransactionResults)
+			assert.Equal(t, block.Events, archived.Events)
+
+			archived, err = archive.ByBlockID(block.Block.ID())
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.ID(), archived.Block.ID())
+
+			collection := block.Collections[1]
+			archived, err = archive.ByCollectionID(collection.ID())
+			require.NoError(t, err)
+			archivedCollection, ok := archived.Collection(collection.ID())
+			require.True(t, ok)
+			assert.Equal(t, collection.ID(), archivedCollection.ID())
+
+			tx := collection.Transactions[1]
+			archived, err = archive.ByTransactionID(tx.ID())
+			require.NoError(t, err)
+			archivedTx, ok := archived.Transaction(tx.ID())
+			require.True(t, ok)
+			assert.Equal(t, tx.ID(), archivedTx.ID())
+		}
+
+		// the last block was not sealed
+		archived, err := archive.ByHeight(104)
+		require.NoError(t, err)
+		assert.Nil(t, archived.Seal)
+		assert.Nil(t, archived.Result)
+		_, ok = archived.TransactionResult(blocks[4].Collections[0].Transactions[0].ID())
+		assert.False(t, ok)
+
+		archived, err = archive.ByHeight(100)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[0].Seal.ID(), archived.Seal.ID())
+		txID := blocks[0].Collections[0].Transactions[0].ID()
+		result, ok := archived.TransactionResult(txID)
+		require.True(t, ok)
+		assert.Equal(t, "failed", result.ErrorMessage)
+		assert.Len(t, archived.TransactionEvents(txID), 1)
+
+		_, err = archive.ByHeight(99)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = archive.ByHeight(105)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = archive.ByTransactionID(unittest.IdentifierFixture())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestAppend(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(10, 4)
+		appendBlocks(t, dir, blocks[:2])
+
+		w, err := OpenWriter(dir)
+		require.NoError(t, err)
+		last, ok := w.LastHeight()
+		require.True(t, ok)
+		assert.Equal(t, uint64(11), last)
+
+		// the blocks must be contiguous
+		require.Error(t, w.Append(blocks[3]))
+		require.NoError(t, w.Append(blocks[2]))
+		require.NoError(t, w.Append(blocks[3]))
+		require.NoError(t, w.Close())
+
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		first, last, ok := archive.Range()
+		require.True(t, ok)
+		assert.Equal(t, uint64(10), first)
+		assert.Equal(t, uint64(13), last)
+		for _, block := range blocks {
+			archived, err := archive.ByBlockID(block.Block.ID())
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.Header.Height, archived.Block.Header.Height)
+		}
+	})
+}
+
+// TestLookup tests that the blocks appended to an archive are served once the writer is closed, and that a
+// transaction included in several blocks is located in the first one.
+func TestLookup(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(0, 3)
+		appendBlocks(t, dir, blocks[:1])
+
+		// include a transaction of the first block in the last one
+		blocks[2].Collections[0].Transactions[0] = blocks[0].Collections[0].Transactions[0]
+		txID := blocks[0].Collections[0].Transactions[0].ID()
+
+		w, err := OpenWriter(dir)
+		require.NoError(t, err)
+		require.NoError(t, w.Append(blocks[1]))
+		require.NoError(t, w.Append(blocks[2]))
+		require.NoError(t, w.Sync())
+
+		// the lookup file does not cover the blocks appended by the open writer
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		_, last, _ := archive.Range()
+		assert.Equal(t, uint64(0), last)
+		_, err = archive.ByBlockID(blocks[1].Block.ID())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		require.NoError(t, archive.Close())
+
+		require.NoError(t, w.Close())
+
+		archive, err = Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+		_, last, _ = archive.Range()
+		assert.Equal(t, uint64(2), last)
+		for _, block := range blocks {
+			archived, err := archive.ByBlockID(block.Block.ID())
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.ID(), archived.Block.ID())
+		}
+
+		archived, err := archive.ByTransactionID(txID)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[0].Block.ID(), archived.Block.ID())
+	})
+}
+
+// TestStaleLookup tests that a writer rebuilds the lookup file not covering all the blocks of the archive, even
+// if it appends no block.
+func TestStaleLookup(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(0, 2)
+		appendBlocks(t, dir, blocks)
+		require.NoError(t, os.Remove(filepath.Join(dir, LookupFilename)))
+
+		_, err := Open(dir)
+		require.Error(t, err)
+
+		appendBlocks(t, dir, nil)
+
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+		first, last, ok := archive.Range()
+		require.True(t, ok)
+		assert.Equal(t, uint64(0), first)
+		assert.Equal(t, uint64(1), last)
+	})
+}
+
+func TestInterruptedAppend(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(0, 3)
+		appendBlocks(t, dir, blocks[:2])
+
+		// simulate an append interrupted after writing the record and some index entries of the next block
+		record, err := encodeRecord(blocks[2])
+		require.NoError(t, err)
+		data, err := os.OpenFile(filepath.Join(dir, DataFilename), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = data.Write(record)
+		require.NoError(t, err)
+		require.NoError(t, data.Close())
+
+		entries := blockEntries(blocks[2], location{})
+		buf := make([]byte, entrySize+entrySize/2)
+		entries[0].encode(buf)
+		index, err := os.OpenFile(filepath.Join(dir, IndexFilename), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = index.Write(buf)
+		require.NoError(t, err)
+		require.NoError(t, index.Close())
+
+		// the incomplete block is ignored by the readers
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		_, last, _ := archive.Range()
+		assert.Equal(t, uint64(1), last)
+		_, err = archive.ByBlockID(blocks[2].Block.ID())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		require.NoError(t, archive.Close())
+
+		// and dropped by the writers
+		appendBlocks(t, dir, blocks[2:])
+
+		archive, err = Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+		_, last, _ = archive.Range()
+		assert.Equal(t, uint64(2), last)
+		archived, err := archive.ByBlockID(blocks[2].Block.ID())
+		require.NoError(t, err)
+		assert.Equal(t, blocks[2].Block.ID(), archived.Block.ID())
+	})
+}
+
+func TestCorruptedRecord(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		blocks := blockFixtures(0, 1)
+		appendBlocks(t, dir, blocks)
+
+		path := filepath.Join(dir, DataFilename)
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xff
+		require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+		archive, err := Open(dir)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		_, err = archive.ByHeight(0)
+		assert.ErrorIs(t, err, ErrCorrupted)
+	})
+}
+
+func TestArchives(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		older := blockFixtures(0, 2)
+		newer := blockFixtures(50, 2)
+		appendBlocks(t, filepath.Join(dir, "older"), older)
+		appendBlocks(t, filepath.Join(dir, "newer"), newer)
+
+		archives, err := OpenAll([]string{filepath.Join(dir, "newer"), filepath.Join(dir, "older")})
+		require.NoError(t, err)
+		defer archives.Close()
+
+		for _, block := range append(older, newer...) {
+			archived, err := archives.ByHeight(block.Block.Header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.ID(), archived.Block.ID())
+
+			txID := block.Collections[0].Transactions[0].ID()
+			archived, err = archives.ByTransactionID(txID)
+			require.NoError(t, err)
+			assert.Equal(t, block.Block.ID(), archived.Block.ID())
+		}
+
+		_, err = archives.ByHeight(10)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		_, err = OpenAll([]string{filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+// Package history implements a portable archive of the history of finalized blocks, with their collections,
+// transactions, execution results and events, which can be served without the node which produced them, for
+// instance once a spork has split the history of the network across nodes.
+//
+// An archive is a directory holding two append-only files: a data file, holding one record per block, and an
+// index file, holding fixed size entries which locate the record of each block by height, block ID, collection
+// ID and transaction ID. The blocks of an archive are contiguous by height, and new blocks can only be appended
+// after the last one. A lookup file holds the entries of the index sorted by key, so that the readers locate the
+// blocks with a binary search instead of loading the index in memory.
+package history
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Block is a finalized block archived with its collections, transactions, execution result and events.
+type Block struct {
+	Block       *flow.Block
+	Collections []*flow.Collection // in the order of the guarantees of the block
+
+	// Seal is the seal of the block, nil if the block was not sealed when it was archived.
+	Seal *flow.Seal
+
+	// Result is the sealed execution result of the block, nil if the block was not sealed, or if the result was
+	// not available, when it was archived, in which case there are no transaction results nor events either.
+	Result             *flow.ExecutionResult
+	TransactionResults []flow.TransactionResult
+	Events             []flow.Event
+}
+
+// Transaction returns the transaction with the given ID included in the block.
+func (b *Block) Transaction(txID flow.Identifier) (*flow.TransactionBody, bool) {
+	for _, collection := range b.Collections {
+		for _, tx := range collection.Transactions {
+			if tx.ID() == txID {
+				return tx, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// TransactionResult returns the result of the transaction with the given ID included in the block.
+func (b *Block) TransactionResult(txID flow.Identifier) (*flow.TransactionResult, bool) {
+	for i := range b.TransactionResults {
+		if b.TransactionResults[i].TransactionID == txID {
+			return &b.TransactionResults[i], true
+		}
+	}
+	return nil, false
+}
+
+// TransactionEvents returns the events emitted by the transaction with the given ID included in the block.
+func (b *Block) TransactionEvents(txID flow.Identifier) []flow.Event {
+	var events []flow.Event
+	for _, event := range b.Events {
+		if event.TransactionID == txID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Collection returns the collection with the given ID included in the block.
+func (b *Block) Collection(collID flow.Identifier) (*flow.Collection, bool) {
+	for _, collection := range b.Collections {
+		if collection.ID() == collID {
+			return collection, true
+		}
+	}
+	return nil, false
+}
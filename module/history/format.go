@@ -0,0 +1,243 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DataFilename is the name of the file of an archive holding the records of the blocks.
+	DataFilename = "blocks.data"
+
+	// IndexFilename is the name of the file of an archive holding the entries locating the records.
+	IndexFilename = "blocks.index"
+
+	// LookupFilename is the name of the file of an archive holding the entries of the index sorted by key, which
+	// the readers binary search.
+	LookupFilename = "blocks.lookup"
+)
+
+// formatVersion is the version of the format of the archives, written in the header of the data file.
+const formatVersion = 1
+
+// dataHeader is the header of the data file: a magic string followed by the format version.
+var dataHeader = []byte{'F', 'L', 'O', 'W', 'H', 'I', 'S', 'T', formatVersion}
+
+// ErrCorrupted is returned when the content of an archive does not match its checksums.
+var ErrCorrupted = errors.New("archive is corrupted")
+
+// Each record of the data file is prefixed with the length and the CRC-32 checksum of its payload, which is the
+// block encoded with msgpack.
+const recordHeaderSize = 8
+
+// kind is the kind of key of an index entry.
+type kind uint8
+
+const (
+	kindBlock       kind = 1
+	kindCollection  kind = 2
+	kindTransaction kind = 3
+
+	// kindHeight is the kind of the entries indexing the blocks by height. It is the last entry written for each
+	// block, so it marks the block as complete.
+	kindHeight kind = 4
+)
+
+// Each entry of the index file is the kind of its key, the key, and the location of the record of the block.
+const (
+	keySize   = len(flow.ZeroID)
+	entrySize = 1 + keySize + 8 + 4
+)
+
+// location is the location of a record in the data file.
+type location struct {
+	offset int64
+	length uint32
+}
+
+// entry is an entry of the index file.
+type entry struct {
+	kind kind
+	key  flow.Identifier // the height is encoded in big endian in the first bytes of the key
+	location
+}
+
+func heightKey(height uint64) flow.Identifier {
+	var key flow.Identifier
+	binary.BigEndian.PutUint64(key[:], height)
+	return key
+}
+
+func (e entry) height() uint64 {
+	return binary.BigEndian.Uint64(e.key[:])
+}
+
+func (e entry) encode(buf []byte) {
+	buf[0] = byte(e.kind)
+	copy(buf[1:], e.key[:])
+	binary.BigEndian.PutUint64(buf[1+keySize:], uint64(e.offset))
+	binary.BigEndian.PutUint32(buf[1+keySize+8:], e.length)
+}
+
+// compare compares the kind and key of the entry with the given kind and key, in the order of the lookup file.
+func (e entry) compare(k kind, key flow.Identifier) int {
+	if e.kind != k {
+		if e.kind < k {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(e.key[:], key[:])
+}
+
+func decodeEntry(buf []byte) entry {
+	var e entry
+	e.kind = kind(buf[0])
+	copy(e.key[:], buf[1:])
+	e.offset = int64(binary.BigEndian.Uint64(buf[1+keySize:]))
+	e.length = binary.BigEndian.Uint32(buf[1+keySize+8:])
+	return e
+}
+
+// blockEntries returns the index entries of the given block, stored at the given location, in the order they
+// are written.
+func blockEntries(block *Block, loc location) []entry {
+	entries := []entry{{kind: kindBlock, key: block.Block.ID(), location: loc}}
+	for _, collection := range block.Collections {
+		entries = append(entries, entry{kind: kindCollection, key: collection.ID(), location: loc})
+		for _, tx := range collection.Transactions {
+			entries = append(entries, entry{kind: kindTransaction, key: tx.ID(), location: loc})
+		}
+	}
+	return append(entries, entry{kind: kindHeight, key: heightKey(block.Block.Header.Height), location: loc})
+}
+
+// encodeRecord encodes the given block into a record of the data file.
+func encodeRecord(block *Block) ([]byte, error) {
+	payload, err := msgpack.Marshal(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode block: %w", err)
+	}
+
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record, uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(payload))
+	copy(record[recordHeaderSize:], payload)
+	return record, nil
+}
+
+// decodeRecord decodes the block of the given record of the data file, after checking its checksum.
+func decodeRecord(record []byte) (*Block, error) {
+	if len(record) < recordHeaderSize || int(binary.BigEndian.Uint32(record)) != len(record)-recordHeaderSize {
+		return nil, fmt.Errorf("invalid record length: %w", ErrCorrupted)
+	}
+	payload := record[recordHeaderSize:]
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(record[4:]) {
+		return nil, fmt.Errorf("invalid record checksum: %w", ErrCorrupted)
+	}
+
+	var block Block
+	err := msgpack.Unmarshal(payload, &block)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode block: %w", err)
+	}
+	return &block, nil
+}
+
+// readIndex reads the entries of the index file of the archive in the given directory, up to the last complete
+// block. The entries written after it belong to a block whose append was interrupted, and are dropped.
+func readIndex(dir string) ([]entry, error) {
+	file, err := os.Open(filepath.Join(dir, IndexFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not open index: %w", err)
+	}
+	defer file.Close()
+
+	var entries []entry
+	complete := 0
+	buf := make([]byte, entrySize)
+	for {
+		_, err := io.ReadFull(file, buf)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read index: %w", err)
+		}
+
+		e := decodeEntry(buf)
+		entries = append(entries, e)
+		if e.kind == kindHeight {
+			complete = len(entries)
+		}
+	}
+
+	return entries[:complete], nil
+}
+
+// checkDataHeader checks that the given data file starts with the header of the supported format.
+func checkDataHeader(file *os.File) error {
+	header := make([]byte, len(dataHeader))
+	_, err := file.ReadAt(header, 0)
+	if err != nil {
+		return fmt.Errorf("could not read data header: %w", err)
+	}
+	if string(header[:len(header)-1]) != string(dataHeader[:len(dataHeader)-1]) {
+		return fmt.Errorf("invalid data header: %w", ErrCorrupted)
+	}
+	if header[len(header)-1] != formatVersion {
+		return fmt.Errorf("unsupported format version %d (supported: %d)", header[len(header)-1], formatVersion)
+	}
+	return nil
+}
+
+// writeLookup writes the lookup file of the archive in the given directory, holding the given index entries
+// sorted by kind and key. The entries of a transaction included in several blocks keep the order of the index,
+// so that the first block including it is found first. The file is replaced atomically, so that the readers
+// opening the archive meanwhile read either the previous or the new lookup file.
+func writeLookup(dir string, entries []entry) error {
+	sorted := make([]entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].compare(sorted[j].kind, sorted[j].key) < 0
+	})
+
+	buf := make([]byte, len(sorted)*entrySize)
+	for i, e := range sorted {
+		e.encode(buf[i*entrySize:])
+	}
+
+	path := filepath.Join(dir, LookupFilename)
+	file, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create lookup file: %w", err)
+	}
+	_, err = file.Write(buf)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return fmt.Errorf("could not write lookup file: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("could not close lookup file: %w", closeErr)
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("could not replace lookup file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Writer appends blocks to an archive. The lookup file of the archive is rebuilt from the index when the writer is
+// closed, so the appended blocks are served by the readers opening the archive after it.
+type Writer struct {
+	dir      string
+	data     *os.File
+	index    *os.File
+	dataSize int64
+	empty    bool
+	last     uint64 // height of the last block of the archive
+	stale    bool   // whether the lookup file does not cover all the blocks of the archive
+}
+
+// OpenWriter opens the archive in the given directory for appending blocks, creating it if it does not exist. The
+// block whose append was interrupted, if any, is dropped.
+func OpenWriter(dir string) (*Writer, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create archive directory: %w", err)
+	}
+
+	data, err := os.OpenFile(filepath.Join(dir, DataFilename), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open data file: %w", err)
+	}
+	index, err := os.OpenFile(filepath.Join(dir, IndexFilename), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		_ = data.Close()
+		return nil, fmt.Errorf("could not open index file: %w", err)
+	}
+
+	w := &Writer{dir: dir, data: data, index: index, empty: true}
+	err = w.recover(dir)
+	if err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// recover positions the writer after the last complete block of the archive, truncating the files if an append
+// was interrupted.
+func (w *Writer) recover(dir string) error {
+	info, err := w.data.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat data file: %w", err)
+	}
+	if info.Size() == 0 {
+		_, err = w.data.Write(dataHeader)
+		if err != nil {
+			return fmt.Errorf("could not write data header: %w", err)
+		}
+	} else {
+		err = checkDataHeader(w.data)
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err := readIndex(dir)
+	if err != nil {
+		return err
+	}
+
+	w.dataSize = int64(len(dataHeader))
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		w.empty = false
+		w.last = last.height()
+		w.dataSize = last.offset + int64(last.length)
+	}
+
+	err = w.index.Truncate(int64(len(entries) * entrySize))
+	if err != nil {
+		return fmt.Errorf("could not truncate index file: %w", err)
+	}
+	err = w.data.Truncate(w.dataSize)
+	if err != nil {
+		return fmt.Errorf("could not truncate data file: %w", err)
+	}
+	_, err = w.index.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("could not seek index file: %w", err)
+	}
+
+	// the lookup file is missing the blocks appended by a writer which was not closed
+	info, err = os.Stat(filepath.Join(dir, LookupFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not stat lookup file: %w", err)
+	}
+	w.stale = err != nil || info.Size() != int64(len(entries)*entrySize)
+	return nil
+}
+
+// LastHeight returns the height of the last block of the archive, and false if it is empty.
+func (w *Writer) LastHeight() (uint64, bool) {
+	return w.last, !w.empty
+}
+
+// Append appends the given block to the archive. Its height must follow the height of the last block of the
+// archive, if it is not empty.
+func (w *Writer) Append(block *Block) error {
+	height := block.Block.Header.Height
+	if !w.empty && height != w.last+1 {
+		return fmt.Errorf("block at height %d does not follow the last block of the archive at height %d", height, w.last)
+	}
+
+	record, err := encodeRecord(block)
+	if err != nil {
+		return err
+	}
+
+	// the record is written before its index entries, so that an interrupted append leaves no entry locating an
+	// incomplete record
+	_, err = w.data.WriteAt(record, w.dataSize)
+	if err != nil {
+		return fmt.Errorf("could not write record: %w", err)
+	}
+
+	entries := blockEntries(block, location{offset: w.dataSize, length: uint32(len(record))})
+	buf := make([]byte, len(entries)*entrySize)
+	for i, e := range entries {
+		e.encode(buf[i*entrySize:])
+	}
+	_, err = w.index.Write(buf)
+	if err != nil {
+		return fmt.Errorf("could not write index entries: %w", err)
+	}
+
+	w.dataSize += int64(len(record))
+	w.last = height
+	w.empty = false
+	w.stale = true
+	return nil
+}
+
+// Sync commits the appended blocks to stable storage.
+func (w *Writer) Sync() error {
+	err := w.data.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync data file: %w", err)
+	}
+	err = w.index.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync index file: %w", err)
+	}
+	return nil
+}
+
+// Close syncs the archive, rebuilds its lookup file if blocks were appended, and closes it. The entries of the
+// index are sorted in memory to rebuild the lookup file.
+func (w *Writer) Close() error {
+	err := w.Sync()
+	if err == nil && w.stale {
+		err = w.writeLookup()
+	}
+	dataErr := w.data.Close()
+	indexErr := w.index.Close()
+	if err != nil {
+		return err
+	}
+	if dataErr != nil {
+		return fmt.Errorf("could not close data file: %w", dataErr)
+	}
+	if indexErr != nil {
+		return fmt.Errorf("could not close index file: %w", indexErr)
+	}
+	return nil
+}
+
+// writeLookup rebuilds the lookup file from the entries of the index.
+func (w *Writer) writeLookup() error {
+	entries, err := readIndex(w.dir)
+	if err != nil {
+		return err
+	}
+	err = writeLookup(w.dir, entries)
+	if err != nil {
+		return err
+	}
+	w.stale = false
+	return nil
+}
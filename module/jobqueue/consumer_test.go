@@ -6,13 +6,13 @@ import (
 	"testing"
 	"time"
 
-	badgerdb "github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -131,7 +131,7 @@ func TestProcessableJobs(t *testing.T) {
 // Test after jobs have been processed, the job status are removed to prevent from memory-leak
 func TestProcessedIndexDeletion(t *testing.T) {
 	setup := func(t *testing.T, f func(c *Consumer, jobs *MockJobs)) {
-		unittest.RunWithBadgerDB(t, func(db *badgerdb.DB) {
+		unittest.RunWithDB(t, func(db kv.DB) {
 			log := unittest.Logger().With().Str("module", "consumer").Logger()
 			jobs := NewMockJobs()
 			progress := badger.NewConsumerProgress(db, "consumer")
@@ -7,6 +7,9 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executionfork"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
 )
 
 type ExecForkActor func([]*flow.IncorporatedResultSeal)
@@ -26,3 +29,28 @@ func LogForkAndCrash(log zerolog.Logger) ExecForkActor {
 		l.Msg("inconsistent seals for the same block")
 	}
 }
+
+// ReportFork reports the execution fork evidenced by the conflicting seals, along with the receipts committing to
+// the conflicting results. It doesn't crash the node by itself: sealing is halted regardless by the
+// ExecForkSuppressor, and it can be followed by LogForkAndCrash to stop the node.
+func ReportFork(log zerolog.Logger, reporter *executionfork.Reporter, receipts storage.ExecutionReceipts) ExecForkActor {
+	return func(conflictingSeals []*flow.IncorporatedResultSeal) {
+		if len(conflictingSeals) == 0 {
+			return
+		}
+		block := conflictingSeals[0].Header
+		results := make([]*flow.ExecutionResult, 0, len(conflictingSeals))
+		for _, s := range conflictingSeals {
+			results = append(results, s.IncorporatedResult.Result)
+		}
+
+		// the fork is reported even if the receipts can't be retrieved, as it's the only response to it
+		blockReceipts, err := receipts.ByBlockID(block.ID())
+		if err != nil {
+			log.Error().Err(err).
+				Hex("block_id", logging.ID(block.ID())).
+				Msg("could not retrieve receipts of execution fork")
+		}
+		reporter.Report(executionfork.NewReport(block, results, blockReceipts))
+	}
+}
@@ -0,0 +1,42 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executionfork"
+	"github.com/onflow/flow-go/module/metrics"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// Test_ReportFork verifies that the execution fork evidenced by conflicting seals is reported
+// with the receipts committing to the conflicting results
+func Test_ReportFork(t *testing.T) {
+	seals := unittest.IncorporatedResultSeal.Fixtures(2)
+	seals[1].Header = seals[0].Header
+	block := seals[0].Header
+
+	executorID := unittest.IdentifierFixture()
+	receipt := unittest.ExecutionReceiptFixture(
+		unittest.WithResult(seals[1].IncorporatedResult.Result),
+		unittest.WithExecutorID(executorID))
+	receipts := &storage.ExecutionReceipts{}
+	receipts.On("ByBlockID", block.ID()).Return(flow.ExecutionReceiptList{receipt}, nil).Once()
+
+	reporter := executionfork.NewReporter(unittest.Logger(), metrics.NewNoopCollector())
+	ReportFork(unittest.Logger(), reporter, receipts)(seals)
+
+	report, detected := reporter.Detected()
+	require.True(t, detected)
+	assert.Equal(t, block.ID(), report.BlockID)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, seals[0].IncorporatedResult.Result.ID(), report.Results[0].ResultID)
+	assert.Empty(t, report.Results[0].Receipts)
+	require.Len(t, report.Results[1].Receipts, 1)
+	assert.Equal(t, executorID, report.Results[1].Receipts[0].ExecutorID)
+	receipts.AssertExpectations(t)
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -16,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 var executionForkErr = fmt.Errorf("forked execution state detected") // sentinel error
@@ -49,14 +49,14 @@ type ExecForkSuppressor struct {
 	lowestHeight     uint64
 	execForkDetected bool
 	onExecFork       ExecForkActor
-	db               *badger.DB
+	db               kv.DB
 	log              zerolog.Logger
 }
 
 // sealSet is a set of seals; internally represented as a map from sealID -> to seal
 type sealSet map[flow.Identifier]*flow.IncorporatedResultSeal
 
-func NewExecStateForkSuppressor(onExecFork ExecForkActor, db *badger.DB, log zerolog.Logger, sealLimit uint) (*ExecForkSuppressor, error) {
+func NewExecStateForkSuppressor(onExecFork ExecForkActor, db kv.DB, log zerolog.Logger, sealLimit uint) (*ExecForkSuppressor, error) {
 	conflictingSeals, err := checkExecutionForkEvidence(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to interface with storage: %w", err)
@@ -333,9 +333,9 @@ func (s *ExecForkSuppressor) enforceConsistentStateTransitions(irSeal, irSeal2 *
 
 // checkExecutionForkDetected checks the database whether evidence
 // about an execution fork is stored. Returns the stored evidence.
-func checkExecutionForkEvidence(db *badger.DB) ([]*flow.IncorporatedResultSeal, error) {
+func checkExecutionForkEvidence(db kv.DB) ([]*flow.IncorporatedResultSeal, error) {
 	var conflictingSeals []*flow.IncorporatedResultSeal
-	err := db.View(func(tx *badger.Txn) error {
+	err := db.View(func(tx kv.Txn) error {
 		err := operation.RetrieveExecutionForkEvidence(&conflictingSeals)(tx)
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil // no evidence in data base; conflictingSeals is still nil slice
@@ -350,8 +350,8 @@ func checkExecutionForkEvidence(db *badger.DB) ([]*flow.IncorporatedResultSeal,
 
 // storeExecutionForkEvidence stores the provided seals in the database
 // as evidence for an execution fork.
-func storeExecutionForkEvidence(conflictingSeals []*flow.IncorporatedResultSeal, db *badger.DB) error {
-	err := operation.RetryOnConflict(db.Update, func(tx *badger.Txn) error {
+func storeExecutionForkEvidence(conflictingSeals []*flow.IncorporatedResultSeal, db kv.DB) error {
+	err := operation.RetryOnConflict(db.Update, func(tx kv.Txn) error {
 		err := operation.InsertExecutionForkEvidence(conflictingSeals)(tx)
 		if errors.Is(err, storage.ErrAlreadyExists) {
 			// some evidence about execution fork already stored;
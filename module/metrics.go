@@ -61,6 +61,43 @@ type NetworkMetrics interface {
 
 	// UnstakedInboundConnections updates the metric tracking the number of inbound connections from unstaked nodes
 	UnstakedInboundConnections(connectionCount uint)
+
+	// PeerPenalized counts the penalties applied to the score of peers which sent messages rejected for the given reason
+	PeerPenalized(reason string)
+
+	// PeerScore tracks the GossipSub score of a peer this node is connected to
+	PeerScore(score float64)
+
+	// GraylistedPeers updates the metric tracking the number of peers whose score is below the graylist threshold
+	GraylistedPeers(count uint)
+
+	// InboundRateLimitExceeded counts the inbound streams and messages dropped on the given channel because the
+	// remote peer exceeded the given rate limit
+	InboundRateLimitExceeded(channel string, limit string)
+
+	// RoleConnections updates the metric tracking the number of connections of this node with nodes of the given role, in the given direction
+	RoleConnections(role string, direction string, connectionCount uint)
+
+	// FanoutSize updates the metric tracking the number of nodes in the fanout computed by the topology of this node
+	FanoutSize(size uint)
+
+	// MeshPeers updates the metric tracking the number of peers in the GossipSub mesh of the given topic
+	MeshPeers(topic string, count uint)
+
+	// RequiredRoleUnreachable updates the metric tracking whether this node is connected to none of the nodes of a role it requires
+	RequiredRoleUnreachable(role string, unreachable bool)
+
+	// ConnectionPruned counts the connections with peers closed by this node for the given reason
+	ConnectionPruned(reason string)
+
+	// OutboundMessageQueued increments the metric tracking the number of messages of the given channel waiting in the outbound queues
+	OutboundMessageQueued(channel string)
+
+	// OutboundMessageDequeued decrements the metric tracking the number of messages of the given channel waiting in the outbound queues
+	OutboundMessageDequeued(channel string)
+
+	// OutboundMessageDropped counts the messages of the given channel dropped by the outbound queues under pressure
+	OutboundMessageDropped(channel string)
 }
 
 type EngineMetrics interface {
@@ -162,7 +199,16 @@ type CollectionMetrics interface {
 	ClusterBlockFinalized(block *cluster.Block)
 }
 
+// ExecutionForkMetrics records the execution forks detected by the node.
+type ExecutionForkMetrics interface {
+	// ExecutionForkDetected records the height of the block for which conflicting execution results were
+	// detected
+	ExecutionForkDetected(blockHeight uint64)
+}
+
 type ConsensusMetrics interface {
+	ExecutionForkMetrics
+
 	// StartCollectionToFinalized reports Metrics C1: Collection Received by CCL→ Collection Included in Finalized Block
 	StartCollectionToFinalized(collectionID flow.Identifier)
 
@@ -186,6 +232,22 @@ type ConsensusMetrics interface {
 
 	// CheckSealingDuration records absolute time for the full sealing check by the consensus match engine
 	CheckSealingDuration(duration time.Duration)
+
+	// FinalizedBlocksWithoutResults records the number of unsealed finalized blocks for which no execution
+	// result is incorporated yet
+	FinalizedBlocksWithoutResults(count uint)
+
+	// ChunksWithMissingApprovals records the number of chunks of the results of unsealed finalized blocks
+	// which haven't collected the approvals required for sealing yet
+	ChunksWithMissingApprovals(count uint)
+
+	// OutstandingApprovalRequests records the number of approval requests sent for the chunks which haven't
+	// collected the approvals required for sealing yet
+	OutstandingApprovalRequests(count uint)
+
+	// EmergencySealableResults records the number of results of unsealed finalized blocks which qualify for
+	// emergency sealing
+	EmergencySealableResults(count uint)
 }
 
 type VerificationMetrics interface {
@@ -338,6 +400,7 @@ type ExecutionMetrics interface {
 	RuntimeMetrics
 	ProviderMetrics
 	WALMetrics
+	ExecutionForkMetrics
 
 	// StartBlockReceivedToExecuted starts a span to trace the duration of a block
 	// from being received for execution to execution being finished
@@ -380,6 +443,18 @@ type ExecutionMetrics interface {
 	ExecutionBlockDataUploadStarted()
 
 	ExecutionBlockDataUploadFinished(dur time.Duration)
+
+	// ExecutionProgramsCacheLookup reports a lookup of a contract program in the programs cache,
+	// distinguishing lookups made before and after the cache was warmed up on startup
+	ExecutionProgramsCacheLookup(hit bool, warmedUp bool)
+
+	// ExecutionProgramsWarmedUp reports the number of programs loaded into the programs cache
+	// on startup and the time it took to load them
+	ExecutionProgramsWarmedUp(count int, dur time.Duration)
+
+	// ExecutionDataPruned reports the height up to which the artifacts of the sealed blocks were pruned, the
+	// approximate number of bytes reclaimed by the pruning and the time it took
+	ExecutionDataPruned(height uint64, reclaimedBytes uint64, dur time.Duration)
 }
 
 type TransactionMetrics interface {
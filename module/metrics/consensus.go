@@ -24,6 +24,15 @@ type ConsensusCollector struct {
 
 	// The number of emergency seals
 	emergencySealedBlocks prometheus.Counter
+
+	// The progress of sealing the unsealed finalized blocks
+	finalizedBlocksWithoutResults prometheus.Gauge
+	chunksWithMissingApprovals    prometheus.Gauge
+	outstandingApprovalRequests   prometheus.Gauge
+	emergencySealableResults      prometheus.Gauge
+
+	// The height of the block for which an execution fork was detected
+	executionForkBlockHeight prometheus.Gauge
 }
 
 // NewConsensusCollector created a new consensus collector
@@ -52,18 +61,58 @@ func NewConsensusCollector(tracer module.Tracer, registerer prometheus.Registere
 		Subsystem: subsystemCompliance,
 		Help:      "the number of blocks sealed in emergency mode",
 	})
+	finalizedBlocksWithoutResults := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "finalized_blocks_without_results",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemSealing,
+		Help:      "the number of unsealed finalized blocks for which no execution result is incorporated",
+	})
+	chunksWithMissingApprovals := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "chunks_with_missing_approvals",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemSealing,
+		Help:      "the number of chunks of the results of unsealed finalized blocks missing the approvals required for sealing",
+	})
+	outstandingApprovalRequests := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "outstanding_approval_requests",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemSealing,
+		Help:      "the number of approval requests sent for the chunks missing the approvals required for sealing",
+	})
+	emergencySealableResults := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "emergency_sealable_results",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemSealing,
+		Help:      "the number of results of unsealed finalized blocks which qualify for emergency sealing",
+	})
+	executionForkBlockHeight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "execution_fork_block_height",
+		Namespace: namespaceConsensus,
+		Subsystem: subsystemSealing,
+		Help:      "the height of the block for which conflicting execution results were detected",
+	})
 	registerer.MustRegister(
 		onReceiptDuration,
 		onApprovalDuration,
 		checkSealingDuration,
 		emergencySealedBlocks,
+		finalizedBlocksWithoutResults,
+		chunksWithMissingApprovals,
+		outstandingApprovalRequests,
+		emergencySealableResults,
+		executionForkBlockHeight,
 	)
 	cc := &ConsensusCollector{
-		tracer:                tracer,
-		onReceiptDuration:     onReceiptDuration,
-		onApprovalDuration:    onApprovalDuration,
-		checkSealingDuration:  checkSealingDuration,
-		emergencySealedBlocks: emergencySealedBlocks,
+		tracer:                        tracer,
+		onReceiptDuration:             onReceiptDuration,
+		onApprovalDuration:            onApprovalDuration,
+		checkSealingDuration:          checkSealingDuration,
+		emergencySealedBlocks:         emergencySealedBlocks,
+		finalizedBlocksWithoutResults: finalizedBlocksWithoutResults,
+		chunksWithMissingApprovals:    chunksWithMissingApprovals,
+		outstandingApprovalRequests:   outstandingApprovalRequests,
+		emergencySealableResults:      emergencySealableResults,
+		executionForkBlockHeight:      executionForkBlockHeight,
 	}
 	return cc
 }
@@ -103,3 +152,28 @@ func (cc *ConsensusCollector) OnApprovalProcessingDuration(duration time.Duratio
 func (cc *ConsensusCollector) CheckSealingDuration(duration time.Duration) {
 	cc.checkSealingDuration.Add(duration.Seconds())
 }
+
+// FinalizedBlocksWithoutResults sets the number of unsealed finalized blocks without incorporated results
+func (cc *ConsensusCollector) FinalizedBlocksWithoutResults(count uint) {
+	cc.finalizedBlocksWithoutResults.Set(float64(count))
+}
+
+// ChunksWithMissingApprovals sets the number of chunks missing the approvals required for sealing
+func (cc *ConsensusCollector) ChunksWithMissingApprovals(count uint) {
+	cc.chunksWithMissingApprovals.Set(float64(count))
+}
+
+// OutstandingApprovalRequests sets the number of approval requests sent for the chunks missing approvals
+func (cc *ConsensusCollector) OutstandingApprovalRequests(count uint) {
+	cc.outstandingApprovalRequests.Set(float64(count))
+}
+
+// EmergencySealableResults sets the number of results which qualify for emergency sealing
+func (cc *ConsensusCollector) EmergencySealableResults(count uint) {
+	cc.emergencySealableResults.Set(float64(count))
+}
+
+// ExecutionForkDetected sets the height of the block for which conflicting execution results were detected
+func (cc *ConsensusCollector) ExecutionForkDetected(blockHeight uint64) {
+	cc.executionForkBlockHeight.Set(float64(blockHeight))
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -60,6 +61,13 @@ type ExecutionCollector struct {
 	executionStateDiskUsage          prometheus.Gauge
 	blockDataUploadsInProgress       prometheus.Gauge
 	blockDataUploadsDuration         prometheus.Histogram
+	programsCacheLookups             *prometheus.CounterVec
+	programsWarmedUp                 prometheus.Gauge
+	programsWarmUpDuration           prometheus.Gauge
+	prunedHeight                     prometheus.Gauge
+	prunedReclaimedBytes             prometheus.Counter
+	pruningDuration                  prometheus.Histogram
+	executionForkBlockHeight         prometheus.Gauge
 }
 
 func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registerer) *ExecutionCollector {
@@ -490,6 +498,56 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 			Name:      "execution_state_disk_usage",
 			Help:      "the disk usage of execution state",
 		}),
+
+		programsCacheLookups: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "programs_cache_lookups_total",
+			Help:      "the number of contract program lookups in the programs cache, by result and warm-up phase",
+		}, []string{LabelResult, LabelWarmedUp}),
+
+		programsWarmedUp: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "programs_warmed_up",
+			Help:      "the number of contract programs loaded into the programs cache on startup",
+		}),
+
+		programsWarmUpDuration: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "programs_warm_up_duration_seconds",
+			Help:      "the time spent loading contract programs into the programs cache on startup",
+		}),
+
+		executionForkBlockHeight: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemIngestion,
+			Name:      "execution_fork_block_height",
+			Help:      "the height of the block for which the executed result conflicts with the sealed result",
+		}),
+
+		prunedHeight: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemPruner,
+			Name:      "pruned_height",
+			Help:      "the height up to which the artifacts of the sealed blocks were pruned",
+		}),
+
+		prunedReclaimedBytes: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemPruner,
+			Name:      "reclaimed_bytes_total",
+			Help:      "the approximate size of the keys and values removed by the pruning of the sealed blocks",
+		}),
+
+		pruningDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemPruner,
+			Name:      "pruning_duration_seconds",
+			Help:      "the time spent pruning a batch of sealed blocks",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30},
+		}),
 	}
 
 	return ec
@@ -657,6 +715,30 @@ func (ec *ExecutionCollector) ExecutionBlockDataUploadFinished(dur time.Duration
 	ec.blockDataUploadsDuration.Observe(float64(dur.Milliseconds()))
 }
 
+func (ec *ExecutionCollector) ExecutionProgramsCacheLookup(hit bool, warmedUp bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	ec.programsCacheLookups.WithLabelValues(result, strconv.FormatBool(warmedUp)).Inc()
+}
+
+func (ec *ExecutionCollector) ExecutionProgramsWarmedUp(count int, dur time.Duration) {
+	ec.programsWarmedUp.Set(float64(count))
+	ec.programsWarmUpDuration.Set(dur.Seconds())
+}
+
+// ExecutionForkDetected reports the height of the block for which the executed result conflicts with the sealed result
+func (ec *ExecutionCollector) ExecutionForkDetected(blockHeight uint64) {
+	ec.executionForkBlockHeight.Set(float64(blockHeight))
+}
+
+func (ec *ExecutionCollector) ExecutionDataPruned(height uint64, reclaimedBytes uint64, dur time.Duration) {
+	ec.prunedHeight.Set(float64(height))
+	ec.prunedReclaimedBytes.Add(float64(reclaimedBytes))
+	ec.pruningDuration.Observe(dur.Seconds())
+}
+
 // TransactionParsed reports the time spent parsing a single transaction
 func (ec *ExecutionCollector) RuntimeTransactionParsed(dur time.Duration) {
 	ec.transactionParseTime.Observe(float64(dur))
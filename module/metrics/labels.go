@@ -13,11 +13,16 @@ const (
 	LabelNodeInfo    = "nodeinfo"
 	LabelNodeVersion = "nodeversion"
 	LabelPriority    = "priority"
+	LabelResult      = "result"
+	LabelWarmedUp    = "warmed_up"
+	LabelReason      = "reason"
+	LabelDirection   = "direction"
 )
 
 const (
 	ChannelOneToOne         = "OneToOne"
 	ChannelOneToOneUnstaked = "OneToOneUnstaked"
+	ChannelUnknown          = "Unknown"
 )
 
 const (
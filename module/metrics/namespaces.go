@@ -43,6 +43,7 @@ const (
 	subsystemCompliance  = "compliance"
 	subsystemHotstuff    = "hotstuff"
 	subsystemMatchEngine = "match"
+	subsystemSealing     = "sealing"
 )
 
 // Execution Subsystems
@@ -53,6 +54,7 @@ const (
 	subsystemRuntime           = "runtime"
 	subsystemProvider          = "provider"
 	subsystemBlockDataUploader = "block_data_uploader"
+	subsystemPruner            = "pruner"
 )
 
 // Verification Subsystems
@@ -30,6 +30,17 @@ type NetworkCollector struct {
 	dnsCacheInvalidationCount       prometheus.Counter
 	unstakedOutboundConnectionCount prometheus.Gauge
 	unstakedInboundConnectionCount  prometheus.Gauge
+	peerPenalties                   *prometheus.CounterVec
+	peerScores                      prometheus.Histogram
+	graylistedPeerCount             prometheus.Gauge
+	inboundRateLimited              *prometheus.CounterVec
+	roleConnectionCount             *prometheus.GaugeVec
+	fanoutSize                      prometheus.Gauge
+	meshPeerCount                   *prometheus.GaugeVec
+	requiredRoleUnreachable         *prometheus.GaugeVec
+	prunedConnections               *prometheus.CounterVec
+	outboundQueueSize               *prometheus.GaugeVec
+	outboundMessagesDropped         *prometheus.CounterVec
 }
 
 func NewNetworkCollector() *NetworkCollector {
@@ -137,6 +148,84 @@ func NewNetworkCollector() *NetworkCollector {
 			Name:      "unstaked_inbound_connection_count",
 			Help:      "the number of inbound connections from unstaked nodes",
 		}),
+
+		peerPenalties: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "peer_penalties_total",
+			Help:      "the number of penalties applied to the score of peers, by reason of the rejected message",
+		}, []string{LabelReason}),
+
+		peerScores: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "peer_score",
+			Help:      "the gossipsub scores of the peers this node is connected to",
+			Buckets:   []float64{-1000, -500, -100, -10, 0, 10, 100},
+		}),
+
+		graylistedPeerCount: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "graylisted_peer_count",
+			Help:      "the number of peers whose score is below the graylist threshold",
+		}),
+
+		inboundRateLimited: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "inbound_rate_limited_total",
+			Help:      "the number of inbound streams and messages dropped because the remote peer exceeded a rate limit",
+		}, []string{LabelChannel, LabelReason}),
+
+		roleConnectionCount: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      "role_connection_count",
+			Help:      "the number of connections of this node with nodes of a role, by direction",
+		}, []string{LabelNodeRole, LabelDirection}),
+
+		fanoutSize: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "fanout_size",
+			Help:      "the number of nodes in the fanout computed by the topology of this node",
+		}),
+
+		meshPeerCount: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "mesh_peer_count",
+			Help:      "the number of peers in the gossipsub mesh of a topic",
+		}, []string{LabelChannel}),
+
+		requiredRoleUnreachable: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "required_role_unreachable",
+			Help:      "whether this node is connected to none of the nodes of a role it requires (1) or not (0)",
+		}, []string{LabelNodeRole}),
+
+		prunedConnections: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "pruned_connections_total",
+			Help:      "the number of connections with peers closed by this node, by reason of the pruning",
+		}, []string{LabelReason}),
+
+		outboundQueueSize: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      "outbound_queue_size",
+			Help:      "the number of messages waiting in the outbound queues, by channel",
+		}, []string{LabelChannel}),
+
+		outboundMessagesDropped: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      "outbound_messages_dropped_total",
+			Help:      "the number of messages dropped by the outbound queues under pressure, by channel",
+		}, []string{LabelChannel}),
 	}
 
 	return nc
@@ -214,3 +303,68 @@ func (nc *NetworkCollector) UnstakedOutboundConnections(connectionCount uint) {
 func (nc *NetworkCollector) UnstakedInboundConnections(connectionCount uint) {
 	nc.unstakedInboundConnectionCount.Set(float64(connectionCount))
 }
+
+// PeerPenalized counts the penalties applied to the score of peers which sent messages rejected for the given reason
+func (nc *NetworkCollector) PeerPenalized(reason string) {
+	nc.peerPenalties.WithLabelValues(reason).Inc()
+}
+
+// PeerScore tracks the GossipSub score of a peer this node is connected to
+func (nc *NetworkCollector) PeerScore(score float64) {
+	nc.peerScores.Observe(score)
+}
+
+// GraylistedPeers updates the metric tracking the number of peers whose score is below the graylist threshold
+func (nc *NetworkCollector) GraylistedPeers(count uint) {
+	nc.graylistedPeerCount.Set(float64(count))
+}
+
+// InboundRateLimitExceeded counts the inbound streams and messages dropped on the given channel because the
+// remote peer exceeded the given rate limit
+func (nc *NetworkCollector) InboundRateLimitExceeded(channel string, limit string) {
+	nc.inboundRateLimited.WithLabelValues(channel, limit).Inc()
+}
+
+// RoleConnections updates the metric tracking the number of connections of this node with nodes of the given role, in the given direction
+func (nc *NetworkCollector) RoleConnections(role string, direction string, connectionCount uint) {
+	nc.roleConnectionCount.WithLabelValues(role, direction).Set(float64(connectionCount))
+}
+
+// FanoutSize updates the metric tracking the number of nodes in the fanout computed by the topology of this node
+func (nc *NetworkCollector) FanoutSize(size uint) {
+	nc.fanoutSize.Set(float64(size))
+}
+
+// MeshPeers updates the metric tracking the number of peers in the GossipSub mesh of the given topic
+func (nc *NetworkCollector) MeshPeers(topic string, count uint) {
+	nc.meshPeerCount.WithLabelValues(topic).Set(float64(count))
+}
+
+// RequiredRoleUnreachable updates the metric tracking whether this node is connected to none of the nodes of a role it requires
+func (nc *NetworkCollector) RequiredRoleUnreachable(role string, unreachable bool) {
+	value := 0.0
+	if unreachable {
+		value = 1
+	}
+	nc.requiredRoleUnreachable.WithLabelValues(role).Set(value)
+}
+
+// ConnectionPruned counts the connections with peers closed by this node for the given reason
+func (nc *NetworkCollector) ConnectionPruned(reason string) {
+	nc.prunedConnections.WithLabelValues(reason).Inc()
+}
+
+// OutboundMessageQueued increments the metric tracking the number of messages of the given channel waiting in the outbound queues
+func (nc *NetworkCollector) OutboundMessageQueued(channel string) {
+	nc.outboundQueueSize.WithLabelValues(channel).Inc()
+}
+
+// OutboundMessageDequeued decrements the metric tracking the number of messages of the given channel waiting in the outbound queues
+func (nc *NetworkCollector) OutboundMessageDequeued(channel string) {
+	nc.outboundQueueSize.WithLabelValues(channel).Dec()
+}
+
+// OutboundMessageDropped counts the messages of the given channel dropped by the outbound queues under pressure
+func (nc *NetworkCollector) OutboundMessageDropped(channel string) {
+	nc.outboundMessagesDropped.WithLabelValues(channel).Inc()
+}
@@ -33,6 +33,18 @@ func (nc *NoopCollector) OnDNSCacheInvalidated()
 func (nc *NoopCollector) OnDNSCacheHit()                                                         {}
 func (nc *NoopCollector) UnstakedOutboundConnections(_ uint)                                     {}
 func (nc *NoopCollector) UnstakedInboundConnections(_ uint)                                      {}
+func (nc *NoopCollector) PeerPenalized(reason string)                                            {}
+func (nc *NoopCollector) PeerScore(score float64)                                                {}
+func (nc *NoopCollector) GraylistedPeers(count uint)                                             {}
+func (nc *NoopCollector) InboundRateLimitExceeded(channel string, limit string)                  {}
+func (nc *NoopCollector) RoleConnections(role string, direction string, connectionCount uint)    {}
+func (nc *NoopCollector) FanoutSize(size uint)                                                   {}
+func (nc *NoopCollector) MeshPeers(topic string, count uint)                                     {}
+func (nc *NoopCollector) RequiredRoleUnreachable(role string, unreachable bool)                  {}
+func (nc *NoopCollector) ConnectionPruned(reason string)                                         {}
+func (nc *NoopCollector) OutboundMessageQueued(channel string)                                   {}
+func (nc *NoopCollector) OutboundMessageDequeued(channel string)                                 {}
+func (nc *NoopCollector) OutboundMessageDropped(channel string)                                  {}
 func (nc *NoopCollector) RanGC(duration time.Duration)                                           {}
 func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                          {}
 func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                         {}
@@ -86,6 +98,10 @@ func (nc *NoopCollector) EmergencySeal()
 func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)                     {}
 func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)                    {}
 func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)                            {}
+func (nc *NoopCollector) FinalizedBlocksWithoutResults(count uint)                               {}
+func (nc *NoopCollector) ChunksWithMissingApprovals(count uint)                                  {}
+func (nc *NoopCollector) OutstandingApprovalRequests(count uint)                                 {}
+func (nc *NoopCollector) EmergencySealableResults(count uint)                                    {}
 func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                             {}
 func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                             {}
 func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                         {}
@@ -146,3 +162,7 @@ func (nc *NoopCollector) ExecutionSync(syncing bool)
 func (nc *NoopCollector) DiskSize(uint64)                                                       {}
 func (nc *NoopCollector) ExecutionBlockDataUploadStarted()                                      {}
 func (nc *NoopCollector) ExecutionBlockDataUploadFinished(dur time.Duration)                    {}
+func (nc *NoopCollector) ExecutionProgramsCacheLookup(hit bool, warmedUp bool)                  {}
+func (nc *NoopCollector) ExecutionProgramsWarmedUp(count int, dur time.Duration)                {}
+func (nc *NoopCollector) ExecutionDataPruned(height uint64, bytes uint64, dur time.Duration)    {}
+func (nc *NoopCollector) ExecutionForkDetected(blockHeight uint64)                              {}
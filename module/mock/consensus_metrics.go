@@ -19,11 +19,31 @@ func (_m *ConsensusMetrics) CheckSealingDuration(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// ChunksWithMissingApprovals provides a mock function with given fields: count
+func (_m *ConsensusMetrics) ChunksWithMissingApprovals(count uint) {
+	_m.Called(count)
+}
+
 // EmergencySeal provides a mock function with given fields:
 func (_m *ConsensusMetrics) EmergencySeal() {
 	_m.Called()
 }
 
+// EmergencySealableResults provides a mock function with given fields: count
+func (_m *ConsensusMetrics) EmergencySealableResults(count uint) {
+	_m.Called(count)
+}
+
+// ExecutionForkDetected provides a mock function with given fields: blockHeight
+func (_m *ConsensusMetrics) ExecutionForkDetected(blockHeight uint64) {
+	_m.Called(blockHeight)
+}
+
+// FinalizedBlocksWithoutResults provides a mock function with given fields: count
+func (_m *ConsensusMetrics) FinalizedBlocksWithoutResults(count uint) {
+	_m.Called(count)
+}
+
 // FinishBlockToSeal provides a mock function with given fields: blockID
 func (_m *ConsensusMetrics) FinishBlockToSeal(blockID flow.Identifier) {
 	_m.Called(blockID)
@@ -44,6 +64,11 @@ func (_m *ConsensusMetrics) OnReceiptProcessingDuration(duration time.Duration)
 	_m.Called(duration)
 }
 
+// OutstandingApprovalRequests provides a mock function with given fields: count
+func (_m *ConsensusMetrics) OutstandingApprovalRequests(count uint) {
+	_m.Called(count)
+}
+
 // StartBlockToSeal provides a mock function with given fields: blockID
 func (_m *ConsensusMetrics) StartBlockToSeal(blockID flow.Identifier) {
 	_m.Called(blockID)
@@ -54,11 +54,31 @@ func (_m *ExecutionMetrics) ExecutionCollectionRequestSent() {
 	_m.Called()
 }
 
+// ExecutionDataPruned provides a mock function with given fields: height, reclaimedBytes, dur
+func (_m *ExecutionMetrics) ExecutionDataPruned(height uint64, reclaimedBytes uint64, dur time.Duration) {
+	_m.Called(height, reclaimedBytes, dur)
+}
+
+// ExecutionForkDetected provides a mock function with given fields: blockHeight
+func (_m *ExecutionMetrics) ExecutionForkDetected(blockHeight uint64) {
+	_m.Called(blockHeight)
+}
+
 // ExecutionLastExecutedBlockHeight provides a mock function with given fields: height
 func (_m *ExecutionMetrics) ExecutionLastExecutedBlockHeight(height uint64) {
 	_m.Called(height)
 }
 
+// ExecutionProgramsCacheLookup provides a mock function with given fields: hit, warmedUp
+func (_m *ExecutionMetrics) ExecutionProgramsCacheLookup(hit bool, warmedUp bool) {
+	_m.Called(hit, warmedUp)
+}
+
+// ExecutionProgramsWarmedUp provides a mock function with given fields: count, dur
+func (_m *ExecutionMetrics) ExecutionProgramsWarmedUp(count int, dur time.Duration) {
+	_m.Called(count, dur)
+}
+
 // ExecutionScriptExecuted provides a mock function with given fields: dur, compUsed
 func (_m *ExecutionMetrics) ExecutionScriptExecuted(dur time.Duration, compUsed uint64) {
 	_m.Called(dur, compUsed)
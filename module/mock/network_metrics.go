@@ -13,11 +13,26 @@ type NetworkMetrics struct {
 	mock.Mock
 }
 
+// ConnectionPruned provides a mock function with given fields: reason
+func (_m *NetworkMetrics) ConnectionPruned(reason string) {
+	_m.Called(reason)
+}
+
 // DNSLookupDuration provides a mock function with given fields: duration
 func (_m *NetworkMetrics) DNSLookupDuration(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// FanoutSize provides a mock function with given fields: size
+func (_m *NetworkMetrics) FanoutSize(size uint) {
+	_m.Called(size)
+}
+
+// GraylistedPeers provides a mock function with given fields: count
+func (_m *NetworkMetrics) GraylistedPeers(count uint) {
+	_m.Called(count)
+}
+
 // InboundConnections provides a mock function with given fields: connectionCount
 func (_m *NetworkMetrics) InboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
@@ -28,6 +43,16 @@ func (_m *NetworkMetrics) InboundProcessDuration(topic string, duration time.Dur
 	_m.Called(topic, duration)
 }
 
+// InboundRateLimitExceeded provides a mock function with given fields: channel, limit
+func (_m *NetworkMetrics) InboundRateLimitExceeded(channel string, limit string) {
+	_m.Called(channel, limit)
+}
+
+// MeshPeers provides a mock function with given fields: topic, count
+func (_m *NetworkMetrics) MeshPeers(topic string, count uint) {
+	_m.Called(topic, count)
+}
+
 // MessageAdded provides a mock function with given fields: priority
 func (_m *NetworkMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
@@ -73,11 +98,46 @@ func (_m *NetworkMetrics) OutboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
 }
 
+// OutboundMessageDequeued provides a mock function with given fields: channel
+func (_m *NetworkMetrics) OutboundMessageDequeued(channel string) {
+	_m.Called(channel)
+}
+
+// OutboundMessageDropped provides a mock function with given fields: channel
+func (_m *NetworkMetrics) OutboundMessageDropped(channel string) {
+	_m.Called(channel)
+}
+
+// OutboundMessageQueued provides a mock function with given fields: channel
+func (_m *NetworkMetrics) OutboundMessageQueued(channel string) {
+	_m.Called(channel)
+}
+
+// PeerPenalized provides a mock function with given fields: reason
+func (_m *NetworkMetrics) PeerPenalized(reason string) {
+	_m.Called(reason)
+}
+
+// PeerScore provides a mock function with given fields: score
+func (_m *NetworkMetrics) PeerScore(score float64) {
+	_m.Called(score)
+}
+
 // QueueDuration provides a mock function with given fields: duration, priority
 func (_m *NetworkMetrics) QueueDuration(duration time.Duration, priority int) {
 	_m.Called(duration, priority)
 }
 
+// RequiredRoleUnreachable provides a mock function with given fields: role, unreachable
+func (_m *NetworkMetrics) RequiredRoleUnreachable(role string, unreachable bool) {
+	_m.Called(role, unreachable)
+}
+
+// RoleConnections provides a mock function with given fields: role, direction, connectionCount
+func (_m *NetworkMetrics) RoleConnections(role string, direction string, connectionCount uint) {
+	_m.Called(role, direction, connectionCount)
+}
+
 // UnstakedInboundConnections provides a mock function with given fields: connectionCount
 func (_m *NetworkMetrics) UnstakedInboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
@@ -14,6 +14,7 @@ import (
 	modmocks "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/signature"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -28,7 +29,8 @@ func TestGetThresholdSignerWithNilPrivateKey(t *testing.T) {
 	epochLookup := new(modmocks.EpochLookup)
 	epochLookup.On("EpochForViewWithFallback", mock.Anything).Return(epoch, nil)
 
-	unittest.RunWithTypedBadgerDB(t, storage.InitSecret, func(db *badger.DB) {
+	unittest.RunWithTypedBadgerDB(t, storage.InitSecret, func(secretsDB *badger.DB) {
+		db := badgerkv.New(secretsDB)
 
 		dkgKeys, err := storage.NewDKGKeys(metrics.NewNoopCollector(), db)
 		assert.NoError(t, err)
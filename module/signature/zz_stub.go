@@ -0,0 +1,25 @@
+// +build !relic
+
+package signature
+
+import (
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/module"
+)
+
+type AggregationProvider struct{}
+
+func NewAggregationProvider(tag string, local module.Local) *AggregationProvider { panic("stub") }
+func (*AggregationProvider) Aggregate([]crypto.Signature) (crypto.Signature, error) {
+	panic("stub")
+}
+
+type AggregationVerifier struct{}
+
+func NewAggregationVerifier(tag string) *AggregationVerifier { panic("stub") }
+func (*AggregationVerifier) Verify([]byte, crypto.Signature, crypto.PublicKey) (bool, error) {
+	panic("stub")
+}
+func (*AggregationVerifier) VerifyMany([]byte, crypto.Signature, []crypto.PublicKey) (bool, error) {
+	panic("stub")
+}
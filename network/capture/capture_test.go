@@ -0,0 +1,291 @@
+package capture_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// createMessage returns the message of an entity request with the given nonce.
+func createMessage(t *testing.T, channel network.Channel, originID flow.Identifier, nonce uint64) *message.Message {
+	payload, err := cborcodec.NewCodec().Encode(&messages.EntityRequest{Nonce: nonce})
+	require.NoError(t, err)
+
+	targetID := unittest.IdentifierFixture()
+	return &message.Message{
+		ChannelID: channel.String(),
+		OriginID:  originID[:],
+		TargetIDs: [][]byte{targetID[:]},
+		Payload:   payload,
+		Type:      "messages.EntityRequest",
+	}
+}
+
+// TestMiddleware_Capture checks that the middleware captures the messages it sends and delivers to the overlay,
+// and that the inbound messages are recorded with the authenticated origin instead of the claimed one.
+func TestMiddleware_Capture(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		codec := cborcodec.NewCodec()
+		writer, err := capture.NewWriter(unittest.Logger(), codec, dir, 0, 0)
+		require.NoError(t, err)
+
+		var overlay network.Overlay
+		wrapped := &mocknetwork.Middleware{}
+		wrapped.On("Start", mock.Anything).Run(func(args mock.Arguments) {
+			overlay = args.Get(0).(network.Overlay)
+		}).Return(nil).Once()
+		wrapped.On("SendDirect", mock.Anything, mock.Anything).Return(nil).Once()
+		wrapped.On("Publish", mock.Anything, mock.Anything).Return(nil).Once()
+		wrapped.On("Stop").Once()
+
+		originID := unittest.IdentifierFixture()
+		// the origin ID claimed by the sender of the inbound message is not the authenticated one
+		inbound := createMessage(t, engine.RequestCollections, unittest.IdentifierFixture(), 1)
+		underlying := &mocknetwork.Overlay{}
+		underlying.On("Receive", originID, inbound).Return(nil).Once()
+
+		mw := capture.NewMiddleware(unittest.Logger(), wrapped, writer)
+		require.NoError(t, mw.Start(underlying))
+		require.NotNil(t, overlay)
+
+		require.NoError(t, overlay.Receive(originID, inbound))
+		require.NoError(t, mw.SendDirect(createMessage(t, engine.RequestCollections, originID, 2), unittest.IdentifierFixture()))
+		require.NoError(t, mw.Publish(createMessage(t, engine.PushBlocks, originID, 3), engine.PushBlocks))
+		mw.Stop()
+
+		wrapped.AssertExpectations(t)
+		underlying.AssertExpectations(t)
+
+		records, err := capture.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+
+		expected := []struct {
+			direction capture.Direction
+			channel   network.Channel
+			nonce     uint64
+		}{
+			{capture.Inbound, engine.RequestCollections, 1},
+			{capture.Outbound, engine.RequestCollections, 2},
+			{capture.Outbound, engine.PushBlocks, 3},
+		}
+		for i, record := range records {
+			assert.Equal(t, expected[i].direction, record.Direction)
+			assert.Equal(t, expected[i].channel, record.Channel)
+			assert.Equal(t, originID, record.OriginID)
+			assert.Len(t, record.TargetIDs, 1)
+			assert.Equal(t, "messages.EntityRequest", record.Type)
+
+			event, err := record.Event(codec)
+			require.NoError(t, err)
+			assert.Equal(t, expected[i].nonce, event.(*messages.EntityRequest).Nonce)
+		}
+	})
+}
+
+// TestMiddleware_CaptureRequests checks that the middleware captures the requests and responses it sends and
+// receives, with the authenticated origin of the inbound ones.
+func TestMiddleware_CaptureRequests(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		writer, err := capture.NewWriter(unittest.Logger(), cborcodec.NewCodec(), dir, 0, 0)
+		require.NoError(t, err)
+
+		localID := unittest.IdentifierFixture()
+		remoteID := unittest.IdentifierFixture()
+		claimedID := unittest.IdentifierFixture()
+
+		// request sent by the node, whose response claims another origin
+		request := createMessage(t, engine.SyncCommittee, localID, 1)
+		response := createMessage(t, engine.SyncCommittee, claimedID, 2)
+		// request received by the node, which claims another origin
+		inboundRequest := createMessage(t, engine.SyncCommittee, claimedID, 3)
+		outboundResponse := createMessage(t, engine.SyncCommittee, localID, 4)
+
+		var overlay network.Overlay
+		wrapped := &mocknetwork.Middleware{}
+		wrapped.On("Start", mock.Anything).Run(func(args mock.Arguments) {
+			overlay = args.Get(0).(network.Overlay)
+		}).Return(nil).Once()
+		wrapped.On("SendRequest", mock.Anything, request, remoteID).Return(response, nil).Once()
+		wrapped.On("Stop").Once()
+
+		underlying := &mocknetwork.Overlay{}
+		underlying.On("ReceiveRequest", remoteID, inboundRequest).Return(outboundResponse, nil).Once()
+
+		mw := capture.NewMiddleware(unittest.Logger(), wrapped, writer)
+		require.NoError(t, mw.Start(underlying))
+
+		_, err = mw.SendRequest(context.Background(), request, remoteID)
+		require.NoError(t, err)
+		_, err = overlay.ReceiveRequest(remoteID, inboundRequest)
+		require.NoError(t, err)
+		mw.Stop()
+
+		wrapped.AssertExpectations(t)
+		underlying.AssertExpectations(t)
+
+		records, err := capture.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+
+		expected := []struct {
+			direction capture.Direction
+			originID  flow.Identifier
+		}{
+			{capture.Outbound, localID},
+			{capture.Inbound, remoteID},
+			{capture.Inbound, remoteID},
+			{capture.Outbound, localID},
+		}
+		for i, record := range records {
+			assert.Equal(t, expected[i].direction, record.Direction)
+			assert.Equal(t, expected[i].originID, record.OriginID)
+		}
+	})
+}
+
+// TestWriter_Rotation checks that the writer rotates the capture files and only keeps the most recent ones.
+func TestWriter_Rotation(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		codec := cborcodec.NewCodec()
+		// each record exceeds the maximum file size, so that it is written to its own file
+		writer, err := capture.NewWriter(unittest.Logger(), codec, dir, 1, 3)
+		require.NoError(t, err)
+
+		mw := capture.NewMiddleware(unittest.Logger(), &mocknetwork.Middleware{}, writer)
+		originID := unittest.IdentifierFixture()
+		for nonce := uint64(0); nonce < 10; nonce++ {
+			msg := createMessage(t, engine.RequestCollections, originID, nonce)
+			mw.Middleware.(*mocknetwork.Middleware).On("SendDirect", msg, mock.Anything).Return(nil).Once()
+			require.NoError(t, mw.SendDirect(msg, unittest.IdentifierFixture()))
+		}
+		require.NoError(t, writer.Close())
+
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+
+		records, err := capture.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		for i, record := range records {
+			event, err := record.Event(codec)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(7+i), event.(*messages.EntityRequest).Nonce)
+		}
+	})
+}
+
+// TestFilter checks the selection of records by direction, channel, origin and time.
+func TestFilter(t *testing.T) {
+	origin1 := unittest.IdentifierFixture()
+	origin2 := unittest.IdentifierFixture()
+	start := time.Now().UTC()
+
+	records := []*capture.Record{
+		{Timestamp: start, Direction: capture.Inbound, Channel: engine.PushBlocks, OriginID: origin1},
+		{Timestamp: start.Add(time.Second), Direction: capture.Outbound, Channel: engine.PushBlocks, OriginID: origin2},
+		{Timestamp: start.Add(2 * time.Second), Direction: capture.Inbound, Channel: engine.RequestCollections, OriginID: origin2},
+	}
+
+	assert.Equal(t, records, capture.Filter{}.Apply(records))
+	assert.Equal(t, []*capture.Record{records[0], records[2]}, capture.Filter{Direction: capture.Inbound}.Apply(records))
+	assert.Equal(t, records[:2], capture.Filter{Channels: []network.Channel{engine.PushBlocks}}.Apply(records))
+	assert.Equal(t, records[1:], capture.Filter{OriginIDs: []flow.Identifier{origin2}}.Apply(records))
+	assert.Equal(t, records[1:2], capture.Filter{From: start.Add(time.Second), To: start.Add(time.Second)}.Apply(records))
+	assert.Empty(t, capture.Filter{Direction: capture.Outbound, Channels: []network.Channel{engine.RequestCollections}}.Apply(records))
+}
+
+// TestFile checks that a subset of a capture can be written to a file and read back, e.g. as a test fixture.
+func TestFile(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		originID := unittest.IdentifierFixture()
+		records := []*capture.Record{
+			{Timestamp: time.Now().UTC(), Direction: capture.Inbound, Channel: engine.PushBlocks, OriginID: originID, Envelope: []byte{1, 2, 3}},
+			{Timestamp: time.Now().UTC(), Direction: capture.Outbound, Channel: engine.PushBlocks, OriginID: originID, TargetIDs: []flow.Identifier{originID}},
+		}
+
+		path := filepath.Join(dir, "fixture.jsonl")
+		require.NoError(t, capture.WriteFile(path, records))
+
+		read, err := capture.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, records, read)
+	})
+}
+
+// TestReplay checks that the inbound messages of a capture are delivered to the engines of a stub network.
+func TestReplay(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		codec := cborcodec.NewCodec()
+		writer, err := capture.NewWriter(unittest.Logger(), codec, dir, 0, 0)
+		require.NoError(t, err)
+
+		var overlay network.Overlay
+		wrapped := &mocknetwork.Middleware{}
+		wrapped.On("Start", mock.Anything).Run(func(args mock.Arguments) {
+			overlay = args.Get(0).(network.Overlay)
+		}).Return(nil)
+		wrapped.On("SendDirect", mock.Anything, mock.Anything).Return(nil)
+		wrapped.On("Stop")
+
+		underlying := &mocknetwork.Overlay{}
+		underlying.On("Receive", mock.Anything, mock.Anything).Return(nil)
+
+		mw := capture.NewMiddleware(unittest.Logger(), wrapped, writer)
+		require.NoError(t, mw.Start(underlying))
+
+		originID := unittest.IdentifierFixture()
+		for nonce := uint64(0); nonce < 5; nonce++ {
+			require.NoError(t, overlay.Receive(originID, createMessage(t, engine.RequestCollections, originID, nonce)))
+		}
+		// neither outbound messages nor messages of channels without engine are replayed
+		require.NoError(t, mw.SendDirect(createMessage(t, engine.RequestCollections, originID, 5), originID))
+		require.NoError(t, overlay.Receive(originID, createMessage(t, engine.PushBlocks, originID, 6)))
+		mw.Stop()
+
+		records, err := capture.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 7)
+
+		me := &module.Local{}
+		me.On("NodeID").Return(unittest.IdentifierFixture())
+		net := stub.NewNetwork(nil, me, stub.NewNetworkHub())
+
+		var nonces []uint64
+		eng := &mocknetwork.Engine{}
+		eng.On("Process", engine.RequestCollections, originID, mock.Anything).Run(func(args mock.Arguments) {
+			nonces = append(nonces, args.Get(2).(*messages.EntityRequest).Nonce)
+		}).Return(nil)
+		_, err = net.Register(engine.RequestCollections, eng)
+		require.NoError(t, err)
+
+		require.NoError(t, net.Replay(records, codec))
+		assert.Equal(t, []uint64{0, 1, 2, 3, 4}, nonces)
+
+		// errors of the engines abort the replay
+		failing := &mocknetwork.Engine{}
+		failing.On("Process", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failure")).Once()
+		_, err = net.Register(engine.PushBlocks, failing)
+		require.NoError(t, err)
+		require.Error(t, net.Replay(records, codec))
+	})
+}
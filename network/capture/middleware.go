@@ -0,0 +1,110 @@
+package capture
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// Middleware wraps a middleware to capture the messages it sends and receives.
+// Inbound messages are captured when the middleware delivers them to the overlay, i.e. after
+// they were authenticated and validated by the wrapped middleware.
+type Middleware struct {
+	network.Middleware
+	log    zerolog.Logger
+	writer *Writer
+}
+
+var _ network.Middleware = (*Middleware)(nil)
+
+// NewMiddleware returns the middleware capturing the messages of mw with the given writer.
+func NewMiddleware(log zerolog.Logger, mw network.Middleware, writer *Writer) *Middleware {
+	return &Middleware{
+		Middleware: mw,
+		log:        log.With().Str("component", "network_capture").Logger(),
+		writer:     writer,
+	}
+}
+
+// Start starts the wrapped middleware, with an overlay capturing the inbound messages.
+func (m *Middleware) Start(overlay network.Overlay) error {
+	return m.Middleware.Start(&captureOverlay{
+		Overlay:         overlay,
+		capture:         m.capture,
+		captureOutbound: m.captureOutbound,
+	})
+}
+
+// Stop stops the wrapped middleware and writes the remaining captured messages.
+func (m *Middleware) Stop() {
+	m.Middleware.Stop()
+
+	err := m.writer.Close()
+	if err != nil {
+		m.log.Error().Err(err).Msg("could not close capture writer")
+	}
+}
+
+func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) error {
+	m.captureOutbound(msg)
+	return m.Middleware.SendDirect(msg, targetID)
+}
+
+// SendRequest captures the request sent by the middleware, and the response it receives.
+func (m *Middleware) SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error) {
+	m.captureOutbound(msg)
+	response, err := m.Middleware.SendRequest(ctx, msg, targetID)
+	if err != nil {
+		return nil, err
+	}
+	// the response is read from the stream opened to the target node
+	m.capture(Inbound, targetID, response)
+	return response, nil
+}
+
+func (m *Middleware) Publish(msg *message.Message, channel network.Channel) error {
+	m.captureOutbound(msg)
+	return m.Middleware.Publish(msg, channel)
+}
+
+// captureOutbound captures a message sent by the node, whose origin ID is set by the overlay.
+func (m *Middleware) captureOutbound(msg *message.Message) {
+	m.capture(Outbound, flow.HashToID(msg.OriginID), msg)
+}
+
+func (m *Middleware) capture(direction Direction, originID flow.Identifier, msg *message.Message) {
+	record, err := newRecord(direction, originID, msg)
+	if err != nil {
+		m.log.Error().Err(err).Str("channel", msg.ChannelID).Msg("could not capture message")
+		return
+	}
+	m.writer.Write(record)
+}
+
+// captureOverlay captures the messages delivered by the middleware to the overlay.
+type captureOverlay struct {
+	network.Overlay
+	capture         func(Direction, flow.Identifier, *message.Message)
+	captureOutbound func(*message.Message)
+}
+
+// Receive captures the message delivered to the overlay, as originating from the authenticated node.
+func (o *captureOverlay) Receive(nodeID flow.Identifier, msg *message.Message) error {
+	o.capture(Inbound, nodeID, msg)
+	return o.Overlay.Receive(nodeID, msg)
+}
+
+// ReceiveRequest captures the request delivered to the overlay, and the response it returns.
+func (o *captureOverlay) ReceiveRequest(nodeID flow.Identifier, msg *message.Message) (*message.Message, error) {
+	o.capture(Inbound, nodeID, msg)
+	response, err := o.Overlay.ReceiveRequest(nodeID, msg)
+	if err != nil {
+		return nil, err
+	}
+	o.captureOutbound(response)
+	return response, nil
+}
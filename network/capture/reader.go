@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadDir reads the records of all the capture files of the directory, oldest first.
+func ReadDir(dir string) ([]*Record, error) {
+	files, err := captureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	for _, file := range files {
+		fileRecords, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+
+	return records, nil
+}
+
+// ReadFile reads the records of a capture file. A truncated last record, e.g. if the node
+// crashed while writing it, is ignored.
+func ReadFile(path string) ([]*Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open capture file: %w", err)
+	}
+	defer file.Close()
+
+	var records []*Record
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode record %d of %s: %w", len(records), path, err)
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// WriteFile writes the records to a capture file, e.g. to store a subset of a capture as a test fixture.
+func WriteFile(path string, records []*Record) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create capture file: %w", err)
+	}
+	defer file.Close()
+
+	buf := bufio.NewWriter(file)
+	encoder := json.NewEncoder(buf)
+	for _, record := range records {
+		err = encoder.Encode(record)
+		if err != nil {
+			return fmt.Errorf("could not encode record: %w", err)
+		}
+	}
+
+	err = buf.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush capture file: %w", err)
+	}
+
+	return file.Close()
+}
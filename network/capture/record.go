@@ -0,0 +1,120 @@
+package capture
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// Direction is the direction of a captured message, from the point of view of the capturing node.
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Record is a message sent or received by the middleware of the capturing node.
+type Record struct {
+	Timestamp time.Time
+	Direction Direction
+	Channel   network.Channel
+	OriginID  flow.Identifier
+	TargetIDs []flow.Identifier
+	// Type is the type of the decoded payload, empty if the payload could not be decoded.
+	Type string
+	// Envelope is the protobuf encoded message, as sent on the wire.
+	Envelope []byte
+}
+
+// newRecord returns the record of the message, without its decoded type.
+// The origin ID is given by the caller, as the origin ID of an inbound message is claimed by its
+// sender while the middleware authenticates the node it was received from.
+func newRecord(direction Direction, originID flow.Identifier, msg *message.Message) (*Record, error) {
+	envelope, err := msg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode message: %w", err)
+	}
+
+	targetIDs := make([]flow.Identifier, 0, len(msg.TargetIDs))
+	for _, targetID := range msg.TargetIDs {
+		targetIDs = append(targetIDs, flow.HashToID(targetID))
+	}
+
+	return &Record{
+		Timestamp: time.Now().UTC(),
+		Direction: direction,
+		Channel:   network.Channel(msg.ChannelID),
+		OriginID:  originID,
+		TargetIDs: targetIDs,
+		Envelope:  envelope,
+	}, nil
+}
+
+// Message decodes the envelope of the record.
+func (r *Record) Message() (*message.Message, error) {
+	var msg message.Message
+	err := msg.Unmarshal(r.Envelope)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode envelope: %w", err)
+	}
+	return &msg, nil
+}
+
+// Event decodes the payload of the record with the given codec.
+func (r *Record) Event(codec network.Codec) (interface{}, error) {
+	msg, err := r.Message()
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := codec.Decode(msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode payload: %w", err)
+	}
+	return event, nil
+}
+
+// Filter selects records. Zero fields match all records.
+type Filter struct {
+	Direction Direction
+	Channels  []network.Channel
+	OriginIDs []flow.Identifier
+	// From and To bound the capture time of the records, inclusively.
+	From time.Time
+	To   time.Time
+}
+
+// Match returns true if the record is selected by the filter.
+func (f Filter) Match(r *Record) bool {
+	if f.Direction != "" && r.Direction != f.Direction {
+		return false
+	}
+	if len(f.Channels) > 0 && !network.ChannelList(f.Channels).Contains(r.Channel) {
+		return false
+	}
+	if len(f.OriginIDs) > 0 && !flow.IdentifierList(f.OriginIDs).Contains(r.OriginID) {
+		return false
+	}
+	if !f.From.IsZero() && r.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && r.Timestamp.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the records selected by the filter, in their original order.
+func (f Filter) Apply(records []*Record) []*Record {
+	var selected []*Record
+	for _, record := range records {
+		if f.Match(record) {
+			selected = append(selected, record)
+		}
+	}
+	return selected
+}
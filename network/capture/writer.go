@@ -0,0 +1,224 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/network"
+)
+
+const (
+	// DefaultMaxFileSize is the size after which the capture file is rotated.
+	DefaultMaxFileSize = 100 * 1024 * 1024
+
+	// DefaultMaxFiles is the number of capture files kept, the oldest ones are removed.
+	DefaultMaxFiles = 10
+
+	// queueSize is the number of records waiting to be written, beyond which new records are dropped.
+	queueSize = 10000
+
+	filePrefix    = "capture-"
+	fileExtension = ".jsonl"
+)
+
+// Writer writes the captured messages to a rotating log of JSON lines files.
+// Records are written asynchronously, so that capturing never blocks the network: records are dropped
+// when the disk cannot keep up with the traffic.
+type Writer struct {
+	sync.RWMutex
+	log         zerolog.Logger
+	codec       network.Codec
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+	records     chan *Record
+	closed      bool
+	done        chan struct{}
+	dropped     uint64
+
+	file *os.File
+	buf  *bufio.Writer
+	size int64
+}
+
+// NewWriter returns a writer of capture files in the given directory, rotating the files once they reach
+// maxFileSize bytes and keeping the maxFiles most recent ones. The codec decodes the type of the payloads.
+func NewWriter(log zerolog.Logger, codec network.Codec, dir string, maxFileSize int64, maxFiles int) (*Writer, error) {
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxFileSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create capture directory: %w", err)
+	}
+
+	w := &Writer{
+		log:         log.With().Str("component", "network_capture").Logger(),
+		codec:       codec,
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		records:     make(chan *Record, queueSize),
+		done:        make(chan struct{}),
+	}
+
+	go w.loop()
+
+	return w, nil
+}
+
+// Write queues the record to be written, or drops it if the queue is full.
+func (w *Writer) Write(record *Record) {
+	w.RLock()
+	defer w.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.records <- record:
+	default:
+		dropped := atomic.AddUint64(&w.dropped, 1)
+		if dropped%1000 == 1 {
+			w.log.Warn().Uint64("dropped", dropped).Msg("capture queue is full, dropping records")
+		}
+	}
+}
+
+// Close writes the queued records and closes the capture file.
+func (w *Writer) Close() error {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.records)
+	w.Unlock()
+
+	<-w.done
+
+	return w.closeFile()
+}
+
+func (w *Writer) loop() {
+	defer close(w.done)
+
+	for record := range w.records {
+		err := w.write(record)
+		if err != nil {
+			w.log.Error().Err(err).Msg("could not write capture record")
+			continue
+		}
+
+		// flush when idle, so that the capture is up to date without flushing every record
+		if len(w.records) == 0 {
+			err = w.buf.Flush()
+			if err != nil {
+				w.log.Error().Err(err).Msg("could not flush capture file")
+			}
+		}
+	}
+}
+
+func (w *Writer) write(record *Record) error {
+	event, err := record.Event(w.codec)
+	if err == nil {
+		record.Type = strings.TrimLeft(fmt.Sprintf("%T", event), "*")
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+	line = append(line, '\n')
+
+	if w.file == nil || (w.size > 0 && w.size+int64(len(line)) > w.maxFileSize) {
+		err = w.rotate()
+		if err != nil {
+			return fmt.Errorf("could not rotate capture file: %w", err)
+		}
+	}
+
+	n, err := w.buf.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// rotate closes the current capture file, opens a new one, and removes the oldest files.
+func (w *Writer) rotate() error {
+	err := w.closeFile()
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Join(w.dir, fmt.Sprintf("%s%020d%s", filePrefix, time.Now().UnixNano(), fileExtension))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create capture file: %w", err)
+	}
+
+	w.file = file
+	w.buf = bufio.NewWriter(file)
+	w.size = 0
+
+	files, err := captureFiles(w.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > w.maxFiles {
+		err = os.Remove(files[0])
+		if err != nil {
+			return fmt.Errorf("could not remove capture file: %w", err)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+func (w *Writer) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.buf.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush capture file: %w", err)
+	}
+
+	err = w.file.Close()
+	if err != nil {
+		return fmt.Errorf("could not close capture file: %w", err)
+	}
+
+	w.file = nil
+	w.buf = nil
+	return nil
+}
+
+// captureFiles returns the capture files of the directory, oldest first.
+func captureFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileExtension))
+	if err != nil {
+		return nil, fmt.Errorf("could not list capture files: %w", err)
+	}
+	// file names contain their creation time
+	sort.Strings(files)
+	return files, nil
+}
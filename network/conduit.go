@@ -3,6 +3,7 @@
 package network
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -81,6 +82,12 @@ type Conduit interface {
 	// The recipients are selected randomly from the targetIDs.
 	Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+	// Request sends the event as a request to the given recipient, and waits for its response.
+	// The request is handled by the engine registered on the same channel by the recipient,
+	// which must implement RequestHandler. It returns an error if the request fails, if the
+	// recipient fails to handle it, or if the context expires before the response is received.
+	Request(ctx context.Context, event interface{}, targetID flow.Identifier) (interface{}, error)
+
 	// Close unsubscribes from the channels of this conduit. After calling close,
 	// the conduit can no longer be used to send a message.
 	Close() error
@@ -31,3 +31,10 @@ type Engine interface {
 type MessageProcessor interface {
 	Process(channel Channel, originID flow.Identifier, message interface{}) error
 }
+
+// RequestHandler is implemented by the engines handling the requests sent with Conduit.Request
+// on their channel. It returns the response to send back to the requester, or an error which is
+// reported to the requester instead.
+type RequestHandler interface {
+	HandleRequest(channel Channel, originID flow.Identifier, request interface{}) (interface{}, error)
+}
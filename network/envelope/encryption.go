@@ -0,0 +1,184 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// contentKeySize is the size of the AES-256 key encrypting the payload of an envelope
+	contentKeySize = 32
+	// ephemeralSeedSize is the size of the seed of the ephemeral keys, large enough for all supported curves
+	ephemeralSeedSize = 64
+)
+
+// encrypt encrypts the payload to the networking keys of the recipients. The payload is encrypted with a
+// random content key, which is wrapped for each recipient with a key agreed (ECDH) between an ephemeral key
+// pair and the networking key of the recipient.
+func encrypt(channel string, payload []byte, recipients flow.IdentityList) ([]RecipientKey, []byte, []byte, error) {
+	contentKey := make([]byte, contentKeySize)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, nil, nil, fmt.Errorf("could not generate content key: %w", err)
+	}
+
+	keys := make([]RecipientKey, 0, len(recipients))
+	for _, recipient := range recipients {
+		key, err := wrapKey(contentKey, recipient)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not wrap content key for %x: %w", recipient.NodeID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	return keys, nonce, aead.Seal(nil, nonce, payload, []byte(channel)), nil
+}
+
+// decrypt decrypts the payload of the envelope with the content key wrapped for the given recipient.
+func decrypt(channel string, e *Envelope, recipientID flow.Identifier, networkKey crypto.PrivateKey) ([]byte, error) {
+	var contentKey []byte
+	for _, key := range e.Keys {
+		if key.RecipientID != recipientID {
+			continue
+		}
+
+		var err error
+		contentKey, err = unwrapKey(key, networkKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not unwrap content key: %w", err)
+		}
+		break
+	}
+	if contentKey == nil {
+		return nil, ErrNotRecipient
+	}
+
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d", len(e.Nonce))
+	}
+
+	payload, err := aead.Open(nil, e.Nonce, e.Payload, []byte(channel))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt payload: %w", err)
+	}
+	return payload, nil
+}
+
+// wrapKey encrypts the content key to the networking key of the recipient.
+func wrapKey(contentKey []byte, recipient *flow.Identity) (RecipientKey, error) {
+	seed := make([]byte, ephemeralSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return RecipientKey{}, fmt.Errorf("could not generate ephemeral seed: %w", err)
+	}
+	ephemeral, err := crypto.GeneratePrivateKey(recipient.NetworkPubKey.Algorithm(), seed)
+	if err != nil {
+		return RecipientKey{}, fmt.Errorf("could not generate ephemeral key: %w", err)
+	}
+
+	ephemeralKey := ephemeral.PublicKey().Encode()
+	kek, err := agreeKey(ephemeral, recipient.NetworkPubKey, ephemeralKey, recipient.NetworkPubKey.Encode())
+	if err != nil {
+		return RecipientKey{}, err
+	}
+
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return RecipientKey{}, err
+	}
+
+	// the key encryption key is only used once, hence the zero nonce
+	return RecipientKey{
+		RecipientID:  recipient.NodeID,
+		EphemeralKey: ephemeralKey,
+		WrappedKey:   aead.Seal(nil, make([]byte, aead.NonceSize()), contentKey, recipient.NodeID[:]),
+	}, nil
+}
+
+// unwrapKey decrypts the content key with the networking key of the recipient.
+func unwrapKey(key RecipientKey, networkKey crypto.PrivateKey) ([]byte, error) {
+	ephemeral, err := crypto.DecodePublicKey(networkKey.Algorithm(), key.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode ephemeral key: %w", err)
+	}
+
+	kek, err := agreeKey(networkKey, ephemeral, key.EphemeralKey, networkKey.PublicKey().Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, make([]byte, aead.NonceSize()), key.WrappedKey, key.RecipientID[:])
+}
+
+// agreeKey derives a key encryption key from the ECDH shared secret of the private and public keys, bound
+// to the ephemeral and recipient public keys.
+func agreeKey(sk crypto.PrivateKey, pk crypto.PublicKey, ephemeralKey []byte, recipientKey []byte) ([]byte, error) {
+	if sk.Algorithm() != pk.Algorithm() {
+		return nil, fmt.Errorf("key algorithms mismatch: %s and %s", sk.Algorithm(), pk.Algorithm())
+	}
+
+	var curve elliptic.Curve
+	switch pk.Algorithm() {
+	case crypto.ECDSAP256:
+		curve = elliptic.P256()
+	case crypto.ECDSASecp256k1:
+		curve = btcec.S256()
+	default:
+		return nil, fmt.Errorf("key algorithm %s does not support key agreement", pk.Algorithm())
+	}
+
+	// public keys are encoded as X||Y
+	encoded := pk.Encode()
+	x := new(big.Int).SetBytes(encoded[:len(encoded)/2])
+	y := new(big.Int).SetBytes(encoded[len(encoded)/2:])
+	secret, _ := curve.ScalarMult(x, y, sk.Encode())
+	if secret.Sign() == 0 {
+		return nil, fmt.Errorf("invalid shared secret")
+	}
+
+	h := hash.NewSHA3_256()
+	for _, data := range [][]byte{secret.Bytes(), ephemeralKey, recipientKey} {
+		if _, err := h.Write(data); err != nil {
+			return nil, fmt.Errorf("could not derive key: %w", err)
+		}
+	}
+	return h.SumHash(), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not create AEAD: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,161 @@
+// Package envelope implements the envelopes wrapping the payloads of the messages sent on private channels.
+//
+// An envelope is signed with the staking key of its sender, so that its origin can be authenticated by any
+// node knowing the identity table, including when the message is relayed by unstaked or intermediate peers.
+// The payload of the envelope is optionally encrypted to the networking keys of its recipients.
+//
+// The signature covers the time the envelope is sealed at, so that an envelope can't be replayed once it is
+// older than MaxEnvelopeAge, and the envelopes opened within this window are remembered by the receiving
+// node to drop their replays. The clocks of the nodes must be synchronized within MaxClockSkew.
+package envelope
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	cborcodec "github.com/onflow/flow-go/model/encoding/cbor"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+)
+
+// Mode is the protection given by the envelopes to the messages of a channel.
+type Mode int
+
+const (
+	// Signed envelopes authenticate their sender but leave their payload in clear.
+	Signed Mode = iota + 1
+	// Encrypted envelopes authenticate their sender and encrypt their payload to their recipients.
+	Encrypted
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Signed:
+		return "signed"
+	case Encrypted:
+		return "encrypted"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(m))
+	}
+}
+
+var (
+	// ErrInvalidSignature is returned when the signature of an envelope does not match its signer.
+	ErrInvalidSignature = errors.New("invalid envelope signature")
+	// ErrUnknownSigner is returned when the signer of an envelope is not a staked node.
+	ErrUnknownSigner = errors.New("unknown envelope signer")
+	// ErrNotRecipient is returned when an encrypted envelope is opened by a node which is not one of its recipients.
+	ErrNotRecipient = errors.New("not a recipient of the envelope")
+	// ErrExpired is returned when an envelope is sealed too long ago, or too far in the future.
+	ErrExpired = errors.New("expired envelope")
+	// ErrReplayed is returned when an envelope is opened again by the same node.
+	ErrReplayed = errors.New("replayed envelope")
+)
+
+const (
+	// MaxEnvelopeAge is the age beyond which an envelope is rejected as expired.
+	MaxEnvelopeAge = 2 * time.Minute
+	// MaxClockSkew is how far in the future an envelope may be sealed, to tolerate the clock skew between nodes.
+	MaxClockSkew = 30 * time.Second
+)
+
+// Envelope wraps the encoded payload of a message with the signature of its sender and, if the payload is
+// encrypted, the content key wrapped for each of its recipients.
+type Envelope struct {
+	SignerID  flow.Identifier
+	SealedAt  int64          // time the envelope is sealed at, in Unix nanoseconds
+	Keys      []RecipientKey // empty if the payload is not encrypted
+	Nonce     []byte         // nonce of the payload encryption, empty if the payload is not encrypted
+	Payload   []byte
+	Signature crypto.Signature
+}
+
+// RecipientKey is the content key of an encrypted envelope, wrapped for one of its recipients.
+type RecipientKey struct {
+	RecipientID  flow.Identifier
+	EphemeralKey []byte // public key of the ephemeral key pair agreed with the recipient networking key
+	WrappedKey   []byte
+}
+
+// signedData is the data covered by the signature of an envelope. The channel is part of it, so that an
+// envelope cannot be replayed on another channel, and the sealing time, so that it cannot be replayed once
+// expired.
+type signedData struct {
+	Channel  string
+	SignerID flow.Identifier
+	SealedAt int64
+	Keys     []RecipientKey
+	Nonce    []byte
+	Payload  []byte
+}
+
+// Encrypted returns true if the payload of the envelope is encrypted.
+func (e *Envelope) Encrypted() bool {
+	return len(e.Keys) > 0
+}
+
+func (e *Envelope) signedData(channel network.Channel) ([]byte, error) {
+	data, err := cborcodec.EncMode.Marshal(signedData{
+		Channel:  channel.String(),
+		SignerID: e.SignerID,
+		SealedAt: e.SealedAt,
+		Keys:     e.Keys,
+		Nonce:    e.Nonce,
+		Payload:  e.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not encode signed data: %w", err)
+	}
+	return data, nil
+}
+
+// Verify checks that the envelope sent on the channel is signed by the staking key of the given signer, and
+// that it is not expired at the given time.
+func (e *Envelope) Verify(channel network.Channel, signer *flow.Identity, hasher hash.Hasher, now time.Time) error {
+	if signer.NodeID != e.SignerID {
+		return fmt.Errorf("envelope signed by %x, not by %x: %w", e.SignerID, signer.NodeID, ErrInvalidSignature)
+	}
+
+	data, err := e.signedData(channel)
+	if err != nil {
+		return err
+	}
+
+	valid, err := signer.StakingPubKey.Verify(e.Signature, data, hasher)
+	if err != nil {
+		return fmt.Errorf("could not verify envelope signature: %w", err)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	// the sealing time is only trusted once the signature is verified
+	sealedAt := time.Unix(0, e.SealedAt)
+	if sealedAt.Before(now.Add(-MaxEnvelopeAge)) || sealedAt.After(now.Add(MaxClockSkew)) {
+		return fmt.Errorf("envelope sealed at %s, checked at %s: %w", sealedAt.UTC(), now.UTC(), ErrExpired)
+	}
+
+	return nil
+}
+
+// Encode returns the wire encoding of the envelope.
+func Encode(e *Envelope) ([]byte, error) {
+	data, err := cborcodec.EncMode.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode envelope: %w", err)
+	}
+	return data, nil
+}
+
+// Decode decodes an envelope from its wire encoding.
+func Decode(data []byte) (*Envelope, error) {
+	var e Envelope
+	err := cborcodec.NewEncoder().Decode(data, &e)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode envelope: %w", err)
+	}
+	return &e, nil
+}
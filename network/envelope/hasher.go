@@ -0,0 +1,14 @@
+// +build relic
+
+package envelope
+
+import (
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/encoding"
+)
+
+// NewHasher returns a hasher for signing and verifying envelopes with staking keys.
+func NewHasher() hash.Hasher {
+	return crypto.NewBLSKMAC(encoding.NetworkEnvelopeTag)
+}
@@ -0,0 +1,11 @@
+// +build !relic
+
+package envelope
+
+import (
+	"github.com/onflow/flow-go/crypto/hash"
+)
+
+func NewHasher() hash.Hasher {
+	panic("NewHasher not supported with non-relic build")
+}
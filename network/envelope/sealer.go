@@ -0,0 +1,199 @@
+package envelope
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/network"
+)
+
+// Sealer seals the payloads of the messages sent on the designated channels in envelopes, and verifies and
+// opens the envelopes received on these channels.
+type Sealer struct {
+	me               module.Local
+	networkKey       crypto.PrivateKey
+	identityProvider id.IdentityProvider
+	newHasher        func() hash.Hasher // hashers are stateful, hence one is created for each signature
+	channels         map[network.Channel]Mode
+	now              func() time.Time
+
+	// the digests of the envelopes opened are kept for two periods of replayWindow, so that each envelope is
+	// remembered until it expires
+	openedLock     sync.Mutex
+	opened         map[flow.Identifier]struct{} // envelopes opened during the current period
+	previousOpened map[flow.Identifier]struct{} // envelopes opened during the previous period
+	periodStart    time.Time
+}
+
+// replayWindow is the longest time an envelope can be opened for, from its first opening to its expiry.
+const replayWindow = MaxEnvelopeAge + MaxClockSkew
+
+// NewSealer returns a sealer of the envelopes of the given channels, signing with the staking key of the
+// local node and decrypting with its networking key. newHasher returns the hasher of the envelope signatures,
+// see NewHasher.
+func NewSealer(
+	me module.Local,
+	networkKey crypto.PrivateKey,
+	identityProvider id.IdentityProvider,
+	newHasher func() hash.Hasher,
+	channels map[network.Channel]Mode,
+) *Sealer {
+	return &Sealer{
+		me:               me,
+		networkKey:       networkKey,
+		identityProvider: identityProvider,
+		newHasher:        newHasher,
+		channels:         channels,
+		now:              time.Now,
+		opened:           make(map[flow.Identifier]struct{}),
+		previousOpened:   make(map[flow.Identifier]struct{}),
+		periodStart:      time.Now(),
+	}
+}
+
+// WithClock sets the clock the envelopes are sealed and checked with, which is time.Now by default.
+func (s *Sealer) WithClock(now func() time.Time) *Sealer {
+	s.now = now
+	s.periodStart = now()
+	return s
+}
+
+// Mode returns the envelope mode of the channel, false if its messages are not sent in envelopes.
+func (s *Sealer) Mode(channel network.Channel) (Mode, bool) {
+	mode, ok := s.channels[channel]
+	return mode, ok
+}
+
+// Seal wraps the encoded payload sent on the channel to the given targets in a signed envelope, and encrypts
+// it to the targets if the channel requires it. It returns the payload unchanged if the channel is not
+// designated for envelopes.
+func (s *Sealer) Seal(channel network.Channel, payload []byte, targetIDs ...flow.Identifier) ([]byte, error) {
+	mode, ok := s.Mode(channel)
+	if !ok {
+		return payload, nil
+	}
+
+	e := &Envelope{
+		SignerID: s.me.NodeID(),
+		SealedAt: s.now().UnixNano(),
+		Payload:  payload,
+	}
+
+	if mode == Encrypted {
+		recipients := s.identityProvider.Identities(filter.HasNodeID(targetIDs...))
+		if len(recipients) != len(flow.IdentifierList(targetIDs).Lookup()) {
+			return nil, fmt.Errorf("could not find the networking keys of all the %d recipients", len(targetIDs))
+		}
+
+		var err error
+		e.Keys, e.Nonce, e.Payload, err = encrypt(channel.String(), payload, recipients)
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt payload: %w", err)
+		}
+	}
+
+	data, err := e.signedData(channel)
+	if err != nil {
+		return nil, err
+	}
+	e.Signature, err = s.me.Sign(data, s.newHasher())
+	if err != nil {
+		return nil, fmt.Errorf("could not sign envelope: %w", err)
+	}
+
+	return Encode(e)
+}
+
+// Verify decodes the envelope received on the channel and checks that it is signed by a staked node, whose
+// identity it returns, and that it is not expired. It does not decrypt the payload, so that the envelopes can
+// be verified by the nodes relaying them.
+func (s *Sealer) Verify(channel network.Channel, data []byte) (*Envelope, *flow.Identity, error) {
+	e, err := Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, ok := s.identityProvider.ByNodeID(e.SignerID)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %x", ErrUnknownSigner, e.SignerID)
+	}
+
+	err = e.Verify(channel, signer, s.newHasher(), s.now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e, signer, nil
+}
+
+// Open verifies the envelope received on the channel and returns its decrypted payload with the identifier
+// of its signer. It returns ErrReplayed if the envelope was already opened. It returns the payload unchanged
+// if the channel is not designated for envelopes.
+func (s *Sealer) Open(channel network.Channel, originID flow.Identifier, data []byte) (flow.Identifier, []byte, error) {
+	mode, ok := s.Mode(channel)
+	if !ok {
+		return originID, data, nil
+	}
+
+	e, signer, err := s.Verify(channel, data)
+	if err != nil {
+		return flow.ZeroID, nil, err
+	}
+
+	if (mode == Encrypted) != e.Encrypted() {
+		return flow.ZeroID, nil, fmt.Errorf("envelope does not match the %s mode of channel %s", mode, channel)
+	}
+
+	err = s.markOpened(channel, e)
+	if err != nil {
+		return flow.ZeroID, nil, err
+	}
+	if !e.Encrypted() {
+		return signer.NodeID, e.Payload, nil
+	}
+
+	payload, err := decrypt(channel.String(), e, s.me.NodeID(), s.networkKey)
+	if err != nil {
+		return flow.ZeroID, nil, err
+	}
+
+	return signer.NodeID, payload, nil
+}
+
+// markOpened records that the envelope received on the channel is opened, and returns ErrReplayed if it was
+// already opened. The envelopes are identified by the digest of their signed data, which the sender of a
+// replay can't alter.
+func (s *Sealer) markOpened(channel network.Channel, e *Envelope) error {
+	data, err := e.signedData(channel)
+	if err != nil {
+		return err
+	}
+	digest := flow.HashToID(hash.NewSHA3_256().ComputeHash(data))
+
+	now := s.now()
+
+	s.openedLock.Lock()
+	defer s.openedLock.Unlock()
+
+	if now.Sub(s.periodStart) >= replayWindow {
+		s.previousOpened = s.opened
+		s.opened = make(map[flow.Identifier]struct{})
+		s.periodStart = now
+	}
+
+	_, opened := s.opened[digest]
+	_, previousOpened := s.previousOpened[digest]
+	if opened || previousOpened {
+		return fmt.Errorf("envelope of %x sealed at %s: %w", e.SignerID, time.Unix(0, e.SealedAt).UTC(), ErrReplayed)
+	}
+	s.opened[digest] = struct{}{}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package envelope_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/envelope"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const (
+	signedChannel    = network.Channel("signed")
+	encryptedChannel = network.Channel("encrypted")
+	clearChannel     = network.Channel("clear")
+)
+
+// sealerFixture returns the sealers of n nodes sharing the same identity table. The staking keys are ECDSA keys,
+// as the BLS keys used in production require the relic build.
+func sealerFixture(t *testing.T, n int) ([]*envelope.Sealer, flow.IdentityList) {
+	identities := make(flow.IdentityList, 0, n)
+	stakingKeys := make([]crypto.PrivateKey, 0, n)
+	networkKeys := make([]crypto.PrivateKey, 0, n)
+	for i := 0; i < n; i++ {
+		stakingKey := unittest.PrivateKeyFixture(crypto.ECDSAP256)
+		networkKey := unittest.PrivateKeyFixture(crypto.ECDSASecp256k1)
+		identity := unittest.IdentityFixture(unittest.WithNetworkingKey(networkKey.PublicKey()))
+		identity.StakingPubKey = stakingKey.PublicKey()
+
+		identities = append(identities, identity)
+		stakingKeys = append(stakingKeys, stakingKey)
+		networkKeys = append(networkKeys, networkKey)
+	}
+
+	channels := map[network.Channel]envelope.Mode{
+		signedChannel:    envelope.Signed,
+		encryptedChannel: envelope.Encrypted,
+	}
+	provider := id.NewFixedIdentityProvider(identities)
+
+	sealers := make([]*envelope.Sealer, 0, n)
+	for i := 0; i < n; i++ {
+		me, err := local.New(identities[i], stakingKeys[i])
+		require.NoError(t, err)
+		sealers = append(sealers, envelope.NewSealer(me, networkKeys[i], provider, hash.NewSHA3_256, channels))
+	}
+
+	return sealers, identities
+}
+
+// TestSealer_Signed tests that the signed envelopes are opened by any node, and authenticate their signer.
+func TestSealer_Signed(t *testing.T) {
+	sealers, identities := sealerFixture(t, 3)
+	payload := []byte("payload")
+
+	data, err := sealers[0].Seal(signedChannel, payload, identities[1].NodeID)
+	require.NoError(t, err)
+
+	// the envelope can be opened by nodes which are not its recipients
+	for _, sealer := range sealers[1:] {
+		signerID, opened, err := sealer.Open(signedChannel, identities[2].NodeID, data)
+		require.NoError(t, err)
+		assert.Equal(t, identities[0].NodeID, signerID)
+		assert.Equal(t, payload, opened)
+	}
+
+	// the envelope cannot be replayed on another designated channel
+	_, _, err = sealers[1].Open(encryptedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrInvalidSignature)
+
+	// the tampered envelopes are rejected
+	e, err := envelope.Decode(data)
+	require.NoError(t, err)
+	e.Payload = []byte("tampered")
+	tampered, err := envelope.Encode(e)
+	require.NoError(t, err)
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, tampered)
+	assert.ErrorIs(t, err, envelope.ErrInvalidSignature)
+
+	// the messages without an envelope are rejected
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, payload)
+	assert.Error(t, err)
+}
+
+// TestSealer_Encrypted tests that the encrypted envelopes are only opened by their recipients.
+func TestSealer_Encrypted(t *testing.T) {
+	sealers, identities := sealerFixture(t, 4)
+	payload := []byte("payload")
+
+	data, err := sealers[0].Seal(encryptedChannel, payload, identities[1].NodeID, identities[2].NodeID)
+	require.NoError(t, err)
+
+	e, err := envelope.Decode(data)
+	require.NoError(t, err)
+	assert.True(t, e.Encrypted())
+	assert.NotContains(t, string(e.Payload), string(payload))
+
+	for _, sealer := range sealers[1:3] {
+		signerID, opened, err := sealer.Open(encryptedChannel, identities[0].NodeID, data)
+		require.NoError(t, err)
+		assert.Equal(t, identities[0].NodeID, signerID)
+		assert.Equal(t, payload, opened)
+	}
+
+	// the other nodes can verify the envelope, but not open it
+	_, signer, err := sealers[3].Verify(encryptedChannel, data)
+	require.NoError(t, err)
+	assert.Equal(t, identities[0], signer)
+	_, _, err = sealers[3].Open(encryptedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrNotRecipient)
+
+	// the envelopes cannot be sealed for unknown recipients
+	_, err = sealers[0].Seal(encryptedChannel, payload, unittest.IdentifierFixture())
+	assert.Error(t, err)
+}
+
+// TestSealer_UndesignatedChannel tests that the payloads of the channels which are not designated for envelopes
+// are left unchanged.
+func TestSealer_UndesignatedChannel(t *testing.T) {
+	sealers, identities := sealerFixture(t, 2)
+	payload := []byte("payload")
+
+	data, err := sealers[0].Seal(clearChannel, payload, identities[1].NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, payload, data)
+
+	originID, opened, err := sealers[1].Open(clearChannel, identities[0].NodeID, data)
+	require.NoError(t, err)
+	assert.Equal(t, identities[0].NodeID, originID)
+	assert.Equal(t, payload, opened)
+}
+
+// TestSealer_UnknownSigner tests that the envelopes of nodes which are not staked are rejected.
+func TestSealer_UnknownSigner(t *testing.T) {
+	sealers, identities := sealerFixture(t, 2)
+	outsiders, _ := sealerFixture(t, 1)
+
+	data, err := outsiders[0].Seal(signedChannel, []byte("payload"), identities[1].NodeID)
+	require.NoError(t, err)
+
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrUnknownSigner)
+}
+
+// TestSealer_Expired tests that the envelopes sealed too long ago, or too far in the future, are rejected.
+func TestSealer_Expired(t *testing.T) {
+	sealers, identities := sealerFixture(t, 2)
+	now := time.Now()
+	sealers[1].WithClock(func() time.Time { return now })
+
+	for _, sealedAt := range []time.Time{
+		now.Add(-envelope.MaxEnvelopeAge - time.Second),
+		now.Add(envelope.MaxClockSkew + time.Second),
+	} {
+		sealedAt := sealedAt
+		data, err := sealers[0].WithClock(func() time.Time { return sealedAt }).
+			Seal(signedChannel, []byte("payload"), identities[1].NodeID)
+		require.NoError(t, err)
+
+		_, _, err = sealers[1].Verify(signedChannel, data)
+		assert.ErrorIs(t, err, envelope.ErrExpired)
+		_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+		assert.ErrorIs(t, err, envelope.ErrExpired)
+	}
+
+	// the envelopes within the clock skew are accepted
+	sealedAt := now.Add(envelope.MaxClockSkew / 2)
+	data, err := sealers[0].WithClock(func() time.Time { return sealedAt }).
+		Seal(signedChannel, []byte("payload"), identities[1].NodeID)
+	require.NoError(t, err)
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	assert.NoError(t, err)
+}
+
+// TestSealer_Replayed tests that an envelope is only opened once, until it expires.
+func TestSealer_Replayed(t *testing.T) {
+	sealers, identities := sealerFixture(t, 3)
+	now := time.Now()
+	clock := func() time.Time { return now }
+	sealers[1].WithClock(clock)
+	sealers[2].WithClock(clock)
+
+	data, err := sealers[0].Seal(signedChannel, []byte("payload"), identities[1].NodeID)
+	require.NoError(t, err)
+
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	require.NoError(t, err)
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrReplayed)
+
+	// the envelopes are opened once by each node
+	_, _, err = sealers[2].Open(signedChannel, identities[0].NodeID, data)
+	assert.NoError(t, err)
+
+	// the replay is still detected once the envelope is about to expire, and rejected as expired afterwards
+	now = now.Add(envelope.MaxEnvelopeAge)
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrReplayed)
+	now = now.Add(time.Second)
+	_, _, err = sealers[1].Open(signedChannel, identities[0].NodeID, data)
+	assert.ErrorIs(t, err, envelope.ErrExpired)
+}
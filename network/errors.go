@@ -4,4 +4,8 @@ import "errors"
 
 var (
 	EmptyTargetList = errors.New("target list empty")
+
+	// ErrRequestNotSupported is returned for the requests sent to a node which does not support the
+	// request/response protocol, e.g. because it runs an older version.
+	ErrRequestNotSupported = errors.New("request/response protocol not supported by the target")
 )
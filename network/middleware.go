@@ -3,6 +3,7 @@
 package network
 
 import (
+	"context"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -37,6 +38,11 @@ type Middleware interface {
 	// a more efficient candidate.
 	SendDirect(msg *message.Message, targetID flow.Identifier) error
 
+	// SendRequest sends msg as a request to the target ID on a 1-1 direct connection, and waits for the response
+	// of the target. It returns an error if the request could not be delivered, if the target failed to handle it,
+	// or if the context expires before the response is received.
+	SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error)
+
 	// Publish publishes a message on the channel. It models a distributed broadcast where the message is meant for all or
 	// a many nodes subscribing to the channel. It does not guarantee the delivery though, and operates on a best
 	// effort.
@@ -73,6 +79,9 @@ type Overlay interface {
 	Identity(peer.ID) (*flow.Identity, bool)
 
 	Receive(nodeID flow.Identifier, msg *message.Message) error
+
+	// ReceiveRequest handles a request received from the given node, and returns the response to send back.
+	ReceiveRequest(nodeID flow.Identifier, msg *message.Message) (*message.Message, error)
 }
 
 // Connection represents an interface to read from & write to a connection.
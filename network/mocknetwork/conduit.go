@@ -3,6 +3,8 @@
 package mocknetwork
 
 import (
+	context "context"
+
 	flow "github.com/onflow/flow-go/model/flow"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -68,6 +70,29 @@ func (_m *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) erro
 	return r0
 }
 
+// Request provides a mock function with given fields: ctx, event, targetID
+func (_m *Conduit) Request(ctx context.Context, event interface{}, targetID flow.Identifier) (interface{}, error) {
+	ret := _m.Called(ctx, event, targetID)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, flow.Identifier) interface{}); ok {
+		r0 = rf(ctx, event, targetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, flow.Identifier) error); ok {
+		r1 = rf(ctx, event, targetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Unicast provides a mock function with given fields: event, targetID
 func (_m *Conduit) Unicast(event interface{}, targetID flow.Identifier) error {
 	ret := _m.Called(event, targetID)
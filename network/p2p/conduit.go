@@ -20,6 +20,10 @@ type UnicastFunc func(channel network.Channel, event interface{}, targetID flow.
 // network to randomly chosen subset of nodes from targetIDs
 type MulticastFunc func(channel network.Channel, event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+// RequestFunc is a function that sends the event as a request to the target ID, and returns
+// the response of the target.
+type RequestFunc func(ctx context.Context, channel network.Channel, event interface{}, targetID flow.Identifier) (interface{}, error)
+
 // CloseFunc is a function that unsubscribes the conduit from the channel
 type CloseFunc func(channel network.Channel) error
 
@@ -33,6 +37,7 @@ type Conduit struct {
 	publish   PublishFunc
 	unicast   UnicastFunc
 	multicast MulticastFunc
+	request   RequestFunc
 	close     CloseFunc
 }
 
@@ -66,6 +71,15 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.multicast(c.channel, event, num, targetIDs...)
 }
 
+// Request sends the event as a request to the given recipient, and waits for its response.
+// It fails if the context expires before the response is received.
+func (c *Conduit) Request(ctx context.Context, event interface{}, targetID flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+	return c.request(ctx, c.channel, event, targetID)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s already closed", c.channel)
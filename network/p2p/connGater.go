@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/control"
@@ -14,16 +15,19 @@ import (
 var _ connmgr.ConnectionGater = (*ConnGater)(nil)
 
 // ConnGater is the implementation of the libp2p connmgr.ConnectionGater interface
-// It provides node allowlisting by libp2p peer.ID which is derived from the node public networking key
+// It provides node allowlisting by libp2p peer.ID which is derived from the node public networking key,
+// and the temporary denial of allowlisted peers which misbehaved
 type ConnGater struct {
 	sync.RWMutex
-	peerIDAllowlist map[peer.ID]struct{} // the in-memory map of approved peer IDs
+	peerIDAllowlist map[peer.ID]struct{}  // the in-memory map of approved peer IDs
+	peerIDDenylist  map[peer.ID]time.Time // the in-memory map of denied peer IDs, with the time until which they are denied
 	log             zerolog.Logger
 }
 
 func NewConnGater(log zerolog.Logger) *ConnGater {
 	cg := &ConnGater{
-		log: log,
+		log:            log,
+		peerIDDenylist: make(map[peer.ID]time.Time),
 	}
 	return cg
 }
@@ -47,6 +51,15 @@ func (c *ConnGater) update(pids peer.IDSlice) {
 	c.log.Info().Msg("approved list of peers updated")
 }
 
+// deny rejects the connections with the peer until the given time, even if it is allowlisted
+func (c *ConnGater) deny(pid peer.ID, until time.Time) {
+	c.Lock()
+	c.peerIDDenylist[pid] = until
+	c.Unlock()
+
+	c.log.Info().Str("peer_id", pid.Pretty()).Time("until", until).Msg("peer denied")
+}
+
 // InterceptPeerDial - a callback which allows or disallows outbound connection
 func (c *ConnGater) InterceptPeerDial(p peer.ID) bool {
 	return c.validPeerID(p)
@@ -89,8 +102,16 @@ func (c *ConnGater) InterceptUpgraded(network.Conn) (allow bool, reason control.
 }
 
 func (c *ConnGater) validPeerID(p peer.ID) bool {
-	c.RLock()
-	defer c.RUnlock()
+	c.Lock()
+	defer c.Unlock()
+
+	if until, denied := c.peerIDDenylist[p]; denied {
+		if time.Now().Before(until) {
+			return false
+		}
+		delete(c.peerIDDenylist, p)
+	}
+
 	_, ok := c.peerIDAllowlist[p]
 	return ok
 }
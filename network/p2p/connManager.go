@@ -1,7 +1,10 @@
 package p2p
 
 import (
+	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/network"
@@ -14,6 +17,38 @@ import (
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 )
 
+const (
+	// PruneReasonIdle is the reason of the pruning of a connection without streams, trimmed as the node has
+	// more connections than its high watermark
+	PruneReasonIdle = "idle"
+	// PruneReasonLowValue is the reason of the pruning of a connection with streams, trimmed as the node has
+	// more connections than its high watermark and the peer has the lowest value among the unprotected peers
+	PruneReasonLowValue = "low_value"
+	// PruneReasonNotInFanout is the reason of the pruning of a connection with a peer which is not part of the
+	// fanout of the node anymore
+	PruneReasonNotInFanout = "not_in_fanout"
+)
+
+// ConnLimits configures the trimming of the connections of the node by the connection manager. The connections
+// which are protected, e.g. with the peers of the topology fanout, the GossipSub mesh peers of the subscribed
+// channels and the peers a stream is being set up with, and the connections carrying Flow streams are never trimmed.
+type ConnLimits struct {
+	HighWater     int           // number of connections above which the connections are trimmed, 0 disables trimming
+	LowWater      int           // number of connections left once the connections are trimmed
+	GracePeriod   time.Duration // duration after being opened during which a connection is not trimmed
+	SilencePeriod time.Duration // minimum duration between two trims
+}
+
+// DefaultConnLimits are the default connection limits, which disable the trimming of the connections.
+var DefaultConnLimits = ConnLimits{
+	GracePeriod:   time.Minute,
+	SilencePeriod: 10 * time.Second,
+}
+
+func (l ConnLimits) enabled() bool {
+	return l.HighWater > 0
+}
+
 // TagLessConnManager is a companion interface to libp2p-core.connmgr.ConnManager which implements a (simplified) tagless variant of the Protect / Unprotect logic
 type TagLessConnManager interface {
 	connmgr.ConnManager
@@ -41,10 +76,31 @@ type ConnManager struct {
 	streamSetupMapLk sync.RWMutex
 
 	idProvider id.IdentityProvider
+
+	// values and protections of the peers, set by libp2p components such as GossipSub for its mesh peers,
+	// and by the connector for the peers of the fanout
+	tags      map[peer.ID]map[string]int
+	protected map[peer.ID]map[string]struct{}
+	tagsLk    sync.RWMutex
+
+	limits   ConnLimits
+	trimLk   sync.Mutex
+	lastTrim time.Time
+	net      network.Network // network of the libp2p host, known once a connection is established
+	netLk    sync.RWMutex
+	now      func() time.Time
 }
 
 type ConnManagerOption func(*ConnManager)
 
+// WithConnLimits enables the trimming of the connections of the node once it has more connections than the
+// high watermark of the limits.
+func WithConnLimits(limits ConnLimits) ConnManagerOption {
+	return func(cm *ConnManager) {
+		cm.limits = limits
+	}
+}
+
 func TrackUnstakedConnections(idProvider id.IdentityProvider) ConnManagerOption {
 	return func(cm *ConnManager) {
 		cm.idProvider = idProvider
@@ -57,6 +113,9 @@ func NewConnManager(log zerolog.Logger, metrics module.NetworkMetrics, opts ...C
 		NullConnMgr:              connmgr.NullConnMgr{},
 		metrics:                  metrics,
 		streamSetupInProgressCnt: make(map[peer.ID]int),
+		tags:                     make(map[peer.ID]map[string]int),
+		protected:                make(map[peer.ID]map[string]struct{}),
+		now:                      time.Now,
 	}
 	n := &network.NotifyBundle{ListenCloseF: cn.ListenCloseNotifee,
 		ListenF:       cn.ListenNotifee,
@@ -91,12 +150,28 @@ func (c *ConnManager) ListenCloseNotifee(n network.Network, m multiaddr.Multiadd
 func (c *ConnManager) Connected(n network.Network, con network.Conn) {
 	c.logConnectionUpdate(n, con, "connection established")
 	c.updateConnectionMetric(n)
+
+	c.netLk.Lock()
+	c.net = n
+	c.netLk.Unlock()
+
+	if c.limits.enabled() && len(n.Conns()) > c.limits.HighWater {
+		// notifiees must not block, hence the connections are trimmed in the background
+		go c.TrimOpenConns(context.Background())
+	}
 }
 
 // called by libp2p when a connection closed
 func (c *ConnManager) Disconnected(n network.Network, con network.Conn) {
 	c.logConnectionUpdate(n, con, "connection removed")
 	c.updateConnectionMetric(n)
+
+	// the value of a peer only accounts for the current connections with the peer
+	if n.Connectedness(con.RemotePeer()) != network.Connected {
+		c.tagsLk.Lock()
+		delete(c.tags, con.RemotePeer())
+		c.tagsLk.Unlock()
+	}
 }
 
 func (c *ConnManager) updateConnectionMetric(n network.Network) {
@@ -186,10 +261,212 @@ func (c *ConnManager) UnprotectPeer(id peer.ID) {
 	c.streamSetupInProgressCnt[id] = cnt
 }
 
-// IsProtected returns true is there is at least one stream setup in progress for the given peer.ID else false
-func (c *ConnManager) IsProtected(id peer.ID, _ string) (protected bool) {
+// IsProtected returns true if the peer.ID is protected with the given tag. With an empty tag, it returns true if
+// the peer.ID is protected with any tag, or if there is at least one stream setup in progress for the peer.ID.
+func (c *ConnManager) IsProtected(id peer.ID, tag string) (protected bool) {
+	c.tagsLk.RLock()
+	tags := c.protected[id]
+	_, protected = tags[tag]
+	protected = protected || (tag == "" && len(tags) > 0)
+	c.tagsLk.RUnlock()
+	if protected || tag != "" {
+		return protected
+	}
+
 	c.streamSetupMapLk.RLock()
 	defer c.streamSetupMapLk.RUnlock()
 
 	return c.streamSetupInProgressCnt[id] > 0
 }
+
+// Protect protects the connections with the peer.ID from being trimmed, until it is unprotected with the same tag.
+// GossipSub protects its mesh peers with a tag per topic.
+func (c *ConnManager) Protect(id peer.ID, tag string) {
+	c.tagsLk.Lock()
+	defer c.tagsLk.Unlock()
+
+	tags, ok := c.protected[id]
+	if !ok {
+		tags = make(map[string]struct{})
+		c.protected[id] = tags
+	}
+	tags[tag] = struct{}{}
+}
+
+// Unprotect removes the protection of the peer.ID with the given tag, and returns true if the peer.ID is still
+// protected with other tags.
+func (c *ConnManager) Unprotect(id peer.ID, tag string) (protected bool) {
+	c.tagsLk.Lock()
+	defer c.tagsLk.Unlock()
+
+	tags, ok := c.protected[id]
+	if !ok {
+		return false
+	}
+	delete(tags, tag)
+	if len(tags) == 0 {
+		delete(c.protected, id)
+		return false
+	}
+	return true
+}
+
+// TagPeer sets the value of the tag of the peer.ID. The value of a peer is the sum of the values of its tags, and
+// the connections with the peers of lowest value are trimmed first.
+func (c *ConnManager) TagPeer(id peer.ID, tag string, value int) {
+	c.UpsertTag(id, tag, func(int) int { return value })
+}
+
+// UntagPeer removes the tag of the peer.ID.
+func (c *ConnManager) UntagPeer(id peer.ID, tag string) {
+	c.tagsLk.Lock()
+	defer c.tagsLk.Unlock()
+
+	delete(c.tags[id], tag)
+}
+
+// UpsertTag updates the value of the tag of the peer.ID, or inserts it if the peer.ID has no such tag.
+// GossipSub bumps the values of the peers delivering messages on its topics first.
+func (c *ConnManager) UpsertTag(id peer.ID, tag string, upsert func(int) int) {
+	c.tagsLk.Lock()
+	defer c.tagsLk.Unlock()
+
+	tags, ok := c.tags[id]
+	if !ok {
+		tags = make(map[string]int)
+		c.tags[id] = tags
+	}
+	tags[tag] = upsert(tags[tag])
+}
+
+// GetTagInfo returns the tags and the value of the peer.ID, or nil if the peer.ID has no tags.
+func (c *ConnManager) GetTagInfo(id peer.ID) *connmgr.TagInfo {
+	c.tagsLk.RLock()
+	defer c.tagsLk.RUnlock()
+
+	tags, ok := c.tags[id]
+	if !ok {
+		return nil
+	}
+	info := &connmgr.TagInfo{Tags: make(map[string]int, len(tags))}
+	for tag, value := range tags {
+		info.Tags[tag] = value
+		info.Value += value
+	}
+	return info
+}
+
+// value returns the sum of the values of the tags of the peer.ID.
+func (c *ConnManager) value(id peer.ID) int {
+	c.tagsLk.RLock()
+	defer c.tagsLk.RUnlock()
+
+	value := 0
+	for _, v := range c.tags[id] {
+		value += v
+	}
+	return value
+}
+
+// TrimOpenConns closes connections until the node has no more connections than the low watermark, if it has more
+// connections than the high watermark. The connections without streams are trimmed first, then the connections
+// with the peers of lowest value. The protected connections, the connections carrying Flow streams and the
+// connections opened during the grace period are kept. It is a no-op if it was called during the silence period.
+func (c *ConnManager) TrimOpenConns(_ context.Context) {
+	if !c.limits.enabled() {
+		return
+	}
+
+	c.netLk.RLock()
+	n := c.net
+	c.netLk.RUnlock()
+	if n == nil {
+		return
+	}
+
+	c.trimLk.Lock()
+	defer c.trimLk.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastTrim) < c.limits.SilencePeriod {
+		return
+	}
+	c.lastTrim = now
+
+	conns := n.Conns()
+	if len(conns) <= c.limits.HighWater {
+		return
+	}
+
+	type candidate struct {
+		id    peer.ID
+		conns int
+		idle  bool
+		value int
+	}
+	candidates := make(map[peer.ID]*candidate)
+	kept := make(map[peer.ID]struct{})
+	for _, conn := range conns {
+		id := conn.RemotePeer()
+		if _, ok := kept[id]; ok {
+			continue
+		}
+		if c.IsProtected(id, "") || flowStream(conn) != nil || now.Sub(conn.Stat().Opened) < c.limits.GracePeriod {
+			// all the connections with the peer are kept
+			kept[id] = struct{}{}
+			delete(candidates, id)
+			continue
+		}
+		cand, ok := candidates[id]
+		if !ok {
+			cand = &candidate{id: id, idle: true, value: c.value(id)}
+			candidates[id] = cand
+		}
+		cand.conns++
+		cand.idle = cand.idle && len(conn.GetStreams()) == 0
+	}
+
+	sorted := make([]*candidate, 0, len(candidates))
+	for _, cand := range candidates {
+		sorted = append(sorted, cand)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].idle != sorted[j].idle {
+			return sorted[i].idle
+		}
+		return sorted[i].value < sorted[j].value
+	})
+
+	excess := len(conns) - c.limits.LowWater
+	for _, cand := range sorted {
+		if excess <= 0 {
+			break
+		}
+
+		reason := PruneReasonLowValue
+		if cand.idle {
+			reason = PruneReasonIdle
+		}
+		log := c.log.With().
+			Str("remote_peer", cand.id.String()).
+			Str("reason", reason).
+			Int("value", cand.value).
+			Logger()
+
+		err := n.ClosePeer(cand.id)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to trim connections with peer")
+			continue
+		}
+		log.Debug().Msg("trimmed connections with peer")
+		c.metrics.ConnectionPruned(reason)
+		excess -= cand.conns
+	}
+
+	if excess > 0 {
+		c.log.Warn().
+			Int("connections", len(conns)).
+			Int("low_water", c.limits.LowWater).
+			Msg("could not trim connections down to the low watermark, remaining connections are protected or in use")
+	}
+}
@@ -1,15 +1,21 @@
 package p2p
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -84,3 +90,141 @@ func generatePeerInfo(t *testing.T) peer.ID {
 	require.NoError(t, err)
 	return pInfo.ID
 }
+
+// TestConnectionManagerTaggedProtection tests that the protections with different tags are independent, and that any
+// of them protects the peer from pruning.
+func TestConnectionManagerTaggedProtection(t *testing.T) {
+	connManager := NewConnManager(zerolog.Nop(), metrics.NewNoopCollector())
+	pID := generatePeerInfo(t)
+
+	connManager.Protect(pID, "pubsub:topic")
+	connManager.Protect(pID, fanoutProtectionTag)
+	require.True(t, connManager.IsProtected(pID, ""))
+	require.True(t, connManager.IsProtected(pID, fanoutProtectionTag))
+
+	require.True(t, connManager.Unprotect(pID, fanoutProtectionTag))
+	require.False(t, connManager.IsProtected(pID, fanoutProtectionTag))
+	require.True(t, connManager.IsProtected(pID, ""))
+
+	require.False(t, connManager.Unprotect(pID, "pubsub:topic"))
+	require.False(t, connManager.IsProtected(pID, ""))
+}
+
+// testConn is a connection with a remote peer, opened at the given time and carrying the given streams.
+type testConn struct {
+	network.Conn
+	remote  peer.ID
+	opened  time.Time
+	streams []network.Stream
+}
+
+func (c *testConn) RemotePeer() peer.ID          { return c.remote }
+func (c *testConn) Stat() network.Stat           { return network.Stat{Opened: c.opened} }
+func (c *testConn) GetStreams() []network.Stream { return c.streams }
+
+// testNetwork is a network whose connections are closed by ClosePeer.
+type testNetwork struct {
+	network.Network
+	mu     sync.Mutex
+	conns  []network.Conn
+	closed []peer.ID
+}
+
+func (n *testNetwork) Conns() []network.Conn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]network.Conn(nil), n.conns...)
+}
+
+func (n *testNetwork) ClosePeer(id peer.ID) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closed = append(n.closed, id)
+	conns := n.conns[:0]
+	for _, conn := range n.conns {
+		if conn.RemotePeer() != id {
+			conns = append(conns, conn)
+		}
+	}
+	n.conns = conns
+	return nil
+}
+
+// testStream is a stream of the given protocol.
+type testStream struct {
+	network.Stream
+	protocol protocol.ID
+}
+
+func (s *testStream) Protocol() protocol.ID { return s.protocol }
+
+// TestConnectionManagerTrimming tests that the connections are trimmed down to the low watermark once there are more
+// connections than the high watermark, idle and low value connections first, and that the protected connections,
+// the connections carrying Flow streams and the new connections are kept.
+func TestConnectionManagerTrimming(t *testing.T) {
+	now := time.Now()
+	collector := new(mockmodule.NetworkMetrics)
+	connManager := NewConnManager(zerolog.Nop(), collector, WithConnLimits(ConnLimits{
+		HighWater:     5,
+		LowWater:      3,
+		GracePeriod:   time.Minute,
+		SilencePeriod: time.Minute,
+	}))
+	connManager.now = func() time.Time { return now }
+
+	peers := make([]peer.ID, 8)
+	for i := range peers {
+		peers[i] = generatePeerInfo(t)
+	}
+	old := now.Add(-time.Hour)
+	oneToOneStream := &testStream{protocol: FlowLibP2POneToOneProtocolIDPrefix}
+	otherStream := &testStream{protocol: "/other"}
+	net := &testNetwork{conns: []network.Conn{
+		&testConn{remote: peers[0], opened: old},                                            // protected
+		&testConn{remote: peers[1], opened: old, streams: []network.Stream{oneToOneStream}}, // flow stream
+		&testConn{remote: peers[2], opened: now},                                            // new connection
+		&testConn{remote: peers[3], opened: old, streams: []network.Stream{otherStream}},    // low value
+		&testConn{remote: peers[4], opened: old, streams: []network.Stream{otherStream}},    // high value
+		&testConn{remote: peers[5], opened: old},                                            // idle
+		&testConn{remote: peers[6], opened: old},                                            // idle
+	}}
+	connManager.Protect(peers[0], "pubsub:topic")
+	connManager.TagPeer(peers[2], "pubsub-deliveries:topic", 5)
+	connManager.TagPeer(peers[3], "pubsub-deliveries:topic", 1)
+	connManager.TagPeer(peers[4], "pubsub-deliveries:topic", 10)
+
+	// no connections are trimmed until the network is known
+	connManager.TrimOpenConns(context.Background())
+	require.Empty(t, net.closed)
+	connManager.net = net
+
+	collector.On("ConnectionPruned", PruneReasonIdle).Twice()
+	collector.On("ConnectionPruned", PruneReasonLowValue).Twice()
+	connManager.TrimOpenConns(context.Background())
+	require.Equal(t, []peer.ID{peers[3], peers[4]}, net.closed[2:])
+	require.ElementsMatch(t, []peer.ID{peers[5], peers[6]}, net.closed[:2])
+	require.Len(t, net.Conns(), 3)
+	collector.AssertExpectations(t)
+
+	// connections are not trimmed again during the silence period
+	net.conns = append(net.conns,
+		&testConn{remote: peers[5], opened: old},
+		&testConn{remote: peers[6], opened: old},
+		&testConn{remote: peers[7], opened: old},
+	)
+	connManager.TrimOpenConns(context.Background())
+	require.Len(t, net.closed, 4)
+
+	// once the grace period is over, the new connection can be trimmed as well, but it has a higher value
+	collector.On("ConnectionPruned", PruneReasonIdle).Times(3)
+	now = now.Add(time.Minute)
+	connManager.TrimOpenConns(context.Background())
+	require.ElementsMatch(t, []peer.ID{peers[5], peers[6], peers[7]}, net.closed[4:])
+	require.Len(t, net.Conns(), 3)
+	collector.AssertExpectations(t)
+
+	// connections are kept when there are no more connections than the high watermark
+	now = now.Add(time.Minute)
+	connManager.TrimOpenConns(context.Background())
+	require.Len(t, net.closed, 7)
+}
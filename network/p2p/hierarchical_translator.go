@@ -44,3 +44,24 @@ func (t *HierarchicalIDTranslator) GetFlowID(peerID peer.ID) (flow.Identifier, e
 	}
 	return flow.ZeroID, fmt.Errorf("could not translate the given peer ID: %w", errs)
 }
+
+// GetPeerAddrInfo returns the first successful address translation of the translators which also translate
+// addresses, see AddrInfoTranslator.
+func (t *HierarchicalIDTranslator) GetPeerAddrInfo(flowID flow.Identifier) (peer.AddrInfo, error) {
+	var errs *multierror.Error
+	for _, translator := range t.translators {
+		addrTranslator, ok := translator.(AddrInfoTranslator)
+		if !ok {
+			continue
+		}
+		info, err := addrTranslator.GetPeerAddrInfo(flowID)
+		if err == nil {
+			return info, nil
+		}
+		errs = multierror.Append(errs, err)
+	}
+	if errs == nil {
+		return peer.AddrInfo{}, fmt.Errorf("no translator translates flow IDs to addresses")
+	}
+	return peer.AddrInfo{}, fmt.Errorf("could not translate the given flow ID to addresses: %w", errs)
+}
@@ -15,3 +15,10 @@ type IDTranslator interface {
 	// GetFlowID returns the Flow ID for the given peer ID
 	GetFlowID(peer.ID) (flow.Identifier, error)
 }
+
+// AddrInfoTranslator is implemented by the IDTranslators which also translate Flow ID's to the
+// addresses the LibP2P peers can be dialed on.
+type AddrInfoTranslator interface {
+	// GetPeerAddrInfo returns the peer ID and the multiaddresses for the given Flow ID
+	GetPeerAddrInfo(flow.Identifier) (peer.AddrInfo, error)
+}
@@ -13,8 +13,10 @@ import (
 
 // IdentityProviderIDTranslator implements an IDTranslator which provides ID
 // translation capabilities for an IdentityProvider.
+// It also translates the identities to their multiaddresses on the configured transports.
 type IdentityProviderIDTranslator struct {
 	idProvider id.IdentityProvider
+	transports []Transport
 }
 
 func (t *IdentityProviderIDTranslator) GetFlowID(p peer.ID) (flow.Identifier, error) {
@@ -49,6 +51,18 @@ func (t *IdentityProviderIDTranslator) GetPeerID(n flow.Identifier) (peer.ID, er
 	return pid, nil
 }
 
-func NewIdentityProviderIDTranslator(provider id.IdentityProvider) *IdentityProviderIDTranslator {
-	return &IdentityProviderIDTranslator{provider}
+// GetPeerAddrInfo returns the peer ID of the given Flow ID with the multiaddresses of its identity address
+// on each of the transports of the translator.
+func (t *IdentityProviderIDTranslator) GetPeerAddrInfo(n flow.Identifier) (peer.AddrInfo, error) {
+	ids := t.idProvider.Identities(filter.HasNodeID(n))
+	if len(ids) == 0 {
+		return peer.AddrInfo{}, fmt.Errorf("could not find identity with id %v", n.String())
+	}
+	return PeerAddressInfo(*ids[0], t.transports...)
+}
+
+// NewIdentityProviderIDTranslator returns a translator of the identities of the provider, whose multiaddresses
+// are on the given transports, TCP if none is given.
+func NewIdentityProviderIDTranslator(provider id.IdentityProvider, transports ...Transport) *IdentityProviderIDTranslator {
+	return &IdentityProviderIDTranslator{provider, transports}
 }
@@ -14,14 +14,22 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
 )
 
+// fanoutProtectionTag is the tag protecting the connections with the peers of the fanout from being trimmed
+// by the connection manager
+const fanoutProtectionTag = "flow:fanout"
+
 // libp2pConnector is a libp2p based Connector implementation to connect and disconnect from peers
 type Libp2pConnector struct {
 	backoffConnector *discovery.BackoffConnector
 	host             host.Host
 	log              zerolog.Logger
+	metrics          module.NetworkMetrics
 	pruneConnections bool
+	fanout           map[peer.ID]struct{} // peers protected as part of the fanout
 }
 
 var _ Connector = &Libp2pConnector{}
@@ -54,9 +62,18 @@ func IsUnconvertibleIdentitiesError(err error) bool {
 
 type ConnectorOption func(connector *Libp2pConnector)
 
+// WithConnectionPruning enables or disables the pruning of the connections with the peers which are not part of
+// the fanout. With the pruning disabled, the connections are only trimmed by the connection manager.
 func WithConnectionPruning(enable bool) ConnectorOption {
 	return func(connector *Libp2pConnector) {
-		connector.pruneConnections = false
+		connector.pruneConnections = enable
+	}
+}
+
+// WithConnectorMetrics reports the connections pruned by the connector to the given metrics.
+func WithConnectorMetrics(metrics module.NetworkMetrics) ConnectorOption {
+	return func(connector *Libp2pConnector) {
+		connector.metrics = metrics
 	}
 }
 
@@ -69,7 +86,9 @@ func NewLibp2pConnector(host host.Host, log zerolog.Logger, options ...Connector
 		backoffConnector: connector,
 		host:             host,
 		log:              log,
+		metrics:          metrics.NewNoopCollector(),
 		pruneConnections: true,
+		fanout:           make(map[peer.ID]struct{}),
 	}
 
 	for _, o := range options {
@@ -79,8 +98,11 @@ func NewLibp2pConnector(host host.Host, log zerolog.Logger, options ...Connector
 }
 
 // UpdatePeers is the implementation of the Connector.UpdatePeers function. It connects to all of the ids and
-// disconnects from any other connection that the libp2p node might have.
+// disconnects from any other connection that the libp2p node might have. The connections with the ids are
+// protected from being trimmed by the connection manager.
 func (l *Libp2pConnector) UpdatePeers(ctx context.Context, peerIDs peer.IDSlice) {
+	l.protectFanout(peerIDs)
+
 	// connect to each of the peer.AddrInfo in pInfos
 	l.connectToPeers(ctx, peerIDs)
 
@@ -90,6 +112,22 @@ func (l *Libp2pConnector) UpdatePeers(ctx context.Context, peerIDs peer.IDSlice)
 	}
 }
 
+// protectFanout protects the connections with the peers of the fanout, and unprotects the connections with the
+// peers which are not part of the fanout anymore.
+func (l *Libp2pConnector) protectFanout(peerIDs peer.IDSlice) {
+	fanout := make(map[peer.ID]struct{}, len(peerIDs))
+	for _, pid := range peerIDs {
+		fanout[pid] = struct{}{}
+		l.host.ConnManager().Protect(pid, fanoutProtectionTag)
+	}
+	for pid := range l.fanout {
+		if _, ok := fanout[pid]; !ok {
+			l.host.ConnManager().Unprotect(pid, fanoutProtectionTag)
+		}
+	}
+	l.fanout = fanout
+}
+
 // connectToPeers connects each of the peer in pInfos
 func (l *Libp2pConnector) connectToPeers(ctx context.Context, peerIDs peer.IDSlice) {
 
@@ -135,6 +173,7 @@ func (l *Libp2pConnector) trimAllConnectionsExcept(peerIDs peer.IDSlice) {
 
 		peerInfo := l.host.Network().Peerstore().PeerInfo(peerID)
 		log := l.log.With().Str("remote_peer", peerInfo.String()).Logger()
+		// the connections with the GossipSub mesh peers of the subscribed channels are protected as well
 		if l.host.ConnManager().IsProtected(peerID, "") {
 			log.Trace().Msg("skipping pruning since connection is protected")
 			continue // connection is protected (stream or connection in progress), skip pruning
@@ -156,6 +195,7 @@ func (l *Libp2pConnector) trimAllConnectionsExcept(peerIDs peer.IDSlice) {
 			log.Error().Err(err).Msg("failed to disconnect from peer")
 		} else {
 			log.Debug().Msg("disconnected from peer not included in the fanout")
+			l.metrics.ConnectionPruned(PruneReasonNotInFanout)
 		}
 	}
 }
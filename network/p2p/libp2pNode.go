@@ -21,14 +21,12 @@ import (
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	swarm "github.com/libp2p/go-libp2p-swarm"
-	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
 	"github.com/libp2p/go-libp2p/config"
-	"github.com/libp2p/go-tcp-transport"
-	"github.com/multiformats/go-multiaddr"
 	madns "github.com/multiformats/go-multiaddr-dns"
 	"github.com/rs/zerolog"
 
 	fcrypto "github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/id"
@@ -73,9 +71,11 @@ func DefaultLibP2PNodeFactory(ctx context.Context,
 	metrics module.NetworkMetrics,
 	pingInfoProvider PingInfoProvider,
 	dnsResolverTTL time.Duration,
+	connLimits ConnLimits,
+	transports []Transport,
 	role string) (LibP2PFactoryFunc, error) {
 
-	connManager := NewConnManager(log, metrics)
+	connManager := NewConnManager(log, metrics, WithConnLimits(connLimits))
 
 	connGater := NewConnGater(log)
 
@@ -100,6 +100,8 @@ func DefaultLibP2PNodeFactory(ctx context.Context,
 			SetPingInfoProvider(pingInfoProvider).
 			SetLogger(log).
 			SetResolver(resolver).
+			SetPeerScoring(NewPeerScoring(log, metrics)).
+			SetTransports(transports...).
 			Build(ctx)
 	}, nil
 }
@@ -114,6 +116,9 @@ type NodeBuilder interface {
 	SetTopicValidation(bool) NodeBuilder
 	SetLogger(zerolog.Logger) NodeBuilder
 	SetResolver(*dns.Resolver) NodeBuilder
+	SetPeerScoring(*PeerScoring) NodeBuilder
+	SetEventTracers(...pubsub.EventTracer) NodeBuilder
+	SetTransports(...Transport) NodeBuilder
 	Build(context.Context) (*Node, error)
 }
 
@@ -130,19 +135,23 @@ type DefaultLibP2PNodeBuilder struct {
 	pubSubOpts       []PubsubOption
 	dhtOpts          []dht.Option
 	topicValidation  bool
+	peerScoring      *PeerScoring
+	eventTracers     []pubsub.EventTracer
+	transports       []Transport
 }
 
 func NewDefaultLibP2PNodeBuilder(id flow.Identifier, address string, flowKey fcrypto.PrivateKey) NodeBuilder {
-	return &DefaultLibP2PNodeBuilder{
+	builder := &DefaultLibP2PNodeBuilder{
 		id: id,
 		pubSubMaker: func(ctx context.Context, h host.Host, opts ...pubsub.Option) (*pubsub.PubSub, error) {
 			return DefaultPubSub(ctx, h, opts...)
 		},
-		hostMaker: func(ctx context.Context, opts ...config.Option) (host.Host, error) {
-			return DefaultLibP2PHost(ctx, address, flowKey, opts...)
-		},
 		topicValidation: true,
 	}
+	builder.hostMaker = func(ctx context.Context, opts ...config.Option) (host.Host, error) {
+		return DefaultLibP2PHost(ctx, address, flowKey, builder.transports, opts...)
+	}
+	return builder
 }
 
 func (builder *DefaultLibP2PNodeBuilder) SetDHTOptions(opts ...dht.Option) NodeBuilder {
@@ -190,6 +199,26 @@ func (builder *DefaultLibP2PNodeBuilder) SetResolver(resolver *dns.Resolver) Nod
 	return builder
 }
 
+// SetPeerScoring enables the GossipSub peer scoring, and the penalties for the messages rejected by the topic validators.
+func (builder *DefaultLibP2PNodeBuilder) SetPeerScoring(peerScoring *PeerScoring) NodeBuilder {
+	builder.peerScoring = peerScoring
+	return builder
+}
+
+// SetEventTracers sets the tracers of the events of the GossipSub router, along with the mesh tracer of the node.
+// The router supports a single event tracer, hence the tracers must be given here rather than as pubsub options.
+func (builder *DefaultLibP2PNodeBuilder) SetEventTracers(tracers ...pubsub.EventTracer) NodeBuilder {
+	builder.eventTracers = tracers
+	return builder
+}
+
+// SetTransports sets the transports the node listens and dials on, TCP if none is set. QUIC is preferred to TCP
+// when dialing a peer listening on both.
+func (builder *DefaultLibP2PNodeBuilder) SetTransports(transports ...Transport) NodeBuilder {
+	builder.transports = transports
+	return builder
+}
+
 func (builder *DefaultLibP2PNodeBuilder) Build(ctx context.Context) (*Node, error) {
 	node := &Node{
 		id:              builder.id,
@@ -211,6 +240,7 @@ func (builder *DefaultLibP2PNodeBuilder) Build(ctx context.Context) (*Node, erro
 		return nil, errors.New("root block ID must be provided")
 	}
 	node.flowLibP2PProtocolID = generateFlowProtocolID(*builder.rootBlockID)
+	node.requestProtocolID = generateRequestProtocolID(*builder.rootBlockID)
 
 	var opts []config.Option
 
@@ -264,6 +294,15 @@ func (builder *DefaultLibP2PNodeBuilder) Build(ctx context.Context) (*Node, erro
 		node.pingService = pingService
 	}
 
+	if builder.peerScoring != nil {
+		builder.pubSubOpts = append(builder.pubSubOpts, builder.peerScoring.PubsubOptions()...)
+		node.peerScoring = builder.peerScoring
+	}
+
+	node.meshTracer = NewMeshTracer()
+	tracers := append([]pubsub.EventTracer{node.meshTracer}, builder.eventTracers...)
+	builder.pubSubOpts = append(builder.pubSubOpts, EventTracersOption(tracers...))
+
 	var libp2pPSOptions []pubsub.Option
 	// generate the libp2p Pubsub options from the given context and host
 	for _, optionGenerator := range builder.pubSubOpts {
@@ -304,11 +343,14 @@ type Node struct {
 	subs                 map[flownet.Topic]*pubsub.Subscription // map of a topic string to an actual subscription
 	id                   flow.Identifier                        // used to represent id of flow node running this instance of libP2P node
 	flowLibP2PProtocolID protocol.ID                            // the unique protocol ID
+	requestProtocolID    protocol.ID                            // the protocol ID of the request/response streams
 	resolver             *dns.Resolver                          // dns resolver for libp2p (is nil if default)
 	pingService          *PingService
 	connMgr              TagLessConnManager
 	dht                  *dht.IpfsDHT
 	topicValidation      bool
+	peerScoring          *PeerScoring // nil if the peer scoring is disabled
+	meshTracer           *MeshTracer
 }
 
 // Stop terminates the libp2p node.
@@ -398,6 +440,16 @@ func (n *Node) RemovePeer(ctx context.Context, peerID peer.ID) error {
 
 // CreateStream returns an existing stream connected to the peer if it exists, or creates a new stream with it.
 func (n *Node) CreateStream(ctx context.Context, peerID peer.ID) (libp2pnet.Stream, error) {
+	return n.createStream(ctx, peerID, n.flowLibP2PProtocolID)
+}
+
+// CreateRequestStream creates a new stream with the peer for the Flow request/response protocol.
+func (n *Node) CreateRequestStream(ctx context.Context, peerID peer.ID) (libp2pnet.Stream, error) {
+	return n.createStream(ctx, peerID, n.requestProtocolID)
+}
+
+// createStream creates a new stream with the peer for the given protocol.
+func (n *Node) createStream(ctx context.Context, peerID peer.ID, protocolID protocol.ID) (libp2pnet.Stream, error) {
 	// If we do not currently have any addresses for the given peer, stream creation will almost
 	// certainly fail. If this Node was configure with a DHT, we can try to lookup the address of
 	// the peer in the DHT as a last resort.
@@ -419,17 +471,17 @@ func (n *Node) CreateStream(ctx context.Context, peerID peer.ID) (libp2pnet.Stre
 		}
 	}
 	// Open libp2p Stream with the remote peer (will use an existing TCP connection underneath if it exists)
-	stream, err := n.tryCreateNewStream(ctx, peerID, maxConnectAttempt)
+	stream, err := n.tryCreateNewStream(ctx, peerID, protocolID, maxConnectAttempt)
 	if err != nil {
 		return nil, flownet.NewPeerUnreachableError(fmt.Errorf("could not create stream (peer_id: %s): %w", peerID, err))
 	}
 	return stream, nil
 }
 
-// tryCreateNewStream makes at most maxAttempts to create a stream with the peer.
+// tryCreateNewStream makes at most maxAttempts to create a stream with the peer for the given protocol.
 // This was put in as a fix for #2416. PubSub and 1-1 communication compete with each other when trying to connect to
 // remote nodes and once in a while NewStream returns an error 'both yamux endpoints are clients'
-func (n *Node) tryCreateNewStream(ctx context.Context, peerID peer.ID, maxAttempts int) (libp2pnet.Stream, error) {
+func (n *Node) tryCreateNewStream(ctx context.Context, peerID peer.ID, protocolID protocol.ID, maxAttempts int) (libp2pnet.Stream, error) {
 	// protect the underlying connection from being inadvertently pruned by the peer manager while the stream and
 	// connection creation is being attempted
 	n.connMgr.ProtectPeer(peerID)
@@ -483,11 +535,14 @@ func (n *Node) tryCreateNewStream(ctx context.Context, peerID peer.ID, maxAttemp
 			continue
 		}
 
-		s, err = n.host.NewStream(ctx, peerID, n.flowLibP2PProtocolID)
+		s, err = n.host.NewStream(ctx, peerID, protocolID)
 		if err != nil {
 			// if the stream creation failed due to invalid protocol id, skip the re-attempt
 			if strings.Contains(err.Error(), "protocol not supported") {
-				return nil, fmt.Errorf("remote node is running on a different spork: %w, protocol attempted: %s", err, n.flowLibP2PProtocolID)
+				if protocolID == n.requestProtocolID {
+					return nil, fmt.Errorf("remote node does not support protocol %s: %w", protocolID, flownet.ErrRequestNotSupported)
+				}
+				return nil, fmt.Errorf("remote node is running on a different spork: %w, protocol attempted: %s", err, protocolID)
 			}
 			errs = multierror.Append(errs, err)
 			continue
@@ -519,7 +574,7 @@ func (n *Node) Subscribe(ctx context.Context, topic flownet.Topic, validators ..
 	var err error
 	if !found {
 		if n.topicValidation {
-			topic_validator := validator.TopicValidator(validators...)
+			topic_validator := validator.TopicValidator(n.RejectionConsumer(), validators...)
 			if err := n.pubSub.RegisterTopicValidator(
 				topic.String(), topic_validator, pubsub.WithValidatorInline(true),
 			); err != nil {
@@ -539,6 +594,14 @@ func (n *Node) Subscribe(ctx context.Context, topic flownet.Topic, validators ..
 			return nil, fmt.Errorf("could not join topic (%s): %w", topic, err)
 		}
 
+		if n.peerScoring != nil {
+			if channel, ok := engine.ChannelFromTopic(topic); ok {
+				if err := tp.SetScoreParams(n.peerScoring.TopicScoreParams(channel)); err != nil {
+					n.logger.Err(err).Str("topic", topic.String()).Msg("failed to set topic score parameters")
+				}
+			}
+		}
+
 		n.topics[topic] = tp
 	}
 
@@ -558,6 +621,15 @@ func (n *Node) Subscribe(ctx context.Context, topic flownet.Topic, validators ..
 	return s, err
 }
 
+// RejectionConsumer returns the consumer penalizing the peers whose messages are rejected by the
+// topic validators, nil if the peer scoring is disabled.
+func (n *Node) RejectionConsumer() validator.RejectionConsumer {
+	if n.peerScoring == nil {
+		return nil
+	}
+	return n.peerScoring.Penalize
+}
+
 // UnSubscribe cancels the subscriber and closes the topic.
 func (n *Node) UnSubscribe(topic flownet.Topic) error {
 	n.Lock()
@@ -646,6 +718,27 @@ func (n *Node) UpdateAllowList(peers peer.IDSlice) {
 	n.connGater.update(peers)
 }
 
+// DenyPeer closes the connections with the peer, and rejects its new connections until the given time.
+// Without connection gating, the peer is only disconnected.
+func (n *Node) DenyPeer(peerID peer.ID, until time.Time) error {
+	if n.connGater != nil {
+		n.connGater.deny(peerID, until)
+	} else {
+		n.logger.Debug().Hex("node_id", logging.ID(n.id)).Msg("connection gating is not enabled, denied peer is only disconnected")
+	}
+
+	err := n.host.Network().ClosePeer(peerID)
+	if err != nil {
+		return fmt.Errorf("could not close connections with peer %s: %w", peerID.Pretty(), err)
+	}
+	return nil
+}
+
+// MeshPeers returns the peers of the GossipSub mesh of each topic the node is part of.
+func (n *Node) MeshPeers() map[string]peer.IDSlice {
+	return n.meshTracer.MeshPeers()
+}
+
 // Host returns pointer to host object of node.
 func (n *Node) Host() host.Host {
 	return n.host
@@ -656,6 +749,11 @@ func (n *Node) SetFlowProtocolStreamHandler(handler libp2pnet.StreamHandler) {
 	n.host.SetStreamHandler(n.flowLibP2PProtocolID, handler)
 }
 
+// SetRequestStreamHandler sets the stream handler of the Flow request/response protocol.
+func (n *Node) SetRequestStreamHandler(handler libp2pnet.StreamHandler) {
+	n.host.SetStreamHandler(n.requestProtocolID, handler)
+}
+
 // SetPingStreamHandler sets the stream handler for the Flow Ping protocol.
 func (n *Node) SetPingStreamHandler(handler libp2pnet.StreamHandler) {
 	n.host.SetStreamHandler(n.flowLibP2PProtocolID, handler)
@@ -668,11 +766,11 @@ func (n *Node) IsConnected(peerID peer.ID) (bool, error) {
 	return isConnected, nil
 }
 
-// DefaultLibP2PHost returns a libp2p host initialized to listen on the given address and using the given private key and
-// customized with options
-func DefaultLibP2PHost(ctx context.Context, address string, key fcrypto.PrivateKey, options ...config.Option) (host.Host,
+// DefaultLibP2PHost returns a libp2p host initialized to listen on the given address with the given transports
+// (TCP if none is given), using the given private key and customized with options
+func DefaultLibP2PHost(ctx context.Context, address string, key fcrypto.PrivateKey, transports []Transport, options ...config.Option) (host.Host,
 	error) {
-	defaultOptions, err := DefaultLibP2POptions(address, key)
+	defaultOptions, err := DefaultLibP2POptions(address, key, transports...)
 	if err != nil {
 		return nil, err
 	}
@@ -689,7 +787,8 @@ func DefaultLibP2PHost(ctx context.Context, address string, key fcrypto.PrivateK
 }
 
 // DefaultLibP2POptions creates and returns the standard LibP2P host options that are used for the Flow Libp2p network
-func DefaultLibP2POptions(address string, key fcrypto.PrivateKey) ([]config.Option, error) {
+// The host listens and dials on the given transports, TCP if none is given.
+func DefaultLibP2POptions(address string, key fcrypto.PrivateKey, transports ...Transport) ([]config.Option, error) {
 
 	libp2pKey, err := keyutils.LibP2PPrivKeyFromFlow(key)
 	if err != nil {
@@ -701,28 +800,16 @@ func DefaultLibP2POptions(address string, key fcrypto.PrivateKey) ([]config.Opti
 		return nil, fmt.Errorf("could not split node address %s:%w", address, err)
 	}
 
-	sourceMultiAddr, err := multiaddr.NewMultiaddr(MultiAddressStr(ip, port))
+	transportOpts, err := transportOptions(ip, port, transports...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to translate Flow address to Libp2p multiaddress: %w", err)
+		return nil, fmt.Errorf("could not create transports: %w", err)
 	}
 
-	// create a transport which disables port reuse and web socket.
-	// Port reuse enables listening and dialing from the same TCP port (https://github.com/libp2p/go-reuseport)
-	// While this sounds great, it intermittently causes a 'broken pipe' error
-	// as the 1-k discovery process and the 1-1 messaging both sometimes attempt to open connection to the same target
-	// As of now there is no requirement of client sockets to be a well-known port, so disabling port reuse all together.
-	transport := libp2p.Transport(func(u *tptu.Upgrader) *tcp.TcpTransport {
-		tpt := tcp.NewTCPTransport(u)
-		tpt.DisableReuseport = true
-		return tpt
-	})
-
 	// gather all the options for the libp2p node
 	options := []config.Option{
-		libp2p.ListenAddrs(sourceMultiAddr), // set the listen address
-		libp2p.Identity(libp2pKey),          // pass in the networking key
-		transport,                           // set the protocol
+		libp2p.Identity(libp2pKey), // pass in the networking key
 	}
+	options = append(options, transportOpts...) // set the listen addresses and the protocols
 
 	return options, nil
 }
@@ -28,6 +28,7 @@ import (
 	fcrypto "github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
+	flownet "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -159,7 +160,7 @@ func (suite *LibP2PNodeTestSuite) TestRemovePeers() {
 
 	// create nodes
 	nodes, identities := suite.NodesFixture(count, nil, false)
-	peerInfos, errs := peerInfosFromIDs(identities)
+	peerInfos, errs := peerInfosFromIDs(identities, NewUnstakedNetworkIDTranslator())
 	assert.Len(suite.T(), errs, 0)
 	defer StopNodes(suite.T(), nodes)
 
@@ -178,6 +179,22 @@ func (suite *LibP2PNodeTestSuite) TestRemovePeers() {
 	}
 }
 
+// TestCreateRequestStream_NotSupported checks that creating a request stream to a node which does not handle the
+// request/response protocol fails with ErrRequestNotSupported.
+func (suite *LibP2PNodeTestSuite) TestCreateRequestStream_NotSupported() {
+	nodes, identities := suite.NodesFixture(2, nil, false)
+	defer StopNodes(suite.T(), nodes)
+
+	pInfo, err := PeerAddressInfo(*identities[1])
+	require.NoError(suite.T(), err)
+	nodes[0].host.Peerstore().AddAddrs(pInfo.ID, pInfo.Addrs, peerstore.AddressTTL)
+
+	// the nodes only handle the request/response protocol once their middleware sets the handler
+	_, err = nodes[0].CreateRequestStream(context.Background(), pInfo.ID)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), errors.Is(err, flownet.ErrRequestNotSupported))
+}
+
 // TestCreateStreams checks if a new streams is created each time when CreateStream is called and an existing stream is not reused
 func (suite *LibP2PNodeTestSuite) TestCreateStream() {
 	count := 2
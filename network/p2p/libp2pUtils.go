@@ -5,6 +5,7 @@ package p2p
 import (
 	"fmt"
 	"net"
+	"sort"
 
 	core "github.com/libp2p/go-libp2p-core"
 	"github.com/libp2p/go-libp2p-core/crypto"
@@ -131,6 +132,16 @@ func MultiAddressStr(ip, port string) string {
 	return fmt.Sprintf("/dns4/%s/tcp/%s", ip, port)
 }
 
+// QUICMultiAddressStr receives a node ip and port and returns its corresponding Libp2p QUIC
+// MultiAddressStr in string format, the IP part of the node address being either an IP or a dns4.
+func QUICMultiAddressStr(ip, port string) string {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP != nil {
+		return fmt.Sprintf("/ip4/%s/udp/%s/quic", ip, port)
+	}
+	return fmt.Sprintf("/dns4/%s/udp/%s/quic", ip, port)
+}
+
 // IPPortFromMultiAddress returns the IP/hostname and the port for the given multi-addresses
 // associated with a libp2p host
 func IPPortFromMultiAddress(addrs ...multiaddr.Multiaddr) (string, string, error) {
@@ -149,8 +160,11 @@ func IPPortFromMultiAddress(addrs ...multiaddr.Multiaddr) (string, string, error
 			}
 		}
 
-		// if either IP address or hostname is found, look for the port number
+		// if either IP address or hostname is found, look for the TCP or UDP (QUIC) port number
 		port, err = a.ValueForProtocol(multiaddr.P_TCP)
+		if err != nil {
+			port, err = a.ValueForProtocol(multiaddr.P_UDP)
+		}
 		if err != nil {
 			// an IPv4 or DNS4 based multiaddress should have a port number
 			return "", "", err
@@ -166,23 +180,27 @@ func generateFlowProtocolID(rootBlockID flow.Identifier) protocol.ID {
 	return protocol.ID(FlowLibP2POneToOneProtocolIDPrefix + rootBlockID.String())
 }
 
+func generateRequestProtocolID(rootBlockID flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PRequestProtocolPrefix + rootBlockID.String())
+}
+
 func generatePingProtcolID(rootBlockID flow.Identifier) protocol.ID {
 	return protocol.ID(FlowLibP2PPingProtocolPrefix + rootBlockID.String())
 }
 
-// PeerAddressInfo generates the libp2p peer.AddrInfo for the given Flow.Identity.
+// PeerAddressInfo generates the libp2p peer.AddrInfo for the given Flow.Identity, with an address for
+// each of the given transports (TCP if none is given), QUIC addresses being dialed first.
 // A node in flow is defined by a flow.Identity while it is defined by a peer.AddrInfo in libp2p.
 // flow.Identity           ---> peer.AddrInfo
 //    |-- Address          --->   |-- []multiaddr.Multiaddr
 //    |-- NetworkPublicKey --->   |-- ID
-func PeerAddressInfo(identity flow.Identity) (peer.AddrInfo, error) {
+func PeerAddressInfo(identity flow.Identity, transports ...Transport) (peer.AddrInfo, error) {
 	ip, port, key, err := networkingInfo(identity)
 	if err != nil {
 		return peer.AddrInfo{}, fmt.Errorf("could not get translate identity to networking info %s: %w", identity.NodeID.String(), err)
 	}
 
-	addr := MultiAddressStr(ip, port)
-	maddr, err := multiaddr.NewMultiaddr(addr)
+	maddrs, err := TransportMultiAddresses(ip, port, dialTransports(transports)...)
 	if err != nil {
 		return peer.AddrInfo{}, err
 	}
@@ -191,18 +209,40 @@ func PeerAddressInfo(identity flow.Identity) (peer.AddrInfo, error) {
 	if err != nil {
 		return peer.AddrInfo{}, fmt.Errorf("could not extract libp2p id from key:%w", err)
 	}
-	pInfo := peer.AddrInfo{ID: id, Addrs: []multiaddr.Multiaddr{maddr}}
+	pInfo := peer.AddrInfo{ID: id, Addrs: maddrs}
 	return pInfo, err
 }
 
+// dialTransports returns the transports in the order their addresses are dialed: QUIC first, as its connections
+// are secured and multiplexed in fewer round trips than TCP ones.
+func dialTransports(transports []Transport) []Transport {
+	ordered := make([]Transport, len(transports))
+	copy(ordered, transports)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i] == TransportQUIC && ordered[j] != TransportQUIC
+	})
+	return ordered
+}
+
 // peerInfosFromIDs converts the given flow.Identities to peer.AddrInfo.
+// The addresses are given by the translator when it is an AddrInfoTranslator which knows the identity, and
+// are otherwise the addresses of the identity on each of the transports.
 // For each identity, if the conversion succeeds, the peer.AddrInfo is included in the result else it is
 // included in the error map with the corresponding error
-func peerInfosFromIDs(ids flow.IdentityList) ([]peer.AddrInfo, map[flow.Identifier]error) {
+func peerInfosFromIDs(ids flow.IdentityList, translator IDTranslator, transports ...Transport) ([]peer.AddrInfo, map[flow.Identifier]error) {
+	addrTranslator, translatesAddrs := translator.(AddrInfoTranslator)
+
 	validIDs := make([]peer.AddrInfo, 0, len(ids))
 	invalidIDs := make(map[flow.Identifier]error)
 	for _, id := range ids {
-		peerInfo, err := PeerAddressInfo(*id)
+		if translatesAddrs {
+			peerInfo, err := addrTranslator.GetPeerAddrInfo(id.NodeID)
+			if err == nil {
+				validIDs = append(validIDs, peerInfo)
+				continue
+			}
+		}
+		peerInfo, err := PeerAddressInfo(*id, transports...)
 		if err != nil {
 			invalidIDs[id.NodeID] = err
 			continue
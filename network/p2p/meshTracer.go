@@ -0,0 +1,112 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+)
+
+// MeshTracer keeps track of the GossipSub mesh of each topic, i.e. the peers the node
+// forwards the messages of the topic to, from the events traced by the GossipSub router.
+type MeshTracer struct {
+	sync.RWMutex
+	meshes map[string]map[peer.ID]struct{}
+}
+
+// NewMeshTracer returns a new mesh tracer, with empty meshes.
+func NewMeshTracer() *MeshTracer {
+	return &MeshTracer{
+		meshes: make(map[string]map[peer.ID]struct{}),
+	}
+}
+
+// EventTracersOption returns the pubsub option installing the tracers on the GossipSub router.
+// The router supports a single event tracer, hence the tracers are chained, each of them tracing all the
+// events in order.
+func EventTracersOption(tracers ...pubsub.EventTracer) PubsubOption {
+	return func(_ context.Context, _ host.Host) (pubsub.Option, error) {
+		return pubsub.WithEventTracer(eventTracers(tracers)), nil
+	}
+}
+
+// eventTracers is a chain of event tracers.
+type eventTracers []pubsub.EventTracer
+
+// Trace passes the event to each of the tracers.
+func (t eventTracers) Trace(evt *pb.TraceEvent) {
+	for _, tracer := range t {
+		tracer.Trace(evt)
+	}
+}
+
+// Trace updates the meshes with the peers grafted to or pruned from them. It implements pubsub.EventTracer,
+// and is called synchronously by the router for each of its events.
+func (t *MeshTracer) Trace(evt *pb.TraceEvent) {
+	switch evt.GetType() {
+	case pb.TraceEvent_GRAFT:
+		t.graft(evt.GetGraft().GetTopic(), peer.ID(evt.GetGraft().GetPeerID()))
+	case pb.TraceEvent_PRUNE:
+		t.prune(evt.GetPrune().GetTopic(), peer.ID(evt.GetPrune().GetPeerID()))
+	case pb.TraceEvent_LEAVE:
+		t.leave(evt.GetLeave().GetTopic())
+	case pb.TraceEvent_REMOVE_PEER:
+		t.removePeer(peer.ID(evt.GetRemovePeer().GetPeerID()))
+	}
+}
+
+// MeshPeers returns the peers of the mesh of each topic the node is part of.
+func (t *MeshTracer) MeshPeers() map[string]peer.IDSlice {
+	t.RLock()
+	defer t.RUnlock()
+
+	meshes := make(map[string]peer.IDSlice, len(t.meshes))
+	for topic, mesh := range t.meshes {
+		peers := make(peer.IDSlice, 0, len(mesh))
+		for pid := range mesh {
+			peers = append(peers, pid)
+		}
+		sort.Sort(peers)
+		meshes[topic] = peers
+	}
+	return meshes
+}
+
+func (t *MeshTracer) graft(topic string, pid peer.ID) {
+	t.Lock()
+	defer t.Unlock()
+
+	mesh, ok := t.meshes[topic]
+	if !ok {
+		mesh = make(map[peer.ID]struct{})
+		t.meshes[topic] = mesh
+	}
+	mesh[pid] = struct{}{}
+}
+
+func (t *MeshTracer) prune(topic string, pid peer.ID) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.meshes[topic], pid)
+}
+
+func (t *MeshTracer) leave(topic string) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.meshes, topic)
+}
+
+func (t *MeshTracer) removePeer(pid peer.ID) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, mesh := range t.meshes {
+		delete(mesh, pid)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/envelope"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/validator"
 	psValidator "github.com/onflow/flow-go/network/validator/pubsub"
@@ -59,6 +60,10 @@ const (
 
 	// maximum time to wait for a unicast request to complete for large message size
 	LargeMsgUnicastTimeout = 1000 * time.Second
+
+	// number of streams and messages dropped by the rate limiter which are logged per minute, the others
+	// are only counted by the metrics
+	rateLimitLogBurst = 10
 )
 
 // Middleware handles the input & output on the direct connections we have to
@@ -83,6 +88,12 @@ type Middleware struct {
 	idTranslator               IDTranslator
 	idProvider                 id.IdentifierProvider
 	previousProtocolStatePeers []peer.AddrInfo
+	rateLimiter                *inboundRateLimiter // nil if inbound rate limiting is disabled
+	rateLimitDenyDuration      time.Duration
+	rateLimitLog               zerolog.Logger     // sampled logger of the streams and messages dropped by the rate limiter
+	requestStreams             *requestStreamPool // outbound streams of the request/response protocol
+	sealer                     *envelope.Sealer   // nil if no channel is designated for envelopes
+	transports                 []Transport        // transports the nodes of the protocol state are dialed on
 }
 
 type MiddlewareOption func(*Middleware)
@@ -99,6 +110,36 @@ func WithMessageValidators(validators ...network.MessageValidator) MiddlewareOpt
 	}
 }
 
+// WithInboundRateLimits limits the unicast streams and the messages received from each peer.
+// The streams and messages exceeding the limits are dropped, and the peer is optionally denied.
+func WithInboundRateLimits(limits InboundRateLimits) MiddlewareOption {
+	return func(mw *Middleware) {
+		if !limits.enabled() {
+			return
+		}
+		mw.rateLimiter = newInboundRateLimiter(limits)
+		mw.rateLimitDenyDuration = limits.DenyDuration
+		// a flooding peer exceeds the limits at the rate of its streams and messages
+		mw.rateLimitLog = mw.log.Sample(&zerolog.BurstSampler{Burst: rateLimitLogBurst, Period: time.Minute})
+	}
+}
+
+// WithEnvelopeValidation validates the envelopes of the messages published on the channels designated by the sealer
+// before they are relayed, and rejects the messages without a valid envelope.
+func WithEnvelopeValidation(sealer *envelope.Sealer) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.sealer = sealer
+	}
+}
+
+// WithTransports dials the nodes of the protocol state on the given transports, TCP if none is given.
+// QUIC is preferred to TCP, which remains the fallback for the nodes not listening on QUIC.
+func WithTransports(transports ...Transport) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.transports = transports
+	}
+}
+
 func WithPeerManager(peerManagerFunc PeerManagerFactoryFunc) MiddlewareOption {
 	return func(mw *Middleware) {
 		mw.peerManagerFactory = peerManagerFunc
@@ -207,7 +248,7 @@ func (m *Middleware) UpdateNodeAddresses() {
 	m.log.Info().Msg("Updating protocol state node addresses")
 
 	ids := m.ov.Identities()
-	newInfos, invalid := peerInfosFromIDs(ids)
+	newInfos, invalid := peerInfosFromIDs(ids, m.idTranslator, m.transports...)
 
 	for id, err := range invalid {
 		m.log.Err(err).Str("node_id", id.String()).Msg("failed to extract peer info from identity")
@@ -238,6 +279,8 @@ func (m *Middleware) Start(ov network.Overlay) error {
 
 	m.libP2PNode = libP2PNode
 	m.libP2PNode.SetFlowProtocolStreamHandler(m.handleIncomingStream)
+	m.libP2PNode.SetRequestStreamHandler(m.handleRequestStream)
+	m.requestStreams = newRequestStreamPool(m.log, m.libP2PNode.CreateRequestStream, LargeMsgMaxUnicastMsgSize)
 
 	if m.idProvider == nil {
 		m.idProvider = NewPeerstoreIdentifierProvider(m.log, m.libP2PNode.host, m.idTranslator)
@@ -289,6 +332,9 @@ func (m *Middleware) Stop() {
 	// cancel the context (this also signals any lingering libp2p go routines to exit)
 	m.cancel()
 
+	// fail the requests still waiting for their responses
+	m.requestStreams.close()
+
 	// wait for the readConnection and readSubscription routines to stop
 	m.wg.Wait()
 }
@@ -359,6 +405,134 @@ func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 	return nil
 }
 
+// SendRequest sends msg as a request to the target ID, and waits for the response of the target.
+// The requests sent to a peer are multiplexed on a single stream, reused until it fails. If the context
+// has no deadline, the request fails after the unicast message timeout.
+func (m *Middleware) SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error) {
+	// translates identifier to peer id
+	peerID, err := m.idTranslator.GetPeerID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find peer id for target id: %w", err)
+	}
+
+	if msg.Size() > DefaultMaxUnicastMsgSize {
+		return nil, fmt.Errorf("message size %d exceeds configured max message size %d", msg.Size(), DefaultMaxUnicastMsgSize)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.unicastMessageTimeout)
+		defer cancel()
+	}
+
+	data, err := msg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the request: %w", err)
+	}
+
+	rs, err := m.requestStreams.get(ctx, peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request stream for %s: %w", targetID, err)
+	}
+
+	channel := metrics.ChannelOneToOne
+	if _, isStaked := m.ov.Identities().ByNodeID(targetID); !isStaked {
+		channel = metrics.ChannelOneToOneUnstaked
+	}
+	m.metrics.NetworkMessageSent(len(data), channel, msg.Type)
+
+	payload, err := rs.roundTrip(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", targetID, err)
+	}
+
+	var response message.Message
+	err = response.Unmarshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response of %s: %w", targetID, err)
+	}
+	// the response was authenticated by libp2p to originate from the target
+	response.OriginID = targetID[:]
+
+	m.metrics.NetworkMessageReceived(len(payload), channel, response.Type)
+
+	return &response, nil
+}
+
+// handleRequestStream handles an incoming stream of the request/response protocol. The requests
+// read from the stream are validated like the other inbound messages, handled by the overlay, and
+// their responses are written back on the stream.
+func (m *Middleware) handleRequestStream(s libp2pnetwork.Stream) {
+	log := streamLogger(m.log, s)
+	peerID := s.Conn().RemotePeer()
+
+	if m.rateLimiter != nil && !m.rateLimiter.allowStream(peerID) {
+		m.onRateLimitExceeded(peerID, metrics.ChannelOneToOne, RateLimitStreams)
+		err := s.Reset()
+		if err != nil {
+			log.Err(err).Msg("failed to reset rate limited stream")
+		}
+		return
+	}
+
+	nodeID, err := m.idTranslator.GetFlowID(peerID)
+	if err != nil {
+		log.Warn().Err(err).Str("peer_id", peerID.Pretty()).Msg("received request stream from unknown peer, resetting it")
+		_ = s.Reset()
+		return
+	}
+
+	channel := metrics.ChannelOneToOne
+	if _, isStaked := m.ov.Identities().ByNodeID(nodeID); !isStaked {
+		channel = metrics.ChannelOneToOneUnstaked
+	}
+
+	handle := func(request []byte) ([]byte, error) {
+		var msg message.Message
+		err := msg.Unmarshal(request)
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal request: %w", err)
+		}
+		m.metrics.NetworkMessageReceived(len(request), channel, msg.Type)
+
+		if m.rateLimiter != nil {
+			if limit := m.rateLimiter.allowMessage(peerID, network.Channel(msg.ChannelID)); limit != "" {
+				m.onRateLimitExceeded(peerID, msg.ChannelID, limit)
+				return nil, fmt.Errorf("rate limit exceeded")
+			}
+		}
+
+		msg.OriginID = nodeID[:]
+		for _, v := range m.validators {
+			if !v.Validate(msg) {
+				return nil, fmt.Errorf("request rejected")
+			}
+		}
+
+		// the errors of the engines are only logged, as they may reveal the internal state of the node
+		response, err := m.ov.ReceiveRequest(nodeID, &msg)
+		if err != nil {
+			log.Warn().Err(err).Str("channel", msg.ChannelID).Str("type", msg.Type).Msg("could not handle request")
+			return nil, errRequestFailed
+		}
+
+		data, err := response.Marshal()
+		if err != nil {
+			log.Error().Err(err).Str("channel", msg.ChannelID).Str("type", msg.Type).Msg("could not marshal response")
+			return nil, errRequestFailed
+		}
+		m.metrics.NetworkMessageSent(len(data), channel, response.Type)
+
+		return data, nil
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		serveRequestStream(m.ctx, s, log, DefaultMaxUnicastMsgSize, m.unicastMessageTimeout, handle)
+	}()
+}
+
 // handleIncomingStream handles an incoming stream from a remote peer
 // it is a callback that gets called for each incoming stream by libp2p with a new stream object
 func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
@@ -368,6 +542,15 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 
 	log.Info().Msg("incoming stream received")
 
+	if m.rateLimiter != nil && !m.rateLimiter.allowStream(s.Conn().RemotePeer()) {
+		m.onRateLimitExceeded(s.Conn().RemotePeer(), metrics.ChannelOneToOne, RateLimitStreams)
+		err := s.Reset()
+		if err != nil {
+			log.Err(err).Msg("failed to reset rate limited stream")
+		}
+		return
+	}
+
 	nodeID, err := m.idTranslator.GetFlowID(s.Conn().RemotePeer())
 	if err != nil {
 		log.Err(err).Str("peer_id", s.Conn().RemotePeer().Pretty()).Msg("could not translate peer ID of incoming stream")
@@ -389,8 +572,21 @@ func (m *Middleware) Subscribe(channel network.Channel) error {
 
 	var validators []psValidator.MessageValidator
 	if !engine.PublicChannels().Contains(channel) {
-		// for channels used by the staked nodes, add the topic validator to filter out messages from non-staked nodes
-		validators = append(validators, psValidator.StakedValidator(m.ov.Identity))
+		// for channels used by the staked nodes, add the topic validator to filter out messages from non-staked nodes,
+		// and from nodes whose role is not involved in the channel
+		onReject := m.libP2PNode.RejectionConsumer()
+		if roles, ok := engine.RolesByChannel(channel); ok {
+			validators = append(validators, psValidator.RoleValidator(roles, m.ov.Identity, onReject))
+		} else {
+			validators = append(validators, psValidator.StakedValidator(m.ov.Identity, onReject))
+		}
+	}
+	if m.sealer != nil {
+		if _, ok := m.sealer.Mode(channel); ok {
+			// the envelopes are verified before the messages are relayed, so that the messages of private
+			// channels remain authenticated by the staking key of their origin across the intermediate peers
+			validators = append(validators, psValidator.EnvelopeValidator(m.sealer, m.libP2PNode.RejectionConsumer()))
+		}
 	}
 
 	s, err := m.libP2PNode.Subscribe(m.ctx, topic, validators...)
@@ -430,6 +626,13 @@ func (m *Middleware) Unsubscribe(channel network.Channel) error {
 // The assumption is that the message has been authenticated at the network level (libp2p) to originate from the peer with ID `peerID`
 // this requirement is fulfilled by e.g. the output of readConnection and readSubscription
 func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID peer.ID) {
+	if m.rateLimiter != nil {
+		if limit := m.rateLimiter.allowMessage(peerID, network.Channel(msg.ChannelID)); limit != "" {
+			m.onRateLimitExceeded(peerID, msg.ChannelID, limit)
+			return
+		}
+	}
+
 	flowID, err := m.idTranslator.GetFlowID(peerID)
 	if err != nil {
 		m.log.Warn().Err(err).Msgf("received message from unknown peer %v, and was dropped", peerID.String())
@@ -441,6 +644,45 @@ func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID pe
 	m.processMessage(msg)
 }
 
+// onRateLimitExceeded reports the stream or message dropped because the peer exceeded the given limit,
+// and denies the peer if configured to do so.
+func (m *Middleware) onRateLimitExceeded(peerID peer.ID, channel string, limit string) {
+	m.metrics.InboundRateLimitExceeded(rateLimitChannelLabel(channel), limit)
+
+	log := m.rateLimitLog.With().
+		Str("peer_id", peerID.Pretty()).
+		Str("channel", channel).
+		Str("limit", limit).
+		Logger()
+
+	if m.rateLimitDenyDuration <= 0 {
+		log.Warn().Msg("inbound rate limit exceeded, dropping stream or message")
+		return
+	}
+
+	log.Warn().Dur("deny_duration", m.rateLimitDenyDuration).Msg("inbound rate limit exceeded, denying peer")
+	err := m.libP2PNode.DenyPeer(peerID, time.Now().Add(m.rateLimitDenyDuration))
+	if err != nil {
+		m.log.Err(err).Str("peer_id", peerID.Pretty()).Msg("failed to deny rate limited peer")
+	}
+}
+
+// rateLimitChannelLabel returns the channel reported to the metrics for a stream or message dropped by the rate
+// limiter. The channel of a dropped message is not validated, hence only known channels are reported, and cluster
+// channels by their prefix, so that peers cannot create an unbounded number of metric series.
+func rateLimitChannelLabel(channel string) string {
+	if channel == metrics.ChannelOneToOne {
+		return channel
+	}
+	if prefix, ok := engine.ClusterChannelPrefix(network.Channel(channel)); ok {
+		return prefix
+	}
+	if engine.Exists(network.Channel(channel)) {
+		return channel
+	}
+	return metrics.ChannelUnknown
+}
+
 // processMessage processes a message and eventually passes it to the overlay
 func (m *Middleware) processMessage(msg *message.Message) {
 
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/rs/zerolog"
 
@@ -18,9 +19,11 @@ import (
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/lifecycle"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/envelope"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/queue"
 	_ "github.com/onflow/flow-go/utils/binstat"
+	"github.com/onflow/flow-go/utils/logging"
 )
 
 const DefaultCacheSize = 10e6
@@ -50,6 +53,31 @@ type Network struct {
 	cancel           context.CancelFunc
 	subMngr          network.SubscriptionManager // used to keep track of subscribed channels
 	lifecycleManager *lifecycle.LifecycleManager // used to manage the network's start-stop lifecycle
+	sealer           *envelope.Sealer            // nil if no channel is designated for envelopes
+	outboundSize     int                         // size in bytes of each outbound queue, 0 to send without queuing
+	outboundLock     sync.Mutex
+	outboundQueues   map[flow.Identifier]*queue.OutboundQueue // outbound queue of each target node
+}
+
+type NetworkOption func(*Network)
+
+// WithEnvelopes wraps the messages of the channels designated by the sealer in signed, and optionally
+// encrypted, envelopes. The messages received on these channels without a valid envelope are dropped.
+func WithEnvelopes(sealer *envelope.Sealer) NetworkOption {
+	return func(n *Network) {
+		n.sealer = sealer
+	}
+}
+
+// WithOutboundQueues buffers the outgoing messages in an outbound queue per target node, which holds up to
+// size bytes of messages of all channels, whether unicast or published. The queues send the messages by
+// priority and drop the lowest priority messages first when they are full, so that e.g. a backlog of sync
+// responses to a node never delays the proposals and votes sent to it. The messages larger than the queues
+// are sent without queuing.
+func WithOutboundQueues(size int) NetworkOption {
+	return func(n *Network) {
+		n.outboundSize = size
+	}
 }
 
 // NewNetwork creates a new naive overlay network, using the given middleware to
@@ -66,6 +94,7 @@ func NewNetwork(
 	sm network.SubscriptionManager,
 	metrics module.NetworkMetrics,
 	identityProvider id.IdentityProvider,
+	opts ...NetworkOption,
 ) (*Network, error) {
 
 	rcache, err := newRcvCache(csize)
@@ -84,6 +113,10 @@ func NewNetwork(
 		subMngr:          sm,
 		lifecycleManager: lifecycle.NewLifecycleManager(),
 		identityProvider: identityProvider,
+		outboundQueues:   make(map[flow.Identifier]*queue.OutboundQueue),
+	}
+	for _, opt := range opts {
+		opt(o)
 	}
 	o.ctx, o.cancel = context.WithCancel(context.Background())
 
@@ -143,6 +176,7 @@ func (n *Network) Register(channel network.Channel, engine network.Engine) (netw
 		publish:   n.publish,
 		unicast:   n.unicast,
 		multicast: n.multicast,
+		request:   n.request,
 		close:     n.unregister,
 	}
 
@@ -189,6 +223,43 @@ func (n *Network) Receive(nodeID flow.Identifier, msg *message.Message) error {
 	return nil
 }
 
+// ReceiveRequest handles a request received from the given node with the engine registered on the channel
+// of the request, and returns the response of the engine. The engine must implement network.RequestHandler.
+// Unlike the other inbound messages, requests are neither deduplicated nor queued, as their responses are
+// awaited by the requesters.
+func (n *Network) ReceiveRequest(nodeID flow.Identifier, msg *message.Message) (*message.Message, error) {
+	channel := network.Channel(msg.ChannelID)
+
+	nodeID, request, err := n.decode(channel, nodeID, msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	eng, err := n.subMngr.GetEngine(channel)
+	if err != nil {
+		return nil, fmt.Errorf("could not get engine for channel %s: %w", channel, err)
+	}
+
+	handler, ok := eng.(network.RequestHandler)
+	if !ok {
+		return nil, fmt.Errorf("engine of channel %s does not handle requests", channel)
+	}
+
+	startTimestamp := time.Now()
+	response, err := handler.HandleRequest(channel, nodeID, request)
+	n.metrics.InboundProcessDuration(channel.String(), time.Since(startTimestamp))
+	if err != nil {
+		return nil, fmt.Errorf("could not handle request of type %T: %w", request, err)
+	}
+
+	msg, err = n.genNetworkMessage(channel, response, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate response message: %w", err)
+	}
+
+	return msg, nil
+}
+
 func (n *Network) processNetworkMessage(senderID flow.Identifier, message *message.Message) error {
 	// checks the cache for deduplication and adds the message if not already present
 	if n.rcache.add(message.EventID, network.Channel(message.ChannelID)) {
@@ -208,7 +279,7 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 	}
 
 	// Convert message payload to a known message type
-	decodedMessage, err := n.codec.Decode(message.Payload)
+	senderID, decodedMessage, err := n.decode(network.Channel(message.ChannelID), senderID, message.Payload)
 	if err != nil {
 		return fmt.Errorf("could not decode event: %w", err)
 	}
@@ -230,6 +301,26 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 	return nil
 }
 
+// decode decodes the payload received from the given node on the channel, after verifying and opening its
+// envelope if the channel is designated for envelopes. It returns the decoded payload with the identifier
+// of its origin, which is the signer of the envelope on the designated channels.
+func (n *Network) decode(channel network.Channel, originID flow.Identifier, payload []byte) (flow.Identifier, interface{}, error) {
+	if n.sealer != nil {
+		var err error
+		originID, payload, err = n.sealer.Open(channel, originID, payload)
+		if err != nil {
+			return flow.ZeroID, nil, fmt.Errorf("could not open envelope: %w", err)
+		}
+	}
+
+	decoded, err := n.codec.Decode(payload)
+	if err != nil {
+		return flow.ZeroID, nil, err
+	}
+
+	return originID, decoded, nil
+}
+
 // genNetworkMessage uses the codec to encode an event into a NetworkMessage
 func (n *Network) genNetworkMessage(channel network.Channel, event interface{}, targetIDs ...flow.Identifier) (*message.Message, error) {
 	// encode the payload using the configured codec
@@ -238,6 +329,14 @@ func (n *Network) genNetworkMessage(channel network.Channel, event interface{},
 		return nil, fmt.Errorf("could not encode event: %w", err)
 	}
 
+	// wrap the payload in an envelope if the channel is designated for envelopes
+	if n.sealer != nil {
+		payload, err = n.sealer.Seal(channel, payload, targetIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("could not seal event: %w", err)
+		}
+	}
+
 	//bs := binstat.EnterTimeVal(binstat.BinNet+":wire<3payload2message", int64(len(payload)))
 	//defer binstat.Leave(bs)
 
@@ -283,7 +382,8 @@ func (n *Network) genNetworkMessage(channel network.Channel, event interface{},
 
 // unicast sends the message in a reliable way to the given recipient.
 // It uses 1-1 direct messaging over the underlying network to deliver the message.
-// It returns an error if unicasting fails.
+// If the outbound queues are enabled, it returns once the message is queued, with an error if the message
+// is dropped by the outbound queue of the recipient, and the errors of the delivery are only logged.
 func (n *Network) unicast(channel network.Channel, message interface{}, targetID flow.Identifier) error {
 	if targetID == n.me.NodeID() {
 		n.logger.Debug().Msg("network skips self unicasting")
@@ -296,7 +396,9 @@ func (n *Network) unicast(channel network.Channel, message interface{}, targetID
 		return fmt.Errorf("unicast could not generate network message: %w", err)
 	}
 
-	err = n.mw.SendDirect(msg, targetID)
+	err = n.send([]flow.Identifier{targetID}, channel, message, msg, func() error {
+		return n.mw.SendDirect(msg, targetID)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to send message to %x: %w", targetID, err)
 	}
@@ -304,6 +406,31 @@ func (n *Network) unicast(channel network.Channel, message interface{}, targetID
 	return nil
 }
 
+// request sends the message as a request to the given recipient, and returns the decoded response.
+// It uses 1-1 direct messaging over the underlying network.
+func (n *Network) request(ctx context.Context, channel network.Channel, message interface{}, targetID flow.Identifier) (interface{}, error) {
+	if targetID == n.me.NodeID() {
+		return nil, fmt.Errorf("cannot send request to self")
+	}
+
+	msg, err := n.genNetworkMessage(channel, message, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("request could not generate network message: %w", err)
+	}
+
+	response, err := n.mw.SendRequest(ctx, msg, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %x: %w", targetID, err)
+	}
+
+	_, decoded, err := n.decode(channel, targetID, response.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response: %w", err)
+	}
+
+	return decoded, nil
+}
+
 // publish sends the message in an unreliable way to the given recipients.
 // In this context, unreliable means that the message is published over a libp2p pub-sub
 // channel and can be read by any node subscribed to that channel.
@@ -360,7 +487,9 @@ func (n *Network) sendOnChannel(channel network.Channel, message interface{}, ta
 
 	// publish the message through the channel, however, the message
 	// is only restricted to targetIDs (if they subscribed to channel).
-	err = n.mw.Publish(msg, channel)
+	err = n.send(targetIDs, channel, message, msg, func() error {
+		return n.mw.Publish(msg, channel)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to send message on channel %s: %w", channel, err)
 	}
@@ -368,6 +497,99 @@ func (n *Network) sendOnChannel(channel network.Channel, message interface{}, ta
 	return nil
 }
 
+// send sends the message to the target nodes with the given send function, through their outbound queues if
+// the outbound queues are enabled. A published message is queued for each of its targets, so that it is
+// scheduled against the other messages sent to them, and is sent once by the first worker to dequeue it. It
+// returns once the message is queued, with an error only if it is dropped by the queues of all its targets.
+// The errors of the delivery of a queued message are logged by the worker sending it.
+func (n *Network) send(targetIDs []flow.Identifier, channel network.Channel, event interface{}, msg *message.Message, send func() error) error {
+	// the messages larger than the queues, e.g. the chunk data responses of up to LargeMsgMaxUnicastMsgSize,
+	// could never be queued, hence they are sent directly
+	if n.outboundSize == 0 || msg.Size() > n.outboundSize {
+		return send()
+	}
+
+	var once sync.Once
+	sendOnce := func() error {
+		var err error
+		once.Do(func() {
+			err = send()
+		})
+		return err
+	}
+
+	qm := queue.QMessage{
+		Payload:  event,
+		Size:     msg.Size(),
+		Target:   channel,
+		SenderID: n.me.NodeID(),
+	}
+	var errs *multierror.Error
+	for _, targetID := range targetIDs {
+		err := n.outboundQueue(targetID).Insert(queue.OutboundMessage{QMessage: qm, Send: sendOnce})
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not queue message for %x: %w", targetID, err))
+			continue
+		}
+		// the message is sent as soon as it is queued for one of its targets
+		return nil
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// outboundQueue returns the outbound queue of the target node, creating it with its sending worker if needed.
+// A single worker sends the messages of each target node, so that the messages sent to a slow node only delay
+// the other messages sent to the same node.
+func (n *Network) outboundQueue(targetID flow.Identifier) *queue.OutboundQueue {
+	n.outboundLock.Lock()
+	defer n.outboundLock.Unlock()
+
+	q, ok := n.outboundQueues[targetID]
+	if !ok {
+		q = queue.NewOutboundQueue(n.ctx, n.outboundSize, n.metrics)
+		n.outboundQueues[targetID] = q
+		go n.outboundWorker(targetID, q)
+	}
+	return q
+}
+
+// outboundWorker sends the messages of the outbound queue of a target node until the network is stopped or
+// the queue is closed, and logs the errors of their delivery.
+func (n *Network) outboundWorker(targetID flow.Identifier, q *queue.OutboundQueue) {
+	for {
+		qm, ok := q.Remove()
+		if !ok {
+			return
+		}
+		err := qm.Send()
+		if err != nil {
+			n.logger.Warn().
+				Err(err).
+				Hex("target_id", logging.ID(targetID)).
+				Str("channel_id", qm.Target.String()).
+				Msg("failed to send queued message")
+		}
+	}
+}
+
+// RemoveStaleOutboundQueues closes the outbound queues of the nodes which are no longer part of the network,
+// which stops their workers and drops their buffered messages. It is meant to be called on identity changes.
+func (n *Network) RemoveStaleOutboundQueues() {
+	identities := n.Identities().Lookup()
+
+	n.outboundLock.Lock()
+	defer n.outboundLock.Unlock()
+
+	for targetID, q := range n.outboundQueues {
+		if _, ok := identities[targetID]; ok {
+			continue
+		}
+		q.Close()
+		delete(n.outboundQueues, targetID)
+	}
+}
+
 // queueSubmitFunc submits the message to the engine synchronously. It is the callback for the queue worker
 // when it gets a message from the queue
 func (n *Network) queueSubmitFunc(message interface{}) {
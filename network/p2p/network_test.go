@@ -0,0 +1,102 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNetwork_OutboundQueuesByPeer checks that the outbound queue of a node schedules the messages of all
+// channels, i.e. that a block proposal published to a node overtakes the backlog of sync responses unicast
+// to it, and that sending returns once the messages are queued.
+func TestNetwork_OutboundQueuesByPeer(t *testing.T) {
+	identities := unittest.IdentityListFixture(2)
+	me, err := local.New(identities[0], nil)
+	require.NoError(t, err)
+	targetID := identities[1].NodeID
+
+	// the middleware blocks the delivery to the target until released, and records the order of the deliveries
+	release := make(chan struct{})
+	sent := make(chan interface{}, 100)
+	codec := cbor.NewCodec()
+	decode := func(msg *message.Message) interface{} {
+		event, err := codec.Decode(msg.Payload)
+		require.NoError(t, err)
+		return event
+	}
+	mw := &mocknetwork.Middleware{}
+	mw.On("SendDirect", mock.Anything, targetID).
+		Run(func(args mock.Arguments) {
+			<-release
+			sent <- decode(args.Get(0).(*message.Message))
+		}).
+		Return(nil)
+	mw.On("Publish", mock.Anything, engine.PushBlocks).
+		Run(func(args mock.Arguments) {
+			<-release
+			sent <- decode(args.Get(0).(*message.Message))
+		}).
+		Return(nil)
+
+	net, err := NewNetwork(
+		zerolog.Nop(),
+		codec,
+		me,
+		mw,
+		DefaultCacheSize,
+		nil,
+		nil,
+		metrics.NewNoopCollector(),
+		id.NewFixedIdentityProvider(identities),
+		WithOutboundQueues(1<<20),
+	)
+	require.NoError(t, err)
+	defer net.cancel()
+
+	// none of the sends blocks on the delivery of the previous messages
+	syncResponses := 10
+	header := unittest.BlockHeaderFixture()
+	unittest.RequireReturnsBefore(t, func() {
+		for i := 0; i < syncResponses; i++ {
+			err := net.unicast(engine.SyncCommittee, &messages.SyncResponse{Nonce: uint64(i)}, targetID)
+			require.NoError(t, err)
+		}
+		err := net.publish(engine.PushBlocks, &messages.BlockProposal{Header: &header}, targetID)
+		require.NoError(t, err)
+	}, time.Second, "sending should return once the messages are queued")
+
+	close(release)
+
+	var events []interface{}
+	for i := 0; i < syncResponses+1; i++ {
+		select {
+		case event := <-sent:
+			events = append(events, event)
+		case <-time.After(time.Second):
+			t.Fatalf("only %d messages sent", len(events))
+		}
+	}
+
+	// at most the sync response being delivered when the proposal was queued is sent before it
+	proposal := -1
+	for i, event := range events {
+		if _, ok := event.(*messages.BlockProposal); ok {
+			proposal = i
+		}
+	}
+	assert.True(t, proposal == 0 || proposal == 1, "proposal should overtake the sync responses, sent at %d", proposal)
+}
@@ -26,6 +26,9 @@ type Connector interface {
 // DefaultPeerUpdateInterval is default duration for which the peer manager waits in between attempts to update peer connections
 var DefaultPeerUpdateInterval = 10 * time.Minute
 
+// DefaultPeerUpdateMinInterval is the default minimum duration between two peer updates
+var DefaultPeerUpdateMinInterval = 10 * time.Second
+
 // PeerManager adds and removes connections to peers periodically and on request
 type PeerManager struct {
 	unit               *engine.Unit
@@ -34,6 +37,7 @@ type PeerManager struct {
 	peerRequestQ       chan struct{}                // a channel to queue a peer update request
 	connector          Connector                    // connector to connect or disconnect from peers
 	peerUpdateInterval time.Duration                // interval the peer manager runs on
+	minUpdateInterval  time.Duration                // minimum duration between two peer updates
 }
 
 // Option represents an option for the peer manager.
@@ -45,6 +49,14 @@ func WithInterval(period time.Duration) Option {
 	}
 }
 
+// WithMinInterval sets the minimum duration between two peer updates. The peer updates requested sooner are delayed,
+// which limits the churn of connections when the peer updates are requested in bursts.
+func WithMinInterval(period time.Duration) Option {
+	return func(pm *PeerManager) {
+		pm.minUpdateInterval = period
+	}
+}
+
 type PeersProvider func() (peer.IDSlice, error)
 
 // NewPeerManager creates a new peer manager which calls the peersProvider callback to get a list of peers to connect to
@@ -103,15 +115,33 @@ func (pm *PeerManager) Done() <-chan struct{} {
 	return pm.unit.Done()
 }
 
-// updateLoop triggers an update peer request when it has been requested
+// updateLoop triggers an update peer request when it has been requested, at most once per minimum update interval
 func (pm *PeerManager) updateLoop() {
+	var lastUpdate time.Time
 	for {
 		select {
 		case <-pm.peerRequestQ:
-			pm.updatePeers()
 		case <-pm.unit.Quit():
 			return
 		}
+
+		if wait := pm.minUpdateInterval - time.Since(lastUpdate); wait > 0 {
+			pm.logger.Debug().Dur("delay", wait).Msg("delaying peer update to limit connection churn")
+			select {
+			case <-time.After(wait):
+			case <-pm.unit.Quit():
+				return
+			}
+
+			// the updates requested in the meantime are served by this update
+			select {
+			case <-pm.peerRequestQ:
+			default:
+			}
+		}
+
+		pm.updatePeers()
+		lastUpdate = time.Now()
 	}
 }
 
@@ -240,3 +240,54 @@ func (suite *PeerManagerTestSuite) TestConcurrentOnDemandPeerUpdate() {
 		return connector.AssertNumberOfCalls(suite.T(), "UpdatePeers", 2)
 	}, 10*time.Second, 100*time.Millisecond)
 }
+
+// TestMinIntervalPeerUpdate tests that the peer updates requested sooner than the minimum interval after the previous
+// update are delayed and coalesced
+func (suite *PeerManagerTestSuite) TestMinIntervalPeerUpdate() {
+	// create some test ids
+	pids := suite.generatePeerIDs(10)
+
+	// setup a ID provider callback to return peer IDs
+	idProvider := func() (peer.IDSlice, error) {
+		return pids, nil
+	}
+
+	mu := &sync.Mutex{} // provides mutual exclusion on the update times
+	var updates []time.Time
+	connector := new(mocknetwork.Connector)
+	connector.On("UpdatePeers", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, time.Now())
+	}).Return(nil)
+	countUpdates := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(updates)
+	}
+
+	minInterval := 200 * time.Millisecond
+	pm := NewPeerManager(suite.log, idProvider, connector, WithInterval(time.Hour), WithMinInterval(minInterval))
+	unittest.RequireCloseBefore(suite.T(), pm.Ready(), 2*time.Second, "could not start peer manager")
+
+	// the first update is not delayed
+	require.Eventually(suite.T(), func() bool {
+		return countUpdates() == 1
+	}, time.Second, 10*time.Millisecond, "UpdatePeers is not running on startup")
+
+	// a burst of requests results in a single update, once the minimum interval has elapsed
+	for i := 0; i < 10; i++ {
+		pm.RequestPeerUpdate()
+	}
+	require.Eventually(suite.T(), func() bool {
+		return countUpdates() == 2
+	}, time.Second, 10*time.Millisecond, "UpdatePeers is not running on request")
+
+	mu.Lock()
+	assert.GreaterOrEqual(suite.T(), updates[1].Sub(updates[0]), minInterval)
+	mu.Unlock()
+
+	// no other update is made for the coalesced requests
+	time.Sleep(2 * minInterval)
+	assert.Equal(suite.T(), 2, countUpdates())
+}
@@ -17,6 +17,10 @@ const (
 	// All nodes communicate with each other using this protocol id suffixed with the id of the root block
 	FlowLibP2POneToOneProtocolIDPrefix = FlowLibP2PProtocolCommonPrefix + "/push/"
 
+	// A unique Libp2p protocol ID prefix for the Flow request/response protocol, suffixed with the id of the root block.
+	// Its streams multiplex the requests sent to a peer and their responses.
+	FlowLibP2PRequestProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/request/"
+
 	// the Flow Ping protocol prefix
 	FlowLibP2PPingProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/ping/"
 )
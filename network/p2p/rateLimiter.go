@@ -0,0 +1,181 @@
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/network"
+)
+
+const (
+	// RateLimitStreams is the limit on the number of unicast streams opened by a peer
+	RateLimitStreams = "streams"
+	// RateLimitMessages is the limit on the number of messages received from a peer on all channels
+	RateLimitMessages = "messages"
+	// RateLimitChannel is the limit on the number of messages received from a peer on a single channel
+	RateLimitChannel = "channel"
+
+	// rateLimiterIdleTimeout is the time after which the limiters of a peer which did not send anything are dropped
+	rateLimiterIdleTimeout = 5 * time.Minute
+)
+
+// RateLimit is the rate, in events per second, and the burst of a token bucket rate limiter.
+// A zero rate disables the limit.
+type RateLimit struct {
+	Rate  rate.Limit
+	Burst int
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+func (l RateLimit) limiter() *rate.Limiter {
+	burst := l.Burst
+	if burst <= 0 {
+		// allow at least one second worth of events at once
+		burst = int(l.Rate)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return rate.NewLimiter(l.Rate, burst)
+}
+
+// InboundRateLimits are the limits on the inbound traffic of each peer.
+type InboundRateLimits struct {
+	// Streams limits the unicast streams opened by a peer.
+	Streams RateLimit
+	// Messages limits the messages received from a peer, by unicast or pubsub, on all channels.
+	// Pubsub messages are accounted to the peer which originated them, not to the peer which relayed them.
+	Messages RateLimit
+	// Channels limits the messages received from a peer on specific channels.
+	Channels map[network.Channel]RateLimit
+	// DenyDuration is how long the connections with a peer exceeding a limit are rejected,
+	// zero to only drop its streams and messages.
+	DenyDuration time.Duration
+}
+
+// enabled returns true if any of the limits is enabled.
+func (l InboundRateLimits) enabled() bool {
+	if l.Streams.enabled() || l.Messages.enabled() {
+		return true
+	}
+	for _, limit := range l.Channels {
+		if limit.enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// inboundRateLimiter enforces the inbound rate limits of each peer.
+type inboundRateLimiter struct {
+	sync.Mutex
+	limits      InboundRateLimits
+	peers       map[peer.ID]*peerRateLimiters
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// peerRateLimiters are the rate limiters of a single peer, created when first needed.
+type peerRateLimiters struct {
+	streams  *rate.Limiter
+	messages *rate.Limiter
+	channels map[network.Channel]*rate.Limiter
+	lastSeen time.Time
+}
+
+func newInboundRateLimiter(limits InboundRateLimits) *inboundRateLimiter {
+	return &inboundRateLimiter{
+		limits:      limits,
+		peers:       make(map[peer.ID]*peerRateLimiters),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// allowStream returns true if the peer is allowed to open a new stream.
+func (r *inboundRateLimiter) allowStream(pid peer.ID) bool {
+	if !r.limits.Streams.enabled() {
+		return true
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	limiters := r.peerLimiters(pid, now)
+	if limiters.streams == nil {
+		limiters.streams = r.limits.Streams.limiter()
+	}
+
+	return limiters.streams.AllowN(now, 1)
+}
+
+// allowMessage returns an empty string if the peer is allowed to send a message on the channel,
+// or the limit it exceeded otherwise.
+func (r *inboundRateLimiter) allowMessage(pid peer.ID, channel network.Channel) string {
+	channelLimit, hasChannelLimit := r.limits.Channels[channel]
+	hasChannelLimit = hasChannelLimit && channelLimit.enabled()
+	if !r.limits.Messages.enabled() && !hasChannelLimit {
+		return ""
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	limiters := r.peerLimiters(pid, now)
+
+	// the channel limit is checked first, so that the messages dropped on a channel
+	// do not consume the tokens of the other channels
+	if hasChannelLimit {
+		limiter, ok := limiters.channels[channel]
+		if !ok {
+			limiter = channelLimit.limiter()
+			limiters.channels[channel] = limiter
+		}
+		if !limiter.AllowN(now, 1) {
+			return RateLimitChannel
+		}
+	}
+
+	if r.limits.Messages.enabled() {
+		if limiters.messages == nil {
+			limiters.messages = r.limits.Messages.limiter()
+		}
+		if !limiters.messages.AllowN(now, 1) {
+			return RateLimitMessages
+		}
+	}
+
+	return ""
+}
+
+// peerLimiters returns the limiters of the peer, and drops the limiters of the idle peers.
+// Must be called with the lock held.
+func (r *inboundRateLimiter) peerLimiters(pid peer.ID, now time.Time) *peerRateLimiters {
+	if now.Sub(r.lastCleanup) > rateLimiterIdleTimeout {
+		for id, limiters := range r.peers {
+			if now.Sub(limiters.lastSeen) > rateLimiterIdleTimeout {
+				delete(r.peers, id)
+			}
+		}
+		r.lastCleanup = now
+	}
+
+	limiters, ok := r.peers[pid]
+	if !ok {
+		limiters = &peerRateLimiters{
+			channels: make(map[network.Channel]*rate.Limiter),
+		}
+		r.peers[pid] = limiters
+	}
+	limiters.lastSeen = now
+
+	return limiters
+}
@@ -0,0 +1,92 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network"
+)
+
+func TestInboundRateLimiter(t *testing.T) {
+	peer1 := peer.ID("peer1")
+	peer2 := peer.ID("peer2")
+
+	t.Run("streams", func(t *testing.T) {
+		now := time.Now()
+		limiter := newInboundRateLimiter(InboundRateLimits{
+			Streams: RateLimit{Rate: 1, Burst: 2},
+		})
+		limiter.now = func() time.Time { return now }
+
+		assert.True(t, limiter.allowStream(peer1))
+		assert.True(t, limiter.allowStream(peer1))
+		assert.False(t, limiter.allowStream(peer1))
+
+		// limits are per peer
+		assert.True(t, limiter.allowStream(peer2))
+
+		// the bucket is refilled over time
+		now = now.Add(time.Second)
+		assert.True(t, limiter.allowStream(peer1))
+		assert.False(t, limiter.allowStream(peer1))
+
+		// messages are not limited
+		assert.Equal(t, "", limiter.allowMessage(peer1, engine.PushBlocks))
+	})
+
+	t.Run("messages and channels", func(t *testing.T) {
+		now := time.Now()
+		limiter := newInboundRateLimiter(InboundRateLimits{
+			Messages: RateLimit{Rate: 1, Burst: 3},
+			Channels: map[network.Channel]RateLimit{
+				engine.PushBlocks: {Rate: 1, Burst: 1},
+			},
+		})
+		limiter.now = func() time.Time { return now }
+
+		assert.Equal(t, "", limiter.allowMessage(peer1, engine.PushBlocks))
+		assert.Equal(t, RateLimitChannel, limiter.allowMessage(peer1, engine.PushBlocks))
+
+		// the messages dropped on a channel do not count towards the limit on all channels
+		assert.Equal(t, "", limiter.allowMessage(peer1, engine.SyncCommittee))
+		assert.Equal(t, "", limiter.allowMessage(peer1, engine.SyncCommittee))
+		assert.Equal(t, RateLimitMessages, limiter.allowMessage(peer1, engine.SyncCommittee))
+
+		assert.Equal(t, "", limiter.allowMessage(peer2, engine.PushBlocks))
+	})
+
+	t.Run("idle peers are dropped", func(t *testing.T) {
+		now := time.Now()
+		limiter := newInboundRateLimiter(InboundRateLimits{
+			Streams: RateLimit{Rate: 1},
+		})
+		limiter.now = func() time.Time { return now }
+
+		assert.True(t, limiter.allowStream(peer1))
+		assert.True(t, limiter.allowStream(peer2))
+		assert.Len(t, limiter.peers, 2)
+
+		now = now.Add(rateLimiterIdleTimeout + time.Second)
+		assert.True(t, limiter.allowStream(peer2))
+		assert.Len(t, limiter.peers, 1)
+	})
+}
+
+// TestRateLimitChannelLabel tests that the dropped messages are reported on known channels only, so that their
+// unvalidated channels cannot create an unbounded number of metric series.
+func TestRateLimitChannelLabel(t *testing.T) {
+	assert.Equal(t, engine.PushBlocks.String(), rateLimitChannelLabel(engine.PushBlocks.String()))
+	assert.Equal(t, metrics.ChannelOneToOne, rateLimitChannelLabel(metrics.ChannelOneToOne))
+	assert.Equal(t, metrics.ChannelUnknown, rateLimitChannelLabel("made-up-channel"))
+
+	// the cluster channels are reported by their prefix, whatever their cluster ID
+	label := rateLimitChannelLabel(engine.ChannelSyncCluster(flow.ChainID("made-up-cluster")).String())
+	assert.Equal(t, rateLimitChannelLabel(engine.ChannelSyncCluster(flow.ChainID("other-cluster")).String()), label)
+	assert.NotEqual(t, metrics.ChannelUnknown, label)
+}
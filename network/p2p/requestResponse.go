@@ -0,0 +1,363 @@
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+)
+
+// The streams of the request/response protocol carry frames, each made of the ID of the request it
+// belongs to, its kind, and its length prefixed payload. The ID correlates a response with its request,
+// hence a single stream per peer carries all the concurrent requests sent to the peer.
+const (
+	requestFrame  byte = iota + 1 // payload is the request message
+	responseFrame                 // payload is the response message
+	errorFrame                    // payload is the error returned by the handler of the request
+)
+
+// maxConcurrentRequests is the maximum number of requests of a stream handled concurrently.
+// The frames of the stream are not read while this many requests are being handled.
+const maxConcurrentRequests = 16
+
+// errRequestStreamClosed is the error of the requests pending on a closed stream.
+var errRequestStreamClosed = errors.New("request stream closed")
+
+// errRequestFailed is the error returned to the remote peer when its request could not be handled.
+var errRequestFailed = errors.New("request failed")
+
+type frame struct {
+	id      uint64
+	kind    byte
+	payload []byte
+}
+
+// writeFrame writes the frame with a single write, so that concurrent frames do not interleave.
+func writeFrame(w io.Writer, f frame) error {
+	buf := make([]byte, 0, 2*binary.MaxVarintLen64+1+len(f.payload))
+	buf = appendUvarint(buf, f.id)
+	buf = append(buf, f.kind)
+	buf = appendUvarint(buf, uint64(len(f.payload)))
+	buf = append(buf, f.payload...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame reads the next frame, rejecting the payloads larger than maxSize.
+func readFrame(r *bufio.Reader, maxSize int) (frame, error) {
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		return frame{}, err
+	}
+	kind, err := r.ReadByte()
+	if err != nil {
+		return frame{}, err
+	}
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return frame{}, err
+	}
+	if size > uint64(maxSize) {
+		return frame{}, fmt.Errorf("frame size %d exceeds max size %d", size, maxSize)
+	}
+	payload := make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return frame{}, err
+	}
+	return frame{id: id, kind: kind, payload: payload}, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+// requestStream is the outbound stream carrying the requests sent to a peer and their responses.
+type requestStream struct {
+	stream  libp2pnetwork.Stream
+	log     zerolog.Logger
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan frame
+	done    chan struct{} // closed when the stream fails
+	err     error
+	onClose func(*requestStream)
+}
+
+func newRequestStream(stream libp2pnetwork.Stream, log zerolog.Logger, onClose func(*requestStream)) *requestStream {
+	return &requestStream{
+		stream:  stream,
+		log:     log,
+		pending: make(map[uint64]chan frame),
+		done:    make(chan struct{}),
+		onClose: onClose,
+	}
+}
+
+// roundTrip sends the request and waits for its response, until the context expires.
+func (rs *requestStream) roundTrip(ctx context.Context, request []byte) ([]byte, error) {
+	rs.mu.Lock()
+	if rs.err != nil {
+		rs.mu.Unlock()
+		return nil, rs.err
+	}
+	rs.nextID++
+	id := rs.nextID
+	response := make(chan frame, 1)
+	rs.pending[id] = response
+	rs.mu.Unlock()
+
+	defer func() {
+		rs.mu.Lock()
+		delete(rs.pending, id)
+		rs.mu.Unlock()
+	}()
+
+	err := rs.write(ctx, frame{id: id, kind: requestFrame, payload: request})
+	if err != nil {
+		rs.fail(fmt.Errorf("could not write request: %w", err))
+		return nil, err
+	}
+
+	select {
+	case f := <-response:
+		if f.kind == errorFrame {
+			return nil, fmt.Errorf("request failed on remote node: %s", f.payload)
+		}
+		return f.payload, nil
+	case <-rs.done:
+		return nil, rs.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (rs *requestStream) write(ctx context.Context, f frame) error {
+	rs.writeMu.Lock()
+	defer rs.writeMu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	err := rs.stream.SetWriteDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("could not set write deadline: %w", err)
+	}
+	return writeFrame(rs.stream, f)
+}
+
+// readLoop dispatches the responses read from the stream to their pending requests, until the stream fails.
+func (rs *requestStream) readLoop(maxSize int) {
+	r := bufio.NewReader(rs.stream)
+	for {
+		f, err := readFrame(r, maxSize)
+		if err != nil {
+			rs.fail(fmt.Errorf("could not read response: %w", err))
+			return
+		}
+
+		rs.mu.Lock()
+		response, ok := rs.pending[f.id]
+		rs.mu.Unlock()
+		if !ok {
+			// the request expired before its response was received
+			rs.log.Debug().Uint64("request_id", f.id).Msg("dropping response of expired request")
+			continue
+		}
+		select {
+		case response <- f:
+		default:
+			rs.log.Warn().Uint64("request_id", f.id).Msg("dropping duplicate response")
+		}
+	}
+}
+
+// fail fails the pending requests of the stream with the given error, and resets the stream.
+func (rs *requestStream) fail(err error) {
+	rs.mu.Lock()
+	if rs.err != nil {
+		rs.mu.Unlock()
+		return
+	}
+	rs.err = fmt.Errorf("%w: %v", errRequestStreamClosed, err)
+	close(rs.done)
+	rs.mu.Unlock()
+
+	rs.onClose(rs)
+	resetErr := rs.stream.Reset()
+	if resetErr != nil {
+		rs.log.Debug().Err(resetErr).Msg("failed to reset request stream")
+	}
+}
+
+// requestStreamPool keeps a request stream per peer, reused by the requests sent to the peer.
+type requestStreamPool struct {
+	sync.Mutex
+	log     zerolog.Logger
+	create  func(ctx context.Context, peerID peer.ID) (libp2pnetwork.Stream, error)
+	maxSize int // maximum size of the responses
+	streams map[peer.ID]*requestStream
+	closed  bool
+}
+
+func newRequestStreamPool(
+	log zerolog.Logger,
+	create func(ctx context.Context, peerID peer.ID) (libp2pnetwork.Stream, error),
+	maxSize int,
+) *requestStreamPool {
+	return &requestStreamPool{
+		log:     log,
+		create:  create,
+		maxSize: maxSize,
+		streams: make(map[peer.ID]*requestStream),
+	}
+}
+
+// get returns the request stream of the peer, creating it if there is none.
+func (p *requestStreamPool) get(ctx context.Context, peerID peer.ID) (*requestStream, error) {
+	p.Lock()
+	rs, ok := p.streams[peerID]
+	p.Unlock()
+	if ok {
+		return rs, nil
+	}
+
+	// the stream is created without holding the lock, so that the requests to the other peers are not delayed
+	stream, err := p.create(ctx, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed {
+		_ = stream.Reset()
+		return nil, errRequestStreamClosed
+	}
+	if existing, ok := p.streams[peerID]; ok {
+		// a concurrent request created a stream in the meantime
+		_ = stream.Close()
+		return existing, nil
+	}
+
+	rs = newRequestStream(stream, p.log.With().Str("peer_id", peerID.Pretty()).Logger(), p.remove)
+	p.streams[peerID] = rs
+	go rs.readLoop(p.maxSize)
+
+	return rs, nil
+}
+
+// remove removes the stream from the pool, if it is still the stream of its peer.
+func (p *requestStreamPool) remove(rs *requestStream) {
+	p.Lock()
+	defer p.Unlock()
+
+	peerID := rs.stream.Conn().RemotePeer()
+	if p.streams[peerID] == rs {
+		delete(p.streams, peerID)
+	}
+}
+
+// close fails the requests pending on the streams of the pool, and prevents the creation of new streams.
+func (p *requestStreamPool) close() {
+	p.Lock()
+	p.closed = true
+	streams := make([]*requestStream, 0, len(p.streams))
+	for _, rs := range p.streams {
+		streams = append(streams, rs)
+	}
+	p.Unlock()
+
+	for _, rs := range streams {
+		rs.fail(errors.New("middleware stopped"))
+	}
+}
+
+// requestHandlerFunc handles a request payload, and returns the response payload.
+type requestHandlerFunc func(request []byte) ([]byte, error)
+
+// serveRequestStream reads the requests of an inbound request stream and writes their responses,
+// until the remote closes the stream or the context is cancelled. The requests are handled
+// concurrently, at most maxConcurrentRequests at a time.
+func serveRequestStream(
+	ctx context.Context,
+	stream libp2pnetwork.Stream,
+	log zerolog.Logger,
+	maxSize int,
+	timeout time.Duration,
+	handle requestHandlerFunc,
+) {
+	var writeMu sync.Mutex
+	var inflight sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentRequests)
+
+	respond := func(f frame) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		err := stream.SetWriteDeadline(time.Now().Add(timeout))
+		if err == nil {
+			err = writeFrame(stream, f)
+		}
+		if err != nil {
+			log.Error().Err(err).Uint64("request_id", f.id).Msg("failed to write response")
+		}
+	}
+
+	r := bufio.NewReader(stream)
+	for {
+		f, err := readFrame(r, maxSize)
+		if err != nil {
+			inflight.Wait()
+			if err == io.EOF {
+				err = stream.Close()
+				if err != nil {
+					log.Error().Err(err).Msg("failed to close request stream")
+				}
+				return
+			}
+			if ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to read request")
+			}
+			_ = stream.Reset()
+			return
+		}
+		if f.kind != requestFrame {
+			log.Error().Uint64("request_id", f.id).Msg("received unexpected frame on request stream")
+			_ = stream.Reset()
+			inflight.Wait()
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			_ = stream.Reset()
+			inflight.Wait()
+			return
+		}
+
+		inflight.Add(1)
+		go func(f frame) {
+			defer inflight.Done()
+			defer func() { <-slots }()
+
+			payload, err := handle(f.payload)
+			if err != nil {
+				respond(frame{id: f.id, kind: errorFrame, payload: []byte(err.Error())})
+				return
+			}
+			respond(frame{id: f.id, kind: responseFrame, payload: payload})
+		}(f)
+	}
+}
@@ -0,0 +1,139 @@
+package p2p
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// pipeStream is a libp2p stream backed by one end of an in-memory pipe.
+type pipeStream struct {
+	libp2pnetwork.Stream
+	pipe net.Conn
+}
+
+func (s *pipeStream) Read(p []byte) (int, error)  { return s.pipe.Read(p) }
+func (s *pipeStream) Write(p []byte) (int, error) { return s.pipe.Write(p) }
+func (s *pipeStream) Close() error                { return s.pipe.Close() }
+func (s *pipeStream) Reset() error                { return s.pipe.Close() }
+func (s *pipeStream) SetWriteDeadline(t time.Time) error {
+	return s.pipe.SetWriteDeadline(t)
+}
+
+// newRequestStreamPair returns a request stream whose requests are served by the given handler.
+func newRequestStreamPair(t *testing.T, handle requestHandlerFunc) *requestStream {
+	client, server := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		serveRequestStream(ctx, &pipeStream{pipe: server}, zerolog.Nop(), DefaultMaxUnicastMsgSize, time.Second, handle)
+	}()
+
+	rs := newRequestStream(&pipeStream{pipe: client}, zerolog.Nop(), func(*requestStream) {})
+	go rs.readLoop(DefaultMaxUnicastMsgSize)
+
+	t.Cleanup(func() {
+		cancel()
+		rs.fail(fmt.Errorf("test done"))
+		unittest.RequireCloseBefore(t, served, time.Second, "request stream should stop being served")
+	})
+
+	return rs
+}
+
+func TestFrame(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeFrame(&buf, frame{id: 300, kind: responseFrame, payload: []byte("response")}))
+	require.NoError(t, writeFrame(&buf, frame{id: 1, kind: requestFrame}))
+
+	r := bufio.NewReader(&buf)
+	f, err := readFrame(r, 100)
+	require.NoError(t, err)
+	assert.Equal(t, frame{id: 300, kind: responseFrame, payload: []byte("response")}, f)
+
+	f, err = readFrame(r, 100)
+	require.NoError(t, err)
+	assert.Equal(t, frame{id: 1, kind: requestFrame, payload: []byte{}}, f)
+
+	// payloads larger than the max size are rejected
+	require.NoError(t, writeFrame(&buf, frame{id: 2, kind: requestFrame, payload: make([]byte, 101)}))
+	_, err = readFrame(r, 100)
+	assert.Error(t, err)
+}
+
+// TestRequestStream_Multiplexing tests that the responses of concurrent requests sent on the same
+// stream are correlated with their requests, regardless of the order in which they are handled.
+func TestRequestStream_Multiplexing(t *testing.T) {
+	const requests = 10
+
+	// the requests are only answered once all of them are received, in reverse order
+	var received sync.WaitGroup
+	received.Add(requests)
+	rs := newRequestStreamPair(t, func(request []byte) ([]byte, error) {
+		received.Done()
+		received.Wait()
+		time.Sleep(time.Duration(requests-int(request[0])) * time.Millisecond)
+		return append([]byte("response-"), request...), nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i byte) {
+			defer wg.Done()
+			response, err := rs.roundTrip(context.Background(), []byte{i})
+			require.NoError(t, err)
+			assert.Equal(t, append([]byte("response-"), i), response)
+		}(byte(i))
+	}
+	unittest.RequireReturnsBefore(t, wg.Wait, 5*time.Second, "requests should be answered")
+}
+
+// TestRequestStream_Errors tests that the errors of the handler are returned to the requester, and
+// that a request fails once its context expires without affecting the other requests of the stream.
+func TestRequestStream_Errors(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	rs := newRequestStreamPair(t, func(request []byte) ([]byte, error) {
+		switch string(request) {
+		case "fail":
+			return nil, fmt.Errorf("handler failed")
+		case "block":
+			<-block
+		}
+		return request, nil
+	})
+
+	_, err := rs.roundTrip(context.Background(), []byte("fail"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "handler failed")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = rs.roundTrip(ctx, []byte("block"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	response, err := rs.roundTrip(context.Background(), []byte("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), response)
+
+	// the pending requests fail once the stream fails
+	rs.fail(fmt.Errorf("stream failed"))
+	_, err = rs.roundTrip(context.Background(), []byte("ok"))
+	assert.ErrorIs(t, err, errRequestStreamClosed)
+}
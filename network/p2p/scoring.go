@@ -0,0 +1,244 @@
+package p2p
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	flownet "github.com/onflow/flow-go/network"
+	validator "github.com/onflow/flow-go/network/validator/pubsub"
+)
+
+const (
+	// DefaultPenaltyHalfLife is the time after which half of the penalties applied to a peer are forgiven.
+	DefaultPenaltyHalfLife = 10 * time.Minute
+
+	// scoreInspectInterval is the interval at which the peer scores are reported to the metrics
+	scoreInspectInterval = 10 * time.Second
+
+	// penalties below this value are forgotten
+	penaltyDecayToZero = 0.01
+)
+
+// rejectionPenalties are the penalties applied to the application specific score of a peer for
+// each of its messages rejected by the topic validators.
+// Messages from unstaked nodes or from roles not involved in a channel cannot be sent by an honest
+// node, while undecodable messages and missing envelopes may be caused by a software version or
+// configuration mismatch.
+var rejectionPenalties = map[validator.Rejection]float64{
+	validator.RejectionUnstakedSender:  -100,
+	validator.RejectionWrongRole:       -100,
+	validator.RejectionInvalidSigner:   -10,
+	validator.RejectionUndecodable:     -10,
+	validator.RejectionInvalidEnvelope: -10,
+}
+
+// DefaultPeerScoreThresholds returns the score thresholds of the GossipSub router. A single message
+// rejected for an unstaked sender or a wrong role is enough to get a peer pruned from the meshes
+// (negative score) and to stop gossiping to it, a few of them get it graylisted.
+func DefaultPeerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:             -99,
+		PublishThreshold:            -199,
+		GraylistThreshold:           -499,
+		AcceptPXThreshold:           100,
+		OpportunisticGraftThreshold: 1,
+	}
+}
+
+// PeerScoring enables the GossipSub peer scoring, with topic parameters derived from the roles
+// involved in each channel, and application specific penalties for the peers which sent messages
+// rejected by the topic validators.
+type PeerScoring struct {
+	log        zerolog.Logger
+	metrics    module.NetworkMetrics
+	thresholds *pubsub.PeerScoreThresholds
+	penalties  *peerPenalties
+}
+
+// NewPeerScoring returns a new peer scoring with the default thresholds and penalty half-life.
+func NewPeerScoring(log zerolog.Logger, metrics module.NetworkMetrics) *PeerScoring {
+	return &PeerScoring{
+		log:        log.With().Str("component", "peer_scoring").Logger(),
+		metrics:    metrics,
+		thresholds: DefaultPeerScoreThresholds(),
+		penalties:  newPeerPenalties(DefaultPenaltyHalfLife),
+	}
+}
+
+// PubsubOptions returns the pubsub options enabling the peer scoring, and the reporting of the scores to the metrics.
+func (s *PeerScoring) PubsubOptions() []PubsubOption {
+	return []PubsubOption{
+		func(_ context.Context, _ host.Host) (pubsub.Option, error) {
+			return pubsub.WithPeerScore(s.peerScoreParams(), s.thresholds), nil
+		},
+		// must come after the peer score option
+		func(_ context.Context, _ host.Host) (pubsub.Option, error) {
+			return pubsub.WithPeerScoreInspect(pubsub.PeerScoreInspectFn(s.inspect), scoreInspectInterval), nil
+		},
+	}
+}
+
+// Penalize lowers the application specific score of the peer held responsible for a message rejected
+// by the topic validators. It implements validator.RejectionConsumer.
+func (s *PeerScoring) Penalize(pid peer.ID, rejection validator.Rejection) {
+	penalty, ok := rejectionPenalties[rejection]
+	if !ok {
+		s.log.Error().Str("rejection", string(rejection)).Msg("no penalty for rejection")
+		return
+	}
+
+	score := s.penalties.add(pid, penalty)
+	s.metrics.PeerPenalized(string(rejection))
+
+	s.log.Debug().
+		Str("peer_id", pid.Pretty()).
+		Str("rejection", string(rejection)).
+		Float64("app_specific_score", score).
+		Msg("peer penalized for rejected message")
+}
+
+// AppSpecificScore returns the application specific score of the peer, which is the sum of its
+// penalties decayed over time.
+func (s *PeerScoring) AppSpecificScore(pid peer.ID) float64 {
+	return s.penalties.score(pid)
+}
+
+// TopicScoreParams returns the score parameters of the topic of the given channel.
+// Channels restricted to a few roles only carry messages sent by staked nodes of these roles, so
+// invalid messages on them weigh more than on channels open to all roles, or to unstaked nodes.
+func (s *PeerScoring) TopicScoreParams(channel flownet.Channel) *pubsub.TopicScoreParams {
+	weight := 1.0
+	if engine.PublicChannels().Contains(channel) {
+		weight = 0.1
+	} else if roles, ok := engine.RolesByChannel(channel); ok && len(roles) == len(flow.Roles()) {
+		weight = 0.5
+	}
+
+	return &pubsub.TopicScoreParams{
+		TopicWeight: weight,
+
+		// P1: small bonus for the time spent in the mesh, up to 1 point per hour
+		TimeInMeshWeight:  1.0 / 3600,
+		TimeInMeshQuantum: time.Second,
+		TimeInMeshCap:     3600,
+
+		// P2: bonus for delivering new messages first
+		FirstMessageDeliveriesWeight: 1,
+		FirstMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(10 * time.Minute),
+		FirstMessageDeliveriesCap:    10,
+
+		// P3: the traffic of most channels is too irregular to expect a delivery rate from mesh peers
+		MeshMessageDeliveriesWeight: 0,
+
+		// P4: invalid messages are the square of their count, forgiven within an hour
+		InvalidMessageDeliveriesWeight: -100,
+		InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+	}
+}
+
+func (s *PeerScoring) peerScoreParams() *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		// topic parameters are set when joining the topics, see TopicScoreParams
+		Topics: make(map[string]*pubsub.TopicScoreParams),
+		// positive topic contributions cannot compensate a single penalty
+		TopicScoreCap: 50,
+
+		AppSpecificScore:  s.AppSpecificScore,
+		AppSpecificWeight: 1,
+
+		// nodes may legitimately run behind the same IP, e.g. in local networks
+		IPColocationFactorWeight: 0,
+
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(10 * time.Minute),
+
+		DecayInterval: pubsub.DefaultDecayInterval,
+		DecayToZero:   pubsub.DefaultDecayToZero,
+		RetainScore:   time.Hour,
+	}
+}
+
+// inspect reports the scores of the peers to the metrics.
+func (s *PeerScoring) inspect(scores map[peer.ID]float64) {
+	graylisted := uint(0)
+	for pid, score := range scores {
+		s.metrics.PeerScore(score)
+		if score < s.thresholds.GraylistThreshold {
+			graylisted++
+			s.log.Debug().Str("peer_id", pid.Pretty()).Float64("score", score).Msg("peer is graylisted")
+		}
+	}
+	s.metrics.GraylistedPeers(graylisted)
+}
+
+// peerPenalties keeps the penalties of peers, which decay exponentially over time.
+type peerPenalties struct {
+	sync.Mutex
+	halfLife  time.Duration
+	penalties map[peer.ID]*penalty
+	now       func() time.Time
+}
+
+type penalty struct {
+	value   float64
+	updated time.Time
+}
+
+func newPeerPenalties(halfLife time.Duration) *peerPenalties {
+	return &peerPenalties{
+		halfLife:  halfLife,
+		penalties: make(map[peer.ID]*penalty),
+		now:       time.Now,
+	}
+}
+
+// add adds the penalty to the peer, and returns its new score.
+func (p *peerPenalties) add(pid peer.ID, value float64) float64 {
+	p.Lock()
+	defer p.Unlock()
+
+	current := p.decayed(pid)
+	p.penalties[pid] = &penalty{
+		value:   current + value,
+		updated: p.now(),
+	}
+
+	return current + value
+}
+
+// score returns the sum of the decayed penalties of the peer.
+func (p *peerPenalties) score(pid peer.ID) float64 {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.decayed(pid)
+}
+
+// decayed returns the decayed penalty of the peer, forgetting the penalties decayed to zero.
+// Must be called with the lock held.
+func (p *peerPenalties) decayed(pid peer.ID) float64 {
+	current, ok := p.penalties[pid]
+	if !ok {
+		return 0
+	}
+
+	elapsed := p.now().Sub(current.updated)
+	value := current.value * math.Pow(0.5, float64(elapsed)/float64(p.halfLife))
+	if math.Abs(value) < penaltyDecayToZero {
+		delete(p.penalties, pid)
+		return 0
+	}
+
+	return value
+}
@@ -0,0 +1,156 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/message"
+	validator "github.com/onflow/flow-go/network/validator/pubsub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPeerPenalties(t *testing.T) {
+	pid := peer.ID("peer")
+	now := time.Now()
+
+	penalties := newPeerPenalties(time.Minute)
+	penalties.now = func() time.Time { return now }
+
+	assert.Equal(t, 0.0, penalties.score(pid))
+	assert.Equal(t, -100.0, penalties.add(pid, -100))
+	assert.Equal(t, -110.0, penalties.add(pid, -10))
+
+	// half of the penalties are forgiven after the half-life
+	now = now.Add(time.Minute)
+	assert.InDelta(t, -55.0, penalties.score(pid), 0.001)
+
+	// and eventually all of them
+	now = now.Add(time.Hour)
+	assert.Equal(t, 0.0, penalties.score(pid))
+	assert.Empty(t, penalties.penalties)
+}
+
+func TestTopicScoreParams(t *testing.T) {
+	scoring := NewPeerScoring(zerolog.Nop(), metrics.NewNoopCollector())
+
+	restricted := scoring.TopicScoreParams(engine.PushApprovals)
+	allRoles := scoring.TopicScoreParams(engine.PushBlocks)
+	public := scoring.TopicScoreParams(engine.PublicSyncCommittee)
+
+	assert.Greater(t, restricted.TopicWeight, allRoles.TopicWeight)
+	assert.Greater(t, allRoles.TopicWeight, public.TopicWeight)
+}
+
+// TestPeerScoring tests that an unstaked node publishing on a channel of the staked nodes is penalized,
+// pruned from the mesh and eventually graylisted.
+func TestPeerScoring(t *testing.T) {
+	topic := engine.TopicFromChannel(engine.SyncCommittee, rootBlockID)
+
+	identity1, privateKey1 := unittest.IdentityWithNetworkingKeyFixture(unittest.WithRole(flow.RoleAccess))
+
+	tracer := &pruneTracer{}
+	scoring := NewPeerScoring(zerolog.Nop(), metrics.NewNoopCollector())
+	node1, err := NewDefaultLibP2PNodeBuilder(identity1.NodeID, "0.0.0.0:0", privateKey1).
+		SetRootBlockID(rootBlockID).
+		SetPubsubOptions(DefaultPubsubOptions(DefaultMaxPubSubMsgSize)...).
+		SetEventTracers(tracer).
+		SetPeerScoring(scoring).
+		Build(context.TODO())
+	require.NoError(t, err)
+
+	unstakedKey, err := unittest.NetworkingKey()
+	require.NoError(t, err)
+	unstakedNode := createNode(t, flow.ZeroID, unstakedKey, rootBlockID)
+	unstakedID := unstakedNode.host.ID()
+
+	require.NoError(t, unstakedNode.AddPeer(context.TODO(), *host.InfoFromHost(node1.host)))
+
+	stakedValidator := validator.StakedValidator(func(pid peer.ID) (*flow.Identity, bool) {
+		if pid == node1.host.ID() {
+			return identity1, true
+		}
+		return nil, false
+	}, node1.RejectionConsumer())
+
+	_, err = node1.Subscribe(context.TODO(), topic, stakedValidator)
+	require.NoError(t, err)
+	_, err = unstakedNode.Subscribe(context.TODO(), topic)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(node1.pubSub.ListPeers(topic.String())) > 0 &&
+			len(unstakedNode.pubSub.ListPeers(topic.String())) > 0
+	}, 3*time.Second, 100*time.Millisecond)
+
+	// the unstaked node joins the mesh of node1 before it is penalized
+	require.Eventually(t, func() bool {
+		return inMesh(node1, unstakedID, topic.String())
+	}, 3*time.Second, 100*time.Millisecond)
+
+	data, err := (&message.Message{Payload: []byte("hello")}).Marshal()
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, unstakedNode.Publish(context.TODO(), topic, data))
+	}
+
+	// every rejected message lowers the score of the unstaked node, until it is graylisted
+	require.Eventually(t, func() bool {
+		return scoring.AppSpecificScore(unstakedID) < DefaultPeerScoreThresholds().GraylistThreshold
+	}, 3*time.Second, 100*time.Millisecond)
+
+	// the unstaked node is pruned from the mesh of node1 on the next heartbeat, the events being traced by
+	// both the given tracer and the mesh tracer of the node
+	require.Eventually(t, func() bool {
+		return tracer.pruned(unstakedID, topic.String())
+	}, 3*time.Second, 100*time.Millisecond)
+	assert.False(t, inMesh(node1, unstakedID, topic.String()))
+}
+
+// pruneTracer records the peers pruned from the meshes.
+type pruneTracer struct {
+	sync.Mutex
+	prunes []*pb.TraceEvent_Prune
+}
+
+func (p *pruneTracer) Trace(evt *pb.TraceEvent) {
+	if evt.GetType() != pb.TraceEvent_PRUNE {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.prunes = append(p.prunes, evt.GetPrune())
+}
+
+func (p *pruneTracer) pruned(pid peer.ID, topic string) bool {
+	p.Lock()
+	defer p.Unlock()
+	for _, prune := range p.prunes {
+		if peer.ID(prune.GetPeerID()) == pid && prune.GetTopic() == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// inMesh returns true if the peer is part of the mesh of the topic of the node.
+func inMesh(node *Node, pid peer.ID, topic string) bool {
+	for _, meshPeer := range node.MeshPeers()[topic] {
+		if meshPeer == pid {
+			return true
+		}
+	}
+	return false
+}
@@ -39,7 +39,7 @@ func TestTopicValidator(t *testing.T) {
 			return &flow.Identity{}, false
 		}
 		return ids.ByNodeID(fid)
-	})
+	}, nil)
 
 	unstakedKey, err := unittest.NetworkingKey()
 	require.NoError(t, err)
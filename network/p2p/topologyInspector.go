@@ -0,0 +1,309 @@
+package p2p
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// DefaultTopologyCheckInterval is the interval at which the connectivity of the node is checked.
+const DefaultTopologyCheckInterval = time.Minute
+
+// unknownRole is the role reported for the peers which are not part of the identity table.
+const unknownRole = "unknown"
+
+// requiredRoles are the roles a node of a given role must be connected to for its protocols to make progress,
+// e.g. a collection node cannot submit its guarantees without consensus nodes.
+var requiredRoles = map[flow.Role]flow.RoleList{
+	flow.RoleCollection:   {flow.RoleCollection, flow.RoleConsensus},
+	flow.RoleConsensus:    {flow.RoleConsensus, flow.RoleCollection, flow.RoleExecution, flow.RoleVerification},
+	flow.RoleExecution:    {flow.RoleConsensus, flow.RoleCollection},
+	flow.RoleVerification: {flow.RoleConsensus, flow.RoleExecution},
+	flow.RoleAccess:       {flow.RoleConsensus, flow.RoleCollection, flow.RoleExecution},
+}
+
+// PeerInfo identifies a peer of the node.
+type PeerInfo struct {
+	PeerID string `json:"peer_id"`
+	NodeID string `json:"node_id,omitempty"` // empty if the peer is not part of the identity table
+	Role   string `json:"role"`
+}
+
+// FanoutPeer is a node of the fanout computed by the topology.
+type FanoutPeer struct {
+	PeerInfo
+	Connected bool `json:"connected"`
+}
+
+// ConnectionInfo is a connection of the node with a peer.
+type ConnectionInfo struct {
+	PeerInfo
+	Direction string    `json:"direction"`
+	Address   string    `json:"address"`
+	Opened    time.Time `json:"opened"`
+	Latency   string    `json:"latency,omitempty"` // moving average of the ping round trips, empty if the peer was never pinged
+}
+
+// RoleConnectivity summarizes the connectivity of the node with the nodes of a role.
+type RoleConnectivity struct {
+	Role      string `json:"role"`
+	Required  bool   `json:"required"`
+	Nodes     int    `json:"nodes"`     // nodes of the role in the identity table, other than this node
+	Connected int    `json:"connected"` // nodes of the role this node is connected to
+}
+
+// TopologyReport is a snapshot of the topology of the node, and of its connectivity with the other nodes.
+type TopologyReport struct {
+	Fanout      []FanoutPeer          `json:"fanout"`
+	Connections []ConnectionInfo      `json:"connections"`
+	Meshes      map[string][]PeerInfo `json:"meshes"`
+	Roles       []RoleConnectivity    `json:"roles"`
+	Warnings    []string              `json:"warnings"`
+}
+
+// TopologyInspector reports the fanout, the connections and the GossipSub meshes of the node, and periodically
+// checks that the node is connected to the nodes of the roles it requires. The outcome of the checks is
+// reported to the metrics, and logged as warnings.
+type TopologyInspector struct {
+	unit     *engine.Unit
+	log      zerolog.Logger
+	me       flow.Identifier
+	role     flow.Role
+	net      *Network
+	mw       *Middleware
+	metrics  module.NetworkMetrics
+	interval time.Duration
+	topics   map[string]struct{} // topics reported to the metrics by the last check
+	started  *atomic.Bool        // set once the middleware is started, as reports are requested concurrently
+}
+
+// NewTopologyInspector returns a new inspector of the topology of the given network, running its
+// connectivity check at the given interval.
+func NewTopologyInspector(
+	log zerolog.Logger,
+	me flow.Identifier,
+	role flow.Role,
+	net *Network,
+	mw *Middleware,
+	metrics module.NetworkMetrics,
+	interval time.Duration,
+) *TopologyInspector {
+	return &TopologyInspector{
+		unit:     engine.NewUnit(),
+		log:      log.With().Str("component", "topology_inspector").Logger(),
+		me:       me,
+		role:     role,
+		net:      net,
+		mw:       mw,
+		metrics:  metrics,
+		interval: interval,
+		topics:   make(map[string]struct{}),
+		started:  atomic.NewBool(false),
+	}
+}
+
+// Ready starts the periodic connectivity check. It must be called once the middleware is started.
+func (ti *TopologyInspector) Ready() <-chan struct{} {
+	ti.started.Store(true)
+	ti.unit.LaunchPeriodically(ti.check, ti.interval, ti.interval)
+	return ti.unit.Ready()
+}
+
+// Done stops the periodic connectivity check.
+func (ti *TopologyInspector) Done() <-chan struct{} {
+	return ti.unit.Done()
+}
+
+// Report returns the current topology of the node.
+// It can be called concurrently with the startup of the node, e.g. by the admin server.
+func (ti *TopologyInspector) Report() (*TopologyReport, error) {
+	// the libp2p node of the middleware is set when the middleware starts, before the inspector
+	if !ti.started.Load() {
+		return nil, fmt.Errorf("topology inspector is not started")
+	}
+	node := ti.mw.libP2PNode
+
+	fanout, err := ti.net.Topology()
+	if err != nil {
+		return nil, fmt.Errorf("could not compute fanout: %w", err)
+	}
+
+	return buildTopologyReport(ti.me, ti.role, ti.net.Identities(), fanout, node.host.Network().Conns(),
+		node.MeshPeers(), node.host.Peerstore().LatencyEWMA, ti.mw.idTranslator), nil
+}
+
+// check reports the topology of the node to the metrics, and logs its connectivity warnings.
+func (ti *TopologyInspector) check() {
+	report, err := ti.Report()
+	if err != nil {
+		ti.log.Error().Err(err).Msg("could not inspect topology")
+		return
+	}
+
+	ti.metrics.FanoutSize(uint(len(report.Fanout)))
+
+	roles := []string{unknownRole}
+	for _, role := range flow.Roles() {
+		roles = append(roles, role.String())
+	}
+	counts := make(map[string]map[string]uint, len(roles))
+	for _, role := range roles {
+		counts[role] = map[string]uint{
+			directionName(libp2pnetwork.DirInbound):  0,
+			directionName(libp2pnetwork.DirOutbound): 0,
+		}
+	}
+	for _, conn := range report.Connections {
+		if _, ok := counts[conn.Role][conn.Direction]; ok {
+			counts[conn.Role][conn.Direction]++
+		}
+	}
+	for role, directions := range counts {
+		for direction, count := range directions {
+			ti.metrics.RoleConnections(role, direction, count)
+		}
+	}
+
+	// topics the node left are reported with an empty mesh
+	topics := make(map[string]struct{}, len(report.Meshes))
+	for topic, peers := range report.Meshes {
+		ti.metrics.MeshPeers(topic, uint(len(peers)))
+		topics[topic] = struct{}{}
+	}
+	for topic := range ti.topics {
+		if _, ok := topics[topic]; !ok {
+			ti.metrics.MeshPeers(topic, 0)
+		}
+	}
+	ti.topics = topics
+
+	for _, connectivity := range report.Roles {
+		if connectivity.Required {
+			ti.metrics.RequiredRoleUnreachable(connectivity.Role, connectivity.Nodes > 0 && connectivity.Connected == 0)
+		}
+	}
+
+	for _, warning := range report.Warnings {
+		ti.log.Warn().Msg(warning)
+	}
+}
+
+// buildTopologyReport builds the topology report of the node from the state of its libp2p host.
+func buildTopologyReport(
+	me flow.Identifier,
+	role flow.Role,
+	identities flow.IdentityList,
+	fanout flow.IdentityList,
+	conns []libp2pnetwork.Conn,
+	meshes map[string]peer.IDSlice,
+	latency func(peer.ID) time.Duration,
+	translator IDTranslator,
+) *TopologyReport {
+
+	report := &TopologyReport{
+		Meshes: make(map[string][]PeerInfo, len(meshes)),
+	}
+
+	byNodeID := make(map[flow.Identifier]*flow.Identity, len(identities))
+	for _, identity := range identities {
+		byNodeID[identity.NodeID] = identity
+	}
+
+	peerInfo := func(pid peer.ID) PeerInfo {
+		info := PeerInfo{PeerID: pid.Pretty(), Role: unknownRole}
+		nodeID, err := translator.GetFlowID(pid)
+		if err != nil {
+			return info
+		}
+		if identity, ok := byNodeID[nodeID]; ok {
+			info.NodeID = nodeID.String()
+			info.Role = identity.Role.String()
+		}
+		return info
+	}
+
+	connected := make(map[peer.ID]struct{}, len(conns))
+	for _, conn := range conns {
+		pid := conn.RemotePeer()
+		connected[pid] = struct{}{}
+
+		info := ConnectionInfo{
+			PeerInfo:  peerInfo(pid),
+			Direction: directionName(conn.Stat().Direction),
+			Address:   conn.RemoteMultiaddr().String(),
+			Opened:    conn.Stat().Opened,
+		}
+		if rtt := latency(pid); rtt > 0 {
+			info.Latency = rtt.String()
+		}
+		report.Connections = append(report.Connections, info)
+	}
+	sort.Slice(report.Connections, func(i, j int) bool {
+		return report.Connections[i].PeerID < report.Connections[j].PeerID
+	})
+
+	for _, identity := range fanout {
+		fanoutPeer := FanoutPeer{
+			PeerInfo: PeerInfo{NodeID: identity.NodeID.String(), Role: identity.Role.String()},
+		}
+		pid, err := translator.GetPeerID(identity.NodeID)
+		if err == nil {
+			fanoutPeer.PeerID = pid.Pretty()
+			_, fanoutPeer.Connected = connected[pid]
+		}
+		if !fanoutPeer.Connected {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("not connected to fanout node %s (%s)", identity.NodeID, identity.Role))
+		}
+		report.Fanout = append(report.Fanout, fanoutPeer)
+	}
+
+	for topic, peers := range meshes {
+		members := make([]PeerInfo, 0, len(peers))
+		for _, pid := range peers {
+			members = append(members, peerInfo(pid))
+		}
+		report.Meshes[topic] = members
+	}
+
+	required := make(map[flow.Role]bool)
+	for _, r := range requiredRoles[role] {
+		required[r] = true
+	}
+	for _, r := range flow.Roles() {
+		connectivity := RoleConnectivity{Role: r.String(), Required: required[r]}
+		for _, identity := range identities.Filter(func(identity *flow.Identity) bool {
+			return identity.Role == r && identity.NodeID != me
+		}) {
+			connectivity.Nodes++
+			pid, err := translator.GetPeerID(identity.NodeID)
+			if err != nil {
+				continue
+			}
+			if _, ok := connected[pid]; ok {
+				connectivity.Connected++
+			}
+		}
+		if connectivity.Required && connectivity.Nodes > 0 && connectivity.Connected == 0 {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("not connected to any of the %d %s nodes required by a %s node",
+				connectivity.Nodes, r, role))
+		}
+		report.Roles = append(report.Roles, connectivity)
+	}
+
+	return report
+}
+
+// directionName returns the name of the direction of a connection in the reports.
+func directionName(direction libp2pnetwork.Direction) string {
+	return strings.ToLower(direction.String())
+}
@@ -0,0 +1,108 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestMeshTracer tests that the mesh tracer follows the peers grafted to and pruned from the meshes.
+func TestMeshTracer(t *testing.T) {
+	tracer := NewMeshTracer()
+	pid1, pid2 := peer.ID("peer-1"), peer.ID("peer-2")
+
+	trace := func(evtType pubsub_pb.TraceEvent_Type, evt *pubsub_pb.TraceEvent) {
+		evt.Type = &evtType
+		tracer.Trace(evt)
+	}
+	graft := func(topic string, pid peer.ID) {
+		trace(pubsub_pb.TraceEvent_GRAFT, &pubsub_pb.TraceEvent{Graft: &pubsub_pb.TraceEvent_Graft{PeerID: []byte(pid), Topic: &topic}})
+	}
+	prune := func(topic string, pid peer.ID) {
+		trace(pubsub_pb.TraceEvent_PRUNE, &pubsub_pb.TraceEvent{Prune: &pubsub_pb.TraceEvent_Prune{PeerID: []byte(pid), Topic: &topic}})
+	}
+
+	graft("a", pid1)
+	graft("a", pid2)
+	graft("b", pid2)
+	assert.Equal(t, map[string]peer.IDSlice{"a": {pid1, pid2}, "b": {pid2}}, tracer.MeshPeers())
+
+	prune("a", pid1)
+	assert.Equal(t, map[string]peer.IDSlice{"a": {pid2}, "b": {pid2}}, tracer.MeshPeers())
+
+	// a disconnected peer leaves all the meshes
+	trace(pubsub_pb.TraceEvent_REMOVE_PEER, &pubsub_pb.TraceEvent{RemovePeer: &pubsub_pb.TraceEvent_RemovePeer{PeerID: []byte(pid2)}})
+	assert.Equal(t, map[string]peer.IDSlice{"a": {}, "b": {}}, tracer.MeshPeers())
+
+	// the mesh of a topic the node left is dropped
+	topic := "a"
+	trace(pubsub_pb.TraceEvent_LEAVE, &pubsub_pb.TraceEvent{Leave: &pubsub_pb.TraceEvent_Leave{Topic: &topic}})
+	assert.Equal(t, map[string]peer.IDSlice{"b": {}}, tracer.MeshPeers())
+}
+
+// TestTopologyReport tests the report of the fanout, the connections and the connectivity of a collection node
+// connected to a consensus node, but not to the other collection node.
+func TestTopologyReport(t *testing.T) {
+	logger := zerolog.Nop()
+
+	node1, identity1 := NodeFixture(t, logger, generateNetworkingKey(t), rootBlockID, nil, false, defaultAddress)
+	node2, identity2 := NodeFixture(t, logger, generateNetworkingKey(t), rootBlockID, nil, false, defaultAddress)
+	defer StopNodes(t, []*Node{node1, node2})
+
+	identity1.Role = flow.RoleCollection
+	identity2.Role = flow.RoleConsensus
+	identity3 := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection), unittest.WithNetworkingKey(generateNetworkingKey(t).PublicKey()))
+	identities := flow.IdentityList{&identity1, &identity2, identity3}
+
+	translator, err := NewFixedTableIdentityTranslator(identities)
+	require.NoError(t, err)
+
+	// node1 connects to node2 and measures its latency
+	pInfo, err := PeerAddressInfo(identity2)
+	require.NoError(t, err)
+	require.NoError(t, node1.AddPeer(context.Background(), pInfo))
+	_, _, err = node1.Ping(context.Background(), pInfo.ID)
+	require.NoError(t, err)
+
+	report := buildTopologyReport(identity1.NodeID, flow.RoleCollection, identities, flow.IdentityList{&identity2, identity3},
+		node1.host.Network().Conns(), node1.MeshPeers(), node1.host.Peerstore().LatencyEWMA, translator)
+
+	require.Len(t, report.Connections, 1)
+	connection := report.Connections[0]
+	assert.Equal(t, pInfo.ID.Pretty(), connection.PeerID)
+	assert.Equal(t, identity2.NodeID.String(), connection.NodeID)
+	assert.Equal(t, flow.RoleConsensus.String(), connection.Role)
+	assert.Equal(t, "outbound", connection.Direction)
+	assert.NotEmpty(t, connection.Latency)
+
+	require.Len(t, report.Fanout, 2)
+	assert.True(t, report.Fanout[0].Connected)
+	assert.False(t, report.Fanout[1].Connected)
+
+	connectivity := make(map[string]RoleConnectivity)
+	for _, role := range report.Roles {
+		connectivity[role.Role] = role
+	}
+	assert.Equal(t, RoleConnectivity{Role: "consensus", Required: true, Nodes: 1, Connected: 1}, connectivity["consensus"])
+	assert.Equal(t, RoleConnectivity{Role: "collection", Required: true, Nodes: 1, Connected: 0}, connectivity["collection"])
+	assert.Equal(t, RoleConnectivity{Role: "execution", Required: false, Nodes: 0, Connected: 0}, connectivity["execution"])
+
+	// the other collection node is both an unconnected fanout node and the only node of a required role
+	assert.Len(t, report.Warnings, 2)
+}
+
+// TestTopologyInspector_NotStarted tests that no report is built before the inspector is started, i.e. before the
+// libp2p node of the middleware exists.
+func TestTopologyInspector_NotStarted(t *testing.T) {
+	inspector := NewTopologyInspector(zerolog.Nop(), unittest.IdentifierFixture(), flow.RoleConsensus, nil, &Middleware{}, nil, DefaultTopologyCheckInterval)
+	_, err := inspector.Report()
+	assert.Error(t, err)
+}
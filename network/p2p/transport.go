@@ -0,0 +1,151 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/libp2p/go-libp2p"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	"github.com/libp2p/go-libp2p/config"
+	"github.com/libp2p/go-tcp-transport"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Transport is a libp2p transport on which a node listens and dials.
+type Transport string
+
+const (
+	TransportTCP  Transport = "tcp"
+	TransportQUIC Transport = "quic"
+)
+
+// DefaultTransports are the transports of the nodes which do not configure them.
+var DefaultTransports = []Transport{TransportTCP}
+
+// ParseTransports parses the names of the transports, e.g. as given on the command line.
+func ParseTransports(names []string) ([]Transport, error) {
+	transports := make([]Transport, 0, len(names))
+	seen := make(map[Transport]struct{}, len(names))
+	for _, name := range names {
+		transport := Transport(name)
+		switch transport {
+		case TransportTCP:
+		case TransportQUIC:
+			if !quicTransportSupported {
+				return nil, fmt.Errorf("transport %s is not supported by this build", name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown transport %s", name)
+		}
+		if _, ok := seen[transport]; ok {
+			continue
+		}
+		seen[transport] = struct{}{}
+		transports = append(transports, transport)
+	}
+	if len(transports) == 0 {
+		return nil, fmt.Errorf("at least one transport is required")
+	}
+	return transports, nil
+}
+
+// TransportMultiAddresses returns the multiaddresses of the node ip and port on each of the transports.
+// All the transports share the port of the node address, TCP and QUIC (UDP) ports being distinct.
+func TransportMultiAddresses(ip, port string, transports ...Transport) ([]multiaddr.Multiaddr, error) {
+	if len(transports) == 0 {
+		transports = DefaultTransports
+	}
+
+	addrs := make([]multiaddr.Multiaddr, 0, len(transports))
+	for _, transport := range transports {
+		var addr string
+		switch transport {
+		case TransportTCP:
+			addr = MultiAddressStr(ip, port)
+		case TransportQUIC:
+			addr = QUICMultiAddressStr(ip, port)
+		default:
+			return nil, fmt.Errorf("unknown transport %s", transport)
+		}
+
+		maddr, err := multiaddr.NewMultiaddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("could not create %s multiaddress: %w", transport, err)
+		}
+		addrs = append(addrs, maddr)
+	}
+	return addrs, nil
+}
+
+// transportOptions returns the libp2p options to listen and dial on the given transports.
+func transportOptions(ip, port string, transports ...Transport) ([]config.Option, error) {
+	if len(transports) == 0 {
+		transports = DefaultTransports
+	}
+
+	// when listening on a port assigned by the OS, pick a port free on both TCP and UDP so that the
+	// transports keep sharing the port of the node address
+	if port == "0" && len(transports) > 1 {
+		var err error
+		port, err = sharedFreePort(ip)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listenAddrs, err := TransportMultiAddresses(ip, port, transports...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate Flow address to Libp2p multiaddress: %w", err)
+	}
+
+	options := []config.Option{libp2p.ListenAddrs(listenAddrs...)}
+	for _, transport := range transports {
+		switch transport {
+		case TransportTCP:
+			// create a transport which disables port reuse and web socket.
+			// Port reuse enables listening and dialing from the same TCP port (https://github.com/libp2p/go-reuseport)
+			// While this sounds great, it intermittently causes a 'broken pipe' error
+			// as the 1-k discovery process and the 1-1 messaging both sometimes attempt to open connection to the same target
+			// As of now there is no requirement of client sockets to be a well-known port, so disabling port reuse all together.
+			options = append(options, libp2p.Transport(func(u *tptu.Upgrader) *tcp.TcpTransport {
+				tpt := tcp.NewTCPTransport(u)
+				tpt.DisableReuseport = true
+				return tpt
+			}))
+		case TransportQUIC:
+			// QUIC is secured and multiplexed by itself, hence its connections are not upgraded
+			option, err := quicTransportOption()
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, option)
+		}
+	}
+
+	return options, nil
+}
+
+// sharedFreePort returns a port which is currently free on both TCP and UDP for the given ip.
+func sharedFreePort(ip string) (string, error) {
+	const attempts = 10
+	for i := 0; i < attempts; i++ {
+		tcpListener, err := net.Listen("tcp4", net.JoinHostPort(ip, "0"))
+		if err != nil {
+			return "", fmt.Errorf("could not find free TCP port: %w", err)
+		}
+		_, port, err := net.SplitHostPort(tcpListener.Addr().String())
+		if err != nil {
+			_ = tcpListener.Close()
+			return "", fmt.Errorf("could not parse TCP listen address: %w", err)
+		}
+
+		udpConn, err := net.ListenPacket("udp4", net.JoinHostPort(ip, port))
+		_ = tcpListener.Close()
+		if err != nil {
+			continue // the port is taken on UDP, try another one
+		}
+		_ = udpConn.Close()
+		return port, nil
+	}
+	return "", fmt.Errorf("could not find a port free on both TCP and UDP after %d attempts", attempts)
+}
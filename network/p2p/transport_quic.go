@@ -0,0 +1,17 @@
+// +build !go1.18
+
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p"
+	libp2pquic "github.com/libp2p/go-libp2p-quic-transport"
+	"github.com/libp2p/go-libp2p/config"
+)
+
+// quicTransportSupported is true if the QUIC transport can be used by this build.
+const quicTransportSupported = true
+
+// quicTransportOption returns the libp2p option enabling the QUIC transport.
+func quicTransportOption() (config.Option, error) {
+	return libp2p.Transport(libp2pquic.NewTransport), nil
+}
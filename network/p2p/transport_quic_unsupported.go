@@ -0,0 +1,17 @@
+// +build go1.18
+
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/config"
+)
+
+// quicTransportSupported is true if the QUIC transport can be used by this build.
+// The QUIC implementation used by libp2p (quic-go v0.21) does not build with Go 1.18 and later.
+const quicTransportSupported = false
+
+func quicTransportOption() (config.Option, error) {
+	return nil, fmt.Errorf("QUIC transport is not supported by binaries built with Go 1.18 or later")
+}
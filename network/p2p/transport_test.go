@@ -0,0 +1,180 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestParseTransports(t *testing.T) {
+	transports, err := ParseTransports([]string{"tcp", "tcp"})
+	require.NoError(t, err)
+	assert.Equal(t, []Transport{TransportTCP}, transports)
+
+	_, err = ParseTransports(nil)
+	assert.Error(t, err)
+
+	_, err = ParseTransports([]string{"tcp", "udp"})
+	assert.Error(t, err)
+
+	transports, err = ParseTransports([]string{"quic", "tcp"})
+	if !quicTransportSupported {
+		assert.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	assert.Equal(t, []Transport{TransportQUIC, TransportTCP}, transports)
+}
+
+func TestTransportMultiAddresses(t *testing.T) {
+	addrs, err := TransportMultiAddresses("1.2.3.4", "3569", TransportTCP, TransportQUIC)
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/3569", addrs[0].String())
+	assert.Equal(t, "/ip4/1.2.3.4/udp/3569/quic", addrs[1].String())
+
+	addrs, err = TransportMultiAddresses("flow.com", "3569", TransportQUIC)
+	require.NoError(t, err)
+	assert.Equal(t, "/dns4/flow.com/udp/3569/quic", addrs[0].String())
+
+	// TCP is the default transport
+	addrs, err = TransportMultiAddresses("1.2.3.4", "3569")
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/3569", addrs[0].String())
+
+	// the port of QUIC multiaddresses is found
+	ip, port, err := IPPortFromMultiAddress(multiaddr.StringCast("/ip4/1.2.3.4/udp/3569/quic"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", ip)
+	assert.Equal(t, "3569", port)
+}
+
+// TestAddrInfoTranslators tests that the identity provider translator returns the multiaddresses of its
+// transports, and that the hierarchical translator delegates to the translators of addresses.
+func TestAddrInfoTranslators(t *testing.T) {
+	key := generateNetworkingKey(t)
+	identity := unittest.IdentityFixture(unittest.WithNetworkingKey(key.PublicKey()), unittest.WithAddress("1.2.3.4:3569"))
+	provider := id.NewFixedIdentityProvider(flow.IdentityList{identity})
+
+	// QUIC addresses come first whatever the order of the transports
+	translator := NewIdentityProviderIDTranslator(provider, TransportTCP, TransportQUIC)
+	info, err := translator.GetPeerAddrInfo(identity.NodeID)
+	require.NoError(t, err)
+	pid, err := translator.GetPeerID(identity.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, pid, info.ID)
+	require.Len(t, info.Addrs, 2)
+	assert.Equal(t, "/ip4/1.2.3.4/udp/3569/quic", info.Addrs[0].String())
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/3569", info.Addrs[1].String())
+
+	_, err = translator.GetPeerAddrInfo(unittest.IdentifierFixture())
+	assert.Error(t, err)
+
+	hierarchical := NewHierarchicalIDTranslator(NewUnstakedNetworkIDTranslator(), translator)
+	hierarchicalInfo, err := hierarchical.GetPeerAddrInfo(identity.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, info, hierarchicalInfo)
+
+	_, err = NewHierarchicalIDTranslator(NewUnstakedNetworkIDTranslator()).GetPeerAddrInfo(identity.NodeID)
+	assert.Error(t, err)
+}
+
+// TestPeerInfosFromIDs tests that the addresses of the identities are given by the translator when it translates
+// them, and are on the transports of the node otherwise.
+func TestPeerInfosFromIDs(t *testing.T) {
+	known := unittest.IdentityFixture(unittest.WithNetworkingKey(generateNetworkingKey(t).PublicKey()), unittest.WithAddress("1.2.3.4:3569"))
+	unknown := unittest.IdentityFixture(unittest.WithNetworkingKey(generateNetworkingKey(t).PublicKey()), unittest.WithAddress("5.6.7.8:3569"))
+	translator := NewIdentityProviderIDTranslator(id.NewFixedIdentityProvider(flow.IdentityList{known}), TransportQUIC)
+
+	infos, invalid := peerInfosFromIDs(flow.IdentityList{known, unknown}, translator, TransportTCP)
+	require.Empty(t, invalid)
+	require.Len(t, infos, 2)
+	require.Len(t, infos[0].Addrs, 1)
+	assert.Equal(t, "/ip4/1.2.3.4/udp/3569/quic", infos[0].Addrs[0].String())
+	require.Len(t, infos[1].Addrs, 1)
+	assert.Equal(t, "/ip4/5.6.7.8/tcp/3569", infos[1].Addrs[0].String())
+
+	// without address translation, all the identities are on the transports of the node
+	infos, invalid = peerInfosFromIDs(flow.IdentityList{known}, NewUnstakedNetworkIDTranslator(), TransportTCP, TransportQUIC)
+	require.Empty(t, invalid)
+	require.Len(t, infos, 1)
+	require.Len(t, infos[0].Addrs, 2)
+	assert.Equal(t, "/ip4/1.2.3.4/udp/3569/quic", infos[0].Addrs[0].String())
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/3569", infos[0].Addrs[1].String())
+}
+
+// TestMixedTransports tests that a node listening on both TCP and QUIC connects over QUIC to the nodes listening
+// on QUIC, and falls back to TCP for the nodes only listening on TCP.
+func TestMixedTransports(t *testing.T) {
+	if !quicTransportSupported {
+		t.Skip("QUIC transport is not supported by this build")
+	}
+
+	rootBlockID := unittest.IdentifierFixture()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mixed, mixedID := transportNodeFixture(t, rootBlockID, TransportTCP, TransportQUIC)
+	tcpOnly, tcpID := transportNodeFixture(t, rootBlockID, TransportTCP)
+	quicOnly, quicID := transportNodeFixture(t, rootBlockID, TransportQUIC)
+	defer StopNodes(t, []*Node{mixed, tcpOnly, quicOnly})
+
+	// the mixed node knows the addresses of the other nodes on both transports, as their identities do not tell
+	// which transports they listen on
+	for _, target := range []flow.Identity{tcpID, quicID} {
+		info, err := PeerAddressInfo(target, TransportTCP, TransportQUIC)
+		require.NoError(t, err)
+		require.NoError(t, mixed.AddPeer(ctx, info))
+	}
+	assertTransport(t, mixed, tcpID, "tcp")
+	assertTransport(t, mixed, quicID, "quic")
+
+	// the single transport nodes reach the mixed node on their transport
+	for _, source := range []*Node{tcpOnly, quicOnly} {
+		info, err := PeerAddressInfo(mixedID, TransportTCP, TransportQUIC)
+		require.NoError(t, err)
+		require.NoError(t, source.AddPeer(ctx, info))
+	}
+	assertTransport(t, tcpOnly, mixedID, "tcp")
+	assertTransport(t, quicOnly, mixedID, "quic")
+}
+
+// transportNodeFixture returns a node listening on the given transports on localhost, with its identity.
+func transportNodeFixture(t *testing.T, rootBlockID flow.Identifier, transports ...Transport) (*Node, flow.Identity) {
+	key := generateNetworkingKey(t)
+	identity := unittest.IdentityFixture(unittest.WithNetworkingKey(key.PublicKey()))
+
+	node, err := NewDefaultLibP2PNodeBuilder(identity.NodeID, "127.0.0.1:0", key).
+		SetRootBlockID(rootBlockID).
+		SetTransports(transports...).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	ip, port, err := node.GetIPPort()
+	require.NoError(t, err)
+	identity.Address = ip + ":" + port
+
+	return node, *identity
+}
+
+// assertTransport asserts that the node is connected to the target over the given transport.
+func assertTransport(t *testing.T, node *Node, target flow.Identity, transport string) {
+	info, err := PeerAddressInfo(target)
+	require.NoError(t, err)
+
+	conns := node.host.Network().ConnsToPeer(info.ID)
+	require.NotEmpty(t, conns)
+	for _, conn := range conns {
+		protocols := conn.RemoteMultiaddr().Protocols()
+		assert.Equal(t, transport, protocols[len(protocols)-1].Name, "unexpected transport to %s", target.Address)
+	}
+}
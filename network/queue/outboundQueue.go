@@ -0,0 +1,221 @@
+package queue
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// ErrOutboundQueueFull is returned when a message is dropped as the outbound queue of its destination is
+// full of messages with a higher or equal priority.
+var ErrOutboundQueueFull = errors.New("outbound queue is full")
+
+// ErrOutboundQueueClosed is returned when a message is dropped as the outbound queue of its destination is
+// closed, e.g. because the destination left the network.
+var ErrOutboundQueueClosed = errors.New("outbound queue is closed")
+
+// OutboundMessage is a message waiting to be sent to a destination.
+type OutboundMessage struct {
+	QMessage              // the message, whose priority is derived like the one of the inbound messages
+	Send     func() error // sends the message to the destination
+	Result   chan<- error // receives the result of the delivery once the message is sent or dropped, may be nil
+}
+
+// Done reports the result of the delivery of the message, i.e. the error returned by Send or the reason
+// the message was dropped. It must be called once per message, and never blocks as long as the result
+// channel is buffered.
+func (m OutboundMessage) Done(err error) {
+	if m.Result != nil {
+		m.Result <- err
+	}
+}
+
+// OutboundQueue buffers the messages sent to a destination, up to a size in bytes. The messages are removed
+// by priority, and in insertion order for the same priority. When the queue is full, the messages with the
+// lowest priority are dropped first, so that a flood of low priority messages never delays the high priority
+// ones.
+type OutboundQueue struct {
+	cond       *sync.Cond
+	ctx        context.Context
+	closed     bool
+	maxSize    int
+	size       int                     // the total size in bytes of the buffered messages
+	buckets    map[Priority]*list.List // the buffered messages of each priority, in insertion order
+	sizes      map[Priority]int        // the total size in bytes of the buffered messages of each priority
+	priorities []Priority              // the priorities of the buckets, in decreasing order
+	metrics    module.NetworkMetrics
+}
+
+// NewOutboundQueue returns an outbound queue buffering up to maxSize bytes of messages. The blocked
+// readers of the queue are released once the context is done.
+func NewOutboundQueue(ctx context.Context, maxSize int, nm module.NetworkMetrics) *OutboundQueue {
+	q := &OutboundQueue{
+		cond:    sync.NewCond(&sync.Mutex{}),
+		ctx:     ctx,
+		maxSize: maxSize,
+		buckets: make(map[Priority]*list.List),
+		sizes:   make(map[Priority]int),
+		metrics: nm,
+	}
+
+	// kick off a go routine to unblock queue readers on shutdown
+	go func() {
+		<-ctx.Done()
+		q.cond.L.Lock()
+		q.cond.Broadcast()
+		q.cond.L.Unlock()
+	}()
+
+	return q
+}
+
+// Insert buffers the message, dropping messages of a lower priority if the queue is full. It returns
+// ErrOutboundQueueFull if the message itself is dropped, i.e. if there is not enough room for it once
+// all the messages of a lower priority are dropped, and ErrOutboundQueueClosed if the queue is closed.
+// The result of the delivery of a message is only reported once it is buffered, i.e. if no error is
+// returned.
+func (q *OutboundQueue) Insert(message OutboundMessage) error {
+	if err := q.ctx.Err(); err != nil {
+		return err
+	}
+
+	priority, err := GetEventPriority(message.QMessage)
+	if err != nil {
+		return fmt.Errorf("failed to derive message priority: %w", err)
+	}
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.closed {
+		return ErrOutboundQueueClosed
+	}
+
+	// the message is dropped if there is not enough room for it once all the lower priority messages are dropped
+	available := q.maxSize - q.size
+	for p, size := range q.sizes {
+		if p < priority {
+			available += size
+		}
+	}
+	if message.Size > available {
+		q.metrics.OutboundMessageDropped(message.Target.String())
+		return ErrOutboundQueueFull
+	}
+
+	for q.size+message.Size > q.maxSize {
+		// drop the latest message of the lowest priority
+		dropped := q.pop(q.priorities[len(q.priorities)-1], false)
+		q.metrics.OutboundMessageDropped(dropped.Target.String())
+		dropped.Done(ErrOutboundQueueFull)
+	}
+
+	bucket, ok := q.buckets[priority]
+	if !ok {
+		bucket = list.New()
+		q.buckets[priority] = bucket
+		q.priorities = append(q.priorities, priority)
+		sort.Slice(q.priorities, func(i, j int) bool {
+			return q.priorities[i] > q.priorities[j]
+		})
+	}
+	bucket.PushBack(message)
+	q.size += message.Size
+	q.sizes[priority] += message.Size
+	q.metrics.OutboundMessageQueued(message.Target.String())
+
+	q.cond.Signal()
+
+	return nil
+}
+
+// Remove returns the oldest message of the highest priority, blocking until a message is available. It
+// returns false once the context of the queue is done or the queue is closed.
+func (q *OutboundQueue) Remove() (OutboundMessage, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for len(q.priorities) == 0 {
+		// if the context has been canceled or the queue closed, don't wait
+		if q.ctx.Err() != nil || q.closed {
+			return OutboundMessage{}, false
+		}
+		q.cond.Wait()
+	}
+	if q.ctx.Err() != nil || q.closed {
+		return OutboundMessage{}, false
+	}
+
+	return q.pop(q.priorities[0], true), true
+}
+
+// Close drops the buffered messages, reporting ErrOutboundQueueClosed as the result of their delivery, and
+// releases the blocked readers of the queue. The messages inserted afterwards are rejected.
+func (q *OutboundQueue) Close() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+
+	for len(q.priorities) > 0 {
+		dropped := q.pop(q.priorities[0], true)
+		q.metrics.OutboundMessageDropped(dropped.Target.String())
+		dropped.Done(ErrOutboundQueueClosed)
+	}
+	q.cond.Broadcast()
+}
+
+// Len returns the number of buffered messages.
+func (q *OutboundQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	length := 0
+	for _, bucket := range q.buckets {
+		length += bucket.Len()
+	}
+	return length
+}
+
+// Size returns the total size in bytes of the buffered messages.
+func (q *OutboundQueue) Size() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.size
+}
+
+// pop removes the oldest (front) or latest message of the priority bucket, which must not be empty.
+// It must be called with the lock held.
+func (q *OutboundQueue) pop(priority Priority, front bool) OutboundMessage {
+	bucket := q.buckets[priority]
+
+	element := bucket.Back()
+	if front {
+		element = bucket.Front()
+	}
+	message := bucket.Remove(element).(OutboundMessage)
+	q.size -= message.Size
+	q.sizes[priority] -= message.Size
+	q.metrics.OutboundMessageDequeued(message.Target.String())
+
+	if bucket.Len() == 0 {
+		delete(q.buckets, priority)
+		delete(q.sizes, priority)
+		for i, p := range q.priorities {
+			if p == priority {
+				q.priorities = append(q.priorities[:i], q.priorities[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return message
+}
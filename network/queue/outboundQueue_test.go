@@ -0,0 +1,157 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/queue"
+)
+
+const (
+	syncChannel      = network.Channel("sync")
+	consensusChannel = network.Channel("consensus")
+)
+
+// outboundMessage returns an outbound message of the given size, whose payload identifies it.
+func outboundMessage(channel network.Channel, payload interface{}, size int) queue.OutboundMessage {
+	return queue.OutboundMessage{
+		QMessage: queue.QMessage{
+			Payload: payload,
+			Size:    size,
+			Target:  channel,
+		},
+		Send: func() error { return nil },
+	}
+}
+
+// TestOutboundQueue_DropLowestPriorityFirst tests that a full outbound queue drops its latest lowest priority
+// messages to make room for the higher priority ones, which are removed first.
+func TestOutboundQueue_DropLowestPriorityFirst(t *testing.T) {
+	nm := &mockmodule.NetworkMetrics{}
+	nm.On("OutboundMessageQueued", mock.Anything)
+	nm.On("OutboundMessageDequeued", mock.Anything)
+	nm.On("OutboundMessageDropped", syncChannel.String()).Once()
+
+	q := queue.NewOutboundQueue(context.Background(), 300, nm)
+
+	responses := make([]*messages.SyncResponse, 3)
+	for i := range responses {
+		responses[i] = &messages.SyncResponse{Height: uint64(i)}
+		require.NoError(t, q.Insert(outboundMessage(syncChannel, responses[i], 100)))
+	}
+	assert.Equal(t, 300, q.Size())
+
+	proposal := &messages.BlockProposal{}
+	require.NoError(t, q.Insert(outboundMessage(consensusChannel, proposal, 100)))
+	assert.Equal(t, 3, q.Len())
+
+	// the proposal is sent before the oldest responses, while the latest response was dropped
+	for _, expected := range []interface{}{proposal, responses[0], responses[1]} {
+		qm, ok := q.Remove()
+		require.True(t, ok)
+		assert.Same(t, expected, qm.Payload)
+	}
+	assert.Equal(t, 0, q.Size())
+
+	nm.AssertExpectations(t)
+}
+
+// TestOutboundQueue_Full tests that the messages are dropped when the queue is full of messages with a higher
+// or equal priority, or when they are larger than the queue.
+func TestOutboundQueue_Full(t *testing.T) {
+	nm := &mockmodule.NetworkMetrics{}
+	nm.On("OutboundMessageQueued", mock.Anything)
+	nm.On("OutboundMessageDropped", syncChannel.String()).Times(2)
+	nm.On("OutboundMessageDropped", consensusChannel.String()).Once()
+
+	q := queue.NewOutboundQueue(context.Background(), 200, nm)
+
+	require.NoError(t, q.Insert(outboundMessage(consensusChannel, &messages.BlockProposal{}, 100)))
+	require.NoError(t, q.Insert(outboundMessage(syncChannel, &messages.SyncResponse{}, 100)))
+
+	err := q.Insert(outboundMessage(syncChannel, &messages.SyncResponse{}, 100))
+	assert.ErrorIs(t, err, queue.ErrOutboundQueueFull)
+
+	err = q.Insert(outboundMessage(syncChannel, &messages.SyncResponse{}, 300))
+	assert.ErrorIs(t, err, queue.ErrOutboundQueueFull)
+
+	// dropping all the lower priority messages does not make enough room for this proposal, hence none is dropped
+	err = q.Insert(outboundMessage(consensusChannel, &messages.BlockProposal{}, 200))
+	assert.ErrorIs(t, err, queue.ErrOutboundQueueFull)
+
+	assert.Equal(t, 2, q.Len())
+
+	nm.AssertExpectations(t)
+}
+
+// TestOutboundQueue_Shutdown tests that the blocked readers of the queue are released once its context is done.
+func TestOutboundQueue_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := queue.NewOutboundQueue(ctx, 100, metrics.NewNoopCollector())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, ok := q.Remove()
+		assert.False(t, ok)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reader was not released on shutdown")
+	}
+
+	assert.Error(t, q.Insert(outboundMessage(syncChannel, &messages.SyncResponse{}, 10)))
+}
+
+// TestOutboundQueue_DroppedResult tests that the messages dropped to make room for higher priority ones report
+// ErrOutboundQueueFull as the result of their delivery.
+func TestOutboundQueue_DroppedResult(t *testing.T) {
+	q := queue.NewOutboundQueue(context.Background(), 100, metrics.NewNoopCollector())
+
+	result := make(chan error, 1)
+	response := outboundMessage(syncChannel, &messages.SyncResponse{}, 100)
+	response.Result = result
+	require.NoError(t, q.Insert(response))
+
+	require.NoError(t, q.Insert(outboundMessage(consensusChannel, &messages.BlockProposal{}, 100)))
+
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, queue.ErrOutboundQueueFull)
+	default:
+		t.Fatal("dropped message did not report its result")
+	}
+}
+
+// TestOutboundQueue_Close tests that closing the queue drops its buffered messages, releases its blocked readers
+// and rejects the messages inserted afterwards.
+func TestOutboundQueue_Close(t *testing.T) {
+	q := queue.NewOutboundQueue(context.Background(), 100, metrics.NewNoopCollector())
+
+	result := make(chan error, 1)
+	response := outboundMessage(syncChannel, &messages.SyncResponse{}, 10)
+	response.Result = result
+	require.NoError(t, q.Insert(response))
+
+	q.Close()
+	assert.ErrorIs(t, <-result, queue.ErrOutboundQueueClosed)
+	assert.Equal(t, 0, q.Len())
+
+	_, ok := q.Remove()
+	assert.False(t, ok)
+
+	err := q.Insert(outboundMessage(syncChannel, &messages.SyncResponse{}, 10))
+	assert.ErrorIs(t, err, queue.ErrOutboundQueueClosed)
+}
@@ -1,7 +1,9 @@
 package stub
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/network"
@@ -15,6 +17,11 @@ type PendingMessage struct {
 	Event   interface{}
 	// The id of the receiver nodes
 	TargetIDs []flow.Identifier
+
+	// fields set by the fault model of the hub
+	deliverAt  time.Time // the message is not delivered before this time
+	heldBack   bool      // the message is delivered after the other messages due for delivery
+	duplicated bool      // the message is delivered twice, bypassing the deduplication of events
 }
 
 // Buffer buffers all the pending messages to be sent over the mock network from one node to a list of nodes
@@ -43,8 +50,8 @@ func (b *Buffer) Save(m *PendingMessage) {
 // message, it is permanently dropped.
 func (b *Buffer) DeliverRecursive(sendOne func(*PendingMessage)) {
 	for {
-		// get all pending messages, and clear the buffer
-		messages := b.takeAll()
+		// get all pending messages due for delivery, and remove them from the buffer
+		messages := b.takeDue()
 
 		// This check is necessary to exit the endless for loop
 		if len(messages) == 0 {
@@ -57,12 +64,12 @@ func (b *Buffer) DeliverRecursive(sendOne func(*PendingMessage)) {
 	}
 }
 
-// Deliver delivers all pending messages currently in the buffer using the
+// Deliver delivers all pending messages due for delivery in the buffer using the
 // provided sendOne method. If sendOne returns false, the message was not sent
 // and will remain in the buffer.
 func (b *Buffer) Deliver(sendOne func(*PendingMessage) bool) {
 
-	messages := b.takeAll()
+	messages := b.takeDue()
 	var unsent []*PendingMessage
 
 	for _, msg := range messages {
@@ -78,13 +85,40 @@ func (b *Buffer) Deliver(sendOne func(*PendingMessage) bool) {
 	b.Unlock()
 }
 
-// takeAll takes all pending messages from the buffer and empties the buffer.
-func (b *Buffer) takeAll() []*PendingMessage {
+// takeDue takes the pending messages due for delivery from the buffer, in order of delivery time.
+// Messages held back for reordering stay in the buffer until the other due messages are delivered.
+func (b *Buffer) takeDue() []*PendingMessage {
 	b.Lock()
 	defer b.Unlock()
 
-	toSend := b.pending[:]
-	b.pending = nil
+	now := time.Now()
+	var due, heldBack, pending []*PendingMessage
+	for _, m := range b.pending {
+		switch {
+		case m.deliverAt.After(now):
+			pending = append(pending, m)
+		case m.heldBack:
+			heldBack = append(heldBack, m)
+		default:
+			due = append(due, m)
+		}
+	}
+
+	// held back messages are released for the next delivery, or delivered now if they are
+	// the only messages due
+	for _, m := range heldBack {
+		m.heldBack = false
+	}
+	if len(due) == 0 {
+		due = heldBack
+	} else {
+		pending = append(heldBack, pending...)
+	}
+	b.pending = pending
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].deliverAt.Before(due[j].deliverAt)
+	})
 
-	return toSend
+	return due
 }
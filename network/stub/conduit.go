@@ -16,6 +16,7 @@ type Conduit struct {
 	publish   p2p.PublishFunc
 	unicast   p2p.UnicastFunc
 	multicast p2p.MulticastFunc
+	request   p2p.RequestFunc
 	close     p2p.CloseFunc
 }
 
@@ -40,6 +41,13 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.multicast(c.channel, event, num, targetIDs...)
 }
 
+func (c *Conduit) Request(ctx context.Context, event interface{}, targetID flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+	return c.request(ctx, c.channel, event, targetID)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s closed", c.channel)
@@ -0,0 +1,215 @@
+package stub
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// LatencyDistribution returns the latency of a message, drawn from the given source of randomness.
+type LatencyDistribution func(rng *rand.Rand) time.Duration
+
+// ConstantLatency delays all messages by the same latency.
+func ConstantLatency(latency time.Duration) LatencyDistribution {
+	return func(*rand.Rand) time.Duration {
+		return latency
+	}
+}
+
+// UniformLatency delays messages by a latency uniformly distributed in [min, max].
+func UniformLatency(min time.Duration, max time.Duration) LatencyDistribution {
+	return func(rng *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rng.Int63n(int64(max-min)+1))
+	}
+}
+
+// NormalLatency delays messages by a latency normally distributed around mean, truncated at zero.
+func NormalLatency(mean time.Duration, stddev time.Duration) LatencyDistribution {
+	return func(rng *rand.Rand) time.Duration {
+		latency := time.Duration(rng.NormFloat64()*float64(stddev)) + mean
+		if latency < 0 {
+			return 0
+		}
+		return latency
+	}
+}
+
+// LinkFaults is the fault model of the messages sent from a node to another node.
+// The zero value delivers all messages immediately, once and in order.
+type LinkFaults struct {
+	Latency   LatencyDistribution // latency of the messages, nil for no latency
+	Drop      float64             // probability that a message is lost
+	Duplicate float64             // probability that a message is delivered twice
+	Reorder   float64             // probability that a message is delivered after messages sent later
+}
+
+// FaultStats counts the faults injected in the messages of a Hub.
+type FaultStats struct {
+	Dropped     uint64 // messages lost on their link
+	Duplicated  uint64 // messages delivered twice
+	Reordered   uint64 // messages held back behind messages sent later
+	Partitioned uint64 // messages lost because sender and receiver were partitioned
+}
+
+// Faults is the fault model of the messages exchanged over a Hub. All random decisions are drawn
+// from a seeded source, so that the faults of an execution can be reproduced from its seed, as long as
+// the engines send their messages in the same order. Latencies are relative to the time the messages
+// are sent, hence the delivery order of delayed messages also depends on the timing of the senders.
+// Partitions can be scripted by the tests at any point, and are applied when the messages are delivered,
+// i.e. messages in flight when a partition starts are lost.
+type Faults struct {
+	sync.Mutex
+	rng       *rand.Rand
+	defaults  LinkFaults
+	links     map[link]LinkFaults
+	partition map[flow.Identifier]int // group of the partitioned nodes, nil if not partitioned
+	stats     FaultStats
+}
+
+// link is the directed link between two nodes.
+type link struct {
+	from flow.Identifier
+	to   flow.Identifier
+}
+
+// NewFaults returns a fault model applying the given faults to all links, with decisions drawn from the seed.
+func NewFaults(seed int64, defaults LinkFaults) *Faults {
+	return &Faults{
+		rng:      rand.New(rand.NewSource(seed)),
+		defaults: defaults,
+		links:    make(map[link]LinkFaults),
+	}
+}
+
+// SetLink overrides the faults of the messages sent from a node to another node.
+func (f *Faults) SetLink(from flow.Identifier, to flow.Identifier, faults LinkFaults) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.links[link{from: from, to: to}] = faults
+}
+
+// Partition splits the nodes into the given groups, which cannot exchange messages until the partition
+// is healed. Nodes that are not part of any group form an additional group, so that a single group
+// isolates its nodes from the rest of the network. A new partition replaces the current one.
+func (f *Faults) Partition(groups ...flow.IdentifierList) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.partition = make(map[flow.Identifier]int)
+	for i, group := range groups {
+		for _, nodeID := range group {
+			// group 0 is the group of the nodes not part of any group
+			f.partition[nodeID] = i + 1
+		}
+	}
+}
+
+// Heal ends the current partition.
+func (f *Faults) Heal() {
+	f.Lock()
+	defer f.Unlock()
+
+	f.partition = nil
+}
+
+// Stats returns the faults injected so far.
+func (f *Faults) Stats() FaultStats {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.stats
+}
+
+// apply splits the message into one message per target, and applies the faults of their link.
+func (f *Faults) apply(m *PendingMessage) []*PendingMessage {
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now()
+	messages := make([]*PendingMessage, 0, len(m.TargetIDs))
+	for _, targetID := range m.TargetIDs {
+		faults, ok := f.links[link{from: m.From, to: targetID}]
+		if !ok {
+			faults = f.defaults
+		}
+
+		if f.rng.Float64() < faults.Drop {
+			f.stats.Dropped++
+			continue
+		}
+
+		copies := 1
+		if f.rng.Float64() < faults.Duplicate {
+			f.stats.Duplicated++
+			copies = 2
+		}
+
+		for i := 0; i < copies; i++ {
+			msg := &PendingMessage{
+				From:       m.From,
+				Channel:    m.Channel,
+				Event:      m.Event,
+				TargetIDs:  []flow.Identifier{targetID},
+				duplicated: copies > 1,
+				deliverAt:  now,
+			}
+			if faults.Latency != nil {
+				msg.deliverAt = now.Add(faults.Latency(f.rng))
+			}
+			if f.rng.Float64() < faults.Reorder {
+				f.stats.Reordered++
+				msg.heldBack = true
+			}
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages
+}
+
+// partitioned returns true if the messages from a node to another node are currently lost.
+func (f *Faults) partitioned(from flow.Identifier, to flow.Identifier) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.partition == nil || f.partition[from] == f.partition[to] {
+		return false
+	}
+	f.stats.Partitioned++
+	return true
+}
+
+// roundTrip applies the faults of the links between the nodes to a request and its response. It returns
+// the latency of the round trip, and false if the request or its response is lost.
+func (f *Faults) roundTrip(from flow.Identifier, to flow.Identifier) (time.Duration, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.partition != nil && f.partition[from] != f.partition[to] {
+		f.stats.Partitioned++
+		return 0, false
+	}
+
+	var latency time.Duration
+	for _, l := range []link{{from: from, to: to}, {from: to, to: from}} {
+		faults, ok := f.links[l]
+		if !ok {
+			faults = f.defaults
+		}
+		if f.rng.Float64() < faults.Drop {
+			f.stats.Dropped++
+			return 0, false
+		}
+		if faults.Latency != nil {
+			latency += faults.Latency(f.rng)
+		}
+	}
+
+	return latency, true
+}
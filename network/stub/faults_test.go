@@ -0,0 +1,174 @@
+package stub_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// faultyNetwork is a sender and a receiver connected through a faulty hub.
+type faultyNetwork struct {
+	sync.Mutex
+	faults     *stub.Faults
+	hub        *stub.Hub
+	senderID   flow.Identifier
+	receiverID flow.Identifier
+	sender     network.Conduit
+	received   []int
+}
+
+func newFaultyNetwork(t *testing.T, seed int64, faults stub.LinkFaults) *faultyNetwork {
+	fn := &faultyNetwork{
+		faults:     stub.NewFaults(seed, faults),
+		senderID:   unittest.IdentifierFixture(),
+		receiverID: unittest.IdentifierFixture(),
+	}
+	fn.hub = stub.NewFaultyNetworkHub(fn.faults)
+
+	sender := &mocknetwork.Engine{}
+	con, err := newNetwork(fn.hub, fn.senderID).Register(engine.TestNetwork, sender)
+	require.NoError(t, err)
+	fn.sender = con
+
+	receiver := &mocknetwork.Engine{}
+	receiver.On("Process", engine.TestNetwork, fn.senderID, mock.Anything).Run(func(args mock.Arguments) {
+		fn.Lock()
+		defer fn.Unlock()
+		fn.received = append(fn.received, args.Get(2).(int))
+	}).Return(nil)
+	_, err = newNetwork(fn.hub, fn.receiverID).Register(engine.TestNetwork, receiver)
+	require.NoError(t, err)
+
+	return fn
+}
+
+func newNetwork(hub *stub.Hub, nodeID flow.Identifier) *stub.Network {
+	me := &module.Local{}
+	me.On("NodeID").Return(nodeID)
+	return stub.NewNetwork(nil, me, hub)
+}
+
+// send sends the events to the receiver, and delivers them synchronously.
+func (fn *faultyNetwork) send(t *testing.T, events ...int) {
+	for _, event := range events {
+		require.NoError(t, fn.sender.Unicast(event, fn.receiverID))
+	}
+	fn.deliver()
+}
+
+func (fn *faultyNetwork) deliver() {
+	net, _ := fn.hub.GetNetwork(fn.senderID)
+	net.DeliverAll(true)
+}
+
+func (fn *faultyNetwork) receivedEvents() []int {
+	fn.Lock()
+	defer fn.Unlock()
+	return append([]int(nil), fn.received...)
+}
+
+func TestFaults_Perfect(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{})
+	fn.send(t, 1, 2, 3)
+	assert.Equal(t, []int{1, 2, 3}, fn.receivedEvents())
+	assert.Equal(t, stub.FaultStats{}, fn.faults.Stats())
+}
+
+func TestFaults_Drop(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{Drop: 0.5})
+
+	events := make([]int, 100)
+	for i := range events {
+		events[i] = i
+	}
+	fn.send(t, events...)
+
+	received := fn.receivedEvents()
+	dropped := fn.faults.Stats().Dropped
+	assert.NotZero(t, dropped)
+	assert.NotEmpty(t, received)
+	assert.Equal(t, len(events), len(received)+int(dropped))
+}
+
+func TestFaults_Duplicate(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{Duplicate: 1})
+	fn.send(t, 1, 2)
+	assert.Equal(t, []int{1, 1, 2, 2}, fn.receivedEvents())
+	assert.Equal(t, uint64(2), fn.faults.Stats().Duplicated)
+}
+
+func TestFaults_Latency(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{Latency: stub.ConstantLatency(100 * time.Millisecond)})
+	fn.send(t, 1)
+	assert.Empty(t, fn.receivedEvents(), "message should not be delivered before its latency")
+
+	time.Sleep(100 * time.Millisecond)
+	fn.deliver()
+	assert.Equal(t, []int{1}, fn.receivedEvents())
+}
+
+func TestFaults_Reorder(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{})
+
+	// the first message is held back behind the second one
+	fn.faults.SetLink(fn.senderID, fn.receiverID, stub.LinkFaults{Reorder: 1})
+	require.NoError(t, fn.sender.Unicast(1, fn.receiverID))
+	fn.faults.SetLink(fn.senderID, fn.receiverID, stub.LinkFaults{})
+	require.NoError(t, fn.sender.Unicast(2, fn.receiverID))
+	fn.deliver()
+
+	assert.Equal(t, []int{2, 1}, fn.receivedEvents())
+	assert.Equal(t, uint64(1), fn.faults.Stats().Reordered)
+}
+
+func TestFaults_Partition(t *testing.T) {
+	fn := newFaultyNetwork(t, 1, stub.LinkFaults{})
+
+	fn.faults.Partition(flow.IdentifierList{fn.senderID})
+	fn.send(t, 1)
+	assert.Empty(t, fn.receivedEvents(), "messages between partitioned nodes should be lost")
+	assert.Equal(t, uint64(1), fn.faults.Stats().Partitioned)
+
+	fn.faults.Heal()
+	fn.send(t, 2)
+	assert.Equal(t, []int{2}, fn.receivedEvents())
+}
+
+// TestFaults_Reproducible checks that the faults are reproduced by the same seed. The delivery order
+// depends on the time the messages are sent, so only the delivered messages are compared.
+func TestFaults_Reproducible(t *testing.T) {
+	faults := stub.LinkFaults{
+		Latency:   stub.UniformLatency(0, time.Millisecond),
+		Drop:      0.3,
+		Duplicate: 0.3,
+		Reorder:   0.3,
+	}
+
+	run := func(seed int64) []int {
+		fn := newFaultyNetwork(t, seed, faults)
+		for event := 0; event < 50; event++ {
+			require.NoError(t, fn.sender.Unicast(event, fn.receiverID))
+		}
+		time.Sleep(time.Millisecond)
+		fn.deliver()
+		received := fn.receivedEvents()
+		sort.Ints(received)
+		return received
+	}
+
+	assert.Equal(t, run(42), run(42))
+	assert.NotEqual(t, run(42), run(43))
+}
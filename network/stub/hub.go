@@ -15,6 +15,7 @@ import (
 type Hub struct {
 	networks map[flow.Identifier]*Network
 	Buffer   *Buffer
+	faults   *Faults // fault model of the messages, nil for a perfect network
 }
 
 // NewNetworkHub creates and returns a new Hub instance.
@@ -25,6 +26,14 @@ func NewNetworkHub() *Hub {
 	}
 }
 
+// NewFaultyNetworkHub creates and returns a new Hub instance, delivering the messages according
+// to the given fault model.
+func NewFaultyNetworkHub(faults *Faults) *Hub {
+	hub := NewNetworkHub()
+	hub.faults = faults
+	return hub
+}
+
 // DeliverAll delivers all the buffered messages in the Network instances attached to the Hub
 // to their destination.
 // Note that the delivery of messages is done in asynchronous mode, i.e., sender and receiver are
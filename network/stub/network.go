@@ -70,6 +70,7 @@ func (n *Network) Register(channel network.Channel, engine network.Engine) (netw
 		publish:   n.publish,
 		unicast:   n.unicast,
 		multicast: n.multicast,
+		request:   n.request,
 	}
 	n.engines[channel] = engine
 	return conduit, nil
@@ -131,6 +132,42 @@ func (n *Network) multicast(channel network.Channel, event interface{}, num uint
 	return n.submit(channel, event, targetIDs...)
 }
 
+// request is called when an engine attached to the channel is sending a request to the engine attached to the
+// same channel on another node. Unlike the other messages, requests are not buffered: the engine of the target
+// handles the request synchronously, after the latency of the round trip if the hub has a fault model.
+func (n *Network) request(ctx context.Context, channel network.Channel, event interface{}, targetID flow.Identifier) (interface{}, error) {
+	receiverNetwork, exist := n.hub.GetNetwork(targetID)
+	if !exist {
+		return nil, network.NewPeerUnreachableError(fmt.Errorf("could not find network of node %v", targetID))
+	}
+
+	if n.hub.faults != nil {
+		latency, delivered := n.hub.faults.roundTrip(n.GetID(), targetID)
+		if !delivered {
+			return nil, network.NewPeerUnreachableError(fmt.Errorf("request to node %v lost", targetID))
+		}
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	receiverNetwork.Lock()
+	receiverEngine, ok := receiverNetwork.engines[channel]
+	receiverNetwork.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("could find engine ID: %v for node: %v", channel, targetID)
+	}
+
+	handler, ok := receiverEngine.(network.RequestHandler)
+	if !ok {
+		return nil, fmt.Errorf("engine of channel %v does not handle requests", channel)
+	}
+
+	return handler.HandleRequest(channel, n.GetID(), event)
+}
+
 // haveSeen returns true if the node attached to this Network instance has seen the event ID.
 // Otherwise, it returns false.
 //
@@ -158,8 +195,16 @@ func (n *Network) seen(eventID string) {
 // buffer saves the message into the pending buffer of the Network hub.
 // Buffering process of a message imitates its transmission over an unreliable Network.
 // In specific, it emulates the process of dispatching the message out of the sender.
+// If the hub has a fault model, the message is buffered once per target, subject to the faults
+// of each link.
 func (n *Network) buffer(msg *PendingMessage) {
-	n.hub.Buffer.Save(msg)
+	if n.hub.faults == nil {
+		n.hub.Buffer.Save(msg)
+		return
+	}
+	for _, m := range n.hub.faults.apply(msg) {
+		n.hub.Buffer.Save(m)
+	}
 }
 
 // DeliverAll sends all pending messages to the receivers. The receivers
@@ -229,9 +274,15 @@ func (n *Network) sendToAllTargets(m *PendingMessage, syncOnProcess bool) error
 			continue
 		}
 
+		// messages between partitioned nodes are lost
+		if n.hub.faults != nil && n.hub.faults.partitioned(m.From, nodeID) {
+			continue
+		}
+
 		// checks if the given engine already received the event.
-		// this prevents a node receiving the same event twice.
-		if receiverNetwork.haveSeen(key) {
+		// this prevents a node receiving the same event twice, unless the message
+		// is duplicated by the fault model.
+		if !m.duplicated && receiverNetwork.haveSeen(key) {
 			continue
 		}
 
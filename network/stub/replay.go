@@ -0,0 +1,39 @@
+package stub
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+)
+
+// Replay delivers the inbound messages of a network capture to the engines attached to this Network,
+// in capture order. Engines process the events synchronously, so that the behavior of an engine on
+// the captured traffic is reproduced deterministically. Messages on channels without an attached
+// engine are skipped. The messages sent by the engines while processing are buffered as usual.
+func (n *Network) Replay(records []*capture.Record, codec network.Codec) error {
+	for i, record := range records {
+		if record.Direction != capture.Inbound {
+			continue
+		}
+
+		n.Lock()
+		receiverEngine, ok := n.engines[record.Channel]
+		n.Unlock()
+		if !ok {
+			continue
+		}
+
+		event, err := record.Event(codec)
+		if err != nil {
+			return fmt.Errorf("could not decode captured message %d: %w", i, err)
+		}
+
+		err = receiverEngine.Process(record.Channel, record.OriginID, event)
+		if err != nil {
+			return fmt.Errorf("engine failed to process captured message %d (%T): %w", i, event, err)
+		}
+	}
+
+	return nil
+}
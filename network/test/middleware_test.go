@@ -1,8 +1,10 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -43,7 +45,8 @@ type tagsObserver struct {
 func (co *tagsObserver) OnNext(peertag interface{}) {
 	pt, ok := peertag.(PeerTag)
 
-	if ok {
+	// the peers of the fanout are also protected, by the connector of the peer manager
+	if ok && strings.HasPrefix(pt.tag, "pubsub:") {
 		co.tags <- fmt.Sprintf("peer: %v tag: %v", pt.peer, pt.tag)
 	}
 
@@ -333,6 +336,27 @@ func (m *MiddlewareTestSuite) TestEcho() {
 	}
 }
 
+// TestSendRequest_HidesHandlerErrors tests that the requester receives a generic error when the target fails to
+// handle its request, rather than the error of the target.
+func (m *MiddlewareTestSuite) TestSendRequest_HidesHandlerErrors() {
+	first := 0
+	last := m.size - 1
+	firstNode := m.ids[first].NodeID
+	lastNode := m.ids[last].NodeID
+
+	request := createMessage(firstNode, lastNode, "request")
+	m.ov[last].On("ReceiveRequest", firstNode, mockery.Anything).Return(nil, fmt.Errorf("internal failure details")).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := m.mws[first].SendRequest(ctx, request, lastNode)
+	require.Error(m.T(), err)
+	assert.Contains(m.T(), err.Error(), "request failed")
+	assert.NotContains(m.T(), err.Error(), "internal failure details")
+
+	m.ov[last].AssertExpectations(m.T())
+}
+
 // TestMaxMessageSize_SendDirect evaluates that invoking SendDirect method of the middleware on a message
 // size beyond the permissible unicast message size returns an error.
 func (m *MiddlewareTestSuite) TestMaxMessageSize_SendDirect() {
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestInboundRateLimits tests that the middleware drops the unicast streams and messages of a peer exceeding the
+// inbound rate limits, and denies it if configured to do so.
+func TestInboundRateLimits(t *testing.T) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.ErrorLevel)
+
+	t.Run("messages exceeding the channel limit are dropped", func(t *testing.T) {
+		mws, ids, received := startRateLimitedMiddlewares(t, logger, p2p.InboundRateLimits{
+			Channels: map[network.Channel]p2p.RateLimit{
+				testChannel: {Rate: 0.01, Burst: 3},
+			},
+		})
+		defer stopRateLimitedMiddlewares(mws)
+
+		for i := 0; i < 10; i++ {
+			err := mws[0].SendDirect(createMessage(ids[0].NodeID, ids[1].NodeID), ids[1].NodeID)
+			require.NoError(t, err)
+		}
+
+		require.Eventually(t, func() bool {
+			return received() == 3
+		}, 3*time.Second, 100*time.Millisecond)
+
+		// the other messages were dropped
+		require.Never(t, func() bool {
+			return received() > 3
+		}, time.Second, 100*time.Millisecond)
+
+		// the peer is not denied
+		connected, err := mws[1].IsConnected(ids[0].NodeID)
+		require.NoError(t, err)
+		assert.True(t, connected)
+	})
+
+	t.Run("peers exceeding the stream limit are denied", func(t *testing.T) {
+		mws, ids, received := startRateLimitedMiddlewares(t, logger, p2p.InboundRateLimits{
+			Streams:      p2p.RateLimit{Rate: 0.01, Burst: 2},
+			DenyDuration: time.Minute,
+		})
+		defer stopRateLimitedMiddlewares(mws)
+
+		for i := 0; i < 3; i++ {
+			// the stream exceeding the limit is reset, which may or may not fail the send
+			_ = mws[0].SendDirect(createMessage(ids[0].NodeID, ids[1].NodeID), ids[1].NodeID)
+		}
+
+		require.Eventually(t, func() bool {
+			return received() == 2
+		}, 3*time.Second, 100*time.Millisecond)
+
+		// the sender is disconnected
+		require.Eventually(t, func() bool {
+			connected, err := mws[1].IsConnected(ids[0].NodeID)
+			return err == nil && !connected
+		}, 3*time.Second, 100*time.Millisecond)
+
+		// and cannot connect again
+		err := mws[0].SendDirect(createMessage(ids[0].NodeID, ids[1].NodeID), ids[1].NodeID)
+		require.Error(t, err)
+		assert.Equal(t, 2, received())
+	})
+}
+
+// startRateLimitedMiddlewares starts two middlewares enforcing the given inbound rate limits, and returns a function
+// counting the messages received by the second one.
+func startRateLimitedMiddlewares(t *testing.T, logger zerolog.Logger, limits p2p.InboundRateLimits) ([]*p2p.Middleware, flow.IdentityList, func() int) {
+	ids, libP2PNodes, _ := GenerateIDs(t, logger, 2, !DryRun, true)
+	mws, _ := GenerateMiddlewares(t, logger, ids, libP2PNodes, true, p2p.WithInboundRateLimits(limits))
+
+	var mu sync.Mutex
+	count := 0
+
+	for _, mw := range mws {
+		overlay := &mocknetwork.Overlay{}
+		overlay.On("Identities").Maybe().Return(ids)
+		overlay.On("Topology").Maybe().Return(ids, nil)
+		overlay.On("Identity", mock.AnythingOfType("peer.ID")).Maybe().Return(unittest.IdentityFixture(), true)
+		overlay.On("Receive", ids[0].NodeID, mock.AnythingOfType("*message.Message")).Maybe().Return(nil).
+			Run(func(args mock.Arguments) {
+				mu.Lock()
+				defer mu.Unlock()
+				count++
+			})
+
+		require.NoError(t, mw.Start(overlay))
+		mw.UpdateAllowList()
+	}
+
+	return mws, ids, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+}
+
+func stopRateLimitedMiddlewares(mws []*p2p.Middleware) {
+	for _, mw := range mws {
+		mw.Stop()
+	}
+}
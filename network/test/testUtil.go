@@ -124,7 +124,7 @@ func GenerateIDs(t *testing.T, logger zerolog.Logger, n int, dryRunMode, connGat
 }
 
 // GenerateMiddlewares creates and initializes middleware instances for all the identities
-func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.IdentityList, libP2PNodes []*p2p.Node, enablePeerManagementAndConnectionGating bool) ([]*p2p.Middleware, []*UpdatableIDProvider) {
+func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.IdentityList, libP2PNodes []*p2p.Node, enablePeerManagementAndConnectionGating bool, opts ...p2p.MiddlewareOption) ([]*p2p.Middleware, []*UpdatableIDProvider) {
 	metrics := metrics.NewNoopCollector()
 	mws := make([]*p2p.Middleware, len(identities))
 	idProviders := make([]*UpdatableIDProvider, len(identities))
@@ -142,6 +142,13 @@ func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.Id
 
 		peerManagerFactory := p2p.PeerManagerFactory(nil)
 
+		mwOpts := append([]p2p.MiddlewareOption{
+			p2p.WithIdentifierProvider(
+				idProviders[i],
+			),
+			p2p.WithPeerManager(peerManagerFactory),
+		}, opts...)
+
 		// creating middleware of nodes
 		mws[i] = p2p.NewMiddleware(logger,
 			factory,
@@ -151,10 +158,7 @@ func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.Id
 			p2p.DefaultUnicastTimeout,
 			enablePeerManagementAndConnectionGating,
 			p2p.NewIdentityProviderIDTranslator(idProviders[i]),
-			p2p.WithIdentifierProvider(
-				idProviders[i],
-			),
-			p2p.WithPeerManager(peerManagerFactory),
+			mwOpts...,
 		)
 	}
 	return mws, idProviders
@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/envelope"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// EnvelopeValidator rejects the messages which are not wrapped in an envelope signed by the staking key of a
+// staked node, and reports their sender to onReject. The payloads of encrypted envelopes are not decrypted, so
+// that the envelopes can be verified by the nodes relaying them.
+func EnvelopeValidator(sealer *envelope.Sealer, onReject RejectionConsumer) MessageValidator {
+	return func(ctx context.Context, from peer.ID, msg *message.Message) pubsub.ValidationResult {
+		_, _, err := sealer.Verify(network.Channel(msg.ChannelID), msg.Payload)
+		if errors.Is(err, envelope.ErrUnknownSigner) {
+			return onReject.reject(from, RejectionUnstakedSender)
+		}
+		// an expired envelope may be relayed in good faith, hence it is dropped without penalizing its sender
+		if errors.Is(err, envelope.ErrExpired) {
+			return pubsub.ValidationIgnore
+		}
+		if err != nil {
+			return onReject.reject(from, RejectionInvalidEnvelope)
+		}
+		return pubsub.ValidationAccept
+	}
+}
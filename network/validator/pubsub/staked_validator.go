@@ -10,11 +10,27 @@ import (
 	"github.com/onflow/flow-go/network/message"
 )
 
-func StakedValidator(getIdentity func(peer.ID) (*flow.Identity, bool)) MessageValidator {
+// StakedValidator rejects the messages sent by nodes which are not staked, and reports their sender to onReject.
+func StakedValidator(getIdentity func(peer.ID) (*flow.Identity, bool), onReject RejectionConsumer) MessageValidator {
 	return func(ctx context.Context, from peer.ID, msg *message.Message) pubsub.ValidationResult {
 		if _, ok := getIdentity(from); ok {
 			return pubsub.ValidationAccept
 		}
-		return pubsub.ValidationReject
+		return onReject.reject(from, RejectionUnstakedSender)
+	}
+}
+
+// RoleValidator rejects the messages sent by nodes which are not staked, or whose role is not one of the
+// given roles, and reports their sender to onReject.
+func RoleValidator(roles flow.RoleList, getIdentity func(peer.ID) (*flow.Identity, bool), onReject RejectionConsumer) MessageValidator {
+	return func(ctx context.Context, from peer.ID, msg *message.Message) pubsub.ValidationResult {
+		identity, ok := getIdentity(from)
+		if !ok {
+			return onReject.reject(from, RejectionUnstakedSender)
+		}
+		if !roles.Contains(identity.Role) {
+			return onReject.reject(from, RejectionWrongRole)
+		}
+		return pubsub.ValidationAccept
 	}
 }
@@ -51,6 +51,34 @@ func messageSigningID(m *pubsub.Message) (peer.ID, error) {
 	return pid, nil
 }
 
+// Rejection is the reason a message was rejected by the topic validators.
+type Rejection string
+
+const (
+	// RejectionUndecodable is the rejection of a message whose payload cannot be decoded.
+	RejectionUndecodable Rejection = "undecodable_payload"
+	// RejectionInvalidSigner is the rejection of a message whose signer cannot be identified.
+	RejectionInvalidSigner Rejection = "invalid_signer"
+	// RejectionUnstakedSender is the rejection of a message sent by a node which is not staked.
+	RejectionUnstakedSender Rejection = "unstaked_sender"
+	// RejectionWrongRole is the rejection of a message sent by a staked node whose role is not involved in the channel.
+	RejectionWrongRole Rejection = "wrong_role"
+	// RejectionInvalidEnvelope is the rejection of a message on a private channel whose envelope is missing or
+	// not signed by its signer.
+	RejectionInvalidEnvelope Rejection = "invalid_envelope"
+)
+
+// RejectionConsumer is notified of the messages rejected by the topic validators, with the peer held
+// responsible for the message. It is used to penalize misbehaving peers and can be nil.
+type RejectionConsumer func(pid peer.ID, rejection Rejection)
+
+func (c RejectionConsumer) reject(pid peer.ID, rejection Rejection) pubsub.ValidationResult {
+	if c != nil {
+		c(pid, rejection)
+	}
+	return pubsub.ValidationReject
+}
+
 // MessageValidator validates the given message with original sender `from`.
 // Note: contrarily to pubsub.ValidatorEx, the peerID parameter does not represent the bearer of the message, but its source.
 type MessageValidator func(ctx context.Context, from peer.ID, msg *message.Message) pubsub.ValidationResult
@@ -60,7 +88,10 @@ type ValidatorData struct {
 	From    peer.ID
 }
 
-func TopicValidator(validators ...MessageValidator) pubsub.ValidatorEx {
+// TopicValidator returns a pubsub validator running the given validators on the decoded messages.
+// Messages which cannot be decoded, or whose signer cannot be identified, are rejected and the peer
+// which relayed them is reported to onReject.
+func TopicValidator(onReject RejectionConsumer, validators ...MessageValidator) pubsub.ValidatorEx {
 	return func(ctx context.Context, receivedFrom peer.ID, rawMsg *pubsub.Message) pubsub.ValidationResult {
 		var msg message.Message
 		// convert the incoming raw message payload to Message type
@@ -68,12 +99,12 @@ func TopicValidator(validators ...MessageValidator) pubsub.ValidatorEx {
 		err := msg.Unmarshal(rawMsg.Data)
 		//binstat.Leave(bs)
 		if err != nil {
-			return pubsub.ValidationReject
+			return onReject.reject(receivedFrom, RejectionUndecodable)
 		}
 
 		from, err := messageSigningID(rawMsg)
 		if err != nil {
-			return pubsub.ValidationReject
+			return onReject.reject(receivedFrom, RejectionInvalidSigner)
 		}
 
 		rawMsg.ValidatorData = ValidatorData{
@@ -3,12 +3,11 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Snapshot represents a snapshot of chain state anchored at a particular
@@ -25,7 +24,7 @@ func (s *Snapshot) Collection() (*flow.Collection, error) {
 	}
 
 	var collection flow.Collection
-	err := s.state.db.View(func(tx *badger.Txn) error {
+	err := s.state.db.View(func(tx kv.Txn) error {
 
 		// get the header for this snapshot
 		var header flow.Header
@@ -56,7 +55,7 @@ func (s *Snapshot) Head() (*flow.Header, error) {
 	}
 
 	var head flow.Header
-	err := s.state.db.View(func(tx *badger.Txn) error {
+	err := s.state.db.View(func(tx kv.Txn) error {
 		return s.head(&head)(tx)
 	})
 	return &head, err
@@ -70,8 +69,8 @@ func (s *Snapshot) Pending() ([]flow.Identifier, error) {
 }
 
 // head finds the header referenced by the snapshot.
-func (s *Snapshot) head(head *flow.Header) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func (s *Snapshot) head(head *flow.Header) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 
 		// get the snapshot header
 		err := operation.RetrieveHeader(s.blockID, head)(tx)
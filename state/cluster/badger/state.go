@@ -4,25 +4,24 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/state/cluster"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type State struct {
-	db        *badger.DB
+	db        kv.DB
 	clusterID flow.ChainID
 }
 
 // Bootstrap initializes the persistent cluster state with a genesis block.
 // The genesis block must have height 0, a parent hash of 32 zero bytes,
 // and an empty collection as payload.
-func Bootstrap(db *badger.DB, stateRoot *StateRoot) (*State, error) {
+func Bootstrap(db kv.DB, stateRoot *StateRoot) (*State, error) {
 	isBootstrapped, err := IsBootstrapped(db, stateRoot.ClusterID())
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine whether database contains bootstrapped state: %w", err)
@@ -34,7 +33,7 @@ func Bootstrap(db *badger.DB, stateRoot *StateRoot) (*State, error) {
 
 	genesis := stateRoot.Block()
 	// bootstrap cluster state
-	err = operation.RetryOnConflict(state.db.Update, func(tx *badger.Txn) error {
+	err = operation.RetryOnConflict(state.db.Update, func(tx kv.Txn) error {
 		chainID := genesis.Header.ChainID
 		// insert the block
 		err := procedure.InsertClusterBlock(genesis)(tx)
@@ -71,7 +70,7 @@ func Bootstrap(db *badger.DB, stateRoot *StateRoot) (*State, error) {
 	return state, nil
 }
 
-func OpenState(db *badger.DB, tracer module.Tracer, headers storage.Headers, payloads storage.ClusterPayloads, clusterID flow.ChainID) (*State, error) {
+func OpenState(db kv.DB, tracer module.Tracer, headers storage.Headers, payloads storage.ClusterPayloads, clusterID flow.ChainID) (*State, error) {
 	isBootstrapped, err := IsBootstrapped(db, clusterID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine whether database contains bootstrapped state: %w", err)
@@ -83,7 +82,7 @@ func OpenState(db *badger.DB, tracer module.Tracer, headers storage.Headers, pay
 	return state, nil
 }
 
-func newState(db *badger.DB, clusterID flow.ChainID) *State {
+func newState(db kv.DB, clusterID flow.ChainID) *State {
 	state := &State{
 		db:        db,
 		clusterID: clusterID,
@@ -101,7 +100,7 @@ func (s *State) Params() cluster.Params {
 func (s *State) Final() cluster.Snapshot {
 	// get the finalized block ID
 	var blockID flow.Identifier
-	err := s.db.View(func(tx *badger.Txn) error {
+	err := s.db.View(func(tx kv.Txn) error {
 		var boundary uint64
 		err := operation.RetrieveClusterFinalizedHeight(s.clusterID, &boundary)(tx)
 		if err != nil {
@@ -137,7 +136,7 @@ func (s *State) AtBlockID(blockID flow.Identifier) cluster.Snapshot {
 }
 
 // IsBootstrapped returns whether or not the database contains a bootstrapped state
-func IsBootstrapped(db *badger.DB, clusterID flow.ChainID) (bool, error) {
+func IsBootstrapped(db kv.DB, clusterID flow.ChainID) (bool, error) {
 	var finalized uint64
 	err := db.View(operation.RetrieveClusterFinalizedHeight(clusterID, &finalized))
 	if errors.Is(err, storage.ErrNotFound) {
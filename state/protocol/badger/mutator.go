@@ -7,8 +7,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
@@ -19,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // errIncompleteEpochConfiguration is a sentinel error returned when there are
@@ -603,7 +602,7 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	// * Update the largest height of sealed and finalized block.
 	//   This value could actually stay the same if it has no seals in
 	//   its payload, in which case the parent's seal is the same.
-	err = operation.RetryOnConflict(m.db.Update, func(tx *badger.Txn) error {
+	err = operation.RetryOnConflict(m.db.Update, func(tx kv.Txn) error {
 		err = operation.IndexBlockHeight(header.Height, blockID)(tx)
 		if err != nil {
 			return fmt.Errorf("could not insert number mapping: %w", err)
@@ -9,7 +9,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -19,6 +18,7 @@ import (
 	bprotocol "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/state/protocol/util"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -31,7 +31,7 @@ func TestHead(t *testing.T) {
 	rootSnapshot := unittest.RootSnapshotFixture(participants)
 	head, err := rootSnapshot.Head()
 	require.NoError(t, err)
-	util.RunWithBootstrapState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.State) {
+	util.RunWithBootstrapState(t, rootSnapshot, func(db kv.DB, state *bprotocol.State) {
 
 		t.Run("works with block number", func(t *testing.T) {
 			retrieved, err := state.AtHeight(head.Height).Head()
@@ -62,7 +62,7 @@ func TestSnapshot_Descendants(t *testing.T) {
 	rootSnapshot := unittest.RootSnapshotFixture(participants)
 	head, err := rootSnapshot.Head()
 	require.NoError(t, err)
-	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.MutableState) {
 		var expectedBlocks []flow.Identifier
 		for i := 5; i > 3; i-- {
 			for _, block := range unittest.ChainFixtureFrom(i, head) {
@@ -87,7 +87,7 @@ func TestSnapshot_ValidDescendants(t *testing.T) {
 	rootSnapshot := unittest.RootSnapshotFixture(participants)
 	head, err := rootSnapshot.Head()
 	require.NoError(t, err)
-	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.MutableState) {
 		var expectedBlocks []flow.Identifier
 		for i := 5; i > 3; i-- {
 			fork := unittest.ChainFixtureFrom(i, head)
@@ -112,7 +112,7 @@ func TestSnapshot_ValidDescendants(t *testing.T) {
 func TestIdentities(t *testing.T) {
 	identities := unittest.IdentityListFixture(5, unittest.WithAllRoles())
 	rootSnapshot := unittest.RootSnapshotFixture(identities)
-	util.RunWithBootstrapState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.State) {
+	util.RunWithBootstrapState(t, rootSnapshot, func(db kv.DB, state *bprotocol.State) {
 
 		t.Run("no filter", func(t *testing.T) {
 			actual, err := state.Final().Identities(filter.Any)
@@ -163,7 +163,7 @@ func TestClusters(t *testing.T) {
 	rootSnapshot, err := inmem.SnapshotFromBootstrapState(root, result, seal, qc)
 	require.NoError(t, err)
 
-	util.RunWithBootstrapState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.State) {
+	util.RunWithBootstrapState(t, rootSnapshot, func(db kv.DB, state *bprotocol.State) {
 		expectedClusters, err := flow.NewClusterList(setup.Assignments, collectors)
 		require.NoError(t, err)
 		actualClusters, err := state.Final().Epochs().Current().Clustering()
@@ -190,7 +190,7 @@ func TestSealingSegment(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("root sealing segment", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 			expected, err := rootSnapshot.SealingSegment()
 			require.NoError(t, err)
 			actual, err := state.AtBlockID(head.ID()).SealingSegment()
@@ -207,7 +207,7 @@ func TestSealingSegment(t *testing.T) {
 	// ROOT <- B1 <- B2(S1)
 	// Expected sealing segment: [B1, B2]
 	t.Run("non-root", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 			// build a block to seal
 			block1 := unittest.BlockWithParentFixture(head)
 			err := state.Extend(context.Background(), &block1)
@@ -236,7 +236,7 @@ func TestSealingSegment(t *testing.T) {
 	// ROOT <- B1 <- .... <- BN(S1)
 	// Expected sealing segment: [B1, ..., BN]
 	t.Run("long sealing segment", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 
 			// build a block to seal
 			block1 := unittest.BlockWithParentFixture(head)
@@ -275,7 +275,7 @@ func TestSealingSegment(t *testing.T) {
 	// ROOT -> B1 -> B2 -> B3(S1) -> B4(S2)
 	// Expected sealing segment: [B2, B3, B4]
 	t.Run("overlapping sealing segment", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 
 			block1 := unittest.BlockWithParentFixture(head)
 			err := state.Extend(context.Background(), &block1)
@@ -314,7 +314,7 @@ func TestLatestSealedResult(t *testing.T) {
 	rootSnapshot := unittest.RootSnapshotFixture(identities)
 
 	t.Run("root snapshot", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 			gotResult, gotSeal, err := state.Final().SealedResult()
 			require.NoError(t, err)
 			expectedResult, expectedSeal, err := rootSnapshot.SealedResult()
@@ -329,7 +329,7 @@ func TestLatestSealedResult(t *testing.T) {
 		head, err := rootSnapshot.Head()
 		require.NoError(t, err)
 
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 			block1 := unittest.BlockWithParentFixture(head)
 			err = state.Extend(context.Background(), &block1)
 			require.NoError(t, err)
@@ -393,7 +393,7 @@ func TestQuorumCertificate(t *testing.T) {
 
 	// should not be able to get QC or random beacon seed from a block with no children
 	t.Run("no children", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 
 			// create a block to query
 			block1 := unittest.BlockWithParentFixture(head)
@@ -412,7 +412,7 @@ func TestQuorumCertificate(t *testing.T) {
 	// should not be able to get random beacon seed from a block with only invalid
 	// or unvalidated children
 	t.Run("un-validated child", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 
 			// create a block to query
 			block1 := unittest.BlockWithParentFixture(head)
@@ -436,7 +436,7 @@ func TestQuorumCertificate(t *testing.T) {
 
 	// should be able to get QC and random beacon seed from root block
 	t.Run("root block", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 			// since we bootstrap with a root snapshot, this will be the root block
 			_, err := state.AtBlockID(head.ID()).QuorumCertificate()
 			assert.NoError(t, err)
@@ -447,7 +447,7 @@ func TestQuorumCertificate(t *testing.T) {
 
 	// should be able to get QC and random beacon seed from a block with a valid child
 	t.Run("valid child", func(t *testing.T) {
-		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.FollowerState) {
+		util.RunWithFollowerProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.FollowerState) {
 
 			// add a block so we aren't testing against root
 			block1 := unittest.BlockWithParentFixture(head)
@@ -487,7 +487,7 @@ func TestSnapshot_EpochQuery(t *testing.T) {
 	result, _, err := rootSnapshot.SealedResult()
 	require.NoError(t, err)
 
-	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.MutableState) {
 		epoch1Counter := result.ServiceEvents[0].Event.(*flow.EpochSetup).Counter
 		epoch2Counter := epoch1Counter + 1
 
@@ -578,7 +578,7 @@ func TestSnapshot_EpochFirstView(t *testing.T) {
 	result, _, err := rootSnapshot.SealedResult()
 	require.NoError(t, err)
 
-	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.MutableState) {
 
 		epochBuilder := unittest.NewEpochBuilder(t, state)
 		// build epoch 1 (prepare epoch 2)
@@ -669,7 +669,7 @@ func TestSnapshot_CrossEpochIdentities(t *testing.T) {
 	epoch3Identities := unittest.IdentityListFixture(10, unittest.WithAllRoles())
 
 	rootSnapshot := unittest.RootSnapshotFixture(epoch1Identities)
-	util.RunWithFullProtocolState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+	util.RunWithFullProtocolState(t, rootSnapshot, func(db kv.DB, state *bprotocol.MutableState) {
 
 		epochBuilder := unittest.NewEpochBuilder(t, state)
 		// build epoch 1 (prepare epoch 2)
@@ -792,7 +792,7 @@ func TestSnapshot_PostSporkIdentities(t *testing.T) {
 	rootSnapshot, err := inmem.SnapshotFromBootstrapState(root, result, seal, qc)
 	require.NoError(t, err)
 
-	util.RunWithBootstrapState(t, rootSnapshot, func(db *badger.DB, state *bprotocol.State) {
+	util.RunWithBootstrapState(t, rootSnapshot, func(db kv.DB, state *bprotocol.State) {
 		actual, err := state.Final().Identities(filter.Any)
 		require.Nil(t, err)
 		assert.ElementsMatch(t, expected, actual)
@@ -6,8 +6,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/state/protocol"
@@ -15,11 +13,12 @@ import (
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type State struct {
 	metrics module.ComplianceMetrics
-	db      *badger.DB
+	db      kv.DB
 	headers storage.Headers
 	blocks  storage.Blocks
 	results storage.ExecutionResults
@@ -51,7 +50,7 @@ func SkipNetworkAddressValidation(conf *BootstrapConfig) {
 
 func Bootstrap(
 	metrics module.ComplianceMetrics,
-	db *badger.DB,
+	db kv.DB,
 	headers storage.Headers,
 	seals storage.Seals,
 	results storage.ExecutionResults,
@@ -195,8 +194,8 @@ func (state *State) bootstrapSealingSegment(root protocol.Snapshot) func(*transa
 	}
 }
 
-func (state *State) bootstrapSealedResult(root protocol.Snapshot) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func (state *State) bootstrapSealedResult(root protocol.Snapshot) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 		head, err := root.Head()
 		if err != nil {
 			return fmt.Errorf("could not get head from root snapshot: %w", err)
@@ -230,8 +229,8 @@ func (state *State) bootstrapSealedResult(root protocol.Snapshot) func(*badger.T
 
 // bootstrapStatePointers instantiates special pointers used to by the protocol
 // state to keep track of special block heights and views.
-func (state *State) bootstrapStatePointers(root protocol.Snapshot) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func (state *State) bootstrapStatePointers(root protocol.Snapshot) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 		segment, err := root.SealingSegment()
 		if err != nil {
 			return fmt.Errorf("could not get sealing segment: %w", err)
@@ -405,7 +404,7 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 
 func OpenState(
 	metrics module.ComplianceMetrics,
-	db *badger.DB,
+	db kv.DB,
 	headers storage.Headers,
 	seals storage.Seals,
 	results storage.ExecutionResults,
@@ -478,7 +477,7 @@ func (s *State) AtBlockID(blockID flow.Identifier) protocol.Snapshot {
 // is expected to contain a an already bootstrapped state or not
 func newState(
 	metrics module.ComplianceMetrics,
-	db *badger.DB,
+	db kv.DB,
 	headers storage.Headers,
 	seals storage.Seals,
 	results storage.ExecutionResults,
@@ -507,7 +506,7 @@ func newState(
 }
 
 // IsBootstrapped returns whether or not the database contains a bootstrapped state
-func IsBootstrapped(db *badger.DB) (bool, error) {
+func IsBootstrapped(db kv.DB) (bool, error) {
 	var finalized uint64
 	err := db.View(operation.RetrieveFinalizedHeight(&finalized))
 	if errors.Is(err, storage.ErrNotFound) {
@@ -3,7 +3,6 @@ package util
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/state/protocol/events"
 	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -62,8 +62,8 @@ func MockSealValidator(sealsDB storage.Seals) module.SealValidator {
 	return validator
 }
 
-func RunWithBootstrapState(t testing.TB, rootSnapshot protocol.Snapshot, f func(*badger.DB, *pbadger.State)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithBootstrapState(t testing.TB, rootSnapshot protocol.Snapshot, f func(kv.DB, *pbadger.State)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		headers, _, seals, _, _, blocks, setups, commits, statuses, results := util.StorageLayer(t, db)
 		state, err := pbadger.Bootstrap(metrics, db, headers, seals, results, blocks, setups, commits, statuses, rootSnapshot)
@@ -72,8 +72,8 @@ func RunWithBootstrapState(t testing.TB, rootSnapshot protocol.Snapshot, f func(
 	})
 }
 
-func RunWithFullProtocolState(t testing.TB, rootSnapshot protocol.Snapshot, f func(*badger.DB, *pbadger.MutableState)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithFullProtocolState(t testing.TB, rootSnapshot protocol.Snapshot, f func(kv.DB, *pbadger.MutableState)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		tracer := trace.NewNoopTracer()
 		consumer := events.NewNoop()
@@ -89,8 +89,8 @@ func RunWithFullProtocolState(t testing.TB, rootSnapshot protocol.Snapshot, f fu
 	})
 }
 
-func RunWithFullProtocolStateAndValidator(t testing.TB, rootSnapshot protocol.Snapshot, validator module.ReceiptValidator, f func(*badger.DB, *pbadger.MutableState)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithFullProtocolStateAndValidator(t testing.TB, rootSnapshot protocol.Snapshot, validator module.ReceiptValidator, f func(kv.DB, *pbadger.MutableState)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		tracer := trace.NewNoopTracer()
 		consumer := events.NewNoop()
@@ -105,8 +105,8 @@ func RunWithFullProtocolStateAndValidator(t testing.TB, rootSnapshot protocol.Sn
 	})
 }
 
-func RunWithFollowerProtocolState(t testing.TB, rootSnapshot protocol.Snapshot, f func(*badger.DB, *pbadger.FollowerState)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithFollowerProtocolState(t testing.TB, rootSnapshot protocol.Snapshot, f func(kv.DB, *pbadger.FollowerState)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		tracer := trace.NewNoopTracer()
 		consumer := events.NewNoop()
@@ -120,8 +120,8 @@ func RunWithFollowerProtocolState(t testing.TB, rootSnapshot protocol.Snapshot,
 	})
 }
 
-func RunWithFullProtocolStateAndConsumer(t testing.TB, rootSnapshot protocol.Snapshot, consumer protocol.Consumer, f func(*badger.DB, *pbadger.MutableState)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithFullProtocolStateAndConsumer(t testing.TB, rootSnapshot protocol.Snapshot, consumer protocol.Consumer, f func(kv.DB, *pbadger.MutableState)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		tracer := trace.NewNoopTracer()
 		headers, _, seals, index, payloads, blocks, setups, commits, statuses, results := util.StorageLayer(t, db)
@@ -136,8 +136,8 @@ func RunWithFullProtocolStateAndConsumer(t testing.TB, rootSnapshot protocol.Sna
 	})
 }
 
-func RunWithFollowerProtocolStateAndHeaders(t testing.TB, rootSnapshot protocol.Snapshot, f func(*badger.DB, *pbadger.FollowerState, storage.Headers, storage.Index)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithFollowerProtocolStateAndHeaders(t testing.TB, rootSnapshot protocol.Snapshot, f func(kv.DB, *pbadger.FollowerState, storage.Headers, storage.Index)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		tracer := trace.NewNoopTracer()
 		consumer := events.NewNoop()
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// AccountTransactions represents the persistent index of the transactions touching each account, by height.
+type AccountTransactions interface {
+
+	// Index indexes the transactions of the block at the given height under the accounts they touch
+	Index(blockID flow.Identifier, height uint64, transactions []AccountTransaction) error
+
+	// BatchIndex indexes the transactions of the block at the given height under the accounts they touch in a given batch
+	BatchIndex(blockID flow.Identifier, height uint64, transactions []AccountTransaction, batch BatchStorage) error
+
+	// ByAddress returns up to limit transactions touching the account between the start and end heights (inclusive),
+	// ordered by height, block and transaction index, starting from the cursor if not nil. The transactions of all
+	// the blocks indexed at each height are returned, including the blocks which are not finalized.
+	// It returns the cursor of the next page, nil if there is none. A limit of 0 returns all the transactions.
+	ByAddress(address flow.Address, startHeight, endHeight uint64, cursor *AccountTransactionCursor, limit uint) ([]AccountTransaction, *AccountTransactionCursor, error)
+}
+
+// AccountRoles are the roles of an account in a transaction, as a bit set.
+type AccountRoles uint8
+
+const (
+	AccountRolePayer      AccountRoles = 1 << iota // pays the fees of the transaction
+	AccountRoleProposer                            // provides the proposal key of the transaction
+	AccountRoleAuthorizer                          // authorizes the transaction
+	AccountRoleOwner                               // owns registers written by the transaction
+)
+
+// Has returns whether the roles include the given role.
+func (r AccountRoles) Has(role AccountRoles) bool {
+	return r&role == role
+}
+
+// AccountTransaction is a transaction touching an account.
+type AccountTransaction struct {
+	Address          flow.Address
+	BlockID          flow.Identifier
+	BlockHeight      uint64
+	TransactionID    flow.Identifier
+	TransactionIndex uint32
+	Roles            AccountRoles
+}
+
+// AccountTransactionCursor is the position of the first transaction of a page of transactions indexed by account.
+type AccountTransactionCursor struct {
+	Height           uint64
+	BlockID          flow.Identifier
+	TransactionIndex uint32
+}
+
+// TransactionAccounts returns the accounts touched by the transaction at the given index of a block, as payer,
+// proposer, authorizer or owner of the given written registers.
+func TransactionAccounts(
+	blockID flow.Identifier,
+	height uint64,
+	txIndex uint32,
+	tx *flow.TransactionBody,
+	owners []flow.Address,
+) []AccountTransaction {
+	roles := make(map[flow.Address]AccountRoles)
+	var addresses []flow.Address
+	add := func(address flow.Address, role AccountRoles) {
+		if _, ok := roles[address]; !ok {
+			addresses = append(addresses, address)
+		}
+		roles[address] |= role
+	}
+
+	add(tx.Payer, AccountRolePayer)
+	add(tx.ProposalKey.Address, AccountRoleProposer)
+	for _, authorizer := range tx.Authorizers {
+		add(authorizer, AccountRoleAuthorizer)
+	}
+	for _, owner := range owners {
+		add(owner, AccountRoleOwner)
+	}
+
+	txID := tx.ID()
+	transactions := make([]AccountTransaction, 0, len(addresses))
+	for _, address := range addresses {
+		transactions = append(transactions, AccountTransaction{
+			Address:          address,
+			BlockID:          blockID,
+			BlockHeight:      height,
+			TransactionID:    txID,
+			TransactionIndex: txIndex,
+			Roles:            roles[address],
+		})
+	}
+	return transactions
+}
+
+// BlockAccountTransactions returns the accounts touched by the transactions of the block as payer, proposer or
+// authorizer, reading the transactions from the stored collections of the block.
+func BlockAccountTransactions(block *flow.Block, collections Collections) ([]AccountTransaction, error) {
+	blockID := block.ID()
+
+	var transactions []AccountTransaction
+	var txIndex uint32
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve collection %x: %w", guarantee.CollectionID, err)
+		}
+		for _, tx := range collection.Transactions {
+			transactions = append(transactions, TransactionAccounts(blockID, block.Header.Height, txIndex, tx, nil)...)
+			txIndex++
+		}
+	}
+	return transactions, nil
+}
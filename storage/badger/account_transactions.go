@@ -0,0 +1,96 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// AccountTransactions implements the index of the transactions touching each account, by height.
+type AccountTransactions struct {
+	db kv.DB
+}
+
+// NewAccountTransactions returns the index of the transactions touching each account.
+func NewAccountTransactions(db kv.DB) *AccountTransactions {
+	return &AccountTransactions{
+		db: db,
+	}
+}
+
+// Index indexes the transactions of the block at the given height under the accounts they touch
+func (a *AccountTransactions) Index(blockID flow.Identifier, height uint64, transactions []storage.AccountTransaction) error {
+	batch := NewBatch(a.db)
+
+	err := a.BatchIndex(blockID, height, transactions, batch)
+	if err != nil {
+		return err
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush account transactions batch: %w", err)
+	}
+
+	return nil
+}
+
+// BatchIndex indexes the transactions of the block at the given height under the accounts they touch in a given batch
+func (a *AccountTransactions) BatchIndex(blockID flow.Identifier, height uint64, transactions []storage.AccountTransaction, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	for _, transaction := range transactions {
+		if transaction.BlockID != blockID || transaction.BlockHeight != height {
+			return fmt.Errorf("transaction %x of block %x at height %d cannot be indexed for block %x at height %d",
+				transaction.TransactionID, transaction.BlockID, transaction.BlockHeight, blockID, height)
+		}
+
+		err := operation.BatchIndexAccountTransaction(transaction)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch index account transaction: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ByAddress returns up to limit transactions touching the account between the start and end heights (inclusive),
+// ordered by height, block and transaction index, starting from the cursor if not nil. The transactions of all
+// the blocks indexed at each height are returned, including the blocks which are not finalized.
+// It returns the cursor of the next page, nil if there is none. A limit of 0 returns all the transactions.
+func (a *AccountTransactions) ByAddress(address flow.Address, startHeight, endHeight uint64, cursor *storage.AccountTransactionCursor, limit uint) ([]storage.AccountTransaction, *storage.AccountTransactionCursor, error) {
+	from := storage.AccountTransactionCursor{Height: startHeight}
+	if cursor != nil {
+		if cursor.Height < startHeight {
+			return nil, nil, fmt.Errorf("cursor height %d is below start height %d", cursor.Height, startHeight)
+		}
+		from = *cursor
+	}
+
+	// look up the first transaction of the next page as well, which is the cursor of the next page
+	lookupLimit := limit
+	if limit > 0 {
+		lookupLimit = limit + 1
+	}
+	var transactions []storage.AccountTransaction
+	err := a.db.View(operation.LookupAccountTransactions(address, from, endHeight, lookupLimit, &transactions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not look up account transactions: %w", err)
+	}
+
+	var next *storage.AccountTransactionCursor
+	if limit > 0 && uint(len(transactions)) > limit {
+		last := transactions[limit]
+		next = &storage.AccountTransactionCursor{
+			Height:           last.BlockHeight,
+			BlockID:          last.BlockID,
+			TransactionIndex: last.TransactionIndex,
+		}
+		transactions = transactions[:limit]
+	}
+
+	return transactions, next, nil
+}
@@ -0,0 +1,161 @@
+package badger_test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestTransactionAccounts(t *testing.T) {
+	payer := unittest.RandomAddressFixture()
+	authorizer := unittest.RandomAddressFixture()
+	owner := unittest.RandomAddressFixture()
+
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.Payer = payer
+		tx.ProposalKey.Address = payer
+		tx.Authorizers = []flow.Address{authorizer}
+	})
+	blockID := unittest.IdentifierFixture()
+
+	transactions := storage.TransactionAccounts(blockID, 10, 2, &tx, []flow.Address{authorizer, owner})
+	require.Len(t, transactions, 3)
+
+	// the roles of each account are merged, in the order the accounts first appear
+	assert.Equal(t, payer, transactions[0].Address)
+	assert.Equal(t, storage.AccountRolePayer|storage.AccountRoleProposer, transactions[0].Roles)
+	assert.Equal(t, authorizer, transactions[1].Address)
+	assert.Equal(t, storage.AccountRoleAuthorizer|storage.AccountRoleOwner, transactions[1].Roles)
+	assert.Equal(t, owner, transactions[2].Address)
+	assert.Equal(t, storage.AccountRoleOwner, transactions[2].Roles)
+
+	for _, transaction := range transactions {
+		assert.Equal(t, blockID, transaction.BlockID)
+		assert.Equal(t, uint64(10), transaction.BlockHeight)
+		assert.Equal(t, tx.ID(), transaction.TransactionID)
+		assert.Equal(t, uint32(2), transaction.TransactionIndex)
+	}
+}
+
+func TestAccountTransactionsIndexAndRetrieve(t *testing.T) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
+		store := badgerstorage.NewAccountTransactions(db)
+
+		address := unittest.RandomAddressFixture()
+		other := unittest.RandomAddressFixture()
+
+		// two conflicting blocks at height 10 and one block at height 11, with two transactions each
+		blockIDs := []flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()}
+		sort.Slice(blockIDs, func(i, j int) bool {
+			return bytes.Compare(blockIDs[i][:], blockIDs[j][:]) < 0
+		})
+		blocks := []struct {
+			id     flow.Identifier
+			height uint64
+		}{
+			{blockIDs[1], 10},
+			{blockIDs[0], 10},
+			{unittest.IdentifierFixture(), 11},
+		}
+
+		var expected []storage.AccountTransaction
+		for _, block := range blocks {
+			var transactions []storage.AccountTransaction
+			for i := uint32(0); i < 2; i++ {
+				transaction := storage.AccountTransaction{
+					Address:          address,
+					BlockID:          block.id,
+					BlockHeight:      block.height,
+					TransactionID:    unittest.IdentifierFixture(),
+					TransactionIndex: i,
+					Roles:            storage.AccountRoleAuthorizer,
+				}
+				transactions = append(transactions, transaction, storage.AccountTransaction{
+					Address:          other,
+					BlockID:          block.id,
+					BlockHeight:      block.height,
+					TransactionID:    transaction.TransactionID,
+					TransactionIndex: i,
+					Roles:            storage.AccountRolePayer,
+				})
+			}
+			err := store.Index(block.id, block.height, transactions)
+			require.NoError(t, err)
+		}
+		// the transactions are ordered by height, block ID and transaction index
+		for _, block := range []int{1, 0, 2} {
+			for i := uint32(0); i < 2; i++ {
+				expected = append(expected, storage.AccountTransaction{
+					Address:          address,
+					BlockID:          blocks[block].id,
+					BlockHeight:      blocks[block].height,
+					TransactionIndex: i,
+					Roles:            storage.AccountRoleAuthorizer,
+				})
+			}
+		}
+
+		t.Run("all transactions of the range are retrieved", func(t *testing.T) {
+			actual, next, err := store.ByAddress(address, 10, 11, nil, 0)
+			require.NoError(t, err)
+			require.Nil(t, next)
+			require.Len(t, actual, len(expected))
+			for i, transaction := range actual {
+				assert.Equal(t, expected[i].Address, transaction.Address)
+				assert.Equal(t, expected[i].BlockID, transaction.BlockID)
+				assert.Equal(t, expected[i].BlockHeight, transaction.BlockHeight)
+				assert.Equal(t, expected[i].TransactionIndex, transaction.TransactionIndex)
+				assert.Equal(t, expected[i].Roles, transaction.Roles)
+			}
+		})
+
+		t.Run("transactions are retrieved by pages", func(t *testing.T) {
+			var actual []storage.AccountTransaction
+			var cursor *storage.AccountTransactionCursor
+			pages := 0
+			for {
+				page, next, err := store.ByAddress(address, 10, 11, cursor, 4)
+				require.NoError(t, err)
+				actual = append(actual, page...)
+				pages++
+				if next == nil {
+					break
+				}
+				cursor = next
+			}
+			assert.Equal(t, 2, pages)
+			require.Len(t, actual, len(expected))
+			for i, transaction := range actual {
+				assert.Equal(t, expected[i].BlockID, transaction.BlockID)
+				assert.Equal(t, expected[i].TransactionIndex, transaction.TransactionIndex)
+			}
+		})
+
+		t.Run("transactions are retrieved within the height range", func(t *testing.T) {
+			actual, _, err := store.ByAddress(address, 11, 20, nil, 0)
+			require.NoError(t, err)
+			require.Len(t, actual, 2)
+			for _, transaction := range actual {
+				assert.Equal(t, uint64(11), transaction.BlockHeight)
+			}
+
+			actual, _, err = store.ByAddress(other, 12, 20, nil, 0)
+			require.NoError(t, err)
+			assert.Empty(t, actual)
+		})
+
+		t.Run("transactions of another block cannot be indexed", func(t *testing.T) {
+			err := store.Index(unittest.IdentifierFixture(), 12, expected[:1])
+			assert.Error(t, err)
+		})
+	})
+}
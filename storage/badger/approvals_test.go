@@ -4,17 +4,17 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestApprovalStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := bstorage.NewResultApprovals(metrics, db)
 
@@ -36,7 +36,7 @@ func TestApprovalStoreAndRetrieve(t *testing.T) {
 }
 
 func TestApprovalStoreTwice(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := bstorage.NewResultApprovals(metrics, db)
 
@@ -56,7 +56,7 @@ func TestApprovalStoreTwice(t *testing.T) {
 }
 
 func TestApprovalStoreTwoDifferentApprovalsShouldFail(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := bstorage.NewResultApprovals(metrics, db)
 
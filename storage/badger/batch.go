@@ -1,15 +1,15 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type Batch struct {
-	writer    *badger.WriteBatch
+	writer    kv.WriteBatch
 	callbacks []func()
 }
 
-func NewBatch(db *badger.DB) *Batch {
+func NewBatch(db kv.DB) *Batch {
 	batch := db.NewWriteBatch()
 	return &Batch{
 		writer:    batch,
@@ -17,7 +17,7 @@ func NewBatch(db *badger.DB) *Batch {
 	}
 }
 
-func (b *Batch) GetWriter() *badger.WriteBatch {
+func (b *Batch) GetWriter() kv.WriteBatch {
 	return b.writer
 }
 
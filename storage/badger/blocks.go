@@ -6,23 +6,22 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Blocks implements a simple block storage around a badger DB.
 type Blocks struct {
-	db       *badger.DB
+	db       kv.DB
 	headers  *Headers
 	payloads *Payloads
 }
 
 // NewBlocks ...
-func NewBlocks(db *badger.DB, headers *Headers, payloads *Payloads) *Blocks {
+func NewBlocks(db kv.DB, headers *Headers, payloads *Payloads) *Blocks {
 	b := &Blocks{
 		db:       db,
 		headers:  headers,
@@ -45,8 +44,8 @@ func (b *Blocks) StoreTx(block *flow.Block) func(*transaction.Tx) error {
 	}
 }
 
-func (b *Blocks) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.Block, error) {
-	return func(tx *badger.Txn) (*flow.Block, error) {
+func (b *Blocks) retrieveTx(blockID flow.Identifier) func(kv.Txn) (*flow.Block, error) {
+	return func(tx kv.Txn) (*flow.Block, error) {
 		header, err := b.headers.retrieveTx(blockID)(tx)
 		if err != nil {
 			return nil, fmt.Errorf("could not retrieve header: %w", err)
@@ -110,7 +109,7 @@ func (b *Blocks) IndexBlockForCollections(blockID flow.Identifier, collIDs []flo
 
 // UpdateLastFullBlockHeight upsert (update or insert) the last full block height
 func (b *Blocks) UpdateLastFullBlockHeight(height uint64) error {
-	return operation.RetryOnConflict(b.db.Update, func(tx *badger.Txn) error {
+	return operation.RetryOnConflict(b.db.Update, func(tx kv.Txn) error {
 
 		// try to update
 		err := operation.UpdateLastCompleteBlockHeight(height)(tx)
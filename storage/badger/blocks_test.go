@@ -4,18 +4,18 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestBlocks(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		store := badgerstorage.NewBlocks(db, nil, nil)
 
 		// check retrieval of non-existing key
@@ -46,7 +46,7 @@ func TestBlocks(t *testing.T) {
 }
 
 func TestBlockStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		cacheMetrics := &metrics.NoopCollector{}
 		// verify after storing a block should be able to retrieve it back
 		blocks := badgerstorage.InitAll(cacheMetrics, db).Blocks
@@ -3,23 +3,22 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // ClusterBlocks implements a simple block storage around a badger DB.
 type ClusterBlocks struct {
-	db       *badger.DB
+	db       kv.DB
 	chainID  flow.ChainID
 	headers  *Headers
 	payloads *ClusterPayloads
 }
 
-func NewClusterBlocks(db *badger.DB, chainID flow.ChainID, headers *Headers, payloads *ClusterPayloads) *ClusterBlocks {
+func NewClusterBlocks(db kv.DB, chainID flow.ChainID, headers *Headers, payloads *ClusterPayloads) *ClusterBlocks {
 	b := &ClusterBlocks{
 		db:       db,
 		chainID:  chainID,
@@ -4,19 +4,19 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
 func TestStoreRetrieveClusterPayload(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewClusterPayloads(metrics, db)
 
@@ -39,7 +39,7 @@ func TestStoreRetrieveClusterPayload(t *testing.T) {
 }
 
 func TestClusterPayloadRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewClusterPayloads(metrics, db)
 
@@ -4,20 +4,19 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type Collections struct {
-	db           *badger.DB
+	db           kv.DB
 	transactions *Transactions
 }
 
-func NewCollections(db *badger.DB, transactions *Transactions) *Collections {
+func NewCollections(db kv.DB, transactions *Transactions) *Collections {
 	c := &Collections{
 		db:           db,
 		transactions: transactions,
@@ -59,7 +58,7 @@ func (c *Collections) ByID(colID flow.Identifier) (*flow.Collection, error) {
 		collection flow.Collection
 	)
 
-	err := c.db.View(func(btx *badger.Txn) error {
+	err := c.db.View(func(btx kv.Txn) error {
 		err := operation.RetrieveCollection(colID, &light)(btx)
 		if err != nil {
 			return fmt.Errorf("could not retrieve collection: %w", err)
@@ -86,7 +85,7 @@ func (c *Collections) ByID(colID flow.Identifier) (*flow.Collection, error) {
 func (c *Collections) LightByID(colID flow.Identifier) (*flow.LightCollection, error) {
 	var collection flow.LightCollection
 
-	err := c.db.View(func(tx *badger.Txn) error {
+	err := c.db.View(func(tx kv.Txn) error {
 		err := operation.RetrieveCollection(colID, &collection)(tx)
 		if err != nil {
 			return fmt.Errorf("could not retrieve collection: %w", err)
@@ -102,7 +101,7 @@ func (c *Collections) LightByID(colID flow.Identifier) (*flow.LightCollection, e
 }
 
 func (c *Collections) Remove(colID flow.Identifier) error {
-	return operation.RetryOnConflict(c.db.Update, func(btx *badger.Txn) error {
+	return operation.RetryOnConflict(c.db.Update, func(btx kv.Txn) error {
 		err := operation.RemoveCollection(colID)(btx)
 		if err != nil {
 			return fmt.Errorf("could not remove collection: %w", err)
@@ -112,7 +111,7 @@ func (c *Collections) Remove(colID flow.Identifier) error {
 }
 
 func (c *Collections) StoreLightAndIndexByTransaction(collection *flow.LightCollection) error {
-	return operation.RetryOnConflict(c.db.Update, func(tx *badger.Txn) error {
+	return operation.RetryOnConflict(c.db.Update, func(tx kv.Txn) error {
 		err := operation.InsertCollection(collection)(tx)
 		if err != nil {
 			return fmt.Errorf("could not insert collection: %w", err)
@@ -134,7 +133,7 @@ func (c *Collections) StoreLightAndIndexByTransaction(collection *flow.LightColl
 
 func (c *Collections) LightByTransactionID(txID flow.Identifier) (*flow.LightCollection, error) {
 	var collection flow.LightCollection
-	err := c.db.View(func(tx *badger.Txn) error {
+	err := c.db.View(func(tx kv.Txn) error {
 		collID := &flow.Identifier{}
 		err := operation.RetrieveCollectionID(txID, collID)(tx)
 		if err != nil {
@@ -4,12 +4,12 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
@@ -17,7 +17,7 @@ import (
 
 // TestCommitsStoreAndRetrieve tests that a commit can be stored, retrieved and attempted to be stored again without an error
 func TestCommitsStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewCommits(metrics, db)
 
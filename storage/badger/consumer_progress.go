@@ -3,17 +3,16 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type ConsumerProgress struct {
-	db       *badger.DB
+	db       kv.DB
 	consumer string // to distinguish the consume progress between different consumers
 }
 
-func NewConsumerProgress(db *badger.DB, consumer string) *ConsumerProgress {
+func NewConsumerProgress(db kv.DB, consumer string) *ConsumerProgress {
 	return &ConsumerProgress{
 		db:       db,
 		consumer: consumer,
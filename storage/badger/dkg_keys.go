@@ -3,22 +3,21 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/dkg"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type DKGKeys struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewDKGKeys(collector module.CacheMetrics, db *badger.DB) (*DKGKeys, error) {
+func NewDKGKeys(collector module.CacheMetrics, db kv.DB) (*DKGKeys, error) {
 
 	err := operation.EnsureSecretDB(db)
 	if err != nil {
@@ -31,10 +30,10 @@ func NewDKGKeys(collector module.CacheMetrics, db *badger.DB) (*DKGKeys, error)
 		return transaction.WithTx(operation.InsertMyDKGPrivateInfo(epochCounter, info))
 	}
 
-	retrieve := func(key interface{}) func(*badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(kv.Txn) (interface{}, error) {
 		epochCounter := key.(uint64)
 		var info dkg.DKGParticipantPriv
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveMyDKGPrivateInfo(epochCounter, &info)(tx)
 			return &info, err
 		}
@@ -56,8 +55,8 @@ func (k *DKGKeys) storeTx(epochCounter uint64, info *dkg.DKGParticipantPriv) fun
 	return k.cache.PutTx(epochCounter, info)
 }
 
-func (k *DKGKeys) retrieveTx(epochCounter uint64) func(tx *badger.Txn) (*dkg.DKGParticipantPriv, error) {
-	return func(tx *badger.Txn) (*dkg.DKGParticipantPriv, error) {
+func (k *DKGKeys) retrieveTx(epochCounter uint64) func(tx kv.Txn) (*dkg.DKGParticipantPriv, error) {
+	return func(tx kv.Txn) (*dkg.DKGParticipantPriv, error) {
 		val, err := k.cache.Get(epochCounter)(tx)
 		if err != nil {
 			return nil, err
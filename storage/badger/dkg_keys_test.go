@@ -13,13 +13,15 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 // TestSecretDBRequirement tests that the DKGKeys constructor will return an
 // error if instantiated using a database not marked with the correct type.
 func TestSecretDBRequirement(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		_, err := bstorage.NewDKGKeys(metrics, db)
 		require.Error(t, err)
@@ -27,7 +29,8 @@ func TestSecretDBRequirement(t *testing.T) {
 }
 
 func TestDKGKeysInsertAndRetrieve(t *testing.T) {
-	unittest.RunWithTypedBadgerDB(t, bstorage.InitSecret, func(db *badger.DB) {
+	unittest.RunWithTypedBadgerDB(t, bstorage.InitSecret, func(secretsDB *badger.DB) {
+		db := badgerkv.New(secretsDB)
 		metrics := metrics.NewNoopCollector()
 		store, err := bstorage.NewDKGKeys(metrics, db)
 		require.NoError(t, err)
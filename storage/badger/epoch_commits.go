@@ -1,21 +1,20 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type EpochCommits struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewEpochCommits(collector module.CacheMetrics, db *badger.DB) *EpochCommits {
+func NewEpochCommits(collector module.CacheMetrics, db kv.DB) *EpochCommits {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		id := key.(flow.Identifier)
@@ -23,10 +22,10 @@ func NewEpochCommits(collector module.CacheMetrics, db *badger.DB) *EpochCommits
 		return transaction.WithTx(operation.SkipDuplicates(operation.InsertEpochCommit(id, commit)))
 	}
 
-	retrieve := func(key interface{}) func(*badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(kv.Txn) (interface{}, error) {
 		id := key.(flow.Identifier)
 		var commit flow.EpochCommit
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveEpochCommit(id, &commit)(tx)
 			return &commit, err
 		}
@@ -47,8 +46,8 @@ func (ec *EpochCommits) StoreTx(commit *flow.EpochCommit) func(*transaction.Tx)
 	return ec.cache.PutTx(commit.ID(), commit)
 }
 
-func (ec *EpochCommits) retrieveTx(commitID flow.Identifier) func(tx *badger.Txn) (*flow.EpochCommit, error) {
-	return func(tx *badger.Txn) (*flow.EpochCommit, error) {
+func (ec *EpochCommits) retrieveTx(commitID flow.Identifier) func(tx kv.Txn) (*flow.EpochCommit, error) {
+	return func(tx kv.Txn) (*flow.EpochCommit, error) {
 		val, err := ec.cache.Get(commitID)(tx)
 		if err != nil {
 			return nil, err
@@ -1,22 +1,21 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type EpochStatuses struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
 // NewEpochStatuses ...
-func NewEpochStatuses(collector module.CacheMetrics, db *badger.DB) *EpochStatuses {
+func NewEpochStatuses(collector module.CacheMetrics, db kv.DB) *EpochStatuses {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		blockID := key.(flow.Identifier)
@@ -24,10 +23,10 @@ func NewEpochStatuses(collector module.CacheMetrics, db *badger.DB) *EpochStatus
 		return transaction.WithTx(operation.InsertEpochStatus(blockID, status))
 	}
 
-	retrieve := func(key interface{}) func(*badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(kv.Txn) (interface{}, error) {
 		blockID := key.(flow.Identifier)
 		var status flow.EpochStatus
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveEpochStatus(blockID, &status)(tx)
 			return &status, err
 		}
@@ -48,8 +47,8 @@ func (es *EpochStatuses) StoreTx(blockID flow.Identifier, status *flow.EpochStat
 	return es.cache.PutTx(blockID, status)
 }
 
-func (es *EpochStatuses) retrieveTx(blockID flow.Identifier) func(tx *badger.Txn) (*flow.EpochStatus, error) {
-	return func(tx *badger.Txn) (*flow.EpochStatus, error) {
+func (es *EpochStatuses) retrieveTx(blockID flow.Identifier) func(tx kv.Txn) (*flow.EpochStatus, error) {
+	return func(tx kv.Txn) (*flow.EpochStatus, error) {
 		val, err := es.cache.Get(blockID)(tx)
 		if err != nil {
 			return nil, err
@@ -1,27 +1,27 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type Events struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewEvents(collector module.CacheMetrics, db *badger.DB) *Events {
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+func NewEvents(collector module.CacheMetrics, db kv.DB) *Events {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		blockID := key.(flow.Identifier)
 		var events []flow.Event
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.LookupEventsByBlockID(blockID, &events)(tx)
 			return events, handleError(err, flow.Event{})
 		}
@@ -109,16 +109,116 @@ func (e *Events) ByBlockIDEventType(blockID flow.Identifier, eventType flow.Even
 	return matched, nil
 }
 
+// BatchIndexByEventType indexes the events of the block at the given height by event type in a given batch
+func (e *Events) BatchIndexByEventType(blockID flow.Identifier, height uint64, blockEvents []flow.EventsList, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	for _, events := range blockEvents {
+		for _, event := range events {
+			err := operation.BatchIndexEventByType(blockID, height, event)(writeBatch)
+			if err != nil {
+				return fmt.Errorf("cannot batch index event by type: %w", err)
+			}
+		}
+	}
+
+	// mark the block as indexed, including when it has no event
+	err := operation.BatchIndexBlockEventsByType(blockID, height)(writeBatch)
+	if err != nil {
+		return fmt.Errorf("cannot batch index block events by type: %w", err)
+	}
+
+	return nil
+}
+
+// IndexedByEventType returns whether the events of the given block ID are indexed by event type
+func (e *Events) IndexedByEventType(blockID flow.Identifier) (bool, error) {
+	var height uint64
+	err := e.db.View(operation.RetrieveBlockEventsByTypeHeight(blockID, &height))
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not check block events index: %w", err)
+	}
+	return true, nil
+}
+
+// ByEventTypeHeightRange returns up to limit events of the given type emitted between the start and end heights
+// (inclusive), ordered by height, block, transaction index and event index, starting from the cursor if not nil.
+// The events of all the blocks indexed at each height are returned, including the blocks which are not finalized.
+// It returns the cursor of the next page, nil if there is none. A limit of 0 returns all the events.
+func (e *Events) ByEventTypeHeightRange(eventType flow.EventType, startHeight, endHeight uint64, cursor *storage.EventCursor, limit uint) ([]storage.IndexedEvent, *storage.EventCursor, error) {
+	from := operation.EventTypeIndexEntry{Height: startHeight}
+	if cursor != nil {
+		if cursor.Height < startHeight {
+			return nil, nil, fmt.Errorf("cursor height %d is below start height %d", cursor.Height, startHeight)
+		}
+		from = operation.EventTypeIndexEntry{
+			Height:           cursor.Height,
+			BlockID:          cursor.BlockID,
+			TransactionIndex: cursor.TransactionIndex,
+			EventIndex:       cursor.EventIndex,
+		}
+	}
+
+	var events []storage.IndexedEvent
+	var next *storage.EventCursor
+	err := e.db.View(func(tx kv.Txn) error {
+		// look up the first entry of the next page as well, which is the cursor of the next page
+		lookupLimit := limit
+		if limit > 0 {
+			lookupLimit = limit + 1
+		}
+		var entries []operation.EventTypeIndexEntry
+		err := operation.LookupEventsByType(eventType, from, endHeight, lookupLimit, &entries)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up events by type: %w", err)
+		}
+
+		if limit > 0 && uint(len(entries)) > limit {
+			last := entries[limit]
+			next = &storage.EventCursor{
+				Height:           last.Height,
+				BlockID:          last.BlockID,
+				TransactionIndex: last.TransactionIndex,
+				EventIndex:       last.EventIndex,
+			}
+			entries = entries[:limit]
+		}
+
+		events = make([]storage.IndexedEvent, 0, len(entries))
+		for _, entry := range entries {
+			var event flow.Event
+			err := operation.RetrieveEvent(entry, &event)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve indexed event: %w", err)
+			}
+			events = append(events, storage.IndexedEvent{
+				BlockID:     entry.BlockID,
+				BlockHeight: entry.Height,
+				Event:       event,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, next, nil
+}
+
 type ServiceEvents struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewServiceEvents(collector module.CacheMetrics, db *badger.DB) *ServiceEvents {
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+func NewServiceEvents(collector module.CacheMetrics, db kv.DB) *ServiceEvents {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		blockID := key.(flow.Identifier)
 		var events []flow.Event
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.LookupServiceEventsByBlockID(blockID, &events)(tx)
 			return events, handleError(err, flow.Event{})
 		}
@@ -3,18 +3,20 @@ package badger_test
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/fvm/systemcontracts"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestEventStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewEvents(metrics, db)
 
@@ -85,7 +87,7 @@ func TestEventStoreRetrieve(t *testing.T) {
 }
 
 func TestEventRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewEvents(metrics, db)
 
@@ -109,3 +111,82 @@ func TestEventRetrieveWithoutStore(t *testing.T) {
 
 	})
 }
+
+func TestEventsByEventTypeHeightRange(t *testing.T) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
+		store := badgerstorage.NewEvents(metrics.NewNoopCollector(), db)
+
+		// two conflicting blocks at height 11, and a block without events at height 12
+		blockIDs := unittest.IdentifierListFixture(4)
+		heights := []uint64{10, 11, 11, 12}
+		txID := unittest.IdentifierFixture()
+		blockEvents := [][]flow.EventsList{
+			{{
+				unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID, 0),
+				unittest.EventFixture(flow.EventAccountUpdated, 0, 1, txID, 0),
+				unittest.EventFixture(flow.EventAccountCreated, 0, 2, txID, 0),
+			}},
+			{{unittest.EventFixture(flow.EventAccountCreated, 1, 0, txID, 0)}},
+			{{unittest.EventFixture(flow.EventAccountCreated, 1, 0, txID, 0)}},
+			{},
+		}
+
+		batch := badgerstorage.NewBatch(db)
+		for i, blockID := range blockIDs {
+			require.NoError(t, store.BatchStore(blockID, blockEvents[i], batch))
+			require.NoError(t, store.BatchIndexByEventType(blockID, heights[i], blockEvents[i], batch))
+		}
+		require.NoError(t, batch.Flush())
+
+		for _, blockID := range blockIDs {
+			indexed, err := store.IndexedByEventType(blockID)
+			require.NoError(t, err)
+			assert.True(t, indexed)
+		}
+		indexed, err := store.IndexedByEventType(unittest.IdentifierFixture())
+		require.NoError(t, err)
+		assert.False(t, indexed)
+
+		// all the events of the type in the range, ordered by height
+		events, next, err := store.ByEventTypeHeightRange(flow.EventAccountCreated, 10, 12, nil, 0)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		require.Len(t, events, 4)
+		assert.Equal(t, blockEvents[0][0][0], events[0].Event)
+		assert.Equal(t, blockEvents[0][0][2], events[1].Event)
+		assert.Equal(t, blockIDs[0], events[0].BlockID)
+		assert.Equal(t, uint64(10), events[0].BlockHeight)
+		assert.ElementsMatch(t, blockIDs[1:3], []flow.Identifier{events[2].BlockID, events[3].BlockID})
+
+		// the events of the other types and heights are not returned
+		events, _, err = store.ByEventTypeHeightRange(flow.EventAccountUpdated, 11, 12, nil, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		// the pages resume from their cursor
+		var paged []storage.IndexedEvent
+		var cursor *storage.EventCursor
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 3)
+			events, cursor, err = store.ByEventTypeHeightRange(flow.EventAccountCreated, 10, 12, cursor, 2)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(events), 2)
+			paged = append(paged, events...)
+			if cursor == nil {
+				break
+			}
+		}
+		all, _, err := store.ByEventTypeHeightRange(flow.EventAccountCreated, 10, 12, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, all, paged)
+
+		// the pages resume from a cursor at the end height
+		events, cursor, err = store.ByEventTypeHeightRange(flow.EventAccountCreated, 10, 11, nil, 3)
+		require.NoError(t, err)
+		require.NotNil(t, cursor)
+		events, cursor, err = store.ByEventTypeHeightRange(flow.EventAccountCreated, 10, 11, cursor, 3)
+		require.NoError(t, err)
+		assert.Nil(t, cursor)
+		assert.Equal(t, all[3:], events)
+	})
+}
@@ -1,22 +1,21 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Guarantees implements persistent storage for collection guarantees.
 type Guarantees struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewGuarantees(collector module.CacheMetrics, db *badger.DB, cacheSize uint) *Guarantees {
+func NewGuarantees(collector module.CacheMetrics, db kv.DB, cacheSize uint) *Guarantees {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		collID := key.(flow.Identifier)
@@ -24,10 +23,10 @@ func NewGuarantees(collector module.CacheMetrics, db *badger.DB, cacheSize uint)
 		return transaction.WithTx(operation.SkipDuplicates(operation.InsertGuarantee(collID, guarantee)))
 	}
 
-	retrieve := func(key interface{}) func(*badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(kv.Txn) (interface{}, error) {
 		collID := key.(flow.Identifier)
 		var guarantee flow.CollectionGuarantee
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveGuarantee(collID, &guarantee)(tx)
 			return &guarantee, err
 		}
@@ -48,8 +47,8 @@ func (g *Guarantees) storeTx(guarantee *flow.CollectionGuarantee) func(*transact
 	return g.cache.PutTx(guarantee.ID(), guarantee)
 }
 
-func (g *Guarantees) retrieveTx(collID flow.Identifier) func(*badger.Txn) (*flow.CollectionGuarantee, error) {
-	return func(tx *badger.Txn) (*flow.CollectionGuarantee, error) {
+func (g *Guarantees) retrieveTx(collID flow.Identifier) func(kv.Txn) (*flow.CollectionGuarantee, error) {
+	return func(tx kv.Txn) (*flow.CollectionGuarantee, error) {
 		val, err := g.cache.Get(collID)(tx)
 		if err != nil {
 			return nil, err
@@ -5,8 +5,6 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
@@ -14,17 +12,18 @@ import (
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Headers implements a simple read-only header storage around a badger DB.
 type Headers struct {
-	db           *badger.DB
+	db           kv.DB
 	cache        *Cache
 	heightCache  *Cache
 	chunkIDCache *Cache
 }
 
-func NewHeaders(collector module.CacheMetrics, db *badger.DB) *Headers {
+func NewHeaders(collector module.CacheMetrics, db kv.DB) *Headers {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		blockID := key.(flow.Identifier)
@@ -46,28 +45,28 @@ func NewHeaders(collector module.CacheMetrics, db *badger.DB) *Headers {
 		return transaction.WithTx(operation.IndexBlockIDByChunkID(chunkID, blockID))
 	}
 
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		blockID := key.(flow.Identifier)
 		var header flow.Header
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveHeader(blockID, &header)(tx)
 			return &header, err
 		}
 	}
 
-	retrieveHeight := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieveHeight := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		height := key.(uint64)
 		var id flow.Identifier
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.LookupBlockHeight(height, &id)(tx)
 			return id, err
 		}
 	}
 
-	retrieveChunkID := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieveChunkID := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		chunkID := key.(flow.Identifier)
 		var blockID flow.Identifier
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.LookupBlockIDByChunkID(chunkID, &blockID)(tx)
 			return blockID, err
 		}
@@ -97,8 +96,8 @@ func (h *Headers) storeTx(header *flow.Header) func(*transaction.Tx) error {
 	return h.cache.PutTx(header.ID(), header)
 }
 
-func (h *Headers) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.Header, error) {
-	return func(tx *badger.Txn) (*flow.Header, error) {
+func (h *Headers) retrieveTx(blockID flow.Identifier) func(kv.Txn) (*flow.Header, error) {
+	return func(tx kv.Txn) (*flow.Header, error) {
 		val, err := h.cache.Get(blockID)(tx)
 		if err != nil {
 			return nil, err
@@ -107,8 +106,8 @@ func (h *Headers) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.H
 	}
 }
 
-func (h *Headers) retrieveIdByHeightTx(height uint64) func(*badger.Txn) (flow.Identifier, error) {
-	return func(tx *badger.Txn) (flow.Identifier, error) {
+func (h *Headers) retrieveIdByHeightTx(height uint64) func(kv.Txn) (flow.Identifier, error) {
+	return func(tx kv.Txn) (flow.Identifier, error) {
 		blockID, err := h.heightCache.Get(height)(tx)
 		if err != nil {
 			return flow.ZeroID, fmt.Errorf("failed to retrieve block ID for height %d: %w", height, err)
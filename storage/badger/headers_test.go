@@ -6,18 +6,18 @@ import (
 
 	"github.com/onflow/flow-go/storage/badger/operation"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
 func TestHeaderStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		headers := badgerstorage.NewHeaders(metrics, db)
 
@@ -39,7 +39,7 @@ func TestHeaderStoreRetrieve(t *testing.T) {
 }
 
 func TestHeaderRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		metrics := metrics.NewNoopCollector()
 		headers := badgerstorage.NewHeaders(metrics, db)
 
@@ -3,23 +3,22 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/procedure"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Index implements a simple read-only payload storage around a badger DB.
 type Index struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewIndex(collector module.CacheMetrics, db *badger.DB) *Index {
+func NewIndex(collector module.CacheMetrics, db kv.DB) *Index {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		blockID := key.(flow.Identifier)
@@ -27,10 +26,10 @@ func NewIndex(collector module.CacheMetrics, db *badger.DB) *Index {
 		return transaction.WithTx(procedure.InsertIndex(blockID, index))
 	}
 
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		blockID := key.(flow.Identifier)
 		var index flow.Index
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := procedure.RetrieveIndex(blockID, &index)(tx)
 			return &index, err
 		}
@@ -51,8 +50,8 @@ func (i *Index) storeTx(blockID flow.Identifier, index *flow.Index) func(*transa
 	return i.cache.PutTx(blockID, index)
 }
 
-func (i *Index) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.Index, error) {
-	return func(tx *badger.Txn) (*flow.Index, error) {
+func (i *Index) retrieveTx(blockID flow.Identifier) func(kv.Txn) (*flow.Index, error) {
+	return func(tx kv.Txn) (*flow.Index, error) {
 		val, err := i.cache.Get(blockID)(tx)
 		if err != nil {
 			return nil, err
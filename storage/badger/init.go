@@ -6,6 +6,7 @@ import (
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 )
 
 // InitPublic initializes a public database by checking and setting the database
@@ -18,7 +19,7 @@ func InitPublic(opts badger.Options) (*badger.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not open db: %w", err)
 	}
-	err = db.Update(operation.InsertPublicDBMarker)
+	err = badgerkv.New(db).Update(operation.InsertPublicDBMarker)
 	if err != nil {
 		return nil, fmt.Errorf("could not assert db type: %w", err)
 	}
@@ -36,7 +37,7 @@ func InitSecret(opts badger.Options) (*badger.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not open db: %w", err)
 	}
-	err = db.Update(operation.InsertSecretDBMarker)
+	err = badgerkv.New(db).Update(operation.InsertSecretDBMarker)
 	if err != nil {
 		return nil, fmt.Errorf("could not assert db type: %w", err)
 	}
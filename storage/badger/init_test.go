@@ -8,23 +8,24 @@ import (
 
 	bstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestInitPublic(t *testing.T) {
 	unittest.RunWithTypedBadgerDB(t, bstorage.InitPublic, func(db *badger.DB) {
-		err := operation.EnsurePublicDB(db)
+		err := operation.EnsurePublicDB(badgerkv.New(db))
 		require.NoError(t, err)
-		err = operation.EnsureSecretDB(db)
+		err = operation.EnsureSecretDB(badgerkv.New(db))
 		require.Error(t, err)
 	})
 }
 
 func TestInitSecret(t *testing.T) {
 	unittest.RunWithTypedBadgerDB(t, bstorage.InitSecret, func(db *badger.DB) {
-		err := operation.EnsureSecretDB(db)
+		err := operation.EnsureSecretDB(badgerkv.New(db))
 		require.NoError(t, err)
-		err = operation.EnsurePublicDB(db)
+		err = operation.EnsurePublicDB(badgerkv.New(db))
 		require.Error(t, err)
 	})
 }
@@ -0,0 +1,223 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
+)
+
+// ErrUnknownVersion is returned when the database follows a schema version which is newer than the latest
+// version known by the node, i.e. it was written by a newer software version.
+var ErrUnknownVersion = errors.New("unknown schema version")
+
+// Migration transforms the database content from the previous schema version to its own version.
+//
+// A migration is applied in a single transaction, together with the update of the schema version, so that the
+// database is never left half-migrated. Its changes must fit in a badger transaction.
+type Migration struct {
+	Version     uint64             // schema version the database follows once migrated
+	Description string             // short description of the schema changes
+	Apply       func(kv.Txn) error // transforms the database content
+}
+
+// Config is the configuration of the migration of a database.
+type Config struct {
+	DryRun    bool   // whether the migrations are applied in transactions which are discarded
+	BackupDir string // directory of the backup taken before applying the migrations, empty to skip the backup, only supported for badger databases
+}
+
+// Migrator applies an ordered list of migrations to bring a database to the latest schema version.
+type Migrator struct {
+	log        zerolog.Logger
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator applying the given migrations, which must have consecutive versions starting
+// at 1. The databases created before the schema was versioned follow the version 0.
+func NewMigrator(log zerolog.Logger, migrations []Migration) (*Migrator, error) {
+	for i, migration := range migrations {
+		if migration.Version != uint64(i+1) {
+			return nil, fmt.Errorf("migration %d has version %d, expected %d", i, migration.Version, i+1)
+		}
+		if migration.Apply == nil {
+			return nil, fmt.Errorf("migration to version %d has no apply function", migration.Version)
+		}
+	}
+
+	return &Migrator{
+		log:        log.With().Str("component", "db_migrator").Logger(),
+		migrations: migrations,
+	}, nil
+}
+
+// LatestVersion returns the latest schema version, to which the databases are migrated.
+func (m *Migrator) LatestVersion() uint64 {
+	return uint64(len(m.migrations))
+}
+
+// Pending returns the migrations which are not applied to a database following the given schema version. It
+// returns ErrUnknownVersion if the version is newer than the latest version.
+func (m *Migrator) Pending(version uint64) ([]Migration, error) {
+	if version > m.LatestVersion() {
+		return nil, fmt.Errorf("database schema version %d is newer than the latest version %d: %w", version, m.LatestVersion(), ErrUnknownVersion)
+	}
+	return m.migrations[version:], nil
+}
+
+// Version returns the schema version of the database, 0 if it was created before the schema was versioned.
+func Version(db kv.DB) (uint64, error) {
+	var version uint64
+	err := db.View(operation.RetrieveSchemaVersion(&version))
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Migrate brings the database to the latest schema version, by applying the pending migrations in order. A
+// database which was never bootstrapped has no content to migrate, so it is marked with the latest version
+// directly. It returns ErrUnknownVersion if the database follows a newer schema version, in which case the
+// node must not start.
+func (m *Migrator) Migrate(db kv.DB, config Config) error {
+	var version uint64
+	err := db.View(operation.RetrieveSchemaVersion(&version))
+	if errors.Is(err, storage.ErrNotFound) {
+		bootstrapped, err := isBootstrapped(db)
+		if err != nil {
+			return err
+		}
+		if !bootstrapped {
+			if config.DryRun {
+				m.log.Info().Uint64("version", m.LatestVersion()).Msg("dry run: empty database would be marked with the latest schema version")
+				return nil
+			}
+			err = db.Update(operation.InsertSchemaVersion(m.LatestVersion()))
+			if err != nil {
+				return fmt.Errorf("could not insert schema version: %w", err)
+			}
+			return nil
+		}
+		// otherwise the database was created before the schema was versioned
+	} else if err != nil {
+		return fmt.Errorf("could not retrieve schema version: %w", err)
+	}
+
+	pending, err := m.Pending(version)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		m.log.Debug().Uint64("version", version).Msg("database schema is up to date")
+		return nil
+	}
+
+	if config.BackupDir != "" && !config.DryRun {
+		err = backup(db, config.BackupDir, version)
+		if err != nil {
+			return fmt.Errorf("could not back up database before migrating: %w", err)
+		}
+	}
+
+	// on a dry run, the migrations are applied in order in a single transaction which is discarded, so that
+	// each one sees the changes of the previous ones
+	var dryRun kv.Transaction
+	if config.DryRun {
+		dryRun = db.NewTransaction(true)
+		defer dryRun.Discard()
+	}
+
+	for _, migration := range pending {
+		log := m.log.With().
+			Uint64("version", migration.Version).
+			Str("description", migration.Description).
+			Bool("dry_run", config.DryRun).
+			Logger()
+		log.Info().Msg("applying database migration")
+
+		started := time.Now()
+		if dryRun != nil {
+			err = apply(migration)(dryRun)
+		} else {
+			err = db.Update(apply(migration))
+		}
+		if err != nil {
+			return fmt.Errorf("could not apply migration to version %d: %w", migration.Version, err)
+		}
+
+		log.Info().Dur("duration", time.Since(started)).Msg("database migration applied")
+	}
+
+	return nil
+}
+
+// apply applies a migration and updates the schema version in the same transaction. The version is only
+// inserted by the first migration, as the databases following the version 0 have none stored.
+func apply(migration Migration) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
+		err := migration.Apply(tx)
+		if err != nil {
+			return err
+		}
+		if migration.Version == 1 {
+			err = operation.InsertSchemaVersion(migration.Version)(tx)
+		} else {
+			err = operation.UpdateSchemaVersion(migration.Version)(tx)
+		}
+		if err != nil {
+			return fmt.Errorf("could not update schema version: %w", err)
+		}
+		return nil
+	}
+}
+
+// backup writes a full backup of the database to a new file in the given directory, which can be restored
+// with the badger restore command.
+func backup(db kv.DB, dir string, version uint64) error {
+	bdb, ok := db.(*badgerkv.DB)
+	if !ok {
+		return fmt.Errorf("backups are only supported for badger databases, not %T", db)
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("could not create backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("protocol-schema-v%d-%d.bak", version, time.Now().Unix()))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create backup file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = bdb.Badger().Backup(file, 0)
+	if err != nil {
+		return fmt.Errorf("could not write backup to %s: %w", path, err)
+	}
+	return file.Sync()
+}
+
+// isBootstrapped returns whether the database holds a bootstrapped protocol state.
+func isBootstrapped(db kv.DB) (bool, error) {
+	var height uint64
+	err := db.View(operation.RetrieveRootHeight(&height))
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve root height: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,212 @@
+package migration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// testMigrations returns migrations which each record the version they were applied at, and check that the
+// previous ones were applied before.
+func testMigrations(count int) []Migration {
+	var migrations []Migration
+	for i := 1; i <= count; i++ {
+		version := uint64(i)
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: "test migration",
+			Apply: func(tx kv.Txn) error {
+				if version > 1 {
+					err := tx.Get(migratedKey(version-1), func([]byte) error { return nil })
+					if err != nil {
+						return err
+					}
+				}
+				return tx.Set(migratedKey(version), nil)
+			},
+		})
+	}
+	return migrations
+}
+
+func migratedKey(version uint64) []byte {
+	return []byte{0xfe, byte(version)}
+}
+
+func isMigrated(t *testing.T, db kv.DB, version uint64) bool {
+	err := db.View(func(tx kv.Txn) error {
+		return tx.Get(migratedKey(version), func([]byte) error { return nil })
+	})
+	if errors.Is(err, kv.ErrNotFound) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func bootstrap(t *testing.T, db kv.DB) {
+	require.NoError(t, db.Update(operation.InsertRootHeight(1)))
+}
+
+func TestNewMigrator(t *testing.T) {
+	_, err := NewMigrator(zerolog.Nop(), testMigrations(3))
+	require.NoError(t, err)
+
+	_, err = NewMigrator(zerolog.Nop(), testMigrations(3)[1:])
+	assert.Error(t, err)
+
+	migrations := testMigrations(2)
+	migrations[1].Apply = nil
+	_, err = NewMigrator(zerolog.Nop(), migrations)
+	assert.Error(t, err)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("unversioned database is migrated from the version 0", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			bootstrap(t, db)
+
+			migrator, err := NewMigrator(zerolog.Nop(), testMigrations(3))
+			require.NoError(t, err)
+			require.NoError(t, migrator.Migrate(db, Config{}))
+
+			version, err := Version(db)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(3), version)
+			for v := uint64(1); v <= 3; v++ {
+				assert.True(t, isMigrated(t, db, v))
+			}
+		})
+	})
+
+	t.Run("only pending migrations are applied", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			bootstrap(t, db)
+
+			migrator, err := NewMigrator(zerolog.Nop(), testMigrations(1))
+			require.NoError(t, err)
+			require.NoError(t, migrator.Migrate(db, Config{}))
+			require.NoError(t, db.Update(func(tx kv.Txn) error {
+				return tx.Delete(migratedKey(1))
+			}))
+
+			migrations := testMigrations(2)
+			migrations[1].Apply = func(tx kv.Txn) error {
+				return tx.Set(migratedKey(2), nil)
+			}
+			migrator, err = NewMigrator(zerolog.Nop(), migrations)
+			require.NoError(t, err)
+			require.NoError(t, migrator.Migrate(db, Config{}))
+
+			version, err := Version(db)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), version)
+			assert.False(t, isMigrated(t, db, 1))
+			assert.True(t, isMigrated(t, db, 2))
+		})
+	})
+
+	t.Run("empty database is marked with the latest version", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			migrator, err := NewMigrator(zerolog.Nop(), testMigrations(3))
+			require.NoError(t, err)
+			require.NoError(t, migrator.Migrate(db, Config{}))
+
+			version, err := Version(db)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(3), version)
+			assert.False(t, isMigrated(t, db, 1))
+		})
+	})
+
+	t.Run("dry run does not change the database", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			bootstrap(t, db)
+
+			migrator, err := NewMigrator(zerolog.Nop(), testMigrations(3))
+			require.NoError(t, err)
+			require.NoError(t, migrator.Migrate(db, Config{DryRun: true}))
+
+			version, err := Version(db)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(0), version)
+			assert.False(t, isMigrated(t, db, 1))
+		})
+	})
+
+	t.Run("failed migration is not committed", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			bootstrap(t, db)
+
+			migrations := testMigrations(2)
+			migrations[1].Apply = func(tx kv.Txn) error {
+				err := tx.Set(migratedKey(2), nil)
+				require.NoError(t, err)
+				return errors.New("migration failed")
+			}
+			migrator, err := NewMigrator(zerolog.Nop(), migrations)
+			require.NoError(t, err)
+			require.Error(t, migrator.Migrate(db, Config{}))
+
+			version, err := Version(db)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), version)
+			assert.True(t, isMigrated(t, db, 1))
+			assert.False(t, isMigrated(t, db, 2))
+		})
+	})
+
+	t.Run("newer schema version is refused", func(t *testing.T) {
+		unittest.RunWithEachDB(t, func(db kv.DB) {
+			bootstrap(t, db)
+			require.NoError(t, db.Update(operation.InsertSchemaVersion(4)))
+
+			migrator, err := NewMigrator(zerolog.Nop(), testMigrations(3))
+			require.NoError(t, err)
+			err = migrator.Migrate(db, Config{})
+			assert.True(t, errors.Is(err, ErrUnknownVersion))
+		})
+	})
+
+	t.Run("database is backed up before migrating", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(badgerDB *badger.DB) {
+			unittest.RunWithTempDir(t, func(dir string) {
+				db := badgerkv.New(badgerDB)
+				bootstrap(t, db)
+
+				migrator, err := NewMigrator(zerolog.Nop(), testMigrations(1))
+				require.NoError(t, err)
+				require.NoError(t, migrator.Migrate(db, Config{BackupDir: dir}))
+
+				files, err := ioutil.ReadDir(dir)
+				require.NoError(t, err)
+				require.Len(t, files, 1)
+
+				// the backup holds the content before the migration
+				unittest.RunWithBadgerDB(t, func(restored *badger.DB) {
+					file, err := os.Open(filepath.Join(dir, files[0].Name()))
+					require.NoError(t, err)
+					defer file.Close()
+					require.NoError(t, restored.Load(file, 16))
+
+					var height uint64
+					require.NoError(t, badgerkv.New(restored).View(operation.RetrieveRootHeight(&height)))
+					assert.Equal(t, uint64(1), height)
+					assert.False(t, isMigrated(t, badgerkv.New(restored), 1))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,16 @@
+package migration
+
+import (
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// Migrations is the registry of the migrations of the protocol database, ordered by version. New migrations
+// are appended with the next version, and are never changed once released, since the databases record which
+// versions they were migrated to.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "baseline schema of the databases created before the schema was versioned",
+		Apply:       func(kv.Txn) error { return nil },
+	},
+}
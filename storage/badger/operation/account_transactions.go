@@ -0,0 +1,96 @@
+package operation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// accountTransactionIndexKeySize is the size of the keys of the index of the transactions by account, i.e. the
+// code, the address, the height, the block ID and the transaction index.
+const accountTransactionIndexKeySize = 1 + flow.AddressLength + 8 + idSize + 4
+
+// accountTransactionValue is the value of an entry of the index of the transactions by account.
+type accountTransactionValue struct {
+	TransactionID flow.Identifier
+	Roles         storage.AccountRoles
+}
+
+// BatchIndexAccountTransaction indexes the transaction under the account it touches. The block ID is part of
+// the key, so that the transactions of conflicting blocks at the same height are all indexed.
+func BatchIndexAccountTransaction(transaction storage.AccountTransaction) func(batch kv.WriteBatch) error {
+	key := makePrefix(codeIndexTransactionByAccount, transaction.Address, transaction.BlockHeight, transaction.BlockID, transaction.TransactionIndex)
+	return batchInsert(key, accountTransactionValue{
+		TransactionID: transaction.TransactionID,
+		Roles:         transaction.Roles,
+	})
+}
+
+// LookupAccountTransactions looks up the transactions touching the account, from the position of the cursor
+// up to the end height (inclusive), ordered by height, block ID and transaction index. A cursor with only a
+// height starts at the first transaction of this height. It looks up at most limit transactions, all of them
+// if limit is 0.
+func LookupAccountTransactions(address flow.Address, from storage.AccountTransactionCursor, endHeight uint64, limit uint, transactions *[]storage.AccountTransaction) func(kv.Txn) error {
+	if from.Height > endHeight {
+		// iterating from a higher key would iterate in reverse order
+		return func(kv.Txn) error { return nil }
+	}
+
+	startKey := makePrefix(codeIndexTransactionByAccount, address, from.Height, from.BlockID, from.TransactionIndex)
+	endKey := makePrefix(codeIndexTransactionByAccount, address, endHeight)
+	// pad the end key to the size of the start key, so that a start key within the end height is not iterated
+	// in reverse order
+	endKey = append(endKey, bytes.Repeat([]byte{0xff}, len(startKey)-len(endKey))...)
+
+	return iterate(startKey, endKey, func() (checkFunc, createFunc, handleFunc) {
+		var key []byte
+		check := func(k []byte) bool {
+			// the values of the keys beyond the limit are not loaded
+			if limit > 0 && uint(len(*transactions)) >= limit {
+				return false
+			}
+			key = k
+			return true
+		}
+		var value accountTransactionValue
+		create := func() interface{} {
+			return &value
+		}
+		handle := func() error {
+			transaction, err := accountTransaction(key, value)
+			if err != nil {
+				return err
+			}
+			*transactions = append(*transactions, transaction)
+			return nil
+		}
+		return check, create, handle
+	})
+}
+
+// accountTransaction decodes an entry of the index of the transactions by account.
+func accountTransaction(key []byte, value accountTransactionValue) (storage.AccountTransaction, error) {
+	if len(key) != accountTransactionIndexKeySize {
+		return storage.AccountTransaction{}, fmt.Errorf("invalid account transaction index key size %d", len(key))
+	}
+
+	// skip the code
+	key = key[1:]
+
+	transaction := storage.AccountTransaction{
+		Address:       flow.BytesToAddress(key[:flow.AddressLength]),
+		TransactionID: value.TransactionID,
+		Roles:         value.Roles,
+	}
+	key = key[flow.AddressLength:]
+	transaction.BlockHeight = binary.BigEndian.Uint64(key)
+	key = key[8:]
+	copy(transaction.BlockID[:], key)
+	transaction.TransactionIndex = binary.BigEndian.Uint32(key[idSize:])
+
+	return transaction, nil
+}
@@ -1,22 +1,45 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // InsertBlockChildren insert an index to lookup the direct child of a block by its ID
-func InsertBlockChildren(blockID flow.Identifier, childrenIDs []flow.Identifier) func(*badger.Txn) error {
+func InsertBlockChildren(blockID flow.Identifier, childrenIDs []flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeBlockChildren, blockID), childrenIDs)
 }
 
 // UpdateBlockChildren updates the children for a block.
-func UpdateBlockChildren(blockID flow.Identifier, childrenIDs []flow.Identifier) func(*badger.Txn) error {
+func UpdateBlockChildren(blockID flow.Identifier, childrenIDs []flow.Identifier) func(kv.Txn) error {
 	return update(makePrefix(codeBlockChildren, blockID), childrenIDs)
 }
 
 // RetrieveBlockChildren the child block ID by parent block ID
-func RetrieveBlockChildren(blockID flow.Identifier, childrenIDs *[]flow.Identifier) func(*badger.Txn) error {
+func RetrieveBlockChildren(blockID flow.Identifier, childrenIDs *[]flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeBlockChildren, blockID), childrenIDs)
 }
+
+// RemoveBlockChildren removes the children index of a block.
+func RemoveBlockChildren(blockID flow.Identifier) func(kv.Txn) error {
+	return remove(makePrefix(codeBlockChildren, blockID))
+}
+
+// TraverseBlockChildren calls the given function on each block whose children are indexed, with the IDs of
+// its children.
+func TraverseBlockChildren(handle func(blockID flow.Identifier, childrenIDs []flow.Identifier) error) func(kv.Txn) error {
+	return traverse(makePrefix(codeBlockChildren), func() (checkFunc, createFunc, handleFunc) {
+		var blockID flow.Identifier
+		check := func(key []byte) bool {
+			copy(blockID[:], key[1:])
+			return true
+		}
+		var childrenIDs []flow.Identifier
+		create := func() interface{} {
+			return &childrenIDs
+		}
+		return check, create, func() error {
+			return handle(blockID, childrenIDs)
+		}
+	})
+}
@@ -1,16 +1,15 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/chunks"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertChunkLocator(locator *chunks.Locator) func(*badger.Txn) error {
+func InsertChunkLocator(locator *chunks.Locator) func(kv.Txn) error {
 	return insert(makePrefix(codeChunk, locator.ID()), locator)
 }
 
-func RetrieveChunkLocator(locatorID flow.Identifier, locator *chunks.Locator) func(*badger.Txn) error {
+func RetrieveChunkLocator(locatorID flow.Identifier, locator *chunks.Locator) func(kv.Txn) error {
 	return retrieve(makePrefix(codeChunk, locatorID), locator)
 }
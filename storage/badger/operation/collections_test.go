@@ -5,16 +5,16 @@ package operation
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestCollections(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		expected := unittest.CollectionFixture(2).Light()
 
 		t.Run("Retrieve nonexistant", func(t *testing.T) {
@@ -47,7 +47,7 @@ func TestCollections(t *testing.T) {
 			expected := unittest.CollectionFixture(1).Light()
 			blockID := unittest.IdentifierFixture()
 
-			_ = db.Update(func(tx *badger.Txn) error {
+			_ = db.Update(func(tx kv.Txn) error {
 				err := InsertCollection(&expected)(tx)
 				assert.Nil(t, err)
 				err = IndexCollectionPayload(blockID, expected.Transactions)(tx)
@@ -67,7 +67,7 @@ func TestCollections(t *testing.T) {
 			transactionID := unittest.IdentifierFixture()
 			actual := flow.Identifier{}
 
-			_ = db.Update(func(tx *badger.Txn) error {
+			_ = db.Update(func(tx kv.Txn) error {
 				err := IndexCollectionByTransaction(transactionID, expected)(tx)
 				assert.Nil(t, err)
 				err = RetrieveCollectionID(transactionID, &actual)(tx)
@@ -3,28 +3,27 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // IndexStateCommitment indexes a state commitment.
 //
 // State commitments are keyed by the block whose execution results in the state with the given commit.
-func IndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(*badger.Txn) error {
+func IndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(kv.Txn) error {
 	return insert(makePrefix(codeCommit, blockID), commit)
 }
 
 // BatchIndexStateCommitment indexes a state commitment into a batch
 //
 // State commitments are keyed by the block whose execution results in the state with the given commit.
-func BatchIndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(batch *badger.WriteBatch) error {
+func BatchIndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeCommit, blockID), commit)
 }
 
 // LookupStateCommitment gets a state commitment keyed by block ID
 //
 // State commitments are keyed by the block whose execution results in the state with the given commit.
-func LookupStateCommitment(blockID flow.Identifier, commit *flow.StateCommitment) func(*badger.Txn) error {
+func LookupStateCommitment(blockID flow.Identifier, commit *flow.StateCommitment) func(kv.Txn) error {
 	return retrieve(makePrefix(codeCommit, blockID), commit)
 }
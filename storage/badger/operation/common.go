@@ -7,18 +7,18 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/vmihailenco/msgpack/v4"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // batchInsert will encode the given entity using msgpack and will upsert the resulting
 // binary data in the badger wrote batch under the provided key - if the value already exists
 // in the database it will be overridden
-func batchInsert(key []byte, entity interface{}) func(writeBatch *badger.WriteBatch) error {
-	return func(writeBatch *badger.WriteBatch) error {
+func batchInsert(key []byte, entity interface{}) func(writeBatch kv.WriteBatch) error {
+	return func(writeBatch kv.WriteBatch) error {
 
 		// update the maximum key size if the inserted key is bigger
 		if uint32(len(key)) > max {
@@ -47,8 +47,8 @@ func batchInsert(key []byte, entity interface{}) func(writeBatch *badger.WriteBa
 // insert will encode the given entity using msgpack and will insert the resulting
 // binary data in the badger DB under the provided key. It will error if the
 // key already exists.
-func insert(key []byte, entity interface{}) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func insert(key []byte, entity interface{}) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 
 		// update the maximum key size if the inserted key is bigger
 		if uint32(len(key)) > max {
@@ -60,12 +60,12 @@ func insert(key []byte, entity interface{}) func(*badger.Txn) error {
 		}
 
 		// check if the key already exists in the db
-		_, err := tx.Get(key)
+		err := tx.Get(key, noop)
 		if err == nil {
 			return storage.ErrAlreadyExists
 		}
 
-		if !errors.Is(err, badger.ErrKeyNotFound) {
+		if !errors.Is(err, kv.ErrNotFound) {
 			return fmt.Errorf("could not retrieve key: %w", err)
 		}
 
@@ -87,12 +87,12 @@ func insert(key []byte, entity interface{}) func(*badger.Txn) error {
 // update will encode the given entity with JSON and update the binary data
 // under the given key in the badger DB. It will error if the key does not exist
 // yet.
-func update(key []byte, entity interface{}) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func update(key []byte, entity interface{}) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 
 		// retrieve the item from the key-value store
-		_, err := tx.Get(key)
-		if errors.Is(err, badger.ErrKeyNotFound) {
+		err := tx.Get(key, noop)
+		if errors.Is(err, kv.ErrNotFound) {
 			return storage.ErrNotFound
 		}
 		if err != nil {
@@ -117,11 +117,11 @@ func update(key []byte, entity interface{}) func(*badger.Txn) error {
 
 // remove removes the entity with the given key, if it exists. If it doesn't
 // exist, this is a no-op.
-func remove(key []byte) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func remove(key []byte) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 		// retrieve the item from the key-value store
-		_, err := tx.Get(key)
-		if errors.Is(err, badger.ErrKeyNotFound) {
+		err := tx.Get(key, noop)
+		if errors.Is(err, kv.ErrNotFound) {
 			return storage.ErrNotFound
 		}
 		if err != nil {
@@ -133,34 +133,79 @@ func remove(key []byte) func(*badger.Txn) error {
 	}
 }
 
+// batchRemove removes the entity with the given key in the badger write batch, if it exists, and adds the size
+// of its key and value to the reclaimed bytes. The key is looked up in the given transaction.
+func batchRemove(key []byte, writeBatch kv.WriteBatch, reclaimed *uint64) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
+		// the key is looked up with an iterator to estimate its size without loading its value
+		it := tx.NewIterator(kv.IteratorOptions{Prefix: key, KeysOnly: true})
+		defer it.Close()
+
+		it.Seek(key)
+		if !it.Valid() || !bytes.Equal(it.Key(), key) {
+			return nil
+		}
+
+		err := writeBatch.Delete(key)
+		if err != nil {
+			return fmt.Errorf("could not remove data: %w", err)
+		}
+		*reclaimed += uint64(it.EstimatedSize())
+		return nil
+	}
+}
+
+// batchRemoveByPrefix removes all the entities whose keys have the given prefix in the badger write batch, and
+// adds the size of their keys and values to the reclaimed bytes. The keys are looked up in the given transaction.
+func batchRemoveByPrefix(prefix []byte, writeBatch kv.WriteBatch, reclaimed *uint64) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
+		it := tx.NewIterator(kv.IteratorOptions{Prefix: prefix, KeysOnly: true})
+		defer it.Close()
+
+		for it.Seek(prefix); it.Valid(); it.Next() {
+			// the key is only valid until the iterator moves on
+			err := writeBatch.Delete(append([]byte(nil), it.Key()...))
+			if err != nil {
+				return fmt.Errorf("could not remove data: %w", err)
+			}
+			*reclaimed += uint64(it.EstimatedSize())
+		}
+
+		return nil
+	}
+}
+
 // retrieve will retrieve the binary data under the given key from the badger DB
 // and decode it into the given entity. The provided entity needs to be a
 // pointer to an initialized entity of the correct type.
-func retrieve(key []byte, entity interface{}) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func retrieve(key []byte, entity interface{}) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 
-		// retrieve the item from the key-value store
-		item, err := tx.Get(key)
-		if errors.Is(err, badger.ErrKeyNotFound) {
+		// retrieve the value from the key-value store and decode it
+		var decodeErr error
+		err := tx.Get(key, func(val []byte) error {
+			decodeErr = msgpack.Unmarshal(val, entity)
+			return nil
+		})
+		if errors.Is(err, kv.ErrNotFound) {
 			return storage.ErrNotFound
 		}
 		if err != nil {
 			return fmt.Errorf("could not load data: %w", err)
 		}
-
-		// get the value from the item
-		err = item.Value(func(val []byte) error {
-			err := msgpack.Unmarshal(val, entity)
-			return err
-		})
-		if err != nil {
-			return fmt.Errorf("could not decode entity: %w", err)
+		if decodeErr != nil {
+			return fmt.Errorf("could not decode entity: %w", decodeErr)
 		}
 
 		return nil
 	}
 }
 
+// noop ignores the value of a key, when only checking whether the key exists.
+func noop([]byte) error {
+	return nil
+}
+
 // checkFunc is called during key iteration through the badger DB in order to
 // check whether we should process the given key-value pair. It can be used to
 // avoid loading the value if its not of interest, as well as storing the key
@@ -219,12 +264,12 @@ func lookup(entityIDs *[]flow.Identifier) func() (checkFunc, createFunc, handleF
 //
 // TODO: this function is unbounded – pass context.Context to this or calling
 // functions to allow timing functions out.
-func iterate(start []byte, end []byte, iteration iterationFunc) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func iterate(start []byte, end []byte, iteration iterationFunc) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 
 		// initialize the default options and comparison modifier for iteration
 		modifier := 1
-		options := badger.DefaultIteratorOptions
+		options := kv.IteratorOptions{}
 
 		// In order to satisfy this function's prefix-wise inclusion semantics,
 		// we append 0xff bytes to the largest of start and end.
@@ -273,9 +318,7 @@ func iterate(start []byte, end []byte, iteration iterationFunc) func(*badger.Txn
 
 		for it.Seek(start); it.Valid(); it.Next() {
 
-			item := it.Item()
-
-			key := item.Key()
+			key := it.Key()
 			// for forward iteration, check whether key > end, for backward
 			// iteration check whether key < end
 			if bytes.Compare(key, end)*modifier > 0 {
@@ -292,7 +335,7 @@ func iterate(start []byte, end []byte, iteration iterationFunc) func(*badger.Txn
 			}
 
 			// process the actual item
-			err := item.Value(func(val []byte) error {
+			err := it.Value(func(val []byte) error {
 
 				// decode into the entity
 				entity := create()
@@ -324,37 +367,29 @@ func iterate(start []byte, end []byte, iteration iterationFunc) func(*badger.Txn
 //
 // On each iteration, it will call the iteration function to initialize
 // functions specific to processing the given key-value pair.
-func traverse(prefix []byte, iteration iterationFunc) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func traverse(prefix []byte, iteration iterationFunc) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 		if len(prefix) == 0 {
 			return fmt.Errorf("prefix must not be empty")
 		}
 
-		opts := badger.DefaultIteratorOptions
-		// NOTE: this is an optimization only, it does not enforce that all
-		// results in the iteration have this prefix.
-		opts.Prefix = prefix
-
-		it := tx.NewIterator(opts)
+		it := tx.NewIterator(kv.IteratorOptions{Prefix: prefix})
 		defer it.Close()
 
-		// this is where we actually enforce that all results have the prefix
-		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
-
-			item := it.Item()
+		for it.Seek(prefix); it.Valid(); it.Next() {
 
 			// initialize processing functions for iteration
 			check, create, handle := iteration()
 
 			// check if we should process the item at all
-			key := item.Key()
+			key := it.Key()
 			ok := check(key)
 			if !ok {
 				continue
 			}
 
 			// process the actual item
-			err := item.Value(func(val []byte) error {
+			err := it.Value(func(val []byte) error {
 
 				// decode into the entity
 				entity := create()
@@ -381,8 +416,8 @@ func traverse(prefix []byte, iteration iterationFunc) func(*badger.Txn) error {
 }
 
 // Fail returns a DB operation function that always fails with the given error.
-func Fail(err error) func(*badger.Txn) error {
-	return func(_ *badger.Txn) error {
+func Fail(err error) func(kv.Txn) error {
+	return func(_ kv.Txn) error {
 		return err
 	}
 }
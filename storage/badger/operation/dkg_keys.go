@@ -1,15 +1,14 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/dkg"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertMyDKGPrivateInfo(epochCounter uint64, info *dkg.DKGParticipantPriv) func(*badger.Txn) error {
+func InsertMyDKGPrivateInfo(epochCounter uint64, info *dkg.DKGParticipantPriv) func(kv.Txn) error {
 	return insert(makePrefix(codeDKGPrivateInfo, epochCounter), info)
 }
 
-func RetrieveMyDKGPrivateInfo(epochCounter uint64, info *dkg.DKGParticipantPriv) func(*badger.Txn) error {
+func RetrieveMyDKGPrivateInfo(epochCounter uint64, info *dkg.DKGParticipantPriv) func(kv.Txn) error {
 	return retrieve(makePrefix(codeDKGPrivateInfo, epochCounter), info)
 }
@@ -1,31 +1,30 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertEpochSetup(eventID flow.Identifier, event *flow.EpochSetup) func(*badger.Txn) error {
+func InsertEpochSetup(eventID flow.Identifier, event *flow.EpochSetup) func(kv.Txn) error {
 	return insert(makePrefix(codeEpochSetup, eventID), event)
 }
 
-func RetrieveEpochSetup(eventID flow.Identifier, event *flow.EpochSetup) func(*badger.Txn) error {
+func RetrieveEpochSetup(eventID flow.Identifier, event *flow.EpochSetup) func(kv.Txn) error {
 	return retrieve(makePrefix(codeEpochSetup, eventID), event)
 }
 
-func InsertEpochCommit(eventID flow.Identifier, event *flow.EpochCommit) func(*badger.Txn) error {
+func InsertEpochCommit(eventID flow.Identifier, event *flow.EpochCommit) func(kv.Txn) error {
 	return insert(makePrefix(codeEpochCommit, eventID), event)
 }
 
-func RetrieveEpochCommit(eventID flow.Identifier, event *flow.EpochCommit) func(*badger.Txn) error {
+func RetrieveEpochCommit(eventID flow.Identifier, event *flow.EpochCommit) func(kv.Txn) error {
 	return retrieve(makePrefix(codeEpochCommit, eventID), event)
 }
 
-func InsertEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*badger.Txn) error {
+func InsertEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(kv.Txn) error {
 	return insert(makePrefix(codeBlockEpochStatus, blockID), status)
 }
 
-func RetrieveEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*badger.Txn) error {
+func RetrieveEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(kv.Txn) error {
 	return retrieve(makePrefix(codeBlockEpochStatus, blockID), status)
 }
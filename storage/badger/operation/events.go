@@ -3,51 +3,200 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 func eventPrefix(prefix byte, blockID flow.Identifier, event flow.Event) []byte {
 	return makePrefix(prefix, blockID, event.TransactionID, event.TransactionIndex, event.EventIndex)
 }
 
-func InsertEvent(blockID flow.Identifier, event flow.Event) func(*badger.Txn) error {
+func InsertEvent(blockID flow.Identifier, event flow.Event) func(kv.Txn) error {
 	return insert(eventPrefix(codeEvent, blockID, event), event)
 }
 
-func BatchInsertEvent(blockID flow.Identifier, event flow.Event) func(batch *badger.WriteBatch) error {
+func BatchInsertEvent(blockID flow.Identifier, event flow.Event) func(batch kv.WriteBatch) error {
 	return batchInsert(eventPrefix(codeEvent, blockID, event), event)
 }
 
-func InsertServiceEvent(blockID flow.Identifier, event flow.Event) func(*badger.Txn) error {
+func InsertServiceEvent(blockID flow.Identifier, event flow.Event) func(kv.Txn) error {
 	return insert(eventPrefix(codeServiceEvent, blockID, event), event)
 }
 
-func BatchInsertServiceEvent(blockID flow.Identifier, event flow.Event) func(batch *badger.WriteBatch) error {
+func BatchInsertServiceEvent(blockID flow.Identifier, event flow.Event) func(batch kv.WriteBatch) error {
 	return batchInsert(eventPrefix(codeServiceEvent, blockID, event), event)
 }
 
-func RetrieveEvents(blockID flow.Identifier, transactionID flow.Identifier, events *[]flow.Event) func(*badger.Txn) error {
+func RetrieveEvents(blockID flow.Identifier, transactionID flow.Identifier, events *[]flow.Event) func(kv.Txn) error {
 	iterationFunc := eventIterationFunc(events)
 	return traverse(makePrefix(codeEvent, blockID, transactionID), iterationFunc)
 }
 
-func LookupEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(*badger.Txn) error {
+func LookupEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(kv.Txn) error {
 	iterationFunc := eventIterationFunc(events)
 	return traverse(makePrefix(codeEvent, blockID), iterationFunc)
 }
 
-func LookupServiceEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(*badger.Txn) error {
+func LookupServiceEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(kv.Txn) error {
 	iterationFunc := eventIterationFunc(events)
 	return traverse(makePrefix(codeServiceEvent, blockID), iterationFunc)
 }
 
-func LookupEventsByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType, events *[]flow.Event) func(*badger.Txn) error {
+func LookupEventsByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType, events *[]flow.Event) func(kv.Txn) error {
 	iterationFunc := eventFilterIterationFunc(events, eventType)
 	return traverse(makePrefix(codeEvent, blockID), iterationFunc)
 }
 
+// BatchRemoveEventsByBlockID removes the events emitted by the block in the write batch, together with their
+// entries in the index of the events by type, and adds their size to the reclaimed bytes.
+func BatchRemoveEventsByBlockID(blockID flow.Identifier, writeBatch kv.WriteBatch, reclaimed *uint64) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
+		var height uint64
+		err := RetrieveBlockEventsByTypeHeight(blockID, &height)(tx)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("could not retrieve height of events indexed by type: %w", err)
+		}
+
+		// the keys of the index of the events by type include the event types, which are only in the events
+		if err == nil {
+			var events []flow.Event
+			err = LookupEventsByBlockID(blockID, &events)(tx)
+			if err != nil {
+				return fmt.Errorf("could not look up events: %w", err)
+			}
+			for _, event := range events {
+				key := makePrefix(codeIndexEventByType, eventTypeID(event.Type), height, blockID, event.TransactionIndex, event.EventIndex)
+				err = batchRemove(key, writeBatch, reclaimed)(tx)
+				if err != nil {
+					return fmt.Errorf("could not remove event type index entry: %w", err)
+				}
+			}
+			err = batchRemove(makePrefix(codeIndexEventByTypeBlock, blockID), writeBatch, reclaimed)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove event type index marker: %w", err)
+			}
+		}
+
+		return batchRemoveByPrefix(makePrefix(codeEvent, blockID), writeBatch, reclaimed)(tx)
+	}
+}
+
+// EventTypeIndexEntry is an entry of the index of the events by type, locating an event emitted at a height.
+type EventTypeIndexEntry struct {
+	Height           uint64
+	BlockID          flow.Identifier
+	TransactionID    flow.Identifier
+	TransactionIndex uint32
+	EventIndex       uint32
+}
+
+// idSize is the size of the identifiers in the keys.
+const idSize = len(flow.ZeroID)
+
+// eventTypeIndexKeySize is the size of the keys of the index of the events by type, i.e. the code, the event
+// type ID, the height, the block ID, the transaction index and the event index.
+const eventTypeIndexKeySize = 1 + idSize + 8 + idSize + 4 + 4
+
+// eventTypeID returns the fixed size ID of the event type, so that the types which are prefixes of other
+// types do not share the keys of their index.
+func eventTypeID(eventType flow.EventType) flow.Identifier {
+	return flow.MakeID(eventType)
+}
+
+// BatchIndexEventByType indexes the event emitted by the block at the given height by its type. The block ID
+// is part of the key, so that the events of conflicting blocks at the same height are all indexed.
+func BatchIndexEventByType(blockID flow.Identifier, height uint64, event flow.Event) func(batch kv.WriteBatch) error {
+	key := makePrefix(codeIndexEventByType, eventTypeID(event.Type), height, blockID, event.TransactionIndex, event.EventIndex)
+	return batchInsert(key, event.TransactionID)
+}
+
+// BatchIndexBlockEventsByType marks the events of the block at the given height as indexed by type.
+func BatchIndexBlockEventsByType(blockID flow.Identifier, height uint64) func(batch kv.WriteBatch) error {
+	return batchInsert(makePrefix(codeIndexEventByTypeBlock, blockID), height)
+}
+
+// RetrieveBlockEventsByTypeHeight retrieves the height of the block whose events are indexed by type.
+func RetrieveBlockEventsByTypeHeight(blockID flow.Identifier, height *uint64) func(kv.Txn) error {
+	return retrieve(makePrefix(codeIndexEventByTypeBlock, blockID), height)
+}
+
+// LookupEventsByType looks up the entries of the index of the events of the given type, from the position of
+// the given entry up to the end height (inclusive), ordered by height, block ID, transaction index and event
+// index. The transaction ID of the from entry is ignored, and an entry with only a height starts at the first
+// event of this height. It looks up at most limit entries, all of them if limit is 0.
+func LookupEventsByType(eventType flow.EventType, from EventTypeIndexEntry, endHeight uint64, limit uint, entries *[]EventTypeIndexEntry) func(kv.Txn) error {
+	if from.Height > endHeight {
+		// iterating from a higher key would iterate in reverse order
+		return func(kv.Txn) error { return nil }
+	}
+
+	typeID := eventTypeID(eventType)
+	startKey := makePrefix(codeIndexEventByType, typeID, from.Height, from.BlockID, from.TransactionIndex, from.EventIndex)
+	endKey := makePrefix(codeIndexEventByType, typeID, endHeight)
+	// pad the end key to the size of the start key, so that a start key within the end height is not iterated
+	// in reverse order
+	endKey = append(endKey, bytes.Repeat([]byte{0xff}, len(startKey)-len(endKey))...)
+
+	return iterate(startKey, endKey, func() (checkFunc, createFunc, handleFunc) {
+		var key []byte
+		check := func(k []byte) bool {
+			// the values of the keys beyond the limit are not loaded
+			if limit > 0 && uint(len(*entries)) >= limit {
+				return false
+			}
+			key = k
+			return true
+		}
+		var transactionID flow.Identifier
+		create := func() interface{} {
+			return &transactionID
+		}
+		handle := func() error {
+			entry, err := eventTypeIndexEntry(key, transactionID)
+			if err != nil {
+				return err
+			}
+			*entries = append(*entries, entry)
+			return nil
+		}
+		return check, create, handle
+	})
+}
+
+// eventTypeIndexEntry decodes the key of an entry of the index of the events by type.
+func eventTypeIndexEntry(key []byte, transactionID flow.Identifier) (EventTypeIndexEntry, error) {
+	if len(key) != eventTypeIndexKeySize {
+		return EventTypeIndexEntry{}, fmt.Errorf("invalid event type index key size %d", len(key))
+	}
+
+	// skip the code and the event type ID
+	key = key[1+idSize:]
+
+	entry := EventTypeIndexEntry{
+		Height:        binary.BigEndian.Uint64(key),
+		TransactionID: transactionID,
+	}
+	key = key[8:]
+	copy(entry.BlockID[:], key)
+	key = key[idSize:]
+	entry.TransactionIndex = binary.BigEndian.Uint32(key)
+	entry.EventIndex = binary.BigEndian.Uint32(key[4:])
+
+	return entry, nil
+}
+
+// RetrieveEvent retrieves the event located by the entry of the index of the events by type.
+func RetrieveEvent(entry EventTypeIndexEntry, event *flow.Event) func(kv.Txn) error {
+	key := makePrefix(codeEvent, entry.BlockID, entry.TransactionID, entry.TransactionIndex, entry.EventIndex)
+	return retrieve(key, event)
+}
+
 // eventIterationFunc returns an in iteration function which returns all events found during traversal or iteration
 func eventIterationFunc(events *[]flow.Event) func() (checkFunc, createFunc, handleFunc) {
 	return func() (checkFunc, createFunc, handleFunc) {
@@ -3,17 +3,17 @@ package operation
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestGuaranteeInsertRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		g := unittest.CollectionGuaranteeFixture()
 
 		err := db.Update(InsertGuarantee(g.CollectionID, g))
@@ -28,7 +28,7 @@ func TestGuaranteeInsertRetrieve(t *testing.T) {
 }
 
 func TestIndexGuaranteedCollectionByBlockHashInsertRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		blockID := flow.Identifier{0x10}
 		collID1 := flow.Identifier{0x01}
 		collID2 := flow.Identifier{0x02}
@@ -38,7 +38,7 @@ func TestIndexGuaranteedCollectionByBlockHashInsertRetrieve(t *testing.T) {
 		}
 		expected := flow.GetIDs(guarantees)
 
-		err := db.Update(func(tx *badger.Txn) error {
+		err := db.Update(func(tx kv.Txn) error {
 			for _, guarantee := range guarantees {
 				if err := InsertGuarantee(guarantee.ID(), guarantee)(tx); err != nil {
 					return err
@@ -60,7 +60,7 @@ func TestIndexGuaranteedCollectionByBlockHashInsertRetrieve(t *testing.T) {
 }
 
 func TestIndexGuaranteedCollectionByBlockHashMultipleBlocks(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		blockID1 := flow.Identifier{0x10}
 		blockID2 := flow.Identifier{0x20}
 		collID1 := flow.Identifier{0x01}
@@ -79,7 +79,7 @@ func TestIndexGuaranteedCollectionByBlockHashMultipleBlocks(t *testing.T) {
 		ids2 := flow.GetIDs(set2)
 
 		// insert block 1
-		err := db.Update(func(tx *badger.Txn) error {
+		err := db.Update(func(tx kv.Txn) error {
 			for _, guarantee := range set1 {
 				if err := InsertGuarantee(guarantee.CollectionID, guarantee)(tx); err != nil {
 					return err
@@ -93,7 +93,7 @@ func TestIndexGuaranteedCollectionByBlockHashMultipleBlocks(t *testing.T) {
 		require.Nil(t, err)
 
 		// insert block 2
-		err = db.Update(func(tx *badger.Txn) error {
+		err = db.Update(func(tx kv.Txn) error {
 			for _, guarantee := range set2 {
 				if err := InsertGuarantee(guarantee.CollectionID, guarantee)(tx); err != nil {
 					return err
@@ -3,79 +3,127 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
+	"encoding/binary"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertHeader(headerID flow.Identifier, header *flow.Header) func(*badger.Txn) error {
+func InsertHeader(headerID flow.Identifier, header *flow.Header) func(kv.Txn) error {
 	return insert(makePrefix(codeHeader, headerID), header)
 }
 
-func RetrieveHeader(blockID flow.Identifier, header *flow.Header) func(*badger.Txn) error {
+func RetrieveHeader(blockID flow.Identifier, header *flow.Header) func(kv.Txn) error {
 	return retrieve(makePrefix(codeHeader, blockID), header)
 }
 
 // IndexBlockHeight indexes the height of a block. It should only be called on
 // finalized blocks.
-func IndexBlockHeight(height uint64, blockID flow.Identifier) func(*badger.Txn) error {
+func IndexBlockHeight(height uint64, blockID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeHeightToBlock, height), blockID)
 }
 
 // LookupBlockHeight retrieves finalized blocks by height.
-func LookupBlockHeight(height uint64, blockID *flow.Identifier) func(*badger.Txn) error {
+func LookupBlockHeight(height uint64, blockID *flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeHeightToBlock, height), blockID)
 }
 
+// RemoveBlockHeight removes the index of a finalized block by height.
+func RemoveBlockHeight(height uint64) func(kv.Txn) error {
+	return remove(makePrefix(codeHeightToBlock, height))
+}
+
+// TraverseBlockHeights calls the given function on each indexed height, in increasing order, with the ID of
+// the finalized block at this height.
+func TraverseBlockHeights(handle func(height uint64, blockID flow.Identifier) error) func(kv.Txn) error {
+	return traverse(makePrefix(codeHeightToBlock), func() (checkFunc, createFunc, handleFunc) {
+		var height uint64
+		check := func(key []byte) bool {
+			height = binary.BigEndian.Uint64(key[1:])
+			return true
+		}
+		var blockID flow.Identifier
+		create := func() interface{} {
+			return &blockID
+		}
+		return check, create, func() error {
+			return handle(height, blockID)
+		}
+	})
+}
+
 // InsertBlockValidity marks a block as valid or invalid, defined by the consensus algorithm.
-func InsertBlockValidity(blockID flow.Identifier, valid bool) func(*badger.Txn) error {
+func InsertBlockValidity(blockID flow.Identifier, valid bool) func(kv.Txn) error {
 	return insert(makePrefix(codeBlockValidity, blockID), valid)
 }
 
 // RetrieveBlockValidity returns a block's validity wrt the consensus algorithm.
-func RetrieveBlockValidity(blockID flow.Identifier, valid *bool) func(*badger.Txn) error {
+func RetrieveBlockValidity(blockID flow.Identifier, valid *bool) func(kv.Txn) error {
 	return retrieve(makePrefix(codeBlockValidity, blockID), valid)
 }
 
-func InsertExecutedBlock(blockID flow.Identifier) func(*badger.Txn) error {
+func InsertExecutedBlock(blockID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeExecutedBlock), blockID)
 }
 
-func UpdateExecutedBlock(blockID flow.Identifier) func(*badger.Txn) error {
+func UpdateExecutedBlock(blockID flow.Identifier) func(kv.Txn) error {
 	return update(makePrefix(codeExecutedBlock), blockID)
 }
 
-func RetrieveExecutedBlock(blockID *flow.Identifier) func(*badger.Txn) error {
+func RetrieveExecutedBlock(blockID *flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeExecutedBlock), blockID)
 }
 
 // IndexCollectionBlock indexes a block by a collection within that block.
-func IndexCollectionBlock(collID flow.Identifier, blockID flow.Identifier) func(*badger.Txn) error {
+func IndexCollectionBlock(collID flow.Identifier, blockID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeCollectionBlock, collID), blockID)
 }
 
-func IndexBlockIDByChunkID(chunkID, blockID flow.Identifier) func(*badger.Txn) error {
+func IndexBlockIDByChunkID(chunkID, blockID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeIndexBlockByChunkID, chunkID), blockID)
 }
 
 // BatchIndexBlockByChunkID indexes blockID by chunkID into a batch
-func BatchIndexBlockByChunkID(blockID, chunkID flow.Identifier) func(batch *badger.WriteBatch) error {
+func BatchIndexBlockByChunkID(blockID, chunkID flow.Identifier) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeIndexBlockByChunkID, chunkID), blockID)
 }
 
 // LookupCollectionBlock looks up a block by a collection within that block.
-func LookupCollectionBlock(collID flow.Identifier, blockID *flow.Identifier) func(*badger.Txn) error {
+func LookupCollectionBlock(collID flow.Identifier, blockID *flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeCollectionBlock, collID), blockID)
 }
 
 // LookupBlockIDByChunkID looks up a block by a collection within that block.
-func LookupBlockIDByChunkID(chunkID flow.Identifier, blockID *flow.Identifier) func(*badger.Txn) error {
+func LookupBlockIDByChunkID(chunkID flow.Identifier, blockID *flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeIndexBlockByChunkID, chunkID), blockID)
 }
 
+// RemoveBlockIDByChunkID removes the index of a block by a chunk within its execution result.
+func RemoveBlockIDByChunkID(chunkID flow.Identifier) func(kv.Txn) error {
+	return remove(makePrefix(codeIndexBlockByChunkID, chunkID))
+}
+
+// TraverseBlockIDsByChunkID calls the given function on each chunk ID indexed, with the ID of its block.
+func TraverseBlockIDsByChunkID(handle func(chunkID flow.Identifier, blockID flow.Identifier) error) func(kv.Txn) error {
+	return traverse(makePrefix(codeIndexBlockByChunkID), func() (checkFunc, createFunc, handleFunc) {
+		var chunkID flow.Identifier
+		check := func(key []byte) bool {
+			copy(chunkID[:], key[1:])
+			return true
+		}
+		var blockID flow.Identifier
+		create := func() interface{} {
+			return &blockID
+		}
+		return check, create, func() error {
+			return handle(chunkID, blockID)
+		}
+	})
+}
+
 // FindHeaders iterates through all headers, calling `filter` on each, and adding
 // them to the `found` slice if `filter` returned true
-func FindHeaders(filter func(header *flow.Header) bool, found *[]flow.Header) func(*badger.Txn) error {
+func FindHeaders(filter func(header *flow.Header) bool, found *[]flow.Header) func(kv.Txn) error {
 	return traverse(makePrefix(codeHeader), func() (checkFunc, createFunc, handleFunc) {
 		check := func(key []byte) bool {
 			return true
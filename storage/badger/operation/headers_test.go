@@ -6,17 +6,17 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestHeaderInsertCheckRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		expected := flow.Header{
 			View:               1337,
 			Timestamp:          time.Now().UTC(),
@@ -41,7 +41,7 @@ func TestHeaderInsertCheckRetrieve(t *testing.T) {
 }
 
 func TestHeaderIDIndexByCollectionID(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 
 		headerID := unittest.IdentifierFixture()
 		collectionID := unittest.IdentifierFixture()
@@ -57,7 +57,7 @@ func TestHeaderIDIndexByCollectionID(t *testing.T) {
 }
 
 func TestBlockHeightIndexLookup(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 
 		height := uint64(1337)
 		expected := flow.Identifier{0x01, 0x02, 0x03}
@@ -3,17 +3,17 @@ package operation_test
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestInsertRetrieveDBTypeMarker(t *testing.T) {
 	t.Run("should insert and ensure type marker", func(t *testing.T) {
 		t.Run("public", func(t *testing.T) {
-			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unittest.RunWithEachDB(t, func(db kv.DB) {
 
 				// can insert db marker to empty DB
 				err := db.Update(operation.InsertPublicDBMarker)
@@ -31,7 +31,7 @@ func TestInsertRetrieveDBTypeMarker(t *testing.T) {
 		})
 
 		t.Run("secret", func(t *testing.T) {
-			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unittest.RunWithEachDB(t, func(db kv.DB) {
 
 				// can insert db marker to empty DB
 				err := db.Update(operation.InsertSecretDBMarker)
@@ -51,7 +51,7 @@ func TestInsertRetrieveDBTypeMarker(t *testing.T) {
 
 	t.Run("should fail to insert different db marker to non-empty db", func(t *testing.T) {
 		t.Run("public", func(t *testing.T) {
-			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unittest.RunWithEachDB(t, func(db kv.DB) {
 
 				// can insert db marker to empty DB
 				err := db.Update(operation.InsertPublicDBMarker)
@@ -62,7 +62,7 @@ func TestInsertRetrieveDBTypeMarker(t *testing.T) {
 			})
 		})
 		t.Run("secret", func(t *testing.T) {
-			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unittest.RunWithEachDB(t, func(db kv.DB) {
 
 				// can insert db marker to empty DB
 				err := db.Update(operation.InsertSecretDBMarker)
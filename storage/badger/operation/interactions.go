@@ -3,14 +3,19 @@ package operation
 import (
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/model/flow"
-
-	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertExecutionStateInteractions(blockID flow.Identifier, interactions []*delta.Snapshot) func(*badger.Txn) error {
+func InsertExecutionStateInteractions(blockID flow.Identifier, interactions []*delta.Snapshot) func(kv.Txn) error {
 	return insert(makePrefix(codeExecutionStateInteractions, blockID), interactions)
 }
 
-func RetrieveExecutionStateInteractions(blockID flow.Identifier, interactions *[]*delta.Snapshot) func(*badger.Txn) error {
+func RetrieveExecutionStateInteractions(blockID flow.Identifier, interactions *[]*delta.Snapshot) func(kv.Txn) error {
 	return retrieve(makePrefix(codeExecutionStateInteractions, blockID), interactions)
 }
+
+// BatchRemoveExecutionStateInteractions removes the execution state interactions of the block in the write batch,
+// if they exist, and adds their size to the reclaimed bytes.
+func BatchRemoveExecutionStateInteractions(blockID flow.Identifier, writeBatch kv.WriteBatch, reclaimed *uint64) func(kv.Txn) error {
+	return batchRemove(makePrefix(codeExecutionStateInteractions, blockID), writeBatch, reclaimed)
+}
@@ -14,6 +14,7 @@ const (
 	// codes for special database markers
 	codeMax    = 1 // keeps track of the maximum key size
 	codeDBType = 2 // specifies a database type
+	codeSchema = 3 // specifies the version of the database schema
 
 	// codes for views with special meaning
 	codeStartedView           = 10 // latest view hotstuff started
@@ -81,11 +82,84 @@ const (
 	codeIndexExecutionResultByBlock  = 202
 	codeIndexCollectionByTransaction = 203
 	codeIndexResultApprovalByChunk   = 204
+	codeIndexEventByType             = 205 // index mapping event type and height to events
+	codeIndexEventByTypeBlock        = 206 // index marking the blocks whose events are indexed by type
+	codeIndexTransactionByAccount    = 207 // index mapping account address and height to transactions
 
 	// internal failure information that should be preserved across restarts
-	codeExecutionFork = 254
+	codeExecutionForkReport = 253 // report of the execution fork detected by an execution node
+	codeExecutionFork       = 254
 )
 
+// prefixNames are the names of the codes prefixing the keys, for reporting on the database content.
+var prefixNames = map[byte]string{
+	codeMax:                          "max",
+	codeDBType:                       "db_type",
+	codeSchema:                       "schema",
+	codeStartedView:                  "started_view",
+	codeVotedView:                    "voted_view",
+	codeRootQuorumCertificate:        "root_quorum_certificate",
+	codeFinalizedHeight:              "finalized_height",
+	codeSealedHeight:                 "sealed_height",
+	codeClusterHeight:                "cluster_height",
+	codeExecutedBlock:                "executed_block",
+	codeRootHeight:                   "root_height",
+	codeLastCompleteBlockHeight:      "last_complete_block_height",
+	codeHeader:                       "header",
+	codeGuarantee:                    "guarantee",
+	codeSeal:                         "seal",
+	codeTransaction:                  "transaction",
+	codeCollection:                   "collection",
+	codeExecutionResult:              "execution_result/execution_receipt_meta", // shared by both entities
+	codeResultApproval:               "result_approval",
+	codeChunk:                        "chunk",
+	codeHeightToBlock:                "height_to_block",
+	codeBlockToSeal:                  "block_to_seal",
+	codeCollectionReference:          "collection_reference",
+	codeBlockValidity:                "block_validity",
+	codeBlockChildren:                "block_children",
+	codePayloadGuarantees:            "payload_guarantees",
+	codePayloadSeals:                 "payload_seals",
+	codeCollectionBlock:              "collection_block",
+	codeOwnBlockReceipt:              "own_block_receipt",
+	codeBlockEpochStatus:             "block_epoch_status",
+	codePayloadReceipts:              "payload_receipts",
+	codePayloadResults:               "payload_results",
+	codeAllBlockReceipts:             "all_block_receipts",
+	codeIndexBlockByChunkID:          "index_block_by_chunk_id",
+	codeEpochSetup:                   "epoch_setup",
+	codeEpochCommit:                  "epoch_commit",
+	codeDKGPrivateInfo:               "dkg_private_info",
+	codeJobConsumerProcessed:         "job_consumer_processed",
+	codeJobQueue:                     "job_queue",
+	codeJobQueuePointer:              "job_queue_pointer",
+	codeChunkDataPack:                "chunk_data_pack",
+	codeCommit:                       "commit",
+	codeEvent:                        "event",
+	codeExecutionStateInteractions:   "execution_state_interactions",
+	codeTransactionResult:            "transaction_result",
+	codeFinalizedCluster:             "finalized_cluster",
+	codeServiceEvent:                 "service_event",
+	codeIndexCollection:              "index_collection",
+	codeIndexExecutionResultByBlock:  "index_execution_result_by_block",
+	codeIndexCollectionByTransaction: "index_collection_by_transaction",
+	codeIndexResultApprovalByChunk:   "index_result_approval_by_chunk",
+	codeIndexEventByType:             "index_event_by_type",
+	codeIndexEventByTypeBlock:        "index_event_by_type_block",
+	codeIndexTransactionByAccount:    "index_transaction_by_account",
+	codeExecutionForkReport:          "execution_fork_report",
+	codeExecutionFork:                "execution_fork",
+}
+
+// PrefixName returns the name of the code prefixing a key, or "unknown" for a code which is not in use.
+func PrefixName(code byte) string {
+	name, ok := prefixNames[code]
+	if !ok {
+		return "unknown"
+	}
+	return name
+}
+
 func makePrefix(code byte, keys ...interface{}) []byte {
 	prefix := make([]byte, 1)
 	prefix[0] = code
@@ -113,6 +187,8 @@ func b(v interface{}) []byte {
 		return []byte{byte(i)}
 	case flow.Identifier:
 		return i[:]
+	case flow.Address:
+		return i[:]
 	case flow.ChainID:
 		return []byte(i)
 	default:
@@ -36,4 +36,10 @@ func TestMakePrefix(t *testing.T) {
 	actual = makePrefix(code, id)
 
 	assert.Equal(t, expected, actual)
+
+	address := flow.Address{0x05, 0x06, 0x07}
+	expected = []byte{0x01, 0x05, 0x06, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00}
+	actual = makePrefix(code, address)
+
+	assert.Equal(t, expected, actual)
 }
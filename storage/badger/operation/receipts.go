@@ -1,54 +1,53 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // InsertExecutionReceiptMeta inserts an execution receipt meta by ID.
-func InsertExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(*badger.Txn) error {
+func InsertExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(kv.Txn) error {
 	return insert(makePrefix(codeExecutionReceiptMeta, receiptID), meta)
 }
 
 // BatchInsertExecutionReceiptMeta inserts an execution receipt meta by ID.
-func BatchInsertExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(batch *badger.WriteBatch) error {
+func BatchInsertExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeExecutionReceiptMeta, receiptID), meta)
 }
 
 // RetrieveExecutionReceipt retrieves a execution receipt meta by ID.
-func RetrieveExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(*badger.Txn) error {
+func RetrieveExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(kv.Txn) error {
 	return retrieve(makePrefix(codeExecutionReceiptMeta, receiptID), meta)
 }
 
 // IndexOwnExecutionReceipt inserts an execution receipt ID keyed by block ID
-func IndexOwnExecutionReceipt(blockID flow.Identifier, receiptID flow.Identifier) func(*badger.Txn) error {
+func IndexOwnExecutionReceipt(blockID flow.Identifier, receiptID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeOwnBlockReceipt, blockID), receiptID)
 }
 
 // BatchIndexOwnExecutionReceipt inserts an execution receipt ID keyed by block ID into a batch
-func BatchIndexOwnExecutionReceipt(blockID flow.Identifier, receiptID flow.Identifier) func(batch *badger.WriteBatch) error {
+func BatchIndexOwnExecutionReceipt(blockID flow.Identifier, receiptID flow.Identifier) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeOwnBlockReceipt, blockID), receiptID)
 }
 
 // LookupOwnExecutionReceipt finds execution receipt ID by block
-func LookupOwnExecutionReceipt(blockID flow.Identifier, receiptID *flow.Identifier) func(*badger.Txn) error {
+func LookupOwnExecutionReceipt(blockID flow.Identifier, receiptID *flow.Identifier) func(kv.Txn) error {
 	return retrieve(makePrefix(codeOwnBlockReceipt, blockID), receiptID)
 }
 
 // IndexExecutionReceipts inserts an execution receipt ID keyed by block ID and receipt ID.
 // one block could have multiple receipts, even if they are from the same executor
-func IndexExecutionReceipts(blockID, receiptID flow.Identifier) func(*badger.Txn) error {
+func IndexExecutionReceipts(blockID, receiptID flow.Identifier) func(kv.Txn) error {
 	return insert(makePrefix(codeAllBlockReceipts, blockID, receiptID), receiptID)
 }
 
 // BatchIndexExecutionReceipts inserts an execution receipt ID keyed by block ID and receipt ID into a batch
-func BatchIndexExecutionReceipts(blockID, receiptID flow.Identifier) func(batch *badger.WriteBatch) error {
+func BatchIndexExecutionReceipts(blockID, receiptID flow.Identifier) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeAllBlockReceipts, blockID, receiptID), receiptID)
 }
 
 // LookupExecutionReceipts finds all execution receipts by block ID
-func LookupExecutionReceipts(blockID flow.Identifier, receiptIDs *[]flow.Identifier) func(*badger.Txn) error {
+func LookupExecutionReceipts(blockID flow.Identifier, receiptIDs *[]flow.Identifier) func(kv.Txn) error {
 	iterationFunc := receiptIterationFunc(receiptIDs)
 	return traverse(makePrefix(codeAllBlockReceipts, blockID), iterationFunc)
 }
@@ -5,16 +5,16 @@ package operation
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestReceipts_InsertRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		receipt := unittest.ExecutionReceiptFixture()
 		expected := receipt.Meta()
 
@@ -30,7 +30,7 @@ func TestReceipts_InsertRetrieve(t *testing.T) {
 }
 
 func TestReceipts_Index(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		receipt := unittest.ExecutionReceiptFixture()
 		expected := receipt.ID()
 		blockID := receipt.ExecutionResult.BlockID
@@ -47,7 +47,7 @@ func TestReceipts_Index(t *testing.T) {
 }
 
 func TestReceipts_MultiIndex(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		expected := []flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()}
 		blockID := unittest.IdentifierFixture()
 
@@ -0,0 +1,60 @@
+package operation
+
+import (
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// InsertSchemaVersion inserts the version of the schema the database content follows.
+func InsertSchemaVersion(version uint64) func(kv.Txn) error {
+	return insert(makePrefix(codeSchema), version)
+}
+
+// UpdateSchemaVersion updates the version of the schema the database content follows, once it is migrated.
+func UpdateSchemaVersion(version uint64) func(kv.Txn) error {
+	return update(makePrefix(codeSchema), version)
+}
+
+// RetrieveSchemaVersion retrieves the version of the schema the database content follows. It returns
+// storage.ErrNotFound for the databases created before the schema was versioned.
+func RetrieveSchemaVersion(version *uint64) func(kv.Txn) error {
+	return retrieve(makePrefix(codeSchema), version)
+}
+
+// PrefixStats are the number of keys stored under a prefix code, and the size of these keys and the estimated
+// size of their values.
+type PrefixStats struct {
+	Code      byte
+	Keys      uint64
+	KeySize   uint64
+	ValueSize uint64
+}
+
+// SummarizePrefixes retrieves the stats of each prefix code in use in the database, ordered by code. It
+// iterates over all the keys without fetching the values, so it is slow on large databases but does not
+// load their content in memory.
+func SummarizePrefixes(stats *[]PrefixStats) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
+		it := tx.NewIterator(kv.IteratorOptions{KeysOnly: true})
+		defer it.Close()
+
+		*stats = nil
+		for it.Seek(nil); it.Valid(); it.Next() {
+			key := it.Key()
+			if len(key) == 0 {
+				continue
+			}
+			code := key[0]
+			if len(*stats) == 0 || (*stats)[len(*stats)-1].Code != code {
+				*stats = append(*stats, PrefixStats{Code: code})
+			}
+			prefix := &(*stats)[len(*stats)-1]
+			prefix.Keys++
+			prefix.KeySize += uint64(len(key))
+			if size := it.EstimatedSize() - int64(len(key)); size > 0 {
+				prefix.ValueSize += uint64(size)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSchemaVersionInsertUpdateRetrieve(t *testing.T) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
+		err := db.Update(InsertSchemaVersion(1))
+		require.NoError(t, err)
+
+		err = db.Update(UpdateSchemaVersion(2))
+		require.NoError(t, err)
+
+		var version uint64
+		err = db.View(RetrieveSchemaVersion(&version))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), version)
+	})
+}
+
+func TestSummarizePrefixes(t *testing.T) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
+		require.NoError(t, db.Update(InsertSchemaVersion(1)))
+		require.NoError(t, db.Update(InsertFinalizedHeight(10)))
+		for i := 0; i < 3; i++ {
+			header := unittest.BlockHeaderFixture()
+			require.NoError(t, db.Update(InsertHeader(header.ID(), &header)))
+		}
+
+		var stats []PrefixStats
+		err := db.View(SummarizePrefixes(&stats))
+		require.NoError(t, err)
+
+		require.Len(t, stats, 3)
+		assert.Equal(t, byte(codeSchema), stats[0].Code)
+		assert.Equal(t, uint64(1), stats[0].Keys)
+		assert.Equal(t, uint64(1), stats[0].KeySize)
+		assert.Equal(t, byte(codeFinalizedHeight), stats[1].Code)
+		assert.Equal(t, byte(codeHeader), stats[2].Code)
+		assert.Equal(t, uint64(3), stats[2].Keys)
+		assert.Equal(t, uint64(3*(1+32)), stats[2].KeySize)
+		assert.NotZero(t, stats[2].ValueSize)
+
+		assert.Equal(t, "header", PrefixName(stats[2].Code))
+		assert.Equal(t, "unknown", PrefixName(255))
+	})
+}
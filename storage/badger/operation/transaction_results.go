@@ -3,24 +3,23 @@
 package operation
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
-func InsertTransactionResult(blockID flow.Identifier, transactionResult *flow.TransactionResult) func(*badger.Txn) error {
+func InsertTransactionResult(blockID flow.Identifier, transactionResult *flow.TransactionResult) func(kv.Txn) error {
 	return insert(makePrefix(codeTransactionResult, blockID, transactionResult.TransactionID), transactionResult)
 }
 
-func BatchInsertTransactionResult(blockID flow.Identifier, transactionResult *flow.TransactionResult) func(batch *badger.WriteBatch) error {
+func BatchInsertTransactionResult(blockID flow.Identifier, transactionResult *flow.TransactionResult) func(batch kv.WriteBatch) error {
 	return batchInsert(makePrefix(codeTransactionResult, blockID, transactionResult.TransactionID), transactionResult)
 }
 
-func RetrieveTransactionResult(blockID flow.Identifier, transactionID flow.Identifier, transactionResult *flow.TransactionResult) func(*badger.Txn) error {
+func RetrieveTransactionResult(blockID flow.Identifier, transactionID flow.Identifier, transactionResult *flow.TransactionResult) func(kv.Txn) error {
 	return retrieve(makePrefix(codeTransactionResult, blockID, transactionID), transactionResult)
 }
 
-func LookupTransactionResultsByBlockID(blockID flow.Identifier, txResults *[]flow.TransactionResult) func(*badger.Txn) error {
+func LookupTransactionResultsByBlockID(blockID flow.Identifier, txResults *[]flow.TransactionResult) func(kv.Txn) error {
 
 	txErrIterFunc := func() (checkFunc, createFunc, handleFunc) {
 		check := func(_ []byte) bool {
@@ -39,3 +38,9 @@ func LookupTransactionResultsByBlockID(blockID flow.Identifier, txResults *[]flo
 
 	return traverse(makePrefix(codeTransactionResult, blockID), txErrIterFunc)
 }
+
+// BatchRemoveTransactionResultsByBlockID removes the transaction results of the block in the write batch, and
+// adds their size to the reclaimed bytes.
+func BatchRemoveTransactionResultsByBlockID(blockID flow.Identifier, writeBatch kv.WriteBatch, reclaimed *uint64) func(kv.Txn) error {
+	return batchRemoveByPrefix(makePrefix(codeTransactionResult, blockID), writeBatch, reclaimed)
+}
@@ -4,11 +4,10 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // IndexNewBlock will add parent-child index for the new block.
@@ -24,8 +23,8 @@ import (
 //      - if the parent block is zero, then we don't need to add this index.
 //      - if the parent block doesn't exist, then we will insert the child index instead of
 // 				updating
-func IndexNewBlock(blockID flow.Identifier, parentID flow.Identifier) func(*badger.Txn) error {
-	return func(tx *badger.Txn) error {
+func IndexNewBlock(blockID flow.Identifier, parentID flow.Identifier) func(kv.Txn) error {
+	return func(tx kv.Txn) error {
 		// Step 1: index the child for the new block.
 		// the new block has no child, so adding an empty child index for it
 		err := operation.InsertBlockChildren(blockID, nil)(tx)
@@ -47,7 +46,7 @@ func IndexNewBlock(blockID flow.Identifier, parentID flow.Identifier) func(*badg
 		var childrenIDs []flow.Identifier
 		err = operation.RetrieveBlockChildren(parentID, &childrenIDs)(tx)
 
-		var saveIndex func(blockID flow.Identifier, childrenIDs []flow.Identifier) func(*badger.Txn) error
+		var saveIndex func(blockID flow.Identifier, childrenIDs []flow.Identifier) func(kv.Txn) error
 		if errors.Is(err, storage.ErrNotFound) {
 			saveIndex = operation.InsertBlockChildren
 		} else if err != nil {
@@ -77,6 +76,6 @@ func IndexNewBlock(blockID flow.Identifier, parentID flow.Identifier) func(*badg
 }
 
 // LookupBlockChildren looks up the IDs of all child blocks of the given parent block.
-func LookupBlockChildren(blockID flow.Identifier, childrenIDs *[]flow.Identifier) func(tx *badger.Txn) error {
+func LookupBlockChildren(blockID flow.Identifier, childrenIDs *[]flow.Identifier) func(tx kv.Txn) error {
 	return operation.RetrieveBlockChildren(blockID, childrenIDs)
 }
@@ -3,17 +3,17 @@ package procedure
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestInsertRetrieveClusterBlock(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		block := unittest.ClusterBlockFixture()
 
 		err := db.Update(InsertClusterBlock(&block))
@@ -28,7 +28,7 @@ func TestInsertRetrieveClusterBlock(t *testing.T) {
 }
 
 func TestFinalizeClusterBlock(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	unittest.RunWithEachDB(t, func(db kv.DB) {
 		parent := unittest.ClusterBlockFixture()
 
 		block := unittest.ClusterBlockWithParent(&parent)
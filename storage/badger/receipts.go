@@ -4,26 +4,25 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // ExecutionReceipts implements storage for execution receipts.
 type ExecutionReceipts struct {
-	db      *badger.DB
+	db      kv.DB
 	results *ExecutionResults
 	cache   *Cache
 }
 
 // NewExecutionReceipts Creates ExecutionReceipts instance which is a database of receipts which
 // supports storing and indexing receipts by receipt ID and block ID.
-func NewExecutionReceipts(collector module.CacheMetrics, db *badger.DB, results *ExecutionResults, cacheSize uint) *ExecutionReceipts {
+func NewExecutionReceipts(collector module.CacheMetrics, db kv.DB, results *ExecutionResults, cacheSize uint) *ExecutionReceipts {
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		receipt := val.(*flow.ExecutionReceipt)
 		receiptID := receipt.ID()
@@ -54,9 +53,9 @@ func NewExecutionReceipts(collector module.CacheMetrics, db *badger.DB, results
 		}
 	}
 
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		receiptID := key.(flow.Identifier)
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			var meta flow.ExecutionReceiptMeta
 			err := operation.RetrieveExecutionReceiptMeta(receiptID, &meta)(tx)
 			if err != nil {
@@ -85,9 +84,9 @@ func (r *ExecutionReceipts) storeTx(receipt *flow.ExecutionReceipt) func(*transa
 	return r.cache.PutTx(receipt.ID(), receipt)
 }
 
-func (r *ExecutionReceipts) byID(receiptID flow.Identifier) func(*badger.Txn) (*flow.ExecutionReceipt, error) {
+func (r *ExecutionReceipts) byID(receiptID flow.Identifier) func(kv.Txn) (*flow.ExecutionReceipt, error) {
 	retrievalOps := r.cache.Get(receiptID) // assemble DB operations to retrieve receipt (no execution)
-	return func(tx *badger.Txn) (*flow.ExecutionReceipt, error) {
+	return func(tx kv.Txn) (*flow.ExecutionReceipt, error) {
 		val, err := retrievalOps(tx) // execute operations to retrieve receipt
 		if err != nil {
 			return nil, err
@@ -96,8 +95,8 @@ func (r *ExecutionReceipts) byID(receiptID flow.Identifier) func(*badger.Txn) (*
 	}
 }
 
-func (r *ExecutionReceipts) byBlockID(blockID flow.Identifier) func(*badger.Txn) ([]*flow.ExecutionReceipt, error) {
-	return func(tx *badger.Txn) ([]*flow.ExecutionReceipt, error) {
+func (r *ExecutionReceipts) byBlockID(blockID flow.Identifier) func(kv.Txn) ([]*flow.ExecutionReceipt, error) {
+	return func(tx kv.Txn) ([]*flow.ExecutionReceipt, error) {
 		var receiptIDs []flow.Identifier
 		err := operation.LookupExecutionReceipts(blockID, &receiptIDs)(tx)
 		if err != nil && !errors.Is(err, storage.ErrNotFound) {
@@ -4,33 +4,32 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // ExecutionResults implements persistent storage for execution results.
 type ExecutionResults struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewExecutionResults(collector module.CacheMetrics, db *badger.DB) *ExecutionResults {
+func NewExecutionResults(collector module.CacheMetrics, db kv.DB) *ExecutionResults {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		result := val.(*flow.ExecutionResult)
 		return transaction.WithTx(operation.SkipDuplicates(operation.InsertExecutionResult(result)))
 	}
 
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		resultID := key.(flow.Identifier)
 		var result flow.ExecutionResult
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveExecutionResult(resultID, &result)(tx)
 			return &result, err
 		}
@@ -51,8 +50,8 @@ func (r *ExecutionResults) store(result *flow.ExecutionResult) func(*transaction
 	return r.cache.PutTx(result.ID(), result)
 }
 
-func (r *ExecutionResults) byID(resultID flow.Identifier) func(*badger.Txn) (*flow.ExecutionResult, error) {
-	return func(tx *badger.Txn) (*flow.ExecutionResult, error) {
+func (r *ExecutionResults) byID(resultID flow.Identifier) func(kv.Txn) (*flow.ExecutionResult, error) {
+	return func(tx kv.Txn) (*flow.ExecutionResult, error) {
 		val, err := r.cache.Get(resultID)(tx)
 		if err != nil {
 			return nil, err
@@ -61,8 +60,8 @@ func (r *ExecutionResults) byID(resultID flow.Identifier) func(*badger.Txn) (*fl
 	}
 }
 
-func (r *ExecutionResults) byBlockID(blockID flow.Identifier) func(*badger.Txn) (*flow.ExecutionResult, error) {
-	return func(tx *badger.Txn) (*flow.ExecutionResult, error) {
+func (r *ExecutionResults) byBlockID(blockID flow.Identifier) func(kv.Txn) (*flow.ExecutionResult, error) {
+	return func(tx kv.Txn) (*flow.ExecutionResult, error) {
 		var resultID flow.Identifier
 		err := operation.LookupExecutionResult(blockID, &resultID)(tx)
 		if err != nil {
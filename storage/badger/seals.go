@@ -5,21 +5,20 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type Seals struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
-func NewSeals(collector module.CacheMetrics, db *badger.DB) *Seals {
+func NewSeals(collector module.CacheMetrics, db kv.DB) *Seals {
 
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		sealID := key.(flow.Identifier)
@@ -27,10 +26,10 @@ func NewSeals(collector module.CacheMetrics, db *badger.DB) *Seals {
 		return transaction.WithTx(operation.SkipDuplicates(operation.InsertSeal(sealID, seal)))
 	}
 
-	retrieve := func(key interface{}) func(*badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(kv.Txn) (interface{}, error) {
 		sealID := key.(flow.Identifier)
 		var seal flow.Seal
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveSeal(sealID, &seal)(tx)
 			return &seal, err
 		}
@@ -51,8 +50,8 @@ func (s *Seals) storeTx(seal *flow.Seal) func(*transaction.Tx) error {
 	return s.cache.PutTx(seal.ID(), seal)
 }
 
-func (s *Seals) retrieveTx(sealID flow.Identifier) func(*badger.Txn) (*flow.Seal, error) {
-	return func(tx *badger.Txn) (*flow.Seal, error) {
+func (s *Seals) retrieveTx(sealID flow.Identifier) func(kv.Txn) (*flow.Seal, error) {
+	return func(tx kv.Txn) (*flow.Seal, error) {
 		val, err := s.cache.Get(sealID)(tx)
 		if err != nil {
 			return nil, err
@@ -3,17 +3,16 @@ package badger
 import (
 	"fmt"
 
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 type TransactionResults struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
@@ -37,10 +36,10 @@ func KeyToBlockIDTransactionID(key string) (flow.Identifier, flow.Identifier, er
 	return blockID, txID, nil
 }
 
-func NewTransactionResults(collector module.CacheMetrics, db *badger.DB, transactionResultsCacheSize uint) *TransactionResults {
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+func NewTransactionResults(collector module.CacheMetrics, db kv.DB, transactionResultsCacheSize uint) *TransactionResults {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		var txResult flow.TransactionResult
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 
 			blockID, txID, err := KeyToBlockIDTransactionID(key.(string))
 			if err != nil {
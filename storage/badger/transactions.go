@@ -1,33 +1,32 @@
 package badger
 
 import (
-	"github.com/dgraph-io/badger/v2"
-
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/kv"
 )
 
 // Transactions ...
 type Transactions struct {
-	db    *badger.DB
+	db    kv.DB
 	cache *Cache
 }
 
 // NewTransactions ...
-func NewTransactions(cacheMetrics module.CacheMetrics, db *badger.DB) *Transactions {
+func NewTransactions(cacheMetrics module.CacheMetrics, db kv.DB) *Transactions {
 	store := func(key interface{}, val interface{}) func(*transaction.Tx) error {
 		txID := key.(flow.Identifier)
 		flowTx := val.(*flow.TransactionBody)
 		return transaction.WithTx(operation.SkipDuplicates(operation.InsertTransaction(txID, flowTx)))
 	}
 
-	retrieve := func(key interface{}) func(tx *badger.Txn) (interface{}, error) {
+	retrieve := func(key interface{}) func(tx kv.Txn) (interface{}, error) {
 		txID := key.(flow.Identifier)
 		var flowTx flow.TransactionBody
-		return func(tx *badger.Txn) (interface{}, error) {
+		return func(tx kv.Txn) (interface{}, error) {
 			err := operation.RetrieveTransaction(txID, &flowTx)(tx)
 			return &flowTx, err
 		}
@@ -60,8 +59,8 @@ func (t *Transactions) storeTx(flowTx *flow.TransactionBody) func(*transaction.T
 	return t.cache.PutTx(flowTx.ID(), flowTx)
 }
 
-func (t *Transactions) retrieveTx(txID flow.Identifier) func(*badger.Txn) (*flow.TransactionBody, error) {
-	return func(tx *badger.Txn) (*flow.TransactionBody, error) {
+func (t *Transactions) retrieveTx(txID flow.Identifier) func(kv.Txn) (*flow.TransactionBody, error) {
+	return func(tx kv.Txn) (*flow.TransactionBody, error) {
 		val, err := t.cache.Get(txID)(tx)
 		if err != nil {
 			return nil, err
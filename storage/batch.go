@@ -1,14 +1,10 @@
 package storage
 
-import "github.com/dgraph-io/badger/v2"
-
-type Transaction interface {
-	Set(key, val []byte) error
-}
+import "github.com/onflow/flow-go/storage/kv"
 
 // BatchStorage
 type BatchStorage interface {
-	GetWriter() *badger.WriteBatch
+	GetWriter() kv.WriteBatch
 
 	// OnSucceed adds a callback to execute after the batch has
 	// been successfully flushed.
@@ -16,6 +16,35 @@ type Events interface {
 
 	// ByBlockIDEventType returns the events for the given block ID and event type
 	ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error)
+
+	// BatchIndexByEventType indexes the events of the block at the given height by event type in a given batch.
+	// The index is only populated by the execution nodes, along with the events of the blocks they execute. The
+	// access nodes do not store events, since they fetch them from the execution nodes, and do not use the index.
+	BatchIndexByEventType(blockID flow.Identifier, height uint64, events []flow.EventsList, batch BatchStorage) error
+
+	// IndexedByEventType returns whether the events of the given block ID are indexed by event type
+	IndexedByEventType(blockID flow.Identifier) (bool, error)
+
+	// ByEventTypeHeightRange returns up to limit events of the given type emitted between the start and end heights
+	// (inclusive), ordered by height, block, transaction index and event index, starting from the cursor if not nil.
+	// The events of all the blocks indexed at each height are returned, including the blocks which are not finalized.
+	// It returns the cursor of the next page, nil if there is none. A limit of 0 returns all the events.
+	ByEventTypeHeightRange(eventType flow.EventType, startHeight, endHeight uint64, cursor *EventCursor, limit uint) ([]IndexedEvent, *EventCursor, error)
+}
+
+// IndexedEvent is an event with the block which emitted it.
+type IndexedEvent struct {
+	BlockID     flow.Identifier
+	BlockHeight uint64
+	flow.Event
+}
+
+// EventCursor is the position of the first event of a page of events indexed by type.
+type EventCursor struct {
+	Height           uint64
+	BlockID          flow.Identifier
+	TransactionIndex uint32
+	EventIndex       uint32
 }
 
 type ServiceEvents interface {
@@ -0,0 +1,177 @@
+// Package badgerkv implements the key-value storage engine of the protocol database with badger.
+package badgerkv
+
+import (
+	"errors"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// DB is a key-value database stored in badger.
+type DB struct {
+	db *badger.DB
+}
+
+var _ kv.DB = (*DB)(nil)
+
+// New returns the key-value database stored in the given badger database.
+func New(db *badger.DB) *DB {
+	return &DB{db: db}
+}
+
+// Badger returns the underlying badger database, for the operations specific to badger, such as backups and
+// value log garbage collection.
+func (db *DB) Badger() *badger.DB {
+	return db.db
+}
+
+func (db *DB) View(f func(tx kv.Txn) error) error {
+	return db.db.View(func(tx *badger.Txn) error {
+		return f(&Txn{tx: tx})
+	})
+}
+
+func (db *DB) Update(f func(tx kv.Txn) error) error {
+	tx := db.NewTransaction(true)
+	defer tx.Discard()
+
+	err := f(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *DB) NewTransaction(update bool) kv.Transaction {
+	return &Txn{tx: db.db.NewTransaction(update)}
+}
+
+func (db *DB) NewWriteBatch() kv.WriteBatch {
+	return &WriteBatch{batch: db.db.NewWriteBatch()}
+}
+
+func (db *DB) Close() error {
+	return db.db.Close()
+}
+
+// Txn is a transaction of a badger database.
+type Txn struct {
+	tx *badger.Txn
+}
+
+var _ kv.Transaction = (*Txn)(nil)
+
+// NewTxn returns the transaction of the key-value database wrapping the given badger transaction.
+func NewTxn(tx *badger.Txn) *Txn {
+	return &Txn{tx: tx}
+}
+
+func (t *Txn) Get(key []byte, handle func(val []byte) error) error {
+	item, err := t.tx.Get(key)
+	if err != nil {
+		return convertError(err)
+	}
+	return item.Value(handle)
+}
+
+func (t *Txn) NewIterator(options kv.IteratorOptions) kv.Iterator {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = options.Prefix
+	opts.Reverse = options.Reverse
+	opts.PrefetchValues = !options.KeysOnly
+	return &Iterator{it: t.tx.NewIterator(opts)}
+}
+
+func (t *Txn) Set(key, val []byte) error {
+	return convertError(t.tx.Set(key, val))
+}
+
+func (t *Txn) Delete(key []byte) error {
+	return convertError(t.tx.Delete(key))
+}
+
+func (t *Txn) Commit() error {
+	return convertError(t.tx.Commit())
+}
+
+func (t *Txn) Discard() {
+	t.tx.Discard()
+}
+
+// Iterator is an iterator of a badger transaction.
+type Iterator struct {
+	it *badger.Iterator
+}
+
+var _ kv.Iterator = (*Iterator)(nil)
+
+func (i *Iterator) Seek(key []byte) {
+	i.it.Seek(key)
+}
+
+func (i *Iterator) Valid() bool {
+	return i.it.Valid()
+}
+
+func (i *Iterator) Next() {
+	i.it.Next()
+}
+
+func (i *Iterator) Key() []byte {
+	return i.it.Item().Key()
+}
+
+func (i *Iterator) Value(handle func(val []byte) error) error {
+	return i.it.Item().Value(handle)
+}
+
+func (i *Iterator) EstimatedSize() int64 {
+	return i.it.Item().EstimatedSize()
+}
+
+func (i *Iterator) Close() {
+	i.it.Close()
+}
+
+// WriteBatch is a write batch of a badger database.
+type WriteBatch struct {
+	batch *badger.WriteBatch
+}
+
+var _ kv.WriteBatch = (*WriteBatch)(nil)
+
+func (b *WriteBatch) Set(key, val []byte) error {
+	return convertError(b.batch.Set(key, val))
+}
+
+func (b *WriteBatch) Delete(key []byte) error {
+	return convertError(b.batch.Delete(key))
+}
+
+func (b *WriteBatch) Flush() error {
+	return convertError(b.batch.Flush())
+}
+
+func (b *WriteBatch) Cancel() {
+	b.batch.Cancel()
+}
+
+// convertError converts the errors of badger to the errors of the key-value database.
+func convertError(err error) error {
+	switch {
+	case errors.Is(err, badger.ErrKeyNotFound):
+		return kv.ErrNotFound
+	case errors.Is(err, badger.ErrConflict):
+		return kv.ErrConflict
+	case errors.Is(err, badger.ErrReadOnlyTxn):
+		return kv.ErrReadOnly
+	case errors.Is(err, badger.ErrEmptyKey):
+		return kv.ErrEmptyKey
+	case errors.Is(err, badger.ErrDiscardedTxn):
+		return kv.ErrDiscarded
+	default:
+		return err
+	}
+}
@@ -0,0 +1,132 @@
+// Package kv defines the key-value storage engine the protocol database is stored in. The operations of
+// storage/badger/operation and storage/badger/procedure are written against these interfaces, so that the
+// engine can be swapped without changing them.
+package kv
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned when a key is not found in the database.
+	// Note: the operations translate it to storage.ErrNotFound, which is the error exposed to the other modules.
+	ErrNotFound = errors.New("key not found")
+
+	// ErrConflict is returned when a transaction conflicts with another transaction committed after it was
+	// started, and can be retried.
+	ErrConflict = errors.New("transaction conflict, please retry")
+
+	// ErrReadOnly is returned when writing in a read-only transaction.
+	ErrReadOnly = errors.New("no writes are allowed in a read-only transaction")
+
+	// ErrEmptyKey is returned when writing an empty key.
+	ErrEmptyKey = errors.New("key cannot be empty")
+
+	// ErrDiscarded is returned when committing a transaction which was discarded.
+	ErrDiscarded = errors.New("transaction has been discarded")
+)
+
+// Reader reads from a consistent snapshot of the database.
+type Reader interface {
+	// Get calls the given function with the value stored under the given key, which is only valid until the
+	// function returns. It returns ErrNotFound if the key does not exist.
+	Get(key []byte, handle func(val []byte) error) error
+
+	// NewIterator returns an iterator over the keys of the snapshot, which must be closed after use.
+	NewIterator(options IteratorOptions) Iterator
+}
+
+// Writer writes to the database.
+type Writer interface {
+	// Set stores the given value under the given key, overwriting any existing value. Neither the key nor the
+	// value may be modified afterwards.
+	Set(key, val []byte) error
+
+	// Delete removes the given key. Deleting a key which does not exist is a no-op.
+	Delete(key []byte) error
+}
+
+// Txn is a transaction of the database. It reads from a snapshot of the database taken when the transaction
+// was started, including its own writes, and its writes are applied atomically when it is committed.
+type Txn interface {
+	Reader
+	Writer
+}
+
+// Transaction is a transaction managed by the caller, which must be discarded after use.
+type Transaction interface {
+	Txn
+
+	// Commit applies the writes of the transaction. It returns ErrConflict if a key read by the transaction
+	// was written by another transaction committed since the transaction was started.
+	Commit() error
+
+	// Discard releases the transaction, and drops its writes if it was not committed.
+	Discard()
+}
+
+// WriteBatch batches a large number of writes, which are applied when the batch is flushed. The writes of a
+// batch are not checked for conflicts and are not guaranteed to be applied atomically.
+type WriteBatch interface {
+	Writer
+
+	// Flush applies the writes of the batch and waits until they are applied.
+	Flush() error
+
+	// Cancel drops the writes of the batch which were not flushed.
+	Cancel()
+}
+
+// IteratorOptions are the options of an iterator.
+type IteratorOptions struct {
+	// Prefix restricts the iteration to the keys with the given prefix.
+	Prefix []byte
+	// Reverse iterates over the keys in decreasing order.
+	Reverse bool
+	// KeysOnly is a hint that the values will not be read, so they do not need to be loaded.
+	KeysOnly bool
+}
+
+// Iterator iterates over the keys of a snapshot of the database in lexicographic order.
+type Iterator interface {
+	// Seek moves to the smallest key greater than or equal to the given key, or to the largest key smaller
+	// than or equal to the given key for reverse iterations.
+	Seek(key []byte)
+
+	// Valid returns false once the iteration is over.
+	Valid() bool
+
+	// Next moves to the next key.
+	Next()
+
+	// Key returns the current key, which is only valid until the iterator moves.
+	Key() []byte
+
+	// Value calls the given function with the current value, which is only valid until the function returns.
+	Value(handle func(val []byte) error) error
+
+	// EstimatedSize returns the approximate size of the current key and value, without loading the value.
+	EstimatedSize() int64
+
+	// Close releases the iterator.
+	Close()
+}
+
+// DB is a transactional key-value database.
+type DB interface {
+	// View runs the given function in a read-only transaction.
+	View(func(tx Txn) error) error
+
+	// Update runs the given function in a read-write transaction, which is committed if the function
+	// succeeds. It returns ErrConflict if the transaction conflicts with another transaction.
+	Update(func(tx Txn) error) error
+
+	// NewTransaction starts a transaction, which is read-write if update is true.
+	NewTransaction(update bool) Transaction
+
+	// NewWriteBatch starts a write batch.
+	NewWriteBatch() WriteBatch
+
+	// Close closes the database, after which it can no longer be used.
+	Close() error
+}
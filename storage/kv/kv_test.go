@@ -0,0 +1,237 @@
+package kv_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
+	"github.com/onflow/flow-go/storage/kv/memkv"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// runWithEachDB runs the given test against each implementation of the key-value database.
+func runWithEachDB(t *testing.T, f func(t *testing.T, db kv.DB)) {
+	t.Run("badger", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			f(t, badgerkv.New(db))
+		})
+	})
+	t.Run("memory", func(t *testing.T) {
+		f(t, memkv.New())
+	})
+}
+
+func get(t *testing.T, tx kv.Reader, key string) (string, error) {
+	var val string
+	err := tx.Get([]byte(key), func(v []byte) error {
+		val = string(v)
+		return nil
+	})
+	return val, err
+}
+
+// keys returns the keys of the iteration from the given key, with the given options.
+func keys(tx kv.Reader, seek string, options kv.IteratorOptions) []string {
+	it := tx.NewIterator(options)
+	defer it.Close()
+
+	var keys []string
+	for it.Seek([]byte(seek)); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	return keys
+}
+
+func set(tx kv.Writer, keys ...string) error {
+	for _, key := range keys {
+		err := tx.Set([]byte(key), []byte("value-"+key))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestGetSetDelete(t *testing.T) {
+	runWithEachDB(t, func(t *testing.T, db kv.DB) {
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a", "b")
+		}))
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			val, err := get(t, tx, "a")
+			require.NoError(t, err)
+			assert.Equal(t, "value-a", val)
+
+			_, err = get(t, tx, "c")
+			assert.ErrorIs(t, err, kv.ErrNotFound)
+
+			assert.ErrorIs(t, tx.Set([]byte("c"), nil), kv.ErrReadOnly)
+			return nil
+		}))
+
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			require.NoError(t, tx.Delete([]byte("a")))
+			require.NoError(t, tx.Delete([]byte("c")))
+			assert.ErrorIs(t, tx.Set(nil, nil), kv.ErrEmptyKey)
+
+			// the writes of the transaction are visible in the transaction
+			_, err := get(t, tx, "a")
+			assert.ErrorIs(t, err, kv.ErrNotFound)
+			return nil
+		}))
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			_, err := get(t, tx, "a")
+			assert.ErrorIs(t, err, kv.ErrNotFound)
+			val, err := get(t, tx, "b")
+			require.NoError(t, err)
+			assert.Equal(t, "value-b", val)
+			return nil
+		}))
+
+		// the writes of a failed transaction are dropped
+		require.Error(t, db.Update(func(tx kv.Txn) error {
+			require.NoError(t, set(tx, "d"))
+			return fmt.Errorf("failed")
+		}))
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			_, err := get(t, tx, "d")
+			assert.ErrorIs(t, err, kv.ErrNotFound)
+			return nil
+		}))
+	})
+}
+
+func TestIterator(t *testing.T) {
+	runWithEachDB(t, func(t *testing.T, db kv.DB) {
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a1", "a3", "b1", "b2", "b3", "c1")
+		}))
+
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			// the pending writes are included
+			require.NoError(t, set(tx, "b4", "a2"))
+			require.NoError(t, tx.Delete([]byte("b2")))
+
+			assert.Equal(t, []string{"a1", "a2", "a3", "b1", "b3", "b4", "c1"}, keys(tx, "", kv.IteratorOptions{}))
+			assert.Equal(t, []string{"b1", "b3", "b4", "c1"}, keys(tx, "b", kv.IteratorOptions{}))
+			assert.Equal(t, []string{"b3", "b4"}, keys(tx, "b2", kv.IteratorOptions{Prefix: []byte("b")}))
+			assert.Equal(t, []string{"b1", "b3", "b4"}, keys(tx, "", kv.IteratorOptions{Prefix: []byte("b"), KeysOnly: true}))
+			assert.Equal(t, []string{"c1", "b4", "b3", "b1", "a3", "a2", "a1"}, keys(tx, "", kv.IteratorOptions{Reverse: true}))
+			assert.Equal(t, []string{"b3", "b1", "a3", "a2", "a1"}, keys(tx, "b3", kv.IteratorOptions{Reverse: true}))
+			assert.Equal(t, []string{"b1"}, keys(tx, "b2", kv.IteratorOptions{Prefix: []byte("b"), Reverse: true}))
+			return nil
+		}))
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			it := tx.NewIterator(kv.IteratorOptions{Prefix: []byte("c")})
+			defer it.Close()
+
+			it.Seek(nil)
+			require.True(t, it.Valid())
+			assert.Equal(t, []byte("c1"), it.Key())
+			assert.Positive(t, it.EstimatedSize())
+			require.NoError(t, it.Value(func(val []byte) error {
+				assert.Equal(t, []byte("value-c1"), val)
+				return nil
+			}))
+			it.Next()
+			assert.False(t, it.Valid())
+			return nil
+		}))
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	runWithEachDB(t, func(t *testing.T, db kv.DB) {
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a")
+		}))
+
+		tx := db.NewTransaction(false)
+		defer tx.Discard()
+
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			require.NoError(t, tx.Delete([]byte("a")))
+			return set(tx, "b")
+		}))
+
+		// the transaction does not see the writes committed after it was started
+		val, err := get(t, tx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "value-a", val)
+		_, err = get(t, tx, "b")
+		assert.ErrorIs(t, err, kv.ErrNotFound)
+		assert.Equal(t, []string{"a"}, keys(tx, "", kv.IteratorOptions{}))
+	})
+}
+
+func TestConflict(t *testing.T) {
+	runWithEachDB(t, func(t *testing.T, db kv.DB) {
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a", "b")
+		}))
+
+		read := db.NewTransaction(true)
+		defer read.Discard()
+		_, err := get(t, read, "a")
+		require.NoError(t, err)
+		require.NoError(t, set(read, "c"))
+
+		blind := db.NewTransaction(true)
+		defer blind.Discard()
+		require.NoError(t, set(blind, "a"))
+
+		iterated := db.NewTransaction(true)
+		defer iterated.Discard()
+		assert.Equal(t, []string{"a", "b"}, keys(iterated, "", kv.IteratorOptions{}))
+		require.NoError(t, set(iterated, "d"))
+
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a")
+		}))
+
+		// the transactions which read the key written since they were started conflict
+		assert.ErrorIs(t, read.Commit(), kv.ErrConflict)
+		assert.ErrorIs(t, iterated.Commit(), kv.ErrConflict)
+		assert.NoError(t, blind.Commit())
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			assert.Equal(t, []string{"a", "b"}, keys(tx, "", kv.IteratorOptions{}))
+			return nil
+		}))
+	})
+}
+
+func TestWriteBatch(t *testing.T) {
+	runWithEachDB(t, func(t *testing.T, db kv.DB) {
+		require.NoError(t, db.Update(func(tx kv.Txn) error {
+			return set(tx, "a")
+		}))
+
+		batch := db.NewWriteBatch()
+		require.NoError(t, set(batch, "b", "c"))
+		require.NoError(t, batch.Delete([]byte("a")))
+		require.NoError(t, batch.Flush())
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			assert.Equal(t, []string{"b", "c"}, keys(tx, "", kv.IteratorOptions{}))
+			return nil
+		}))
+
+		batch = db.NewWriteBatch()
+		require.NoError(t, set(batch, "d"))
+		batch.Cancel()
+
+		require.NoError(t, db.View(func(tx kv.Txn) error {
+			assert.Equal(t, []string{"b", "c"}, keys(tx, "", kv.IteratorOptions{}))
+			return nil
+		}))
+	})
+}
@@ -0,0 +1,425 @@
+// Package memkv implements the key-value storage engine of the protocol database in memory, with the same
+// semantics as badger, to speed up the tests which do not depend on badger.
+package memkv
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/storage/kv"
+)
+
+// DB is a key-value database stored in memory. Like badger, it keeps multiple versions of the values, so that
+// each transaction reads from a snapshot of the database, and detects the conflicts between transactions on
+// commit.
+type DB struct {
+	mu       sync.RWMutex
+	keys     []string             // sorted keys of all the values, replaced on each commit
+	values   map[string][]version // versions of the values of each key, by increasing timestamp
+	ts       uint64               // timestamp of the latest commit
+	snapshot map[uint64]int       // number of transactions reading from the snapshot at each timestamp
+}
+
+var _ kv.DB = (*DB)(nil)
+
+// version is the value of a key written by the commit at the given timestamp.
+type version struct {
+	ts      uint64
+	val     []byte
+	deleted bool
+}
+
+// New returns an empty key-value database.
+func New() *DB {
+	return &DB{
+		values:   make(map[string][]version),
+		snapshot: make(map[uint64]int),
+	}
+}
+
+func (db *DB) View(f func(tx kv.Txn) error) error {
+	tx := db.NewTransaction(false)
+	defer tx.Discard()
+
+	return f(tx)
+}
+
+func (db *DB) Update(f func(tx kv.Txn) error) error {
+	tx := db.NewTransaction(true)
+	defer tx.Discard()
+
+	err := f(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *DB) NewTransaction(update bool) kv.Transaction {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.snapshot[db.ts]++
+	return &Txn{
+		db:     db,
+		update: update,
+		readTs: db.ts,
+		keys:   db.keys,
+		writes: make(map[string]write),
+		reads:  make(map[string]struct{}),
+	}
+}
+
+func (db *DB) NewWriteBatch() kv.WriteBatch {
+	return &WriteBatch{
+		db:     db,
+		writes: make(map[string]write),
+	}
+}
+
+func (db *DB) Close() error {
+	return nil
+}
+
+// get returns the value of the given key in the snapshot at the given timestamp.
+func (db *DB) get(key string, readTs uint64) ([]byte, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	versions := db.values[key]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].ts <= readTs {
+			return versions[i].val, !versions[i].deleted
+		}
+	}
+	return nil, false
+}
+
+// commit applies the given writes, after checking that none of the given keys read by the transaction reading
+// from the snapshot at the given timestamp was written since.
+func (db *DB) commit(writes map[string]write, reads map[string]struct{}, readTs uint64) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for key := range reads {
+		versions := db.values[key]
+		if len(versions) > 0 && versions[len(versions)-1].ts > readTs {
+			return kv.ErrConflict
+		}
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	db.ts++
+	var added []string
+	for key, w := range writes {
+		if _, ok := db.values[key]; !ok {
+			added = append(added, key)
+		}
+		db.values[key] = append(db.values[key], version{ts: db.ts, val: w.val, deleted: w.deleted})
+	}
+
+	// drop the versions which can no longer be read by any transaction
+	oldest := db.ts
+	for ts := range db.snapshot {
+		if ts < oldest {
+			oldest = ts
+		}
+	}
+	for key := range writes {
+		versions := db.values[key]
+		keep := 0
+		for keep < len(versions)-1 && versions[keep+1].ts <= oldest {
+			keep++
+		}
+		db.values[key] = versions[keep:]
+	}
+
+	// the keys are replaced rather than updated, as they are shared with the running transactions
+	if len(added) > 0 {
+		sort.Strings(added)
+		keys := make([]string, 0, len(db.keys)+len(added))
+		i, j := 0, 0
+		for i < len(db.keys) || j < len(added) {
+			if j == len(added) || (i < len(db.keys) && db.keys[i] < added[j]) {
+				keys = append(keys, db.keys[i])
+				i++
+			} else {
+				keys = append(keys, added[j])
+				j++
+			}
+		}
+		db.keys = keys
+	}
+
+	return nil
+}
+
+// release releases the snapshot at the given timestamp.
+func (db *DB) release(readTs uint64) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.snapshot[readTs]--
+	if db.snapshot[readTs] == 0 {
+		delete(db.snapshot, readTs)
+	}
+}
+
+// write is a pending write of a transaction or a write batch.
+type write struct {
+	val     []byte
+	deleted bool
+}
+
+// Txn is a transaction of a key-value database stored in memory.
+type Txn struct {
+	db        *DB
+	update    bool
+	readTs    uint64
+	keys      []string
+	writes    map[string]write
+	reads     map[string]struct{}
+	discarded bool
+}
+
+var _ kv.Transaction = (*Txn)(nil)
+
+func (t *Txn) Get(key []byte, handle func(val []byte) error) error {
+	if len(key) == 0 {
+		return kv.ErrEmptyKey
+	}
+
+	val, ok := t.get(string(key))
+	if !ok {
+		return kv.ErrNotFound
+	}
+	return handle(val)
+}
+
+// get returns the value of the given key, including the pending writes of the transaction.
+func (t *Txn) get(key string) ([]byte, bool) {
+	if w, ok := t.writes[key]; ok {
+		return w.val, !w.deleted
+	}
+	if t.update {
+		t.reads[key] = struct{}{}
+	}
+	return t.db.get(key, t.readTs)
+}
+
+func (t *Txn) NewIterator(options kv.IteratorOptions) kv.Iterator {
+	// like badger, the iterator includes the writes pending when it is created
+	writes := make(map[string]write, len(t.writes))
+	pending := make([]string, 0, len(t.writes))
+	for key, w := range t.writes {
+		writes[key] = w
+		pending = append(pending, key)
+	}
+	sort.Strings(pending)
+
+	return &Iterator{
+		txn:     t,
+		writes:  writes,
+		keys:    [2][]string{t.keys, pending},
+		prefix:  options.Prefix,
+		reverse: options.Reverse,
+	}
+}
+
+func (t *Txn) Set(key, val []byte) error {
+	return t.modify(key, write{val: val})
+}
+
+func (t *Txn) Delete(key []byte) error {
+	return t.modify(key, write{deleted: true})
+}
+
+func (t *Txn) modify(key []byte, w write) error {
+	if !t.update {
+		return kv.ErrReadOnly
+	}
+	if len(key) == 0 {
+		return kv.ErrEmptyKey
+	}
+	t.writes[string(key)] = w
+	return nil
+}
+
+func (t *Txn) Commit() error {
+	if t.discarded {
+		return kv.ErrDiscarded
+	}
+	if !t.update {
+		return nil
+	}
+	defer t.Discard()
+	return t.db.commit(t.writes, t.reads, t.readTs)
+}
+
+func (t *Txn) Discard() {
+	if t.discarded {
+		return
+	}
+	t.discarded = true
+	t.db.release(t.readTs)
+}
+
+// Iterator iterates over the keys of a transaction of a key-value database stored in memory, merging the
+// keys of its snapshot and of its pending writes.
+type Iterator struct {
+	txn     *Txn
+	writes  map[string]write
+	keys    [2][]string // keys of the snapshot and of the pending writes
+	pos     [2]int      // position of the iterator in both lists of keys
+	prefix  []byte
+	reverse bool
+	key     string
+	val     []byte
+	valid   bool
+}
+
+var _ kv.Iterator = (*Iterator)(nil)
+
+func (i *Iterator) Seek(key []byte) {
+	if len(key) == 0 {
+		key = i.prefix
+	}
+
+	for l, keys := range i.keys {
+		if i.reverse {
+			if len(key) == 0 {
+				i.pos[l] = len(keys) - 1
+				continue
+			}
+			i.pos[l] = sort.Search(len(keys), func(n int) bool { return keys[n] > string(key) }) - 1
+		} else {
+			i.pos[l] = sort.SearchStrings(keys, string(key))
+		}
+	}
+	i.advance()
+}
+
+func (i *Iterator) Valid() bool {
+	return i.valid && bytes.HasPrefix([]byte(i.key), i.prefix)
+}
+
+func (i *Iterator) Next() {
+	for l, keys := range i.keys {
+		if i.current(l) && keys[i.pos[l]] == i.key {
+			i.move(l)
+		}
+	}
+	i.advance()
+}
+
+func (i *Iterator) Key() []byte {
+	if i.txn.update {
+		i.txn.reads[i.key] = struct{}{}
+	}
+	return []byte(i.key)
+}
+
+func (i *Iterator) Value(handle func(val []byte) error) error {
+	return handle(i.val)
+}
+
+func (i *Iterator) EstimatedSize() int64 {
+	return int64(len(i.key) + len(i.val))
+}
+
+func (i *Iterator) Close() {}
+
+// current returns whether the given list of keys is not exhausted.
+func (i *Iterator) current(l int) bool {
+	return i.pos[l] >= 0 && i.pos[l] < len(i.keys[l])
+}
+
+// move moves to the next key of the given list of keys.
+func (i *Iterator) move(l int) {
+	if i.reverse {
+		i.pos[l]--
+	} else {
+		i.pos[l]++
+	}
+}
+
+// advance moves to the first key from the current positions which has a value in the transaction.
+func (i *Iterator) advance() {
+	for {
+		next := -1
+		for l := range i.keys {
+			if !i.current(l) {
+				continue
+			}
+			if next == -1 {
+				next = l
+				continue
+			}
+			key, nextKey := i.keys[l][i.pos[l]], i.keys[next][i.pos[next]]
+			if (!i.reverse && key < nextKey) || (i.reverse && key > nextKey) {
+				next = l
+			}
+		}
+		if next == -1 {
+			i.valid = false
+			return
+		}
+
+		key := i.keys[next][i.pos[next]]
+		val, ok := i.get(key)
+		if ok {
+			i.key, i.val, i.valid = key, val, true
+			return
+		}
+		for l, keys := range i.keys {
+			if i.current(l) && keys[i.pos[l]] == key {
+				i.move(l)
+			}
+		}
+	}
+}
+
+// get returns the value of the given key, without adding it to the keys read by the transaction.
+func (i *Iterator) get(key string) ([]byte, bool) {
+	if w, ok := i.writes[key]; ok {
+		return w.val, !w.deleted
+	}
+	return i.txn.db.get(key, i.txn.readTs)
+}
+
+// WriteBatch is a write batch of a key-value database stored in memory, whose writes are applied atomically.
+type WriteBatch struct {
+	db     *DB
+	writes map[string]write
+}
+
+var _ kv.WriteBatch = (*WriteBatch)(nil)
+
+func (b *WriteBatch) Set(key, val []byte) error {
+	if len(key) == 0 {
+		return kv.ErrEmptyKey
+	}
+	b.writes[string(key)] = write{val: val}
+	return nil
+}
+
+func (b *WriteBatch) Delete(key []byte) error {
+	if len(key) == 0 {
+		return kv.ErrEmptyKey
+	}
+	b.writes[string(key)] = write{deleted: true}
+	return nil
+}
+
+func (b *WriteBatch) Flush() error {
+	writes := b.writes
+	b.writes = make(map[string]write)
+	return b.db.commit(writes, nil, 0)
+}
+
+func (b *WriteBatch) Cancel() {
+	b.writes = make(map[string]write)
+}
@@ -14,6 +14,20 @@ type Events struct {
 	mock.Mock
 }
 
+// BatchIndexByEventType provides a mock function with given fields: blockID, height, events, batch
+func (_m *Events) BatchIndexByEventType(blockID flow.Identifier, height uint64, events []flow.EventsList, batch storage.BatchStorage) error {
+	ret := _m.Called(blockID, height, events, batch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(flow.Identifier, uint64, []flow.EventsList, storage.BatchStorage) error); ok {
+		r0 = rf(blockID, height, events, batch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BatchStore provides a mock function with given fields: blockID, events, batch
 func (_m *Events) BatchStore(blockID flow.Identifier, events []flow.EventsList, batch storage.BatchStorage) error {
 	ret := _m.Called(blockID, events, batch)
@@ -96,3 +110,56 @@ func (_m *Events) ByBlockIDTransactionID(blockID flow.Identifier, transactionID
 
 	return r0, r1
 }
+
+// ByEventTypeHeightRange provides a mock function with given fields: eventType, startHeight, endHeight, cursor, limit
+func (_m *Events) ByEventTypeHeightRange(eventType flow.EventType, startHeight uint64, endHeight uint64, cursor *storage.EventCursor, limit uint) ([]storage.IndexedEvent, *storage.EventCursor, error) {
+	ret := _m.Called(eventType, startHeight, endHeight, cursor, limit)
+
+	var r0 []storage.IndexedEvent
+	if rf, ok := ret.Get(0).(func(flow.EventType, uint64, uint64, *storage.EventCursor, uint) []storage.IndexedEvent); ok {
+		r0 = rf(eventType, startHeight, endHeight, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.IndexedEvent)
+		}
+	}
+
+	var r1 *storage.EventCursor
+	if rf, ok := ret.Get(1).(func(flow.EventType, uint64, uint64, *storage.EventCursor, uint) *storage.EventCursor); ok {
+		r1 = rf(eventType, startHeight, endHeight, cursor, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*storage.EventCursor)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(flow.EventType, uint64, uint64, *storage.EventCursor, uint) error); ok {
+		r2 = rf(eventType, startHeight, endHeight, cursor, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IndexedByEventType provides a mock function with given fields: blockID
+func (_m *Events) IndexedByEventType(blockID flow.Identifier) (bool, error) {
+	ret := _m.Called(blockID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(flow.Identifier) bool); ok {
+		r0 = rf(blockID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
+		r1 = rf(blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -541,6 +541,20 @@ func (m *MockEvents) EXPECT() *MockEventsMockRecorder {
 	return m.recorder
 }
 
+// BatchIndexByEventType mocks base method
+func (m *MockEvents) BatchIndexByEventType(arg0 flow.Identifier, arg1 uint64, arg2 []flow.EventsList, arg3 storage.BatchStorage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchIndexByEventType", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchIndexByEventType indicates an expected call of BatchIndexByEventType
+func (mr *MockEventsMockRecorder) BatchIndexByEventType(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchIndexByEventType", reflect.TypeOf((*MockEvents)(nil).BatchIndexByEventType), arg0, arg1, arg2, arg3)
+}
+
 // BatchStore mocks base method
 func (m *MockEvents) BatchStore(arg0 flow.Identifier, arg1 []flow.EventsList, arg2 storage.BatchStorage) error {
 	m.ctrl.T.Helper()
@@ -600,6 +614,37 @@ func (mr *MockEventsMockRecorder) ByBlockIDTransactionID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByBlockIDTransactionID", reflect.TypeOf((*MockEvents)(nil).ByBlockIDTransactionID), arg0, arg1)
 }
 
+// ByEventTypeHeightRange mocks base method
+func (m *MockEvents) ByEventTypeHeightRange(arg0 flow.EventType, arg1, arg2 uint64, arg3 *storage.EventCursor, arg4 uint) ([]storage.IndexedEvent, *storage.EventCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ByEventTypeHeightRange", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]storage.IndexedEvent)
+	ret1, _ := ret[1].(*storage.EventCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ByEventTypeHeightRange indicates an expected call of ByEventTypeHeightRange
+func (mr *MockEventsMockRecorder) ByEventTypeHeightRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByEventTypeHeightRange", reflect.TypeOf((*MockEvents)(nil).ByEventTypeHeightRange), arg0, arg1, arg2, arg3, arg4)
+}
+
+// IndexedByEventType mocks base method
+func (m *MockEvents) IndexedByEventType(arg0 flow.Identifier) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexedByEventType", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexedByEventType indicates an expected call of IndexedByEventType
+func (mr *MockEventsMockRecorder) IndexedByEventType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexedByEventType", reflect.TypeOf((*MockEvents)(nil).IndexedByEventType), arg0)
+}
+
 // MockServiceEvents is a mock of ServiceEvents interface
 type MockServiceEvents struct {
 	ctrl     *gomock.Controller
@@ -5,15 +5,15 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/module/metrics"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/kv"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func StorageLayer(t testing.TB, db *badger.DB) (*storage.Headers, *storage.Guarantees, *storage.Seals, *storage.Index, *storage.Payloads, *storage.Blocks, *storage.EpochSetups, *storage.EpochCommits, *storage.EpochStatuses, *storage.ExecutionResults) {
+func StorageLayer(t testing.TB, db kv.DB) (*storage.Headers, *storage.Guarantees, *storage.Seals, *storage.Index, *storage.Payloads, *storage.Blocks, *storage.EpochSetups, *storage.EpochCommits, *storage.EpochStatuses, *storage.ExecutionResults) {
 	metrics := metrics.NewNoopCollector()
 	headers := storage.NewHeaders(metrics, db)
 	guarantees := storage.NewGuarantees(metrics, db, storage.DefaultCacheSize)
@@ -29,8 +29,8 @@ func StorageLayer(t testing.TB, db *badger.DB) (*storage.Headers, *storage.Guara
 	return headers, guarantees, seals, index, payloads, blocks, setups, commits, statuses, results
 }
 
-func RunWithStorageLayer(t testing.TB, f func(*badger.DB, *storage.Headers, *storage.Guarantees, *storage.Seals, *storage.Index, *storage.Payloads, *storage.Blocks, *storage.EpochSetups, *storage.EpochCommits, *storage.EpochStatuses)) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+func RunWithStorageLayer(t testing.TB, f func(kv.DB, *storage.Headers, *storage.Guarantees, *storage.Seals, *storage.Index, *storage.Payloads, *storage.Blocks, *storage.EpochSetups, *storage.EpochCommits, *storage.EpochStatuses)) {
+	unittest.RunWithDB(t, func(db kv.DB) {
 		headers, guarantees, seals, index, payloads, blocks, setups, commits, statuses, _ := StorageLayer(t, db)
 		f(db, headers, guarantees, seals, index, payloads, blocks, setups, commits, statuses)
 	})
@@ -71,6 +71,9 @@ func (ps *ProtocolState) AtBlockID(blockID flow.Identifier) protocol.Snapshot {
 	} else {
 		snapshot.On("Head").Return(nil, storage.ErrNotFound)
 	}
+	if ps.root != nil && blockID == ps.root.ID() {
+		snapshot.On("SealedResult").Return(ps.result, ps.seal, nil)
+	}
 	return snapshot
 }
 
@@ -14,6 +14,9 @@ import (
 
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/lifecycle"
+	"github.com/onflow/flow-go/storage/kv"
+	"github.com/onflow/flow-go/storage/kv/badgerkv"
+	"github.com/onflow/flow-go/storage/kv/memkv"
 )
 
 func ExpectPanic(expectedMsg string, t *testing.T) {
@@ -238,6 +241,32 @@ func RunWithTypedBadgerDB(t testing.TB, create func(badger.Options) (*badger.DB,
 	})
 }
 
+// RunWithDB creates an in-memory key-value database that is passed to f. Unit tests which do not depend on
+// badger should use it, as it is much faster to create than a badger database.
+func RunWithDB(t testing.TB, f func(kv.DB)) {
+	db := memkv.New()
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+	f(db)
+}
+
+// RunWithEachDB runs f against each implementation of the key-value database: a badger database and an
+// in-memory database. The implementation is logged, so that it is reported with the failures of f.
+func RunWithEachDB(t testing.TB, f func(kv.DB)) {
+	RunWithBadgerDB(t, func(db *badger.DB) {
+		t.Log("running against badger database")
+		f(badgerkv.New(db))
+	})
+	if t.Failed() {
+		return
+	}
+	RunWithDB(t, func(db kv.DB) {
+		t.Log("running against in-memory database")
+		f(db)
+	})
+}
+
 func TempBadgerDB(t testing.TB) (*badger.DB, string) {
 	dir := TempDir(t)
 	db := BadgerDB(t, dir)